@@ -18,6 +18,7 @@ package ipaddr
 
 import (
 	"fmt"
+	"hash"
 	"math/big"
 	"reflect"
 	"unsafe"
@@ -262,6 +263,16 @@ func (addr *addressInternal) ContainsPrefixBlock(prefixLen BitCount) bool {
 	return addr.section == nil || addr.section.ContainsPrefixBlock(prefixLen)
 }
 
+// ContainsPrefixBlockBySegment reports, for each segment, whether that segment spans the values required for this address or subnet to contain the block of addresses for the given prefix length.
+// ContainsPrefixBlock returns true if and only if every element is true, so the false elements identify the segments preventing a CIDR prefix block.
+// See the section method ContainsPrefixBlockBySegment for more details.
+func (addr *addressInternal) ContainsPrefixBlockBySegment(prefixLen BitCount) []bool {
+	if addr.section == nil {
+		return []bool{}
+	}
+	return addr.section.ContainsPrefixBlockBySegment(prefixLen)
+}
+
 // ContainsSinglePrefixBlock returns whether this address contains a single prefix block for the given prefix length.
 //
 // This means there is only one prefix value for the given prefix length, and it also contains the full prefix block for that prefix, all addresses with that prefix.
@@ -401,14 +412,14 @@ func (addr *addressInternal) trieCompare(other *Address) int {
 }
 
 func trieIncrement[T TrieKeyConstraint[T]](addr T) (t T, ok bool) {
-	if res, ok := tree.TrieIncrement(trieKey[T]{addr}); ok {
+	if res, ok := tree.TrieIncrement(newTrieKey(addr)); ok {
 		return res.address, true
 	}
 	return
 }
 
 func trieDecrement[T TrieKeyConstraint[T]](addr T) (t T, ok bool) {
-	if res, ok := tree.TrieDecrement(trieKey[T]{addr}); ok {
+	if res, ok := tree.TrieDecrement(newTrieKey(addr)); ok {
 		return res.address, true
 	}
 	return
@@ -421,7 +432,7 @@ func (addr *addressInternal) toString() string {
 	} else if addr.isMAC() {
 		return addr.toNormalizedString()
 	}
-	return addr.toCanonicalString()
+	return addr.toDefaultFormatString()
 }
 
 // IsSequential returns whether the address or subnet represents a range of addresses that are sequential.
@@ -941,6 +952,33 @@ func (addr *addressInternal) increment(increment int64) *Address {
 	return addr.checkIdentity(addr.section.increment(increment))
 }
 
+func (addr *addressInternal) incrementBig(increment *big.Int) *Address {
+	return addr.checkIdentity(addr.section.incrementBig(increment))
+}
+
+func (addr *addressInternal) distance(other *addressInternal) *big.Int {
+	res := other.section.GetValue()
+	return res.Sub(res, addr.section.GetValue())
+}
+
+func (addr *addressInternal) enumerate(other AddressType) *big.Int {
+	if other == nil {
+		return nil
+	}
+	otherAddr := other.ToAddressBase()
+	if otherAddr == nil || !addr.isSameZone(otherAddr) {
+		return nil
+	}
+	otherSection := otherAddr.GetSection()
+	if addr.section == nil {
+		if otherSection.GetSegmentCount() == 0 {
+			return bigZero()
+		}
+		return nil
+	}
+	return addr.section.enumerate(otherSection)
+}
+
 func (addr *addressInternal) incrementBoundary(increment int64) *Address {
 	return addr.checkIdentity(addr.section.incrementBoundary(increment))
 }
@@ -957,6 +995,10 @@ func (addr *addressInternal) getSegmentStrings() []string {
 	return addr.section.getSegmentStrings()
 }
 
+func (addr *addressInternal) getSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	return addr.section.getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 func (addr *addressInternal) toCanonicalString() string {
 	if addr.hasZone() {
 		cache := addr.getStringCache()
@@ -1154,6 +1196,28 @@ func (addr *Address) Contains(other AddressType) bool {
 	return addr.init().contains(other)
 }
 
+// Relation returns the relation of this address or subnet to the given address or subnet,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+// Addresses and subnets of different types or versions are disjoint.
+func (addr *Address) Relation(other AddressType) Relation {
+	if addr == nil {
+		if other == nil || other.ToAddressBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return addr.init().relation(other)
+}
+
+// IsContainedBy returns whether this is the same type and version as the given address or subnet and whether it is contained by the given address or subnet.
+// It is the reverse of Contains.
+func (addr *Address) IsContainedBy(other AddressType) bool {
+	if other == nil {
+		return addr == nil
+	}
+	return other.ToAddressBase().Contains(addr)
+}
+
 // Compare returns a negative integer, zero, or a positive integer if this address or subnet is less than, equal, or greater than the given item.
 // Any address item is comparable to any other.  All address items use CountComparator to compare.
 func (addr *Address) Compare(item AddressItem) int {
@@ -1277,6 +1341,19 @@ func (addr *Address) GetSegments() []*AddressSegment {
 	return addr.GetSection().GetSegments()
 }
 
+// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+// as much as can be fit into the slice, returning the number of segments copied.
+func (addr *Address) CopyGenericSegments(segs []AddressSegmentType) (count int) {
+	return addr.GetSection().CopyGenericSegments(segs)
+}
+
+// GetGenericSegments returns a slice with the address segments as AddressSegmentType,
+// allowing all segment types to be represented by a single type.
+// The returned slice is not backed by the same array as this address.
+func (addr *Address) GetGenericSegments() []AddressSegmentType {
+	return addr.GetSection().GetGenericSegments()
+}
+
 // GetSegment returns the segment at the given index.
 // The first segment is at index 0.
 // GetSegment will panic given a negative index or an index matching or larger than the segment count.
@@ -1296,6 +1373,20 @@ func (addr *Address) ForEachSegment(consumer func(segmentIndex int, segment *Add
 	return addr.GetSection().ForEachSegment(consumer)
 }
 
+// ForEachSegmentValue visits each segment in order from most-significant to least, the most significant with index 0,
+// calling the given function with the lower and upper values of each, and the segment prefix length for IP segments, terminating early if the function returns true.
+// Unlike ForEachSegment, the segments themselves are not provided, and unlike GetSegments, no allocation is required.
+// Returns the number of visited segments.
+func (addr *Address) ForEachSegmentValue(consumer func(segmentIndex int, value, upperValue SegInt, segmentPrefixLen PrefixLen) (stop bool)) int {
+	return addr.GetSection().ForEachSegmentValue(consumer)
+}
+
+// SegmentValuesAppend appends the lower segment values, the segment values of the lowest address in this subnet, to the given slice, returning the extended slice.
+// When the slice has sufficient capacity, no allocation is required.
+func (addr *Address) SegmentValuesAppend(values []SegInt) []SegInt {
+	return addr.GetSection().SegmentValuesAppend(values)
+}
+
 // GetGenericDivision returns the segment at the given index as a DivisionType.
 // The first segment is at index 0.
 // GetGenericDivision will panic given a negative index or index larger than the division count.
@@ -1378,6 +1469,21 @@ func (addr *Address) CopyUpperBytes(bytes []byte) []byte {
 	return addr.init().section.CopyUpperBytes(bytes)
 }
 
+// AppendBytes appends the value of the lowest individual address in the subnet or address collection to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices, so a buffer can be reused when encoding many addresses, as in packet encoders.
+// In contrast, Bytes and UpperBytes return a new slice with each call, while CopyBytes and CopyUpperBytes copy from bytes that are calculated and cached by the first call of any of those four methods.
+func (addr *Address) AppendBytes(bytes []byte) []byte {
+	return addr.init().section.AppendBytes(bytes)
+}
+
+// AppendUpperBytes appends the value of the highest individual address in the subnet or address collection to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (addr *Address) AppendUpperBytes(bytes []byte) []byte {
+	return addr.init().section.AppendUpperBytes(bytes)
+}
+
 // IsMax returns whether this address matches exactly the maximum possible value, the address whose bits are all ones.
 func (addr *Address) IsMax() bool {
 	return addr.init().section.IsMax()
@@ -1638,6 +1744,24 @@ func (addr *Address) Increment(increment int64) *Address {
 	return addr.init().increment(increment)
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with IPv6, where the count of addresses in a subnet can be much larger than the range of int64.
+//
+// On address overflow or underflow, IncrementBig returns nil.
+func (addr *Address) IncrementBig(increment *big.Int) *Address {
+	return addr.init().incrementBig(increment)
+}
+
+// Enumerate returns the index of the given individual address within the iteration order of this address or subnet,
+// which is the increment that, when supplied to IncrementBig, produces the given address from this subnet.
+// If the given address is not an individual address contained by this address or subnet, then nil is returned.
+func (addr *Address) Enumerate(other AddressType) *big.Int {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().enumerate(other)
+}
+
 // ReverseBytes returns a new address with the bytes reversed.  Any prefix length is dropped.
 //
 // If each segment is more than 1 byte long, and the bytes within a single segment cannot be reversed because the segment represents a range,
@@ -1710,6 +1834,8 @@ func (addr *Address) GetTrailingBitCount(ones bool) BitCount {
 // Format implements [fmt.Formatter] interface. It accepts the formats
 //  - 'v' for the default address and section format (either the normalized or canonical string),
 //  - 's' (string) for the same,
+//  - 'S' (uppercase string) for the same string with uppercase hexadecimal digits,
+//  - 'r' (reverse DNS) for the reverse-DNS lookup string of IP addresses,
 //  - 'b' (binary), 'o' (octal with 0 prefix), 'O' (octal with 0o prefix),
 //  - 'd' (decimal), 'x' (lowercase hexadecimal), and
 //  - 'X' (uppercase hexadecimal).
@@ -1719,6 +1845,10 @@ func (addr *Address) GetTrailingBitCount(ones bool) BitCount {
 // a leading "0x" or "0X" for "%#x" and "%#X" respectively.
 // Also supported is specification of minimum digits precision, output field width,
 // space or zero padding, and '-' for left or right justification.
+//
+// With 's' and 'S', the '+' flag selects the fully expanded string, as provided by ToFullString,
+// while the '#' flag selects the mixed IPv6/IPv4 string provided by ToMixedString for IPv6.
+// Combining the two, as in "%+#s", produces the mixed string with expanded IPv6 segments.
 func (addr Address) Format(state fmt.State, verb rune) {
 	addr.init().format(state, verb)
 }
@@ -1739,6 +1869,19 @@ func (addr *Address) GetSegmentStrings() []string {
 	return addr.init().getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (addr *Address) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 // ToCanonicalString produces a canonical string for the address.
 //
 // For IPv4, dotted octet format, also known as dotted decimal format, is used.
@@ -1940,6 +2083,15 @@ func (addr *Address) Wrap() WrappedAddress {
 	return wrapAddress(addr.init())
 }
 
+// WriteToHash writes the canonical bytes of this address into the given hash, without allocating:
+// the address type, byte count, and the bytes of the lowest and highest values, followed by the zone for IPv6.
+// The prefix length is not written.  The same bytes are written by the keys for this address.  See HashWriterTo for details.
+func (addr *Address) WriteToHash(h hash.Hash) {
+	if addr != nil {
+		addr.init().writeToHash(h)
+	}
+}
+
 // ToKey creates the associated address key.
 // While addresses can be compared with the Compare, TrieCompare or Equal methods as well as various provided instances of AddressComparator,
 // they are not comparable with Go operators.
@@ -2032,3 +2184,21 @@ func asMap[T AddressType](addrs []T) (result map[string]struct{}) {
 	}
 	return
 }
+
+// SafeString returns the string of the given address provided by its String method.
+// Unlike calling String directly, it accepts a nil interface as well as an interface holding a nil pointer, returning "<nil>" for both without panicking.
+func SafeString(addr AddressType) string {
+	if addr == nil {
+		return nilString()
+	}
+	return addr.ToAddressBase().String()
+}
+
+// SafeCanonical returns the canonical string of the given address provided by ToCanonicalString.
+// Unlike calling ToCanonicalString directly, it accepts a nil interface as well as an interface holding a nil pointer, returning "<nil>" for both without panicking.
+func SafeCanonical(addr AddressType) string {
+	if addr == nil {
+		return nilString()
+	}
+	return addr.ToAddressBase().ToCanonicalString()
+}
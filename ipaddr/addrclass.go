@@ -0,0 +1,214 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import "strconv"
+
+// AddressClassification is the classification of an IP address or subnet,
+// derived from the IANA IPv4 and IPv6 special-purpose address registries (RFC 6890) and the multicast address spaces.
+type AddressClassification int
+
+const (
+	// ClassNone is the classification of the zero-value IPAddress, which is neither IPv4 nor IPv6.
+	ClassNone AddressClassification = iota
+
+	// ClassMixed is the classification of a subnet whose addresses do not all share the same classification.
+	// A subnet within a single special-purpose block is not mixed, even when it includes special-purpose blocks nested within that block,
+	// so "0.0.0.0/8" has the classification ClassThisNetwork although it includes the unspecified address "0.0.0.0".
+	ClassMixed
+
+	// ClassGlobalUnicast is the classification of unicast addresses not otherwise reserved for a special purpose.
+	ClassGlobalUnicast
+
+	// ClassUnspecified is the classification of the unspecified address, "0.0.0.0" or "::".
+	ClassUnspecified
+
+	// ClassThisNetwork is the classification of the IPv4 "this network" block "0.0.0.0/8", RFC 791.
+	ClassThisNetwork
+
+	// ClassLoopback is the classification of the loopback addresses "127.0.0.0/8" and "::1".
+	ClassLoopback
+
+	// ClassPrivateUse is the classification of the RFC 1918 IPv4 private-use blocks.
+	ClassPrivateUse
+
+	// ClassSharedAddressSpace is the classification of the IPv4 shared address space "100.64.0.0/10" used by carrier-grade NAT, RFC 6598.
+	ClassSharedAddressSpace
+
+	// ClassLinkLocal is the classification of the unicast link-local blocks "169.254.0.0/16" and "fe80::/10".
+	ClassLinkLocal
+
+	// ClassSiteLocal is the classification of the deprecated IPv6 site-local block "fec0::/10", RFC 3879.
+	ClassSiteLocal
+
+	// ClassUniqueLocal is the classification of the IPv6 unique-local block "fc00::/7", RFC 4193.
+	ClassUniqueLocal
+
+	// ClassIETFProtocolAssignment is the classification of the IETF protocol assignment blocks "192.0.0.0/24" and "2001::/23", RFC 6890.
+	ClassIETFProtocolAssignment
+
+	// ClassDocumentation is the classification of the blocks reserved for documentation,
+	// "192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24", "2001:db8::/32" and "3fff::/20", RFC 5737, RFC 3849 and RFC 9637.
+	ClassDocumentation
+
+	// ClassBenchmarking is the classification of the blocks reserved for benchmarking, "198.18.0.0/15" and "2001:2::/48", RFC 2544 and RFC 5180.
+	ClassBenchmarking
+
+	// Class6To4 is the classification of the 6to4 block "2002::/16" and the deprecated 6to4 relay anycast block "192.88.99.0/24", RFC 3056 and RFC 7526.
+	Class6To4
+
+	// ClassTeredo is the classification of the Teredo block "2001::/32", RFC 4380.
+	ClassTeredo
+
+	// ClassIPv4Mapped is the classification of the IPv4-mapped block "::ffff:0:0/96", RFC 4291.
+	ClassIPv4Mapped
+
+	// ClassIPv4Translation is the classification of the IPv4/IPv6 translation blocks "64:ff9b::/96" and "64:ff9b:1::/48", RFC 6052 and RFC 8215.
+	ClassIPv4Translation
+
+	// ClassDiscardOnly is the classification of the IPv6 discard-only block "100::/64", RFC 6666.
+	ClassDiscardOnly
+
+	// ClassMulticast is the classification of the multicast blocks "224.0.0.0/4" and "ff00::/8".
+	ClassMulticast
+
+	// ClassLimitedBroadcast is the classification of the IPv4 limited broadcast address "255.255.255.255", RFC 919.
+	ClassLimitedBroadcast
+
+	// ClassReserved is the classification of addresses reserved for future use,
+	// "240.0.0.0/4" and the IPv6 address space outside the global unicast, unique-local, link-local and multicast allocations.
+	ClassReserved
+)
+
+var classificationStrings = [...]string{
+	ClassNone:                   "None",
+	ClassMixed:                  "Mixed",
+	ClassGlobalUnicast:          "GlobalUnicast",
+	ClassUnspecified:            "Unspecified",
+	ClassThisNetwork:            "ThisNetwork",
+	ClassLoopback:               "Loopback",
+	ClassPrivateUse:             "PrivateUse",
+	ClassSharedAddressSpace:     "SharedAddressSpace",
+	ClassLinkLocal:              "LinkLocal",
+	ClassSiteLocal:              "SiteLocal",
+	ClassUniqueLocal:            "UniqueLocal",
+	ClassIETFProtocolAssignment: "IETFProtocolAssignment",
+	ClassDocumentation:          "Documentation",
+	ClassBenchmarking:           "Benchmarking",
+	Class6To4:                   "6To4",
+	ClassTeredo:                 "Teredo",
+	ClassIPv4Mapped:             "IPv4Mapped",
+	ClassIPv4Translation:        "IPv4Translation",
+	ClassDiscardOnly:            "DiscardOnly",
+	ClassMulticast:              "Multicast",
+	ClassLimitedBroadcast:       "LimitedBroadcast",
+	ClassReserved:               "Reserved",
+}
+
+// String returns the name of the classification, such as "Loopback" or "PrivateUse".
+func (class AddressClassification) String() string {
+	if class >= 0 && int(class) < len(classificationStrings) {
+		return classificationStrings[class]
+	}
+	return "AddressClassification(" + strconv.Itoa(int(class)) + ")"
+}
+
+// IsSpecialPurpose returns whether the classification is that of a special-purpose block,
+// which is to say anything other than global unicast, mixed, or none.
+func (class AddressClassification) IsSpecialPurpose() bool {
+	return class > ClassGlobalUnicast
+}
+
+type classifiedBlock struct {
+	block *IPAddress
+	class AddressClassification
+}
+
+// The registries are ordered such that nested blocks precede the blocks enclosing them.
+var (
+	ipv4ClassifiedBlocks = []classifiedBlock{
+		{ipv4ClassBlock(0x00000000, 32), ClassUnspecified},
+		{ipv4ClassBlock(0x00000000, 8), ClassThisNetwork},
+		{ipv4ClassBlock(0x0a000000, 8), ClassPrivateUse},
+		{ipv4ClassBlock(0x64400000, 10), ClassSharedAddressSpace},
+		{ipv4ClassBlock(0x7f000000, 8), ClassLoopback},
+		{ipv4ClassBlock(0xa9fe0000, 16), ClassLinkLocal},
+		{ipv4ClassBlock(0xac100000, 12), ClassPrivateUse},
+		{ipv4ClassBlock(0xc0000000, 24), ClassIETFProtocolAssignment},
+		{ipv4ClassBlock(0xc0000200, 24), ClassDocumentation},
+		{ipv4ClassBlock(0xc0586300, 24), Class6To4},
+		{ipv4ClassBlock(0xc0a80000, 16), ClassPrivateUse},
+		{ipv4ClassBlock(0xc6120000, 15), ClassBenchmarking},
+		{ipv4ClassBlock(0xc6336400, 24), ClassDocumentation},
+		{ipv4ClassBlock(0xcb007100, 24), ClassDocumentation},
+		{ipv4ClassBlock(0xe0000000, 4), ClassMulticast},
+		{ipv4ClassBlock(0xffffffff, 32), ClassLimitedBroadcast},
+		{ipv4ClassBlock(0xf0000000, 4), ClassReserved},
+	}
+
+	ipv6ClassifiedBlocks = []classifiedBlock{
+		{ipv6ClassBlock(0, 0, 128), ClassUnspecified},
+		{ipv6ClassBlock(0, 1, 128), ClassLoopback},
+		{ipv6ClassBlock(0, 0x0000ffff00000000, 96), ClassIPv4Mapped},
+		{ipv6ClassBlock(0x0064ff9b00000000, 0, 96), ClassIPv4Translation},
+		{ipv6ClassBlock(0x0064ff9b00010000, 0, 48), ClassIPv4Translation},
+		{ipv6ClassBlock(0x0100000000000000, 0, 64), ClassDiscardOnly},
+		{ipv6ClassBlock(0x2001000000000000, 0, 32), ClassTeredo},
+		{ipv6ClassBlock(0x2001000200000000, 0, 48), ClassBenchmarking},
+		{ipv6ClassBlock(0x2001000000000000, 0, 23), ClassIETFProtocolAssignment},
+		{ipv6ClassBlock(0x20010db800000000, 0, 32), ClassDocumentation},
+		{ipv6ClassBlock(0x2002000000000000, 0, 16), Class6To4},
+		{ipv6ClassBlock(0x3fff000000000000, 0, 20), ClassDocumentation},
+		{ipv6ClassBlock(0x2000000000000000, 0, 3), ClassGlobalUnicast},
+		{ipv6ClassBlock(0xfc00000000000000, 0, 7), ClassUniqueLocal},
+		{ipv6ClassBlock(0xfe80000000000000, 0, 10), ClassLinkLocal},
+		{ipv6ClassBlock(0xfec0000000000000, 0, 10), ClassSiteLocal},
+		{ipv6ClassBlock(0xff00000000000000, 0, 8), ClassMulticast},
+	}
+)
+
+func ipv4ClassBlock(val uint32, prefLen BitCount) *IPAddress {
+	return NewIPv4AddressFromPrefixedUint32(val, cacheBitCount(prefLen)).ToPrefixBlock().ToIP()
+}
+
+func ipv6ClassBlock(high, low uint64, prefLen BitCount) *IPAddress {
+	return NewIPv6AddressFromPrefixedUint64(high, low, cacheBitCount(prefLen)).ToPrefixBlock().ToIP()
+}
+
+// classify returns the classification of the first block containing the given address or subnet.
+// Since nested blocks precede the blocks enclosing them, a subnet intersecting blocks that do not contain it may still be contained by an enclosing block.
+// The subnet then takes the classification of the enclosing block when that block is special-purpose, as with "0.0.0.0/8" enclosing "0.0.0.0",
+// and otherwise the classification is ClassMixed, as with "2000::/3" enclosing "2001:db8::/32".
+// If the subnet straddles a block boundary, the classification is ClassMixed.
+// If no block intersects the address or subnet, the classification is the given default.
+func classify(addr *IPAddress, blocks []classifiedBlock, defaultClass AddressClassification) AddressClassification {
+	var isMixed bool
+	for _, classBlock := range blocks {
+		if classBlock.block.Contains(addr) {
+			if isMixed && !classBlock.class.IsSpecialPurpose() {
+				return ClassMixed
+			}
+			return classBlock.class
+		} else if addr.IsMultiple() && classBlock.block.Intersect(addr) != nil {
+			isMixed = true
+		}
+	}
+	if isMixed {
+		return ClassMixed
+	}
+	return defaultClass
+}
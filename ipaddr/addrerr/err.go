@@ -26,6 +26,12 @@ AddressError
 		- HostNameError
 		- AddressStringError
 	- AddressValueError
+		- ValueError
+
+InputError
+	- IndexError
+
+The sentinel errors, such as ErrInvalidPrefixLen, categorize the errors by key, for use with errors.Is.
 
 unused, but present in Java:
 NetworkMismatchException
@@ -98,3 +104,131 @@ type SizeMismatchError interface {
 type AddressValueError interface {
 	AddressError
 }
+
+// InputError is implemented by errors resulting from an invalid string, providing the offending string.
+// Use errors.As to access the string from an error returned by this library.
+type InputError interface {
+	AddressError
+
+	// GetInput returns the offending input string, or the empty string if not available.
+	GetInput() string
+}
+
+// IndexError is implemented by errors resulting from an invalid character or component at a specific location within a string.
+// Use errors.As to access the index from an error returned by this library.
+type IndexError interface {
+	InputError
+
+	// GetIndex returns the byte index of the error location in the offending string.
+	GetIndex() int
+}
+
+// ValueError is implemented by errors resulting from an invalid value supplied to an address operation.
+// Use errors.As to access the value from an error returned by this library.
+type ValueError interface {
+	AddressValueError
+
+	// GetValue returns the offending value.
+	GetValue() int
+}
+
+// SentinelError is an error that categorizes the errors returned by this library, for use with errors.Is.
+// An error returned by this library matches a sentinel error when the key of the error is one of the keys of the sentinel.
+// Errors that wrap other errors, such as host name errors wrapping address errors, match when any error in the chain matches.
+type SentinelError struct {
+	msg  string
+	keys []string
+}
+
+// Error returns a description of the category of errors.
+func (sentinel *SentinelError) Error() string {
+	return sentinel.msg
+}
+
+// GetKeys returns the keys of the errors matching this sentinel error.
+func (sentinel *SentinelError) GetKeys() []string {
+	return append([]string(nil), sentinel.keys...)
+}
+
+// MatchesKey returns whether an error with the given key matches this sentinel error.
+func (sentinel *SentinelError) MatchesKey(key string) bool {
+	for _, k := range sentinel.keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// ErrInvalidPrefixLen is matched by errors resulting from a prefix length that is invalid or not permitted.
+	ErrInvalidPrefixLen = &SentinelError{
+		msg: "invalid prefix length",
+		keys: []string{
+			"ipaddress.error.invalidCIDRPrefix",
+			"ipaddress.error.invalidCIDRPrefixOrMask",
+			"ipaddress.error.prefixSize",
+			"ipaddress.error.ipv4.prefix.leading.zeros",
+			"ipaddress.error.ipv6.prefix.leading.zeros",
+			"ipaddress.error.inconsistent.prefixes",
+		},
+	}
+
+	// ErrIncompatibleRange is matched by errors resulting from an operation on a range of values whose result cannot be represented as a sequential range.
+	ErrIncompatibleRange = &SentinelError{
+		msg: "incompatible range of values",
+		keys: []string{
+			"ipaddress.error.maskMismatch",
+			"ipaddress.error.segmentMismatch",
+			"ipaddress.error.reverseRange",
+			"ipaddress.error.splitMismatch",
+			"ipaddress.error.splitSeg",
+			"ipaddress.error.invalidMixedRange",
+			"ipaddress.error.invalidMACIPv6Range",
+			"ipaddress.error.invalid.joined.ranges",
+		},
+	}
+
+	// ErrVersionMismatch is matched by errors resulting from addresses or masks whose IP versions do not match.
+	ErrVersionMismatch = &SentinelError{
+		msg: "IP version mismatch",
+		keys: []string{
+			"ipaddress.error.ipMismatch",
+			"ipaddress.error.version.mismatch",
+			"ipaddress.error.ipVersionMismatch",
+			"ipaddress.error.mixedVersions",
+		},
+	}
+
+	// ErrSizeMismatch is matched by errors resulting from address items that do not match in size.
+	ErrSizeMismatch = &SentinelError{
+		msg: "size mismatch",
+		keys: []string{
+			"ipaddress.error.sizeMismatch",
+			"ipaddress.error.mismatched.bit.size",
+		},
+	}
+
+	// ErrValueOutOfRange is matched by errors resulting from a value that is too large or too small.
+	ErrValueOutOfRange = &SentinelError{
+		msg: "value out of range",
+		keys: []string{
+			"ipaddress.error.exceeds.size",
+			"ipaddress.error.negative",
+			"ipaddress.error.address.too.large",
+			"ipaddress.error.ipv4.segment.too.large",
+			"ipaddress.error.lower.below.range",
+			"ipaddress.error.lower.above.range",
+			"ipaddress.error.address.out.of.range",
+		},
+	}
+
+	// ErrVersionNotAllowed is matched by errors resulting from parsing an address string whose IP version is not allowed by the parameters.
+	ErrVersionNotAllowed = &SentinelError{
+		msg: "IP version not allowed",
+		keys: []string{
+			"ipaddress.error.ipv4",
+			"ipaddress.error.ipv6",
+		},
+	}
+)
@@ -0,0 +1,222 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"net"
+	"net/netip"
+	"strconv"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// NewIPAddressPort constructs an IPAddressPort from an IP address or subnet and a port.
+// The zone of an IPv6 address, if any, is retained.
+func NewIPAddressPort(addr *IPAddress, port uint16) *IPAddressPort {
+	return &IPAddressPort{addr: addr, port: port}
+}
+
+// ParseIPAddressPort parses a socket address string with an IP address and a port, such as "1.2.3.4:80" or "[a:b::1%eth0]:80",
+// in which an IPv6 address must be enclosed in square brackets.
+// The address can be a subnet, such as "1.2.3.*:80" or "[a:b::/64]:80".
+//
+// An error is returned if the string is not a valid host string, if the host is a host name rather than an IP address,
+// or if there is no port, including when there is a service name in place of the port.
+// Host names are not resolved.
+func ParseIPAddressPort(str string) (*IPAddressPort, addrerr.HostNameError) {
+	host := NewHostName(str)
+	if err := host.Validate(); err != nil {
+		return nil, err
+	} else if !host.IsAddress() {
+		return nil, &hostNameError{addressError{str: str, key: "ipaddress.host.error.invalid.type"}}
+	}
+	port := host.GetPort()
+	if port == nil {
+		return nil, &hostNameError{addressError{str: str, key: "ipaddress.host.error.invalidPort.no.digits"}}
+	}
+	return NewIPAddressPort(host.AsAddress(), uint16(*port)), nil
+}
+
+// NewIPAddressPortFromNetTCPAddr constructs an IPAddressPort from a net.TCPAddr.
+// An error is returned if the IP address of the net.TCPAddr is not a valid IPv4 or IPv6 address, or the port is not a valid port number.
+func NewIPAddressPortFromNetTCPAddr(addr *net.TCPAddr) (*IPAddressPort, addrerr.AddressValueError) {
+	return newIPAddressPortFromSocketAddr(addr.IP, addr.Port, addr.Zone)
+}
+
+// NewIPAddressPortFromNetUDPAddr constructs an IPAddressPort from a net.UDPAddr.
+// An error is returned if the IP address of the net.UDPAddr is not a valid IPv4 or IPv6 address, or the port is not a valid port number.
+func NewIPAddressPortFromNetUDPAddr(addr *net.UDPAddr) (*IPAddressPort, addrerr.AddressValueError) {
+	return newIPAddressPortFromSocketAddr(addr.IP, addr.Port, addr.Zone)
+}
+
+func newIPAddressPortFromSocketAddr(ip net.IP, port int, zone string) (*IPAddressPort, addrerr.AddressValueError) {
+	if port < minPortNumInternal || port > maxPortNumInternal {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.host.error.invalidPort.too.large"}, val: port}
+	}
+	ipAddr, err := NewIPAddressFromNetIPAddr(&net.IPAddr{IP: ip, Zone: zone})
+	if err != nil {
+		return nil, err
+	} else if ipAddr == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.exceeds.size"}}
+	}
+	return NewIPAddressPort(ipAddr, uint16(port)), nil
+}
+
+// NewIPAddressPortFromNetNetIPAddrPort constructs an IPAddressPort from a netip.AddrPort.
+// It returns nil if the netip.AddrPort is the zero value, having no address.
+func NewIPAddressPortFromNetNetIPAddrPort(addrPort netip.AddrPort) *IPAddressPort {
+	ipAddr := NewIPAddressFromNetNetIPAddr(addrPort.Addr())
+	if ipAddr == nil {
+		return nil
+	}
+	return NewIPAddressPort(ipAddr, addrPort.Port())
+}
+
+// IPAddressPort is an IP address or subnet paired with a port, such as the address of a TCP or UDP socket,
+// along with the zone of an IPv6 address, if any.
+//
+// It bridges the addresses of this library and the socket address types of the standard library, net.TCPAddr, net.UDPAddr and netip.AddrPort,
+// so that applications can carry an address or subnet with its port up to the point of dialing or listening.
+//
+// IPAddressPort is immutable and can be used concurrently by multiple goroutines.
+// Use ToKey to obtain a key for use with Go operators and maps.
+type IPAddressPort struct {
+	addr *IPAddress
+	port uint16
+}
+
+// GetAddress returns the address or subnet, including its zone, if any.
+func (addrPort *IPAddressPort) GetAddress() *IPAddress {
+	return addrPort.addr
+}
+
+// GetPort returns the port.
+func (addrPort *IPAddressPort) GetPort() uint16 {
+	return addrPort.port
+}
+
+// GetZone returns the zone of the address, or NoZone if the address is not an IPv6 address with a zone.
+func (addrPort *IPAddressPort) GetZone() Zone {
+	if ipv6Addr := addrPort.addr.ToIPv6(); ipv6Addr != nil {
+		return ipv6Addr.GetZone()
+	}
+	return NoZone
+}
+
+// IsMultiple returns whether the address is a subnet of multiple addresses.
+func (addrPort *IPAddressPort) IsMultiple() bool {
+	return addrPort.addr.IsMultiple()
+}
+
+// ToHostName returns a HostName with the address and port.
+func (addrPort *IPAddressPort) ToHostName() *HostName {
+	return NewHostNameFromAddrPort(addrPort.addr, addrPort.port)
+}
+
+// ToNetTCPAddr returns the address and port as a net.TCPAddr.
+// For a subnet, the lowest address in the subnet is used, as with GetNetIP of IPAddress.
+func (addrPort *IPAddressPort) ToNetTCPAddr() *net.TCPAddr {
+	return &net.TCPAddr{
+		IP:   addrPort.addr.GetNetIP(),
+		Port: int(addrPort.port),
+		Zone: string(addrPort.GetZone()),
+	}
+}
+
+// ToNetUDPAddr returns the address and port as a net.UDPAddr.
+// For a subnet, the lowest address in the subnet is used, as with GetNetIP of IPAddress.
+func (addrPort *IPAddressPort) ToNetUDPAddr() *net.UDPAddr {
+	return &net.UDPAddr{
+		IP:   addrPort.addr.GetNetIP(),
+		Port: int(addrPort.port),
+		Zone: string(addrPort.GetZone()),
+	}
+}
+
+// ToNetNetIPAddrPort returns the address and port as a netip.AddrPort.
+// For a subnet, the lowest address in the subnet is used, as with GetNetNetIPAddr of IPAddress.
+func (addrPort *IPAddressPort) ToNetNetIPAddrPort() netip.AddrPort {
+	return netip.AddrPortFrom(addrPort.addr.GetNetNetIPAddr(), addrPort.port)
+}
+
+// Equal returns whether the given IPAddressPort has an equal address or subnet, as determined by Equal of IPAddress, and the same port.
+func (addrPort *IPAddressPort) Equal(other *IPAddressPort) bool {
+	if addrPort == nil {
+		return other == nil
+	} else if other == nil {
+		return false
+	}
+	return addrPort.port == other.port && addrPort.addr.Equal(other.addr)
+}
+
+// Compare returns a negative integer, zero, or a positive integer if this IPAddressPort is less than, equal, or greater than the given IPAddressPort.
+// The addresses are compared first, with Compare of IPAddress, and the ports are compared when the addresses are equal.
+// A nil IPAddressPort is less than any other.
+func (addrPort *IPAddressPort) Compare(other *IPAddressPort) int {
+	if addrPort == nil {
+		if other == nil {
+			return 0
+		}
+		return -1
+	} else if other == nil {
+		return 1
+	} else if result := addrPort.addr.Compare(other.addr); result != 0 {
+		return result
+	}
+	return int(addrPort.port) - int(other.port)
+}
+
+// ToKey creates the associated key, which can be compared with Go operators and used as a map key.
+func (addrPort *IPAddressPort) ToKey() IPAddressPortKey {
+	return IPAddressPortKey{
+		Address: addrPort.addr.ToKey(),
+		Port:    addrPort.port,
+	}
+}
+
+// String returns the canonical string of the address followed by the port,
+// with an IPv6 address enclosed in square brackets, such as "1.2.3.4:80" or "[a:b::1%eth0]:80".
+// It returns "<nil>" if the receiver is a nil pointer.
+func (addrPort *IPAddressPort) String() string {
+	if addrPort == nil {
+		return nilString()
+	}
+	addrStr := addrPort.addr.String()
+	if addrPort.addr.IsIPv6() {
+		addrStr = "[" + addrStr + "]"
+	}
+	return addrStr + ":" + strconv.Itoa(int(addrPort.port))
+}
+
+// IPAddressPortKey is a representation of an IPAddressPort that is comparable as defined by the language specification.
+// See https://go.dev/ref/spec#Comparison_operators
+//
+// It can be used as a map key.  Like the key of an address, it does not incorporate the prefix length of the address.
+type IPAddressPortKey struct {
+	Address IPAddressKey
+	Port    uint16
+}
+
+// ToAddressPort converts back to an IPAddressPort instance.
+func (key IPAddressPortKey) ToAddressPort() *IPAddressPort {
+	return NewIPAddressPort(key.Address.ToAddress(), key.Port)
+}
+
+// String returns the same string as the corresponding IPAddressPort.
+func (key IPAddressPortKey) String() string {
+	return key.ToAddressPort().String()
+}
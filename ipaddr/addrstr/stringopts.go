@@ -338,9 +338,40 @@ func (builder *StringOptionsBuilder) ToOptions() StringOptions {
 	return &res
 }
 
-// MACStringOptionsBuilder is used to build an immutable StringOptions instance for MAC address strings.
+// MACStringOptions represents a clear way to create a specific type of MAC address or MAC address collection string.
+type MACStringOptions interface {
+	StringOptions
+
+	// GetSegmentGrouping returns the number of adjacent segments to be joined and written as a single segment,
+	// such as 2 for the dotted format aaaa.bbbb.cccc.  The default is 1, each segment written individually.
+	GetSegmentGrouping() int
+}
+
+type macStringOptions struct {
+	stringOptions
+
+	segmentGrouping int
+}
+
+// GetSegmentGrouping returns the number of adjacent segments to be joined and written as a single segment.
+func (opts *macStringOptions) GetSegmentGrouping() int {
+	return opts.segmentGrouping
+}
+
+var _ MACStringOptions = &macStringOptions{}
+
+// MACStringOptionsBuilder is used to build an immutable MACStringOptions instance for MAC address strings.
 type MACStringOptionsBuilder struct {
 	StringOptionsBuilder
+	segmentGrouping int
+}
+
+// SetSegmentGrouping dictates the number of adjacent segments to be joined and written as a single segment,
+// such as 2 for the dotted format aaaa.bbbb.cccc, or 3 for the format aabbcc-ddeeff.
+// The default is 1, each segment written individually.
+func (builder *MACStringOptionsBuilder) SetSegmentGrouping(segmentCount int) *MACStringOptionsBuilder {
+	builder.segmentGrouping = segmentCount
+	return builder
 }
 
 // SetWildcards specifies the wildcards for use in the string.
@@ -399,11 +430,15 @@ func (builder *MACStringOptionsBuilder) SetSegmentStrPrefix(prefix string) *MACS
 	return builder
 }
 
-// ToOptions returns an immutable StringOptions instance built by this builder.
-func (builder *MACStringOptionsBuilder) ToOptions() StringOptions {
+// ToOptions returns an immutable MACStringOptions instance built by this builder.
+func (builder *MACStringOptionsBuilder) ToOptions() MACStringOptions {
 	b := &builder.StringOptionsBuilder
 	b.hasSeparator, b.separator = getMACDefaults(b.hasSeparator, b.separator)
-	return builder.StringOptionsBuilder.ToOptions()
+	res := macStringOptions{stringOptions: *b.ToOptions().(*stringOptions), segmentGrouping: builder.segmentGrouping}
+	if res.segmentGrouping < 1 {
+		res.segmentGrouping = 1
+	}
+	return &res
 }
 
 // WildcardOption indicates options indicating when and where to use wildcards.
@@ -943,6 +978,23 @@ const (
 	AllowMixedCompression MixedCompressionOptions = ""
 )
 
+// CompressionRunOptions specify which range of zero-segments is compressed when there is more than one candidate.
+type CompressionRunOptions string
+
+const (
+	// LongestLeftmost - compress the longest range of segments, choosing the leftmost when two or more ranges are the longest.  This is the choice prescribed by RFC 5952.
+	LongestLeftmost CompressionRunOptions = ""
+
+	// LongestRightmost - compress the longest range of segments, choosing the rightmost when two or more ranges are the longest.
+	LongestRightmost CompressionRunOptions = "longest rightmost"
+
+	// Leftmost - compress the leftmost range of segments, regardless of length.
+	Leftmost CompressionRunOptions = "leftmost"
+
+	// Rightmost - compress the rightmost range of segments, regardless of length.
+	Rightmost CompressionRunOptions = "rightmost"
+)
+
 // CompressOptions specifies how to compress the zero-segments in an address or subnet string.
 type CompressOptions interface {
 	// GetCompressionChoiceOptions provides the CompressionChoiceOptions which specify which zero-segments should be compressed.
@@ -955,11 +1007,28 @@ type CompressOptions interface {
 	CompressSingle() bool
 }
 
+// CompressionRunOptionsProvider is an optional interface for CompressOptions, specifying which range of zero-segments is compressed and the minimum length of the range.
+// The CompressOptions instances created by CompressOptionsBuilder implement it.
+// When a CompressOptions does not implement it, the longest range is compressed, choosing the leftmost when there is a tie, as with LongestLeftmost,
+// and there is no minimum beyond that of CompressSingle.
+type CompressionRunOptionsProvider interface {
+	// GetCompressionRunOptions provides the CompressionRunOptions which specify which range of zero-segments is compressed when there is more than one candidate.
+	GetCompressionRunOptions() CompressionRunOptions
+
+	// GetMinCompressCount provides the minimum number of consecutive segments that can be compressed.
+	// A single segment is compressed only when CompressSingle is also true.
+	GetMinCompressCount() int
+}
+
 type compressOptions struct {
 	compressSingle bool
 
+	minCompressCount int
+
 	rangeSelection CompressionChoiceOptions
 
+	runSelection CompressionRunOptions
+
 	//options for addresses with an ipv4 section
 	compressMixedOptions MixedCompressionOptions
 }
@@ -979,7 +1048,19 @@ func (opts *compressOptions) CompressSingle() bool {
 	return opts.compressSingle
 }
 
+// GetCompressionRunOptions provides the CompressionRunOptions which specify which range of zero-segments is compressed when there is more than one candidate.
+func (opts *compressOptions) GetCompressionRunOptions() CompressionRunOptions {
+	return opts.runSelection
+}
+
+// GetMinCompressCount provides the minimum number of consecutive segments that can be compressed.
+// A single segment is compressed only when CompressSingle is also true.
+func (opts *compressOptions) GetMinCompressCount() int {
+	return opts.minCompressCount
+}
+
 var _ CompressOptions = &compressOptions{}
+var _ CompressionRunOptionsProvider = &compressOptions{}
 
 // CompressOptionsBuilder is used to build an immutable CompressOptions instance for IPv6 address strings.
 type CompressOptionsBuilder struct {
@@ -998,6 +1079,20 @@ func (builder *CompressOptionsBuilder) SetCompressionChoiceOptions(rangeSelectio
 	return builder
 }
 
+// SetCompressionRunOptions sets the CompressionRunOptions which specify which range of zero-segments is compressed when there is more than one candidate
+func (builder *CompressOptionsBuilder) SetCompressionRunOptions(runSelection CompressionRunOptions) *CompressOptionsBuilder {
+	builder.runSelection = runSelection
+	return builder
+}
+
+// SetMinCompressCount sets the minimum number of consecutive segments that can be compressed.
+// Ranges of segments shorter than this will not be compressed.
+// A single segment is compressed only when CompressSingle is also true.
+func (builder *CompressOptionsBuilder) SetMinCompressCount(minCount int) *CompressOptionsBuilder {
+	builder.minCompressCount = minCount
+	return builder
+}
+
 // SetMixedCompressionOptions sets the MixedCompressionOptions which specify which zero-segments should be compressed in mixed IPv6/v4 strings
 func (builder *CompressOptionsBuilder) SetMixedCompressionOptions(compressMixedOptions MixedCompressionOptions) *CompressOptionsBuilder {
 	builder.compressMixedOptions = compressMixedOptions
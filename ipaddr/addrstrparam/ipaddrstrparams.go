@@ -76,6 +76,41 @@ type IPAddressStringParams interface {
 	GetIPv6Params() IPv6AddressStringParams
 }
 
+// ReverseDNSProvider is an optional interface for IPAddressStringParams, indicating whether reverse-DNS names are parsed as addresses.
+// The IPAddressStringParams instances created by IPAddressStringParamsBuilder implement it.
+// When an IPAddressStringParams does not implement it, reverse-DNS names are not allowed.
+type ReverseDNSProvider interface {
+	// AllowsReverseDNS allows reverse-DNS names like "4.3.2.1.in-addr.arpa" or "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	// which are parsed as the corresponding address.  Zone names with fewer labels, like "2.1.in-addr.arpa", are parsed as the prefix block covered by the zone.
+	AllowsReverseDNS() bool
+}
+
+// allowsReverseDNS returns whether the given parameters allow reverse-DNS names, which is false when the parameters do not implement ReverseDNSProvider
+func allowsReverseDNS(params IPAddressStringParams) bool {
+	if dnsProvider, ok := params.(ReverseDNSProvider); ok {
+		return dnsProvider.AllowsReverseDNS()
+	}
+	return false
+}
+
+// SpaceSeparatedMaskProvider is an optional interface for IPAddressStringParams, indicating whether an address may be followed by a network mask separated by whitespace.
+// The IPAddressStringParams instances created by IPAddressStringParamsBuilder implement it.
+// When an IPAddressStringParams does not implement it, space-separated masks are not allowed.
+type SpaceSeparatedMaskProvider interface {
+	// AllowsSpaceSeparatedMask allows an address followed by a network mask separated by whitespace, like "1.2.3.0 255.255.255.0",
+	// as commonly found in network device configurations.  The string is parsed as if the mask followed a '/' character, as in "1.2.3.0/255.255.255.0",
+	// so masks are also subject to AllowsMask.
+	AllowsSpaceSeparatedMask() bool
+}
+
+// allowsSpaceSeparatedMask returns whether the given parameters allow space-separated masks, which is false when the parameters do not implement SpaceSeparatedMaskProvider
+func allowsSpaceSeparatedMask(params IPAddressStringParams) bool {
+	if maskProvider, ok := params.(SpaceSeparatedMaskProvider); ok {
+		return maskProvider.AllowsSpaceSeparatedMask()
+	}
+	return false
+}
+
 // EmptyStrOption is an option indicating how to translate an empty address string to an address.
 type EmptyStrOption string
 
@@ -102,6 +137,8 @@ const (
 )
 
 var _ IPAddressStringParams = &ipAddressStringParameters{}
+var _ SpaceSeparatedMaskProvider = &ipAddressStringParameters{}
+var _ ReverseDNSProvider = &ipAddressStringParameters{}
 
 // IPv4AddressStringParams provides parameters specific to IPv4 addresses and subnets
 type IPv4AddressStringParams interface {
@@ -129,6 +166,26 @@ type IPv4AddressStringParams interface {
 }
 
 var _ IPv4AddressStringParams = &ipv4AddressStringParameters{}
+var _ JoinedSegmentCountProvider = &ipv4AddressStringParameters{}
+
+// JoinedSegmentCountProvider is an optional interface for IPv4AddressStringParams, indicating for which segment counts IPv4 joined segments are allowed.
+// The IPv4AddressStringParams instances created by IPv4AddressStringParamsBuilder implement it.
+// When an IPv4AddressStringParams does not implement it, joined segments are allowed for both 2 and 3 segments when Allows_inet_aton_joinedSegments is true.
+type JoinedSegmentCountProvider interface {
+	// Allows_inet_aton_joinedSegmentCount indicates whether IPv4 joined segments are allowed for addresses with the given number of segments,
+	// either 2 as in "1.2" or 3 as in "1.2.3".
+	// It returns false when Allows_inet_aton_joinedSegments is false, and for any other segment count.
+	Allows_inet_aton_joinedSegmentCount(segmentCount int) bool
+}
+
+// allows_inet_aton_joinedSegmentCount returns whether the given parameters allow joined segments with the given segment count,
+// which depends only on Allows_inet_aton_joinedSegments when the parameters do not implement JoinedSegmentCountProvider
+func allows_inet_aton_joinedSegmentCount(params IPv4AddressStringParams, segmentCount int) bool {
+	if countProvider, ok := params.(JoinedSegmentCountProvider); ok {
+		return countProvider.Allows_inet_aton_joinedSegmentCount(segmentCount)
+	}
+	return params.Allows_inet_aton_joinedSegments() && (segmentCount == 2 || segmentCount == 3)
+}
 
 // IPv6AddressStringParams provides parameters specific to IPv6 addresses and subnets.
 type IPv6AddressStringParams interface {
@@ -154,6 +211,23 @@ type IPv6AddressStringParams interface {
 }
 
 var _ IPv6AddressStringParams = &ipv6AddressStringParameters{}
+var _ URIZoneEncodingProvider = &ipv6AddressStringParameters{}
+
+// URIZoneEncodingProvider is an optional interface for IPv6AddressStringParams, indicating whether zones may be percent-encoded as in URIs.
+// The IPv6AddressStringParams instances created by IPv6AddressStringParamsBuilder implement it.
+// When an IPv6AddressStringParams does not implement it, URI zone encoding is not allowed.
+type URIZoneEncodingProvider interface {
+	// AllowsURIZoneEncoding allows zones percent-encoded as in URIs, following RFC 6874, like "fe80::1%25eth0" for the zone "eth0".
+	AllowsURIZoneEncoding() bool
+}
+
+// allowsURIZoneEncoding returns whether the given parameters allow URI zone encoding, which is false when the parameters do not implement URIZoneEncodingProvider
+func allowsURIZoneEncoding(params IPv6AddressStringParams) bool {
+	if zoneProvider, ok := params.(URIZoneEncodingProvider); ok {
+		return zoneProvider.AllowsURIZoneEncoding()
+	}
+	return false
+}
 
 // IPAddressStringFormatParams provides format parameters that apply to all IP addresses, but can be different for IPv4 or IPv6,
 // allowing for cases where you may wish to allow something for one version but not the same for the other version.
@@ -202,6 +276,8 @@ type ipAddressStringParameters struct {
 	//emptyIsNotLoopback,
 	//noPrefixOnly,
 	noPrefix, noMask, noIPv6, noIPv4 bool
+
+	allowReverseDNS, allowSpaceSeparatedMask bool
 }
 
 // AllowsPrefix indicates whether addresses with prefix length like 1.2.0.0/16 are allowed.
@@ -249,6 +325,19 @@ func (params *ipAddressStringParameters) AllowsIPv6() bool {
 	return !params.noIPv6
 }
 
+// AllowsReverseDNS allows reverse-DNS names like "4.3.2.1.in-addr.arpa" or "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+// which are parsed as the corresponding address.  Zone names with fewer labels, like "2.1.in-addr.arpa", are parsed as the prefix block covered by the zone.
+func (params *ipAddressStringParameters) AllowsReverseDNS() bool {
+	return params.allowReverseDNS
+}
+
+// AllowsSpaceSeparatedMask allows an address followed by a network mask separated by whitespace, like "1.2.3.0 255.255.255.0",
+// as commonly found in network device configurations.  The string is parsed as if the mask followed a '/' character, as in "1.2.3.0/255.255.255.0",
+// so masks are also subject to AllowsMask.
+func (params *ipAddressStringParameters) AllowsSpaceSeparatedMask() bool {
+	return params.allowSpaceSeparatedMask
+}
+
 // GetIPv4Params returns the parameters that apply specifically to IPv4 addresses and subnets.
 func (params *ipAddressStringParameters) GetIPv4Params() IPv4AddressStringParams {
 	return &params.ipv4Params
@@ -318,6 +407,9 @@ func (builder *IPAddressStringParamsBuilder) set(params IPAddressStringParams, i
 			noMask:            !params.AllowsMask(),
 			noIPv6:            !params.AllowsIPv6(),
 			noIPv4:            !params.AllowsIPv4(),
+			allowReverseDNS:   allowsReverseDNS(params),
+
+			allowSpaceSeparatedMask: allowsSpaceSeparatedMask(params),
 		}
 	}
 	builder.AddressStringParamsBuilder.set(params)
@@ -399,6 +491,22 @@ func (builder *IPAddressStringParamsBuilder) AllowIPv6(allow bool) *IPAddressStr
 	return builder
 }
 
+// AllowReverseDNS dictates whether to allow reverse-DNS names like "4.3.2.1.in-addr.arpa", which are parsed as the corresponding address.
+// Zone names with fewer labels, like "2.1.in-addr.arpa", are parsed as the prefix block covered by the zone.
+// The suffixes ".in-addr.arpa", ".ip6.arpa" and ".ip6.int" are accepted, in any case, with or without a trailing dot.
+func (builder *IPAddressStringParamsBuilder) AllowReverseDNS(allow bool) *IPAddressStringParamsBuilder {
+	builder.params.allowReverseDNS = allow
+	return builder
+}
+
+// AllowSpaceSeparatedMask dictates whether to allow an address followed by a network mask separated by whitespace, like "1.2.3.0 255.255.255.0".
+// The string is parsed as if the mask followed a '/' character, as in "1.2.3.0/255.255.255.0",
+// so a network mask is converted to a prefix length, while any other mask is applied to the address, and masks must also be allowed with AllowMask.
+func (builder *IPAddressStringParamsBuilder) AllowSpaceSeparatedMask(allow bool) *IPAddressStringParamsBuilder {
+	builder.params.allowSpaceSeparatedMask = allow
+	return builder
+}
+
 // AllowWildcardedSeparator dictates whether the wildcard '*' or '%' can replace the segment separators '.' and ':'.
 // If so, then you can write addresses like *.* or *:*
 func (builder *IPAddressStringParamsBuilder) AllowWildcardedSeparator(allow bool) *IPAddressStringParamsBuilder {
@@ -421,6 +529,30 @@ func (builder *IPAddressStringParamsBuilder) Allow_inet_aton(allow bool) *IPAddr
 	return builder
 }
 
+// Set_inet_aton_strict restricts IPv4 to the four-segment dotted-decimal format of RFC 3986 host parsing,
+// in which each segment is a decimal value from 0 to 255 with no leading zeros,
+// disallowing all inet_aton formats, both for IPv4 addresses and for the embedded IPv4 section of mixed IPv6/v4 addresses,
+// as well as single-segment addresses like "16909060".  See IPv4AddressStringParamsBuilder.Set_inet_aton_strict for the formats disallowed.
+func (builder *IPAddressStringParamsBuilder) Set_inet_aton_strict() *IPAddressStringParamsBuilder {
+	builder.GetIPv4AddressParamsBuilder().Set_inet_aton_strict()
+	if embedded := builder.GetIPv6AddressParamsBuilder().getEmbeddedIPv4ParametersBuilder(); embedded != nil {
+		embedded.GetIPv4AddressParamsBuilder().Set_inet_aton_strict()
+	}
+	return builder.AllowSingleSegment(false)
+}
+
+// Set_inet_aton_permissive allows all IPv4 inet_aton formats, both for IPv4 addresses and for the embedded IPv4 section of mixed IPv6/v4 addresses,
+// as well as single-segment addresses like "16909060".  See IPv4AddressStringParamsBuilder.Set_inet_aton_permissive for the formats allowed.
+func (builder *IPAddressStringParamsBuilder) Set_inet_aton_permissive() *IPAddressStringParamsBuilder {
+	builder.GetIPv4AddressParamsBuilder().Set_inet_aton_permissive()
+	ipv6Builder := builder.GetIPv6AddressParamsBuilder()
+	ipv6Builder.Allow_mixed_inet_aton(true)
+	if embedded := ipv6Builder.getEmbeddedIPv4ParametersBuilder(); embedded != nil {
+		embedded.GetIPv4AddressParamsBuilder().Set_inet_aton_permissive()
+	}
+	return builder.AllowSingleSegment(true)
+}
+
 type ipAddressStringFormatParameters struct {
 	addressStringFormatParameters
 
@@ -510,6 +642,8 @@ type ipv6AddressStringParameters struct {
 
 	noMixed, noZone, noBase85, noEmptyZone bool
 
+	allowURIZoneEncoding bool
+
 	embeddedParams *ipAddressStringParameters
 }
 
@@ -528,6 +662,11 @@ func (params *ipv6AddressStringParameters) AllowsEmptyZone() bool {
 	return !params.noEmptyZone
 }
 
+// AllowsURIZoneEncoding allows zones percent-encoded as in URIs, following RFC 6874, like "fe80::1%25eth0" for the zone "eth0".
+func (params *ipv6AddressStringParameters) AllowsURIZoneEncoding() bool {
+	return params.allowURIZoneEncoding
+}
+
 // AllowsBase85 allows IPv6 single-segment base 85 addresses'
 func (params *ipv6AddressStringParameters) AllowsBase85() bool {
 	return !params.noBase85
@@ -593,6 +732,11 @@ func (builder *IPv6AddressStringParamsBuilder) AllowsEmptyZone() bool {
 	return builder.params.AllowsEmptyZone()
 }
 
+// AllowsURIZoneEncoding allows zones percent-encoded as in URIs, following RFC 6874, like "fe80::1%25eth0" for the zone "eth0".
+func (builder *IPv6AddressStringParamsBuilder) AllowsURIZoneEncoding() bool {
+	return builder.params.AllowsURIZoneEncoding()
+}
+
 // AllowsBase85 allows IPv6 single-segment base 85 addresses.
 func (builder *IPv6AddressStringParamsBuilder) AllowsBase85() bool {
 	return builder.params.AllowsBase85()
@@ -618,6 +762,8 @@ func (builder *IPv6AddressStringParamsBuilder) set(params IPv6AddressStringParam
 			noZone:      !params.AllowsZone(),
 			noEmptyZone: !params.AllowsEmptyZone(),
 			noBase85:    !params.AllowsBase85(),
+
+			allowURIZoneEncoding: allowsURIZoneEncoding(params),
 		}
 	}
 	builder.IPAddressStringFormatParamsBuilder.set(params)
@@ -652,6 +798,18 @@ func (builder *IPv6AddressStringParamsBuilder) AllowEmptyZone(allow bool) *IPv6A
 	return builder
 }
 
+// AllowURIZoneEncoding dictates whether to allow zones percent-encoded as in URIs, following RFC 6874.
+// When allowed, the zone separator may itself be encoded as "%25", as in "fe80::1%25eth0" for the zone "eth0",
+// and other characters of the zone may be percent-encoded, as in "fe80::1%25en%301" for the zone "en01".
+// Zones that are not percent-encoded, like "fe80::1%eth0", remain valid.  Zones are only allowed when AllowsZone is also true.
+func (builder *IPv6AddressStringParamsBuilder) AllowURIZoneEncoding(allow bool) *IPv6AddressStringParamsBuilder {
+	builder.params.allowURIZoneEncoding = allow
+	if ipv4Builder := builder.getEmbeddedIPv4ParametersBuilder(); ipv4Builder != nil {
+		ipv4Builder.GetIPv6AddressParamsBuilder().params.allowURIZoneEncoding = allow
+	}
+	return builder
+}
+
 // AllowMixed dictates whether to allow mixed-in embedded IPv4 like "a:b:c:d:e:f:1.2.3.4".
 func (builder *IPv6AddressStringParamsBuilder) AllowMixed(allow bool) *IPv6AddressStringParamsBuilder {
 	builder.params.noMixed = !allow
@@ -748,6 +906,8 @@ type ipv4AddressStringParameters struct {
 	no_inet_aton_hex,
 	no_inet_aton_octal,
 	no_inet_aton_joinedSegments,
+	no_inet_aton_two_segments,
+	no_inet_aton_three_segments,
 	inet_aton_single_segment_mask,
 	no_inet_aton_leading_zeros bool
 }
@@ -770,6 +930,22 @@ func (params *ipv4AddressStringParameters) Allows_inet_aton_joinedSegments() boo
 	return !params.no_inet_aton_joinedSegments
 }
 
+// Allows_inet_aton_joinedSegmentCount indicates whether IPv4 joined segments are allowed for addresses with the given number of segments,
+// either 2 as in "1.2" or 3 as in "1.2.3".
+// It returns false when Allows_inet_aton_joinedSegments is false, and for any other segment count.
+func (params *ipv4AddressStringParameters) Allows_inet_aton_joinedSegmentCount(segmentCount int) bool {
+	if params.no_inet_aton_joinedSegments {
+		return false
+	}
+	switch segmentCount {
+	case 2:
+		return !params.no_inet_aton_two_segments
+	case 3:
+		return !params.no_inet_aton_three_segments
+	}
+	return false
+}
+
 // Allows_inet_aton_single_segment_mask indicates whether you allow a mask that looks like a prefix length when you allow IPv4 joined segments: "1.2.3.5/255".
 func (params *ipv4AddressStringParameters) Allows_inet_aton_single_segment_mask() bool {
 	return params.inet_aton_single_segment_mask
@@ -823,6 +999,8 @@ func (builder *IPv4AddressStringParamsBuilder) Set(params IPv4AddressStringParam
 			no_inet_aton_hex:              !params.Allows_inet_aton_hex(),
 			no_inet_aton_octal:            !params.Allows_inet_aton_octal(),
 			no_inet_aton_joinedSegments:   !params.Allows_inet_aton_joinedSegments(),
+			no_inet_aton_two_segments:     params.Allows_inet_aton_joinedSegments() && !allows_inet_aton_joinedSegmentCount(params, 2),
+			no_inet_aton_three_segments:   params.Allows_inet_aton_joinedSegments() && !allows_inet_aton_joinedSegmentCount(params, 3),
 			inet_aton_single_segment_mask: params.Allows_inet_aton_single_segment_mask(),
 			no_inet_aton_leading_zeros:    !params.Allows_inet_aton_leading_zeros(),
 		}
@@ -868,12 +1046,68 @@ func (builder *IPv4AddressStringParamsBuilder) Allow_inet_aton_joinedSegments(al
 	return builder
 }
 
+// Allow_inet_aton_joinedSegmentCount dictates whether to allow IPv4 joined segments for addresses with the given number of segments,
+// either 2 as in "1.2" or 3 as in "1.2.3".  Other segment counts are ignored.
+//
+// This refines Allow_inet_aton_joinedSegments, which must also allow joined segments for addresses with the given number of segments to be allowed.
+func (builder *IPv4AddressStringParamsBuilder) Allow_inet_aton_joinedSegmentCount(segmentCount int, allow bool) *IPv4AddressStringParamsBuilder {
+	switch segmentCount {
+	case 2:
+		builder.params.no_inet_aton_two_segments = !allow
+	case 3:
+		builder.params.no_inet_aton_three_segments = !allow
+	}
+	return builder
+}
+
 // Allow_inet_aton_single_segment_mask dictates whether to allow a mask that looks like a prefix length when you allow IPv4 joined segments: "1.2.3.5/255".
 func (builder *IPv4AddressStringParamsBuilder) Allow_inet_aton_single_segment_mask(allow bool) *IPv4AddressStringParamsBuilder {
 	builder.params.inet_aton_single_segment_mask = allow
 	return builder
 }
 
+// Set_inet_aton_strict disallows every IPv4 format beyond the four-segment dotted-decimal format of RFC 3986,
+// in which each segment is a decimal value from 0 to 255 with no leading zeros, "1.2.3.4" being an example.
+// Disallowed are:
+//   - inet_aton hexadecimal segments, such as "0xa.0xb.0xc.0xd",
+//   - inet_aton octal segments, such as "04.05.06.07",
+//   - inet_aton joined segments, such as "1.2.3" or "1.2",
+//   - leading zeros, such as "001.2.3.004", whether decimal, hexadecimal or octal,
+//   - binary segments, such as "11111111.0.1.0", and
+//   - a joined segment mask that looks like a prefix length, such as "1.2.3.5/255".
+//
+// Single-segment addresses like "16909060" are controlled by AllowSingleSegment of IPAddressStringParamsBuilder, see IPAddressStringParamsBuilder.Set_inet_aton_strict.
+func (builder *IPv4AddressStringParamsBuilder) Set_inet_aton_strict() *IPv4AddressStringParamsBuilder {
+	builder.Allow_inet_aton(false)
+	builder.params.no_inet_aton_two_segments = true
+	builder.params.no_inet_aton_three_segments = true
+	builder.params.no_inet_aton_leading_zeros = true
+	builder.params.inet_aton_single_segment_mask = false
+	builder.allowLeadingZeros(false)
+	builder.allowBinary(false)
+	return builder
+}
+
+// Set_inet_aton_permissive allows every IPv4 inet_aton format:
+//   - inet_aton hexadecimal segments, such as "0xa.0xb.0xc.0xd",
+//   - inet_aton octal segments, such as "04.05.06.07",
+//   - inet_aton joined segments, whether three segments like "1.2.3" or two segments like "1.2",
+//   - leading zeros in hexadecimal or octal segments, such as "0x0a.00b.c.d", and
+//   - leading zeros extending segments beyond the usual length, such as "0001.0002.0003.0004".
+//
+// Leading zeros are allowed, since they denote hexadecimal and octal segments, so segments with leading zeros like "010" are parsed as octal.
+//
+// The joined segment mask that looks like a prefix length, such as "1.2.3.5/255", is not affected, being ambiguous with a prefix length.
+// Single-segment addresses like "16909060" are controlled by AllowSingleSegment of IPAddressStringParamsBuilder, see IPAddressStringParamsBuilder.Set_inet_aton_permissive.
+func (builder *IPv4AddressStringParamsBuilder) Set_inet_aton_permissive() *IPv4AddressStringParamsBuilder {
+	builder.Allow_inet_aton(true)
+	builder.params.no_inet_aton_two_segments = false
+	builder.params.no_inet_aton_three_segments = false
+	builder.params.no_inet_aton_leading_zeros = false
+	builder.allowLeadingZeros(true)
+	return builder
+}
+
 // AllowWildcardedSeparator dictates whether the wildcard '*' or '%' can replace the segment separators '.' and ':'.
 // If so, then you can write addresses like *.* or *:*
 func (builder *IPv4AddressStringParamsBuilder) AllowWildcardedSeparator(allow bool) *IPv4AddressStringParamsBuilder {
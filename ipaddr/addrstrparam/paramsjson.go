@@ -0,0 +1,492 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package addrstrparam
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// The JSON representation of parameters only records the settings that differ from the defaults of a zero-value builder,
+// so the parameters used by HostName, IPAddressString and MACAddressString when none are specified serialize to "{}".
+// When unmarshalling, any setting that is absent keeps its default value.
+// Each boolean setting is named in lower camel case after its accessor without the "Allows" prefix,
+// so AllowsWildcard is "wildcard" and Allows_inet_aton_hex is "inetAtonHex".
+
+type rangeParamsJSON struct {
+	Wildcard         *bool `json:"wildcard,omitempty"`
+	RangeSeparator   *bool `json:"rangeSeparator,omitempty"`
+	ReverseRange     *bool `json:"reverseRange,omitempty"`
+	SingleWildcard   *bool `json:"singleWildcard,omitempty"`
+	InferredBoundary *bool `json:"inferredBoundary,omitempty"`
+}
+
+type formatParamsJSON struct {
+	WildcardedSeparator   *bool            `json:"wildcardedSeparator,omitempty"`
+	LeadingZeros          *bool            `json:"leadingZeros,omitempty"`
+	UnlimitedLeadingZeros *bool            `json:"unlimitedLeadingZeros,omitempty"`
+	RangeParams           *rangeParamsJSON `json:"rangeParams,omitempty"`
+}
+
+type ipFormatParamsJSON struct {
+	formatParamsJSON
+	PrefixesBeyondAddressSize *bool `json:"prefixesBeyondAddressSize,omitempty"`
+	PrefixLenLeadingZeros     *bool `json:"prefixLenLeadingZeros,omitempty"`
+	Binary                    *bool `json:"binary,omitempty"`
+}
+
+type ipv4ParamsJSON struct {
+	ipFormatParamsJSON
+	InetAtonHex               *bool `json:"inetAtonHex,omitempty"`
+	InetAtonOctal             *bool `json:"inetAtonOctal,omitempty"`
+	InetAtonJoinedSegments    *bool `json:"inetAtonJoinedSegments,omitempty"`
+	InetAtonTwoSegments       *bool `json:"inetAtonTwoSegments,omitempty"`
+	InetAtonThreeSegments     *bool `json:"inetAtonThreeSegments,omitempty"`
+	InetAtonSingleSegmentMask *bool `json:"inetAtonSingleSegmentMask,omitempty"`
+	InetAtonLeadingZeros      *bool `json:"inetAtonLeadingZeros,omitempty"`
+}
+
+type ipv6ParamsJSON struct {
+	ipFormatParamsJSON
+	Mixed           *bool           `json:"mixed,omitempty"`
+	Zone            *bool           `json:"zone,omitempty"`
+	EmptyZone       *bool           `json:"emptyZone,omitempty"`
+	URIZoneEncoding *bool           `json:"uriZoneEncoding,omitempty"`
+	Base85          *bool           `json:"base85,omitempty"`
+	EmbeddedIPv4    *ipv4ParamsJSON `json:"embeddedIPv4Params,omitempty"`
+}
+
+type addressParamsJSON struct {
+	Empty         *bool `json:"empty,omitempty"`
+	SingleSegment *bool `json:"singleSegment,omitempty"`
+	All           *bool `json:"all,omitempty"`
+}
+
+type ipAddressParamsJSON struct {
+	addressParamsJSON
+	Prefix           *bool           `json:"prefix,omitempty"`
+	Mask             *bool           `json:"mask,omitempty"`
+	IPv4             *bool           `json:"ipv4,omitempty"`
+	IPv6             *bool           `json:"ipv6,omitempty"`
+	ReverseDNS       *bool           `json:"reverseDNS,omitempty"`
+	SpaceMask        *bool           `json:"spaceSeparatedMask,omitempty"`
+	PreferredVersion IPVersion       `json:"preferredVersion,omitempty"`
+	EmptyStrParsedAs EmptyStrOption  `json:"emptyStrParsedAs,omitempty"`
+	AllStrParsedAs   AllStrOption    `json:"allStrParsedAs,omitempty"`
+	IPv4Params       *ipv4ParamsJSON `json:"ipv4Params,omitempty"`
+	IPv6Params       *ipv6ParamsJSON `json:"ipv6Params,omitempty"`
+}
+
+type hostNameParamsJSON struct {
+	Empty                 *bool                `json:"empty,omitempty"`
+	PreferredVersion      IPVersion            `json:"preferredVersion,omitempty"`
+	BracketedIPv4         *bool                `json:"bracketedIPv4,omitempty"`
+	BracketedIPv6         *bool                `json:"bracketedIPv6,omitempty"`
+	NormalizeToLowercase  *bool                `json:"normalizeToLowercase,omitempty"`
+	IPAddress             *bool                `json:"ipAddress,omitempty"`
+	Port                  *bool                `json:"port,omitempty"`
+	Service               *bool                `json:"service,omitempty"`
+	ExpectPort            *bool                `json:"expectPort,omitempty"`
+	IPAddressStringParams *ipAddressParamsJSON `json:"ipAddressParams,omitempty"`
+}
+
+type macFormatParamsJSON struct {
+	formatParamsJSON
+	ShortSegments *bool `json:"shortSegments,omitempty"`
+}
+
+type macAddressParamsJSON struct {
+	addressParamsJSON
+	PreferredLen   MACAddressLen        `json:"preferredLen,omitempty"`
+	Dashed         *bool                `json:"dashed,omitempty"`
+	SingleDashed   *bool                `json:"singleDashed,omitempty"`
+	ColonDelimited *bool                `json:"colonDelimited,omitempty"`
+	Dotted         *bool                `json:"dotted,omitempty"`
+	SpaceDelimited *bool                `json:"spaceDelimited,omitempty"`
+	FormatParams   *macFormatParamsJSON `json:"formatParams,omitempty"`
+}
+
+// MarshalHostNameParams produces a JSON representation of the given HostNameParams,
+// which can be stored in configuration and converted back to an equivalent HostNameParams with UnmarshalHostNameParams.
+// Only the settings that differ from those of a zero-value HostNameParamsBuilder are included.
+func MarshalHostNameParams(params HostNameParams) ([]byte, error) {
+	return json.Marshal(toHostNameParamsJSON(params))
+}
+
+// UnmarshalHostNameParams reconstructs an immutable HostNameParams from the JSON representation produced by MarshalHostNameParams.
+// Settings missing from the JSON have their default values.  Unknown settings or invalid option values produce an error.
+func UnmarshalHostNameParams(data []byte) (HostNameParams, error) {
+	var js hostNameParamsJSON
+	if err := decodeParamsJSON(data, &js); err != nil {
+		return nil, err
+	}
+	builder := new(HostNameParamsBuilder)
+	if err := js.apply(builder); err != nil {
+		return nil, err
+	}
+	return builder.ToParams(), nil
+}
+
+// MarshalIPAddressStringParams produces a JSON representation of the given IPAddressStringParams,
+// which can be stored in configuration and converted back to an equivalent IPAddressStringParams with UnmarshalIPAddressStringParams.
+// Only the settings that differ from those of a zero-value IPAddressStringParamsBuilder are included.
+func MarshalIPAddressStringParams(params IPAddressStringParams) ([]byte, error) {
+	result := toIPAddressParamsJSON(params)
+	if result == nil {
+		result = &ipAddressParamsJSON{}
+	}
+	return json.Marshal(result)
+}
+
+// UnmarshalIPAddressStringParams reconstructs an immutable IPAddressStringParams from the JSON representation produced by MarshalIPAddressStringParams.
+// Settings missing from the JSON have their default values.  Unknown settings or invalid option values produce an error.
+func UnmarshalIPAddressStringParams(data []byte) (IPAddressStringParams, error) {
+	var js ipAddressParamsJSON
+	if err := decodeParamsJSON(data, &js); err != nil {
+		return nil, err
+	}
+	builder := new(IPAddressStringParamsBuilder)
+	if err := js.apply(builder); err != nil {
+		return nil, err
+	}
+	return builder.ToParams(), nil
+}
+
+// MarshalMACAddressStringParams produces a JSON representation of the given MACAddressStringParams,
+// which can be stored in configuration and converted back to an equivalent MACAddressStringParams with UnmarshalMACAddressStringParams.
+// Only the settings that differ from those of a zero-value MACAddressStringParamsBuilder are included.
+func MarshalMACAddressStringParams(params MACAddressStringParams) ([]byte, error) {
+	return json.Marshal(toMACAddressParamsJSON(params))
+}
+
+// UnmarshalMACAddressStringParams reconstructs an immutable MACAddressStringParams from the JSON representation produced by MarshalMACAddressStringParams.
+// Settings missing from the JSON have their default values.  Unknown settings or invalid option values produce an error.
+func UnmarshalMACAddressStringParams(data []byte) (MACAddressStringParams, error) {
+	var js macAddressParamsJSON
+	if err := decodeParamsJSON(data, &js); err != nil {
+		return nil, err
+	}
+	builder := new(MACAddressStringParamsBuilder)
+	if err := js.apply(builder); err != nil {
+		return nil, err
+	}
+	return builder.ToParams(), nil
+}
+
+func decodeParamsJSON(data []byte, target interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(target)
+}
+
+// diffBool returns nil when the value matches the default, so that it is omitted from the JSON.
+func diffBool(value, defaultValue bool) *bool {
+	if value == defaultValue {
+		return nil
+	}
+	return &value
+}
+
+func applyBool(value *bool, setter func(bool)) {
+	if value != nil {
+		setter(*value)
+	}
+}
+
+func toRangeParamsJSON(params, defaults RangeParams) *rangeParamsJSON {
+	result := rangeParamsJSON{
+		Wildcard:         diffBool(params.AllowsWildcard(), defaults.AllowsWildcard()),
+		RangeSeparator:   diffBool(params.AllowsRangeSeparator(), defaults.AllowsRangeSeparator()),
+		ReverseRange:     diffBool(params.AllowsReverseRange(), defaults.AllowsReverseRange()),
+		SingleWildcard:   diffBool(params.AllowsSingleWildcard(), defaults.AllowsSingleWildcard()),
+		InferredBoundary: diffBool(params.AllowsInferredBoundary(), defaults.AllowsInferredBoundary()),
+	}
+	if result == (rangeParamsJSON{}) {
+		return nil
+	}
+	return &result
+}
+
+func (js *rangeParamsJSON) apply(builder *RangeParamsBuilder) {
+	if js == nil {
+		return
+	}
+	applyBool(js.Wildcard, func(allow bool) { builder.AllowWildcard(allow) })
+	applyBool(js.RangeSeparator, func(allow bool) { builder.AllowRangeSeparator(allow) })
+	applyBool(js.ReverseRange, func(allow bool) { builder.AllowReverseRange(allow) })
+	applyBool(js.SingleWildcard, func(allow bool) { builder.AllowSingleWildcard(allow) })
+	applyBool(js.InferredBoundary, func(allow bool) { builder.AllowInferredBoundary(allow) })
+}
+
+func toFormatParamsJSON(params, defaults AddressStringFormatParams) formatParamsJSON {
+	return formatParamsJSON{
+		WildcardedSeparator:   diffBool(params.AllowsWildcardedSeparator(), defaults.AllowsWildcardedSeparator()),
+		LeadingZeros:          diffBool(params.AllowsLeadingZeros(), defaults.AllowsLeadingZeros()),
+		UnlimitedLeadingZeros: diffBool(params.AllowsUnlimitedLeadingZeros(), defaults.AllowsUnlimitedLeadingZeros()),
+		RangeParams:           toRangeParamsJSON(params.GetRangeParams(), defaults.GetRangeParams()),
+	}
+}
+
+// apply uses the unexported builder methods, which unlike the exported IPv6 builder methods,
+// do not carry the settings over to the embedded IPv4 parameters, which are serialized separately.
+func (js *formatParamsJSON) apply(builder *AddressStringFormatParamsBuilder) {
+	applyBool(js.WildcardedSeparator, builder.allowWildcardedSeparator)
+	applyBool(js.LeadingZeros, builder.allowLeadingZeros)
+	applyBool(js.UnlimitedLeadingZeros, builder.allowUnlimitedLeadingZeros)
+	js.RangeParams.apply(&builder.rangeParamsBuilder)
+}
+
+func toIPFormatParamsJSON(params, defaults IPAddressStringFormatParams) ipFormatParamsJSON {
+	return ipFormatParamsJSON{
+		formatParamsJSON:          toFormatParamsJSON(params, defaults),
+		PrefixesBeyondAddressSize: diffBool(params.AllowsPrefixesBeyondAddressSize(), defaults.AllowsPrefixesBeyondAddressSize()),
+		PrefixLenLeadingZeros:     diffBool(params.AllowsPrefixLenLeadingZeros(), defaults.AllowsPrefixLenLeadingZeros()),
+		Binary:                    diffBool(params.AllowsBinary(), defaults.AllowsBinary()),
+	}
+}
+
+func (js *ipFormatParamsJSON) apply(builder *IPAddressStringFormatParamsBuilder) {
+	js.formatParamsJSON.apply(&builder.AddressStringFormatParamsBuilder)
+	applyBool(js.PrefixesBeyondAddressSize, builder.allowPrefixesBeyondAddressSize)
+	applyBool(js.PrefixLenLeadingZeros, builder.allowPrefixLengthLeadingZeros)
+	applyBool(js.Binary, builder.allowBinary)
+}
+
+func toIPv4ParamsJSON(params, defaults IPv4AddressStringParams) *ipv4ParamsJSON {
+	result := ipv4ParamsJSON{
+		ipFormatParamsJSON:        toIPFormatParamsJSON(params, defaults),
+		InetAtonHex:               diffBool(params.Allows_inet_aton_hex(), defaults.Allows_inet_aton_hex()),
+		InetAtonOctal:             diffBool(params.Allows_inet_aton_octal(), defaults.Allows_inet_aton_octal()),
+		InetAtonJoinedSegments:    diffBool(params.Allows_inet_aton_joinedSegments(), defaults.Allows_inet_aton_joinedSegments()),
+		InetAtonTwoSegments:       diffBool(allowsJoinedSegmentCount(params, 2), allowsJoinedSegmentCount(defaults, 2)),
+		InetAtonThreeSegments:     diffBool(allowsJoinedSegmentCount(params, 3), allowsJoinedSegmentCount(defaults, 3)),
+		InetAtonSingleSegmentMask: diffBool(params.Allows_inet_aton_single_segment_mask(), defaults.Allows_inet_aton_single_segment_mask()),
+		InetAtonLeadingZeros:      diffBool(params.Allows_inet_aton_leading_zeros(), defaults.Allows_inet_aton_leading_zeros()),
+	}
+	if result == (ipv4ParamsJSON{}) {
+		return nil
+	}
+	return &result
+}
+
+// allowsJoinedSegmentCount returns whether the given count of joined segments is allowed, independent of whether joined segments are allowed at all
+func allowsJoinedSegmentCount(params IPv4AddressStringParams, segmentCount int) bool {
+	if p, ok := params.(*ipv4AddressStringParameters); ok {
+		switch segmentCount {
+		case 2:
+			return !p.no_inet_aton_two_segments
+		case 3:
+			return !p.no_inet_aton_three_segments
+		}
+	}
+	return !params.Allows_inet_aton_joinedSegments() || allows_inet_aton_joinedSegmentCount(params, segmentCount)
+}
+
+func (js *ipv4ParamsJSON) apply(builder *IPv4AddressStringParamsBuilder) {
+	if js == nil {
+		return
+	}
+	js.ipFormatParamsJSON.apply(&builder.IPAddressStringFormatParamsBuilder)
+	applyBool(js.InetAtonHex, func(allow bool) { builder.Allow_inet_aton_hex(allow) })
+	applyBool(js.InetAtonOctal, func(allow bool) { builder.Allow_inet_aton_octal(allow) })
+	applyBool(js.InetAtonJoinedSegments, func(allow bool) { builder.Allow_inet_aton_joinedSegments(allow) })
+	applyBool(js.InetAtonTwoSegments, func(allow bool) { builder.Allow_inet_aton_joinedSegmentCount(2, allow) })
+	applyBool(js.InetAtonThreeSegments, func(allow bool) { builder.Allow_inet_aton_joinedSegmentCount(3, allow) })
+	applyBool(js.InetAtonSingleSegmentMask, func(allow bool) { builder.Allow_inet_aton_single_segment_mask(allow) })
+	applyBool(js.InetAtonLeadingZeros, func(allow bool) { builder.Allow_inet_aton_leading_zeros(allow) })
+}
+
+func toIPv6ParamsJSON(params, defaults IPv6AddressStringParams) *ipv6ParamsJSON {
+	result := ipv6ParamsJSON{
+		ipFormatParamsJSON: toIPFormatParamsJSON(params, defaults),
+		Mixed:              diffBool(params.AllowsMixed(), defaults.AllowsMixed()),
+		Zone:               diffBool(params.AllowsZone(), defaults.AllowsZone()),
+		EmptyZone:          diffBool(params.AllowsEmptyZone(), defaults.AllowsEmptyZone()),
+		URIZoneEncoding:    diffBool(allowsURIZoneEncoding(params), allowsURIZoneEncoding(defaults)),
+		Base85:             diffBool(params.AllowsBase85(), defaults.AllowsBase85()),
+		EmbeddedIPv4:       toIPv4ParamsJSON(params.GetEmbeddedIPv4AddressParams(), defaults.GetEmbeddedIPv4AddressParams()),
+	}
+	if result == (ipv6ParamsJSON{}) {
+		return nil
+	}
+	return &result
+}
+
+func (js *ipv6ParamsJSON) apply(builder *IPv6AddressStringParamsBuilder) {
+	if js == nil {
+		return
+	}
+	js.ipFormatParamsJSON.apply(&builder.IPAddressStringFormatParamsBuilder)
+	applyBool(js.Mixed, func(allow bool) { builder.AllowMixed(allow) })
+	applyBool(js.Zone, func(allow bool) { builder.AllowZone(allow) })
+	applyBool(js.EmptyZone, func(allow bool) { builder.AllowEmptyZone(allow) })
+	applyBool(js.URIZoneEncoding, func(allow bool) { builder.AllowURIZoneEncoding(allow) })
+	applyBool(js.Base85, func(allow bool) { builder.AllowBase85(allow) })
+	js.EmbeddedIPv4.apply(builder.GetEmbeddedIPv4AddressParamsBuilder())
+}
+
+func toAddressParamsJSON(params, defaults AddressStringParams) addressParamsJSON {
+	return addressParamsJSON{
+		Empty:         diffBool(params.AllowsEmpty(), defaults.AllowsEmpty()),
+		SingleSegment: diffBool(params.AllowsSingleSegment(), defaults.AllowsSingleSegment()),
+		All:           diffBool(params.AllowsAll(), defaults.AllowsAll()),
+	}
+}
+
+func (js *addressParamsJSON) apply(builder *AddressStringParamsBuilder) {
+	applyBool(js.Empty, builder.allowEmpty)
+	applyBool(js.SingleSegment, builder.allowSingleSegment)
+	applyBool(js.All, builder.allowAll)
+}
+
+func toIPAddressParamsJSON(params IPAddressStringParams) *ipAddressParamsJSON {
+	defaults := new(IPAddressStringParamsBuilder).ToParams()
+	result := ipAddressParamsJSON{
+		addressParamsJSON: toAddressParamsJSON(params, defaults),
+		Prefix:            diffBool(params.AllowsPrefix(), defaults.AllowsPrefix()),
+		Mask:              diffBool(params.AllowsMask(), defaults.AllowsMask()),
+		IPv4:              diffBool(params.AllowsIPv4(), defaults.AllowsIPv4()),
+		IPv6:              diffBool(params.AllowsIPv6(), defaults.AllowsIPv6()),
+		ReverseDNS:        diffBool(allowsReverseDNS(params), allowsReverseDNS(defaults)),
+		SpaceMask:         diffBool(allowsSpaceSeparatedMask(params), allowsSpaceSeparatedMask(defaults)),
+		PreferredVersion:  params.GetPreferredVersion(),
+		EmptyStrParsedAs:  params.EmptyStrParsedAs(),
+		AllStrParsedAs:    params.AllStrParsedAs(),
+		IPv4Params:        toIPv4ParamsJSON(params.GetIPv4Params(), defaults.GetIPv4Params()),
+		IPv6Params:        toIPv6ParamsJSON(params.GetIPv6Params(), defaults.GetIPv6Params()),
+	}
+	if result == (ipAddressParamsJSON{}) {
+		return nil
+	}
+	return &result
+}
+
+func (js *ipAddressParamsJSON) apply(builder *IPAddressStringParamsBuilder) error {
+	if js == nil {
+		return nil
+	}
+	if err := checkIPVersion(js.PreferredVersion); err != nil {
+		return err
+	}
+	switch js.EmptyStrParsedAs {
+	case NoAddressOption, ZeroAddressOption, LoopbackOption:
+	default:
+		return fmt.Errorf("invalid empty string option %q", js.EmptyStrParsedAs)
+	}
+	switch js.AllStrParsedAs {
+	case AllAddresses, AllPreferredIPVersion:
+	default:
+		return fmt.Errorf("invalid all string option %q", js.AllStrParsedAs)
+	}
+	builder.SetPreferredVersion(js.PreferredVersion)
+	builder.ParseAllStrAs(js.AllStrParsedAs)
+	// ParseEmptyStrAs also allows empty strings, so the "empty" setting must be applied afterwards
+	builder.ParseEmptyStrAs(js.EmptyStrParsedAs)
+	js.addressParamsJSON.apply(&builder.AddressStringParamsBuilder)
+	applyBool(js.Prefix, func(allow bool) { builder.AllowPrefix(allow) })
+	applyBool(js.Mask, func(allow bool) { builder.AllowMask(allow) })
+	applyBool(js.IPv4, func(allow bool) { builder.AllowIPv4(allow) })
+	applyBool(js.IPv6, func(allow bool) { builder.AllowIPv6(allow) })
+	applyBool(js.ReverseDNS, func(allow bool) { builder.AllowReverseDNS(allow) })
+	applyBool(js.SpaceMask, func(allow bool) { builder.AllowSpaceSeparatedMask(allow) })
+	js.IPv4Params.apply(builder.GetIPv4AddressParamsBuilder())
+	js.IPv6Params.apply(builder.GetIPv6AddressParamsBuilder())
+	return nil
+}
+
+func toHostNameParamsJSON(params HostNameParams) *hostNameParamsJSON {
+	defaults := new(HostNameParamsBuilder).ToParams()
+	return &hostNameParamsJSON{
+		Empty:                 diffBool(params.AllowsEmpty(), defaults.AllowsEmpty()),
+		PreferredVersion:      params.GetPreferredVersion(),
+		BracketedIPv4:         diffBool(params.AllowsBracketedIPv4(), defaults.AllowsBracketedIPv4()),
+		BracketedIPv6:         diffBool(params.AllowsBracketedIPv6(), defaults.AllowsBracketedIPv6()),
+		NormalizeToLowercase:  diffBool(params.NormalizesToLowercase(), defaults.NormalizesToLowercase()),
+		IPAddress:             diffBool(params.AllowsIPAddress(), defaults.AllowsIPAddress()),
+		Port:                  diffBool(params.AllowsPort(), defaults.AllowsPort()),
+		Service:               diffBool(params.AllowsService(), defaults.AllowsService()),
+		ExpectPort:            diffBool(params.ExpectsPort(), defaults.ExpectsPort()),
+		IPAddressStringParams: toIPAddressParamsJSON(params.GetIPAddressParams()),
+	}
+}
+
+func (js *hostNameParamsJSON) apply(builder *HostNameParamsBuilder) error {
+	if err := checkIPVersion(js.PreferredVersion); err != nil {
+		return err
+	}
+	applyBool(js.Empty, func(allow bool) { builder.AllowEmpty(allow) })
+	applyBool(js.BracketedIPv4, func(allow bool) { builder.AllowBracketedIPv4(allow) })
+	applyBool(js.BracketedIPv6, func(allow bool) { builder.AllowBracketedIPv6(allow) })
+	applyBool(js.NormalizeToLowercase, func(allow bool) { builder.NormalizeToLowercase(allow) })
+	applyBool(js.IPAddress, func(allow bool) { builder.AllowIPAddress(allow) })
+	applyBool(js.Port, func(allow bool) { builder.AllowPort(allow) })
+	applyBool(js.Service, func(allow bool) { builder.AllowService(allow) })
+	applyBool(js.ExpectPort, func(expect bool) { builder.ExpectPort(expect) })
+	builder.SetPreferredVersion(js.PreferredVersion)
+	return js.IPAddressStringParams.apply(builder.GetIPAddressParamsBuilder())
+}
+
+func toMACAddressParamsJSON(params MACAddressStringParams) *macAddressParamsJSON {
+	defaults := new(MACAddressStringParamsBuilder).ToParams()
+	result := &macAddressParamsJSON{
+		addressParamsJSON: toAddressParamsJSON(params, defaults),
+		PreferredLen:      params.GetPreferredLen(),
+		Dashed:            diffBool(params.AllowsDashed(), defaults.AllowsDashed()),
+		SingleDashed:      diffBool(params.AllowsSingleDashed(), defaults.AllowsSingleDashed()),
+		ColonDelimited:    diffBool(params.AllowsColonDelimited(), defaults.AllowsColonDelimited()),
+		Dotted:            diffBool(params.AllowsDotted(), defaults.AllowsDotted()),
+		SpaceDelimited:    diffBool(params.AllowsSpaceDelimited(), defaults.AllowsSpaceDelimited()),
+	}
+	formatParams, formatDefaults := params.GetFormatParams(), defaults.GetFormatParams()
+	format := macFormatParamsJSON{
+		formatParamsJSON: toFormatParamsJSON(formatParams, formatDefaults),
+		ShortSegments:    diffBool(formatParams.AllowsShortSegments(), formatDefaults.AllowsShortSegments()),
+	}
+	if format != (macFormatParamsJSON{}) {
+		result.FormatParams = &format
+	}
+	return result
+}
+
+func (js *macAddressParamsJSON) apply(builder *MACAddressStringParamsBuilder) error {
+	switch js.PreferredLen {
+	case MAC48Len, EUI64Len, UnspecifiedMACLen:
+	default:
+		return fmt.Errorf("invalid MAC address length %q", js.PreferredLen)
+	}
+	js.addressParamsJSON.apply(&builder.AddressStringParamsBuilder)
+	applyBool(js.Dashed, func(allow bool) { builder.AllowDashed(allow) })
+	applyBool(js.SingleDashed, func(allow bool) { builder.AllowSingleDashed(allow) })
+	applyBool(js.ColonDelimited, func(allow bool) { builder.AllowColonDelimited(allow) })
+	applyBool(js.Dotted, func(allow bool) { builder.AllowDotted(allow) })
+	applyBool(js.SpaceDelimited, func(allow bool) { builder.AllowSpaceDelimited(allow) })
+	builder.SetPreferredLen(js.PreferredLen)
+	if format := js.FormatParams; format != nil {
+		formatBuilder := builder.GetFormatParamsBuilder()
+		format.formatParamsJSON.apply(&formatBuilder.AddressStringFormatParamsBuilder)
+		applyBool(format.ShortSegments, func(allow bool) { formatBuilder.AllowShortSegments(allow) })
+	}
+	return nil
+}
+
+func checkIPVersion(version IPVersion) error {
+	if version != IndeterminateIPVersion && version.IsIndeterminate() {
+		return fmt.Errorf("invalid IP version %q", version)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+// SubTrie is a view of the part of a trie whose keys are contained by a given prefix block or address, created by Trie.SubTrie.
+//
+// The view is backed by the trie, so changes made to the trie are reflected in the view, and changes made through the view are made to the trie.
+// Keys outside the block are ignored by the operations of the view: they are never found, added or removed.
+// Use Clone to obtain an independent copy of the view as a new trie.
+type SubTrie[T TrieKeyConstraint[T]] struct {
+	trie  *Trie[T]
+	block T
+}
+
+// SubTrie returns a view of the part of this trie whose keys are contained by the given prefix block subnet or individual address.
+// Changes made to this trie are reflected in the view, and changes made through the view are made to this trie.
+//
+// If the argument is not a single address nor prefix block, this method will panic.
+// The [Partition] type can be used to convert the argument to single addresses and prefix blocks before calling this method.
+func (trie *Trie[T]) SubTrie(block T) *SubTrie[T] {
+	return &SubTrie[T]{trie: trie, block: mustBeBlockOrAddress(block)}
+}
+
+// GetBlock returns the prefix block or address containing the keys of this view.
+func (sub *SubTrie[T]) GetBlock() T {
+	return sub.block
+}
+
+// GetTrie returns the trie backing this view.
+func (sub *SubTrie[T]) GetTrie() *Trie[T] {
+	return sub.trie
+}
+
+func (sub *SubTrie[T]) isInBlock(addr T) bool {
+	return sub.block.ToAddressBase().Contains(addr.ToAddressBase())
+}
+
+// GetRoot returns the root node of the sub-trie of the backing trie contained by the block of this view, or nil if there is no such node.
+// The node returned need not be an "added" node, see IsAdded for more details on added nodes.
+func (sub *SubTrie[T]) GetRoot() *TrieNode[T] {
+	return sub.trie.ElementsContainedBy(sub.block)
+}
+
+// Size returns the number of elements in the backing trie contained by the block of this view.
+func (sub *SubTrie[T]) Size() int {
+	if root := sub.GetRoot(); root != nil {
+		return root.Size()
+	}
+	return 0
+}
+
+// IsEmpty returns true if there are no elements in the backing trie contained by the block of this view.
+func (sub *SubTrie[T]) IsEmpty() bool {
+	return sub.Size() == 0
+}
+
+// Contains returns whether the given address or prefix block subnet is contained by the block of this view and is an added element of the backing trie.
+//
+// If the argument is not a single address nor prefix block, this method will panic.
+func (sub *SubTrie[T]) Contains(addr T) bool {
+	return sub.isInBlock(addr) && sub.trie.Contains(addr)
+}
+
+// Add adds the address to the backing trie, if it is contained by the block of this view.
+// Returns true if the address is contained by the block and did not already exist in the trie.
+//
+// If the argument is not a single address nor prefix block, this method will panic.
+func (sub *SubTrie[T]) Add(addr T) bool {
+	return sub.isInBlock(addr) && sub.trie.Add(addr)
+}
+
+// Remove removes the given single address or prefix block subnet from the backing trie, if it is contained by the block of this view.
+// Returns true if the address is contained by the block and was removed.
+//
+// If the argument is not a single address nor prefix block, this method will panic.
+func (sub *SubTrie[T]) Remove(addr T) bool {
+	return sub.isInBlock(addr) && sub.trie.Remove(addr)
+}
+
+// Clear removes all the elements of the backing trie contained by the block of this view.
+func (sub *SubTrie[T]) Clear() {
+	sub.trie.RemoveElementsContainedBy(sub.block)
+}
+
+// Iterator returns an iterator that iterates through the elements of this view in increasing order.
+func (sub *SubTrie[T]) Iterator() Iterator[T] {
+	if root := sub.GetRoot(); root != nil {
+		return root.Iterator()
+	}
+	return nilAddressIterator[T]()
+}
+
+// DescendingIterator returns an iterator that iterates through the elements of this view in decreasing order.
+func (sub *SubTrie[T]) DescendingIterator() Iterator[T] {
+	if root := sub.GetRoot(); root != nil {
+		return root.DescendingIterator()
+	}
+	return nilAddressIterator[T]()
+}
+
+// Clone copies the elements of this view into a new trie, which is not backed by the trie of this view.
+func (sub *SubTrie[T]) Clone() *Trie[T] {
+	if root := sub.GetRoot(); root != nil {
+		return root.AsNewTrie()
+	}
+	return &Trie[T]{}
+}
+
+// String returns a visual representation of the elements of this view, in the format of the String method of Trie.
+// It returns "<nil>" if the receiver is a nil pointer.
+func (sub *SubTrie[T]) String() string {
+	if sub == nil {
+		return nilString()
+	}
+	return sub.Clone().String()
+}
@@ -0,0 +1,240 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AddressTemplateFormatter formats a value for a placeholder of an AddressTemplate.
+// The maximum value is the largest value the placeholder can have, which formatters can use to pad values to a fixed width.
+type AddressTemplateFormatter func(value, maxValue uint64) string
+
+const indexTemplatePlaceholder = "index"
+
+const segmentTemplatePlaceholder = "seg"
+
+const defaultTemplateFormatter = "dec"
+
+var templateFormatters = map[string]AddressTemplateFormatter{
+	"dec": func(value, _ uint64) string {
+		return strconv.FormatUint(value, 10)
+	},
+	"hex": func(value, _ uint64) string {
+		return strconv.FormatUint(value, 16)
+	},
+	"pad": func(value, maxValue uint64) string {
+		return padTemplateValue(value, maxValue, 10)
+	},
+	"padhex": func(value, maxValue uint64) string {
+		return padTemplateValue(value, maxValue, 16)
+	},
+}
+
+func padTemplateValue(value, maxValue uint64, radix int) string {
+	str := strconv.FormatUint(value, radix)
+	if width := len(strconv.FormatUint(maxValue, radix)); len(str) < width {
+		return strings.Repeat("0", width-len(str)) + str
+	}
+	return str
+}
+
+type addressTemplatePart struct {
+	literal      string
+	segmentIndex int // -1 for the index placeholder
+	formatter    AddressTemplateFormatter
+}
+
+// AddressTemplate generates strings such as host names or configuration entries from addresses, created by NewAddressTemplate.
+//
+// A template is a pattern of literal text and placeholders in braces.
+// The placeholder "{segN}" is replaced by the value of segment N of the address, numbering the segments from 1,
+// and the placeholder "{index}" is replaced by the index of the address in the iteration of a subnet, starting from 0.
+// For example, "host-{seg3}-{seg4}.example.com" for the IPv4 address "10.0.1.2" is "host-1-2.example.com".
+//
+// A placeholder can name the formatter for its value following a colon, such as "{seg4:hex}" or "{index:pad}".
+// The available formatters are "dec" for decimal, the default, "hex" for hexadecimal, and "pad" and "padhex" for decimal and hexadecimal
+// zero-padded to the width of the largest value of the placeholder, along with any formatters supplied to NewAddressTemplate.
+// Literal braces are written as "{{" and "}}".
+type AddressTemplate struct {
+	pattern         string
+	parts           []addressTemplatePart
+	maxSegmentIndex int
+}
+
+// NewAddressTemplate parses the given pattern, as described by AddressTemplate.
+// The given formatters are made available to the placeholders of the pattern by name, in addition to, or replacing, the standard formatters.
+//
+// An error is returned if the pattern has unmatched braces, or a placeholder that is unknown or names an unknown formatter.
+func NewAddressTemplate(pattern string, formatters map[string]AddressTemplateFormatter) (*AddressTemplate, error) {
+	template := &AddressTemplate{pattern: pattern, maxSegmentIndex: -1}
+	var literal strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c == '}' {
+			if i+1 < len(pattern) && pattern[i+1] == '}' {
+				literal.WriteByte(c)
+				i++
+				continue
+			}
+			return nil, errorF("unmatched '}' at index %d of address template %s", i, pattern)
+		} else if c != '{' {
+			literal.WriteByte(c)
+			continue
+		} else if i+1 < len(pattern) && pattern[i+1] == '{' {
+			literal.WriteByte(c)
+			i++
+			continue
+		}
+		end := strings.IndexByte(pattern[i:], '}')
+		if end < 0 {
+			return nil, errorF("unmatched '{' at index %d of address template %s", i, pattern)
+		}
+		part, err := newAddressTemplatePart(pattern[i+1:i+end], formatters)
+		if err != nil {
+			return nil, errorF("%v in address template %s", err, pattern)
+		}
+		part.literal = literal.String()
+		literal.Reset()
+		if part.segmentIndex > template.maxSegmentIndex {
+			template.maxSegmentIndex = part.segmentIndex
+		}
+		template.parts = append(template.parts, part)
+		i += end
+	}
+	if literal.Len() > 0 {
+		template.parts = append(template.parts, addressTemplatePart{literal: literal.String()})
+	}
+	return template, nil
+}
+
+func newAddressTemplatePart(placeholder string, formatters map[string]AddressTemplateFormatter) (part addressTemplatePart, err error) {
+	name, formatterName := placeholder, defaultTemplateFormatter
+	if colon := strings.IndexByte(placeholder, ':'); colon >= 0 {
+		name, formatterName = placeholder[:colon], placeholder[colon+1:]
+	}
+	if part.formatter = formatters[formatterName]; part.formatter == nil {
+		if part.formatter = templateFormatters[formatterName]; part.formatter == nil {
+			err = errorF("unknown formatter %s", formatterName)
+			return
+		}
+	}
+	if name == indexTemplatePlaceholder {
+		part.segmentIndex = -1
+		return
+	}
+	segNum := strings.TrimPrefix(name, segmentTemplatePlaceholder)
+	if len(segNum) < len(name) && len(segNum) > 0 && segNum[0] >= '1' && segNum[0] <= '9' {
+		if segIndex, convErr := strconv.ParseUint(segNum, 10, 16); convErr == nil {
+			part.segmentIndex = int(segIndex) - 1
+			return
+		}
+	}
+	err = errorF("unknown placeholder {%s}", placeholder)
+	return
+}
+
+// String returns the pattern of this template, or "<nil>" if the receiver is a nil pointer.
+func (template *AddressTemplate) String() string {
+	if template == nil {
+		return nilString()
+	}
+	return template.pattern
+}
+
+// Format returns the string generated by this template for the given address, using the given index for the "{index}" placeholder.
+// For a subnet, each segment placeholder is replaced by the lowest value of the segment.
+//
+// The "pad" and "padhex" formatters pad the index to the width of the largest index of the given address in an iteration,
+// which is the address count minus one, or to the width of the given index if larger.
+// For an individual address, the index is not padded.
+//
+// An error is returned if the template has a segment placeholder beyond the segment count of the address.
+func (template *AddressTemplate) Format(addr AddressType, index uint64) (string, error) {
+	address := addr.ToAddressBase()
+	if err := template.checkSegmentCount(address); err != nil {
+		return "", err
+	}
+	maxIndex := maxTemplateIndex(address)
+	if index > maxIndex {
+		maxIndex = index
+	}
+	return template.format(address, index, maxIndex), nil
+}
+
+// maxTemplateIndex returns the largest index of the addresses in the iteration of the given subnet
+func maxTemplateIndex(addr *Address) uint64 {
+	if count := addr.GetCount(); count.IsUint64() {
+		return count.Uint64() - 1
+	}
+	return math.MaxUint64
+}
+
+func (template *AddressTemplate) checkSegmentCount(addr *Address) error {
+	if template.maxSegmentIndex >= addr.GetSegmentCount() {
+		return errorF("address template %s requires %d segments, but address %v has %d", template.pattern, template.maxSegmentIndex+1, addr, addr.GetSegmentCount())
+	}
+	return nil
+}
+
+func (template *AddressTemplate) format(addr *Address, index, maxIndex uint64) string {
+	var builder strings.Builder
+	for _, part := range template.parts {
+		builder.WriteString(part.literal)
+		if part.formatter == nil {
+			continue
+		} else if part.segmentIndex < 0 {
+			builder.WriteString(part.formatter(index, maxIndex))
+		} else {
+			seg := addr.GetSegment(part.segmentIndex)
+			builder.WriteString(part.formatter(uint64(seg.GetSegmentValue()), uint64(seg.GetMaxValue())))
+		}
+	}
+	return builder.String()
+}
+
+// Iterator returns an iterator of the strings generated by this template for each of the individual addresses of the given subnet, in the order of the subnet's iterator.
+// The "{index}" placeholder is replaced by the index of each address in the iteration, starting from 0.
+//
+// An error is returned if the template has a segment placeholder beyond the segment count of the subnet.
+func (template *AddressTemplate) Iterator(subnet AddressType) (Iterator[string], error) {
+	addr := subnet.ToAddressBase()
+	if err := template.checkSegmentCount(addr); err != nil {
+		return nil, err
+	}
+	return &addressTemplateIterator{template: template, iterator: addr.Iterator(), maxIndex: maxTemplateIndex(addr)}, nil
+}
+
+type addressTemplateIterator struct {
+	template        *AddressTemplate
+	iterator        Iterator[*Address]
+	index, maxIndex uint64
+}
+
+func (iter *addressTemplateIterator) HasNext() bool {
+	return iter.iterator.HasNext()
+}
+
+func (iter *addressTemplateIterator) Next() (res string) {
+	if iter.HasNext() {
+		res = iter.template.format(iter.iterator.Next(), iter.index, iter.maxIndex)
+		iter.index++
+	}
+	return
+}
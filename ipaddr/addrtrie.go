@@ -17,9 +17,13 @@
 package ipaddr
 
 import (
+	"encoding/csv"
 	"fmt"
 	"github.com/seancfoley/bintree/tree"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+	"io"
+	"reflect"
+	"sort"
 	"unsafe"
 )
 
@@ -39,12 +43,12 @@ func (trie *trieBase[T, V]) getRoot() *tree.BinTrieNode[trieKey[T], V] {
 
 func (trie *trieBase[T, V]) add(addr T) bool {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.Add(trieKey[T]{addr})
+	return trie.trie.Add(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) addNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.AddNode(trieKey[T]{addr})
+	return trie.trie.AddNode(newTrieKey(addr))
 }
 
 // constructAddedNodesTree constructs an associative trie in which the root and each added node are mapped to a list of their respective direct added sub-nodes.
@@ -61,54 +65,89 @@ func (trie *trieBase[T, V]) addTrie(added *trieNode[T, V]) *tree.BinTrieNode[tri
 
 func (trie *trieBase[T, V]) contains(addr T) bool {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.Contains(trieKey[T]{addr})
+	return trie.trie.Contains(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) remove(addr T) bool {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.Remove(trieKey[T]{addr})
+	return trie.trie.Remove(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) removeElementsContainedBy(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.RemoveElementsContainedBy(trieKey[T]{addr})
+	return trie.trie.RemoveElementsContainedBy(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) elementsContainedBy(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.ElementsContainedBy(trieKey[T]{addr})
+	return trie.trie.ElementsContainedBy(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) elementsContaining(addr T) *containmentPath[T, V] {
 	addr = mustBeBlockOrAddress(addr)
-	return toContainmentPath[T, V](trie.trie.ElementsContaining(trieKey[T]{addr}))
+	return toContainmentPath[T, V](trie.trie.ElementsContaining(newTrieKey(addr)))
 }
 
 func (trie *trieBase[T, V]) longestPrefixMatch(addr T) (t T) {
 	addr = mustBeBlockOrAddress(addr)
-	key, _ := trie.trie.LongestPrefixMatch(trieKey[T]{addr})
+	key, _ := trie.trie.LongestPrefixMatch(newTrieKey(addr))
 	return key.address
 }
 
 // only added nodes are added to the linked list
 func (trie *trieBase[T, V]) longestPrefixMatchNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.LongestPrefixMatchNode(trieKey[T]{addr})
+	return trie.trie.LongestPrefixMatchNode(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) elementContains(addr T) bool {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.ElementContains(trieKey[T]{addr})
+	return trie.trie.ElementContains(newTrieKey(addr))
+}
+
+func (trie *trieBase[T, V]) containsAny(addrs []T) bool {
+	for _, addr := range addrs {
+		if trie.contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (trie *trieBase[T, V]) elementContainsAny(addrs []T) bool {
+	for _, addr := range addrs {
+		if trie.elementContains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func (trie *trieBase[T, V]) elementContainsAll(addrs []T) bool {
+	for _, addr := range addrs {
+		if !trie.elementContains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (trie *trieBase[T, V]) longestPrefixMatchAll(addrs []T) []T {
+	result := make([]T, len(addrs))
+	for i, addr := range addrs {
+		result[i] = trie.longestPrefixMatch(addr)
+	}
+	return result
 }
 
 func (trie *trieBase[T, V]) getNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.GetNode(trieKey[T]{addr})
+	return trie.trie.GetNode(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) getAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.GetAddedNode(trieKey[T]{addr})
+	return trie.trie.GetAddedNode(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) iterator() Iterator[T] {
@@ -118,6 +157,53 @@ func (trie *trieBase[T, V]) iterator() Iterator[T] {
 	return addressKeyIterator[T]{trie.trie.Iterator()}
 }
 
+// keys returns the added keys in sorted order
+func (trie *trieBase[T, V]) keys() []T {
+	keys := make([]T, 0, trie.trie.Size())
+	for iter := trie.iterator(); iter.HasNext(); {
+		keys = append(keys, iter.Next())
+	}
+	return keys
+}
+
+// diff visits the added nodes of this trie and the other in trie order, merging the two sequences of nodes.
+// Nodes with the same key are visited together, otherwise one of the two nodes is nil.
+func (trie *trieBase[T, V]) diff(other *trieBase[T, V], visitor func(node, otherNode *tree.BinTrieNode[trieKey[T], V]) (stop bool)) {
+	iter, otherIter := trie.nodeIterator(true), other.nodeIterator(true)
+	var node, otherNode *tree.BinTrieNode[trieKey[T], V]
+	for {
+		if node == nil && iter.HasNext() {
+			node = iter.Next()
+		}
+		if otherNode == nil && otherIter.HasNext() {
+			otherNode = otherIter.Next()
+		}
+		var stop bool
+		if node == nil {
+			if otherNode == nil {
+				return
+			}
+			stop = visitor(nil, otherNode)
+			otherNode = nil
+		} else if otherNode == nil {
+			stop = visitor(node, nil)
+			node = nil
+		} else if comp := node.GetKey().Compare(otherNode.GetKey()); comp < 0 {
+			stop = visitor(node, nil)
+			node = nil
+		} else if comp > 0 {
+			stop = visitor(nil, otherNode)
+			otherNode = nil
+		} else {
+			stop = visitor(node, otherNode)
+			node, otherNode = nil, nil
+		}
+		if stop {
+			return
+		}
+	}
+}
+
 func (trie *trieBase[T, V]) descendingIterator() Iterator[T] {
 	if trie == nil {
 		return nilAddressIterator[T]()
@@ -171,22 +257,22 @@ func (trie *trieBase[T, V]) containedFirstAllNodeIterator(forwardSubNodeOrder bo
 
 func (trie *trieBase[T, V]) lowerAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.LowerAddedNode(trieKey[T]{addr})
+	return trie.trie.LowerAddedNode(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) floorAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.FloorAddedNode(trieKey[T]{addr})
+	return trie.trie.FloorAddedNode(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) higherAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.HigherAddedNode(trieKey[T]{addr})
+	return trie.trie.HigherAddedNode(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) ceilingAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.CeilingAddedNode(trieKey[T]{addr})
+	return trie.trie.CeilingAddedNode(newTrieKey(addr))
 }
 
 func (trie *trieBase[T, V]) clone() *tree.BinTrie[trieKey[T], V] {
@@ -324,7 +410,11 @@ func (trie *Trie[T]) TreeString(withNonAddedKeys bool) string {
 }
 
 // String returns a visual representation of the trie with one node per line.
+// It returns "<nil>" if the receiver is a nil pointer.
 func (trie *Trie[T]) String() string {
+	if trie == nil {
+		return nilString()
+	}
 	return trie.toTrie().String()
 }
 
@@ -334,6 +424,12 @@ func (trie *Trie[T]) AddedNodesTreeString() string {
 	return trie.toTrie().AddedNodesTreeString()
 }
 
+// WritePrefixList writes the added addresses and prefix blocks of this trie, in sorted order, to the writer as a prefix list with the given name.
+// See the WritePrefixList function for details of the formats and options.
+func (trie *Trie[T]) WritePrefixList(writer io.Writer, name string, options PrefixListOptions) error {
+	return WritePrefixList(writer, name, trie.tobase().keys(), options)
+}
+
 // Add adds the address to this trie.
 // The address must match the same type and version of any existing addresses already in the trie.
 // Returns true if the address did not already exist in the trie.
@@ -460,6 +556,37 @@ func (trie *Trie[T]) ElementContains(addr T) bool {
 	return trie.elementContains(addr)
 }
 
+// ContainsAny returns whether any of the given addresses or prefix block subnets have been added to the trie, checking each with Contains.
+// It returns true as soon as one is found, without checking the remaining addresses.
+//
+// If any of the checked addresses is not a single address nor prefix block, this method will panic.
+func (trie *Trie[T]) ContainsAny(addrs []T) bool {
+	return trie.containsAny(addrs)
+}
+
+// ElementContainsAny returns whether any of the given addresses or prefix block subnets is contained by an element of the trie, checking each with ElementContains.
+// It returns true as soon as one is found to be contained, without checking the remaining addresses.
+//
+// If any of the checked addresses is not a single address nor prefix block, this method will panic.
+func (trie *Trie[T]) ElementContainsAny(addrs []T) bool {
+	return trie.elementContainsAny(addrs)
+}
+
+// ElementContainsAll returns whether every one of the given addresses or prefix block subnets is contained by an element of the trie, checking each with ElementContains.
+// It returns false as soon as one is found not to be contained, without checking the remaining addresses.
+// It returns true when the slice is empty.
+//
+// If any of the checked addresses is not a single address nor prefix block, this method will panic.
+func (trie *Trie[T]) ElementContainsAll(addrs []T) bool {
+	return trie.elementContainsAll(addrs)
+}
+
+// LongestPrefixMatchAll returns a slice with the result of LongestPrefixMatch for each of the given addresses, in the same order.
+// Each address with no matching address in the trie has the zero value of T, such as nil, at its index.
+func (trie *Trie[T]) LongestPrefixMatchAll(addrs []T) []T {
+	return trie.longestPrefixMatchAll(addrs)
+}
+
 // GetNode gets the node in the trie corresponding to the given address,
 // or returns nil if not such element exists.
 //
@@ -554,6 +681,17 @@ func (trie *Trie[T]) ContainingFirstAllNodeIterator(forwardSubNodeOrder bool) Ca
 	return cachingAddressTrieNodeIterator[T, emptyValue]{trie.tobase().containingFirstAllNodeIterator(forwardSubNodeOrder)}
 }
 
+// StructureIterator returns an iterator of the structural metadata of all the nodes of the trie, both added and non-added nodes,
+// in a pre-order traversal with lower sub-nodes first.
+// Since each node is visited before its sub-nodes, the structure of the trie can be reconstructed from the depth of each node.
+// Use it to render the structure of a trie, in preference to parsing the output of TreeString.
+func (trie *Trie[T]) StructureIterator() Iterator[TrieNodeInfo[T]] {
+	if root := trie.GetRoot(); root != nil {
+		return root.StructureIterator()
+	}
+	return &sliceIterator[TrieNodeInfo[T]]{}
+}
+
 // ContainedFirstIterator returns an iterator that does a post-order binary tree traversal of the added nodes.
 // All added sub-nodes will be visited before their parent nodes.
 // For an address trie this means contained addresses and subnets will be visited before their containing subnet blocks.
@@ -620,6 +758,53 @@ func (trie *Trie[T]) Equal(other *Trie[T]) bool {
 	return trie.toTrie().Equal(other.toTrie())
 }
 
+// Diff compares the added keys of this trie with those of the given trie,
+// returning the keys only in this trie and the keys only in the other trie, both in trie order.
+func (trie *Trie[T]) Diff(other *Trie[T]) (onlyInThis, onlyInOther []T) {
+	trie.DiffFunc(other, func(node, otherNode *TrieNode[T]) bool {
+		if otherNode == nil {
+			onlyInThis = append(onlyInThis, node.GetKey())
+		} else {
+			onlyInOther = append(onlyInOther, otherNode.GetKey())
+		}
+		return false
+	})
+	return
+}
+
+// DiffFunc compares the added keys of this trie with those of the given trie, visiting in trie order each added node with a key found in only one of the two tries,
+// without building intermediate collections.
+// For a key only in this trie, the visitor is called with the node from this trie and a nil node, otherwise it is called with a nil node and the node from the other trie.
+// The comparison terminates early when the visitor returns true.
+func (trie *Trie[T]) DiffFunc(other *Trie[T], visitor func(node, otherNode *TrieNode[T]) (stop bool)) {
+	trie.tobase().diff(other.tobase(), func(node, otherNode *tree.BinTrieNode[trieKey[T], emptyValue]) bool {
+		if node != nil && otherNode != nil {
+			return false
+		}
+		return visitor(toAddressTrieNode[T](node), toAddressTrieNode[T](otherNode))
+	})
+}
+
+// SortedNodes returns the added nodes of this trie in a slice, sorted using the given comparator,
+// which returns a negative integer, zero, or a positive integer if the first node is to be ordered before, equal to, or after the second.
+// The comparator is typically used to order nodes by some priority associated with the node values.
+//
+// Nodes which the comparator orders as equal remain in trie order, the same order as provided by NodeIterator(true) and by the node Compare method.
+// Since no two added nodes have the same key, the result is deterministic for any given trie and comparator.
+// If the comparator is nil, the slice is in trie order.
+func (trie *AssociativeTrie[T, V]) SortedNodes(comparator func(one, two *AssociativeTrieNode[T, V]) int) []*AssociativeTrieNode[T, V] {
+	nodes := make([]*AssociativeTrieNode[T, V], 0, trie.Size())
+	for iter := trie.NodeIterator(true); iter.HasNext(); {
+		nodes = append(nodes, iter.Next())
+	}
+	if comparator != nil {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return comparator(nodes[i], nodes[j]) < 0
+		})
+	}
+	return nodes
+}
+
 // For some reason Format must be here and not in addressTrieNode for nil node.
 // It panics in fmt code either way, but if in here then it is handled by a recover() call in fmt properly.
 // Seems to be a problem only in the debugger.
@@ -666,6 +851,9 @@ func toAddressTrie[T TrieKeyConstraint[T]](trie *tree.BinTrie[trieKey[T], emptyV
 //
 // All the characteristics of Trie are common to AssociativeTrie.
 //
+// To order the added nodes by other criteria, such as a priority associated with each value, use SortedNodes,
+// which retains trie order for nodes the supplied comparator considers equal.
+//
 // The zero value is a binary trie ready for use.
 type AssociativeTrie[T TrieKeyConstraint[T], V any] struct {
 	trieBase[T, V]
@@ -709,7 +897,11 @@ func (trie *AssociativeTrie[T, V]) TreeString(withNonAddedKeys bool) string {
 }
 
 // String returns a visual representation of the tree with one node per line.
+// It returns "<nil>" if the receiver is a nil pointer.
 func (trie *AssociativeTrie[T, V]) String() string {
+	if trie == nil {
+		return nilString()
+	}
 	return trie.toTrie().String()
 }
 
@@ -719,6 +911,75 @@ func (trie *AssociativeTrie[T, V]) AddedNodesTreeString() string {
 	return trie.toTrie().AddedNodesTreeString()
 }
 
+// WritePrefixList writes the added addresses and prefix blocks of this trie, in sorted order, to the writer as a prefix list with the given name.
+// See the WritePrefixList function for details of the formats and options.
+func (trie *AssociativeTrie[T, V]) WritePrefixList(writer io.Writer, name string, options PrefixListOptions) error {
+	return WritePrefixList(writer, name, trie.tobase().keys(), options)
+}
+
+// WriteCSV writes the added keys of this trie and their associated values, in sorted order, to the writer as CSV records.
+// The records are preceded by a header record identifying the format version, TrieCSVFormatVersion.
+// Each following record has two fields, the key string and the value encoded by the given encoder.
+// Prefix block keys are written with their prefix lengths, so the trie can be reloaded with ReadCSV.
+func (trie *AssociativeTrie[T, V]) WriteCSV(writer io.Writer, valueEncoder func(V) string) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := writeTrieCSVHeader(csvWriter); err != nil {
+		return err
+	}
+	for iter := trie.NodeIterator(true); iter.HasNext(); {
+		node := iter.Next()
+		if err := csvWriter.Write([]string{node.GetKey().String(), valueEncoder(node.GetValue())}); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// ReadCSV reads CSV records of the form key,value from the reader, as written by WriteCSV, and puts each key and value into this trie.
+// The values are decoded by the given decoder.
+// The records must be preceded by a header record with a supported format version, see TrieCSVFormatVersion.
+//
+// Each key must parse as an address of the key type of this trie, and must be a single address or a prefix block.
+// Keys must also match the type and version of any existing keys in the trie.
+// When the key type is *Address, keys are parsed as the same address type as the existing keys,
+// or, when the trie is empty, as IP addresses, or as MAC addresses if they are not valid IP addresses.
+//
+// Reading stops at the first invalid record, in which case the returned error is a [*csv.ParseError] indicating the line of the record.
+// Records read before the invalid record remain in the trie.
+func (trie *AssociativeTrie[T, V]) ReadCSV(reader io.Reader, valueDecoder func(string) (V, error)) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = 2
+	if _, err := readTrieCSVHeader(csvReader); err != nil {
+		if _, isParseErr := err.(*csv.ParseError); isParseErr {
+			return err
+		}
+		return &csv.ParseError{StartLine: 1, Line: 1, Column: 1, Err: err}
+	}
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		var existing *Address
+		if root := trie.GetRoot(); root != nil {
+			existing = root.GetKey().ToAddressBase()
+		}
+		key, err := parseTrieKey[T](record[0], existing)
+		if err == nil {
+			var value V
+			if value, err = valueDecoder(record[1]); err == nil {
+				trie.Put(key, value)
+				continue
+			}
+		}
+		line, column := csvReader.FieldPos(0)
+		return &csv.ParseError{StartLine: line, Line: line, Column: column, Err: err}
+	}
+}
+
 // Add adds the address to this trie.
 // Returns true if the address did not already exist in the trie.
 func (trie *AssociativeTrie[T, V]) Add(addr T) bool {
@@ -843,6 +1104,37 @@ func (trie *AssociativeTrie[T, V]) ElementContains(addr T) bool {
 	return trie.elementContains(addr)
 }
 
+// ContainsAny returns whether any of the given addresses or prefix block subnets have been added to the trie, checking each with Contains.
+// It returns true as soon as one is found, without checking the remaining addresses.
+//
+// If any of the checked addresses is not a single address nor prefix block, this method will panic.
+func (trie *AssociativeTrie[T, V]) ContainsAny(addrs []T) bool {
+	return trie.containsAny(addrs)
+}
+
+// ElementContainsAny returns whether any of the given addresses or prefix block subnets is contained by an element of the trie, checking each with ElementContains.
+// It returns true as soon as one is found to be contained, without checking the remaining addresses.
+//
+// If any of the checked addresses is not a single address nor prefix block, this method will panic.
+func (trie *AssociativeTrie[T, V]) ElementContainsAny(addrs []T) bool {
+	return trie.elementContainsAny(addrs)
+}
+
+// ElementContainsAll returns whether every one of the given addresses or prefix block subnets is contained by an element of the trie, checking each with ElementContains.
+// It returns false as soon as one is found not to be contained, without checking the remaining addresses.
+// It returns true when the slice is empty.
+//
+// If any of the checked addresses is not a single address nor prefix block, this method will panic.
+func (trie *AssociativeTrie[T, V]) ElementContainsAll(addrs []T) bool {
+	return trie.elementContainsAll(addrs)
+}
+
+// LongestPrefixMatchAll returns a slice with the result of LongestPrefixMatch for each of the given addresses, in the same order.
+// Each address with no matching address in the trie has the zero value of T, such as nil, at its index.
+func (trie *AssociativeTrie[T, V]) LongestPrefixMatchAll(addrs []T) []T {
+	return trie.longestPrefixMatchAll(addrs)
+}
+
 // GetNode gets the node in the trie corresponding to the given address,
 // or returns nil if not such element exists.
 //
@@ -937,6 +1229,17 @@ func (trie *AssociativeTrie[T, V]) ContainingFirstAllNodeIterator(forwardSubNode
 	return cachingAssociativeAddressTrieNodeIteratorX[T, V]{trie.tobase().containingFirstAllNodeIterator(forwardSubNodeOrder)}
 }
 
+// StructureIterator returns an iterator of the structural metadata of all the nodes of the trie, both added and non-added nodes,
+// in a pre-order traversal with lower sub-nodes first.
+// Since each node is visited before its sub-nodes, the structure of the trie can be reconstructed from the depth of each node.
+// Use it to render the structure of a trie, in preference to parsing the output of TreeString.
+func (trie *AssociativeTrie[T, V]) StructureIterator() Iterator[TrieNodeInfo[T]] {
+	if root := trie.GetRoot(); root != nil {
+		return root.StructureIterator()
+	}
+	return &sliceIterator[TrieNodeInfo[T]]{}
+}
+
 // ContainedFirstIterator returns an iterator that does a post-order binary trie traversal of the added nodes.
 // All added sub-nodes will be visited before their parent nodes.
 // For an address trie this means contained addresses and subnets will be visited before their containing subnet blocks.
@@ -1013,6 +1316,108 @@ func (trie *AssociativeTrie[T, V]) DeepEqual(other *AssociativeTrie[T, V]) bool
 	return trie.toTrie().DeepEqual(other.toTrie())
 }
 
+// Diff compares the added keys and their values in this trie with those of the given trie,
+// returning the keys only in this trie, the keys only in the other trie, and the keys in both whose values differ, all in trie order.
+//
+// Values are compared with the given function, or with reflect.DeepEqual if the function is nil.
+func (trie *AssociativeTrie[T, V]) Diff(other *AssociativeTrie[T, V], valuesEqual func(value, otherValue V) bool) (onlyInThis, onlyInOther, changed []T) {
+	trie.DiffFunc(other, valuesEqual, func(node, otherNode *AssociativeTrieNode[T, V]) bool {
+		if otherNode == nil {
+			onlyInThis = append(onlyInThis, node.GetKey())
+		} else if node == nil {
+			onlyInOther = append(onlyInOther, otherNode.GetKey())
+		} else {
+			changed = append(changed, node.GetKey())
+		}
+		return false
+	})
+	return
+}
+
+// DiffFunc compares the added keys and their values in this trie with those of the given trie,
+// visiting in trie order each added node with a key found in only one of the two tries, or with values that differ,
+// without building intermediate collections.
+//
+// For a key only in this trie, the visitor is called with the node from this trie and a nil node,
+// for a key only in the other trie, it is called with a nil node and the node from the other trie,
+// and for a key in both tries with differing values, it is called with the nodes from both tries.
+// The comparison terminates early when the visitor returns true.
+//
+// Values are compared with the given function, or with reflect.DeepEqual if the function is nil.
+func (trie *AssociativeTrie[T, V]) DiffFunc(other *AssociativeTrie[T, V], valuesEqual func(value, otherValue V) bool, visitor func(node, otherNode *AssociativeTrieNode[T, V]) (stop bool)) {
+	valuesEqual = toValuesEqual(valuesEqual)
+	trie.tobase().diff(other.tobase(), func(node, otherNode *tree.BinTrieNode[trieKey[T], V]) bool {
+		if node != nil && otherNode != nil && valuesEqual(node.GetValue(), otherNode.GetValue()) {
+			return false
+		}
+		return visitor(toAssociativeTrieNode[T, V](node), toAssociativeTrieNode[T, V](otherNode))
+	})
+}
+
+// ValuesIterator returns an iterator that iterates through the distinct values of the added nodes in the trie,
+// ordered by the first added node in sorted element order mapped to each value.
+// The iterator is not affected by later changes to the trie.
+//
+// Values are compared with the given function, or with reflect.DeepEqual if the function is nil.
+// Since each value is compared with the distinct values preceding it, InvertAssociativeTrie is more efficient
+// for tries having many distinct values of a comparable type.
+func (trie *AssociativeTrie[T, V]) ValuesIterator(valuesEqual func(value, otherValue V) bool) Iterator[V] {
+	valuesEqual = toValuesEqual(valuesEqual)
+	var values []V
+	for iter := trie.NodeIterator(true); iter.HasNext(); {
+		value := iter.Next().GetValue()
+		isDistinct := true
+		for _, distinct := range values {
+			if valuesEqual(distinct, value) {
+				isDistinct = false
+				break
+			}
+		}
+		if isDistinct {
+			values = append(values, value)
+		}
+	}
+	return &sliceIterator[V]{values}
+}
+
+// KeysForValue returns an iterator that iterates through the added addresses and prefix blocks in the trie mapped to the given value.
+// The iteration is in sorted element order.  The iterator is not affected by later changes to the trie.
+//
+// Values are compared with the given function, or with reflect.DeepEqual if the function is nil.
+// Each call visits every added node, so to retrieve the keys for many values, use InvertAssociativeTrie if the values are comparable.
+func (trie *AssociativeTrie[T, V]) KeysForValue(value V, valuesEqual func(value, otherValue V) bool) Iterator[T] {
+	valuesEqual = toValuesEqual(valuesEqual)
+	var keys []T
+	for iter := trie.NodeIterator(true); iter.HasNext(); {
+		if node := iter.Next(); valuesEqual(value, node.GetValue()) {
+			keys = append(keys, node.GetKey())
+		}
+	}
+	return &sliceIterator[T]{keys}
+}
+
+// InvertAssociativeTrie returns the inverse mapping of the given trie, mapping each distinct value to the added addresses and prefix blocks mapped to it,
+// in sorted element order, such as mapping each autonomous system number to its prefixes.
+// The map provides fast retrieval of the keys for each value, and is not affected by later changes to the trie.
+func InvertAssociativeTrie[T TrieKeyConstraint[T], V comparable](trie *AssociativeTrie[T, V]) map[V][]T {
+	inverse := make(map[V][]T)
+	for iter := trie.NodeIterator(true); iter.HasNext(); {
+		node := iter.Next()
+		value := node.GetValue()
+		inverse[value] = append(inverse[value], node.GetKey())
+	}
+	return inverse
+}
+
+func toValuesEqual[V any](valuesEqual func(value, otherValue V) bool) func(value, otherValue V) bool {
+	if valuesEqual == nil {
+		return func(value, otherValue V) bool {
+			return reflect.DeepEqual(value, otherValue)
+		}
+	}
+	return valuesEqual
+}
+
 // Put associates the specified value with the specified key in this map.
 //
 // If the argument is not a single address nor prefix block, this method will panic.
@@ -1024,7 +1429,7 @@ func (trie *AssociativeTrie[T, V]) DeepEqual(other *AssociativeTrie[T, V]) bool
 // The boolean return value allows you to distinguish whether the address was previously mapped to nil or not mapped at all.
 func (trie *AssociativeTrie[T, V]) Put(addr T, value V) (V, bool) {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.Put(trieKey[T]{addr}, value)
+	return trie.trie.Put(newTrieKey(addr), value)
 }
 
 // PutTrie adds nodes for the address keys and values in the trie with the root node as the passed in node.  To add only the keys, use AddTrie.
@@ -1053,7 +1458,7 @@ func (trie *AssociativeTrie[T, V]) PutTrie(added *AssociativeTrieNode[T, V]) *As
 // If you wish to know whether the node was already there when adding, use PutNew, or before adding you can use GetAddedNode.
 func (trie *AssociativeTrie[T, V]) PutNode(addr T, value V) *AssociativeTrieNode[T, V] {
 	addr = mustBeBlockOrAddress(addr)
-	return toAssociativeTrieNode[T, V](trie.trie.PutNode(trieKey[T]{addr}, value))
+	return toAssociativeTrieNode[T, V](trie.trie.PutNode(newTrieKey(addr), value))
 }
 
 // Remap remaps node values in the trie.
@@ -1081,7 +1486,7 @@ func (trie *AssociativeTrie[T, V]) PutNode(addr T, value V) *AssociativeTrieNode
 // The [Partition] type can be used to convert the argument to single addresses and prefix blocks before calling this method.
 func (trie *AssociativeTrie[T, V]) Remap(addr T, remapper func(existingValue V, found bool) (mapped V, mapIt bool)) *AssociativeTrieNode[T, V] {
 	addr = mustBeBlockOrAddress(addr)
-	return toAssociativeTrieNode[T, V](trie.trieBase.trie.Remap(trieKey[T]{addr}, remapper))
+	return toAssociativeTrieNode[T, V](trie.trieBase.trie.Remap(newTrieKey(addr), remapper))
 }
 
 // RemapIfAbsent remaps node values in the trie, but only for nodes that do not exist or are not "added".
@@ -1100,7 +1505,30 @@ func (trie *AssociativeTrie[T, V]) Remap(addr T, remapper func(existingValue V,
 // The [Partition] type can be used to convert the argument to single addresses and prefix blocks before calling this method.
 func (trie *AssociativeTrie[T, V]) RemapIfAbsent(addr T, supplier func() V) *AssociativeTrieNode[T, V] {
 	addr = mustBeBlockOrAddress(addr)
-	return toAssociativeTrieNode[T, V](trie.trieBase.trie.RemapIfAbsent(trieKey[T]{addr}, supplier))
+	return toAssociativeTrieNode[T, V](trie.trieBase.trie.RemapIfAbsent(newTrieKey(addr), supplier))
+}
+
+// UpdateValue replaces the value mapped to the given key with the value returned by the updater, which is called with the existing value.
+// It returns the new value and true if the key is mapped, and otherwise it returns the zero value and false, without calling the updater.
+// Keys are not added, so the structure of the trie is unchanged.
+//
+// The update is atomic with respect to the other value updates of UpdateValue and CompareAndSetValue of AssociativeTrieNode,
+// and to the value reads of LoadValue of AssociativeTrieNode.
+// Goroutines can therefore update the values of a trie concurrently while holding a shared read lock,
+// holding the write lock only for methods that add or remove keys.  The updater must not modify the trie.
+//
+// The updater is called without holding any lock, so it can read and update the values of the trie,
+// but it is called again with the latest value when the value is updated concurrently, so it should have no other side effects.
+//
+// If the argument is not a single address nor prefix block, this method will panic.
+// The [Partition] type can be used to convert the argument to single addresses and prefix blocks before calling this method.
+func (trie *AssociativeTrie[T, V]) UpdateValue(addr T, updater func(existingValue V) V) (V, bool) {
+	node := trie.GetAddedNode(mustBeBlockOrAddress(addr))
+	if node == nil {
+		var v V
+		return v, false
+	}
+	return node.updateValue(updater), true
 }
 
 // Get gets the value for the specified key in this mapped trie or sub-trie.
@@ -1112,7 +1540,7 @@ func (trie *AssociativeTrie[T, V]) RemapIfAbsent(addr T, supplier func() V) *Ass
 // Returns nil if the contains no mapping for that key or if the mapped value is nil.
 func (trie *AssociativeTrie[T, V]) Get(addr T) (V, bool) {
 	addr = mustBeBlockOrAddress(addr)
-	return trie.trie.Get(trieKey[T]{addr})
+	return trie.trie.Get(newTrieKey(addr))
 }
 
 // For some reason Format must be here and not in addressTrieNode for nil node.
@@ -1156,6 +1584,62 @@ func checkBlockOrAddress[T TrieKeyConstraint[T]](addr T) (res T, err addrerr.Inc
 	return addr.toSinglePrefixBlockOrAddress()
 }
 
+// parseTrieKey parses the string as an address of the key type, which must be either a single address or a prefix block.
+// If existing is not nil, the parsed key must have the same type and bit count as existing.
+func parseTrieKey[T TrieKeyConstraint[T]](str string, existing *Address) (key T, err error) {
+	var addrErr addrerr.AddressError
+	switch any(key).(type) {
+	case *IPv4Address:
+		var addr *IPAddress
+		if addr, addrErr = NewIPAddressString(str).ToVersionedAddress(IPv4); addr != nil {
+			key = any(addr.ToIPv4()).(T)
+		}
+	case *IPv6Address:
+		var addr *IPAddress
+		if addr, addrErr = NewIPAddressString(str).ToVersionedAddress(IPv6); addr != nil {
+			key = any(addr.ToIPv6()).(T)
+		}
+	case *IPAddress:
+		var addr *IPAddress
+		if addr, addrErr = NewIPAddressString(str).ToAddress(); addr != nil {
+			key = any(addr).(T)
+		}
+	case *MACAddress:
+		var addr *MACAddress
+		if addr, addrErr = NewMACAddressString(str).ToAddress(); addr != nil {
+			key = any(addr).(T)
+		}
+	default:
+		// some strings are valid as both IPv6 and MAC, so the existing key decides the address type when there is one
+		var addr *IPAddress
+		if existing == nil || !existing.IsMAC() {
+			addr, addrErr = NewIPAddressString(str).ToAddress()
+		}
+		if addr != nil {
+			key = any(addr.ToAddressBase()).(T)
+		} else if existing == nil || existing.IsMAC() {
+			macAddr, macErr := NewMACAddressString(str).ToAddress()
+			if macAddr != nil {
+				key, addrErr = any(macAddr.ToAddressBase()).(T), nil
+			} else if addrErr == nil {
+				addrErr = macErr
+			}
+		}
+	}
+	if addrErr != nil {
+		return key, addrErr
+	} else if key.ToAddressBase() == nil {
+		return key, &addressError{str: str, key: "ipaddress.error.empty"}
+	} else if existing != nil && key.GetBitCount() != existing.GetBitCount() {
+		return key, &addressError{str: str, key: "ipaddress.error.mismatched.bit.size"}
+	}
+	key, blockErr := checkBlockOrAddress(key)
+	if blockErr != nil {
+		return key, blockErr
+	}
+	return key, nil
+}
+
 // NewTrie constructs an address trie for the given type, without a root.  For the generic type T,
 // you can choose *Address, *IPAddress, *IPv4Address, *IPv6Address, or *MACAddress.
 func NewTrie[T TrieKeyConstraint[T]]() *Trie[T] {
@@ -1182,25 +1666,25 @@ type (
 // NewIPv4AddressTrie constructs an IPv4 address trie with the root as the 0.0.0.0/0 prefix block
 // This is here for backwards compatibility.  Using NewTrie is recommended instead.
 func NewIPv4AddressTrie() *Trie[*IPv4Address] { // for backwards compatibility
-	return &Trie[*IPv4Address]{trieBase[*IPv4Address, emptyValue]{tree.NewBinTrie[trieKey[*IPv4Address], emptyValue](trieKey[*IPv4Address]{ipv4All})}}
+	return &Trie[*IPv4Address]{trieBase[*IPv4Address, emptyValue]{tree.NewBinTrie[trieKey[*IPv4Address], emptyValue](newTrieKey(ipv4All))}}
 }
 
 // NewIPv4AddressAssociativeTrie constructs an IPv4 associative address trie with the root as the 0.0.0.0/0 prefix block
 // This is here for backwards compatibility.  Using NewAssociativeTrie is recommended instead.
 func NewIPv4AddressAssociativeTrie() *AssociativeTrie[*IPv4Address, any] { // for backwards compatibility
-	return &AssociativeTrie[*IPv4Address, any]{trieBase[*IPv4Address, any]{tree.NewBinTrie[trieKey[*IPv4Address], any](trieKey[*IPv4Address]{ipv4All})}}
+	return &AssociativeTrie[*IPv4Address, any]{trieBase[*IPv4Address, any]{tree.NewBinTrie[trieKey[*IPv4Address], any](newTrieKey(ipv4All))}}
 }
 
 // NewIPv6AddressTrie constructs an IPv6 address trie with the root as the ::/0 prefix block
 // This is here for backwards compatibility.  Using NewTrie is recommended instead.
 func NewIPv6AddressTrie() *Trie[*IPv6Address] { // for backwards compatibility
-	return &Trie[*IPv6Address]{trieBase[*IPv6Address, emptyValue]{tree.NewBinTrie[trieKey[*IPv6Address], emptyValue](trieKey[*IPv6Address]{ipv6All})}}
+	return &Trie[*IPv6Address]{trieBase[*IPv6Address, emptyValue]{tree.NewBinTrie[trieKey[*IPv6Address], emptyValue](newTrieKey(ipv6All))}}
 }
 
 // NewIPv6AddressAssociativeTrie constructs an IPv6 associative address trie with the root as the ::/0 prefix block
 // This is here for backwards compatibility.  Using NewAssociativeTrie is recommended instead.
 func NewIPv6AddressAssociativeTrie() *AssociativeTrie[*IPv6Address, any] { // for backwards compatibility
-	return &AssociativeTrie[*IPv6Address, any]{trieBase[*IPv6Address, any]{tree.NewBinTrie[trieKey[*IPv6Address], any](trieKey[*IPv6Address]{ipv6All})}}
+	return &AssociativeTrie[*IPv6Address, any]{trieBase[*IPv6Address, any]{tree.NewBinTrie[trieKey[*IPv6Address], any](newTrieKey(ipv6All))}}
 }
 
 // NewMACAddressTrie constructs a MAC address trie with the root as the zero-prefix block
@@ -1215,7 +1699,7 @@ func NewMACAddressTrie(extended bool) *Trie[*MACAddress] {
 	} else {
 		rootAddr = macAll
 	}
-	return &Trie[*MACAddress]{trieBase[*MACAddress, emptyValue]{tree.NewBinTrie[trieKey[*MACAddress], emptyValue](trieKey[*MACAddress]{rootAddr})}}
+	return &Trie[*MACAddress]{trieBase[*MACAddress, emptyValue]{tree.NewBinTrie[trieKey[*MACAddress], emptyValue](newTrieKey(rootAddr))}}
 }
 
 // NewMACAddressAssociativeTrie constructs a MAC associative address trie with the root as the zero-prefix prefix block
@@ -1227,7 +1711,7 @@ func NewMACAddressAssociativeTrie(extended bool) *AssociativeTrie[*MACAddress, a
 	} else {
 		rootAddr = macAll
 	}
-	return &AssociativeTrie[*MACAddress, any]{trieBase[*MACAddress, any]{tree.NewBinTrie[trieKey[*MACAddress], any](trieKey[*MACAddress]{rootAddr})}}
+	return &AssociativeTrie[*MACAddress, any]{trieBase[*MACAddress, any]{tree.NewBinTrie[trieKey[*MACAddress], any](newTrieKey(rootAddr))}}
 }
 
 // AddedTree is an alternative non-binary tree data structure originating from a binary trie
@@ -19,6 +19,8 @@ import (
 	"fmt"
 	"github.com/seancfoley/bintree/tree"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+	"math/bits"
+	"sync"
 	"unsafe"
 )
 
@@ -45,6 +47,38 @@ type TrieKeyConstraint[T any] interface {
 
 type trieKey[T TrieKeyConstraint[T]] struct {
 	address T
+
+	// For IPv4, IPv6 and MAC addresses, which are at most 128 bits,
+	// the lower value of the address is stored alongside it as a pair of uint64, shifted so the first address bit is the most significant bit of high.
+	// This allows MatchBits to match all the bits of two keys at once, rather than segment by segment.
+	// The bit count is zero when the value is not stored.
+	high, low uint64
+	bitCount  BitCount
+}
+
+func newTrieKey[T TrieKeyConstraint[T]](addr T) trieKey[T] {
+	key := trieKey[T]{address: addr}
+	if base := addr.ToAddressBase(); base != nil && base.section != nil {
+		switch base.getAddrType() {
+		case ipv4Type, ipv6Type, macType:
+			section := base.section
+			bitsPerSegment := uint(section.GetBitsPerSegment())
+			var high, low uint64
+			divs := section.getDivArray()
+			for _, div := range divs {
+				high = high<<bitsPerSegment | low>>(64-bitsPerSegment)
+				low = low<<bitsPerSegment | uint64(div.getDivisionValue())
+			}
+			bitCount := BitCount(len(divs)) * BitCount(bitsPerSegment)
+			if shift := uint(128 - bitCount); shift >= 64 {
+				high, low = low<<(shift-64), 0
+			} else if shift > 0 {
+				high, low = high<<shift|low>>(64-shift), low<<shift
+			}
+			key.high, key.low, key.bitCount = high, low, bitCount
+		}
+	}
+	return key
 }
 
 func (a trieKey[T]) GetBitCount() tree.BitCount {
@@ -64,7 +98,8 @@ func (a trieKey[T]) GetTrailingBitCount(ones bool) tree.BitCount {
 }
 
 func (a trieKey[T]) GetPrefixLen() tree.PrefixLen {
-	return tree.PrefixLen(a.address.GetPrefixLen())
+	// no need to copy the prefix length, the trie does not modify it
+	return tree.PrefixLen(a.address.ToAddressBase().getPrefixLen())
 }
 
 // ToPrefixBlockLen returns the address key associated with the prefix length provided,
@@ -72,7 +107,7 @@ func (a trieKey[T]) GetPrefixLen() tree.PrefixLen {
 //
 // The returned address key will represent all addresses with the same prefix as this one, the prefix "block".
 func (a trieKey[T]) ToPrefixBlockLen(bitCount BitCount) trieKey[T] {
-	return trieKey[T]{a.address.ToPrefixBlockLen(bitCount)}
+	return newTrieKey(a.address.ToPrefixBlockLen(bitCount))
 }
 
 // Compare compares to provide the same ordering used by the trie,
@@ -105,6 +140,9 @@ func (a trieKey[T]) Compare(other trieKey[T]) int {
 // MatchBits returns false if we need to keep going and try to match sub-nodes.
 // MatchBits returns true if the bits do not match, or the bits match to the very end.
 func (a trieKey[T]) MatchBits(key trieKey[T], bitIndex int, handleMatch tree.KeyCompareResult) bool {
+	if a.bitCount != 0 && a.bitCount == key.bitCount {
+		return a.matchValueBits(key, bitIndex, handleMatch)
+	}
 	existingAddr := key.address.ToAddressBase()
 	bitsPerSegment := existingAddr.GetBitsPerSegment()
 	bytesPerSegment := existingAddr.GetBytesPerSegment()
@@ -182,14 +220,58 @@ func (a trieKey[T]) MatchBits(key trieKey[T], bitIndex int, handleMatch tree.Key
 	}
 }
 
+// matchValueBits is the equivalent of MatchBits for keys with stored values,
+// matching the values of the two keys all at once rather than segment by segment.
+func (a trieKey[T]) matchValueBits(key trieKey[T], bitIndex int, handleMatch tree.KeyCompareResult) bool {
+	bitCount := key.bitCount
+	if bitIndex >= bitCount {
+		// all the bits match
+		handleMatch.BitsMatch()
+		return true
+	}
+	var matchingBits BitCount
+	if xor := key.high ^ a.high; xor != 0 {
+		matchingBits = BitCount(bits.LeadingZeros64(xor))
+	} else {
+		matchingBits = 64 + BitCount(bits.LeadingZeros64(key.low^a.low))
+	}
+	existingPref, newPref := key.address.ToAddressBase().getPrefixLen(), a.address.ToAddressBase().getPrefixLen()
+	if existingPref != nil {
+		existingPrefLen := existingPref.bitCount()
+		if newPref != nil && newPref.bitCount() <= existingPrefLen {
+			if matchingBits >= newPref.bitCount() {
+				handleMatch.BitsMatch()
+			} else {
+				handleMatch.BitsDoNotMatch(matchingBits)
+			}
+		} else if matchingBits >= existingPrefLen {
+			// match - the current subnet is a match so far, and we must go further to check smaller subnets
+			return false
+		} else {
+			handleMatch.BitsDoNotMatch(matchingBits)
+		}
+	} else if newPref != nil {
+		if matchingBits >= newPref.bitCount() {
+			handleMatch.BitsMatch()
+		} else {
+			handleMatch.BitsDoNotMatch(matchingBits)
+		}
+	} else if matchingBits >= bitCount {
+		handleMatch.BitsMatch()
+	} else {
+		handleMatch.BitsDoNotMatch(matchingBits)
+	}
+	return true
+}
+
 // ToMaxLower changes this key to a new key with a 0 at the first bit beyond the prefix, followed by all ones, and with no prefix length.
 func (a trieKey[T]) ToMaxLower() trieKey[T] {
-	return trieKey[T]{a.address.toMaxLower()}
+	return newTrieKey(a.address.toMaxLower())
 }
 
 // ToMinUpper changes this key to a new key with a 1 at the first bit beyond the prefix, followed by all zeros, and with no prefix length.
 func (a trieKey[T]) ToMinUpper() trieKey[T] {
-	return trieKey[T]{a.address.toMinUpper()}
+	return newTrieKey(a.address.toMinUpper())
 }
 
 var (
@@ -215,27 +297,27 @@ func (node *trieNode[T, V]) getKey() (t T) {
 
 func (node *trieNode[T, V]) get(addr T) (V, bool) {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().Get(trieKey[T]{addr})
+	return node.toBinTrieNode().Get(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) lowerAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().LowerAddedNode(trieKey[T]{addr})
+	return node.toBinTrieNode().LowerAddedNode(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) floorAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().FloorAddedNode(trieKey[T]{addr})
+	return node.toBinTrieNode().FloorAddedNode(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) higherAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().HigherAddedNode(trieKey[T]{addr})
+	return node.toBinTrieNode().HigherAddedNode(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) ceilingAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().CeilingAddedNode(trieKey[T]{addr})
+	return node.toBinTrieNode().CeilingAddedNode(newTrieKey(addr))
 }
 
 // iterator returns an iterator that iterates through the elements of the sub-trie with this node as the root.
@@ -300,59 +382,135 @@ func (node *trieNode[T, V]) containedFirstAllNodeIterator(forwardSubNodeOrder bo
 
 func (node *trieNode[T, V]) contains(addr T) bool {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().Contains(trieKey[T]{addr})
+	return node.toBinTrieNode().Contains(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) removeNode(addr T) bool {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().RemoveNode(trieKey[T]{addr})
+	return node.toBinTrieNode().RemoveNode(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) removeElementsContainedBy(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().RemoveElementsContainedBy(trieKey[T]{addr})
+	return node.toBinTrieNode().RemoveElementsContainedBy(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) elementsContainedBy(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().ElementsContainedBy(trieKey[T]{addr})
+	return node.toBinTrieNode().ElementsContainedBy(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) elementsContaining(addr T) *containmentPath[T, V] {
 	addr = mustBeBlockOrAddress(addr)
-	return toContainmentPath[T, V](node.toBinTrieNode().ElementsContaining(trieKey[T]{addr}))
+	return toContainmentPath[T, V](node.toBinTrieNode().ElementsContaining(newTrieKey(addr)))
 }
 
 func (node *trieNode[T, V]) longestPrefixMatch(addr T) (t T) {
 	addr = mustBeBlockOrAddress(addr)
-	key, _ := node.toBinTrieNode().LongestPrefixMatch(trieKey[T]{addr})
+	key, _ := node.toBinTrieNode().LongestPrefixMatch(newTrieKey(addr))
 	return key.address
 }
 
 func (node *trieNode[T, V]) longestPrefixMatchNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().LongestPrefixMatchNode(trieKey[T]{addr})
+	return node.toBinTrieNode().LongestPrefixMatchNode(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) elementContains(addr T) bool {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().ElementContains(trieKey[T]{addr})
+	return node.toBinTrieNode().ElementContains(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) getNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().GetNode(trieKey[T]{addr})
+	return node.toBinTrieNode().GetNode(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) getAddedNode(addr T) *tree.BinTrieNode[trieKey[T], V] {
 	addr = mustBeBlockOrAddress(addr)
-	return node.toBinTrieNode().GetAddedNode(trieKey[T]{addr})
+	return node.toBinTrieNode().GetAddedNode(newTrieKey(addr))
 }
 
 func (node *trieNode[T, V]) toBinTrieNode() *tree.BinTrieNode[trieKey[T], V] {
 	return (*tree.BinTrieNode[trieKey[T], V])(unsafe.Pointer(node))
 }
 
+func (node *trieNode[T, V]) getSplitBit() BitCount {
+	key := node.getKey()
+	if prefLen := key.ToAddressBase().GetPrefixLen(); prefLen != nil {
+		return prefLen.bitCount()
+	}
+	return key.GetBitCount()
+}
+
+func (node *trieNode[T, V]) getDepth() (depth int) {
+	for parent := node.toBinTrieNode().GetParent(); parent != nil; parent = parent.GetParent() {
+		depth++
+	}
+	return
+}
+
+func (node *trieNode[T, V]) getInfo(depth int) TrieNodeInfo[T] {
+	binNode := node.toBinTrieNode()
+	return TrieNodeInfo[T]{
+		Key:             node.getKey(),
+		Depth:           depth,
+		SplitBit:        node.getSplitBit(),
+		IsAdded:         binNode.IsAdded(),
+		HasLowerSubNode: binNode.GetLowerSubNode() != nil,
+		HasUpperSubNode: binNode.GetUpperSubNode() != nil,
+	}
+}
+
+func (node *trieNode[T, V]) structureIterator() Iterator[TrieNodeInfo[T]] {
+	return &trieStructureIterator[T, V]{iter: node.containingFirstAllNodeIterator(true), depth: node.getDepth()}
+}
+
+// TrieNodeInfo is the structural metadata of a trie node, including both added nodes and the non-added junction nodes that join them,
+// provided by the StructureIterator methods of tries and trie nodes for rendering the structure of a trie.
+type TrieNodeInfo[T TrieKeyConstraint[T]] struct {
+	// Key is the key of the node, a prefix block or an individual address.
+	Key T
+
+	// Depth is the number of ancestors of the node in its trie, which is zero for the root.
+	Depth int
+
+	// SplitBit is the index of the bit, counting from zero for the most significant bit, at which the keys of the sub-trie of the node diverge.
+	// The keys of the lower sub-trie have a zero bit at that index and those of the upper sub-trie have a one bit.
+	// It is the prefix length of the key, or the bit count when the key is an individual address, which has no sub-nodes.
+	SplitBit BitCount
+
+	// IsAdded is true for a node whose key was added to the trie, and false for a junction node.
+	IsAdded bool
+
+	// HasLowerSubNode and HasUpperSubNode indicate which of the sub-nodes of the node exist.
+	HasLowerSubNode, HasUpperSubNode bool
+}
+
+type trieStructureIterator[T TrieKeyConstraint[T], V any] struct {
+	iter  tree.CachingTrieNodeIterator[trieKey[T], V]
+	depth int // the depth of the first node
+}
+
+func (iter *trieStructureIterator[T, V]) HasNext() bool {
+	return iter.iter.HasNext()
+}
+
+func (iter *trieStructureIterator[T, V]) Next() (info TrieNodeInfo[T]) {
+	if iter.HasNext() {
+		node := (*trieNode[T, V])(unsafe.Pointer(iter.iter.Next()))
+		depth := iter.depth
+		if cached := iter.iter.GetCached(); cached != nil {
+			depth = cached.(int)
+		}
+		// the sub-nodes are one level deeper
+		iter.iter.CacheWithLowerSubNode(depth + 1)
+		iter.iter.CacheWithUpperSubNode(depth + 1)
+		info = node.getInfo(depth)
+	}
+	return
+}
+
 func toAddressTrieNode[T TrieKeyConstraint[T], V any](node *tree.BinTrieNode[trieKey[T], V]) *TrieNode[T] {
 	return (*TrieNode[T])(unsafe.Pointer(node))
 }
@@ -430,6 +588,26 @@ func (node *TrieNode[T]) IsLeaf() bool {
 	return node.toBinTrieNode().IsLeaf()
 }
 
+// GetSplitBit returns the index of the bit, counting from zero for the most significant bit, at which the keys of the sub-trie of this node diverge,
+// the keys of the lower sub-trie having a zero bit at that index and those of the upper sub-trie a one bit.
+// It is the prefix length of the key, or the bit count when the key is an individual address, which has no sub-nodes.
+func (node *TrieNode[T]) GetSplitBit() BitCount {
+	return node.tobase().getSplitBit()
+}
+
+// GetDepth returns the number of ancestors of this node in its trie, which is zero for the root.
+func (node *TrieNode[T]) GetDepth() int {
+	return node.tobase().getDepth()
+}
+
+// StructureIterator returns an iterator of the structural metadata of all the nodes of the sub-trie with this node as the root,
+// both added and non-added nodes, in a pre-order traversal with lower sub-nodes first.
+// Since each node is visited before its sub-nodes, the structure of the sub-trie can be reconstructed from the depth of each node.
+// Use it to render the structure of a trie, in preference to parsing the output of TreeString.
+func (node *TrieNode[T]) StructureIterator() Iterator[TrieNodeInfo[T]] {
+	return node.tobase().structureIterator()
+}
+
 // GetUpperSubNode gets the direct child node whose key is largest in value.
 func (node *TrieNode[T]) GetUpperSubNode() *TrieNode[T] {
 	return toAddressTrieNode[T](node.toBinTrieNode().GetUpperSubNode())
@@ -894,6 +1072,26 @@ func (node *AssociativeTrieNode[T, V]) IsLeaf() bool {
 	return node.toBinTrieNode().IsLeaf()
 }
 
+// GetSplitBit returns the index of the bit, counting from zero for the most significant bit, at which the keys of the sub-trie of this node diverge,
+// the keys of the lower sub-trie having a zero bit at that index and those of the upper sub-trie a one bit.
+// It is the prefix length of the key, or the bit count when the key is an individual address, which has no sub-nodes.
+func (node *AssociativeTrieNode[T, V]) GetSplitBit() BitCount {
+	return node.toBase().getSplitBit()
+}
+
+// GetDepth returns the number of ancestors of this node in its trie, which is zero for the root.
+func (node *AssociativeTrieNode[T, V]) GetDepth() int {
+	return node.toBase().getDepth()
+}
+
+// StructureIterator returns an iterator of the structural metadata of all the nodes of the sub-trie with this node as the root,
+// both added and non-added nodes, in a pre-order traversal with lower sub-nodes first.
+// Since each node is visited before its sub-nodes, the structure of the sub-trie can be reconstructed from the depth of each node.
+// Use it to render the structure of a trie, in preference to parsing the output of TreeString.
+func (node *AssociativeTrieNode[T, V]) StructureIterator() Iterator[TrieNodeInfo[T]] {
+	return node.toBase().structureIterator()
+}
+
 // ClearValue makes the value associated with this node the zero-value of V.
 func (node *AssociativeTrieNode[T, V]) ClearValue() {
 	node.toBinTrieNode().ClearValue()
@@ -909,6 +1107,78 @@ func (node *AssociativeTrieNode[T, V]) GetValue() V {
 	return node.toBinTrieNode().GetValue()
 }
 
+// CompareAndSetValue sets the value associated with this added node to the new value, but only if the current value is equal to the old value,
+// returning whether the value was set.  If the node is not an added node, the value is not set.
+//
+// Values are compared with the given function, or with reflect.DeepEqual if the function is nil.
+//
+// The comparison and update are atomic with respect to the other value updates of CompareAndSetValue and UpdateValue of AssociativeTrie,
+// and to the value reads of LoadValue, which allows goroutines to update the values of a trie concurrently.
+// They are not atomic with respect to SetValue, GetValue, or any method that changes the structure of the trie.
+// Goroutines can therefore share a trie by holding a read lock while looking up nodes and updating their values,
+// and a write lock while adding or removing nodes, without locking the whole trie for each value update.
+func (node *AssociativeTrieNode[T, V]) CompareAndSetValue(oldValue, newValue V, valuesEqual func(value, otherValue V) bool) bool {
+	valuesEqual = toValuesEqual(valuesEqual)
+	index := node.valueLockIndex()
+	lock := &nodeValueLocks[index]
+	lock.Lock()
+	defer lock.Unlock()
+	if !node.IsAdded() || !valuesEqual(node.GetValue(), oldValue) {
+		return false
+	}
+	node.SetValue(newValue)
+	nodeValueVersions[index]++
+	return true
+}
+
+// LoadValue returns the value associated with this node, like GetValue,
+// but reading the value atomically with respect to the value updates of CompareAndSetValue and UpdateValue of AssociativeTrie.
+func (node *AssociativeTrieNode[T, V]) LoadValue() V {
+	lock := &nodeValueLocks[node.valueLockIndex()]
+	lock.Lock()
+	defer lock.Unlock()
+	return node.GetValue()
+}
+
+// updateValue replaces the value of this added node with the value returned by the updater, atomically with respect to CompareAndSetValue.
+// The updater is called without holding a lock, so that it can read and update trie values without deadlocking,
+// and it is called again with the latest value whenever the value was updated concurrently, as with a compare-and-set retry loop.
+func (node *AssociativeTrieNode[T, V]) updateValue(updater func(V) V) V {
+	index := node.valueLockIndex()
+	lock := &nodeValueLocks[index]
+	valuesEqual := toValuesEqual[V](nil)
+	for {
+		lock.Lock()
+		value, version := node.GetValue(), nodeValueVersions[index]
+		lock.Unlock()
+
+		newValue := updater(value)
+
+		lock.Lock()
+		// the version changes with the updates of any node sharing the lock, so compare the values as well
+		if nodeValueVersions[index] == version || valuesEqual(node.GetValue(), value) {
+			node.SetValue(newValue)
+			nodeValueVersions[index]++
+			lock.Unlock()
+			return newValue
+		}
+		lock.Unlock()
+	}
+}
+
+// nodeValueLocks serialize the value updates of trie nodes, each node being assigned one of the locks by its memory address,
+// so that nodes need not hold a lock of their own.
+// nodeValueVersions count the value updates made while holding each lock, so that updateValue can detect updates made while it was computing a value.
+var (
+	nodeValueLocks    [64]sync.Mutex
+	nodeValueVersions [64]uint64
+)
+
+func (node *AssociativeTrieNode[T, V]) valueLockIndex() int {
+	// shift out the low bits, which are shared by all nodes due to alignment
+	return int((uintptr(unsafe.Pointer(node)) >> 4) % uintptr(len(nodeValueLocks)))
+}
+
 // GetUpperSubNode gets the direct child node whose key is largest in value.
 func (node *AssociativeTrieNode[T, V]) GetUpperSubNode() *AssociativeTrieNode[T, V] {
 	return toAssociativeTrieNode[T, V](node.toBinTrieNode().GetUpperSubNode())
@@ -1377,7 +1647,11 @@ func (path *ContainmentPath[T]) Count() int {
 }
 
 // String returns a visual representation of the Path with one node per line.
+// It returns "<nil>" if the receiver is a nil pointer.
 func (path *ContainmentPath[T]) String() string {
+	if path == nil {
+		return nilString()
+	}
 	return path.string()
 }
 
@@ -1417,7 +1691,11 @@ func (path *ContainmentValuesPath[T, V]) Count() int {
 }
 
 // String returns a visual representation of the Path with one node per line.
+// It returns "<nil>" if the receiver is a nil pointer.
 func (path *ContainmentValuesPath[T, V]) String() string {
+	if path == nil {
+		return nilString()
+	}
 	return path.string()
 }
 
@@ -1498,8 +1776,12 @@ func (node *ContainmentPathNode[T]) Count() int {
 	return node.count()
 }
 
-// String returns a visual representation of this node including the address key
+// String returns a visual representation of this node including the address key.
+// It returns "<nil>" if the receiver is a nil pointer.
 func (node *ContainmentPathNode[T]) String() string {
+	if node == nil {
+		return nilString()
+	}
 	return node.string()
 }
 
@@ -1545,8 +1827,12 @@ func (node *ContainmentValuesPathNode[T, V]) Count() int {
 	return node.count()
 }
 
-// String returns a visual representation of this node including the address key
+// String returns a visual representation of this node including the address key.
+// It returns "<nil>" if the receiver is a nil pointer.
 func (node *ContainmentValuesPathNode[T, V]) String() string {
+	if node == nil {
+		return nilString()
+	}
 	return node.string()
 }
 
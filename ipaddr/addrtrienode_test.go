@@ -0,0 +1,210 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"testing"
+
+	"github.com/seancfoley/bintree/tree"
+)
+
+// matchBitsResult records the result of MatchBits, for comparing the packed value path with the per-segment path
+type matchBitsResult struct {
+	called, bitsMatch bool
+	matchedBits       tree.BitCount
+}
+
+func (result *matchBitsResult) BitsMatch() {
+	result.called, result.bitsMatch = true, true
+}
+
+func (result *matchBitsResult) BitsDoNotMatch(matchedBits tree.BitCount) {
+	result.called, result.matchedBits = true, matchedBits
+}
+
+// segmentTrieKey returns a key without the packed value, so that MatchBits matches the key segment by segment
+func segmentTrieKey[T TrieKeyConstraint[T]](addr T) trieKey[T] {
+	return trieKey[T]{address: addr}
+}
+
+// prefixBlocks returns the given addresses along with their prefix blocks of each of the given prefix lengths
+func prefixBlocks[T interface {
+	TrieKeyConstraint[T]
+	ToPrefixBlockLen(BitCount) T
+}](addrs []T, prefLens ...BitCount) []T {
+	result := append([]T(nil), addrs...)
+	for _, addr := range addrs {
+		for _, prefLen := range prefLens {
+			if prefLen <= addr.GetBitCount() {
+				result = append(result, addr.ToPrefixBlockLen(prefLen))
+			}
+		}
+	}
+	return result
+}
+
+var (
+	matchBitsIPv4Strs = []string{"1.2.3.4", "1.2.3.5", "1.2.3.128", "1.2.4.4", "129.2.3.4", "0.0.0.0", "255.255.255.255"}
+	matchBitsIPv6Strs = []string{
+		"1:2:3:4:5:6:7:8", "1:2:3:4:5:6:7:9", "1:2:3:4:8005:6:7:8", "1:2:3:5:5:6:7:8",
+		"8001:2:3:4:5:6:7:8", "1:2:3:4::", "::", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+	}
+	matchBitsMACStrs   = []string{"1:2:3:4:5:6", "1:2:3:4:5:7", "1:2:3:84:5:6", "81:2:3:4:5:6", "0:0:0:0:0:0"}
+	matchBitsEUI64Strs = []string{"1:2:3:4:5:6:7:8", "1:2:3:4:5:6:7:9", "1:2:3:4:85:6:7:8", "ff:ff:ff:ff:ff:ff:ff:ff"}
+
+	matchBitsIPv4PrefLens = []BitCount{0, 1, 7, 8, 15, 16, 17, 24, 25, 31, 32}
+	matchBitsIPv6PrefLens = []BitCount{0, 1, 15, 16, 17, 32, 48, 63, 64, 65, 96, 112, 127, 128}
+	matchBitsMACPrefLens  = []BitCount{0, 1, 8, 12, 24, 25, 36, 47, 48, 63, 64}
+)
+
+func matchBitsIPv4Addrs() []*IPv4Address {
+	var addrs []*IPv4Address
+	for _, str := range matchBitsIPv4Strs {
+		addrs = append(addrs, NewIPAddressString(str).GetAddress().ToIPv4())
+	}
+	return prefixBlocks(addrs, matchBitsIPv4PrefLens...)
+}
+
+func matchBitsIPv6Addrs() []*IPv6Address {
+	var addrs []*IPv6Address
+	for _, str := range matchBitsIPv6Strs {
+		addrs = append(addrs, NewIPAddressString(str).GetAddress().ToIPv6())
+	}
+	return prefixBlocks(addrs, matchBitsIPv6PrefLens...)
+}
+
+func matchBitsMACAddrs(strs []string) []*MACAddress {
+	var addrs []*MACAddress
+	for _, str := range strs {
+		addrs = append(addrs, NewMACAddressString(str).GetAddress())
+	}
+	return prefixBlocks(addrs, matchBitsMACPrefLens...)
+}
+
+// testMatchBits checks that matching the packed values of two keys gives the same result as matching them segment by segment,
+// starting from each bit index up to the number of leading bits that the two keys share
+func testMatchBits[T TrieKeyConstraint[T]](t *testing.T, addrs []T) {
+	for _, addr := range addrs {
+		for _, existing := range addrs {
+			key, existingKey := newTrieKey(addr), newTrieKey(existing)
+			if key.bitCount == 0 || key.bitCount != existingKey.bitCount {
+				t.Fatalf("no packed value for %v or %v", addr, existing)
+			}
+			// the trie matches a node from the bit following the prefix of its parent node, which is no further than the prefix of the node
+			bitCount, maxBitIndex := addr.GetBitCount(), existing.GetBitCount()
+			if prefLen := existing.GetPrefixLen(); prefLen != nil {
+				maxBitIndex = prefLen.Len()
+			}
+			for bitIndex := 0; bitIndex <= maxBitIndex; bitIndex++ {
+				var valueResult, segmentResult matchBitsResult
+				valueDone := key.MatchBits(existingKey, bitIndex, &valueResult)
+				segmentDone := segmentTrieKey(addr).MatchBits(segmentTrieKey(existing), bitIndex, &segmentResult)
+				if valueDone != segmentDone || valueResult != segmentResult {
+					t.Errorf("matching %v to %v from bit %d: packed values gave %v %+v, segments gave %v %+v",
+						addr, existing, bitIndex, valueDone, valueResult, segmentDone, segmentResult)
+				}
+				if bitIndex < bitCount && addr.IsOneBit(bitIndex) != existing.IsOneBit(bitIndex) {
+					// the trie never matches beyond the first differing bit
+					break
+				}
+			}
+		}
+	}
+}
+
+// testTrieMatching checks that trie lookups with packed value keys give the same results as lookups with keys matched segment by segment
+func testTrieMatching[T TrieKeyConstraint[T]](t *testing.T, addrs []T) {
+	var trie Trie[T]
+	for i, addr := range addrs {
+		// leave some addresses out of the trie so that some lookups do not match
+		if i%3 != 0 {
+			trie.Add(addr)
+		}
+	}
+	for _, addr := range addrs {
+		valueMatch, valueFound := trie.trie.LongestPrefixMatch(newTrieKey(addr))
+		segmentMatch, segmentFound := trie.trie.LongestPrefixMatch(segmentTrieKey(addr))
+		if valueFound != segmentFound || (valueFound && !valueMatch.address.ToAddressBase().Equal(segmentMatch.address.ToAddressBase())) {
+			t.Errorf("longest prefix match of %v: packed values gave %v %v, segments gave %v %v",
+				addr, valueMatch.address, valueFound, segmentMatch.address, segmentFound)
+		}
+		if valueContains, segmentContains := trie.trie.ElementContains(newTrieKey(addr)), trie.trie.ElementContains(segmentTrieKey(addr)); valueContains != segmentContains {
+			t.Errorf("element contains %v: packed values gave %v, segments gave %v", addr, valueContains, segmentContains)
+		}
+		if valueContained, segmentContained := trie.trie.Contains(newTrieKey(addr)), trie.trie.Contains(segmentTrieKey(addr)); valueContained != segmentContained {
+			t.Errorf("contains %v: packed values gave %v, segments gave %v", addr, valueContained, segmentContained)
+		}
+	}
+}
+
+func TestTrieKeyMatchBits(t *testing.T) {
+	testMatchBits(t, matchBitsIPv4Addrs())
+	testMatchBits(t, matchBitsIPv6Addrs())
+	testMatchBits(t, matchBitsMACAddrs(matchBitsMACStrs))
+	testMatchBits(t, matchBitsMACAddrs(matchBitsEUI64Strs))
+}
+
+func TestTrieKeyMatching(t *testing.T) {
+	testTrieMatching(t, matchBitsIPv4Addrs())
+	testTrieMatching(t, matchBitsIPv6Addrs())
+	testTrieMatching(t, matchBitsMACAddrs(matchBitsMACStrs))
+	testTrieMatching(t, matchBitsMACAddrs(matchBitsEUI64Strs))
+}
+
+// benchmarkTrieLookups compares trie lookups using keys with packed values to lookups using keys matched segment by segment
+func benchmarkTrieLookups(b *testing.B, lookup func(trie *tree.BinTrie[trieKey[*IPv6Address], emptyValue], key trieKey[*IPv6Address])) {
+	var trie Trie[*IPv6Address]
+	var addrs []*IPv6Address
+	block := NewIPAddressString("2001:db8::/48").GetAddress().ToIPv6()
+	iter := block.SetPrefixLen(56).PrefixBlockIterator()
+	for i := 0; iter.HasNext(); i++ {
+		prefixBlock := iter.Next()
+		if i%2 == 0 {
+			trie.Add(prefixBlock)
+		}
+		addrs = append(addrs, prefixBlock.GetLower().Increment(int64(i)).WithoutPrefixLen())
+	}
+	for _, keyType := range []struct {
+		name   string
+		newKey func(*IPv6Address) trieKey[*IPv6Address]
+	}{
+		{"packed", newTrieKey[*IPv6Address]},
+		{"segments", segmentTrieKey[*IPv6Address]},
+	} {
+		keys := make([]trieKey[*IPv6Address], len(addrs))
+		for i, addr := range addrs {
+			keys[i] = keyType.newKey(addr)
+		}
+		b.Run(keyType.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				lookup(&trie.trie, keys[i%len(keys)])
+			}
+		})
+	}
+}
+
+func BenchmarkTrieLongestPrefixMatch(b *testing.B) {
+	benchmarkTrieLookups(b, func(trie *tree.BinTrie[trieKey[*IPv6Address], emptyValue], key trieKey[*IPv6Address]) {
+		trie.LongestPrefixMatch(key)
+	})
+}
+
+func BenchmarkTrieElementContains(b *testing.B) {
+	benchmarkTrieLookups(b, func(trie *tree.BinTrie[trieKey[*IPv6Address], emptyValue], key trieKey[*IPv6Address]) {
+		trie.ElementContains(key)
+	})
+}
@@ -22,6 +22,8 @@ import (
 	"math/big"
 	"sort"
 	"strings"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
 )
 
 // PrefixBlockConstraint is the generic type constraint used for a prefix block allocator.
@@ -121,6 +123,8 @@ func (alloc *PrefixBlockAllocator[T]) AddAvailable(blocks ...T) {
 			blocks = append(blocks, existingBlocks...)
 			alloc.blocks[i] = nil
 		}
+		// the existing blocks are counted again when re-inserted
+		alloc.totalBlockCount = 0
 	}
 
 	blocks = blocks[0].MergeToPrefixBlocks(blocks...)
@@ -241,6 +245,24 @@ func (alloc *PrefixBlockAllocator[T]) AllocateBitLen(bitLength BitCount) T {
 	return result
 }
 
+// AllocatePrefixLen allocates a block with the given prefix length,
+// or nil if no such block is available in the allocator, or if the prefix length is not valid for the allocator's IP version.
+// The reserved count is ignored when allocating by prefix length.
+func (alloc *PrefixBlockAllocator[T]) AllocatePrefixLen(prefixLen BitCount) T {
+	if alloc.totalBlockCount == 0 || prefixLen < 0 || prefixLen > alloc.version.GetBitCount() {
+		var t T
+		return t // nil
+	}
+	return alloc.AllocateBitLen(alloc.version.GetBitCount() - prefixLen)
+}
+
+// Free returns previously allocated blocks to the allocator, making them available for allocation once again.
+// Returned blocks are merged with any adjacent available blocks, so that larger blocks can be allocated from them.
+// Like AddAvailable, it panics if a block does not match the allocator's IP version.
+func (alloc *PrefixBlockAllocator[T]) Free(blocks ...T) {
+	alloc.AddAvailable(blocks...)
+}
+
 // AllocateMultiBitLens returns multiple blocks of the given bit-lengths,
 // or nil if there is insufficient space in the allocator.
 // The reserved count is ignored when allocating by bit-length.
@@ -267,6 +289,83 @@ func (alloc *PrefixBlockAllocator[T]) AllocateMultiBitLens(bitLengths ...BitCoun
 	return result
 }
 
+// AllocateSubnets lays out subnets within the given parent block using variable-length subnet masks (VLSM),
+// allocating a block for each of the given host counts, largest first, so that the blocks do not overlap and leave as little unused space as possible.
+// Each block is the smallest prefix block accommodating its host count as well as the given reserved count,
+// which for IPv4 is typically two, for the network and broadcast addresses.
+// Host counts which the reserved count reduces to zero are skipped.
+//
+// The blocks are returned in the order allocated, from largest to smallest.
+// An error is returned if the parent block has insufficient space, with the value of the error being the first host count that could not be accommodated,
+// or math.MaxInt when that host count exceeds math.MaxInt.
+// The parent need not be a single prefix block, in which case it is divided into prefix blocks for allocation.
+func AllocateSubnets[T PrefixBlockConstraint[T]](parent T, reservedCount int, hostCounts ...uint64) ([]AllocatedBlock[T], addrerr.AddressValueError) {
+	alloc := PrefixBlockAllocator[T]{}
+	alloc.AddAvailable(parent)
+	alloc.SetReserved(reservedCount)
+	counts := append(make([]uint64, 0, len(hostCounts)), hostCounts...)
+	sort.Slice(counts, func(i, j int) bool {
+		return counts[i] > counts[j]
+	})
+	result := make([]AllocatedBlock[T], 0, len(counts))
+	for _, hostCount := range counts {
+		if reservedCount < 0 && uint64(-reservedCount) >= hostCount {
+			continue
+		} else if reservedCount == 0 && hostCount == 0 {
+			continue
+		}
+		allocated := alloc.AllocateSize(hostCount)
+		var t T
+		if allocated == t {
+			val := math.MaxInt
+			if hostCount < math.MaxInt {
+				val = int(hostCount)
+			}
+			return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.insufficient.space"}, val: val}
+		}
+		result = append(result, AllocatedBlock[T]{
+			blockSize:     new(big.Int).SetUint64(hostCount),
+			reservedCount: reservedCount,
+			block:         allocated,
+		})
+	}
+	return result, nil
+}
+
+// AllocateSubnetPrefixLens lays out subnets within the given parent block using variable-length subnet masks (VLSM),
+// allocating a block for each of the given prefix lengths, shortest first, so that the blocks do not overlap.
+//
+// The blocks are returned in the order allocated, from largest to smallest.
+// An error is returned if a prefix length is shorter than the prefix length of the parent or exceeds the address bit count,
+// or if the parent block has insufficient space, with the value of the error being the offending prefix length.
+func AllocateSubnetPrefixLens[T PrefixBlockConstraint[T]](parent T, prefixLens ...BitCount) ([]AllocatedBlock[T], addrerr.AddressValueError) {
+	bitCount := parent.GetBitCount()
+	parentPrefixLen := parent.GetPrefixLen().LenOr(bitCount)
+	lengths := append(make([]BitCount, 0, len(prefixLens)), prefixLens...)
+	sort.Ints(lengths)
+	for _, prefixLen := range lengths {
+		if prefixLen < parentPrefixLen || prefixLen > bitCount {
+			return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixSize"}, val: prefixLen}
+		}
+	}
+	alloc := PrefixBlockAllocator[T]{}
+	alloc.AddAvailable(parent)
+	result := make([]AllocatedBlock[T], 0, len(lengths))
+	for _, prefixLen := range lengths {
+		allocated := alloc.AllocatePrefixLen(prefixLen)
+		var t T
+		if allocated == t {
+			return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.insufficient.space"}, val: prefixLen}
+		}
+		hostBitCount := HostBitCount(bitCount - prefixLen)
+		result = append(result, AllocatedBlock[T]{
+			blockSize: hostBitCount.BlockSize(),
+			block:     allocated,
+		})
+	}
+	return result, nil
+}
+
 // String returns a string showing the counts of available blocks for each prefix size in the allocator.
 func (alloc PrefixBlockAllocator[T]) String() string {
 	var builder strings.Builder
@@ -0,0 +1,113 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// Anonymize truncates this address or subnet for privacy, zeroing the host bits following the given prefix length for its IP version,
+// such as 24 for IPv4 and 48 for IPv6, commonly used when logging addresses.
+// The zero host is obtained as with ToZeroHostLen, so the result has no prefix length unless this address has the same prefix length.
+//
+// This returns an error if this is a subnet that cannot be converted to a subnet of zero hosts, as with ToZeroHostLen.
+// If this address is neither IPv4 nor IPv6, it is returned unchanged.
+func (addr *IPAddress) Anonymize(ipv4PrefLen, ipv6PrefLen BitCount) (*IPAddress, addrerr.IncompatibleAddressError) {
+	addr = addr.init()
+	if addr.IsIPv4() {
+		return addr.ToZeroHostLen(ipv4PrefLen)
+	} else if addr.IsIPv6() {
+		return addr.ToZeroHostLen(ipv6PrefLen)
+	}
+	return addr, nil
+}
+
+// AddressPseudonymizer maps individual IP addresses to pseudonymous addresses of the same IP version,
+// so that addresses can be recorded, for example in logs, and correlated with each other, without revealing the original addresses.
+//
+// PrefixPreservingPseudonymizer is an implementation using a secret key.
+type AddressPseudonymizer interface {
+	// Pseudonymize returns the pseudonymous address for the given individual address.
+	// An error is returned if the given address is a subnet of multiple addresses, or is neither IPv4 nor IPv6.
+	Pseudonymize(addr *IPAddress) (*IPAddress, addrerr.IncompatibleAddressError)
+}
+
+// PrefixPreservingPseudonymizer is an AddressPseudonymizer that permutes addresses with a keyed hash, in the manner of Crypto-PAn.
+//
+// Each bit of an address is flipped or not depending upon the HMAC-SHA256, with the secret key, of the bits preceding it.
+// The mapping is deterministic for a given key, it is a permutation of the addresses of each IP version, and it is prefix-preserving:
+// two addresses sharing a prefix of a given length are mapped to two addresses sharing a prefix of the same length.
+// So the pseudonymous addresses can still be grouped into subnets, without being linked to the original subnets by anyone without the key.
+//
+// The zero value is not usable, use NewPrefixPreservingPseudonymizer to create one.
+// A PrefixPreservingPseudonymizer can be used concurrently by multiple goroutines.
+type PrefixPreservingPseudonymizer struct {
+	key []byte
+}
+
+var _ AddressPseudonymizer = &PrefixPreservingPseudonymizer{}
+
+// NewPrefixPreservingPseudonymizer creates a PrefixPreservingPseudonymizer with the given secret key.
+// The key should be at least 32 bytes of random data.
+func NewPrefixPreservingPseudonymizer(key []byte) *PrefixPreservingPseudonymizer {
+	return &PrefixPreservingPseudonymizer{key: cloneBytes(key)}
+}
+
+// Pseudonymize returns the pseudonymous address for the given individual address.
+// The pseudonymous address has no prefix length and no zone.
+//
+// An error is returned if the given address is a subnet of multiple addresses, or is neither IPv4 nor IPv6.
+func (pseudonymizer *PrefixPreservingPseudonymizer) Pseudonymize(addr *IPAddress) (*IPAddress, addrerr.IncompatibleAddressError) {
+	if addr == nil || addr.IsMultiple() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.single.address.required"}}
+	}
+	version := addr.GetIPVersion()
+	if version.IsIndeterminate() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipVersionIndeterminate"}}
+	}
+	original := addr.Bytes()
+	result := cloneBytes(original)
+	mac := hmac.New(sha256.New, pseudonymizer.key)
+	input := make([]byte, 2+len(original))
+	input[0] = byte(len(original)) // distinguishes the IP versions
+	var sum []byte
+	for i := 0; i < len(original)<<3; i++ {
+		// the input is the bit index and the bits of the original address preceding it
+		input[1] = byte(i)
+		byteIndex := i >> 3
+		if bitIndex := i & 7; bitIndex == 0 {
+			if byteIndex > 0 {
+				input[byteIndex+1] = original[byteIndex-1]
+			}
+		} else {
+			input[byteIndex+2] = original[byteIndex] & ^(0xff >> uint(bitIndex))
+		}
+		mac.Reset()
+		mac.Write(input)
+		sum = mac.Sum(sum[:0])
+		result[byteIndex] ^= (sum[0] & 0x80) >> uint(i&7)
+	}
+	if version.IsIPv4() {
+		res, _ := NewIPv4AddressFromBytes(result)
+		return res.ToIP(), nil
+	}
+	res, _ := NewIPv6AddressFromBytes(result)
+	return res.ToIP(), nil
+}
@@ -0,0 +1,210 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import "math/bits"
+
+const (
+	// HostBitmapHostBitCount is the number of host bits of the blocks represented by a HostBitmap, the blocks being IPv4 /16 or IPv6 /112 prefix blocks.
+	HostBitmapHostBitCount BitCount = 16
+
+	// HostBitmapWordCount is the number of 64-bit words in the bitmap of a HostBitmap, one bit for each of the 65536 addresses in the block.
+	HostBitmapWordCount = (1 << HostBitmapHostBitCount) / 64
+)
+
+// HostBitmap is a set of the addresses within an IPv4 /16 or IPv6 /112 prefix block, stored as a bitmap with one bit per address.
+//
+// Bit i of the bitmap, which is bit i % 64 of word i / 64, corresponds to the address at offset i from the lowest address in the block.
+// The words are available from Words and a HostBitmap can be created from them with NewHostBitmapFromWords,
+// allowing large membership joins to be computed outside the library with bitwise operations, and the results converted back to ranges or prefix blocks.
+//
+// The zero value is an empty bitmap with no block.  Use NewHostBitmap to create a bitmap for a given block.
+type HostBitmap struct {
+	block *IPAddress
+	words [HostBitmapWordCount]uint64
+}
+
+// NewHostBitmap creates an empty bitmap for the IPv4 /16 or IPv6 /112 prefix block containing the lowest address of the given address or subnet.
+// It returns nil if the given address is nil or is neither IPv4 nor IPv6.
+func NewHostBitmap(addr *IPAddress) *HostBitmap {
+	if addr == nil || addr.GetIPVersion().IsIndeterminate() {
+		return nil
+	}
+	block := addr.GetLower().ToPrefixBlockLen(addr.GetBitCount() - HostBitmapHostBitCount)
+	return &HostBitmap{block: block}
+}
+
+// NewHostBitmapFromWords creates a bitmap for the IPv4 /16 or IPv6 /112 prefix block containing the lowest address of the given address or subnet,
+// with the bits from the given words.  Missing words are treated as zero and extra words are ignored.
+// It returns nil if the given address is nil or is neither IPv4 nor IPv6.
+func NewHostBitmapFromWords(addr *IPAddress, words []uint64) *HostBitmap {
+	bitmap := NewHostBitmap(addr)
+	if bitmap != nil {
+		copy(bitmap.words[:], words)
+	}
+	return bitmap
+}
+
+// GetBlock returns the prefix block whose addresses are represented by this bitmap.
+func (bitmap *HostBitmap) GetBlock() *IPAddress {
+	return bitmap.block
+}
+
+// Words returns a copy of the bitmap words, HostBitmapWordCount words in all.
+func (bitmap *HostBitmap) Words() []uint64 {
+	result := make([]uint64, HostBitmapWordCount)
+	copy(result, bitmap.words[:])
+	return result
+}
+
+// Add adds the addresses of the given address or subnet that are within the block of this bitmap.
+// It returns whether any of the addresses were within the block.
+// Addresses of a different IP version are ignored.
+func (bitmap *HostBitmap) Add(addr *IPAddress) (added bool) {
+	if bitmap.block == nil || addr == nil || !versionsMatch(bitmap.block, addr) {
+		return false
+	}
+	// iterate only the part within the block, the whole argument may have far too many sequential blocks
+	addr = addr.Intersect(bitmap.block)
+	if addr == nil {
+		return false
+	}
+	iterator := addr.SequentialBlockIterator()
+	for iterator.HasNext() {
+		added = bitmap.AddRange(iterator.Next().ToSequentialRange()) || added
+	}
+	return
+}
+
+// AddRange adds the addresses of the given sequential range that are within the block of this bitmap.
+// It returns whether any of the addresses were within the block.
+// Ranges of a different IP version are ignored.
+func (bitmap *HostBitmap) AddRange(rng *SequentialRange[*IPAddress]) bool {
+	if bitmap.block == nil || rng == nil || !versionsMatch(bitmap.block, rng.GetLower()) {
+		return false
+	}
+	rng = rng.Intersect(bitmap.block.ToSequentialRange())
+	if rng == nil {
+		return false
+	}
+	bitmap.setBits(hostBitmapOffset(rng.GetLower().Bytes()), hostBitmapOffset(rng.GetUpper().Bytes()))
+	return true
+}
+
+// Contains returns whether all the addresses of the given address or subnet are in the bitmap.
+func (bitmap *HostBitmap) Contains(addr *IPAddress) bool {
+	if bitmap.block == nil || addr == nil || !bitmap.block.Contains(addr) {
+		return false
+	}
+	iterator := addr.Intersect(bitmap.block).SequentialBlockIterator()
+	for iterator.HasNext() {
+		block := iterator.Next()
+		lower, upper := hostBitmapOffset(block.Bytes()), hostBitmapOffset(block.UpperBytes())
+		for i := lower; i <= upper; i++ {
+			if bitmap.words[i>>6]&(1<<uint(i&63)) == 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GetCount returns the number of addresses in the bitmap.
+func (bitmap *HostBitmap) GetCount() int {
+	var count int
+	for _, word := range bitmap.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+// IsEmpty returns whether the bitmap has no addresses.
+func (bitmap *HostBitmap) IsEmpty() bool {
+	for _, word := range bitmap.words {
+		if word != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// ToSequentialRanges returns the minimal list of sequential ranges containing the addresses of the bitmap, in increasing order.
+func (bitmap *HostBitmap) ToSequentialRanges() []*SequentialRange[*IPAddress] {
+	if bitmap.block == nil {
+		return nil
+	}
+	var result []*SequentialRange[*IPAddress]
+	lowest := bitmap.block.GetLower().WithoutPrefixLen()
+	for i := 0; i < 1<<HostBitmapHostBitCount; {
+		start := bitmap.nextBit(i, true)
+		if start < 0 {
+			break
+		}
+		end := bitmap.nextBit(start, false)
+		if end < 0 {
+			end = 1 << HostBitmapHostBitCount
+		}
+		result = append(result, lowest.Increment(int64(start)).SpanWithRange(lowest.Increment(int64(end-1))))
+		i = end
+	}
+	return result
+}
+
+// ToPrefixBlocks returns the minimal list of prefix blocks containing the addresses of the bitmap, in increasing order.
+func (bitmap *HostBitmap) ToPrefixBlocks() []*IPAddress {
+	var result []*IPAddress
+	for _, rng := range bitmap.ToSequentialRanges() {
+		result = append(result, rng.SpanWithPrefixBlocks()...)
+	}
+	return result
+}
+
+// nextBit returns the index of the first bit at or after the given index with the given value, or -1 if there is none.
+func (bitmap *HostBitmap) nextBit(index int, set bool) int {
+	for wordIndex := index >> 6; wordIndex < HostBitmapWordCount; wordIndex++ {
+		word := bitmap.words[wordIndex]
+		if !set {
+			word = ^word
+		}
+		if wordIndex == index>>6 {
+			word &= ^uint64(0) << uint(index&63)
+		}
+		if word != 0 {
+			return wordIndex<<6 + bits.TrailingZeros64(word)
+		}
+	}
+	return -1
+}
+
+// setBits sets the bits from lower to upper inclusive.
+func (bitmap *HostBitmap) setBits(lower, upper int) {
+	for wordIndex := lower >> 6; wordIndex <= upper>>6; wordIndex++ {
+		mask := ^uint64(0)
+		if wordIndex == lower>>6 {
+			mask &= ^uint64(0) << uint(lower&63)
+		}
+		if wordIndex == upper>>6 {
+			mask &= ^uint64(0) >> uint(63-upper&63)
+		}
+		bitmap.words[wordIndex] |= mask
+	}
+}
+
+// hostBitmapOffset returns the offset within its block of the address with the given bytes, the value of the last two bytes.
+func hostBitmapOffset(bytes []byte) int {
+	return int(bytes[len(bytes)-2])<<8 | int(bytes[len(bytes)-1])
+}
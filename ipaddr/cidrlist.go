@@ -0,0 +1,104 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"strings"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// ToCIDRList returns the canonical CIDR strings of the given prefix blocks, in the same order, such as "1.2.0.0/16" and "a:b::/64".
+// An individual address without a prefix length is written with the full bit-length as its prefix length, such as "1.2.3.4/32".
+// IPv6 zones are omitted.
+//
+// The conversion is all or nothing.  If any of the given addresses is nil, is not an IPv4 or IPv6 address,
+// or is neither a single prefix block nor an individual address, no strings are returned,
+// and the returned error describes every such address along with its index.
+// To write a subnet that is not a prefix block, first split it with SpanWithPrefixBlocks.
+func ToCIDRList(blocks []*IPAddress) ([]string, error) {
+	cidrs := make([]string, 0, len(blocks))
+	var errs []error
+	for i, block := range blocks {
+		block, err := toCIDRBlock(block)
+		if err != nil {
+			errs = append(errs, wrapErrf(err, "address at index %d", i))
+		} else if errs == nil {
+			cidrs = append(cidrs, block.ToCanonicalString())
+		}
+	}
+	if errs != nil {
+		return nil, mergeAllErrs(errs...)
+	}
+	return cidrs, nil
+}
+
+// FromCIDRList parses the given CIDR strings, one per line, such as the lines of a file, returning the prefix blocks in the same order.
+// Surrounding whitespace is ignored, as are IPv6 zones.
+// A string of an individual address without a prefix length is parsed as the prefix block of that address alone, with the full bit-length as its prefix length.
+//
+// The parsing is strict and all or nothing.  Each string must be the CIDR notation of a single IPv4 or IPv6 prefix block,
+// so that a string with host bits set, such as "1.2.3.4/16", is rejected rather than silently widened to its enclosing block,
+// as are empty strings and strings of subnets that are not prefix blocks.
+// If any string is rejected, no addresses are returned,
+// and the returned error describes every rejected string along with its line number, numbering the lines from 1.
+func FromCIDRList(cidrs []string) ([]*IPAddress, error) {
+	blocks := make([]*IPAddress, 0, len(cidrs))
+	var errs []error
+	for i, cidr := range cidrs {
+		block, err := parseCIDRBlock(cidr)
+		if err != nil {
+			errs = append(errs, wrapErrf(err, "line %d", i+1))
+		} else if errs == nil {
+			blocks = append(blocks, block)
+		}
+	}
+	if errs != nil {
+		return nil, mergeAllErrs(errs...)
+	}
+	return blocks, nil
+}
+
+func parseCIDRBlock(cidr string) (*IPAddress, addrerr.AddressError) {
+	if strings.TrimSpace(cidr) == "" {
+		// the parser would otherwise interpret an empty string as the zero address
+		return nil, &addressStringError{addressError{str: cidr, key: "ipaddress.error.empty"}}
+	}
+	addr, err := NewIPAddressString(cidr).ToAddress()
+	if err != nil {
+		return nil, err
+	}
+	return toCIDRBlock(addr)
+}
+
+// toCIDRBlock returns the given address as a single prefix block, assigning the full prefix length to an individual address without one
+func toCIDRBlock(addr *IPAddress) (*IPAddress, addrerr.AddressError) {
+	if addr == nil || !addr.IsIPv4() && !addr.IsIPv6() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipVersionIndeterminate"}}
+	} else if ipv6Addr := addr.ToIPv6(); ipv6Addr != nil {
+		addr = ipv6Addr.WithoutZone().ToIP()
+	}
+	if !addr.IsPrefixed() {
+		if addr.IsMultiple() {
+			return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.not.block"}}
+		}
+		return addr.ToPrefixBlockLen(addr.GetBitCount()), nil
+	} else if !addr.IsSinglePrefixBlock() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.not.block"}}
+	}
+	return addr, nil
+}
@@ -0,0 +1,152 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+// AddressConstraint is the generic type constraint for the address and subnet types,
+// allowing generic code to operate uniformly on slices of any one address type.
+//
+// The generic type T can be *Address, *IPAddress, *IPv4Address, *IPv6Address or *MACAddress.
+type AddressConstraint[T any] interface {
+	TrieKeyConstraint[T]
+	AddressType
+}
+
+var (
+	_ = Min[*Address]
+	_ = Min[*IPAddress]
+	_ = Min[*IPv4Address]
+	_ = Min[*IPv6Address]
+	_ = Min[*MACAddress]
+)
+
+// Min returns the lowest of the given addresses and subnets, using the ordering of CountComparator, the same ordering as the Compare method of the addresses.
+// Nil addresses are ignored.  If there are no non-nil addresses, nil is returned.
+func Min[T AddressConstraint[T]](addrs ...T) (result T) {
+	var zero T
+	for _, addr := range addrs {
+		if addr != zero && (result == zero || CountComparator.CompareAddresses(addr, result) < 0) {
+			result = addr
+		}
+	}
+	return
+}
+
+// Max returns the highest of the given addresses and subnets, using the ordering of CountComparator, the same ordering as the Compare method of the addresses.
+// Nil addresses are ignored.  If there are no non-nil addresses, nil is returned.
+func Max[T AddressConstraint[T]](addrs ...T) (result T) {
+	var zero T
+	for _, addr := range addrs {
+		if addr != zero && (result == zero || CountComparator.CompareAddresses(addr, result) > 0) {
+			result = addr
+		}
+	}
+	return
+}
+
+// SortedUnique returns a new slice with the given addresses and subnets sorted with CountComparator, as with SortAddresses,
+// and with duplicates removed, duplicates being those that are equal according to their Equal method.
+// Nil addresses are omitted.  The given slice is not modified.
+func SortedUnique[T AddressConstraint[T]](addrs []T) []T {
+	var zero T
+	result := make([]T, 0, len(addrs))
+	for _, addr := range addrs {
+		if addr != zero {
+			result = append(result, addr)
+		}
+	}
+	SortAddresses(result)
+	unique := result[:0]
+	for i, addr := range result {
+		if i == 0 || !addr.Equal(unique[len(unique)-1]) {
+			unique = append(unique, addr)
+		}
+	}
+	return unique
+}
+
+// MergeAllToPrefixBlocks merges the given addresses and subnets of both IP versions to produce the smallest slice of prefix blocks covering them.
+//
+// The IPv4 and IPv6 addresses are merged separately, as with MergeToPrefixBlocks, and the result is the merged IPv4 blocks followed by the merged IPv6 blocks,
+// each sorted from lowest address value to highest, so the result does not depend on the order of the arguments.
+// Nil addresses and addresses that are neither IPv4 nor IPv6 are ignored.  If there are no IPv4 nor IPv6 addresses, nil is returned.
+func MergeAllToPrefixBlocks(addrs ...*IPAddress) []*IPAddress {
+	return mergeAll(addrs, (*IPAddress).MergeToPrefixBlocks)
+}
+
+// MergeAllToSequentialBlocks merges the given addresses and subnets of both IP versions to produce the smallest slice of sequential blocks covering them.
+//
+// The IPv4 and IPv6 addresses are merged separately, as with MergeToSequentialBlocks, and the result is the merged IPv4 blocks followed by the merged IPv6 blocks,
+// each sorted from lowest address value to highest, so the result does not depend on the order of the arguments.
+// Nil addresses and addresses that are neither IPv4 nor IPv6 are ignored.  If there are no IPv4 nor IPv6 addresses, nil is returned.
+func MergeAllToSequentialBlocks(addrs ...*IPAddress) []*IPAddress {
+	return mergeAll(addrs, (*IPAddress).MergeToSequentialBlocks)
+}
+
+func mergeAll(addrs []*IPAddress, merger func(*IPAddress, ...*IPAddress) []*IPAddress) (result []*IPAddress) {
+	var ipv4Addrs, ipv6Addrs []*IPAddress
+	for _, addr := range addrs {
+		if addr.IsIPv4() {
+			ipv4Addrs = append(ipv4Addrs, addr)
+		} else if addr.IsIPv6() {
+			ipv6Addrs = append(ipv6Addrs, addr)
+		}
+	}
+	if len(ipv4Addrs) > 0 {
+		result = append(result, merger(ipv4Addrs[0], ipv4Addrs[1:]...)...)
+	}
+	if len(ipv6Addrs) > 0 {
+		result = append(result, merger(ipv6Addrs[0], ipv6Addrs[1:]...)...)
+	}
+	return
+}
+
+// ContainsAll returns whether each of the given contained addresses and subnets is contained by at least one of the given addresses and subnets.
+// Nil contained addresses are ignored.  When there are no contained addresses, true is returned.
+//
+// Containment is determined by the Contains method, so an address or subnet spanning several of the given addresses,
+// without being contained by any one of them, is not considered contained.
+func ContainsAll[T AddressConstraint[T]](addrs []T, contained ...T) bool {
+	var zero T
+	for _, other := range contained {
+		if other != zero && !containsAny(addrs, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// ContainsAny returns whether any of the given contained addresses and subnets is contained by at least one of the given addresses and subnets.
+// Nil contained addresses are ignored.  When there are no contained addresses, false is returned.
+func ContainsAny[T AddressConstraint[T]](addrs []T, contained ...T) bool {
+	var zero T
+	for _, other := range contained {
+		if other != zero && containsAny(addrs, other) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny[T AddressConstraint[T]](addrs []T, other T) bool {
+	var zero T
+	for _, addr := range addrs {
+		if addr != zero && addr.Contains(other) {
+			return true
+		}
+	}
+	return false
+}
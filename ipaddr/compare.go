@@ -20,21 +20,21 @@ import "math/big"
 
 var (
 	// CountComparator compares by count first, then by value.
-	CountComparator = AddressComparator{countComparator{}}
+	CountComparator = AddressComparator{componentComparator: countComparator{}}
 
 	// HighValueComparator compares by high value first, then low, then count.
-	HighValueComparator = AddressComparator{valueComparator{compareHighValue: true}}
+	HighValueComparator = AddressComparator{componentComparator: valueComparator{compareHighValue: true}}
 
 	// LowValueComparator compares by low value first, then high, then count.
-	LowValueComparator = AddressComparator{valueComparator{}}
+	LowValueComparator = AddressComparator{componentComparator: valueComparator{}}
 
 	// With the reverse comparators, ordering with the secondary values (higher or lower) follow a reverse ordering than the primary values (lower or higher)
 
 	// ReverseHighValueComparator is like HighValueComparator but when comparing the low value, reverses the comparison.
-	ReverseHighValueComparator = AddressComparator{valueComparator{compareHighValue: true, flipSecond: true}}
+	ReverseHighValueComparator = AddressComparator{componentComparator: valueComparator{compareHighValue: true, flipSecond: true}}
 
 	// ReverseLowValueComparator is like LowValueComparator but when comparing the high value, reverses the comparison.
-	ReverseLowValueComparator = AddressComparator{valueComparator{flipSecond: true}}
+	ReverseLowValueComparator = AddressComparator{componentComparator: valueComparator{flipSecond: true}}
 )
 
 type componentComparator interface {
@@ -251,13 +251,34 @@ func checkRangeTypeX(r IPAddressSeqRangeType) (isNil bool, rngType rangeType, rn
 // AddressComparator has methods to compare addresses, or sections, or division series, or segments, or divisions, or sequential ranges.
 // AddressComparator also allows you to compare any two instances of any such address items, using the Compare method.
 // The zero value acts like CountComparator, the default comparator.
+//
+// Each comparator is a total ordering of address items.  Use Reverse to obtain a comparator with the opposite ordering.
+// Items of the same type, bit count and values compare as equal regardless of prefix length,
+// so use a stable sort such as sort.SliceStable, as SortAddresses and SortRanges do, to retain the original order of such items.
 type AddressComparator struct {
 	componentComparator componentComparator
+	reversed            bool
+}
+
+// Reverse returns a comparator with the reverse ordering of this comparator.
+// Reversing a reversed comparator gives back the original ordering.
+func (comp AddressComparator) Reverse() AddressComparator {
+	comp.reversed = !comp.reversed
+	return comp
+}
+
+// IsReversed returns whether this comparator was obtained by reversing another with Reverse.
+// ReverseHighValueComparator and ReverseLowValueComparator are not reversed comparators, they reverse only the secondary comparison of values.
+func (comp AddressComparator) IsReversed() bool {
+	return comp.reversed
 }
 
 // CompareAddresses compares any two addresses (including different versions or address types)
 // It returns a negative integer, zero, or a positive integer if address item one is less than, equal, or greater than address item two.
 func (comp AddressComparator) CompareAddresses(one, two AddressType) int {
+	if comp.reversed {
+		return comp.Reverse().CompareAddresses(two, one)
+	}
 	if one == nil || one.ToAddressBase() == nil {
 		if two == nil || two.ToAddressBase() == nil {
 			return 0
@@ -288,6 +309,9 @@ func (comp AddressComparator) CompareAddresses(one, two AddressType) int {
 // CompareAddressSections compares any two address sections (including from different versions or address types).
 // It returns a negative integer, zero, or a positive integer if address item one is less than, equal, or greater than address item two.
 func (comp AddressComparator) CompareAddressSections(one, two AddressSectionType) int {
+	if comp.reversed {
+		return comp.Reverse().CompareAddressSections(two, one)
+	}
 	oneIsNil, oneGroupingType := checkSectionType(one)
 	twoIsNil, twoGroupingType := checkSectionType(two)
 	if oneIsNil {
@@ -323,6 +347,9 @@ func unwrapWrapper(item AddressDivisionSeries) AddressDivisionSeries {
 // CompareSeries compares any two address division series (including from different versions or address types).
 // It returns a negative integer, zero, or a positive integer if address item one is less than, equal, or greater than address item two.
 func (comp AddressComparator) CompareSeries(one, two AddressDivisionSeries) int {
+	if comp.reversed {
+		return comp.Reverse().CompareSeries(two, one)
+	}
 	one = unwrapWrapper(one)
 	two = unwrapWrapper(two)
 	if addrSeries1, ok := one.(AddressType); ok {
@@ -363,6 +390,9 @@ func (comp AddressComparator) CompareSeries(one, two AddressDivisionSeries) int
 // CompareSegments compares any two address segments (including from different versions or address types).
 // It returns a negative integer, zero, or a positive integer if address item one is less than, equal, or greater than address item two.
 func (comp AddressComparator) CompareSegments(one, two AddressSegmentType) int {
+	if comp.reversed {
+		return comp.Reverse().CompareSegments(two, one)
+	}
 	oneIsNil, oneDivType := checkSegmentType(one)
 	twoIsNil, twoDivType := checkSegmentType(two)
 	// All nils are equivalent.  We decided that a nil interface should be equivalent to an interface with a nil value (standard or large)
@@ -389,6 +419,9 @@ func (comp AddressComparator) CompareSegments(one, two AddressSegmentType) int {
 // CompareDivisions compares any two address divisions (including from different versions or address types).
 // It returns a negative integer, zero, or a positive integer if address item one is less than, equal, or greater than address item two.
 func (comp AddressComparator) CompareDivisions(one, two DivisionType) int {
+	if comp.reversed {
+		return comp.Reverse().CompareDivisions(two, one)
+	}
 	if addrSeg1, ok := one.(AddressSegmentType); ok {
 		if addrSeg2, ok := two.(AddressSegmentType); ok {
 			return comp.CompareSegments(addrSeg1, addrSeg2)
@@ -424,6 +457,9 @@ func (comp AddressComparator) CompareDivisions(one, two DivisionType) int {
 // CompareRanges compares any two IP address sequential ranges (including from different IP versions).
 // It returns a negative integer, zero, or a positive integer if address item one is less than, equal, or greater than address item two.
 func (comp AddressComparator) CompareRanges(one, two IPAddressSeqRangeType) int {
+	if comp.reversed {
+		return comp.Reverse().CompareRanges(two, one)
+	}
 	oneIsNil, r1Type, r1 := checkRangeTypeX(one)
 	twoIsNil, r2Type, r2 := checkRangeTypeX(two)
 	if oneIsNil {
@@ -450,6 +486,9 @@ func (comp AddressComparator) CompareRanges(one, two IPAddressSeqRangeType) int
 // Compare returns a negative integer, zero, or a positive integer if address item one is less than, equal, or greater than address item two.
 // Any address item is comparable to any other.
 func (comp AddressComparator) Compare(one, two AddressItem) int {
+	if comp.reversed {
+		return comp.Reverse().Compare(two, one)
+	}
 	if one == nil {
 		if two == nil {
 			return 0
@@ -16,6 +16,12 @@
 
 package ipaddr
 
+import (
+	"sync/atomic"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
 // IPv6AddressConverter converts IP addresses to IPv6.
 type IPv6AddressConverter interface {
 	// ToIPv6 converts to IPv6.  If the given address is IPv6, or can be converted to IPv6, returns that IPv6Address.  Otherwise, returns nil.
@@ -53,10 +59,15 @@ type DefaultAddressConverter struct{}
 var _ IPAddressConverter = DefaultAddressConverter{}
 
 // ToIPv4 converts IPv4-mapped IPv6 addresses to IPv4, or returns the original address if IPv4 already, or returns nil if the address cannot be converted.
+//
+// Only IPv4-mapped addresses are converted, matching IsIPv4Convertible.
+// Earlier releases converted any IPv6 address to the IPv4 address of its lowest 32 bits, so that "1::1.2.3.4" became "1.2.3.4",
+// while IsIPv4Convertible returned false for that same address.
+// To obtain the IPv4 address of the lowest 32 bits of any IPv6 address, use IPv6Address.GetEmbeddedIPv4Address.
 func (converter DefaultAddressConverter) ToIPv4(address *IPAddress) *IPv4Address {
 	if addr := address.ToIPv4(); addr != nil {
 		return addr
-	} else if addr := address.ToIPv6(); addr != nil {
+	} else if addr := address.ToIPv6(); addr != nil && addr.IsIPv4Mapped() {
 		if ipv4Addr, err := addr.GetEmbeddedIPv4Address(); err == nil {
 			return ipv4Addr
 		}
@@ -98,3 +109,245 @@ func (converter DefaultAddressConverter) IsIPv6Convertible(address *IPAddress) b
 	}
 	return address.IsIPv6()
 }
+
+// SixToFourAddressConverter converts to/from 6to4 addresses, RFC 3056, which maps IPv4 "a.b.c.d" to/from the 6to4 site prefix block "2002:aabb:ccdd::/48".
+// Converting from IPv6 to IPv4 requires that the IPv6 address be within "2002::/16", and produces the IPv4 address of the 6to4 site,
+// regardless of the subnet and interface identifiers that follow the site prefix.
+type SixToFourAddressConverter struct{}
+
+var _ IPAddressConverter = SixToFourAddressConverter{}
+
+// ToIPv4 converts 6to4 IPv6 addresses to the IPv4 address of the 6to4 site, or returns the original address if IPv4 already, or returns nil if the address cannot be converted.
+func (converter SixToFourAddressConverter) ToIPv4(address *IPAddress) *IPv4Address {
+	if addr := address.ToIPv4(); addr != nil {
+		return addr
+	} else if addr := address.ToIPv6(); addr != nil && addr.Is6To4() {
+		if ipv4Addr, err := addr.Get6To4IPv4Address(); err == nil {
+			return ipv4Addr
+		}
+	}
+	return nil
+}
+
+// ToIPv6 converts to the 6to4 site prefix block, or returns the original address if IPv6 already.
+func (converter SixToFourAddressConverter) ToIPv6(address *IPAddress) *IPv6Address {
+	if addr := address.ToIPv6(); addr != nil {
+		return addr
+	} else if addr := address.ToIPv4(); addr != nil {
+		section := addr.GetSection().WithoutPrefixLen()
+		high, err := section.GetSegment(0).Join(section.GetSegment(1))
+		if err != nil {
+			return nil
+		}
+		low, err := section.GetSegment(2).Join(section.GetSegment(3))
+		if err != nil {
+			return nil
+		}
+		segs := make([]*IPv6AddressSegment, IPv6SegmentCount)
+		segs[0], segs[1], segs[2] = NewIPv6Segment(0x2002), high, low
+		for i := 3; i < IPv6SegmentCount; i++ {
+			segs[i] = zeroIPv6Seg
+		}
+		return newIPv6Address(NewIPv6Section(segs)).ToPrefixBlockLen(48)
+	}
+	return nil
+}
+
+// IsIPv4Convertible returns true if ToIPv4 returns non-nil.
+func (converter SixToFourAddressConverter) IsIPv4Convertible(address *IPAddress) bool {
+	return converter.ToIPv4(address) != nil
+}
+
+// IsIPv6Convertible returns true if ToIPv6 returns non-nil.
+func (converter SixToFourAddressConverter) IsIPv6Convertible(address *IPAddress) bool {
+	if addr := address.ToIPv4(); addr != nil {
+		return addr.GetSegment(0).isJoinableTo(addr.GetSegment(1)) && addr.GetSegment(2).isJoinableTo(addr.GetSegment(3))
+	}
+	return address.IsIPv6()
+}
+
+// NAT64AddressConverter converts to/from IPv4-embedded IPv6 addresses with a NAT64 prefix, as described in RFC 6052,
+// such as the well-known prefix "64:ff9b::/96", which maps IPv4 "a.b.c.d" to/from "64:ff9b::a.b.c.d".
+//
+// The prefix length of the NAT64 prefix is one of 32, 40, 48, 56, 64 or 96.
+// With a prefix length other than 96, the bits 64 to 71 of the IPv6 address, the "u" octet, are skipped when embedding the IPv4 address,
+// and only individual addresses can be converted.  With the prefix length 96, subnets can be converted as with DefaultAddressConverter.
+//
+// Construct a NAT64AddressConverter with NewNAT64AddressConverter.
+type NAT64AddressConverter struct {
+	prefix *IPv6Address
+}
+
+var _ IPAddressConverter = &NAT64AddressConverter{}
+
+// NewNAT64AddressConverter constructs a converter for the given NAT64 prefix, using the prefix length of the given address.
+// If the given prefix is nil, the well-known prefix "64:ff9b::/96" is used.
+//
+// An error is returned if the prefix length is not one of 32, 40, 48, 56, 64 or 96,
+// or if the given address has multiple prefix values for that prefix length.
+func NewNAT64AddressConverter(prefix *IPv6Address) (*NAT64AddressConverter, addrerr.AddressValueError) {
+	if prefix == nil {
+		prefix = wellKnownNAT64Prefix
+	}
+	prefLen := prefix.GetNetworkPrefixLen()
+	if prefLen == nil {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalidCIDRPrefix"}, val: int(prefix.GetBitCount())}
+	}
+	switch prefLen.bitCount() {
+	case 32, 40, 48, 56, 64, 96:
+	default:
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalidCIDRPrefix"}, val: int(prefLen.bitCount())}
+	}
+	if !prefix.IsSingleNetwork() {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalidCIDRPrefix"}, val: int(prefLen.bitCount())}
+	}
+	return &NAT64AddressConverter{prefix: prefix.ToPrefixBlock()}, nil
+}
+
+var wellKnownNAT64Prefix = NewIPv6AddressFromUint64(0x64ff9b<<32, 0).SetPrefixLen(96)
+
+// GetPrefix returns the NAT64 prefix block used by this converter.
+func (converter *NAT64AddressConverter) GetPrefix() *IPv6Address {
+	return converter.prefix
+}
+
+// nat64ByteIndices returns the indices of the bytes of the IPv6 address holding the embedded IPv4 bytes, skipping the "u" octet
+func (converter *NAT64AddressConverter) nat64ByteIndices() (indices [IPv4ByteCount]int) {
+	index := int(converter.prefix.GetNetworkPrefixLen().bitCount()) >> 3
+	for i := range indices {
+		if index == 8 {
+			index++
+		}
+		indices[i] = index
+		index++
+	}
+	return
+}
+
+// ToIPv4 converts IPv6 addresses within the NAT64 prefix to IPv4, or returns the original address if IPv4 already, or returns nil if the address cannot be converted.
+func (converter *NAT64AddressConverter) ToIPv4(address *IPAddress) *IPv4Address {
+	if addr := address.ToIPv4(); addr != nil {
+		return addr
+	} else if addr := address.ToIPv6(); addr != nil && converter.prefix.Contains(addr) {
+		if converter.prefix.GetNetworkPrefixLen().bitCount() == 96 {
+			if ipv4Addr, err := addr.GetEmbeddedIPv4Address(); err == nil {
+				return ipv4Addr
+			}
+		} else if !addr.IsMultiple() {
+			bytes := addr.Bytes()
+			if bytes[8] != 0 {
+				return nil
+			}
+			var ipv4Bytes [IPv4ByteCount]byte
+			for i, index := range converter.nat64ByteIndices() {
+				ipv4Bytes[i] = bytes[index]
+			}
+			ipv4Addr, _ := NewIPv4AddressFromBytes(ipv4Bytes[:])
+			return ipv4Addr
+		}
+	}
+	return nil
+}
+
+// ToIPv6 converts to an IPv4-embedded IPv6 address within the NAT64 prefix, or returns the original address if IPv6 already.
+func (converter *NAT64AddressConverter) ToIPv6(address *IPAddress) *IPv6Address {
+	if addr := address.ToIPv6(); addr != nil {
+		return addr
+	} else if addr := address.ToIPv4(); addr != nil {
+		if converter.prefix.GetNetworkPrefixLen().bitCount() == 96 {
+			if ipv6Addr, err := addr.getIPv6Address(converter.prefix.WithoutPrefixLen().getDivisionsInternal()); err == nil {
+				return ipv6Addr
+			}
+		} else if !addr.IsMultiple() {
+			bytes := converter.prefix.GetLower().Bytes()
+			ipv4Bytes := addr.Bytes()
+			for i, index := range converter.nat64ByteIndices() {
+				bytes[index] = ipv4Bytes[i]
+			}
+			ipv6Addr, _ := NewIPv6AddressFromBytes(bytes)
+			return ipv6Addr
+		}
+	}
+	return nil
+}
+
+// IsIPv4Convertible returns true if ToIPv4 returns non-nil.
+func (converter *NAT64AddressConverter) IsIPv4Convertible(address *IPAddress) bool {
+	return converter.ToIPv4(address) != nil
+}
+
+// IsIPv6Convertible returns true if ToIPv6 returns non-nil.
+func (converter *NAT64AddressConverter) IsIPv6Convertible(address *IPAddress) bool {
+	return converter.ToIPv6(address) != nil
+}
+
+// ChainedAddressConverter combines multiple converters, converting with the first converter in the chain able to convert a given address.
+// Order matters when converting from IPv4 to IPv6, since each converter maps IPv4 addresses to a different IPv6 address.
+type ChainedAddressConverter []IPAddressConverter
+
+var _ IPAddressConverter = ChainedAddressConverter{}
+
+// ToIPv4 converts to IPv4 using the first converter in the chain that can convert the address, or returns nil if none can.
+func (converter ChainedAddressConverter) ToIPv4(address *IPAddress) *IPv4Address {
+	for _, conv := range converter {
+		if addr := conv.ToIPv4(address); addr != nil {
+			return addr
+		}
+	}
+	return nil
+}
+
+// ToIPv6 converts to IPv6 using the first converter in the chain that can convert the address, or returns nil if none can.
+func (converter ChainedAddressConverter) ToIPv6(address *IPAddress) *IPv6Address {
+	for _, conv := range converter {
+		if addr := conv.ToIPv6(address); addr != nil {
+			return addr
+		}
+	}
+	return nil
+}
+
+// IsIPv4Convertible returns true if any converter in the chain can convert the address to IPv4.
+func (converter ChainedAddressConverter) IsIPv4Convertible(address *IPAddress) bool {
+	for _, conv := range converter {
+		if conv.IsIPv4Convertible(address) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIPv6Convertible returns true if any converter in the chain can convert the address to IPv6.
+func (converter ChainedAddressConverter) IsIPv6Convertible(address *IPAddress) bool {
+	for _, conv := range converter {
+		if conv.IsIPv6Convertible(address) {
+			return true
+		}
+	}
+	return false
+}
+
+type addressConverterHolder struct {
+	converter IPAddressConverter
+}
+
+var addressConverter atomic.Value
+
+// SetAddressConverter registers the converter used by the IPAddress methods that convert between IP versions,
+// ConvertToIPv4, ConvertToIPv6, IsIPv4Convertible and IsIPv6Convertible, and by the methods that compare addresses of different versions, ContainsConverted and EqualConverted.
+// Use ChainedAddressConverter to register multiple converters.
+// Passing nil restores the default, DefaultAddressConverter.
+//
+// It is intended to be called once, when the program is initialized.  Although calling it concurrently with the use of addresses is safe,
+// conversions performed concurrently may use either the previous or the new converter.
+func SetAddressConverter(converter IPAddressConverter) {
+	addressConverter.Store(addressConverterHolder{converter})
+}
+
+// GetAddressConverter returns the converter registered with SetAddressConverter, or DefaultAddressConverter if none was registered.
+func GetAddressConverter() IPAddressConverter {
+	if holder, ok := addressConverter.Load().(addressConverterHolder); ok && holder.converter != nil {
+		return holder.converter
+	}
+	return DefaultAddressConverter{}
+}
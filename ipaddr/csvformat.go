@@ -0,0 +1,62 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// TrieCSVFormatVersion is the version of the CSV format written by AssociativeTrie.WriteCSV.
+//
+// The format is stable: CSV written with any format version can be read by ReadCSV in this and all later releases.
+// Keys are written as canonical address strings, with prefix lengths for prefix blocks, strings which will continue to be parsed as the same addresses.
+//
+// Version 1 is the initial format, starting with the header record "#version,1", followed by the key and value records.
+// Use TrieCSVVersion to detect the version of persisted CSV.
+const TrieCSVFormatVersion = 1
+
+const trieCSVVersionField = "#version"
+
+func writeTrieCSVHeader(csvWriter *csv.Writer) error {
+	return csvWriter.Write([]string{trieCSVVersionField, strconv.Itoa(TrieCSVFormatVersion)})
+}
+
+// readTrieCSVHeader reads the header record, returning its format version.
+// An error is returned if the CSV cannot be read, if there is no header record, or if the version is invalid or later than TrieCSVFormatVersion.
+func readTrieCSVHeader(csvReader *csv.Reader) (version int, err error) {
+	record, err := csvReader.Read()
+	if err == io.EOF || (err == nil && record[0] != trieCSVVersionField) {
+		return 0, errorF("missing trie CSV format version header")
+	} else if err != nil {
+		return 0, err
+	} else if version, err = strconv.Atoi(record[1]); err != nil || version < 1 || version > TrieCSVFormatVersion {
+		return 0, errorF("unsupported trie CSV format version %s", record[1])
+	}
+	return
+}
+
+// TrieCSVVersion returns the format version of the given CSV written by AssociativeTrie.WriteCSV.
+// An error is returned if the CSV cannot be read, if it has no header record, or if it has a version later than TrieCSVFormatVersion.
+func TrieCSVVersion(data []byte) (int, error) {
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	csvReader.FieldsPerRecord = 2
+	return readTrieCSVHeader(csvReader)
+}
+
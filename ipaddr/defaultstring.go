@@ -0,0 +1,135 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import "sync/atomic"
+
+// StringFormat selects the string produced by String, and by Format with the 'v', 's' and 'q' verbs,
+// for IP addresses, IP address sections, and sequential ranges of IP addresses.
+type StringFormat int32
+
+const (
+	// DefaultStringFormat is the canonical string for IP addresses and the normalized string for IP address sections.
+	DefaultStringFormat StringFormat = iota
+
+	// CanonicalStringFormat is the string produced by ToCanonicalString.
+	CanonicalStringFormat
+
+	// NormalizedStringFormat is the string produced by ToNormalizedString.
+	NormalizedStringFormat
+
+	// NormalizedWildcardStringFormat is the string produced by ToNormalizedWildcardString.
+	NormalizedWildcardStringFormat
+
+	// FullStringFormat is the string produced by ToFullString.
+	FullStringFormat
+)
+
+// String returns a description of the string format.
+func (format StringFormat) String() string {
+	switch format {
+	case DefaultStringFormat:
+		return "default"
+	case CanonicalStringFormat:
+		return "canonical"
+	case NormalizedStringFormat:
+		return "normalized"
+	case NormalizedWildcardStringFormat:
+		return "normalized wildcard"
+	case FullStringFormat:
+		return "full"
+	}
+	return "unknown"
+}
+
+var defaultStringFormat int32
+
+// SetDefaultStringFormat selects the string produced by String, and by Format with the 'v', 's' and 'q' verbs,
+// for all IP addresses, IP address sections, and sequential ranges of IP addresses, so that logging and other output is consistent
+// without wrapping the address types.  MAC addresses and sections are not affected.
+//
+// It is intended to be called once, when the program is initialized.  Although calling it concurrently with the use of addresses is safe,
+// strings produced concurrently may be in either the previous or the new format.
+// An unknown format is treated as DefaultStringFormat.
+func SetDefaultStringFormat(format StringFormat) {
+	atomic.StoreInt32(&defaultStringFormat, int32(format))
+}
+
+// GetDefaultStringFormat returns the string format selected with SetDefaultStringFormat.
+func GetDefaultStringFormat() StringFormat {
+	return StringFormat(atomic.LoadInt32(&defaultStringFormat))
+}
+
+// toDefaultFormatString produces the string for String and the string verbs of Format, which is the canonical or normalized string
+// unless another format has been selected for IP addresses and sections with SetDefaultStringFormat
+func (section *addressSectionInternal) toDefaultFormatString(zone Zone, useCanonical bool) string {
+	if sect := section.toIPv6AddressSection(); sect != nil {
+		if zone != NoZone {
+			switch GetDefaultStringFormat() {
+			case NormalizedWildcardStringFormat:
+				return sect.toNormalizedWildcardStringZoned(zone)
+			case FullStringFormat:
+				return sect.toFullStringZoned(zone)
+			case CanonicalStringFormat:
+				return sect.toCanonicalString(zone)
+			case NormalizedStringFormat:
+				return sect.toNormalizedString(zone)
+			}
+			if useCanonical {
+				return sect.toCanonicalString(zone)
+			}
+			return sect.toNormalizedString(zone)
+		}
+		return sect.toDefaultIPFormatString(useCanonical)
+	} else if sect := section.toIPv4AddressSection(); sect != nil {
+		return sect.toDefaultIPFormatString(useCanonical)
+	} else if useCanonical {
+		return section.toCanonicalString()
+	}
+	return section.toNormalizedString()
+}
+
+func (section *ipAddressSectionInternal) toDefaultIPFormatString(useCanonical bool) string {
+	switch GetDefaultStringFormat() {
+	case NormalizedWildcardStringFormat:
+		return section.toNormalizedWildcardString()
+	case FullStringFormat:
+		return section.toFullString()
+	case CanonicalStringFormat:
+		useCanonical = true
+	case NormalizedStringFormat:
+		useCanonical = false
+	}
+	if useCanonical {
+		return section.toCanonicalString()
+	}
+	return section.toNormalizedString()
+}
+
+// toDefaultFormatString produces the string for String for IP addresses, which is the canonical string
+// unless another format has been selected with SetDefaultStringFormat
+func (addr *addressInternal) toDefaultFormatString() string {
+	switch GetDefaultStringFormat() {
+	case NormalizedStringFormat:
+		return addr.toNormalizedString()
+	case NormalizedWildcardStringFormat:
+		return addr.toNormalizedWildcardString()
+	case FullStringFormat:
+		return addr.toAddress().ToIP().toFullString()
+	}
+	return addr.toCanonicalString()
+}
@@ -0,0 +1,359 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	crand "crypto/rand"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// DelegationOrder determines which free prefix a DelegationPool delegates next.
+type DelegationOrder int
+
+const (
+	// SequentialDelegation delegates the lowest free prefix.
+	SequentialDelegation DelegationOrder = iota
+
+	// RandomDelegation delegates a free prefix chosen at random, so that delegated prefixes cannot be predicted from one another.
+	RandomDelegation
+)
+
+// DelegationPoolOptions controls how a DelegationPool delegates prefixes.  The zero value delegates /64 prefixes in sequential order.
+type DelegationPoolOptions struct {
+	// PrefixLen is the prefix length of the delegated prefixes, commonly 56 or 64.  Zero selects 64.
+	PrefixLen BitCount
+
+	// Order selects sequential or random delegation.
+	Order DelegationOrder
+
+	// Random is the source of randomness for RandomDelegation.  If nil, crypto/rand is used.
+	Random io.Reader
+}
+
+// DelegationLease is a prefix delegated from a DelegationPool, along with the identifier of the lease.
+type DelegationLease struct {
+	// ID is the identifier of the lease, such as the DUID and IAID of a DHCPv6 client.
+	ID string
+
+	// Prefix is the delegated prefix block.
+	Prefix *IPv6Address
+}
+
+// String returns the lease identifier and the delegated prefix, such as "client1 2001:db8:0:1::/64".
+func (lease DelegationLease) String() string {
+	return lease.ID + " " + lease.Prefix.String()
+}
+
+// DelegationPoolSnapshot is the state of a DelegationPool, for persisting the pool and restoring it with Restore.
+// The prefixes are held as strings, so that the snapshot can be written with encoding/json, encoding/gob, or any other encoding.
+type DelegationPoolSnapshot struct {
+	// Parent is the canonical string of the parent prefix of the pool.
+	Parent string
+
+	// PrefixLen is the prefix length of the delegated prefixes.
+	PrefixLen BitCount
+
+	// Leases maps each lease identifier to the canonical string of its delegated prefix.
+	Leases map[string]string
+}
+
+// NewDelegationPool constructs a pool that delegates prefixes from the given parent prefix block, such as "2001:db8::/48".
+//
+// An error is returned if the parent is nil or is not a single prefix block,
+// or if the delegated prefix length is shorter than the prefix length of the parent or exceeds the IPv6 bit count.
+func NewDelegationPool(parent *IPv6Address, options DelegationPoolOptions) (*DelegationPool, addrerr.AddressError) {
+	if parent == nil || !parent.IsSinglePrefixBlock() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.not.block"}}
+	}
+	if options.PrefixLen == 0 {
+		options.PrefixLen = IPv6BitCount >> 1
+	}
+	parent = parent.WithoutZone()
+	if prefLen := options.PrefixLen; prefLen < parent.GetPrefixLen().Len() || prefLen > IPv6BitCount {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixSize"}, val: int(prefLen)}
+	}
+	if options.Random == nil {
+		options.Random = crand.Reader
+	}
+	pool := &DelegationPool{
+		parent:  parent,
+		options: options,
+		leases:  make(map[string]*IPv6Address),
+	}
+	pool.space.AddToPool(parent)
+	pool.free = pool.newFreeLists(&pool.space)
+	return pool, nil
+}
+
+// DelegationPool delegates IPv6 prefixes of a fixed length, such as /56 or /64 prefixes, from a parent prefix, such as a /48,
+// as with DHCPv6 prefix delegation.
+//
+// Each delegation is a lease with an identifier chosen by the caller, such as the DUID and IAID of a DHCPv6 client.
+// Delegating to an identifier that already holds a lease returns the prefix already delegated,
+// and the prefix returns to the pool when the lease is released.
+// The pool tracks the delegated space with an AddressPool, which can report on the utilization of the parent prefix.
+//
+// The state of the pool can be persisted with Snapshot and recovered with Restore.
+//
+// The zero value is not usable, use NewDelegationPool to create one.
+// A DelegationPool is not safe for concurrent use by multiple goroutines if any goroutine is modifying it.
+type DelegationPool struct {
+	parent  *IPv6Address
+	options DelegationPoolOptions
+	space   IPv6Pool
+	leases  map[string]*IPv6Address
+
+	// free holds the free prefix blocks, indexed by the prefix length of the blocks less the prefix length of the parent,
+	// so that delegating and releasing a prefix does not require going through the whole of the delegated space.
+	// Each list is sorted in descending order, so that the lowest free block of each length is last.
+	free [][]*IPv6Address
+}
+
+// GetParent returns the parent prefix block from which prefixes are delegated.
+func (pool *DelegationPool) GetParent() *IPv6Address {
+	return pool.parent
+}
+
+// GetPrefixLen returns the prefix length of the delegated prefixes.
+func (pool *DelegationPool) GetPrefixLen() BitCount {
+	return pool.options.PrefixLen
+}
+
+// Delegate delegates a free prefix for the lease with the given identifier, returning the delegated prefix block.
+// If the identifier already holds a lease, the prefix of that lease is returned.
+// If there is no free prefix, nil is returned.
+func (pool *DelegationPool) Delegate(id string) *IPv6Address {
+	if prefix, ok := pool.leases[id]; ok {
+		return prefix
+	}
+	var block *IPv6Address
+	offset := bigZero()
+	if pool.options.Order == RandomDelegation {
+		block, offset = pool.chooseRandom()
+	} else {
+		block = pool.getLowestFree()
+	}
+	if block == nil {
+		return nil
+	}
+	prefLen := pool.options.PrefixLen
+	hostBits := uint(IPv6BitCount - prefLen)
+	prefix := block.GetLower().WithoutPrefixLen().IncrementBig(offset.Lsh(offset, hostBits)).ToPrefixBlockLen(prefLen)
+	if !pool.space.Allocate(prefix) {
+		return nil
+	}
+	pool.removeFree(block)
+	// the remainder of the block is the sibling of the prefix and the sibling of each block containing the prefix within the block
+	for blockLen := getPoolBlockPrefixLen(block); prefLen > blockLen; prefLen-- {
+		pool.insertFree(getDelegationSibling(prefix.ToPrefixBlockLen(prefLen)))
+	}
+	pool.leases[id] = prefix
+	return prefix
+}
+
+// getLowestFree returns the lowest free block, or nil if there is none
+func (pool *DelegationPool) getLowestFree() (lowest *IPv6Address) {
+	for _, blocks := range pool.free {
+		if len(blocks) > 0 {
+			if block := blocks[len(blocks)-1]; lowest == nil || block.GetLower().Compare(lowest.GetLower()) < 0 {
+				lowest = block
+			}
+		}
+	}
+	return
+}
+
+// chooseRandom chooses a free prefix uniformly from the free blocks, returning the block holding the prefix and the index of the prefix within the block
+func (pool *DelegationPool) chooseRandom() (*IPv6Address, *big.Int) {
+	total := bigZero()
+	for i, blocks := range pool.free {
+		count := pool.getPrefixCount(i)
+		total.Add(total, count.Mul(count, big.NewInt(int64(len(blocks)))))
+	}
+	if total.Sign() == 0 {
+		return nil, bigZero()
+	}
+	index, err := crand.Int(pool.options.Random, total)
+	if err != nil {
+		// the source of randomness failed, fall back to the lowest free prefix
+		return pool.getLowestFree(), bigZero()
+	}
+	for i, blocks := range pool.free {
+		count := pool.getPrefixCount(i)
+		blocksCount := new(big.Int).Mul(count, big.NewInt(int64(len(blocks))))
+		if index.Cmp(blocksCount) < 0 {
+			blockIndex, offset := index.QuoRem(index, count, new(big.Int))
+			return blocks[blockIndex.Int64()], offset
+		}
+		index.Sub(index, blocksCount)
+	}
+	return pool.getLowestFree(), bigZero()
+}
+
+// getPrefixCount returns the number of delegated prefixes in a free block of the given free list
+func (pool *DelegationPool) getPrefixCount(freeIndex int) *big.Int {
+	bits := HostBitCount(pool.options.PrefixLen - pool.parent.GetPrefixLen().Len() - freeIndex)
+	return bits.BlockSize()
+}
+
+// newFreeLists returns the free lists of the given space
+func (pool *DelegationPool) newFreeLists(space *IPv6Pool) [][]*IPv6Address {
+	parentLen, prefLen := pool.parent.GetPrefixLen().Len(), pool.options.PrefixLen
+	free := make([][]*IPv6Address, prefLen-parentLen+1)
+	blocks := space.GetFreeBlocks()
+	// the free blocks are in ascending order, so add them in reverse
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blockLen := getPoolBlockPrefixLen(blocks[i]); blockLen <= prefLen {
+			free[blockLen-parentLen] = append(free[blockLen-parentLen], blocks[i])
+		}
+	}
+	return free
+}
+
+// findFree returns the free list of the given block, along with the index of the block in the list,
+// or the index at which it would be inserted if it is not free
+func (pool *DelegationPool) findFree(block *IPv6Address) (freeIndex, index int, found bool) {
+	freeIndex = getPoolBlockPrefixLen(block) - pool.parent.GetPrefixLen().Len()
+	blocks := pool.free[freeIndex]
+	index = sort.Search(len(blocks), func(i int) bool {
+		return blocks[i].Compare(block) <= 0
+	})
+	found = index < len(blocks) && blocks[index].Equal(block)
+	return
+}
+
+// insertFree adds the given block to its free list
+func (pool *DelegationPool) insertFree(block *IPv6Address) {
+	freeIndex, index, _ := pool.findFree(block)
+	blocks := append(pool.free[freeIndex], nil)
+	copy(blocks[index+1:], blocks[index:])
+	blocks[index] = block
+	pool.free[freeIndex] = blocks
+}
+
+// removeFree removes the given block from its free list, returning false if it is not free
+func (pool *DelegationPool) removeFree(block *IPv6Address) bool {
+	freeIndex, index, found := pool.findFree(block)
+	if found {
+		blocks := pool.free[freeIndex]
+		pool.free[freeIndex] = append(blocks[:index], blocks[index+1:]...)
+	}
+	return found
+}
+
+// getDelegationSibling returns the prefix block that forms the prefix block one bit shorter when joined with the given prefix block
+func getDelegationSibling(block *IPv6Address) *IPv6Address {
+	prefLen := block.GetPrefixLen().Len()
+	hostBits := HostBitCount(IPv6BitCount - prefLen)
+	size := hostBits.BlockSize()
+	if block.IsOneBit(prefLen - 1) {
+		size.Neg(size)
+	}
+	return block.GetLower().WithoutPrefixLen().IncrementBig(size).ToPrefixBlockLen(prefLen)
+}
+
+// Release releases the lease with the given identifier, returning its prefix to the pool.
+// It returns true if the identifier held a lease.
+func (pool *DelegationPool) Release(id string) bool {
+	prefix, ok := pool.leases[id]
+	if ok {
+		delete(pool.leases, id)
+		pool.space.Release(prefix)
+		// join the prefix with each free sibling to restore the largest free block containing it
+		block, parentLen := prefix, pool.parent.GetPrefixLen().Len()
+		for prefLen := block.GetPrefixLen().Len(); prefLen > parentLen && pool.removeFree(getDelegationSibling(block)); prefLen-- {
+			block = block.ToPrefixBlockLen(prefLen - 1)
+		}
+		pool.insertFree(block)
+	}
+	return ok
+}
+
+// GetLease returns the prefix delegated to the lease with the given identifier, or nil if the identifier holds no lease.
+func (pool *DelegationPool) GetLease(id string) *IPv6Address {
+	return pool.leases[id]
+}
+
+// GetLeases returns the leases of the pool, sorted by prefix.
+func (pool *DelegationPool) GetLeases() []DelegationLease {
+	leases := make([]DelegationLease, 0, len(pool.leases))
+	for id, prefix := range pool.leases {
+		leases = append(leases, DelegationLease{ID: id, Prefix: prefix})
+	}
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].Prefix.Compare(leases[j].Prefix) < 0
+	})
+	return leases
+}
+
+// GetLeaseCount returns the number of leases in the pool.
+func (pool *DelegationPool) GetLeaseCount() int {
+	return len(pool.leases)
+}
+
+// GetSpace returns the AddressPool tracking the delegated prefixes of the parent prefix, for reporting on the free and delegated space.
+// The returned pool must not be modified.
+func (pool *DelegationPool) GetSpace() *IPv6Pool {
+	return &pool.space
+}
+
+// Snapshot returns the state of the pool, for persisting the pool.
+func (pool *DelegationPool) Snapshot() DelegationPoolSnapshot {
+	leases := make(map[string]string, len(pool.leases))
+	for id, prefix := range pool.leases {
+		leases[id] = prefix.ToCanonicalString()
+	}
+	return DelegationPoolSnapshot{
+		Parent:    pool.parent.ToCanonicalString(),
+		PrefixLen: pool.options.PrefixLen,
+		Leases:    leases,
+	}
+}
+
+// Restore replaces the leases of the pool with those of the given snapshot, as provided by Snapshot.
+//
+// An error is returned, and the pool is left unchanged, if the parent prefix and delegated prefix length of the snapshot do not match those of the pool,
+// or if any lease prefix is not a prefix block of the delegated prefix length within the parent prefix, or overlaps another lease.
+func (pool *DelegationPool) Restore(snapshot DelegationPoolSnapshot) error {
+	if parent, err := NewIPAddressString(snapshot.Parent).ToAddress(); err != nil {
+		return err
+	} else if !parent.Equal(pool.parent) {
+		return wrapErrf(&incompatibleAddressError{addressError{key: "ipaddress.error.address.out.of.range"}}, "parent %s", snapshot.Parent)
+	} else if snapshot.PrefixLen != pool.options.PrefixLen {
+		return &addressValueError{addressError: addressError{key: "ipaddress.error.prefixSize"}, val: int(snapshot.PrefixLen)}
+	}
+	var space IPv6Pool
+	space.AddToPool(pool.parent)
+	leases := make(map[string]*IPv6Address, len(snapshot.Leases))
+	for id, str := range snapshot.Leases {
+		addr, err := NewIPAddressString(str).ToAddress()
+		if err != nil {
+			return wrapErrf(err, "lease %s", id)
+		}
+		prefix := addr.ToIPv6()
+		if prefix == nil || !prefix.IsSinglePrefixBlock() || prefix.GetPrefixLen().Len() != pool.options.PrefixLen || !space.Allocate(prefix) {
+			return wrapErrf(&incompatibleAddressError{addressError{key: "ipaddress.error.address.out.of.range"}}, "lease %s prefix %s", id, str)
+		}
+		leases[id] = prefix
+	}
+	pool.space, pool.leases, pool.free = space, leases, pool.newFreeLists(&space)
+	return nil
+}
@@ -0,0 +1,107 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// ParseDiagnostic is a structured description of a reason that a string is invalid, for use by editor tooling and validators,
+// giving the location of the problem along with a code and message.
+type ParseDiagnostic struct {
+	// Key is the code identifying the reason, the key of the corresponding error.
+	// The keys and their English text are listed in IPAddressResources.properties, allowing for translation.
+	Key string
+
+	// Message is the English text describing the reason.
+	Message string
+
+	// Input is the string in which the problem was found.
+	// For a host name containing an address, the input of an address diagnostic may be the address portion of the host name.
+	Input string
+
+	// Index is the byte offset within Input of the offending character or component, or -1 if the problem is not at a specific location.
+	Index int
+}
+
+// String returns the message of the diagnostic, followed by the index if the problem is at a specific location.
+func (diagnostic ParseDiagnostic) String() string {
+	if diagnostic.Index >= 0 {
+		return diagnostic.Message + " at index " + strconv.Itoa(diagnostic.Index)
+	}
+	return diagnostic.Message
+}
+
+// Diagnose returns the diagnostics for an error returned by this library, one for each error in the chain of wrapped errors, from outermost to innermost.
+// When a host name could not be resolved, the diagnostics for each of the resolution failures follow.
+// It returns nil if the error is nil.
+func Diagnose(err error) (result []ParseDiagnostic) {
+	var mergedErrs []addrerr.AddressError
+	for ; err != nil; err = errors.Unwrap(err) {
+		if merged, ok := err.(interface{ GetMerged() []addrerr.AddressError }); ok {
+			// the primary error is unwrapped next
+			mergedErrs = append(mergedErrs, merged.GetMerged()...)
+		} else if addrErr, ok := err.(addrerr.AddressError); ok && addrErr.GetKey() != "" {
+			diagnostic := ParseDiagnostic{
+				Key:     addrErr.GetKey(),
+				Message: lookupStr(addrErr.GetKey()),
+				Index:   -1,
+			}
+			if inputErr, ok := err.(addrerr.InputError); ok {
+				diagnostic.Input = inputErr.GetInput()
+			}
+			if indexErr, ok := err.(addrerr.IndexError); ok {
+				diagnostic.Index = indexErr.GetIndex()
+			}
+			result = append(result, diagnostic)
+		}
+	}
+	for _, mergedErr := range mergedErrs {
+		result = append(result, Diagnose(mergedErr)...)
+	}
+	return
+}
+
+// Diagnose returns the diagnostics describing why this string is not a valid IP address string, or nil if it is valid.
+// Each diagnostic provides the code, location and message for the problem.
+func (addrStr *IPAddressString) Diagnose() []ParseDiagnostic {
+	if err := addrStr.Validate(); err != nil {
+		return Diagnose(err)
+	}
+	return nil
+}
+
+// Diagnose returns the diagnostics describing why this string is not a valid MAC address string, or nil if it is valid.
+// Each diagnostic provides the code, location and message for the problem.
+func (addrStr *MACAddressString) Diagnose() []ParseDiagnostic {
+	if err := addrStr.Validate(); err != nil {
+		return Diagnose(err)
+	}
+	return nil
+}
+
+// Diagnose returns the diagnostics describing why this string is not a valid host name, or nil if it is valid.
+// Each diagnostic provides the code, location and message for the problem.
+func (host *HostName) Diagnose() []ParseDiagnostic {
+	if err := host.Validate(); err != nil {
+		return Diagnose(err)
+	}
+	return nil
+}
@@ -1198,7 +1198,7 @@ func divValSame(oneVal, twoVal DivInt) bool {
 
 func cacheStrPtr(cachedString **string, strPtr *string) {
 	cachedVal := (*string)(atomicLoadPointer((*unsafe.Pointer)(unsafe.Pointer(cachedString))))
-	if cachedVal == nil {
+	if cachedVal == nil && IsStringCaching() {
 		dataLoc := (*unsafe.Pointer)(unsafe.Pointer(cachedString))
 		atomicStorePointer(dataLoc, unsafe.Pointer(strPtr))
 	}
@@ -1209,8 +1209,10 @@ func cacheStr(cachedString **string, stringer func() string) (str string) {
 	cachedVal := (*string)(atomicLoadPointer((*unsafe.Pointer)(unsafe.Pointer(cachedString))))
 	if cachedVal == nil {
 		str = stringer()
-		dataLoc := (*unsafe.Pointer)(unsafe.Pointer(cachedString))
-		atomicStorePointer(dataLoc, unsafe.Pointer(&str))
+		if IsStringCaching() {
+			dataLoc := (*unsafe.Pointer)(unsafe.Pointer(cachedString))
+			atomicStorePointer(dataLoc, unsafe.Pointer(&str))
+		}
 	} else {
 		str = *cachedVal
 	}
@@ -1221,7 +1223,7 @@ func cacheStrErr(cachedString **string, stringer func() (string, addrerr.Incompa
 	cachedVal := (*string)(atomicLoadPointer((*unsafe.Pointer)(unsafe.Pointer(cachedString))))
 	if cachedVal == nil {
 		str, err = stringer()
-		if err == nil {
+		if err == nil && IsStringCaching() {
 			dataLoc := (*unsafe.Pointer)(unsafe.Pointer(cachedString))
 			atomicStorePointer(dataLoc, unsafe.Pointer(&str))
 		}
@@ -55,6 +55,15 @@ func (a *addressError) GetKey() string {
 	return a.key
 }
 
+// Is returns whether the given target is a sentinel error in the addrerr package, such as addrerr.ErrInvalidPrefixLen, that matches the key of this error.
+// It allows this error to be matched with errors.Is.
+func (a *addressError) Is(target error) bool {
+	if sentinel, ok := target.(*addrerr.SentinelError); ok {
+		return sentinel.MatchesKey(a.key)
+	}
+	return false
+}
+
 type mergedError struct {
 	addrerr.AddressError
 	merged []addrerr.AddressError
@@ -64,10 +73,20 @@ func (a *mergedError) GetMerged() []addrerr.AddressError {
 	return a.merged
 }
 
+// Unwrap returns the primary error
+func (a *mergedError) Unwrap() error {
+	return a.AddressError
+}
+
 type addressStringError struct {
 	addressError
 }
 
+// GetInput returns the address string that could not be parsed
+func (a *addressStringError) GetInput() string {
+	return a.str
+}
+
 type addressStringNestedError struct {
 	addressStringError
 	nested addrerr.AddressStringError
@@ -77,6 +96,11 @@ func (a *addressStringNestedError) Error() string {
 	return a.addressError.Error() + ": " + a.nested.Error()
 }
 
+// Unwrap returns the nested error
+func (a *addressStringNestedError) Unwrap() error {
+	return a.nested
+}
+
 type addressStringIndexError struct {
 	addressStringError
 
@@ -88,6 +112,11 @@ func (a *addressStringIndexError) Error() string {
 	return lookupStr("ipaddress.address.error") + " " + lookupStr(a.key) + " " + strconv.Itoa(a.index)
 }
 
+// GetIndex returns the byte index of the error location in the address string
+func (a *addressStringIndexError) GetIndex() int {
+	return a.index
+}
+
 type hostNameError struct {
 	addressError
 }
@@ -101,11 +130,21 @@ func (a *hostNameError) Error() string {
 	return getStr(a.str) + lookupStr("ipaddress.host.error") + " " + lookupStr(a.key)
 }
 
+// GetInput returns the host string that could not be parsed
+func (a *hostNameError) GetInput() string {
+	return a.str
+}
+
 type hostNameNestedError struct {
 	hostNameError
 	nested error
 }
 
+// Unwrap returns the nested error
+func (a *hostNameNestedError) Unwrap() error {
+	return a.nested
+}
+
 type hostAddressNestedError struct {
 	hostNameIndexError
 	nested addrerr.AddressError
@@ -116,6 +155,11 @@ func (a *hostAddressNestedError) GetAddrError() addrerr.AddressError {
 	return a.nested
 }
 
+// Unwrap returns the nested address error
+func (a *hostAddressNestedError) Unwrap() error {
+	return a.nested
+}
+
 func (a *hostAddressNestedError) Error() string {
 	if a.hostNameIndexError.key != "" {
 		return getStr(a.str) + lookupStr("ipaddress.host.error") + " " + a.hostNameIndexError.Error() + " " + a.nested.Error()
@@ -134,6 +178,11 @@ func (a *hostNameIndexError) Error() string {
 	return getStr(a.str) + lookupStr("ipaddress.host.error") + " " + lookupStr(a.key) + " " + strconv.Itoa(a.index)
 }
 
+// GetIndex returns the byte index of the error location in the host string
+func (a *hostNameIndexError) GetIndex() int {
+	return a.index
+}
+
 type incompatibleAddressError struct {
 	addressError
 }
@@ -149,6 +198,19 @@ type addressValueError struct {
 	val int
 }
 
+// GetValue returns the invalid value
+func (a *addressValueError) GetValue() int {
+	return a.val
+}
+
+var (
+	_ addrerr.InputError = &addressStringError{}
+	_ addrerr.IndexError = &addressStringIndexError{}
+	_ addrerr.InputError = &hostNameError{}
+	_ addrerr.IndexError = &hostNameIndexError{}
+	_ addrerr.ValueError = &addressValueError{}
+)
+
 ///////////////////////////////////////////////
 
 type wrappedErr struct {
@@ -171,6 +233,11 @@ func (wrappedErr *wrappedErr) Error() string {
 	return str
 }
 
+// Unwrap returns the root cause
+func (wrappedErr *wrappedErr) Unwrap() error {
+	return wrappedErr.cause
+}
+
 func newError(str string) error {
 	return errors.New(str)
 }
@@ -0,0 +1,285 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// AddressFamily is a custom fixed-length address family, such as 64-bit locators or 160-bit overlay addresses,
+// defined by its bit length, the bit length of its segments, the separator between segments and the radix of segment strings.
+//
+// The addresses and subnets of a family are represented by AddressDivisionGrouping instances with equal-length divisions,
+// created with NewGrouping or Parse.  The family provides formatting, containment and prefix block operations for those groupings.
+//
+// Segments have at most 64 bits, the bit length of a DivInt.
+// Addresses have at most 255 bits, since prefix lengths, like all prefix lengths in this library, are limited to 255 bits.
+type AddressFamily struct {
+	bitCount, bitsPerSegment BitCount
+	separator                byte
+	radix                    int
+}
+
+// NewAddressFamily creates an address family whose addresses have the given bit length, divided into segments of the given bit length,
+// each written in the given radix and separated by the given separator.
+//
+// An error is returned if the bit length is not a positive multiple of the segment bit length, if the bit length exceeds 255, if the segment bit length exceeds 64,
+// if the radix is not between 2 and 36, or if the separator is a digit of the radix or one of the range, wildcard or prefix length characters '-', '*' and '/'.
+func NewAddressFamily(bitCount, bitsPerSegment BitCount, separator byte, radix int) (*AddressFamily, addrerr.AddressValueError) {
+	if bitsPerSegment <= 0 || bitsPerSegment > 64 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.family"}, val: bitsPerSegment}
+	} else if bitCount <= 0 || bitCount > 255 || bitCount%bitsPerSegment != 0 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.family"}, val: bitCount}
+	} else if radix < 2 || radix > 36 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.family"}, val: radix}
+	} else if _, err := strconv.ParseUint(string(separator), radix, 64); err == nil || separator == '-' || separator == '*' || separator == '/' {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.invalid.family"}, val: int(separator)}
+	}
+	return &AddressFamily{
+		bitCount:       bitCount,
+		bitsPerSegment: bitsPerSegment,
+		separator:      separator,
+		radix:          radix,
+	}, nil
+}
+
+// GetBitCount returns the number of bits in each address of the family.
+func (family *AddressFamily) GetBitCount() BitCount {
+	return family.bitCount
+}
+
+// GetBitsPerSegment returns the number of bits in each segment of the family's addresses.
+func (family *AddressFamily) GetBitsPerSegment() BitCount {
+	return family.bitsPerSegment
+}
+
+// GetSegmentCount returns the number of segments in each address of the family.
+func (family *AddressFamily) GetSegmentCount() int {
+	return int(family.bitCount / family.bitsPerSegment)
+}
+
+// GetSeparator returns the separator between segments in the family's address strings.
+func (family *AddressFamily) GetSeparator() byte {
+	return family.separator
+}
+
+// GetRadix returns the radix of the segment values in the family's address strings.
+func (family *AddressFamily) GetRadix() int {
+	return family.radix
+}
+
+// GetMaxSegmentValue returns the maximum possible segment value of the family's addresses.
+func (family *AddressFamily) GetMaxSegmentValue() DivInt {
+	return ^DivInt(0) >> uint(64-family.bitsPerSegment)
+}
+
+// IsMember returns whether the given grouping has the segment count and segment bit length of the family.
+func (family *AddressFamily) IsMember(grouping *AddressDivisionGrouping) bool {
+	if grouping == nil || grouping.GetDivisionCount() != family.GetSegmentCount() {
+		return false
+	}
+	for i := range grouping.getDivArray() {
+		if grouping.GetDivision(i).GetBitCount() != family.bitsPerSegment {
+			return false
+		}
+	}
+	return true
+}
+
+// NewGrouping creates an address or subnet of the family from the given lower and upper segment values, and the given prefix length.
+// If upperValues is nil, the lower values are used for the upper values as well, creating an individual address.
+// Values exceeding the segment bit length are truncated, and a prefix length exceeding the family bit length is adjusted to the bit length.
+func (family *AddressFamily) NewGrouping(lowerValues, upperValues func(segmentIndex int) DivInt, prefixLen PrefixLen) *AddressDivisionGrouping {
+	if upperValues == nil {
+		upperValues = lowerValues
+	}
+	if prefixLen != nil {
+		prefixLen = cacheBitCount(checkBitCount(prefixLen.bitCount(), family.bitCount))
+	}
+	segCount := family.GetSegmentCount()
+	divs := make([]*AddressDivision, segCount)
+	for i := 0; i < segCount; i++ {
+		var segPrefixLen PrefixLen
+		if prefixLen != nil {
+			segPrefixLen = getPrefixedSegmentPrefixLength(family.bitsPerSegment, prefixLen.bitCount(), i)
+		}
+		divs[i] = NewRangePrefixDivision(lowerValues(i), upperValues(i), segPrefixLen, family.bitsPerSegment)
+	}
+	return NewDivisionGrouping(divs)
+}
+
+// Parse parses the given string as an address or subnet of the family.
+//
+// Each segment is a value in the radix of the family, a range of two values separated by '-', or the wildcard '*' matching all values.
+// The segments may be followed by '/' and a decimal prefix length.
+func (family *AddressFamily) Parse(str string) (*AddressDivisionGrouping, addrerr.AddressStringError) {
+	if len(str) == 0 {
+		return nil, &addressStringError{addressError{str: str, key: "ipaddress.error.empty"}}
+	}
+	addrStr := str
+	var prefixLen PrefixLen
+	if index := strings.IndexByte(str, '/'); index >= 0 {
+		prefLen, err := strconv.Atoi(str[index+1:])
+		if err != nil || prefLen < 0 || prefLen > int(family.bitCount) {
+			return nil, &addressStringError{addressError{str: str, key: "ipaddress.error.prefixSize"}}
+		}
+		addrStr, prefixLen = str[:index], cacheBitCount(BitCount(prefLen))
+	}
+	segStrs := strings.Split(addrStr, string(family.separator))
+	if segCount := family.GetSegmentCount(); len(segStrs) < segCount {
+		return nil, &addressStringError{addressError{str: str, key: "ipaddress.error.too.few.segments"}}
+	} else if len(segStrs) > segCount {
+		return nil, &addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}}
+	}
+	lowerValues, upperValues := make([]DivInt, len(segStrs)), make([]DivInt, len(segStrs))
+	for i, segStr := range segStrs {
+		var err addrerr.AddressStringError
+		if segStr == SegmentWildcardStr {
+			lowerValues[i], upperValues[i] = 0, family.GetMaxSegmentValue()
+		} else if index := strings.IndexByte(segStr, RangeSeparator); index >= 0 {
+			if lowerValues[i], err = family.parseSegment(str, segStr[:index]); err != nil {
+				return nil, err
+			} else if upperValues[i], err = family.parseSegment(str, segStr[index+1:]); err != nil {
+				return nil, err
+			} else if lowerValues[i] > upperValues[i] {
+				return nil, &addressStringError{addressError{str: str, key: "ipaddress.error.invalidRange"}}
+			}
+		} else if lowerValues[i], err = family.parseSegment(str, segStr); err != nil {
+			return nil, err
+		} else {
+			upperValues[i] = lowerValues[i]
+		}
+	}
+	return family.NewGrouping(
+		func(segmentIndex int) DivInt {
+			return lowerValues[segmentIndex]
+		},
+		func(segmentIndex int) DivInt {
+			return upperValues[segmentIndex]
+		}, prefixLen), nil
+}
+
+func (family *AddressFamily) parseSegment(str, segStr string) (DivInt, addrerr.AddressStringError) {
+	if len(segStr) == 0 {
+		return 0, &addressStringError{addressError{str: str, key: "ipaddress.error.empty.segment.at.index"}}
+	} else if segStr[0] == '+' || segStr[0] == '-' {
+		// ParseUint accepts neither sign, but underscores are accepted with a base prefix, which we never supply
+		return 0, &addressStringError{addressError{str: str, key: "ipaddress.error.invalid.character"}}
+	}
+	val, err := strconv.ParseUint(segStr, family.radix, int(family.bitsPerSegment))
+	if err != nil {
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return 0, &addressStringError{addressError{str: str, key: "ipaddress.error.address.too.large"}}
+		}
+		return 0, &addressStringError{addressError{str: str, key: "ipaddress.error.invalid.character"}}
+	}
+	return DivInt(val), nil
+}
+
+// ToString produces a string for an address or subnet of the family, the reverse of Parse.
+// Segments spanning all values are written as the wildcard '*', other ranges are written as two values separated by '-',
+// and the prefix length, if any, is written at the end, preceded by '/'.
+//
+// If the grouping is not a member of the family, the result of its String method is returned.
+func (family *AddressFamily) ToString(grouping *AddressDivisionGrouping) string {
+	if !family.IsMember(grouping) {
+		return grouping.String()
+	}
+	maxValue := family.GetMaxSegmentValue()
+	builder := strings.Builder{}
+	for i := range grouping.getDivArray() {
+		if i > 0 {
+			builder.WriteByte(family.separator)
+		}
+		div := grouping.GetDivision(i)
+		lower, upper := div.GetDivisionValue(), div.GetUpperDivisionValue()
+		if lower == 0 && upper == maxValue && lower != upper {
+			builder.WriteString(SegmentWildcardStr)
+		} else {
+			builder.WriteString(strconv.FormatUint(uint64(lower), family.radix))
+			if lower != upper {
+				builder.WriteByte(RangeSeparator)
+				builder.WriteString(strconv.FormatUint(uint64(upper), family.radix))
+			}
+		}
+	}
+	if prefLen := grouping.getPrefixLen(); prefLen != nil {
+		builder.WriteByte(PrefixLenSeparator)
+		builder.WriteString(strconv.Itoa(int(prefLen.bitCount())))
+	}
+	return builder.String()
+}
+
+// Contains returns whether the given address or subnet of the family is contained in the other given address or subnet of the family.
+// It returns false if either is not a member of the family.
+func (family *AddressFamily) Contains(grouping, other *AddressDivisionGrouping) bool {
+	if !family.IsMember(grouping) || !family.IsMember(other) {
+		return false
+	}
+	for i := range grouping.getDivArray() {
+		div, otherDiv := grouping.GetDivision(i), other.GetDivision(i)
+		if div.GetDivisionValue() > otherDiv.GetDivisionValue() || div.GetUpperDivisionValue() < otherDiv.GetUpperDivisionValue() {
+			return false
+		}
+	}
+	return true
+}
+
+// ToPrefixBlockLen returns the prefix block of the family with the given prefix length that contains the lowest address of the given address or subnet,
+// the block of all addresses sharing that prefix.
+// The prefix length is adjusted to be between zero and the family bit length.
+// It returns nil if the given grouping is not a member of the family.
+func (family *AddressFamily) ToPrefixBlockLen(grouping *AddressDivisionGrouping, prefixLen BitCount) *AddressDivisionGrouping {
+	if !family.IsMember(grouping) {
+		return nil
+	}
+	prefixLen = checkBitCount(prefixLen, family.bitCount)
+	return family.NewGrouping(
+		func(segmentIndex int) DivInt {
+			return grouping.GetDivision(segmentIndex).GetDivisionValue() &^ family.getHostMask(prefixLen, segmentIndex)
+		},
+		func(segmentIndex int) DivInt {
+			return grouping.GetDivision(segmentIndex).GetDivisionValue() | family.getHostMask(prefixLen, segmentIndex)
+		}, cacheBitCount(prefixLen))
+}
+
+// ToPrefixBlock returns the prefix block of the family for the prefix length of the given address or subnet.
+// If the grouping has no prefix length, it is returned unchanged.
+// It returns nil if the given grouping is not a member of the family.
+func (family *AddressFamily) ToPrefixBlock(grouping *AddressDivisionGrouping) *AddressDivisionGrouping {
+	if !family.IsMember(grouping) {
+		return nil
+	} else if prefLen := grouping.getPrefixLen(); prefLen != nil {
+		return family.ToPrefixBlockLen(grouping, prefLen.bitCount())
+	}
+	return grouping
+}
+
+// getHostMask returns the mask of the host bits in the given segment for the given prefix length.
+func (family *AddressFamily) getHostMask(prefixLen BitCount, segmentIndex int) DivInt {
+	bitsPerSegment := family.bitsPerSegment
+	networkBits := prefixLen - BitCount(segmentIndex)*bitsPerSegment
+	if networkBits <= 0 {
+		return family.GetMaxSegmentValue()
+	} else if networkBits >= bitsPerSegment {
+		return 0
+	}
+	return family.GetMaxSegmentValue() >> uint(networkBits)
+}
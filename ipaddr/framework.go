@@ -337,6 +337,35 @@ type AddressSegmentSeries interface { // Address and above, AddressSection and a
 
 var _, _ AddressSegmentSeries = &Address{}, &AddressSection{}
 
+// SegmentStringsRadixProvider is an optional interface for AddressSegmentSeries, providing the segment strings in a given radix.
+// The address and address section types of this library implement it.
+// When an AddressSegmentSeries does not implement it, SegmentStringOptions.GetSegmentStrings produces the strings from the segments provided by GetGenericSegment.
+type SegmentStringsRadixProvider interface {
+	// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+	// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+	// It returns nil if the radix is invalid.
+	GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string
+}
+
+// GenericSegmentsProvider is an optional interface for AddressSegmentSeries, providing all the segments as AddressSegmentType.
+// The address and address section types of this library implement it.
+// When an AddressSegmentSeries does not implement it, the segments are available individually from GetGenericSegment.
+type GenericSegmentsProvider interface {
+	// GetGenericSegments returns a slice with the segments as AddressSegmentType.
+	// The returned slice is not backed by the same array as the series.
+	GetGenericSegments() []AddressSegmentType
+
+	// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+	// as much as can be fit into the slice, returning the number of segments copied.
+	CopyGenericSegments(segs []AddressSegmentType) (count int)
+}
+
+var _, _, _, _ GenericSegmentsProvider = &Address{}, &AddressSection{}, &IPAddress{}, &IPAddressSection{}
+var _, _, _, _, _, _ GenericSegmentsProvider = &IPv4Address{}, &IPv4AddressSection{}, &IPv6Address{}, &IPv6AddressSection{}, &MACAddress{}, &MACAddressSection{}
+
+var _, _, _, _ SegmentStringsRadixProvider = &Address{}, &AddressSection{}, &IPAddress{}, &IPAddressSection{}
+var _, _, _, _, _, _ SegmentStringsRadixProvider = &IPv4Address{}, &IPv4AddressSection{}, &IPv6Address{}, &IPv6AddressSection{}, &MACAddress{}, &MACAddressSection{}
+
 // IPAddressSegmentSeries serves as a common interface to all IP address sections and IP addresses.
 type IPAddressSegmentSeries interface { // IPAddress and above, IPAddressSection and above, ExtendedIPSegmentSeries
 	AddressSegmentSeries
@@ -804,6 +833,40 @@ var _, _, _ IPAddressSeqRangeType = &SequentialRange[*IPAddress]{},
 	&SequentialRange[*IPv4Address]{},
 	&SequentialRange[*IPv6Address]{}
 
+// IPAddressRangeSeries represents both IP addresses and IP address sequential ranges with the base types *IPAddress and *SequentialRange[*IPAddress],
+// providing the operations common to both, so that generic code for spanning, covering and iterating can accept either.
+//
+// Use ToIP to convert an IPv4 or IPv6 address or sequential range to a type implementing IPAddressRangeSeries.
+// Use Unwrap and then ToIP to obtain one from an ExtendedIPSegmentSeries wrapping an address.
+type IPAddressRangeSeries interface {
+	AddressItem
+
+	IPAddressRange
+
+	// ContainsRange returns whether all the addresses in the given sequential range are also contained in this address or range.
+	ContainsRange(IPAddressSeqRangeType) bool
+
+	// Iterator provides an iterator to iterate through the individual addresses of this address or range.
+	Iterator() Iterator[*IPAddress]
+
+	// SpanWithPrefixBlocks returns an array of prefix blocks that spans the same set of addresses as this address or range.
+	SpanWithPrefixBlocks() []*IPAddress
+
+	// SpanWithSequentialBlocks produces the smallest slice of sequential blocks that cover the same set of addresses as this address or range.
+	SpanWithSequentialBlocks() []*IPAddress
+
+	// CoverWithPrefixBlock returns the minimal-size prefix block that covers all the addresses of this address or range.
+	CoverWithPrefixBlock() *IPAddress
+
+	// ToCanonicalString produces a canonical string for the address or range.
+	ToCanonicalString() string
+
+	// ToNormalizedString produces a normalized string for the address or range.
+	ToNormalizedString() string
+}
+
+var _, _ IPAddressRangeSeries = &IPAddress{}, &SequentialRange[*IPAddress]{}
+
 // HostIdentifierString represents a string that is used to identify a host.
 type HostIdentifierString interface {
 
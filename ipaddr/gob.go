@@ -0,0 +1,332 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+)
+
+// GobFormatVersion is the version of the binary format written by the GobEncode methods of the address, sequential range and trie types.
+// It is the first byte of each encoded address, allowing later releases to continue decoding data encoded with this version.
+//
+// An encoded address is the version byte, followed by the address type, the prefix length plus one (zero when there is no prefix length),
+// the byte count of the address, the bytes of the lowest and highest addresses in the subnet, and finally the zone, if any.
+const GobFormatVersion = 1
+
+const gobHeaderLen = 4
+
+func (addr *addressInternal) gobEncode() ([]byte, error) {
+	section := addr.section
+	if section == nil {
+		return []byte{GobFormatVersion, byte(zeroType), 0, 0}, nil
+	}
+	lower, upper := section.getBytes(), section.getUpperBytes()
+	var prefixByte byte
+	if prefLen := section.GetPrefixLen(); prefLen != nil {
+		prefixByte = byte(prefLen.Len() + 1)
+	}
+	result := make([]byte, 0, gobHeaderLen+len(lower)+len(upper)+len(addr.zone))
+	result = append(result, GobFormatVersion, byte(section.addrType), prefixByte, byte(len(lower)))
+	result = append(result, lower...)
+	result = append(result, upper...)
+	return append(result, addr.zone...), nil
+}
+
+// gobDecodeAddress decodes data written by gobEncode.
+func gobDecodeAddress(data []byte) (*Address, error) {
+	if len(data) < gobHeaderLen || data[0] != GobFormatVersion {
+		return nil, errorF("unsupported address gob encoding")
+	}
+	addressType, prefixByte, byteCount := addrType(data[1]), data[2], int(data[3])
+	data = data[gobHeaderLen:]
+	if len(data) < byteCount<<1 {
+		return nil, errorF("truncated address gob encoding")
+	}
+	lower, upper, zone := data[:byteCount], data[byteCount:byteCount<<1], data[byteCount<<1:]
+	var prefLen PrefixLen
+	if prefixByte != 0 {
+		if bitCount := BitCount(prefixByte) - 1; bitCount <= BitCount(byteCount<<3) {
+			prefLen = cacheBitCount(bitCount)
+		} else {
+			return nil, errorF("invalid prefix length %d in address gob encoding", bitCount)
+		}
+	}
+	if len(zone) > 0 && !addressType.isIPv6() {
+		return nil, errorF("zone in gob encoding of address that is not IPv6")
+	}
+	var addr *Address
+	switch {
+	case addressType.isZeroSegments() && byteCount == 0:
+		return &Address{}, nil
+	case addressType.isIPv4() && byteCount == IPv4ByteCount:
+		addr = NewIPv4AddressFromRange(
+			func(segmentIndex int) IPv4SegInt {
+				return IPv4SegInt(lower[segmentIndex])
+			},
+			func(segmentIndex int) IPv4SegInt {
+				return IPv4SegInt(upper[segmentIndex])
+			}).ToAddressBase()
+	case addressType.isIPv6() && byteCount == IPv6ByteCount:
+		addr = NewIPv6AddressFromZonedRange(
+			func(segmentIndex int) IPv6SegInt {
+				return IPv6SegInt(lower[segmentIndex<<1])<<8 | IPv6SegInt(lower[segmentIndex<<1|1])
+			},
+			func(segmentIndex int) IPv6SegInt {
+				return IPv6SegInt(upper[segmentIndex<<1])<<8 | IPv6SegInt(upper[segmentIndex<<1|1])
+			}, string(zone)).ToAddressBase()
+	case addressType.isMAC() && (byteCount == MediaAccessControlSegmentCount || byteCount == ExtendedUniqueIdentifier64SegmentCount):
+		addr = NewMACAddressFromRangeExt(
+			func(segmentIndex int) MACSegInt {
+				return MACSegInt(lower[segmentIndex])
+			},
+			func(segmentIndex int) MACSegInt {
+				return MACSegInt(upper[segmentIndex])
+			}, byteCount == ExtendedUniqueIdentifier64SegmentCount).ToAddressBase()
+	default:
+		return nil, errorF("invalid address type or byte count in address gob encoding")
+	}
+	if prefLen != nil {
+		// the prefix length is applied after construction, since constructors would convert an address with a zero host to the prefix block
+		addr = addr.SetPrefixLen(prefLen.Len())
+	}
+	return addr, nil
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the address or subnet along with its prefix length and zone, as described by GobFormatVersion.
+func (addr *Address) GobEncode() ([]byte, error) {
+	return addr.init().gobEncode()
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing this address with the address or subnet decoded from data written by GobEncode.
+func (addr *Address) GobDecode(data []byte) error {
+	decoded, err := gobDecodeAddress(data)
+	if err != nil {
+		return err
+	}
+	*addr = *decoded
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the address or subnet along with its prefix length and zone, as described by GobFormatVersion.
+func (addr *IPAddress) GobEncode() ([]byte, error) {
+	return addr.init().gobEncode()
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing this address with the address or subnet decoded from data written by GobEncode.
+// An error is returned if the data encodes a MAC address.
+func (addr *IPAddress) GobDecode(data []byte) error {
+	decoded, err := gobDecodeAddress(data)
+	if err != nil {
+		return err
+	} else if decoded.IsMAC() {
+		return errorF("gob encoding is not that of an IP address")
+	}
+	*addr = IPAddress{ipAddressInternal{decoded.addressInternal}}
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the address or subnet along with its prefix length, as described by GobFormatVersion.
+func (addr *IPv4Address) GobEncode() ([]byte, error) {
+	return addr.init().gobEncode()
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing this address with the address or subnet decoded from data written by GobEncode.
+// An error is returned if the data does not encode an IPv4 address.
+func (addr *IPv4Address) GobDecode(data []byte) error {
+	decoded, err := gobDecodeAddress(data)
+	if err != nil {
+		return err
+	} else if !decoded.IsIPv4() {
+		return errorF("gob encoding is not that of an IPv4 address")
+	}
+	*addr = *decoded.ToIPv4()
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the address or subnet along with its prefix length and zone, as described by GobFormatVersion.
+func (addr *IPv6Address) GobEncode() ([]byte, error) {
+	return addr.init().gobEncode()
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing this address with the address or subnet decoded from data written by GobEncode.
+// An error is returned if the data does not encode an IPv6 address.
+func (addr *IPv6Address) GobDecode(data []byte) error {
+	decoded, err := gobDecodeAddress(data)
+	if err != nil {
+		return err
+	} else if !decoded.IsIPv6() {
+		return errorF("gob encoding is not that of an IPv6 address")
+	}
+	*addr = *decoded.ToIPv6()
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the address or subnet along with its prefix length, as described by GobFormatVersion.
+func (addr *MACAddress) GobEncode() ([]byte, error) {
+	return addr.init().gobEncode()
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing this address with the address or subnet decoded from data written by GobEncode.
+// An error is returned if the data does not encode a MAC address.
+func (addr *MACAddress) GobDecode(data []byte) error {
+	decoded, err := gobDecodeAddress(data)
+	if err != nil {
+		return err
+	} else if !decoded.IsMAC() {
+		return errorF("gob encoding is not that of a MAC address")
+	}
+	*addr = *decoded.ToMAC()
+	return nil
+}
+
+type rangeGob[T any] struct {
+	Lower, Upper T
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the lower and upper addresses of the range.
+func (rng *SequentialRange[T]) GobEncode() ([]byte, error) {
+	rng = rng.init()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(rangeGob[T]{rng.lower, rng.upper}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing this range with the range decoded from data written by GobEncode.
+func (rng *SequentialRange[T]) GobDecode(data []byte) error {
+	var decoded rangeGob[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	result := NewSequentialRange(decoded.Lower, decoded.Upper)
+	if result == nil {
+		return errorF("gob encoding of range has mismatched versions")
+	}
+	*rng = *result
+	return nil
+}
+
+// checkGobTrieKey checks that a decoded key can be added to a trie alongside the first decoded key.
+func checkGobTrieKey[T TrieKeyConstraint[T]](key, first T) error {
+	if _, err := checkBlockOrAddress(key); err != nil {
+		return err
+	} else if key.GetBitCount() != first.GetBitCount() {
+		return errorF("gob encoding of trie has keys of mismatched bit size")
+	}
+	return nil
+}
+
+type trieGob[T any] struct {
+	Keys []T
+}
+
+type associativeTrieGob[T, V any] struct {
+	Keys []T
+
+	// HasValue indicates for each key whether its value is in Values, the value being the zero value otherwise
+	HasValue []bool
+	Values   []V
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the added keys of the trie in sorted order.
+func (trie *Trie[T]) GobEncode() ([]byte, error) {
+	keys := make([]T, 0, trie.Size())
+	for iter := trie.Iterator(); iter.HasNext(); {
+		keys = append(keys, iter.Next())
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(trieGob[T]{keys}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing the contents of this trie with the keys decoded from data written by GobEncode.
+// If the data cannot be decoded, an error is returned and the trie is unchanged.
+func (trie *Trie[T]) GobDecode(data []byte) error {
+	var decoded trieGob[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	var result Trie[T]
+	for _, key := range decoded.Keys {
+		if err := checkGobTrieKey(key, decoded.Keys[0]); err != nil {
+			return err
+		}
+		result.Add(key)
+	}
+	*trie = result
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface, encoding the added keys of the trie along with their mapped values.
+//
+// The values are encoded with encoding/gob, so when V is an interface type, the concrete types of the values must be registered with gob.Register,
+// as must any interface-typed values nested within them.
+// Values that are the zero value of V, such as the nil values of keys added with Add, are not encoded, and are restored as the zero value by GobDecode.
+func (trie *AssociativeTrie[T, V]) GobEncode() ([]byte, error) {
+	size := trie.Size()
+	keys, hasValue, values := make([]T, 0, size), make([]bool, 0, size), make([]V, 0, size)
+	for iter := trie.NodeIterator(true); iter.HasNext(); {
+		node := iter.Next()
+		value := node.GetValue()
+		isZero := reflect.ValueOf(&value).Elem().IsZero()
+		keys, hasValue = append(keys, node.GetKey()), append(hasValue, !isZero)
+		if !isZero {
+			values = append(values, value)
+		}
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(associativeTrieGob[T, V]{keys, hasValue, values}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface, replacing the contents of this trie with the keys and values decoded from data written by GobEncode.
+// If the data cannot be decoded, an error is returned and the trie is unchanged.
+func (trie *AssociativeTrie[T, V]) GobDecode(data []byte) error {
+	var decoded associativeTrieGob[T, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	} else if len(decoded.Keys) != len(decoded.HasValue) {
+		return errorF("gob encoding of trie has %d keys and %d value flags", len(decoded.Keys), len(decoded.HasValue))
+	}
+	var result AssociativeTrie[T, V]
+	values := decoded.Values
+	for i, key := range decoded.Keys {
+		if err := checkGobTrieKey(key, decoded.Keys[0]); err != nil {
+			return err
+		}
+		var value V
+		if decoded.HasValue[i] {
+			if len(values) == 0 {
+				return errorF("gob encoding of trie has too few values")
+			}
+			value, values = values[0], values[1:]
+		}
+		result.Put(key, value)
+	}
+	if len(values) > 0 {
+		return errorF("gob encoding of trie has too many values")
+	}
+	*trie = result
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"unsafe"
 
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrstr"
 )
 
 func createGrouping(divs []*AddressDivision, prefixLength PrefixLen, addrType addrType) *AddressDivisionGrouping {
@@ -288,6 +289,57 @@ func (grouping *addressDivisionGroupingInternal) getSegmentStrings() []string {
 	return result
 }
 
+func (grouping *addressDivisionGroupingInternal) getSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	return toSegmentStringsRadix(grouping.GetDivisionCount(), grouping.getDivision, radix, uppercase, leadingZeros)
+}
+
+func toSegmentStringsRadix(segmentCount int, getDivision func(int) *AddressDivision, radix int, uppercase, leadingZeros bool) []string {
+	if radix < 2 || radix > 36 {
+		return nil
+	} else if segmentCount == 0 {
+		return []string{}
+	}
+	opts := new(addrstr.StringOptionsBuilder).
+		SetRadix(radix).
+		SetUppercase(uppercase).
+		SetExpandedSegments(leadingZeros).
+		SetWildcards(segmentStringsWildcards).ToOptions()
+	result := make([]string, segmentCount)
+	for i := range result {
+		result[i] = getDivision(i).toStringOpts(opts)
+	}
+	return result
+}
+
+var segmentStringsWildcards = new(addrstr.WildcardsBuilder).SetWildcard(SegmentWildcardStr).ToWildcards()
+
+// SegmentStringOptions are the options for the strings of the segments of an address or address section,
+// which can be reused for each address or section to be rendered, such as the rows of a table of segment values.
+type SegmentStringOptions struct {
+	// Radix is the radix of the segment values, which must be between 2 and 36, such as 10 for decimal or 16 for hexadecimal.
+	Radix int
+
+	// Uppercase specifies uppercase for the digits beyond 9 in radices larger than 10.
+	Uppercase bool
+
+	// LeadingZeros specifies that each segment value is padded with leading zeros to the maximum number of digits for the segment bit count in the given radix.
+	LeadingZeros bool
+}
+
+// GetSegmentStrings returns a slice with the string for each segment of the given address or section, using these options,
+// as provided by GetSegmentStringsRadix when the series implements SegmentStringsRadixProvider, as do the address and section types of this library.
+// It returns nil if the given series is nil or the radix is invalid.
+func (options SegmentStringOptions) GetSegmentStrings(series AddressSegmentSeries) []string {
+	if series == nil {
+		return nil
+	} else if radixProvider, ok := series.(SegmentStringsRadixProvider); ok {
+		return radixProvider.GetSegmentStringsRadix(options.Radix, options.Uppercase, options.LeadingZeros)
+	}
+	return toSegmentStringsRadix(series.GetSegmentCount(), func(index int) *AddressDivision {
+		return series.GetGenericSegment(index).ToSegmentBase().ToDiv()
+	}, options.Radix, options.Uppercase, options.LeadingZeros)
+}
+
 func (grouping *addressDivisionGroupingInternal) toAddressDivisionGrouping() *AddressDivisionGrouping {
 	return (*AddressDivisionGrouping)(unsafe.Pointer(grouping))
 }
@@ -382,7 +434,7 @@ func (grouping addressDivisionGroupingInternal) defaultFormat(state fmt.State, v
 
 func (grouping *addressDivisionGroupingInternal) toString() string {
 	if sect := grouping.toAddressSection(); sect != nil {
-		return sect.ToNormalizedString()
+		return sect.toDefaultFormatString(NoZone, false)
 	}
 	return fmt.Sprint(grouping.initDivs().getDivArray())
 }
@@ -685,6 +737,50 @@ func (grouping *addressDivisionGroupingInternal) CopyUpperBytes(bytes []byte) []
 	return getBytesCopy(bytes, grouping.getUpperBytes())
 }
 
+// AppendBytes appends the value of the lowest division grouping in the range to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// The bytes of the divisions of IPv4, IPv6 and MAC groupings are written directly to the slice, so no allocation is made when the capacity of the given slice suffices.
+// Other groupings append the bytes that are calculated and cached by the first call of Bytes, CopyBytes, AppendBytes or the corresponding upper methods.
+func (grouping *addressDivisionGroupingInternal) AppendBytes(bytes []byte) []byte {
+	return grouping.appendBytes(bytes, false)
+}
+
+// AppendUpperBytes appends the value of the highest division grouping in the range to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// The bytes of the divisions of IPv4, IPv6 and MAC groupings are written directly to the slice, so no allocation is made when the capacity of the given slice suffices.
+// Other groupings append the bytes that are calculated and cached by the first call of Bytes, CopyBytes, AppendBytes or the corresponding upper methods.
+func (grouping *addressDivisionGroupingInternal) AppendUpperBytes(bytes []byte) []byte {
+	return grouping.appendBytes(bytes, true)
+}
+
+func (grouping *addressDivisionGroupingInternal) appendBytes(bytes []byte, upper bool) []byte {
+	if grouping.hasNoDivisions() {
+		return bytes
+	}
+	addrType := grouping.getAddrType()
+	if addrType.isIPv4() || addrType.isMAC() || addrType.isIPv6() {
+		isIPv6 := addrType.isIPv6()
+		divisionCount := grouping.GetDivisionCount()
+		for i := 0; i < divisionCount; i++ {
+			seg := grouping.getDivision(i).ToSegmentBase()
+			var val SegInt
+			if upper {
+				val = seg.GetUpperSegmentValue()
+			} else {
+				val = seg.GetSegmentValue()
+			}
+			if isIPv6 {
+				bytes = append(bytes, byte(val>>8))
+			}
+			bytes = append(bytes, byte(val))
+		}
+		return bytes
+	} else if upper {
+		return append(bytes, grouping.getUpperBytes()...)
+	}
+	return append(bytes, grouping.getBytes()...)
+}
+
 func (grouping *addressDivisionGroupingInternal) getBytes() (bytes []byte) {
 	bytes, _ = grouping.getCachedBytes(grouping.calcBytes)
 	return
@@ -812,7 +908,12 @@ func (grouping *addressDivisionGroupingInternal) createNewPrefixedDivisions(bits
 	}
 
 	// at this point bitDivs has our division sizes
+	return grouping.createBitCountDivisions(bitDivs, networkPrefixLength)
+}
 
+// createBitCountDivisions creates divisions with the given bit sizes, which are ordered from the least significant division to the most.
+// The bit sizes must add up to the bit count of this grouping, and each must be no more than 64 bits, the size of DivInt.
+func (grouping *addressDivisionGroupingInternal) createBitCountDivisions(bitDivs []BitCount, networkPrefixLength PrefixLen) ([]*AddressDivision, addrerr.IncompatibleAddressError) {
 	divCount := len(bitDivs)
 	divs := make([]*AddressDivision, divCount)
 	if divCount > 0 {
@@ -904,6 +1005,34 @@ func (grouping *addressDivisionGroupingInternal) createNewPrefixedDivisions(bits
 	return divs, nil
 }
 
+// RegroupByBitCounts returns a grouping of the same bits as this grouping, divided into divisions of the given bit lengths,
+// ordered from the most significant division to the least.
+// This allows a series of bits to be viewed according to an arbitrary layout of fields, such as those of a protocol header.
+// Any prefix length is carried over to the new divisions.
+//
+// The bit lengths must add up to the bit count of this grouping, and each must be between 1 and 64, otherwise an error is returned.
+// An error is also returned when the grouping has ranged values that cannot be represented as sequential ranges in the new divisions.
+func (grouping *addressDivisionGroupingInternal) RegroupByBitCounts(bitCounts ...BitCount) (*AddressDivisionGrouping, addrerr.IncompatibleAddressError) {
+	var total BitCount
+	bitDivs := make([]BitCount, len(bitCounts))
+	for i, bitCount := range bitCounts {
+		if bitCount <= 0 || bitCount > 64 {
+			return nil, &sizeMismatchError{incompatibleAddressError{addressError{key: "ipaddress.error.mismatched.bit.size"}}}
+		}
+		total += bitCount
+		bitDivs[len(bitCounts)-i-1] = bitCount
+	}
+	if total != grouping.GetBitCount() {
+		return nil, &sizeMismatchError{incompatibleAddressError{addressError{key: "ipaddress.error.mismatched.bit.size"}}}
+	}
+	prefixLength := grouping.getPrefixLen()
+	divs, err := grouping.createBitCountDivisions(bitDivs, prefixLength)
+	if err != nil {
+		return nil, err
+	}
+	return createInitializedGrouping(divs, prefixLength), nil
+}
+
 //// only needed for godoc / pkgsite
 
 // GetBitCount returns the number of bits in each value comprising this address item.
@@ -985,6 +1114,27 @@ func NewDivisionGrouping(divs []*AddressDivision) *AddressDivisionGrouping {
 	return result
 }
 
+// The bit lengths of the fields in the first 32 bits of an IPv6 packet header, RFC 8200.
+const (
+	IPv6HeaderVersionBitCount      = 4
+	IPv6HeaderTrafficClassBitCount = 8
+	IPv6HeaderFlowLabelBitCount    = 20
+)
+
+// NewIPv6HeaderFlowGrouping creates a grouping modelling the first 32 bits of an IPv6 packet header,
+// with a 4-bit division for the version, which is always 6, an 8-bit division for the given traffic class, and a 20-bit division for the given flow label.
+// Values exceeding the bit length of their division are truncated.
+//
+// The same layout can be obtained from existing header bits held in a section with RegroupByBitCounts,
+// passing IPv6HeaderVersionBitCount, IPv6HeaderTrafficClassBitCount and IPv6HeaderFlowLabelBitCount.
+func NewIPv6HeaderFlowGrouping(trafficClass, flowLabel DivInt) *AddressDivisionGrouping {
+	return NewDivisionGrouping([]*AddressDivision{
+		NewDivision(6, IPv6HeaderVersionBitCount),
+		NewDivision(trafficClass, IPv6HeaderTrafficClassBitCount),
+		NewDivision(flowLabel, IPv6HeaderFlowLabelBitCount),
+	})
+}
+
 func normalizeDivisions(divs []*AddressDivision) (newDivs []*AddressDivision, newPref PrefixLen, isMultiple bool) {
 	divCount := len(divs)
 	newDivs = make([]*AddressDivision, 0, divCount)
@@ -487,6 +487,8 @@ type valueCache struct {
 
 	stringCache stringCache
 
+	stringCachingDisabled bool // see WithoutStringCaching
+
 	sectionCache *groupingCache
 
 	mixed *mixedCache
@@ -0,0 +1,110 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import "hash"
+
+// HashWriterTo is implemented by addresses, address sections and address keys,
+// which can write a canonical byte representation of themselves into a hash.
+//
+// The bytes written are the address type, the byte count, the bytes of the lowest value, and the bytes of the highest value,
+// followed by the zone length and zone for IPv6 addresses.
+// The encoding is self-delimiting, so the bytes of many items can be fed into the same hash to identify a collection.
+//
+// Prefix lengths are not written, consistent with the Equal methods and with address keys.
+// Equal addresses write the same bytes, and an address writes the same bytes as its keys.
+type HashWriterTo interface {
+	// WriteToHash writes the canonical bytes to the given hash.
+	WriteToHash(h hash.Hash)
+}
+
+var (
+	_, _, _, _, _ HashWriterTo = &Address{}, &IPAddress{}, &IPv4Address{}, &IPv6Address{}, &MACAddress{}
+	_, _, _, _, _ HashWriterTo = &AddressSection{}, &IPAddressSection{}, &IPv4AddressSection{}, &IPv6AddressSection{}, &MACAddressSection{}
+	_, _, _, _, _ HashWriterTo = IPv4AddressKey{}, IPv6AddressKey{}, MACAddressKey{}, Key[*IPAddress]{}, Key[*Address]{}
+)
+
+// byteValues holds every byte value, so single bytes can be written to a hash without allocating a buffer
+var byteValues = func() (vals [256]byte) {
+	for i := range vals {
+		vals[i] = byte(i)
+	}
+	return
+}()
+
+func writeHashByte(h hash.Hash, b byte) {
+	h.Write(byteValues[b : int(b)+1])
+}
+
+func writeHashHeader(h hash.Hash, addrType addrType, byteCount int) {
+	writeHashByte(h, byte(addrType))
+	writeHashByte(h, byte(byteCount))
+}
+
+// writeHashBytes writes the given number of low-order bytes of val, most significant first
+func writeHashBytes(h hash.Hash, val uint64, byteCount int) {
+	for shift := (byteCount - 1) << 3; shift >= 0; shift -= 8 {
+		writeHashByte(h, byte(val>>uint(shift)))
+	}
+}
+
+// writeHashZone writes the zone length as a uvarint, followed by the zone
+func writeHashZone(h hash.Hash, zone Zone) {
+	length := uint64(len(zone))
+	for length >= 0x80 {
+		writeHashByte(h, byte(length)|0x80)
+		length >>= 7
+	}
+	writeHashByte(h, byte(length))
+	// hash.Hash is not an io.StringWriter, so writing the zone as a whole would allocate a byte slice
+	for i := 0; i < len(zone); i++ {
+		writeHashByte(h, zone[i])
+	}
+}
+
+func (addr *addressInternal) writeToHash(h hash.Hash) {
+	addr.section.WriteToHash(h)
+	if addr.isIPv6() {
+		writeHashZone(h, addr.zone)
+	}
+}
+
+func (key *keyContents) writeToHash(h hash.Hash, scheme addressScheme) {
+	switch scheme {
+	case ipv4Scheme:
+		writeHashHeader(h, ipv4Type, IPv4ByteCount)
+		writeHashBytes(h, key.vals[0].lower, IPv4ByteCount)
+		writeHashBytes(h, key.vals[0].upper, IPv4ByteCount)
+	case ipv6Scheme:
+		writeHashHeader(h, ipv6Type, IPv6ByteCount)
+		writeHashBytes(h, key.vals[0].lower, 8)
+		writeHashBytes(h, key.vals[1].lower, 8)
+		writeHashBytes(h, key.vals[0].upper, 8)
+		writeHashBytes(h, key.vals[1].upper, 8)
+		writeHashZone(h, key.zone)
+	case mac48Scheme:
+		writeHashHeader(h, macType, MediaAccessControlSegmentCount)
+		writeHashBytes(h, key.vals[0].lower, MediaAccessControlSegmentCount)
+		writeHashBytes(h, key.vals[0].upper, MediaAccessControlSegmentCount)
+	case eui64Scheme:
+		writeHashHeader(h, macType, ExtendedUniqueIdentifier64SegmentCount)
+		writeHashBytes(h, key.vals[0].lower, ExtendedUniqueIdentifier64SegmentCount)
+		writeHashBytes(h, key.vals[0].upper, ExtendedUniqueIdentifier64SegmentCount)
+	default:
+		writeHashHeader(h, zeroType, 0)
+	}
+}
@@ -17,9 +17,12 @@
 package ipaddr
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/netip"
+	"net/url"
+	"sort"
 	"strings"
 	"unsafe"
 
@@ -44,6 +47,60 @@ func parseHostName(str string, params addrstrparam.HostNameParams) *HostName {
 	return res
 }
 
+// ParseReverseDNSName parses a reverse-DNS name, either a full name like "4.3.2.1.in-addr.arpa" for an individual address,
+// or a zone name like "2.1.in-addr.arpa" with fewer labels, for the prefix block covered by the zone.
+// A trailing dot, as in a fully-qualified domain name, is ignored.
+// The suffixes ".in-addr.arpa", ".ip6.arpa" and ".ip6.int" are accepted, in any case.
+//
+// For "2.1.in-addr.arpa" it is "1.2.0.0/16", and for "8.b.d.0.1.0.0.2.ip6.arpa" it is "2001:db8::/32".
+//
+// This is the inverse of ToReverseDNSString for individual addresses and of ToReverseDNSZoneStrings for zones.
+// If the name is not a valid reverse-DNS name, an error is returned.
+func ParseReverseDNSName(name string) (*IPAddress, addrerr.HostNameError) {
+	str := strings.TrimSuffix(name, ".")
+	var labelBits BitCount
+	var fullLabelCount int
+	if hasReverseDNSSuffix(str, IPv4ReverseDnsSuffix) {
+		labelBits, fullLabelCount = IPv4BitsPerSegment, IPv4SegmentCount
+	} else if hasReverseDNSSuffix(str, IPv6ReverseDnsSuffix) || hasReverseDNSSuffix(str, IPv6ReverseDnsSuffixDeprecated) {
+		labelBits, fullLabelCount = 4, IPv6SegmentCount<<2
+	} else {
+		return nil, &hostNameError{addressError{str: name, key: "ipaddress.host.error.invalid"}}
+	}
+	// the suffix has a single separator, the labels preceding it each have one more
+	labelCount := strings.Count(str, ".") - 1
+	if !strings.HasPrefix(str, ".") && labelCount < fullLabelCount {
+		// a zone name, so we fill in the zero-valued least-significant labels of the lowest address in the zone
+		str = strings.Repeat("0.", fullLabelCount-labelCount) + str
+	}
+	host := NewHostName(str)
+	if err := host.Validate(); err != nil {
+		if labelCount >= fullLabelCount {
+			return nil, err
+		}
+		return nil, &hostNameError{addressError{str: name, key: "ipaddress.host.error.invalid"}}
+	}
+	addr := host.AsAddress()
+	if addr == nil || !host.IsReverseDNS() {
+		return nil, &hostNameError{addressError{str: name, key: "ipaddress.host.error.invalid"}}
+	}
+	if labelCount < fullLabelCount {
+		addr = addr.ToPrefixBlockLen(BitCount(labelCount) * labelBits)
+	}
+	return addr, nil
+}
+
+// hasReverseDNSSuffix returns whether the string is the given suffix, or ends with it, ignoring case
+// and ignoring the leading separator of the suffix when the string is the suffix.
+func hasReverseDNSSuffix(str, suffix string) bool {
+	if len(str) < len(suffix)-1 {
+		return false
+	} else if len(str) == len(suffix)-1 {
+		return strings.EqualFold(str, suffix[1:])
+	}
+	return strings.EqualFold(str[len(str)-len(suffix):], suffix)
+}
+
 // NewHostName constructs a HostName that will parse the given string according to the default parameters.
 func NewHostName(str string) *HostName {
 	return parseHostName(str, defaultHostParameters)
@@ -60,6 +117,21 @@ func NewHostNameParams(str string, params addrstrparam.HostNameParams) *HostName
 	return parseHostName(str, prms)
 }
 
+// IsValidHostName returns whether the given string is a valid host name according to the given parameters,
+// or the default parameters used by NewHostName if the parameters are nil.
+//
+// It is equivalent to calling IsValid on the HostName from NewHostNameParams, but is faster, since the parameters are not copied,
+// and neither the HostName nor its address is retained.
+// Use it to validate strings in performance-sensitive code when neither the host nor a descriptive error is needed.
+func IsValidHostName(str string, params addrstrparam.HostNameParams) bool {
+	if params == nil {
+		params = defaultHostParameters
+	}
+	host := HostName{str: strings.TrimSpace(str)}
+	_, err := validator.validateHostName(&host, params)
+	return err == nil
+}
+
 // NewHostNameFromAddrPort constructs a HostName from an IP address and a port.
 func NewHostNameFromAddrPort(addr *IPAddress, port uint16) *HostName {
 	portVal := PortInt(port)
@@ -76,6 +148,109 @@ func NewHostNameFromAddrPort(addr *IPAddress, port uint16) *HostName {
 	}
 }
 
+// SplitHostPort parses a socket address string of the form "host:port", such as "1.2.3.4:80" or "[2001:db8::1%eth0]:80",
+// into its IP address and port.  An IPv6 address must be enclosed in square brackets, with any zone inside the brackets.
+// This is the inverse of ToHostPortString.
+//
+// An error is returned if the string is not a valid host name, if the host is not an IP address, or if there is no port.
+// As with HostName, the port must be between 1 and 65535, so a port of 0 is rejected.
+// The host must be a single address, so an empty host, which HostName parses as the zero address by default, and subnets like "1.2.*.*:80" are rejected.
+func SplitHostPort(str string) (*IPAddress, Port, addrerr.HostNameError) {
+	host := NewHostNameParams(str, socketAddressHostParameters)
+	if err := host.Validate(); err != nil {
+		return nil, nil, err
+	} else if host.IsEmpty() {
+		return nil, nil, &hostNameError{addressError{str: str, key: "ipaddress.host.error.empty"}}
+	} else if !host.IsAddress() {
+		return nil, nil, &hostNameError{addressError{str: str, key: "ipaddress.host.error.invalid.type"}}
+	}
+	port := host.GetPort()
+	if port == nil {
+		return nil, nil, &hostNameError{addressError{str: str, key: "ipaddress.host.error.invalidPort.no.digits"}}
+	}
+	addr := host.AsAddress()
+	if addr.IsMultiple() {
+		return nil, nil, &hostNameError{addressError{str: str, key: "ipaddress.error.single.address.required"}}
+	}
+	return addr, port, nil
+}
+
+// ParseAuthority parses the authority component of a URL, such as "user@[fe80::1%25eth0]:8080" or "example.com:80", into a HostName.
+//
+// Any user information preceding the host is discarded.  IPv6 addresses must be enclosed in square brackets,
+// and a zone must be percent-encoded as described by RFC 6874, with the '%' character itself encoded as "%25".
+// The port, if any, must be numeric.  An empty port, as in "example.com:", is the same as no port, as described by RFC 3986.
+// An error is returned if the authority is invalid, if it is a subnet rather than a single address, or if it is followed by a path, query or fragment.
+func ParseAuthority(authority string) (*HostName, addrerr.HostNameError) {
+	hostPort := authority
+	if index := strings.LastIndexByte(authority, '@'); index >= 0 {
+		hostPort = authority[index+1:]
+	}
+	if index := strings.IndexAny(hostPort, "/?#"); index >= 0 {
+		return nil, &hostNameIndexError{
+			hostNameError{addressError{str: authority, key: "ipaddress.host.error.invalid.character.at.index"}},
+			len(authority) - len(hostPort) + index}
+	} else if index = strings.IndexByte(hostPort, '%'); index >= 0 && !strings.HasPrefix(hostPort[index+1:], "25") {
+		// RFC 6874 requires the zone separator to be encoded
+		return nil, &hostNameIndexError{
+			hostNameError{addressError{str: authority, key: "ipaddress.host.error.invalid.character.at.index"}},
+			len(authority) - len(hostPort) + index}
+	}
+	if trimmed := strings.TrimSuffix(hostPort, ":"); len(trimmed) < len(hostPort) &&
+		(strings.HasSuffix(trimmed, "]") || !strings.ContainsRune(trimmed, ':')) {
+		hostPort = trimmed // RFC 3986 allows an empty port
+	}
+	return validateURLHost(hostPort, authorityHostParameters)
+}
+
+// NewHostNameFromURL constructs a HostName from the host and port of the given URL, such as the host "[fe80::1%eth0]:8080" of "http://[fe80::1%25eth0]:8080/".
+// The Host field of url.URL holds the host with any IPv6 zone already decoded.
+// An error is returned if the host is invalid or is a subnet rather than a single address.  A nil URL is treated as a URL with an empty host.
+func NewHostNameFromURL(u *url.URL) (*HostName, addrerr.HostNameError) {
+	var hostPort string
+	if u != nil {
+		hostPort = u.Host
+	}
+	return validateURLHost(hostPort, urlHostParameters)
+}
+
+func validateURLHost(hostPort string, params addrstrparam.HostNameParams) (*HostName, addrerr.HostNameError) {
+	host := parseHostName(hostPort, params)
+	if err := host.Validate(); err != nil {
+		return nil, err
+	} else if host.IsAddress() {
+		if addr := host.AsAddress(); addr.isIPv6() && !strings.HasPrefix(hostPort, "[") {
+			return nil, &hostNameError{addressError{str: hostPort, key: "ipaddress.host.error.host.brackets"}}
+		} else if addr.IsMultiple() {
+			return nil, &hostNameError{addressError{str: hostPort, key: "ipaddress.error.single.address.required"}}
+		}
+	} else if host.IsAddressString() && host.AsAddressString().IsAllAddresses() {
+		return nil, &hostNameError{addressError{str: hostPort, key: "ipaddress.error.single.address.required"}}
+	}
+	return host, nil
+}
+
+func newURLHostParams(uriZoneEncoding bool) addrstrparam.HostNameParams {
+	builder := new(addrstrparam.HostNameParamsBuilder).AllowService(false)
+	builder.GetIPAddressParamsBuilder().AllowPrefix(false).AllowMask(false).
+		GetIPv6AddressParamsBuilder().AllowURIZoneEncoding(uriZoneEncoding)
+	return builder.ToParams()
+}
+
+var (
+	authorityHostParameters = newURLHostParams(true)
+	urlHostParameters       = newURLHostParams(false)
+
+	socketAddressHostParameters = newSocketAddressHostParams()
+)
+
+// newSocketAddressHostParams returns the default parameters, except that an empty host is not parsed as the zero address
+func newSocketAddressHostParams() addrstrparam.HostNameParams {
+	builder := new(addrstrparam.HostNameParamsBuilder).Set(defaultHostParameters)
+	builder.GetIPAddressParamsBuilder().ParseEmptyStrAs(addrstrparam.NoAddressOption)
+	return builder.ToParams()
+}
+
 // NewHostNameFromAddr constructs a HostName from an IP address.
 func NewHostNameFromAddr(addr *IPAddress) *HostName {
 	hostStr := addr.ToNormalizedString()
@@ -358,135 +533,206 @@ func (host *HostName) ToAddress() (addr *IPAddress, err addrerr.AddressError) {
 // The error can be addrerr.AddressStringError,addrerr.IncompatibleAddressError, or addrerr.HostNameError.
 // This method can potentially return a list of resolved addresses and an error as well if some resolved addresses were invalid.
 func (host *HostName) ToAddresses() (addrs []*IPAddress, err addrerr.AddressError) {
+	return host.ResolveWithContext(context.Background())
+}
+
+// ResolveWithContext is the same as ToAddresses, but the given context controls the host name lookup,
+// so that it can be cancelled or given a deadline.
+// As with ToAddresses, the resolved addresses are cached in this HostName, while a failed lookup is attempted again with the next call.
+func (host *HostName) ResolveWithContext(ctx context.Context) (addrs []*IPAddress, err addrerr.AddressError) {
 	host = host.init()
 	data := (*resolveData)(atomicLoadPointer((*unsafe.Pointer)(unsafe.Pointer(&host.resolveData))))
 	if data == nil {
-		//note that validation handles empty address resolution
-		err = host.Validate() //addrerr.HostNameError
-		if err != nil {
+		if data, err = host.resolve(ctx, net.DefaultResolver); err != nil {
 			return
 		}
-		// http://networkbit.ch/golang-dns-lookup/
-		parsedHost := host.parsedHost
-		if parsedHost.isAddressString() {
-			addr, addrErr := parsedHost.asAddress() //addrerr.IncompatibleAddressError
-			addrs, err = []*IPAddress{addr}, addrErr
-			//note there is no need to apply prefix or mask here, it would have been applied to the address already
+		dataLoc := (*unsafe.Pointer)(unsafe.Pointer(&host.resolveData))
+		atomicStorePointer(dataLoc, unsafe.Pointer(data))
+	}
+	return data.resolvedAddrs, nil
+}
+
+// ResolveWithResolver is the same as ResolveWithContext, but host names are looked up with the given resolver rather than the default resolver of the net package.
+// Since the results depend on the resolver, they are neither taken from nor stored in the cache of this HostName.
+func (host *HostName) ResolveWithResolver(ctx context.Context, resolver HostResolver) (addrs []*IPAddress, err addrerr.AddressError) {
+	data, err := host.init().resolve(ctx, resolver)
+	if err != nil {
+		return
+	}
+	return data.resolvedAddrs, nil
+}
+
+// ResolveAll resolves to all addresses, returning them in a deterministic order,
+// which does not depend on the order in which they were returned by the name server.
+// The addresses of the preferred IP version, given by the HostNameParams used to construct this HostName, come first, each version ordered by address value.
+// Duplicate addresses are removed.  The returned slice is not shared with this HostName.
+//
+// If the given resolver is nil, this resolves as does ResolveWithContext, otherwise as does ResolveWithResolver.
+func (host *HostName) ResolveAll(ctx context.Context, resolver HostResolver) ([]*IPAddress, addrerr.AddressError) {
+	var addrs []*IPAddress
+	var err addrerr.AddressError
+	if resolver == nil {
+		addrs, err = host.ResolveWithContext(ctx)
+	} else {
+		addrs, err = host.ResolveWithResolver(ctx, resolver)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := append(make([]*IPAddress, 0, len(addrs)), addrs...)
+	preferredAddrType := IPVersion(host.GetValidationOptions().GetPreferredVersion()).toType()
+	sort.SliceStable(result, func(i, j int) bool {
+		one, two := result[i], result[j]
+		if oneType, twoType := one.getAddrType(), two.getAddrType(); oneType != twoType {
+			if oneType == preferredAddrType || twoType == preferredAddrType {
+				return oneType == preferredAddrType
+			}
+			return oneType < twoType
+		}
+		return one.Compare(two) < 0
+	})
+	unique := result[:0]
+	for i, addr := range result {
+		if i == 0 || !addr.Equal(result[i-1]) {
+			unique = append(unique, addr)
+		}
+	}
+	return unique, nil
+}
+
+// HostResolver looks up the IP addresses of host names.
+// It is implemented by *net.Resolver, and can be supplied to HostName.ResolveWithResolver to customize host name resolution.
+type HostResolver interface {
+	// LookupIPAddr looks up the IP addresses of the given host.
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+var _ HostResolver = net.DefaultResolver
+
+// resolve validates and resolves the host.  When it returns an error, there is no resolveData, and resolution can be attempted again.
+func (host *HostName) resolve(ctx context.Context, resolver HostResolver) (data *resolveData, err addrerr.AddressError) {
+	var addrs []*IPAddress
+	var resolveErr error
+	//note that validation handles empty address resolution
+	if err = host.Validate(); err != nil { //addrerr.HostNameError
+		return
+	}
+	// http://networkbit.ch/golang-dns-lookup/
+	parsedHost := host.parsedHost
+	if parsedHost.isAddressString() {
+		addr, addrErr := parsedHost.asAddress() //addrerr.IncompatibleAddressError
+		addrs, resolveErr = []*IPAddress{addr}, addrErr
+		//note there is no need to apply prefix or mask here, it would have been applied to the address already
+	} else {
+		strHost := parsedHost.getHost()
+		validationOptions := host.GetValidationOptions()
+		if len(strHost) == 0 {
+			addrs = []*IPAddress{}
 		} else {
-			strHost := parsedHost.getHost()
-			validationOptions := host.GetValidationOptions()
-			if len(strHost) == 0 {
-				addrs = []*IPAddress{}
-			} else {
-				var ips []net.IP
-				ips, lookupErr := net.LookupIP(strHost)
-				if lookupErr != nil {
-					//Note we do not set resolveData, so we will attempt to resolve again
-					err = &hostNameNestedError{nested: lookupErr,
-						hostNameError: hostNameError{addressError{str: strHost, key: "ipaddress.host.error.host.resolve"}}}
-					return
+			ipAddrs, lookupErr := resolver.LookupIPAddr(ctx, strHost)
+			if lookupErr != nil {
+				//Note we do not set resolveData, so we will attempt to resolve again
+				err = &hostNameNestedError{nested: lookupErr,
+					hostNameError: hostNameError{addressError{str: strHost, key: "ipaddress.host.error.host.resolve"}}}
+				return
+			}
+			count := len(ipAddrs)
+			addrs = make([]*IPAddress, 0, count)
+			var errs []addrerr.AddressError
+			for j := 0; j < count; j++ {
+				ip := ipAddrs[j].IP
+				if ipv4 := ip.To4(); ipv4 != nil {
+					ip = ipv4
 				}
-				count := len(ips)
-				addrs = make([]*IPAddress, 0, count)
-				var errs []addrerr.AddressError
-				for j := 0; j < count; j++ {
-					ip := ips[j]
-					if ipv4 := ip.To4(); ipv4 != nil {
-						ip = ipv4
-					}
-					networkPrefixLength := parsedHost.getNetworkPrefixLen()
-					byteLen := len(ip)
-					if networkPrefixLength == nil {
-						mask := parsedHost.getMask()
-						if mask != nil {
-							maskBytes := mask.Bytes()
-							if len(maskBytes) == byteLen {
-								for i := 0; i < byteLen; i++ {
-									ip[i] &= maskBytes[i]
-								}
-								networkPrefixLength = mask.GetBlockMaskPrefixLen(true)
+				networkPrefixLength := parsedHost.getNetworkPrefixLen()
+				byteLen := len(ip)
+				if networkPrefixLength == nil {
+					mask := parsedHost.getMask()
+					if mask != nil {
+						maskBytes := mask.Bytes()
+						if len(maskBytes) == byteLen {
+							for i := 0; i < byteLen; i++ {
+								ip[i] &= maskBytes[i]
 							}
+							networkPrefixLength = mask.GetBlockMaskPrefixLen(true)
 						}
 					}
-					ipAddr, addrErr := NewIPAddressFromPrefixedNetIP(ip, networkPrefixLength)
-					if addrErr != nil {
-						errs = append(errs, addrErr)
-					} else {
-						cache := ipAddr.cache
-						if cache != nil {
-							cache.identifierStr = &identifierStr{host}
-						}
-						addrs = append(addrs, ipAddr)
-					}
 				}
-				if len(errs) > 0 {
-					err = &mergedError{AddressError: &hostNameError{addressError{str: strHost, key: "ipaddress.host.error.host.resolve"}}, merged: errs}
+				ipAddr, addrErr := NewIPAddressFromPrefixedNetIP(ip, networkPrefixLength)
+				if addrErr != nil {
+					errs = append(errs, addrErr)
+				} else {
+					cache := ipAddr.cache
+					if cache != nil {
+						cache.identifierStr = &identifierStr{host}
+					}
+					addrs = append(addrs, ipAddr)
 				}
-				count = len(addrs)
-				if count > 0 {
-					// sort by preferred version
-					preferredVersion := IPVersion(validationOptions.GetPreferredVersion())
-					if !preferredVersion.IsIndeterminate() {
-						preferredAddrType := preferredVersion.toType()
-						boundaryCase := 8 // we sort differently based on list size
-						if count > boundaryCase {
-							c := 0
-							newAddrs := make([]*IPAddress, count)
-							for _, val := range addrs {
-								if val.getAddrType() == preferredAddrType {
-									newAddrs[c] = val
-									c++
-								}
+			}
+			if len(errs) > 0 {
+				resolveErr = &mergedError{AddressError: &hostNameError{addressError{str: strHost, key: "ipaddress.host.error.host.resolve"}}, merged: errs}
+			}
+			count = len(addrs)
+			if count > 0 {
+				// sort by preferred version
+				preferredVersion := IPVersion(validationOptions.GetPreferredVersion())
+				if !preferredVersion.IsIndeterminate() {
+					preferredAddrType := preferredVersion.toType()
+					boundaryCase := 8 // we sort differently based on list size
+					if count > boundaryCase {
+						c := 0
+						newAddrs := make([]*IPAddress, count)
+						for _, val := range addrs {
+							if val.getAddrType() == preferredAddrType {
+								newAddrs[c] = val
+								c++
 							}
-							for i := 0; c < count; i++ {
-								val := addrs[i]
-								if val.getAddrType() != preferredAddrType {
-									newAddrs[c] = val
-									c++
-								}
+						}
+						for i := 0; c < count; i++ {
+							val := addrs[i]
+							if val.getAddrType() != preferredAddrType {
+								newAddrs[c] = val
+								c++
 							}
-							addrs = newAddrs
-						} else {
-							preferredIndex := 0
-						top:
-							for i := 0; i < count; i++ {
-								val := addrs[i]
-								if val.getAddrType() != preferredAddrType {
-									var j int
-									if preferredIndex == 0 {
-										j = i + 1
-									} else {
-										j = preferredIndex
-									}
-									for ; j < len(addrs); j++ {
-										if addrs[j].getAddrType() == preferredAddrType {
-											// move the preferred into the non-preferred's spot
-											addrs[i] = addrs[j]
-											// don't swap so the non-preferred order is preserved,
-											// instead shift each upwards by one spot
-											k := i + 1
-											for ; k < j; k++ {
-												addrs[k], val = val, addrs[k]
-											}
-											addrs[k] = val
-											preferredIndex = j + 1
-											continue top
+						}
+						addrs = newAddrs
+					} else {
+						preferredIndex := 0
+					top:
+						for i := 0; i < count; i++ {
+							val := addrs[i]
+							if val.getAddrType() != preferredAddrType {
+								var j int
+								if preferredIndex == 0 {
+									j = i + 1
+								} else {
+									j = preferredIndex
+								}
+								for ; j < len(addrs); j++ {
+									if addrs[j].getAddrType() == preferredAddrType {
+										// move the preferred into the non-preferred's spot
+										addrs[i] = addrs[j]
+										// don't swap so the non-preferred order is preserved,
+										// instead shift each upwards by one spot
+										k := i + 1
+										for ; k < j; k++ {
+											addrs[k], val = val, addrs[k]
 										}
+										addrs[k] = val
+										preferredIndex = j + 1
+										continue top
 									}
-									// no more preferred so nothing more to do
-									break
 								}
+								// no more preferred so nothing more to do
+								break
 							}
 						}
 					}
 				}
 			}
 		}
-		data = &resolveData{addrs, err}
-		dataLoc := (*unsafe.Pointer)(unsafe.Pointer(&host.resolveData))
-		atomicStorePointer(dataLoc, unsafe.Pointer(data))
 	}
-	return data.resolvedAddrs, nil
+	return &resolveData{addrs, resolveErr}, nil
 }
 
 // IsValid returns whether this represents a valid host name or IP address format.
@@ -586,6 +832,73 @@ func (host *HostName) toNormalizedString(wildcard, addTrailingDot bool) string {
 	return host.str
 }
 
+// ToURLHostString produces the host and port, if any, in the form used in the authority component of URL strings.
+// IPv6 addresses are enclosed in square brackets, with any zone percent-encoded as described by RFC 6874,
+// so that "fe80::1%eth0" with port 8080 becomes "[fe80::1%25eth0]:8080".
+// Prefix lengths, masks and service names are not included.
+//
+// The Host field of url.URL holds the zone without the encoding, which is added when the URL is converted to a string,
+// so when populating a url.URL use ToNormalizedString or ToHostPortString instead.
+// If this host name is not valid, the original string is returned.
+//
+// An error is returned if this host is a subnet rather than a single address, since a URL cannot represent a subnet.
+func (host *HostName) ToURLHostString() (string, addrerr.IncompatibleAddressError) {
+	host = host.init()
+	if !host.IsValid() {
+		return host.str, nil
+	}
+	var builder strings.Builder
+	if host.IsAddress() {
+		addr := host.AsAddress().WithoutPrefixLen()
+		if addr.IsMultiple() {
+			return "", &incompatibleAddressError{addressError{key: "ipaddress.error.single.address.required"}}
+		} else if addr.isIPv6() {
+			builder.WriteByte(IPv6StartBracket)
+			builder.WriteString(addr.ToURIString())
+			builder.WriteByte(IPv6EndBracket)
+		} else {
+			builder.WriteString(addr.ToURIString())
+		}
+	} else if host.IsAddressString() {
+		addrStr := host.AsAddressString()
+		if addrStr.IsAllAddresses() {
+			return "", &incompatibleAddressError{addressError{key: "ipaddress.error.single.address.required"}}
+		}
+		builder.WriteString(addrStr.ToNormalizedString())
+	} else {
+		builder.WriteString(host.parsedHost.getHost())
+	}
+	if port := host.parsedHost.getPort(); port != nil {
+		toNormalizedPortString(port.portNum(), &builder)
+	}
+	return builder.String(), nil
+}
+
+// toHostPortString produces the socket address string, bracketing IPv6 addresses while leaving any zone unescaped inside the brackets.
+// It produces only strings that SplitHostPort accepts, returning an error for the zero IPAddress, for subnets, and for port 0.
+func toHostPortString(addr *IPAddress, port PortInt) (string, addrerr.IncompatibleAddressError) {
+	if addr.getAddrType().isZeroSegments() {
+		return "", &incompatibleAddressError{addressError{key: "ipaddress.error.ipVersionIndeterminate"}}
+	} else if addr.IsMultiple() {
+		return "", &incompatibleAddressError{addressError{key: "ipaddress.error.single.address.required"}}
+	} else if port == 0 {
+		return "", &incompatibleAddressError{addressError{key: "ipaddress.host.error.invalidPort.zero"}}
+	}
+	builder := strings.Builder{}
+	str := addr.WithoutPrefixLen().ToCanonicalString()
+	if addr.isIPv6() {
+		builder.Grow(len(str) + 8)
+		builder.WriteByte(IPv6StartBracket)
+		builder.WriteString(str)
+		builder.WriteByte(IPv6EndBracket)
+	} else {
+		builder.Grow(len(str) + 6)
+		builder.WriteString(str)
+	}
+	toNormalizedPortString(port, &builder)
+	return builder.String(), nil
+}
+
 func toNormalizedPortString(port PortInt, builder *strings.Builder) {
 	builder.WriteByte(PortSeparator)
 	toUnsignedString(uint64(port), 10, builder)
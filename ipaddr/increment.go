@@ -86,9 +86,9 @@ func fastIncrement( // used by IPv6
 		maxUint64.SetUint64(math.MaxUint64)
 		countMinus1 := count.Sub(count, bigOneConst())
 		if countMinus1.CmpAbs(&maxUint64) <= 0 {
-			longCount := count.Uint64()
-			if longCount > uincrement {
-				if longCount == uincrement+1 {
+			longCountMinus1 := countMinus1.Uint64()
+			if longCountMinus1 >= uincrement {
+				if longCountMinus1 == uincrement {
 					return upperProducer()
 				}
 				return incrementRange(section, inc, lowerProducer, prefixLength)
@@ -259,3 +259,108 @@ func add(section *AddressSection, fullValue uint64, increment int64, creator add
 		prefixLength)
 	return createSection(newSegs, prefixLength, section.getAddrType())
 }
+
+// incrementBigInt increments by an arbitrary amount, returning nil on overflow or underflow
+func incrementBigInt(
+	section *AddressSection,
+	increment *big.Int,
+	creator addressSegmentCreator,
+	lowerProducer,
+	upperProducer func() *AddressSection,
+	prefixLength PrefixLen) *AddressSection {
+	isMultiple := section.isMultiple()
+	sign := increment.Sign()
+	if sign == 0 && !isMultiple {
+		return section
+	} else if sign < 0 {
+		if section.GetValue().CmpAbs(increment) < 0 {
+			return nil
+		}
+		return addBig(lowerProducer(), increment, creator, prefixLength)
+	}
+	if isMultiple {
+		count := section.GetCount()
+		if increment.CmpAbs(count) < 0 {
+			return incrementRangeBig(section, increment, lowerProducer, prefixLength)
+		}
+		increment = new(big.Int).Sub(increment, count.Sub(count, bigOneConst()))
+		section = upperProducer()
+	}
+	newValue := section.GetValue()
+	if newValue.Add(newValue, increment).BitLen() > int(section.GetBitCount()) {
+		return nil
+	}
+	return addBig(section, increment, creator, prefixLength)
+}
+
+// incrementRangeBig is the same as incrementRange, but for increments that may not fit into an int64
+func incrementRangeBig(
+	section *AddressSection,
+	increment *big.Int,
+	lowerProducer func() *AddressSection,
+	prefixLength PrefixLen) *AddressSection {
+	if increment.IsInt64() {
+		return incrementRange(section, increment.Int64(), lowerProducer, prefixLength)
+	}
+	segCount := section.GetSegmentCount()
+	newSegments := make([]*AddressDivision, segCount)
+	revolutions := new(big.Int).Set(increment)
+	var remainder, segRange big.Int
+	for i := segCount - 1; i >= 0; i-- {
+		seg := section.GetSegment(i)
+		segRange.SetUint64(uint64(seg.GetValueCount()))
+		revolutions.QuoRem(revolutions, &segRange, &remainder)
+		val := seg.getSegmentValue() + SegInt(remainder.Uint64())
+		segPrefixLength := getSegmentPrefixLength(section.GetBitsPerSegment(), prefixLength, i)
+		newSegments[i] = createAddressDivision(seg.deriveNewMultiSeg(val, val, segPrefixLength))
+		if revolutions.Sign() == 0 {
+			for i--; i >= 0; i-- {
+				original := section.GetSegment(i)
+				val = original.getSegmentValue()
+				segPrefixLength = getSegmentPrefixLength(section.GetBitsPerSegment(), prefixLength, i)
+				newSegments[i] = createAddressDivision(seg.deriveNewMultiSeg(val, val, segPrefixLength))
+			}
+			break
+		}
+	}
+	return createSection(newSegments, prefixLength, section.getAddrType())
+}
+
+// addToSegment adds the delta to the segment at the given index, carrying into the preceding segments.
+// It returns nil on overflow or underflow, when the carry differs across the values of a segment range, or when the index is out of range.
+func addToSegment(section *AddressSection, index int, delta int64, prefixLength PrefixLen) *AddressSection {
+	segCount := section.GetSegmentCount()
+	if index < 0 || index >= segCount {
+		// this includes every index of the zero section, which has no segments
+		return nil
+	} else if delta == 0 {
+		return section
+	}
+	segRange := int64(section.GetMaxSegmentValue()) + 1
+	newSegments := make([]*AddressDivision, segCount)
+	section.copySubDivisions(0, segCount, newSegments)
+	for i := index; delta != 0; i-- {
+		if i < 0 {
+			return nil
+		}
+		// use floored division, so that the remainder added to the segment values is never negative
+		carry, remainder := delta/segRange, delta%segRange
+		if remainder < 0 {
+			carry--
+			remainder += segRange
+		}
+		seg := section.GetSegment(i)
+		lower, upper := int64(seg.GetSegmentValue())+remainder, int64(seg.GetUpperSegmentValue())+remainder
+		if lowerCarries := lower >= segRange; lowerCarries != (upper >= segRange) {
+			return nil
+		} else if lowerCarries {
+			carry++
+			lower -= segRange
+			upper -= segRange
+		}
+		segPrefixLength := getSegmentPrefixLength(section.GetBitsPerSegment(), prefixLength, i)
+		newSegments[i] = createAddressDivision(seg.deriveNewMultiSeg(SegInt(lower), SegInt(upper), segPrefixLength))
+		delta = carry
+	}
+	return createSectionMultiple(newSegments, prefixLength, section.getAddrType(), section.isMultiple())
+}
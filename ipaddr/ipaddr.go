@@ -20,9 +20,11 @@ import (
 	"fmt"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrstr"
+	"hash"
 	"math/big"
 	"net"
 	"net/netip"
+	"strconv"
 	"strings"
 	"unsafe"
 )
@@ -46,6 +48,35 @@ const (
 	IPv6 IPVersion = "IPv6"
 )
 
+// Versions returns the determinate IP versions, IPv4 followed by IPv6.
+func Versions() []IPVersion {
+	return []IPVersion{IPv4, IPv6}
+}
+
+// ParseVersion parses the given string as an IP version.
+// Matching is case-insensitive and accepts either the version name, "IPv4" or "IPv6", or the version number alone, "4" or "6".
+// The empty string parses to IndeterminateIPVersion.  Any other string results in an error.
+func ParseVersion(str string) (IPVersion, addrerr.AddressStringError) {
+	switch len(str) {
+	case 0:
+		return IndeterminateIPVersion, nil
+	case 1:
+		if str[0] == '4' {
+			return IPv4, nil
+		} else if str[0] == '6' {
+			return IPv6, nil
+		}
+	case 4:
+		version := IPVersion(str)
+		if version.IsIPv4() {
+			return IPv4, nil
+		} else if version.IsIPv6() {
+			return IPv6, nil
+		}
+	}
+	return IndeterminateIPVersion, &addressStringError{addressError{str: str, key: "ipaddress.error.invalid.ipVersion"}}
+}
+
 // IsIPv6 returns true if this represents version 6
 func (version IPVersion) IsIPv6() bool {
 	return len(version) == 4 && strings.EqualFold(string(version), string(IPv6))
@@ -101,6 +132,27 @@ func (version IPVersion) String() string {
 	return string(version)
 }
 
+// MarshalText implements the encoding.TextMarshaler interface, writing "IPv4", "IPv6", or the empty string for an indeterminate version.
+// Mixed-case versions are written in their canonical form.
+func (version IPVersion) MarshalText() ([]byte, error) {
+	if version.IsIPv4() {
+		return []byte(IPv4), nil
+	} else if version.IsIPv6() {
+		return []byte(IPv6), nil
+	}
+	return []byte{}, nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface, accepting the same strings as ParseVersion.
+func (version *IPVersion) UnmarshalText(text []byte) error {
+	parsed, err := ParseVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*version = parsed
+	return nil
+}
+
 func (version IPVersion) GetNetwork() (network IPAddressNetwork) {
 	if version.IsIPv6() {
 		network = ipv6Network
@@ -315,6 +367,33 @@ func (addr *ipAddressInternal) isZeroHostLen(prefLen BitCount) bool {
 	return addr.getSection().IsZeroHostLen(prefLen)
 }
 
+// containsRange returns whether this address or subnet contains all the addresses in the given range
+func (addr *ipAddressInternal) containsRange(other *SequentialRange[*IPAddress]) bool {
+	if addr.IsSequential() {
+		return addr.contains(other.GetLower()) && addr.contains(other.GetUpper())
+	}
+	for _, block := range other.SpanWithSequentialBlocks() {
+		if !addr.contains(block) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsNonZeroHosts returns whether this address or subnet contains the addresses of the given subnet that are not zero hosts for the prefix length of the given subnet
+func (addr *ipAddressInternal) containsNonZeroHosts(other *IPAddress) bool {
+	prefLen := other.GetPrefixLen()
+	if prefLen == nil {
+		return addr.contains(other)
+	}
+	for _, remaining := range other.Subtract(addr.toIPAddress()) {
+		if !remaining.IsZeroHostLen(prefLen.bitCount()) {
+			return false
+		}
+	}
+	return true
+}
+
 // when boundariesOnly is true, there will be no error
 func (addr *ipAddressInternal) toZeroHost(boundariesOnly bool) (res *IPAddress, err addrerr.IncompatibleAddressError) {
 	section, err := addr.section.toIPAddressSection().toZeroHost(boundariesOnly)
@@ -352,6 +431,33 @@ func (addr *ipAddressInternal) toMaxHostLen(prefixLength BitCount) (res *IPAddre
 	return
 }
 
+// toHostRanges supplies the lower and upper bounds of the sequential ranges of the zero hosts, or the max hosts, of the individual addresses of this address or subnet.
+// Distinct zero hosts or max hosts are never adjacent when there are host bits, so each range is then a single address.
+func (addr *ipAddressInternal) toHostRanges(maxHost bool, rangeSupplier func(lower, upper *IPAddress)) {
+	var iter Iterator[*Address]
+	if prefLen := addr.getPrefixLen(); prefLen == nil {
+		iter = &singleIterator[*Address]{original: addr.toAddress()} // the whole address is the host
+	} else if prefLen.bitCount() >= addr.GetBitCount() {
+		for iter = addr.sequentialBlockIterator(); iter.HasNext(); {
+			block := iter.Next().ToIP()
+			rangeSupplier(block.GetLower(), block.GetUpper())
+		}
+		return
+	} else {
+		iter = addr.prefixIterator(false)
+	}
+	for iter.HasNext() {
+		prefix := iter.Next().ToIP()
+		var host *IPAddress
+		if maxHost {
+			host, _ = prefix.ToMaxHost()
+		} else {
+			host, _ = prefix.ToZeroHost()
+		}
+		rangeSupplier(host, host)
+	}
+}
+
 func (addr *ipAddressInternal) checkIdentity(section *IPAddressSection) *IPAddress {
 	if section == nil {
 		return nil
@@ -562,6 +668,19 @@ func (addr *ipAddressInternal) toSubnetString() string {
 	return addr.getSection().ToSubnetString()
 }
 
+func (addr *ipAddressInternal) toAddressMaskString() string {
+	ipAddr := addr.toIPAddress()
+	if addr.getAddrType().isZeroSegments() {
+		// the zero IPAddress has no network mask
+		return ipAddr.toCanonicalString()
+	}
+	host := ipAddr
+	if ipAddr.IsPrefixBlock() {
+		host = ipAddr.GetLower()
+	}
+	return host.WithoutPrefixLen().ToCanonicalString() + " " + ipAddr.GetNetworkMask().WithoutPrefixLen().ToCanonicalString()
+}
+
 func (addr *ipAddressInternal) toCompressedWildcardString() string {
 	if addr.hasZone() {
 		cache := addr.getStringCache()
@@ -887,6 +1006,8 @@ func (addr *IPAddress) IsMultiple() bool {
 // Format implements [fmt.Formatter] interface. It accepts the formats
 //  - 'v' for the default address and section format (either the normalized or canonical string),
 //  - 's' (string) for the same,
+//  - 'S' (uppercase string) for the same string with uppercase hexadecimal digits,
+//  - 'r' (reverse DNS) for the reverse-DNS lookup string of IP addresses,
 //  - 'b' (binary), 'o' (octal with 0 prefix), 'O' (octal with 0o prefix),
 //  - 'd' (decimal), 'x' (lowercase hexadecimal), and
 //  - 'X' (uppercase hexadecimal).
@@ -896,6 +1017,10 @@ func (addr *IPAddress) IsMultiple() bool {
 // a leading "0x" or "0X" for "%#x" and "%#X" respectively.
 // Also supported is specification of minimum digits precision, output field width,
 // space or zero padding, and '-' for left or right justification.
+//
+// With 's' and 'S', the '+' flag selects the fully expanded string, as provided by ToFullString,
+// while the '#' flag selects the mixed IPv6/IPv4 string provided by ToMixedString for IPv6.
+// Combining the two, as in "%+#s", produces the mixed string with expanded IPv6 segments.
 func (addr IPAddress) Format(state fmt.State, verb rune) {
 	addr.init().format(state, verb)
 }
@@ -986,6 +1111,19 @@ func (addr *IPAddress) GetSegments() []*IPAddressSegment {
 	return addr.GetSection().GetSegments()
 }
 
+// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+// as much as can be fit into the slice, returning the number of segments copied.
+func (addr *IPAddress) CopyGenericSegments(segs []AddressSegmentType) (count int) {
+	return addr.GetSection().CopyGenericSegments(segs)
+}
+
+// GetGenericSegments returns a slice with the address segments as AddressSegmentType,
+// allowing all segment types to be represented by a single type.
+// The returned slice is not backed by the same array as this address.
+func (addr *IPAddress) GetGenericSegments() []AddressSegmentType {
+	return addr.GetSection().GetGenericSegments()
+}
+
 // GetSegment returns the segment at the given index.
 // The first segment is at index 0.
 // GetSegment will panic given a negative index or an index matching or larger than the segment count.
@@ -1004,6 +1142,20 @@ func (addr *IPAddress) ForEachSegment(consumer func(segmentIndex int, segment *I
 	return addr.GetSection().ForEachSegment(consumer)
 }
 
+// ForEachSegmentValue visits each segment in order from most-significant to least, the most significant with index 0,
+// calling the given function with the lower and upper values of each and the segment prefix length, terminating early if the function returns true.
+// Unlike ForEachSegment, the segments themselves are not provided, and unlike GetSegments, no allocation is required.
+// Returns the number of visited segments.
+func (addr *IPAddress) ForEachSegmentValue(consumer func(segmentIndex int, value, upperValue SegInt, segmentPrefixLen PrefixLen) (stop bool)) int {
+	return addr.GetSection().ForEachSegmentValue(consumer)
+}
+
+// SegmentValuesAppend appends the lower segment values, the segment values of the lowest address in this subnet, to the given slice, returning the extended slice.
+// When the slice has sufficient capacity, no allocation is required.
+func (addr *IPAddress) SegmentValuesAppend(values []SegInt) []SegInt {
+	return addr.GetSection().SegmentValuesAppend(values)
+}
+
 // GetGenericDivision returns the segment at the given index as a DivisionType.
 func (addr *IPAddress) GetGenericDivision(index int) DivisionType {
 	return addr.getDivision(index)
@@ -1095,6 +1247,20 @@ func (addr *IPAddress) ToZeroHost() (*IPAddress, addrerr.IncompatibleAddressErro
 	return addr.init().toZeroHost(false)
 }
 
+// ToZeroHostRanges returns the sequential ranges of the addresses obtained by converting each individual address of this address or subnet to the zero host, the address with the same prefix and a host of zero,
+// the host being the bits following the prefix length.
+// If the address or subnet has no prefix length, then it returns a single range with the all-zero address.
+//
+// Unlike ToZeroHost, this does not return an error when the converted addresses do not form a subnet.
+// Since distinct zero hosts differ by at least 2 when there are host bits, the returned ranges are single addresses, one for each prefix of this subnet.
+// The ranges are in increasing order.
+func (addr *IPAddress) ToZeroHostRanges() (res []*SequentialRange[*IPAddress]) {
+	addr.init().toHostRanges(false, func(lower, upper *IPAddress) {
+		res = append(res, NewSequentialRange(lower, upper))
+	})
+	return
+}
+
 // ToZeroHostLen converts the address or subnet to one in which all individual addresses have a host of zero,
 // the host being the bits following the given prefix length.
 // If this address or subnet has the same prefix length, then the returned one will too, otherwise the returned series will have no prefix length.
@@ -1138,6 +1304,20 @@ func (addr *IPAddress) ToMaxHost() (*IPAddress, addrerr.IncompatibleAddressError
 	return addr.init().toMaxHost()
 }
 
+// ToMaxHostRanges returns the sequential ranges of the addresses obtained by converting each individual address of this address or subnet to the max host, the address with the same prefix and a host of all one-bits,
+// the host being the bits following the prefix length.
+// If the address or subnet has no prefix length, then it returns a single range with the max address.
+//
+// Unlike ToMaxHost, this does not return an error when the converted addresses do not form a subnet.
+// Since distinct max hosts differ by at least 2 when there are host bits, the returned ranges are single addresses, one for each prefix of this subnet.
+// The ranges are in increasing order.
+func (addr *IPAddress) ToMaxHostRanges() (res []*SequentialRange[*IPAddress]) {
+	addr.init().toHostRanges(true, func(lower, upper *IPAddress) {
+		res = append(res, NewSequentialRange(lower, upper))
+	})
+	return
+}
+
 // ToMaxHostLen converts the address or subnet to one in which all individual addresses have a host of all one-bits, the max host,
 // the host being the bits following the given prefix length.
 // If this address or subnet has the same prefix length, then the resulting one will too, otherwise the resulting address or subnet will have no prefix length.
@@ -1161,6 +1341,16 @@ func (addr *IPAddress) ToPrefixBlock() *IPAddress {
 	return addr.init().toPrefixBlock().ToIP()
 }
 
+// ToPrefixBlockAndHost returns both the prefix block provided by ToPrefixBlock and the host section provided by GetHostSection.
+// The host section, whose value is the offset of this address within the block, has the bits of this address beyond the prefix length.
+// If this address has no prefix length, the block is this address and the host section is the full section.
+//
+// For example, if the address is "1.2.3.4/16" it returns the subnet "1.2.0.0/16" and the host section "3.4".
+func (addr *IPAddress) ToPrefixBlockAndHost() (block *IPAddress, host *IPAddressSection) {
+	addr = addr.init()
+	return addr.toPrefixBlock().ToIP(), addr.GetSection().GetHostSection()
+}
+
 // ToPrefixBlockLen returns the subnet associated with the given prefix length.
 //
 // The subnet will include all addresses with the same prefix as this one, the prefix "block" for that prefix length.
@@ -1400,6 +1590,21 @@ func (addr *IPAddress) CopyUpperBytes(bytes []byte) []byte {
 	return addr.init().section.CopyUpperBytes(bytes)
 }
 
+// AppendBytes appends the value of the lowest individual address in the subnet to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices, so a buffer can be reused when encoding many addresses, as in packet encoders.
+// In contrast, Bytes and UpperBytes return a new slice with each call, while CopyBytes and CopyUpperBytes copy from bytes that are calculated and cached by the first call of any of those four methods.
+func (addr *IPAddress) AppendBytes(bytes []byte) []byte {
+	return addr.init().section.AppendBytes(bytes)
+}
+
+// AppendUpperBytes appends the value of the highest individual address in the subnet to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (addr *IPAddress) AppendUpperBytes(bytes []byte) []byte {
+	return addr.init().section.AppendUpperBytes(bytes)
+}
+
 // IsMax returns whether this address matches exactly the maximum possible value, the address whose bits are all ones.
 func (addr *IPAddress) IsMax() bool {
 	return addr.init().section.IsMax()
@@ -1446,6 +1651,53 @@ func (addr *IPAddress) Contains(other AddressType) bool {
 	return addr.init().contains(other)
 }
 
+// ContainsNonZeroHosts returns whether this address or subnet contains the non-zero host addresses of the given subnet,
+// all the addresses of the given subnet other than those whose host is zero for the prefix length of the given subnet.
+// The zero host of a prefix block is its network address, which is often not assigned to any host.
+//
+// If the given subnet has no prefix length, this is the same as Contains.
+func (addr *IPAddress) ContainsNonZeroHosts(other *IPAddress) bool {
+	if other == nil {
+		return true
+	} else if addr == nil {
+		return false
+	}
+	return addr.init().containsNonZeroHosts(other)
+}
+
+// Relation returns the relation of this address or subnet to the given address or subnet,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+// Addresses and subnets of different types or versions are disjoint.
+func (addr *IPAddress) Relation(other AddressType) Relation {
+	if addr == nil {
+		if other == nil || other.ToAddressBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return addr.init().relation(other)
+}
+
+// IsContainedBy returns whether this is the same type and version as the given address or subnet and whether it is contained by the given address or subnet.
+// It is the reverse of Contains.
+func (addr *IPAddress) IsContainedBy(other AddressType) bool {
+	if other == nil {
+		return addr == nil
+	}
+	return other.ToAddressBase().Contains(addr)
+}
+
+// ContainsRange returns whether this is the same version as the given sequential range and whether it contains all addresses in the given range.
+// The addresses in this subnet need not be sequential.
+func (addr *IPAddress) ContainsRange(other IPAddressSeqRangeType) bool {
+	if addr == nil {
+		return other == nil || other.ToIP() == nil
+	} else if other == nil || other.ToIP() == nil {
+		return true
+	}
+	return addr.init().containsRange(other.ToIP())
+}
+
 // Compare returns a negative integer, zero, or a positive integer if this address or subnet is less than, equal, or greater than the given item.
 // Any address item is comparable to any other.  All address items use CountComparator to compare.
 func (addr *IPAddress) Compare(item AddressItem) int {
@@ -1598,6 +1850,72 @@ func (addr *IPAddress) ToIPv4() *IPv4Address {
 	return nil
 }
 
+// IsIPv4Convertible returns whether this address or subnet is IPv4, or can be converted to IPv4 by the converter registered with SetAddressConverter.
+// If true, ConvertToIPv4 returns non-nil.
+func (addr *IPAddress) IsIPv4Convertible() bool {
+	return addr != nil && GetAddressConverter().IsIPv4Convertible(addr.init())
+}
+
+// IsIPv6Convertible returns whether this address or subnet is IPv6, or can be converted to IPv6 by the converter registered with SetAddressConverter.
+// If true, ConvertToIPv6 returns non-nil.
+func (addr *IPAddress) IsIPv6Convertible() bool {
+	return addr != nil && GetAddressConverter().IsIPv6Convertible(addr.init())
+}
+
+// ConvertToIPv4 returns this address or subnet if IPv4, or otherwise converts it to IPv4 using the converter registered with SetAddressConverter,
+// which by default converts IPv4-mapped IPv6 addresses.  It returns nil if the address cannot be converted.
+//
+// Unlike ToIPv4, which only converts the type of addresses that are already IPv4, ConvertToIPv4 can map IPv6 addresses to IPv4.
+func (addr *IPAddress) ConvertToIPv4() *IPv4Address {
+	if addr == nil {
+		return nil
+	}
+	return GetAddressConverter().ToIPv4(addr.init())
+}
+
+// ConvertToIPv6 returns this address or subnet if IPv6, or otherwise converts it to IPv6 using the converter registered with SetAddressConverter,
+// which by default converts to IPv4-mapped IPv6 addresses.  It returns nil if the address cannot be converted.
+//
+// Unlike ToIPv6, which only converts the type of addresses that are already IPv6, ConvertToIPv6 can map IPv4 addresses to IPv6.
+func (addr *IPAddress) ConvertToIPv6() *IPv6Address {
+	if addr == nil {
+		return nil
+	}
+	return GetAddressConverter().ToIPv6(addr.init())
+}
+
+// convertToVersion converts the given address to the IP version of this address using the registered converter, returning nil if it cannot be converted
+func (addr *IPAddress) convertToVersion(other *IPAddress) *IPAddress {
+	if addr.IsIPv4() {
+		return other.ConvertToIPv4().ToIP()
+	}
+	return other.ConvertToIPv6().ToIP()
+}
+
+// ContainsConverted returns whether this address or subnet contains all addresses in the given address or subnet,
+// after converting the given address to the IP version of this address with the converter registered with SetAddressConverter when the versions differ.
+// It returns false if the given address cannot be converted.
+func (addr *IPAddress) ContainsConverted(other *IPAddress) bool {
+	if addr == nil || other == nil {
+		return addr.Contains(other)
+	}
+	addr = addr.init()
+	converted := addr.convertToVersion(other.init())
+	return converted != nil && addr.Contains(converted)
+}
+
+// EqualConverted returns whether this address or subnet is equal to the given address or subnet,
+// after converting the given address to the IP version of this address with the converter registered with SetAddressConverter when the versions differ.
+// It returns false if the given address cannot be converted.
+func (addr *IPAddress) EqualConverted(other *IPAddress) bool {
+	if addr == nil || other == nil {
+		return addr.Equal(other)
+	}
+	addr = addr.init()
+	converted := addr.convertToVersion(other.init())
+	return converted != nil && addr.Equal(converted)
+}
+
 // Wrap wraps this IP address, returning a WrappedIPAddress, an implementation of ExtendedIPSegmentSeries,
 // which can be used to write code that works with both IP addresses and IP address sections.
 // Wrap can be called with a nil receiver, wrapping a nil address.
@@ -1632,6 +1950,18 @@ func (addr *IPAddress) Iterator() Iterator[*IPAddress] {
 	return ipAddrIterator{addr.init().addrIterator(nil)}
 }
 
+// PermutationIterator provides an iterator to iterate through the individual addresses of this address or subnet in a pseudo-random order determined by the given seed.
+// Each individual address is visited exactly once, and the same seed always produces the same order.
+// The order is produced by a Feistel network permuting the indices of the iteration, so the addresses are not collected in advance.
+//
+// As with Iterator, the prefix length is preserved.
+func (addr *IPAddress) PermutationIterator(seed int64) Iterator[*IPAddress] {
+	if addr == nil {
+		return addr.Iterator()
+	}
+	return newPermutationIterator[*IPAddress](addr.init(), seed)
+}
+
 // PrefixIterator provides an iterator to iterate through the individual prefixes of this subnet,
 // each iterated element spanning the range of values for its prefix.
 //
@@ -1757,6 +2087,38 @@ func (addr *IPAddress) Increment(increment int64) *IPAddress {
 	return addr.init().increment(increment).ToIP()
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with IPv6, where the count of addresses in a subnet can be much larger than the range of int64.
+//
+// On address overflow or underflow, IncrementBig returns nil.
+func (addr *IPAddress) IncrementBig(increment *big.Int) *IPAddress {
+	return addr.init().incrementBig(increment).ToIP()
+}
+
+// Enumerate returns the index of the given individual address within the iteration order of this address or subnet,
+// which is the increment that, when supplied to IncrementBig, produces the given address from this subnet.
+// If the given address is not an individual address contained by this address or subnet, then nil is returned.
+func (addr *IPAddress) Enumerate(other AddressType) *big.Int {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().enumerate(other)
+}
+
+// Distance returns the numeric difference between the lowest address of the given address or subnet and the lowest address of this address or subnet.
+// For individual addresses, this is the increment which, when supplied to IncrementBig, produces the given address from this one.
+// The result is negative when the given address is lower than this one.
+//
+// If the given address is a different version than this, then nil is returned.
+func (addr *IPAddress) Distance(other *IPAddress) *big.Int {
+	addr = addr.init()
+	other = other.init()
+	if addr.getAddrType() != other.getAddrType() {
+		return nil
+	}
+	return addr.distance(&other.addressInternal)
+}
+
 // SpanWithRange returns an IPAddressSeqRange instance that spans this subnet to the given subnet.
 // If the other address is a different version than this, then the other is ignored, and the result is equivalent to calling ToSequentialRange.
 func (addr *IPAddress) SpanWithRange(other *IPAddress) *SequentialRange[*IPAddress] {
@@ -1817,6 +2179,58 @@ func (addr *IPAddress) bitwiseOrPrefixed(other *IPAddress, retainPrefix bool) (*
 	return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipMismatch"}}
 }
 
+// BitwiseXor does the bitwise exclusive disjunction with this address or subnet, flipping the bits that are one-bits in the given address.
+// Applied with the all-ones address it converts a network mask to the corresponding wildcard mask, and back.
+//
+// The operation is applied to all individual addresses and the result is returned.
+//
+// If the given address is a different version than this, then an error is returned.
+//
+// If this is a subnet representing multiple addresses, and applying the operation to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (addr *IPAddress) BitwiseXor(other *IPAddress) (masked *IPAddress, err addrerr.IncompatibleAddressError) {
+	if thisAddr := addr.ToIPv4(); thisAddr != nil {
+		if oth := other.ToIPv4(); oth != nil {
+			result, err := thisAddr.BitwiseXor(oth)
+			return result.ToIP(), err
+		}
+	} else if thisAddr := addr.ToIPv6(); thisAddr != nil {
+		if oth := other.ToIPv6(); oth != nil {
+			result, err := thisAddr.BitwiseXor(oth)
+			return result.ToIP(), err
+		}
+	}
+	return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipMismatch"}}
+}
+
+// MaskLen applies the network mask of the given prefix length to all addresses represented by this address, returning the result.
+// It is equivalent to Mask with the network mask of the same version and bit count, so unlike Mask there is no version mismatch.
+//
+// If this represents multiple addresses, and applying the mask to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (addr *IPAddress) MaskLen(prefLen BitCount) (masked *IPAddress, err addrerr.IncompatibleAddressError) {
+	if thisAddr := addr.ToIPv4(); thisAddr != nil {
+		result, err := thisAddr.MaskLen(prefLen)
+		return result.ToIP(), err
+	} else if thisAddr := addr.ToIPv6(); thisAddr != nil {
+		result, err := thisAddr.MaskLen(prefLen)
+		return result.ToIP(), err
+	}
+	return addr, nil
+}
+
+// InvertMask flips all the bits of this address, converting a network mask such as a subnet mask to the corresponding wildcard mask, and vice versa.
+// Wildcard masks are used in place of network masks by Cisco access control lists and OSPF configurations.
+// The prefix length is dropped from the result.
+func (addr *IPAddress) InvertMask() *IPAddress {
+	if thisAddr := addr.ToIPv4(); thisAddr != nil {
+		return thisAddr.InvertMask().ToIP()
+	} else if thisAddr := addr.ToIPv6(); thisAddr != nil {
+		return thisAddr.InvertMask().ToIP()
+	}
+	return addr
+}
+
 // Intersect returns the subnet whose addresses are found in both this and the given subnet argument, or nil if no such addresses exist.
 //
 // This is also known as the conjunction of the two sets of addresses.
@@ -1829,6 +2243,9 @@ func (addr *IPAddress) Intersect(other *IPAddress) *IPAddress {
 		if oth := other.ToIPv6(); oth != nil {
 			return thisAddr.Intersect(oth).ToIP()
 		}
+	} else if versionsMatch(addr, other) {
+		// the zero IPAddress has no segments, and so it intersects only with itself
+		return addr.init()
 	}
 	return nil
 }
@@ -1912,6 +2329,40 @@ func (addr *IPAddress) IsMulticast() bool {
 	return false
 }
 
+// IsDocumentation returns whether this address or subnet is entirely within a block reserved for documentation,
+// such as "192.0.2.0/24" or "2001:db8::/32".
+func (addr *IPAddress) IsDocumentation() bool {
+	if thisAddr := addr.ToIPv4(); thisAddr != nil {
+		return thisAddr.IsDocumentation()
+	} else if thisAddr := addr.ToIPv6(); thisAddr != nil {
+		return thisAddr.IsDocumentation()
+	}
+	return false
+}
+
+// IsBenchmarking returns whether this address or subnet is entirely within a block reserved for benchmarking, "198.18.0.0/15" or "2001:2::/48".
+func (addr *IPAddress) IsBenchmarking() bool {
+	if thisAddr := addr.ToIPv4(); thisAddr != nil {
+		return thisAddr.IsBenchmarking()
+	} else if thisAddr := addr.ToIPv6(); thisAddr != nil {
+		return thisAddr.IsBenchmarking()
+	}
+	return false
+}
+
+// GetAddressClassification returns the classification of this address or subnet according to the IANA special-purpose address registries for its version.
+// A subnet whose addresses span more than one classification is classified as ClassMixed,
+// unless the subnet is within a single special-purpose block, such as "0.0.0.0/8", which is classified as ClassThisNetwork although it includes "0.0.0.0".
+// The zero IPAddress, which has no version, is classified as ClassNone.
+func (addr *IPAddress) GetAddressClassification() AddressClassification {
+	if thisAddr := addr.ToIPv4(); thisAddr != nil {
+		return thisAddr.GetAddressClassification()
+	} else if thisAddr := addr.ToIPv6(); thisAddr != nil {
+		return thisAddr.GetAddressClassification()
+	}
+	return ClassNone
+}
+
 func versionsMatch(one, two *IPAddress) bool {
 	return one.getAddrType() == two.getAddrType()
 }
@@ -1926,6 +2377,34 @@ func versionsMatch(one, two *IPAddress) bool {
 //	return true
 //}
 
+// IsAdjacentTo returns whether this address or subnet and the given address or subnet are contiguous, with no gap and no overlap,
+// which is to say the highest address of one immediately precedes the lowest address of the other.
+// It is equivalent to checking whether Relation returns RelationAdjacent.
+func (addr *IPAddress) IsAdjacentTo(other *IPAddress) bool {
+	return addr.Relation(other) == RelationAdjacent
+}
+
+// TryMergeWith returns the union of this address or subnet with the given address or subnet
+// when that union is a single sequential block, which is the case when the two are sequential blocks that are adjacent or overlapping.
+// Otherwise, it returns nil.
+//
+// Use MergeToSequentialBlocks or MergeToPrefixBlocks to merge subnets whose union spans more than one block.
+//
+// If this address is nil, the given address is returned when it is a single sequential block, as with the IPv4 and IPv6 versions of this method.
+func (addr *IPAddress) TryMergeWith(other *IPAddress) *IPAddress {
+	if other == nil {
+		return nil
+	} else if addr == nil {
+		addr = other
+	} else if !versionsMatch(addr, other) || !addr.isSameZone(other.ToAddressBase()) {
+		return nil
+	}
+	if blocks := addr.MergeToSequentialBlocks(other); len(blocks) == 1 {
+		return blocks[0]
+	}
+	return nil
+}
+
 // MergeToSequentialBlocks merges this with the list of addresses to produce the smallest array of sequential blocks.
 //
 // The resulting slice is sorted from lowest address value to highest, regardless of the size of each prefix block.
@@ -2071,6 +2550,19 @@ func (addr *IPAddress) GetSegmentStrings() []string {
 	return addr.init().getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (addr *IPAddress) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 //I considered changing to uppercase, see https://www.ieee802.org/1/files/public/docs2020/yangsters-smansfield-mac-address-format-0420-v01.pdf
 //and https://standards.ieee.org/wp-content/uploads/import/documents/tutorials/macgrp.pdf and https://en.wikipedia.org/wiki/MAC_address
 //canonicalParams = new MACStringOptions.Builder().setSeparator(MACAddress.DASH_SEGMENT_SEPARATOR).setUppercase(true).setExpandedSegments(true).setWildcards(new Wildcards(MACAddress.DASHED_SEGMENT_RANGE_SEPARATOR_STR, Address.SEGMENT_WILDCARD_STR, null)).toOptions();
@@ -2111,6 +2603,18 @@ func (addr *IPAddress) ToCanonicalWildcardString() string {
 	return addr.init().toCanonicalWildcardString()
 }
 
+// ToHostPortString produces the socket address string combining this address with the given port, such as "1.2.3.4:80" or "[2001:db8::1%eth0]:80".
+// IPv6 addresses are enclosed in square brackets, with any zone inside the brackets.  The prefix length, if any, is omitted.
+// The string can be parsed back with SplitHostPort.
+//
+// An error is returned for the zero IPAddress, which has no IP version, for subnets, and for port 0, none of which SplitHostPort can parse.
+func (addr *IPAddress) ToHostPortString(port uint16) (string, addrerr.IncompatibleAddressError) {
+	if addr == nil {
+		return nilString(), nil
+	}
+	return toHostPortString(addr.init(), PortInt(port))
+}
+
 // ToNormalizedString produces a normalized string for the address.
 //
 // For IPv4, it is the same as the canonical string.
@@ -2140,6 +2644,29 @@ func (addr *IPAddress) ToCompressedString() string {
 	return addr.init().toCompressedString()
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived addresses whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this address with WithoutStringCaching.
+func (addr *IPAddress) WarmStrings() {
+	if addr != nil {
+		addr.ToCanonicalString()
+		addr.ToNormalizedString()
+		addr.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address that does not cache the strings it produces, sharing the same segments.
+// See the WithoutStringCaching method of the address sections for the memory trade-offs of string caching.
+func (addr *IPAddress) WithoutStringCaching() *IPAddress {
+	if addr == nil {
+		return nil
+	}
+	addr = addr.init()
+	return createIPAddress(addr.section.withoutStringCaching(), addr.zone)
+}
+
 // ToNormalizedWildcardString produces a string similar to the normalized string but avoids the CIDR prefix length.
 // CIDR addresses will be shown with wildcards and ranges (denoted by '*' and '-') instead of using the CIDR prefix notation.
 func (addr *IPAddress) ToNormalizedWildcardString() string {
@@ -2186,6 +2713,57 @@ func (addr *IPAddress) ToReverseDNSString() (string, addrerr.IncompatibleAddress
 	return addr.init().toReverseDNSString()
 }
 
+// ToReverseDNSZoneStrings returns the names of the reverse-DNS zones that together cover this address or subnet.
+//
+// The covered prefix length is the prefix length of this address, or when there is no prefix length, the value of GetMinPrefixLenForBlock.
+// Since reverse-DNS zones are delegated on octet boundaries for IPv4 and nibble boundaries for IPv6,
+// that prefix length is rounded up to a multiple of 8 for IPv4 or 4 for IPv6, and there is one zone for each prefix block of the rounded length.
+//
+// For "1.2.16.0/20" it is the 16 zones "16.2.1.in-addr.arpa" to "31.2.1.in-addr.arpa".
+// For "2001:db8::/30" it is the 4 zones "8.b.d.0.1.0.0.2.ip6.arpa" to "b.b.d.0.1.0.0.2.ip6.arpa".
+//
+// Use ParseReverseDNSName to convert a zone name back to the prefix block.
+func (addr *IPAddress) ToReverseDNSZoneStrings() []string {
+	addr = addr.init()
+	var labelBits BitCount
+	var suffix string
+	if addr.IsIPv4() {
+		labelBits, suffix = IPv4BitsPerSegment, IPv4ReverseDnsSuffix
+	} else if addr.IsIPv6() {
+		labelBits, suffix = 4, IPv6ReverseDnsSuffix
+	} else {
+		return nil
+	}
+	var prefLen BitCount
+	if addr.IsPrefixed() {
+		prefLen = addr.getNetworkPrefixLen().bitCount()
+		addr = addr.ToPrefixBlock()
+	} else {
+		prefLen = addr.GetMinPrefixLenForBlock()
+	}
+	labelCount := (prefLen + labelBits - 1) / labelBits
+	var zones []string
+	for iter := addr.SetPrefixLen(labelCount * labelBits).PrefixBlockIterator(); iter.HasNext(); {
+		bytes := iter.Next().Bytes()
+		var builder strings.Builder
+		for i := int(labelCount) - 1; i >= 0; i-- {
+			if labelBits == IPv4BitsPerSegment {
+				builder.WriteString(strconv.Itoa(int(bytes[i])))
+			} else {
+				nibble := bytes[i>>1]
+				if i&1 == 0 {
+					nibble >>= 4
+				}
+				builder.WriteByte(digits[nibble&0xf])
+			}
+			builder.WriteByte(IPv4SegmentSeparator)
+		}
+		builder.WriteString(suffix[1:])
+		zones = append(zones, builder.String())
+	}
+	return zones
+}
+
 // ToPrefixLenString returns a string with a CIDR network prefix length if this address has a network prefix length.
 // For IPv6, a zero host section will be compressed with "::". For IPv4 the string is equivalent to the canonical string.
 func (addr *IPAddress) ToPrefixLenString() string {
@@ -2195,6 +2773,20 @@ func (addr *IPAddress) ToPrefixLenString() string {
 	return addr.init().toPrefixLenString()
 }
 
+// ToAddressMaskString produces a string with the address followed by its network mask, separated by a space,
+// the address-plus-netmask notation used in many network device configurations, such as "1.2.3.0 255.255.255.0".
+//
+// For a prefix block subnet, the address is the lowest address in the block, otherwise it is the canonical string without the prefix length.
+// Without a prefix length, the mask is the all-ones mask.
+// The string can be parsed by an IPAddressString when space-separated masks are allowed by the parse parameters, see AllowSpaceSeparatedMask in addrstrparam.IPAddressStringParamsBuilder.
+// The zero IPAddress, having no network mask, produces the same string as ToCanonicalString.
+func (addr *IPAddress) ToAddressMaskString() string {
+	if addr == nil {
+		return nilString()
+	}
+	return addr.init().toAddressMaskString()
+}
+
 // ToSubnetString produces a string with specific formats for subnets.
 // The subnet string looks like "1.2.*.*" or "1:2::/16".
 //
@@ -2264,6 +2856,20 @@ func (addr *IPAddress) ToUNCHostName() string {
 	return addr.ToCanonicalString()
 }
 
+// ToURIString produces the canonical string with the zone, if any, percent-encoded as in URIs, following RFC 6874.
+//
+// For IPv4 it is the canonical string.
+// For IPv6, the zone separator is encoded as "%25", and any zone characters reserved in URIs are percent-encoded,
+// so that "fe80::1%eth0" becomes "fe80::1%25eth0".
+func (addr *IPAddress) ToURIString() string {
+	if addr == nil {
+		return nilString()
+	} else if thisAddr := addr.ToIPv6(); thisAddr != nil {
+		return thisAddr.ToURIString()
+	}
+	return addr.ToCanonicalString()
+}
+
 // ToCustomString creates a customized string from this address or subnet according to the given string option parameters.
 func (addr *IPAddress) ToCustomString(stringOptions addrstr.IPStringOptions) string {
 	if addr == nil {
@@ -2384,6 +2990,15 @@ func (addr *IPAddress) toMinUpper() *IPAddress {
 	return addr.init().addressInternal.toMinUpper().ToIP()
 }
 
+// WriteToHash writes the canonical bytes of this address into the given hash, without allocating:
+// the address type, byte count, and the bytes of the lowest and highest values, followed by the zone for IPv6.
+// The prefix length is not written.  The same bytes are written by the keys for this address.  See HashWriterTo for details.
+func (addr *IPAddress) WriteToHash(h hash.Hash) {
+	if addr != nil {
+		addr.init().writeToHash(h)
+	}
+}
+
 // ToKey creates the associated address key.
 // While addresses can be compared with the Compare, TrieCompare or Equal methods as well as various provided instances of AddressComparator,
 // they are not comparable with Go operators.
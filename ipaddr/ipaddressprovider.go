@@ -32,12 +32,10 @@ import (
 type ipType int
 
 func fromVersion(version IPVersion) ipType {
-	switch version {
-	case IPv4:
+	if version.IsIPv4() {
 		return ipv4AddrType
-	case IPv6:
+	} else if version.IsIPv6() {
 		return ipv6AddrType
-	default:
 	}
 	return uninitializedType
 }
@@ -440,15 +438,15 @@ func (versioned *versionedAddressCreator) getParameters() addrstrparam.IPAddress
 }
 
 func (versioned *versionedAddressCreator) isProvidingIPAddress() bool {
-	return versioned.adjustedVersion != IndeterminateIPVersion
+	return !versioned.adjustedVersion.IsIndeterminate()
 }
 
 func (versioned *versionedAddressCreator) isProvidingIPv4() bool {
-	return versioned.adjustedVersion == IPv4
+	return versioned.adjustedVersion.IsIPv4()
 }
 
 func (versioned *versionedAddressCreator) isProvidingIPv6() bool {
-	return versioned.adjustedVersion == IPv6
+	return versioned.adjustedVersion.IsIPv6()
 }
 
 func (versioned *versionedAddressCreator) getProviderIPVersion() IPVersion {
@@ -620,15 +618,11 @@ func (adjusted *adjustedAddressCreator) getProviderHostAddress() (*IPAddress, ad
 }
 
 func newMaskCreator(options addrstrparam.IPAddressStringParams, adjustedVersion IPVersion, networkPrefixLength PrefixLen) *maskCreator {
-	if adjustedVersion == IndeterminateIPVersion {
+	if adjustedVersion.IsIndeterminate() {
 		adjustedVersion = IPVersion(options.GetPreferredVersion())
 	}
 	createVersionedMask := func(version IPVersion, prefLen PrefixLen, withPrefixLength bool) *IPAddress {
-		if version == IPv4 {
-			network := ipv4Network
-			return network.GetNetworkMask(prefLen.bitCount())
-		} else if version == IPv6 {
-			network := ipv6Network
+		if network := version.GetNetwork(); network != nil {
 			return network.GetNetworkMask(prefLen.bitCount())
 		}
 		return nil
@@ -704,7 +698,7 @@ func (all *allCreator) providerEquals(other ipAddressProvider) (bool, addrerr.In
 
 // providing **all** addresses of any IP version, ie "*", not "*.*" or "*:*"
 func (all *allCreator) isProvidingAllAddresses() bool {
-	return all.adjustedVersion == IndeterminateIPVersion
+	return all.adjustedVersion.IsIndeterminate()
 }
 
 func (all *allCreator) getProviderNetworkPrefixLen() PrefixLen {
@@ -164,6 +164,13 @@ var keyStrMap = map[string]int{
 	`ipaddress.host.error.invalid`:                             133,
 	`ipaddress.host.error.invalid.port.service`:                138,
 	`ipaddress.error.invalid.size`:                             25,
+	`ipaddress.error.invalid.family`:                           145,
+	`ipaddress.error.insufficient.space`:                       146,
+	`ipaddress.error.single.address.required`:                  147,
+	`ipaddress.host.error.invalidPort.zero`:                    148,
+	`ipaddress.error.duplicate.network`:                        149,
+	`ipaddress.error.nullAddress`:                              150,
+	`ipaddress.error.invalid.ipVersion`:                        151,
 }
 
 var strIndices = []int{
@@ -181,7 +188,8 @@ var strIndices = []int{
 	4339, 4377, 4435, 4465, 4500, 4546, 4611, 4641, 4669, 4715,
 	4736, 4784, 4952, 4973, 5023, 5046, 5081, 5146, 5175, 5229,
 	5246, 5272, 5336, 5367, 5379, 5427, 5465, 5572, 5629, 5677,
-	5692, 5733, 5808, 6003, 6045,
+	5692, 5733, 5808, 6003, 6045, 6089, 6333, 6376, 6415, 6441,
+	6483, 6497, 6544,
 }
 
 var strVals = `service name is empty` +
@@ -328,7 +336,14 @@ var strVals = `service name is empty` +
 	`validation options do not allow you to specify a non-segmented single value` +
 	`A mask must be a single IP address, while a CIDR prefix length must indicate the count of subnet bits, between 0 and 32 for IP version 4 addresses and between 0 and 128 for IP version 6 addresses` +
 	`service name must have at least one letter` +
-	`service name cannot have consecutive hyphens`
+	`service name cannot have consecutive hyphens` +
+	`invalid address family, the bit count must be a positive multiple of the segment bit count and at most 255, segments must have at most 64 bits, the radix must be between 2 and 36, and the separator cannot be a digit, range or wildcard character` +
+	`insufficient space for the requested blocks` +
+	`only individual addresses are supported` +
+	`port zero is not supported` +
+	`network registered with more than one name` +
+	`address is nil` +
+	`invalid IP version, expected IPv4, IPv6, 4 or 6`
 
 func lookupStr(key string) (result string) {
 	if index, ok := keyStrMap[key]; ok {
@@ -56,6 +56,38 @@ func parseIPAddressString(str string, params addrstrparam.IPAddressStringParams)
 	return res
 }
 
+// IsValidIPv4String returns whether the given string is a valid IPv4 address string according to the given parameters,
+// or the default parameters used by NewIPAddressString if the parameters are nil.
+//
+// It is equivalent to calling IsIPv4 on the IPAddressString from NewIPAddressStringParams, but is faster, since the parameters are not copied,
+// strings with IPv6 segment separators are rejected without parsing, and neither the IPAddressString nor its address is retained.
+// Use it to validate strings in performance-sensitive code when neither the address nor a descriptive error is needed.
+func IsValidIPv4String(str string, params addrstrparam.IPAddressStringParams) bool {
+	if strings.IndexByte(str, IPv6SegmentSeparator) >= 0 {
+		return false
+	}
+	return isValidIPAddressStr(str, params, IPv4)
+}
+
+// IsValidIPv6String returns whether the given string is a valid IPv6 address string according to the given parameters,
+// or the default parameters used by NewIPAddressString if the parameters are nil.
+//
+// It is equivalent to calling IsIPv6 on the IPAddressString from NewIPAddressStringParams, but is faster, since the parameters are not copied,
+// and neither the IPAddressString nor its address is retained.
+// Use it to validate strings in performance-sensitive code when neither the address nor a descriptive error is needed.
+func IsValidIPv6String(str string, params addrstrparam.IPAddressStringParams) bool {
+	return isValidIPAddressStr(str, params, IPv6)
+}
+
+func isValidIPAddressStr(str string, params addrstrparam.IPAddressStringParams, version IPVersion) bool {
+	if params == nil {
+		params = defaultIPAddrParameters
+	}
+	addrStr := IPAddressString{str: strings.TrimSpace(str)}
+	prov, err := validator.validateIPAddressStr(&addrStr, params)
+	return err == nil && prov.getProviderIPVersion() == version
+}
+
 var validator hostIdentifierStringValidator = strValidator{}
 
 var defaultIPAddrParameters = new(addrstrparam.IPAddressStringParamsBuilder).ToParams()
@@ -655,6 +655,50 @@ func (section *ipAddressSectionInternal) bitwiseOr(msk *IPAddressSection, retain
 		func(i int) SegInt { return msk.GetSegment(i).GetSegmentValue() })
 }
 
+// maskLen applies the network mask of the given prefix length, error can be addrerr.IncompatibleAddressError
+func (section *ipAddressSectionInternal) maskLen(prefLen BitCount, retainPrefix bool) (*IPAddressSection, addrerr.IncompatibleAddressError) {
+	prefLen = checkBitCount(prefLen, section.GetBitCount())
+	var prefixLen PrefixLen
+	if retainPrefix {
+		prefixLen = section.getPrefixLen()
+	}
+	bitsPerSegment := section.GetBitsPerSegment()
+	maxValue := section.GetMaxSegmentValue()
+	return section.getSubnetSegments(
+		0,
+		prefixLen,
+		true,
+		section.getDivision,
+		func(i int) SegInt {
+			networkBits := checkBitCount(prefLen-BitCount(i)*bitsPerSegment, bitsPerSegment)
+			return maxValue & (^SegInt(0) << uint(bitsPerSegment-networkBits))
+		})
+}
+
+// error can be addrerr.IncompatibleAddressError or addrerr.SizeMismatchError
+func (section *ipAddressSectionInternal) bitwiseXor(msk *IPAddressSection, retainPrefix bool) (*IPAddressSection, addrerr.IncompatibleAddressError) {
+	if err := section.checkSectionCount(msk); err != nil {
+		return nil, err
+	}
+	var prefLen PrefixLen
+	if retainPrefix {
+		prefLen = section.getPrefixLen()
+	}
+	return section.getXoredSegments(
+		prefLen,
+		func(i int) SegInt { return msk.GetSegment(i).GetSegmentValue() })
+}
+
+// invertMask flips all the bits, the prefix length is dropped
+func (section *ipAddressSectionInternal) invertMask() *IPAddressSection {
+	// every range is a single value or is inverted entirely, so there can be no error
+	maxValue := section.GetMaxSegmentValue()
+	res, _ := section.getXoredSegments(
+		nil,
+		func(int) SegInt { return maxValue })
+	return res
+}
+
 func (section *ipAddressSectionInternal) matchesWithMask(other *IPAddressSection, mask *IPAddressSection) bool {
 	if err := section.checkSectionCount(other); err != nil {
 		return false
@@ -1078,7 +1122,7 @@ func (section *ipAddressSectionInternal) getOredSegments(
 			mask64 := uint64(maskValue)
 			val64 := uint64(value)
 			upperVal64 := uint64(upperValue)
-			masker := bitwiseOrRange(val64, upperVal64, mask64, seg.GetMaxValue())
+			masker := BitwiseOrRange(val64, upperVal64, mask64, seg.GetMaxValue())
 			if !masker.IsSequential() {
 				err = &incompatibleAddressError{addressError{key: "ipaddress.error.maskMismatch"}}
 				return
@@ -1103,7 +1147,7 @@ func (section *ipAddressSectionInternal) getOredSegments(
 					mask64 := uint64(maskValue)
 					val64 := uint64(value)
 					upperVal64 := uint64(upperValue)
-					masker := bitwiseOrRange(val64, upperVal64, mask64, seg.GetMaxValue())
+					masker := BitwiseOrRange(val64, upperVal64, mask64, seg.GetMaxValue())
 					if !masker.IsSequential() {
 						err = &incompatibleAddressError{addressError{key: "ipaddress.error.maskMismatch"}}
 						return
@@ -1129,6 +1173,65 @@ func (section *ipAddressSectionInternal) getOredSegments(
 	return
 }
 
+func (section *ipAddressSectionInternal) getXoredSegments(
+	networkPrefixLength PrefixLen,
+	segmentMaskProducer func(int) SegInt) (res *IPAddressSection, err addrerr.IncompatibleAddressError) {
+	networkPrefixLength = checkPrefLen(networkPrefixLength, section.GetBitCount())
+	bitsPerSegment := section.GetBitsPerSegment()
+	count := section.GetSegmentCount()
+	newSegments := createSegmentArray(count)
+	for i := 0; i < count; i++ {
+		segmentPrefixLength := getSegmentPrefixLength(bitsPerSegment, networkPrefixLength, i)
+		seg := section.getDivision(i)
+		origValue, origUpperValue := seg.getSegmentValue(), seg.getUpperSegmentValue()
+		value, upperValue, ok := bitwiseXorRange(origValue, origUpperValue, segmentMaskProducer(i))
+		if !ok {
+			err = &incompatibleAddressError{addressError{key: "ipaddress.error.maskMismatch"}}
+			return
+		}
+		if !segsSame(segmentPrefixLength, seg.getDivisionPrefixLength(), value, origValue, upperValue, origUpperValue) {
+			newSegments[i] = createAddressDivision(seg.deriveNewMultiSeg(value, upperValue, segmentPrefixLength))
+		} else {
+			newSegments[i] = seg
+		}
+	}
+	res = deriveIPAddressSectionPrefLen(section.toIPAddressSection(), newSegments, networkPrefixLength)
+	return
+}
+
+// bitwiseXorRange returns the range of values resulting from the exclusive disjunction of the mask with every value in the given range,
+// returning false if the results are not sequential.
+func bitwiseXorRange(value, upperValue, maskValue SegInt) (SegInt, SegInt, bool) {
+	if value == upperValue {
+		value ^= maskValue
+		return value, value, true
+	}
+	// Split the range into aligned blocks.  The exclusive disjunction maps each aligned block to an aligned block of the same size,
+	// and since it maps distinct values to distinct values, the results are sequential exactly when they span as many values as the original range.
+	resultValue, resultUpperValue := ^SegInt(0), SegInt(0)
+	for lower := value; ; {
+		var hostMask SegInt
+		for next := hostMask<<1 | 1; next != hostMask && lower&next == 0 && lower|next <= upperValue; next = hostMask<<1 | 1 {
+			hostMask = next
+		}
+		blockValue := (lower ^ maskValue) &^ hostMask
+		if blockValue < resultValue {
+			resultValue = blockValue
+		}
+		if blockUpperValue := blockValue | hostMask; blockUpperValue > resultUpperValue {
+			resultUpperValue = blockUpperValue
+		}
+		if lower|hostMask == upperValue {
+			break
+		}
+		lower = (lower | hostMask) + 1
+	}
+	if resultUpperValue-resultValue != upperValue-value {
+		return 0, 0, false
+	}
+	return resultValue, resultUpperValue, true
+}
+
 func (section *ipAddressSectionInternal) getNetwork() IPAddressNetwork {
 	if addrType := section.getAddrType(); addrType.isIPv4() {
 		return ipv4Network
@@ -1487,6 +1590,20 @@ func (section *ipAddressSectionInternal) CopyUpperBytes(bytes []byte) []byte {
 	return section.addressSectionInternal.CopyUpperBytes(bytes)
 }
 
+// AppendBytes appends the value of the lowest individual address section in the section to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (section *ipAddressSectionInternal) AppendBytes(bytes []byte) []byte {
+	return section.addressSectionInternal.AppendBytes(bytes)
+}
+
+// AppendUpperBytes appends the value of the highest individual address section in the section to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (section *ipAddressSectionInternal) AppendUpperBytes(bytes []byte) []byte {
+	return section.addressSectionInternal.AppendUpperBytes(bytes)
+}
+
 // IsSequential returns  whether the section represents a range of values that are sequential.
 //
 // Generally, this means that any segment covering a range of values must be followed by segment that are full range, covering all values.
@@ -1511,6 +1628,18 @@ func (section *ipAddressSectionInternal) GetGenericSegment(index int) AddressSeg
 	return section.addressSectionInternal.GetGenericSegment(index)
 }
 
+// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+// as much as can be fit into the slice, returning the number of segments copied.
+func (section *ipAddressSectionInternal) CopyGenericSegments(segs []AddressSegmentType) (count int) {
+	return section.addressSectionInternal.CopyGenericSegments(segs)
+}
+
+// GetGenericSegments returns a slice with the segments as AddressSegmentType.
+// The returned slice is not backed by the same array as this section.
+func (section *ipAddressSectionInternal) GetGenericSegments() []AddressSegmentType {
+	return section.addressSectionInternal.GetGenericSegments()
+}
+
 // GetSegmentCount returns the segment/division count.
 func (section *ipAddressSectionInternal) GetSegmentCount() int {
 	return section.addressSectionInternal.GetSegmentCount()
@@ -1579,6 +1708,29 @@ func (section *IPAddressSection) Contains(other AddressSectionType) bool {
 	return section.contains(other)
 }
 
+// Relation returns the relation of this address section to the given address section,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+//
+// Sections must have the same type, version and number of segments to be comparable, otherwise RelationDisjoint is returned.
+func (section *IPAddressSection) Relation(other AddressSectionType) Relation {
+	if section == nil {
+		if other == nil || other.ToSectionBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return section.relation(other)
+}
+
+// IsContainedBy returns whether this is same type and version as the given address section and whether all its values are contained in the given section.
+// It is the reverse of Contains.
+func (section *IPAddressSection) IsContainedBy(other AddressSectionType) bool {
+	if other == nil {
+		return section == nil
+	}
+	return other.ToSectionBase().Contains(section)
+}
+
 // Equal returns whether the given address section is equal to this address section.
 // Two address sections are equal if they represent the same set of sections.
 // They must match:
@@ -1790,6 +1942,33 @@ func (section *IPAddressSection) GetHostMask() *IPAddressSection {
 	return section.getHostMask(section.getNetwork())
 }
 
+// BitwiseXor does the bitwise exclusive disjunction with this address section, flipping the bits that are one-bits in the given section.
+//
+// If the given section is a different version or has a different number of segments than this, then an error is returned.
+//
+// If this represents multiple address sections, and applying the operation to all sections creates a set of sections
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (section *IPAddressSection) BitwiseXor(other *IPAddressSection) (*IPAddressSection, addrerr.IncompatibleAddressError) {
+	if section.GetIPVersion() != other.GetIPVersion() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipMismatch"}}
+	}
+	return section.bitwiseXor(other, true)
+}
+
+// MaskLen applies the network mask of the given prefix length to all address sections represented by this section, returning the result.
+//
+// If this represents multiple addresses, and applying the mask to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (section *IPAddressSection) MaskLen(prefLen BitCount) (*IPAddressSection, addrerr.IncompatibleAddressError) {
+	return section.maskLen(prefLen, true)
+}
+
+// InvertMask flips all the bits of this address section, converting a network mask to the corresponding wildcard mask, and vice versa.
+// The prefix length is dropped from the result.
+func (section *IPAddressSection) InvertMask() *IPAddressSection {
+	return section.invertMask()
+}
+
 // CopySubSegments copies the existing segments from the given start index until but not including the segment at the given end index,
 // into the given slice, as much as can be fit into the slice, returning the number of segments copied.
 func (section *IPAddressSection) CopySubSegments(start, end int, segs []*IPAddressSegment) (count int) {
@@ -1991,6 +2170,18 @@ func (section *IPAddressSection) Iterator() Iterator[*IPAddressSection] {
 	return ipSectionIterator{section.sectionIterator(nil)}
 }
 
+// PermutationIterator provides an iterator to iterate through the individual address sections of this address section in a pseudo-random order determined by the given seed.
+// Each individual address section is visited exactly once, and the same seed always produces the same order.
+// The order is produced by a Feistel network permuting the indices of the iteration, so the address sections are not collected in advance.
+//
+// As with Iterator, the prefix length is preserved.
+func (section *IPAddressSection) PermutationIterator(seed int64) Iterator[*IPAddressSection] {
+	if section == nil {
+		return section.Iterator()
+	}
+	return newPermutationIterator[*IPAddressSection](section, seed)
+}
+
 // PrefixIterator provides an iterator to iterate through the individual prefixes of this address section,
 // each iterated element spanning the range of values for its prefix.
 //
@@ -2010,6 +2201,19 @@ func (section *IPAddressSection) PrefixBlockIterator() Iterator[*IPAddressSectio
 	return ipSectionIterator{section.prefixIterator(true)}
 }
 
+// PrefixBlocksOfLenIterator provides an iterator to iterate through the prefix blocks of the given prefix length that span this address section,
+// one for each distinct prefix of that length, without regard to the prefix length of this address section.
+// For example, iterating with a prefix length of 24 through a "/16" prefix block section produces each of its 256 "/24" prefix blocks in order.
+//
+// It is equivalent to calling PrefixBlockIterator on the section returned by SetPrefixLen with the given length,
+// and the number of iterated blocks is given by GetPrefixCountLen with the same length.
+// The blocks are produced lazily as the iterator advances, without computing the addresses in each block.
+//
+// A prefix length will not be set to a value lower than zero or beyond the bit length of the address section.
+func (section *IPAddressSection) PrefixBlocksOfLenIterator(prefLen BitCount) Iterator[*IPAddressSection] {
+	return ipSectionIterator{section.prefixBlocksOfLenIterator(prefLen)}
+}
+
 // BlockIterator Iterates through the address sections that can be obtained by iterating through all the upper segments up to the given segment count.
 // The segments following remain the same in all iterated sections.
 func (section *IPAddressSection) BlockIterator(segmentCount int) Iterator[*IPAddressSection] {
@@ -2060,6 +2264,38 @@ func (section *IPAddressSection) Increment(increment int64) *IPAddressSection {
 	return section.increment(increment).ToIP()
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with IPv6, where the count of addresses in a subnet can be much larger than the range of int64.
+//
+// On overflow or underflow, IncrementBig returns nil.
+func (section *IPAddressSection) IncrementBig(increment *big.Int) *IPAddressSection {
+	return section.incrementBig(increment).ToIP()
+}
+
+// Enumerate returns the index of the given individual address section within the iteration order of this address section,
+// which is the increment that, when supplied to IncrementBig, produces the given section from this one.
+// If the given section is not an individual address section contained by this address section, then nil is returned.
+func (section *IPAddressSection) Enumerate(other AddressSectionType) *big.Int {
+	if section == nil {
+		return nil
+	}
+	return section.enumerate(other)
+}
+
+// AddToSegment adds the given delta to the value of the segment at the given index,
+// carrying into the preceding, more significant, segments whenever a segment value overflows or underflows.
+// This is the same as adding the delta multiplied by the value count of each of the segments following the index.
+// The following segments and the prefix length remain the same.  If the delta is zero, this section is returned.
+//
+// If this represents multiple values, the delta is added to each.
+// Nil is returned if the results cannot be represented as a range of values in each segment,
+// which is the case when some values in a segment range carry into the preceding segment and others do not.
+//
+// On overflow or underflow, or if the index is not the index of a segment of this section, AddToSegment returns nil.
+func (section *IPAddressSection) AddToSegment(index int, delta int64) *IPAddressSection {
+	return section.addToSegment(index, delta).ToIP()
+}
+
 // SpanWithPrefixBlocks returns an array of prefix blocks that spans the same set of individual address sections as this section.
 //
 // Unlike SpanWithPrefixBlocksTo, the result only includes blocks that are a part of this section.
@@ -2184,6 +2420,33 @@ func (section *IPAddressSection) ToCompressedString() string {
 	return section.toCompressedString()
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address section,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived sections whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this section with WithoutStringCaching.
+func (section *IPAddressSection) WarmStrings() {
+	if section != nil {
+		section.ToCanonicalString()
+		section.ToNormalizedString()
+		section.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address section that does not cache the strings it produces, sharing the same segments.
+//
+// Each string produced by an address section is cached by default, which speeds up repeated calls for the same string,
+// but retains the memory of each string as long as the section is retained.
+// For short-lived sections whose strings are produced only once, or for large numbers of retained sections whose strings are rarely produced,
+// a section without string caching uses less memory.  Sections derived from the returned section do cache their strings.
+// To disable string caching for all address items, use SetStringCaching.
+func (section *IPAddressSection) WithoutStringCaching() *IPAddressSection {
+	if section == nil {
+		return nil
+	}
+	return section.withoutStringCaching().ToIP()
+}
+
 // ToHexString writes this address section as a single hexadecimal value (possibly two values if a range that is not a prefixed block),
 // the number of digits according to the bit count, with or without a preceding "0x" prefix.
 //
@@ -2320,6 +2583,19 @@ func (section *IPAddressSection) GetSegmentStrings() []string {
 	return section.getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (section *IPAddressSection) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if section == nil {
+		return nil
+	}
+	return section.getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 var (
 	rangeWildcard                 = new(addrstr.WildcardsBuilder).ToWildcards()
 	allWildcards                  = new(addrstr.WildcardOptionsBuilder).SetWildcardOptions(addrstr.WildcardsAll).ToOptions()
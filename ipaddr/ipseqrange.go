@@ -18,6 +18,7 @@ package ipaddr
 
 import (
 	"fmt"
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
 	"math/big"
 	"math/bits"
 	"net"
@@ -47,6 +48,7 @@ type SequentialRangeConstraint[T any] interface {
 	PrefixedConstraint[T]
 
 	Increment(int64) T
+	IncrementBig(*big.Int) T
 	GetLower() T
 	GetUpper() T
 
@@ -568,6 +570,30 @@ func (rng *SequentialRange[T]) ContainsRange(other IPAddressSeqRangeType) bool {
 		compareLowIPAddressValues(otherRange.GetUpper(), rng.upper) <= 0
 }
 
+// Relation returns the relation of this sequential range to the given sequential range,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+// Ranges of different versions are disjoint.
+func (rng *SequentialRange[T]) Relation(other IPAddressSeqRangeType) Relation {
+	if rng == nil {
+		if other == nil || other.ToIP() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	} else if other == nil || other.ToIP() == nil {
+		return RelationDisjoint
+	}
+	return rng.init().relation(other.ToIP().init())
+}
+
+// IsContainedBy returns whether all the addresses in this sequential range are also contained in the given sequential range.
+// It is the reverse of ContainsRange.
+func (rng *SequentialRange[T]) IsContainedBy(other IPAddressSeqRangeType) bool {
+	if other == nil {
+		return rng == nil
+	}
+	return other.ToIP().ContainsRange(rng)
+}
+
 // Equal returns whether the given sequential address range is equal to this sequential address range.
 // Two sequential address ranges are equal if their lower and upper range boundaries are equal.
 func (rng *SequentialRange[T]) Equal(other IPAddressSeqRangeType) bool {
@@ -768,6 +794,20 @@ func (rng *SequentialRange[T]) CoverWithPrefixBlock() T {
 	return rng.GetLower().CoverWithPrefixBlockTo(rng.GetUpper())
 }
 
+// EnclosingPrefixBlock returns the smallest prefix block, the single CIDR block, that contains all the addresses in this range.
+// It is the same as CoverWithPrefixBlock.
+func (rng *SequentialRange[T]) EnclosingPrefixBlock() T {
+	return rng.CoverWithPrefixBlock()
+}
+
+// IsSingleCIDR returns whether this range matches a single prefix block, which is a block that can be written in CIDR notation.
+// Individual addresses are prefix blocks with the prefix length of the address bit count.
+//
+// When true, EnclosingPrefixBlock returns a block with the same addresses as this range.
+func (rng *SequentialRange[T]) IsSingleCIDR() bool {
+	return rng.GetPrefixLenForSingleBlock() != nil
+}
+
 // SpanWithPrefixBlocks returns an array of prefix blocks that spans the same set of addresses as this range.
 func (rng *SequentialRange[T]) SpanWithPrefixBlocks() []T {
 	return rng.GetLower().SpanWithPrefixBlocksTo(rng.GetUpper())
@@ -788,6 +828,13 @@ func (rng *SequentialRange[T]) Join(ranges ...*SequentialRange[T]) []*Sequential
 	return joinRanges(ranges)
 }
 
+// IsAdjacent returns whether this range and the given range are contiguous, with no gap and no overlap,
+// which is to say the upper address of one immediately precedes the lower address of the other.
+// Use JoinTo to merge ranges that are adjacent or overlapping.
+func (rng *SequentialRange[T]) IsAdjacent(other *SequentialRange[T]) bool {
+	return rng.Relation(other) == RelationAdjacent
+}
+
 // JoinTo joins this range to the other if they are contiguous.  If this range overlaps with the given range,
 // or if the highest value of the lower range is one below the lowest value of the higher range,
 // then the two are joined into a new larger range that is returned.
@@ -828,6 +875,35 @@ func (rng *SequentialRange[T]) JoinTo(other *SequentialRange[T]) *SequentialRang
 	return newSequRangeUnchecked(lowestLower, highestUpper, true)
 }
 
+// IncrementBig returns the range that results from shifting both the lower and upper addresses of this range by the given increment, positive or negative.
+// The size of the range is unchanged.
+//
+// On address overflow or underflow, IncrementBig returns nil.
+func (rng *SequentialRange[T]) IncrementBig(increment *big.Int) *SequentialRange[T] {
+	rng = rng.init()
+	lower, upper := rng.lower.IncrementBig(increment), rng.upper.IncrementBig(increment)
+	var t T
+	if lower == t || upper == t {
+		return nil
+	}
+	return newSequRangeUnchecked(lower, upper, rng.isMultiple)
+}
+
+// Enumerate returns the index of the given individual address within this range,
+// which is the distance from the lower address of this range to the given address.
+// If the given address is not an individual address contained by this range, then nil is returned.
+func (rng *SequentialRange[T]) Enumerate(other IPAddressType) *big.Int {
+	if rng == nil || other == nil {
+		return nil
+	}
+	otherAddr := other.ToIP()
+	if otherAddr == nil || otherAddr.IsMultiple() || !rng.Contains(otherAddr) {
+		return nil
+	}
+	index := otherAddr.GetValue()
+	return index.Sub(index, rng.init().lower.GetValue())
+}
+
 // Extend extends this sequential range to include all address in the given range.
 // If the argument has a different IP version than this, nil is returned.
 // Otherwise, this method returns the range that includes this range, the given range, and all addresses in-between.
@@ -890,6 +966,89 @@ func (rng *SequentialRange[T]) Subtract(other *SequentialRange[T]) []*Sequential
 	}
 }
 
+// Mask applies the given mask to all addresses represented by this range, returning the range of masked addresses.
+// The lowest address value of the mask is used as the mask.
+//
+// If the mask is a different version than this, then an error is returned.
+//
+// If applying the mask to all addresses in the range creates a set of addresses
+// that cannot be represented as a sequential range, then an error is returned.
+func (rng *SequentialRange[T]) Mask(other T) (*SequentialRange[T], addrerr.IncompatibleAddressError) {
+	return rng.applyMask(other, false)
+}
+
+// BitwiseOr does the bitwise disjunction with all addresses represented by this range, returning the range of resulting addresses.
+// It is similar to Mask which does the bitwise conjunction.  The lowest address value of the argument is used for the operation.
+//
+// If the given address is a different version than this, then an error is returned.
+//
+// If applying the operation to all addresses in the range creates a set of addresses
+// that cannot be represented as a sequential range, then an error is returned.
+func (rng *SequentialRange[T]) BitwiseOr(other T) (*SequentialRange[T], addrerr.IncompatibleAddressError) {
+	return rng.applyMask(other, true)
+}
+
+// applyMask masks the range as a single division spanning the full address bit count.
+// The bitwise disjunction is the complement of the conjunction of the complements,
+// and the complement of a sequential range is the sequential range from the complemented upper to the complemented lower,
+// so disjunction is done with a masker as well.
+func (rng *SequentialRange[T]) applyMask(other T, isOr bool) (*SequentialRange[T], addrerr.IncompatibleAddressError) {
+	rng = rng.init()
+	lower, upper, mask := rng.lower.ToIP(), rng.upper.ToIP(), other.ToIP()
+	if lower.getAddrType() != mask.getAddrType() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipMismatch"}}
+	}
+	var newLower, newUpper *IPAddress
+	if lower.IsIPv4() {
+		value, upperValue, maskValue := uint64(lower.ToIPv4().Uint32Value()), uint64(upper.ToIPv4().Uint32Value()), uint64(mask.ToIPv4().Uint32Value())
+		const maxValue = 0xffffffff
+		if isOr {
+			value, upperValue, maskValue = maxValue^upperValue, maxValue^value, maxValue^maskValue
+		}
+		masker := MaskRange(value, upperValue, maskValue, maxValue)
+		if !masker.IsSequential() {
+			return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.maskMismatch"}}
+		}
+		value, upperValue = masker.GetMaskedLower(value, maskValue), masker.GetMaskedUpper(upperValue, maskValue)
+		if isOr {
+			value, upperValue = maxValue^upperValue, maxValue^value
+		}
+		newLower, newUpper = NewIPv4AddressFromUint32(uint32(value)).ToIP(), NewIPv4AddressFromUint32(uint32(upperValue)).ToIP()
+	} else {
+		extendedValue, value := lower.ToIPv6().Uint64ValuesBE()
+		extendedUpperValue, upperValue := upper.ToIPv6().Uint64ValuesBE()
+		extendedMaskValue, maskValue := mask.ToIPv6().Uint64ValuesBE()
+		if isOr {
+			extendedValue, extendedUpperValue, extendedMaskValue = ^extendedUpperValue, ^extendedValue, ^extendedMaskValue
+			value, upperValue, maskValue = ^upperValue, ^value, ^maskValue
+		}
+		masker := MaskExtendedRange(
+			value, extendedValue,
+			upperValue, extendedUpperValue,
+			maskValue, extendedMaskValue,
+			0xffffffffffffffff, 0xffffffffffffffff)
+		if !masker.IsSequential() {
+			return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.maskMismatch"}}
+		}
+		extendedValue, value, extendedUpperValue, upperValue =
+			masker.GetExtendedMaskedLower(extendedValue, extendedMaskValue), masker.GetMaskedLower(value, maskValue),
+			masker.GetExtendedMaskedUpper(extendedUpperValue, extendedMaskValue), masker.GetMaskedUpper(upperValue, maskValue)
+		if isOr {
+			extendedValue, extendedUpperValue = ^extendedUpperValue, ^extendedValue
+			value, upperValue = ^upperValue, ^value
+		}
+		newLower, newUpper = NewIPv6AddressFromUint64(extendedValue, value).ToIP(), NewIPv6AddressFromUint64(extendedUpperValue, upperValue).ToIP()
+	}
+	var t T
+	switch any(t).(type) {
+	case *IPv4Address:
+		return newSequRangeCheckSize(any(newLower.ToIPv4()).(T), any(newUpper.ToIPv4()).(T)), nil
+	case *IPv6Address:
+		return newSequRangeCheckSize(any(newLower.ToIPv6()).(T), any(newUpper.ToIPv6()).(T)), nil
+	}
+	return newSequRangeCheckSize(any(newLower).(T), any(newUpper).(T)), nil
+}
+
 // ToKey creates the associated address range key.
 // While address ranges can be compared with the Compare or Equal methods as well as various provided instances of AddressComparator,
 // they are not comparable with Go operators.
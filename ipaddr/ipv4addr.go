@@ -18,7 +18,9 @@ package ipaddr
 
 import (
 	"fmt"
+	"hash"
 	"math/big"
+	"math/bits"
 	"net"
 	"net/netip"
 
@@ -126,6 +128,21 @@ func NewIPv4AddressFromUint32(val uint32) *IPv4Address {
 	return createAddress(section.ToSectionBase(), NoZone).ToIPv4()
 }
 
+// NewIPv4AddressFromUint32BE constructs an IPv4 address from the given value in big-endian (network) byte order,
+// the most significant byte of the integer being the first byte of the address.  This is the same as NewIPv4AddressFromUint32.
+// It is the inverse of Uint32ValueBE.
+func NewIPv4AddressFromUint32BE(val uint32) *IPv4Address {
+	return NewIPv4AddressFromUint32(val)
+}
+
+// NewIPv4AddressFromUint32LE constructs an IPv4 address from the given value in little-endian byte order,
+// the least significant byte of the integer being the first byte of the address,
+// such as the s_addr field of an in_addr structure read on a little-endian host like x86.
+// It is the inverse of Uint32ValueLE.
+func NewIPv4AddressFromUint32LE(val uint32) *IPv4Address {
+	return NewIPv4AddressFromUint32(bits.ReverseBytes32(val))
+}
+
 // NewIPv4AddressFromPrefixedUint32 constructs an IPv4 address or prefix block from the given value and prefix length.
 // If the address has a zero host for the given prefix length, the returned address will be the prefix block.
 func NewIPv4AddressFromPrefixedUint32(val uint32, prefixLength PrefixLen) *IPv4Address {
@@ -242,6 +259,27 @@ func (addr *IPv4Address) GetIPv4PrefixCountLen(prefixLength BitCount) uint64 {
 	return addr.GetSection().GetIPv4PrefixCountLen(prefixLength)
 }
 
+// GetUsableHostCount returns the number of usable host addresses in the prefix blocks of this address or subnet,
+// the addresses provided by UsableHostIterator.
+//
+// The network address and the broadcast address of each prefix block, the zero host and the max host, are not usable hosts,
+// except when the prefix length is 31 or 32, in which case all the addresses are usable, as described by RFC 3021 for point-to-point links.
+// For instance, "1.2.3.4/24" has 254 usable hosts and "1.2.3.4/31" has 2.
+//
+// If this address has no prefix length, this returns the same value as GetIPv4Count.
+func (addr *IPv4Address) GetUsableHostCount() uint64 {
+	prefLen := addr.GetPrefixLen()
+	if prefLen == nil {
+		return addr.GetIPv4Count()
+	}
+	block := addr.ToPrefixBlock()
+	count := block.GetIPv4Count()
+	if prefLen.bitCount() < IPv4BitCount-1 {
+		count -= 2 * block.GetIPv4PrefixCount()
+	}
+	return count
+}
+
 // GetIPv4BlockCount returns the count of distinct values in the given number of initial (more significant) segments.
 //
 // It is similar to GetBlockCount but returns a uint64 instead of a big integer.
@@ -366,6 +404,19 @@ func (addr *IPv4Address) GetSegments() []*IPv4AddressSegment {
 	return addr.GetSection().GetSegments()
 }
 
+// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+// as much as can be fit into the slice, returning the number of segments copied.
+func (addr *IPv4Address) CopyGenericSegments(segs []AddressSegmentType) (count int) {
+	return addr.GetSection().CopyGenericSegments(segs)
+}
+
+// GetGenericSegments returns a slice with the address segments as AddressSegmentType,
+// allowing all segment types to be represented by a single type.
+// The returned slice is not backed by the same array as this address.
+func (addr *IPv4Address) GetGenericSegments() []AddressSegmentType {
+	return addr.GetSection().GetGenericSegments()
+}
+
 // GetSegment returns the segment at the given index.
 // The first segment is at index 0.
 // GetSegment will panic given a negative index or an index matching or larger than the segment count.
@@ -384,6 +435,33 @@ func (addr *IPv4Address) ForEachSegment(consumer func(segmentIndex int, segment
 	return addr.GetSection().ForEachSegment(consumer)
 }
 
+// ForEachSegmentValue visits each segment in order from most-significant to least, the most significant with index 0,
+// calling the given function with the lower and upper values of each and the segment prefix length, terminating early if the function returns true.
+// Unlike ForEachSegment, the segments themselves are not provided, and unlike GetSegments, no allocation is required.
+// Returns the number of visited segments.
+func (addr *IPv4Address) ForEachSegmentValue(consumer func(segmentIndex int, value, upperValue SegInt, segmentPrefixLen PrefixLen) (stop bool)) int {
+	return addr.GetSection().ForEachSegmentValue(consumer)
+}
+
+// SegmentValuesAppend appends the lower segment values, the segment values of the lowest address in this subnet, to the given slice, returning the extended slice.
+// When the slice has sufficient capacity, no allocation is required.
+func (addr *IPv4Address) SegmentValuesAppend(values []SegInt) []SegInt {
+	return addr.GetSection().SegmentValuesAppend(values)
+}
+
+// MapSegments returns the address obtained by replacing each segment with the result of calling the given function on that segment,
+// visiting the segments in order from most-significant to least.
+// A nil segment returned by the function is treated as a zero-valued segment.
+// The prefix length of this address, if any, is applied to the result.
+func (addr *IPv4Address) MapSegments(mapper func(seg *IPv4AddressSegment) *IPv4AddressSegment) *IPv4Address {
+	addr = addr.init()
+	segs := addr.GetSegments()
+	for i, seg := range segs {
+		segs[i] = mapper(seg)
+	}
+	return newIPv4Address(NewIPv4PrefixedSection(segs, addr.getPrefixLen()))
+}
+
 // GetGenericDivision returns the segment at the given index as a DivisionType.
 func (addr *IPv4Address) GetGenericDivision(index int) DivisionType {
 	return addr.init().getDivision(index)
@@ -455,6 +533,44 @@ func (addr *IPv4Address) bitwiseOrPrefixed(other *IPv4Address, retainPrefix bool
 	return
 }
 
+// BitwiseXor does the bitwise exclusive disjunction with this address or subnet, flipping the bits that are one-bits in the given address.
+// Applied with the all-ones address it converts a network mask to the corresponding wildcard mask, and back.
+//
+// The operation is applied to all individual addresses and the result is returned.
+//
+// If this is a subnet representing multiple addresses, and applying the operation to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (addr *IPv4Address) BitwiseXor(other *IPv4Address) (masked *IPv4Address, err addrerr.IncompatibleAddressError) {
+	addr = addr.init()
+	sect, err := addr.GetSection().BitwiseXor(other.GetSection())
+	if err == nil {
+		masked = addr.checkIdentity(sect)
+	}
+	return
+}
+
+// MaskLen applies the network mask of the given prefix length to all addresses represented by this address, returning the result.
+// It is equivalent to Mask with the network mask of the same bit count.
+//
+// If this represents multiple addresses, and applying the mask to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (addr *IPv4Address) MaskLen(prefLen BitCount) (masked *IPv4Address, err addrerr.IncompatibleAddressError) {
+	addr = addr.init()
+	sect, err := addr.GetSection().MaskLen(prefLen)
+	if err == nil {
+		masked = addr.checkIdentity(sect)
+	}
+	return
+}
+
+// InvertMask flips all the bits of this address, converting a network mask such as a subnet mask to the corresponding wildcard mask, and vice versa.
+// Wildcard masks are used in place of network masks by Cisco access control lists and OSPF configurations.
+// The prefix length is dropped from the result.
+func (addr *IPv4Address) InvertMask() *IPv4Address {
+	addr = addr.init()
+	return addr.checkIdentity(addr.GetSection().InvertMask())
+}
+
 // Subtract subtracts the given subnet from this subnet, returning an array of subnets for the result (the subnets will not be contiguous so an array is required).
 // Subtract computes the subnet difference, the set of addresses in this address subnet but not in the provided subnet.
 // This is also known as the relative complement of the given argument in this subnet.
@@ -550,6 +666,20 @@ func (addr *IPv4Address) ToZeroHost() (*IPv4Address, addrerr.IncompatibleAddress
 	return res.ToIPv4(), err
 }
 
+// ToZeroHostRanges returns the sequential ranges of the addresses obtained by converting each individual address of this address or subnet to the zero host, the address with the same prefix and a host of zero,
+// the host being the bits following the prefix length.
+// If the address or subnet has no prefix length, then it returns a single range with the all-zero address.
+//
+// Unlike ToZeroHost, this does not return an error when the converted addresses do not form a subnet.
+// Since distinct zero hosts differ by at least 2 when there are host bits, the returned ranges are single addresses, one for each prefix of this subnet.
+// The ranges are in increasing order.
+func (addr *IPv4Address) ToZeroHostRanges() (res []*SequentialRange[*IPv4Address]) {
+	addr.init().toHostRanges(false, func(lower, upper *IPAddress) {
+		res = append(res, NewSequentialRange(lower.ToIPv4(), upper.ToIPv4()))
+	})
+	return
+}
+
 // ToZeroHostLen converts the address or subnet to one in which all individual addresses have a host of zero,
 // the host being the bits following the given prefix length.
 // If this address or subnet has the same prefix length, then the returned one will too, otherwise the returned series will have no prefix length.
@@ -595,6 +725,20 @@ func (addr *IPv4Address) ToMaxHost() (*IPv4Address, addrerr.IncompatibleAddressE
 	return res.ToIPv4(), err
 }
 
+// ToMaxHostRanges returns the sequential ranges of the addresses obtained by converting each individual address of this address or subnet to the max host, the address with the same prefix and a host of all one-bits,
+// the host being the bits following the prefix length.
+// If the address or subnet has no prefix length, then it returns a single range with the max address.
+//
+// Unlike ToMaxHost, this does not return an error when the converted addresses do not form a subnet.
+// Since distinct max hosts differ by at least 2 when there are host bits, the returned ranges are single addresses, one for each prefix of this subnet.
+// The ranges are in increasing order.
+func (addr *IPv4Address) ToMaxHostRanges() (res []*SequentialRange[*IPv4Address]) {
+	addr.init().toHostRanges(true, func(lower, upper *IPAddress) {
+		res = append(res, NewSequentialRange(lower.ToIPv4(), upper.ToIPv4()))
+	})
+	return
+}
+
 // ToMaxHostLen converts the address or subnet to one in which all individual addresses have a host of all one-bits, the max host,
 // the host being the bits following the given prefix length.
 // If this address or subnet has the same prefix length, then the resulting one will too, otherwise the resulting address or subnet will have no prefix length.
@@ -618,6 +762,31 @@ func (addr *IPv4Address) UpperUint32Value() uint32 {
 	return addr.GetSection().UpperUint32Value()
 }
 
+// Uint32ValueBE returns the lowest address in the subnet range as a uint32 in big-endian (network) byte order,
+// the most significant byte of the integer being the first byte of the address.  This is the same value returned by Uint32Value.
+func (addr *IPv4Address) Uint32ValueBE() uint32 {
+	return addr.Uint32Value()
+}
+
+// Uint32ValueLE returns the lowest address in the subnet range as a uint32 in little-endian byte order,
+// the least significant byte of the integer being the first byte of the address.
+// This is the value obtained when the four address bytes in network order are read as an integer on a little-endian host,
+// such as when reading the s_addr field of an in_addr structure on x86.
+func (addr *IPv4Address) Uint32ValueLE() uint32 {
+	return bits.ReverseBytes32(addr.Uint32Value())
+}
+
+// UpperUint32ValueBE returns the highest address in the subnet range as a uint32 in big-endian (network) byte order.
+// This is the same value returned by UpperUint32Value.
+func (addr *IPv4Address) UpperUint32ValueBE() uint32 {
+	return addr.UpperUint32Value()
+}
+
+// UpperUint32ValueLE returns the highest address in the subnet range as a uint32 in little-endian byte order.
+func (addr *IPv4Address) UpperUint32ValueLE() uint32 {
+	return bits.ReverseBytes32(addr.UpperUint32Value())
+}
+
 // ToPrefixBlock returns the subnet associated with the prefix length of this address.
 // If this address has no prefix length, this address is returned.
 //
@@ -629,6 +798,16 @@ func (addr *IPv4Address) ToPrefixBlock() *IPv4Address {
 	return addr.init().toPrefixBlock().ToIPv4()
 }
 
+// ToPrefixBlockAndHost returns both the prefix block provided by ToPrefixBlock and the host section provided by GetHostSection.
+// The host section, whose value is the offset of this address within the block, has the bits of this address beyond the prefix length.
+// If this address has no prefix length, the block is this address and the host section is the full section.
+//
+// For example, if the address is "1.2.3.4/16" it returns the subnet "1.2.0.0/16" and the host section "3.4".
+func (addr *IPv4Address) ToPrefixBlockAndHost() (block *IPv4Address, host *IPv4AddressSection) {
+	addr = addr.init()
+	return addr.toPrefixBlock().ToIPv4(), addr.GetSection().GetHostSection()
+}
+
 // ToPrefixBlockLen returns the subnet associated with the given prefix length.
 //
 // The subnet will include all addresses with the same prefix as this one, the prefix "block" for that prefix length.
@@ -775,6 +954,12 @@ func (addr *IPv4Address) ContainsPrefixBlock(prefixLen BitCount) bool {
 	return addr.init().ipAddressInternal.ContainsPrefixBlock(prefixLen)
 }
 
+// ContainsPrefixBlockBySegment reports, for each segment, whether that segment spans the values required for this address to contain the block of addresses for the given prefix length.
+// ContainsPrefixBlock returns true if and only if every element is true, so the false elements identify the segments preventing a CIDR prefix block.
+func (addr *IPv4Address) ContainsPrefixBlockBySegment(prefixLen BitCount) []bool {
+	return addr.init().addressInternal.ContainsPrefixBlockBySegment(prefixLen)
+}
+
 // ContainsSinglePrefixBlock returns whether this address contains a single prefix block for the given prefix length.
 //
 // This means there is only one prefix value for the given prefix length, and it also contains the full prefix block for that prefix, all addresses with that prefix.
@@ -908,6 +1093,21 @@ func (addr *IPv4Address) CopyUpperBytes(bytes []byte) []byte {
 	return addr.init().section.CopyUpperBytes(bytes)
 }
 
+// AppendBytes appends the value of the lowest individual address in the subnet to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices, so a buffer can be reused when encoding many addresses, as in packet encoders.
+// In contrast, Bytes and UpperBytes return a new slice with each call, while CopyBytes and CopyUpperBytes copy from bytes that are calculated and cached by the first call of any of those four methods.
+func (addr *IPv4Address) AppendBytes(bytes []byte) []byte {
+	return addr.init().section.AppendBytes(bytes)
+}
+
+// AppendUpperBytes appends the value of the highest individual address in the subnet to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (addr *IPv4Address) AppendUpperBytes(bytes []byte) []byte {
+	return addr.init().section.AppendUpperBytes(bytes)
+}
+
 // IsMax returns whether this address matches exactly the maximum possible value, the address whose bits are all ones.
 func (addr *IPv4Address) IsMax() bool {
 	return addr.init().section.IsMax()
@@ -961,6 +1161,53 @@ func (addr *IPv4Address) Contains(other AddressType) bool {
 	return otherAddr.getAddrType() == ipv4Type && addr.section.sameCountTypeContains(otherAddr.GetSection())
 }
 
+// ContainsNonZeroHosts returns whether this address or subnet contains the non-zero host addresses of the given subnet,
+// all the addresses of the given subnet other than those whose host is zero for the prefix length of the given subnet.
+// The zero host of a prefix block is its network address, which is often not assigned to any host.
+//
+// If the given subnet has no prefix length, this is the same as Contains.
+func (addr *IPv4Address) ContainsNonZeroHosts(other *IPv4Address) bool {
+	if other == nil {
+		return true
+	} else if addr == nil {
+		return false
+	}
+	return addr.init().containsNonZeroHosts(other.ToIP())
+}
+
+// Relation returns the relation of this address or subnet to the given address or subnet,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+// Addresses and subnets of different types or versions are disjoint.
+func (addr *IPv4Address) Relation(other AddressType) Relation {
+	if addr == nil {
+		if other == nil || other.ToAddressBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return addr.init().relation(other)
+}
+
+// IsContainedBy returns whether this is the same type and version as the given address or subnet and whether it is contained by the given address or subnet.
+// It is the reverse of Contains.
+func (addr *IPv4Address) IsContainedBy(other AddressType) bool {
+	if other == nil {
+		return addr == nil
+	}
+	return other.ToAddressBase().Contains(addr)
+}
+
+// ContainsRange returns whether this is the same version as the given sequential range and whether it contains all addresses in the given range.
+// The addresses in this subnet need not be sequential.
+func (addr *IPv4Address) ContainsRange(other IPAddressSeqRangeType) bool {
+	if other == nil || other.ToIP() == nil {
+		return true
+	} else if addr == nil {
+		return false
+	}
+	return addr.init().containsRange(other.ToIP())
+}
+
 // Compare returns a negative integer, zero, or a positive integer if this address or subnet is less than, equal, or greater than the given item.
 // Any address item is comparable to any other.
 func (addr *IPv4Address) Compare(item AddressItem) int {
@@ -1129,6 +1376,40 @@ func (addr *IPv4Address) IsPrivate() bool {
 		(seg0.Matches(192) && seg1.Matches(168))
 }
 
+// IsSharedAddressSpace returns whether this address or subnet is entirely within the shared address space "100.64.0.0/10",
+// the block reserved by RFC 6598 for use by service providers in carrier-grade NAT deployments.
+func (addr *IPv4Address) IsSharedAddressSpace() bool {
+	return addr.GetSegment(0).Matches(100) && addr.GetSegment(1).MatchesWithPrefixMask(64, 2)
+}
+
+// IsCGNAT returns whether this address or subnet is entirely within the carrier-grade NAT shared address space "100.64.0.0/10".
+// It is equivalent to IsSharedAddressSpace.
+func (addr *IPv4Address) IsCGNAT() bool {
+	return addr.IsSharedAddressSpace()
+}
+
+// IsDocumentation returns whether this address or subnet is entirely within one of the blocks reserved for documentation by RFC 5737,
+// "192.0.2.0/24", "198.51.100.0/24" and "203.0.113.0/24".
+func (addr *IPv4Address) IsDocumentation() bool {
+	seg0, seg1, seg2 := addr.GetSegment(0), addr.GetSegment(1), addr.GetSegment(2)
+	return (seg0.Matches(192) && seg1.IsZero() && seg2.Matches(2)) ||
+		(seg0.Matches(198) && seg1.Matches(51) && seg2.Matches(100)) ||
+		(seg0.Matches(203) && seg1.IsZero() && seg2.Matches(113))
+}
+
+// IsBenchmarking returns whether this address or subnet is entirely within the block "198.18.0.0/15" reserved for benchmarking by RFC 2544.
+func (addr *IPv4Address) IsBenchmarking() bool {
+	return addr.GetSegment(0).Matches(198) && addr.GetSegment(1).MatchesWithPrefixMask(18, 7)
+}
+
+// GetAddressClassification returns the classification of this address or subnet according to the IANA IPv4 special-purpose address registry,
+// or ClassGlobalUnicast for an address outside of any special-purpose block.
+// A subnet whose addresses span more than one classification is classified as ClassMixed,
+// unless the subnet is within a single special-purpose block, such as "0.0.0.0/8", which is classified as ClassThisNetwork although it includes "0.0.0.0".
+func (addr *IPv4Address) GetAddressClassification() AddressClassification {
+	return classify(addr.init().ToIP(), ipv4ClassifiedBlocks, ClassGlobalUnicast)
+}
+
 // IsMulticast returns whether this address or subnet is entirely multicast.
 func (addr *IPv4Address) IsMulticast() bool {
 	// 1110...
@@ -1136,6 +1417,29 @@ func (addr *IPv4Address) IsMulticast() bool {
 	return addr.GetSegment(0).MatchesWithPrefixMask(0xe0, 4)
 }
 
+// ToMulticastMAC returns the Ethernet MAC address to which this IPv4 multicast address is mapped, RFC 1112,
+// which is the "01:00:5e" prefix followed by the low 23 bits of this address.
+// Since the upper 9 bits are not mapped, 32 IPv4 multicast addresses map to each MAC address.
+//
+// The prefix length of this address is ignored.
+// If this is a subnet, the result is the corresponding MAC address collection.
+// An error is returned if the low 23 bits of the subnet cannot be represented as a range of values in each MAC segment.
+func (addr *IPv4Address) ToMulticastMAC() (*MACAddress, addrerr.IncompatibleAddressError) {
+	masked, err := addr.init().maskPrefixed(NewIPv4AddressFromUint32(0x7fffff), false)
+	if err != nil {
+		return nil, err
+	}
+	newSegs := createSegmentArray(MediaAccessControlSegmentCount)
+	newSegs[0] = NewMACSegment(0x01).ToDiv()
+	newSegs[1] = NewMACSegment(0x00).ToDiv()
+	newSegs[2] = NewMACSegment(0x5e).ToDiv()
+	for i := 1; i < IPv4SegmentCount; i++ {
+		seg := masked.GetSegment(i)
+		newSegs[i+2] = NewMACRangeSegment(MACSegInt(seg.GetSegmentValue()), MACSegInt(seg.GetUpperSegmentValue())).ToDiv()
+	}
+	return newMACAddress(newMACSectionEUI(newSegs)), nil
+}
+
 // IsLocal returns true if the address is link local, site local, organization local, administered locally, or unspecified.
 // This includes both unicast and multicast.
 func (addr *IPv4Address) IsLocal() bool {
@@ -1189,6 +1493,18 @@ func (addr *IPv4Address) Iterator() Iterator[*IPv4Address] {
 	return ipv4AddressIterator{addr.init().addrIterator(nil)}
 }
 
+// PermutationIterator provides an iterator to iterate through the individual addresses of this address or subnet in a pseudo-random order determined by the given seed.
+// Each individual address is visited exactly once, and the same seed always produces the same order.
+// The order is produced by a Feistel network permuting the indices of the iteration, so the addresses are not collected in advance.
+//
+// As with Iterator, the prefix length is preserved.
+func (addr *IPv4Address) PermutationIterator(seed int64) Iterator[*IPv4Address] {
+	if addr == nil {
+		return addr.Iterator()
+	}
+	return newPermutationIterator[*IPv4Address](addr.init(), seed)
+}
+
 // PrefixIterator provides an iterator to iterate through the individual prefixes of this subnet,
 // each iterated element spanning the range of values for its prefix.
 //
@@ -1208,6 +1524,27 @@ func (addr *IPv4Address) PrefixBlockIterator() Iterator[*IPv4Address] {
 	return ipv4AddressIterator{addr.init().prefixIterator(true)}
 }
 
+// UsableHostIterator provides an iterator to iterate through the usable host addresses in the prefix blocks of this address or subnet,
+// skipping the network address and the broadcast address of each prefix block, except when the prefix length is 31 or 32, as described by RFC 3021.
+// GetUsableHostCount provides the count of the iterated addresses.
+//
+// As with Iterator, the prefix length is preserved.
+// If this address has no prefix length, then this is equivalent to Iterator.
+func (addr *IPv4Address) UsableHostIterator() Iterator[*IPv4Address] {
+	prefLen := addr.GetPrefixLen()
+	if prefLen == nil {
+		return addr.Iterator()
+	}
+	block := addr.ToPrefixBlock()
+	if prefLen.bitCount() >= IPv4BitCount-1 {
+		return block.Iterator()
+	}
+	return ipv4AddressIterator{NewFilteredAddrIterator(block.ToAddressBase().Iterator(), func(next *Address) bool {
+		ipAddr := next.ToIP()
+		return ipAddr.IsZeroHost() || ipAddr.IsMaxHost()
+	})}
+}
+
 // BlockIterator iterates through the addresses that can be obtained by iterating through all the upper segments up to the given segment count.
 // The segments following remain the same in all iterated addresses.
 //
@@ -1291,6 +1628,31 @@ func (addr *IPv4Address) Increment(increment int64) *IPv4Address {
 	return addr.init().increment(increment).ToIPv4()
 }
 
+// IncrementBig is the same as Increment, but accepts the increment as a big.Int, such as those returned by Enumerate and Distance.
+// Since the count of IPv4 addresses fits in an int64, Increment is sufficient for any IPv4 subnet.
+//
+// On address overflow or underflow, IncrementBig returns nil.
+func (addr *IPv4Address) IncrementBig(increment *big.Int) *IPv4Address {
+	return addr.init().incrementBig(increment).ToIPv4()
+}
+
+// Enumerate returns the index of the given individual address within the iteration order of this address or subnet,
+// which is the increment that, when supplied to IncrementBig, produces the given address from this subnet.
+// If the given address is not an individual address contained by this address or subnet, then nil is returned.
+func (addr *IPv4Address) Enumerate(other AddressType) *big.Int {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().enumerate(other)
+}
+
+// Distance returns the numeric difference between the lowest address of the given address or subnet and the lowest address of this address or subnet.
+// For individual addresses, this is the increment which, when supplied to IncrementBig, produces the given address from this one.
+// The result is negative when the given address is lower than this one.
+func (addr *IPv4Address) Distance(other *IPv4Address) *big.Int {
+	return addr.init().distance(&other.init().addressInternal)
+}
+
 // SpanWithPrefixBlocks returns an array of prefix blocks that cover the same set of addresses as this subnet.
 //
 // Unlike SpanWithPrefixBlocksTo, the result only includes addresses that are a part of this subnet.
@@ -1363,6 +1725,30 @@ func (addr *IPv4Address) CoverWithPrefixBlock() *IPv4Address {
 	return addr.init().coverWithPrefixBlock().ToIPv4()
 }
 
+// IsAdjacentTo returns whether this address or subnet and the given address or subnet are contiguous, with no gap and no overlap,
+// which is to say the highest address of one immediately precedes the lowest address of the other.
+// It is equivalent to checking whether Relation returns RelationAdjacent.
+func (addr *IPv4Address) IsAdjacentTo(other *IPv4Address) bool {
+	return addr.Relation(other) == RelationAdjacent
+}
+
+// TryMergeWith returns the union of this address or subnet with the given address or subnet
+// when that union is a single sequential block, which is the case when the two are sequential blocks that are adjacent or overlapping.
+// Otherwise, it returns nil.
+//
+// Use MergeToSequentialBlocks or MergeToPrefixBlocks to merge subnets whose union spans more than one block.
+//
+// If this address is nil, the given address is returned when it is a single sequential block.
+func (addr *IPv4Address) TryMergeWith(other *IPv4Address) *IPv4Address {
+	if other == nil {
+		return nil
+	}
+	if blocks := addr.MergeToSequentialBlocks(other); len(blocks) == 1 {
+		return blocks[0]
+	}
+	return nil
+}
+
 //
 // MergeToSequentialBlocks merges this with the list of addresses to produce the smallest array of sequential blocks.
 //
@@ -1538,6 +1924,8 @@ func createMixedSection(newIPv6Divisions []*AddressDivision, mixedSection *IPv4A
 // Format implements [fmt.Formatter] interface. It accepts the formats
 //  - 'v' for the default address and section format (either the normalized or canonical string),
 //  - 's' (string) for the same,
+//  - 'S' (uppercase string) for the same string with uppercase hexadecimal digits,
+//  - 'r' (reverse DNS) for the reverse-DNS lookup string of IP addresses,
 //  - 'b' (binary), 'o' (octal with 0 prefix), 'O' (octal with 0o prefix),
 //  - 'd' (decimal), 'x' (lowercase hexadecimal), and
 //  - 'X' (uppercase hexadecimal).
@@ -1547,6 +1935,10 @@ func createMixedSection(newIPv6Divisions []*AddressDivision, mixedSection *IPv4A
 // a leading "0x" or "0X" for "%#x" and "%#X" respectively.
 // Also supported is specification of minimum digits precision, output field width,
 // space or zero padding, and '-' for left or right justification.
+//
+// With 's' and 'S', the '+' flag selects the fully expanded string, as provided by ToFullString,
+// while the '#' flag selects the mixed IPv6/IPv4 string provided by ToMixedString for IPv6.
+// Combining the two, as in "%+#s", produces the mixed string with expanded IPv6 segments.
 func (addr IPv4Address) Format(state fmt.State, verb rune) {
 	addr.init().format(state, verb)
 }
@@ -1567,6 +1959,19 @@ func (addr *IPv4Address) GetSegmentStrings() []string {
 	return addr.init().getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (addr *IPv4Address) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 // ToCanonicalString produces a canonical string for the address.
 //
 // For IPv4, dotted octet format, also known as dotted decimal format, is used.
@@ -1608,6 +2013,29 @@ func (addr *IPv4Address) ToCompressedString() string {
 	return addr.init().toCompressedString()
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived addresses whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this address with WithoutStringCaching.
+func (addr *IPv4Address) WarmStrings() {
+	if addr != nil {
+		addr.ToCanonicalString()
+		addr.ToNormalizedString()
+		addr.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address that does not cache the strings it produces, sharing the same segments.
+// See the WithoutStringCaching method of the address sections for the memory trade-offs of string caching.
+func (addr *IPv4Address) WithoutStringCaching() *IPv4Address {
+	if addr == nil {
+		return nil
+	}
+	addr = addr.init()
+	return newIPv4Address(addr.GetSection().WithoutStringCaching())
+}
+
 // ToCanonicalWildcardString produces a string similar to the canonical string and avoids the CIDR prefix length.
 // Addresses and subnets with a network prefix length will be shown with wildcards and ranges (denoted by '*' and '-') instead of using the CIDR prefix length notation.
 // For IPv4 it is the same as ToNormalizedWildcardString.
@@ -1618,6 +2046,17 @@ func (addr *IPv4Address) ToCanonicalWildcardString() string {
 	return addr.init().toCanonicalWildcardString()
 }
 
+// ToHostPortString produces the socket address string combining this address with the given port, such as "1.2.3.4:80".
+// The prefix length, if any, is omitted.  The string can be parsed back with SplitHostPort.
+//
+// An error is returned for subnets and for port 0, neither of which SplitHostPort can parse.
+func (addr *IPv4Address) ToHostPortString(port uint16) (string, addrerr.IncompatibleAddressError) {
+	if addr == nil {
+		return nilString(), nil
+	}
+	return toHostPortString(addr.init().ToIP(), PortInt(port))
+}
+
 // ToNormalizedWildcardString produces a string similar to the normalized string but avoids the CIDR prefix length.
 // CIDR addresses will be shown with wildcards and ranges (denoted by '*' and '-') instead of using the CIDR prefix notation.
 func (addr *IPv4Address) ToNormalizedWildcardString() string {
@@ -1664,6 +2103,15 @@ func (addr *IPv4Address) ToReverseDNSString() (string, addrerr.IncompatibleAddre
 	return str, nil
 }
 
+// ToReverseDNSZoneStrings returns the names of the reverse-DNS zones that together cover this address or subnet.
+//
+// The covered prefix length is the prefix length of this address, or when there is no prefix length, the value of GetMinPrefixLenForBlock.
+// That prefix length is rounded up to a multiple of 8, and there is one zone for each prefix block of the rounded length.
+// For "1.2.16.0/20" it is the 16 zones "16.2.1.in-addr.arpa" to "31.2.1.in-addr.arpa".
+func (addr *IPv4Address) ToReverseDNSZoneStrings() []string {
+	return addr.init().ToIP().ToReverseDNSZoneStrings()
+}
+
 // ToPrefixLenString returns a string with a CIDR network prefix length if this address has a network prefix length.
 // For IPv6, a zero host section will be compressed with "::". For IPv4 the string is equivalent to the canonical string.
 func (addr *IPv4Address) ToPrefixLenString() string {
@@ -1673,6 +2121,19 @@ func (addr *IPv4Address) ToPrefixLenString() string {
 	return addr.init().toPrefixLenString()
 }
 
+// ToAddressMaskString produces a string with the address followed by its network mask, separated by a space,
+// the address-plus-netmask notation used in many network device configurations, such as "1.2.3.0 255.255.255.0".
+//
+// For a prefix block subnet, the address is the lowest address in the block, otherwise it is the canonical string without the prefix length.
+// Without a prefix length, the mask is the all-ones mask.
+// The string can be parsed by an IPAddressString when space-separated masks are allowed by the parse parameters, see AllowSpaceSeparatedMask in addrstrparam.IPAddressStringParamsBuilder.
+func (addr *IPv4Address) ToAddressMaskString() string {
+	if addr == nil {
+		return nilString()
+	}
+	return addr.init().toAddressMaskString()
+}
+
 // ToSubnetString produces a string with specific formats for subnets.
 // The subnet string looks like "1.2.*.*" or "1:2::/16".
 //
@@ -1733,6 +2194,11 @@ func (addr *IPv4Address) ToUNCHostName() string {
 	return addr.ToCanonicalString()
 }
 
+// ToURIString produces the string for use in URIs, which for IPv4 is the canonical string.
+func (addr *IPv4Address) ToURIString() string {
+	return addr.ToCanonicalString()
+}
+
 // ToInetAtonString returns a string with a format that is styled from the inet_aton routine.
 // The string can have an octal or hexadecimal radix rather than decimal.
 // When using octal, the octal segments each have a leading zero prefix of "0", and when using hex, a prefix of "0x".
@@ -1808,6 +2274,15 @@ func (addr *IPv4Address) toMinUpper() *IPv4Address {
 	return addr.init().addressInternal.toMinUpper().ToIPv4()
 }
 
+// WriteToHash writes the canonical bytes of this address into the given hash, without allocating:
+// the address type, byte count, and the bytes of the lowest and highest values.
+// The prefix length is not written.  The same bytes are written by the keys for this address.  See HashWriterTo for details.
+func (addr *IPv4Address) WriteToHash(h hash.Hash) {
+	if addr != nil {
+		addr.init().writeToHash(h)
+	}
+}
+
 // ToKey creates the associated address key.
 // While addresses can be compared with the Compare, TrieCompare or Equal methods as well as various provided instances of AddressComparator,
 // they are not comparable with Go operators.
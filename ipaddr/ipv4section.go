@@ -223,6 +223,29 @@ func (section *IPv4AddressSection) Contains(other AddressSectionType) bool {
 	return section.contains(other)
 }
 
+// Relation returns the relation of this address section to the given address section,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+//
+// Sections must have the same type, version and number of segments to be comparable, otherwise RelationDisjoint is returned.
+func (section *IPv4AddressSection) Relation(other AddressSectionType) Relation {
+	if section == nil {
+		if other == nil || other.ToSectionBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return section.relation(other)
+}
+
+// IsContainedBy returns whether this is same type and version as the given address section and whether all its values are contained in the given section.
+// It is the reverse of Contains.
+func (section *IPv4AddressSection) IsContainedBy(other AddressSectionType) bool {
+	if other == nil {
+		return section == nil
+	}
+	return other.ToSectionBase().Contains(section)
+}
+
 // Equal returns whether the given address section is equal to this address section.
 // Two address sections are equal if they represent the same set of sections.
 // They must match:
@@ -548,6 +571,42 @@ func (section *IPv4AddressSection) bitwiseOrPrefixed(other *IPv4AddressSection,
 	return
 }
 
+// BitwiseXor does the bitwise exclusive disjunction with this address section, flipping the bits that are one-bits in the given section.
+// Applied with an all-ones section it converts a network mask to the corresponding wildcard mask, and back.
+//
+// The operation is applied to all individual addresses and the result is returned.
+//
+// If this represents multiple address sections, and applying the operation to all sections creates a set of sections
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (section *IPv4AddressSection) BitwiseXor(other *IPv4AddressSection) (res *IPv4AddressSection, err addrerr.IncompatibleAddressError) {
+	sec, err := section.bitwiseXor(other.ToIP(), true)
+	if err == nil {
+		res = sec.ToIPv4()
+	}
+	return
+}
+
+// MaskLen applies the network mask of the given prefix length to all address sections represented by this section, returning the result.
+// It is equivalent to Mask with the network mask of the same bit count.
+//
+// If this represents multiple addresses, and applying the mask to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (section *IPv4AddressSection) MaskLen(prefLen BitCount) (res *IPv4AddressSection, err addrerr.IncompatibleAddressError) {
+	sec, err := section.maskLen(prefLen, true)
+	if err == nil {
+		res = sec.ToIPv4()
+	}
+	return
+}
+
+// InvertMask flips all the bits of this address section, converting a network mask such as a subnet mask to the corresponding wildcard mask, and vice versa.
+// The prefix length is dropped from the result.
+//
+// Each segment range is either a single value or is flipped in its entirety, so the result is always sequential within each segment.
+func (section *IPv4AddressSection) InvertMask() *IPv4AddressSection {
+	return section.invertMask().ToIPv4()
+}
+
 // MatchesWithMask applies the mask to this address section and then compares the result with the given address section,
 // returning true if they match, false otherwise.  To match, both the given section and mask must have the same number of segments as this section.
 func (section *IPv4AddressSection) MatchesWithMask(other *IPv4AddressSection, mask *IPv4AddressSection) bool {
@@ -789,6 +848,18 @@ func (section *IPv4AddressSection) Iterator() Iterator[*IPv4AddressSection] {
 	return ipv4SectionIterator{section.sectionIterator(nil)}
 }
 
+// PermutationIterator provides an iterator to iterate through the individual address sections of this address section in a pseudo-random order determined by the given seed.
+// Each individual address section is visited exactly once, and the same seed always produces the same order.
+// The order is produced by a Feistel network permuting the indices of the iteration, so the address sections are not collected in advance.
+//
+// As with Iterator, the prefix length is preserved.
+func (section *IPv4AddressSection) PermutationIterator(seed int64) Iterator[*IPv4AddressSection] {
+	if section == nil {
+		return section.Iterator()
+	}
+	return newPermutationIterator[*IPv4AddressSection](section, seed)
+}
+
 // PrefixIterator provides an iterator to iterate through the individual prefixes of this address section,
 // each iterated element spanning the range of values for its prefix.
 //
@@ -808,6 +879,19 @@ func (section *IPv4AddressSection) PrefixBlockIterator() Iterator[*IPv4AddressSe
 	return ipv4SectionIterator{section.prefixIterator(true)}
 }
 
+// PrefixBlocksOfLenIterator provides an iterator to iterate through the prefix blocks of the given prefix length that span this address section,
+// one for each distinct prefix of that length, without regard to the prefix length of this address section.
+// For example, iterating with a prefix length of 24 through a "/16" prefix block section produces each of its 256 "/24" prefix blocks in order.
+//
+// It is equivalent to calling PrefixBlockIterator on the section returned by SetPrefixLen with the given length,
+// and the number of iterated blocks is given by GetPrefixCountLen with the same length.
+// The blocks are produced lazily as the iterator advances, without computing the addresses in each block.
+//
+// A prefix length will not be set to a value lower than zero or beyond the bit length of the address section.
+func (section *IPv4AddressSection) PrefixBlocksOfLenIterator(prefLen BitCount) Iterator[*IPv4AddressSection] {
+	return ipv4SectionIterator{section.prefixBlocksOfLenIterator(prefLen)}
+}
+
 // BlockIterator Iterates through the address sections that can be obtained by iterating through all the upper segments up to the given segment count.
 // The segments following remain the same in all iterated sections.
 func (section *IPv4AddressSection) BlockIterator(segmentCount int) Iterator[*IPv4AddressSection] {
@@ -904,6 +988,44 @@ func (section *IPv4AddressSection) Increment(inc int64) *IPv4AddressSection {
 		section.getPrefixLen()).ToIPv4()
 }
 
+// IncrementBig is the same as Increment, but accepts the increment as a big.Int, such as those returned by Enumerate.
+// Since the count of IPv4 address sections fits in an int64, Increment is sufficient for any IPv4 address section.
+//
+// On overflow or underflow, IncrementBig returns nil.
+func (section *IPv4AddressSection) IncrementBig(increment *big.Int) *IPv4AddressSection {
+	return incrementBigInt(
+		section.ToSectionBase(),
+		increment,
+		ipv4Network.getIPAddressCreator(),
+		section.getLower,
+		section.getUpper,
+		section.getPrefixLen()).ToIPv4()
+}
+
+// Enumerate returns the index of the given individual address section within the iteration order of this address section,
+// which is the increment that, when supplied to IncrementBig, produces the given section from this one.
+// If the given section is not an individual address section contained by this address section, then nil is returned.
+func (section *IPv4AddressSection) Enumerate(other AddressSectionType) *big.Int {
+	if section == nil {
+		return nil
+	}
+	return section.enumerate(other)
+}
+
+// AddToSegment adds the given delta to the value of the segment at the given index,
+// carrying into the preceding, more significant, segments whenever a segment value overflows or underflows.
+// This is the same as adding the delta multiplied by the value count of each of the segments following the index.
+// The following segments and the prefix length remain the same.  If the delta is zero, this section is returned.
+//
+// If this represents multiple values, the delta is added to each.
+// Nil is returned if the results cannot be represented as a range of values in each segment,
+// which is the case when some values in a segment range carry into the preceding segment and others do not.
+//
+// On overflow or underflow, or if the index is not the index of a segment of this section, AddToSegment returns nil.
+func (section *IPv4AddressSection) AddToSegment(index int, delta int64) *IPv4AddressSection {
+	return section.addToSegment(index, delta).ToIPv4()
+}
+
 // SpanWithPrefixBlocks returns an array of prefix blocks that spans the same set of individual address sections as this section.
 //
 // Unlike SpanWithPrefixBlocksTo, the result only includes blocks that are a part of this section.
@@ -1173,6 +1295,33 @@ func (section *IPv4AddressSection) ToCompressedString() string {
 	return section.ToCanonicalString()
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address section,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived sections whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this section with WithoutStringCaching.
+func (section *IPv4AddressSection) WarmStrings() {
+	if section != nil {
+		section.ToCanonicalString()
+		section.ToNormalizedString()
+		section.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address section that does not cache the strings it produces, sharing the same segments.
+//
+// Each string produced by an address section is cached by default, which speeds up repeated calls for the same string,
+// but retains the memory of each string as long as the section is retained.
+// For short-lived sections whose strings are produced only once, or for large numbers of retained sections whose strings are rarely produced,
+// a section without string caching uses less memory.  Sections derived from the returned section do cache their strings.
+// To disable string caching for all address items, use SetStringCaching.
+func (section *IPv4AddressSection) WithoutStringCaching() *IPv4AddressSection {
+	if section == nil {
+		return nil
+	}
+	return section.withoutStringCaching().ToIPv4()
+}
+
 // ToNormalizedWildcardString produces a string similar to the normalized string but avoids the CIDR prefix length.
 // CIDR addresses will be shown with wildcards and ranges (denoted by '*' and '-') instead of using the CIDR prefix notation.
 func (section *IPv4AddressSection) ToNormalizedWildcardString() string {
@@ -1451,6 +1600,19 @@ func (section *IPv4AddressSection) GetSegmentStrings() []string {
 	return section.getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (section *IPv4AddressSection) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if section == nil {
+		return nil
+	}
+	return section.getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 // Inet_aton_radix represents a radix for printing an address string.
 type Inet_aton_radix int
 
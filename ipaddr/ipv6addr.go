@@ -17,12 +17,17 @@
 package ipaddr
 
 import (
+	"crypto/sha1"
+	"encoding/binary"
 	"fmt"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrstr"
+	"hash"
 	"math/big"
+	"math/bits"
 	"net"
 	"net/netip"
+	"time"
 )
 
 const (
@@ -282,6 +287,23 @@ func NewIPv6AddressFromUint64(highBytes, lowBytes uint64) *IPv6Address {
 	return newIPv6Address(section)
 }
 
+// NewIPv6AddressFromUint64BE constructs an IPv6 address from the given pair of values in big-endian (network) byte order,
+// the high value holding the first 8 bytes of the address and the low value the last 8 bytes,
+// the most significant byte of each being the first of its 8 bytes.  This is the same as NewIPv6AddressFromUint64.
+// It is the inverse of Uint64ValuesBE.
+func NewIPv6AddressFromUint64BE(high, low uint64) *IPv6Address {
+	return NewIPv6AddressFromUint64(high, low)
+}
+
+// NewIPv6AddressFromUint64LE constructs an IPv6 address from the given pair of values in little-endian byte order,
+// the high value holding the first 8 bytes of the address and the low value the last 8 bytes,
+// the least significant byte of each being the first of its 8 bytes,
+// such as the in6_addr structure viewed as an array of two 64-bit integers on a little-endian host like x86.
+// It is the inverse of Uint64ValuesLE.
+func NewIPv6AddressFromUint64LE(high, low uint64) *IPv6Address {
+	return NewIPv6AddressFromUint64(bits.ReverseBytes64(high), bits.ReverseBytes64(low))
+}
+
 // NewIPv6AddressFromPrefixedUint64 constructs an IPv6 address or prefix block from the given values and prefix length.
 // If the address has a zero host for the given prefix length, the returned address will be the prefix block.
 func NewIPv6AddressFromPrefixedUint64(highBytes, lowBytes uint64, prefixLength PrefixLen) *IPv6Address {
@@ -346,6 +368,44 @@ func newIPv6AddressFromPrefixedSingle(vals, upperVals IPv6SegmentValueProvider,
 	return newIPv6AddressZoned(section, zone)
 }
 
+// NewIPv6AddressFrom6To4 constructs a 6to4 address, RFC 3056, from the "2002::/16" prefix, the 32 bits of the given IPv4 address,
+// and the given 16-bit subnet identifier and 64-bit interface identifier.
+// The 6to4 site prefix is the "/48" prefix block of the returned address, which can be obtained with ToPrefixBlockLen(48).
+//
+// If the IPv4 address is a subnet, its lowest address is used.
+func NewIPv6AddressFrom6To4(ipv4 *IPv4Address, subnetID uint16, interfaceID uint64) *IPv6Address {
+	high := uint64(0x2002)<<48 | uint64(ipv4.Uint32Value())<<16 | uint64(subnetID)
+	return NewIPv6AddressFromUint64(high, interfaceID)
+}
+
+// TeredoComponents holds the values encoded in a Teredo address, RFC 4380.
+//
+// A Teredo address has the format "2001:0:SSSS:SSSS:FFFF:PPPP:CCCC:CCCC",
+// with the Teredo server IPv4 address S, the flags F, and the external client port P and client IPv4 address C,
+// the latter two with their bits inverted (obfuscated).  The port and client address held here are the original values, not the inverted values.
+type TeredoComponents struct {
+	// Server is the IPv4 address of the Teredo server.
+	Server *IPv4Address
+
+	// Client is the external IPv4 address of the Teredo client.
+	Client *IPv4Address
+
+	// Flags holds the flag bits, including the cone bit, the most significant bit.
+	Flags uint16
+
+	// Port is the external UDP port of the Teredo client.
+	Port uint16
+}
+
+// NewIPv6AddressFromTeredo constructs a Teredo address, RFC 4380, from the given components,
+// inverting the bits of the client port and client IPv4 address as required by the Teredo address format.
+// Both server and client addresses must be non-nil.  If either is a subnet, its lowest address is used.
+func NewIPv6AddressFromTeredo(components TeredoComponents) *IPv6Address {
+	high := uint64(0x20010000)<<32 | uint64(components.Server.Uint32Value())
+	low := uint64(components.Flags)<<48 | uint64(^components.Port)<<32 | uint64(^components.Client.Uint32Value())
+	return NewIPv6AddressFromUint64(high, low)
+}
+
 // NewIPv6AddressFromMAC constructs an IPv6 address from a modified EUI-64 (Extended Unique Identifier) MAC address and an IPv6 address 64-bit prefix.
 //
 // If the supplied MAC address section is an 8-byte EUI-64, then it must match the required EUI-64 format of "xx-xx-ff-fe-xx-xx"
@@ -471,6 +531,27 @@ func (addr *IPv6Address) GetCount() *big.Int {
 	return addr.getCount()
 }
 
+// GetUsableHostCount returns the number of usable host addresses in the prefix blocks of this address or subnet,
+// the addresses provided by UsableHostIterator.
+//
+// IPv6 has no broadcast address, but the zero host of each prefix block is the subnet-router anycast address described by RFC 4291, which is not a usable host,
+// except when the prefix length is 127 or 128, in which case all the addresses are usable, as described by RFC 6164 for point-to-point links.
+// For instance, "1::/64" has 2 to the power of 64 minus 1 usable hosts, and "1::/127" has 2.
+//
+// If this address has no prefix length, this returns the same value as GetCount.
+func (addr *IPv6Address) GetUsableHostCount() *big.Int {
+	prefLen := addr.GetPrefixLen()
+	if prefLen == nil {
+		return addr.GetCount()
+	}
+	block := addr.ToPrefixBlock()
+	count := block.GetCount()
+	if prefLen.bitCount() < IPv6BitCount-1 {
+		count.Sub(count, block.GetPrefixCount())
+	}
+	return count
+}
+
 // IsMultiple returns true if this represents more than a single individual address, whether it is a subnet of multiple addresses.
 func (addr *IPv6Address) IsMultiple() bool {
 	return addr != nil && addr.isMultiple()
@@ -614,10 +695,96 @@ func (addr *IPv6Address) GetIPv4AddressSection(startIndex, endIndex int) (*IPv4A
 }
 
 // Get6To4IPv4Address Returns the second and third segments as an IPv4Address.
+// For a 6to4 address, one for which Is6To4 returns true, this is the IPv4 address of the 6to4 site.
 func (addr *IPv6Address) Get6To4IPv4Address() (*IPv4Address, addrerr.IncompatibleAddressError) {
 	return addr.GetEmbeddedIPv4AddressAt(2)
 }
 
+// GetIPv4MappedAddress returns the IPv4 address or subnet mapped to this IPv4-mapped address, one for which IsIPv4Mapped returns true,
+// which is the IPv4 address in the lowest 4 bytes, as in "::ffff:1.2.3.4".
+// It returns nil if this is not an IPv4-mapped address.
+// An error can result when one of the associated IPv6 segments has a range of values that cannot be split into two ranges.
+func (addr *IPv6Address) GetIPv4MappedAddress() (*IPv4Address, addrerr.IncompatibleAddressError) {
+	if !addr.IsIPv4Mapped() {
+		return nil, nil
+	}
+	return addr.GetEmbeddedIPv4Address()
+}
+
+// GetIPv4CompatibleAddress returns the IPv4 address or subnet of this deprecated IPv4-compatible address, one for which IsIPv4Compatible returns true,
+// which is the IPv4 address in the lowest 4 bytes, as in "::1.2.3.4".
+// It returns nil if this is not an IPv4-compatible address.
+// An error can result when one of the associated IPv6 segments has a range of values that cannot be split into two ranges.
+func (addr *IPv6Address) GetIPv4CompatibleAddress() (*IPv4Address, addrerr.IncompatibleAddressError) {
+	if !addr.IsIPv4Compatible() {
+		return nil, nil
+	}
+	return addr.GetEmbeddedIPv4Address()
+}
+
+// Is6rd returns whether the address or all addresses in the subnet are within the given 6rd prefix, RFC 5969,
+// with room following the prefix for the embedded IPv4 address bits, those following the first ipv4MaskLen bits.
+//
+// The 6rd prefix and the IPv4 mask length are the parameters of the 6rd domain of an operator,
+// the IPv4 mask length being the number of high-order bits shared by all IPv4 addresses in the domain, which are omitted from the 6rd addresses.
+// It returns false if the 6rd prefix is nil or has no prefix length, or the IPv4 mask length exceeds 32.
+func (addr *IPv6Address) Is6rd(sixrdPrefix *IPv6Address, ipv4MaskLen BitCount) bool {
+	if sixrdPrefix == nil || !sixrdPrefix.IsPrefixed() || ipv4MaskLen < 0 || ipv4MaskLen > IPv4BitCount {
+		return false
+	}
+	prefLen := sixrdPrefix.GetPrefixLen().Len()
+	return prefLen+IPv4BitCount-ipv4MaskLen <= IPv6BitCount && sixrdPrefix.ToPrefixBlock().Contains(addr.WithoutZone())
+}
+
+// Get6rdIPv4Address returns the IPv4 address embedded in this 6rd address, RFC 5969, one for which Is6rd returns true with the same 6rd parameters.
+// It returns nil if this is not a 6rd address of the given 6rd prefix and IPv4 mask length.
+//
+// The embedded bits following the 6rd prefix supply the final 32 - ipv4MaskLen bits of the IPv4 address,
+// while the given IPv4 address of the 6rd domain, such as the address of the border relay, supplies the first ipv4MaskLen bits, which are common to the domain.
+// The domain address can be nil when the IPv4 mask length is zero.
+//
+// If this is a subnet, the IPv4 address is that of the lowest address in the subnet.
+func (addr *IPv6Address) Get6rdIPv4Address(sixrdPrefix *IPv6Address, ipv4MaskLen BitCount, domainAddr *IPv4Address) *IPv4Address {
+	if !addr.Is6rd(sixrdPrefix, ipv4MaskLen) {
+		return nil
+	}
+	embeddedBits := IPv4BitCount - ipv4MaskLen
+	shift := uint(IPv6BitCount - sixrdPrefix.GetPrefixLen().Len() - embeddedBits)
+	high, low := addr.GetLower().Uint64ValuesBE()
+	var embedded uint64
+	if shift >= 64 {
+		embedded = high >> (shift - 64)
+	} else if shift == 0 {
+		embedded = low
+	} else {
+		embedded = (low >> shift) | (high << (64 - shift))
+	}
+	hostMask := ^(^uint64(0) << uint(embeddedBits))
+	result := uint32(embedded & hostMask)
+	if domainAddr != nil {
+		result |= domainAddr.GetLower().Uint32Value() &^ uint32(hostMask)
+	}
+	return NewIPv4AddressFromUint32(result)
+}
+
+// GetTeredoComponents decodes the Teredo server address, client address, flags and client port from this Teredo address, RFC 4380,
+// restoring the original values of the inverted client address and port.
+// It returns nil if this is not a Teredo address, one for which IsTeredo returns true.
+//
+// If this is a subnet, the components are those of the lowest address in the subnet.
+func (addr *IPv6Address) GetTeredoComponents() *TeredoComponents {
+	if !addr.IsTeredo() {
+		return nil
+	}
+	high, low := addr.Uint64ValuesBE()
+	return &TeredoComponents{
+		Server: NewIPv4AddressFromUint32(uint32(high)),
+		Client: NewIPv4AddressFromUint32(^uint32(low)),
+		Flags:  uint16(low >> 48),
+		Port:   ^uint16(low >> 32),
+	}
+}
+
 // GetEmbeddedIPv4AddressAt produces an IPv4 address corresponding to any sequence of 4 bytes in this IPv6 address, starting at the given index.
 func (addr *IPv6Address) GetEmbeddedIPv4AddressAt(byteIndex int) (*IPv4Address, addrerr.IncompatibleAddressError) {
 	if byteIndex == IPv6MixedOriginalSegmentCount*IPv6BytesPerSegment {
@@ -658,6 +825,19 @@ func (addr *IPv6Address) GetSegments() []*IPv6AddressSegment {
 	return addr.GetSection().GetSegments()
 }
 
+// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+// as much as can be fit into the slice, returning the number of segments copied.
+func (addr *IPv6Address) CopyGenericSegments(segs []AddressSegmentType) (count int) {
+	return addr.GetSection().CopyGenericSegments(segs)
+}
+
+// GetGenericSegments returns a slice with the address segments as AddressSegmentType,
+// allowing all segment types to be represented by a single type.
+// The returned slice is not backed by the same array as this address.
+func (addr *IPv6Address) GetGenericSegments() []AddressSegmentType {
+	return addr.GetSection().GetGenericSegments()
+}
+
 // GetSegment returns the segment at the given index.
 // The first segment is at index 0.
 // GetSegment will panic given a negative index or an index matching or larger than the segment count.
@@ -676,6 +856,33 @@ func (addr *IPv6Address) ForEachSegment(consumer func(segmentIndex int, segment
 	return addr.GetSection().ForEachSegment(consumer)
 }
 
+// ForEachSegmentValue visits each segment in order from most-significant to least, the most significant with index 0,
+// calling the given function with the lower and upper values of each and the segment prefix length, terminating early if the function returns true.
+// Unlike ForEachSegment, the segments themselves are not provided, and unlike GetSegments, no allocation is required.
+// Returns the number of visited segments.
+func (addr *IPv6Address) ForEachSegmentValue(consumer func(segmentIndex int, value, upperValue SegInt, segmentPrefixLen PrefixLen) (stop bool)) int {
+	return addr.GetSection().ForEachSegmentValue(consumer)
+}
+
+// SegmentValuesAppend appends the lower segment values, the segment values of the lowest address in this subnet, to the given slice, returning the extended slice.
+// When the slice has sufficient capacity, no allocation is required.
+func (addr *IPv6Address) SegmentValuesAppend(values []SegInt) []SegInt {
+	return addr.GetSection().SegmentValuesAppend(values)
+}
+
+// MapSegments returns the address obtained by replacing each segment with the result of calling the given function on that segment,
+// visiting the segments in order from most-significant to least.
+// A nil segment returned by the function is treated as a zero-valued segment.
+// The prefix length and zone of this address, if any, are applied to the result.
+func (addr *IPv6Address) MapSegments(mapper func(seg *IPv6AddressSegment) *IPv6AddressSegment) *IPv6Address {
+	addr = addr.init()
+	segs := addr.GetSegments()
+	for i, seg := range segs {
+		segs[i] = mapper(seg)
+	}
+	return newIPv6AddressZoned(NewIPv6PrefixedSection(segs, addr.getPrefixLen()), string(addr.zone))
+}
+
 // GetGenericDivision returns the segment at the given index as a DivisionType.
 func (addr *IPv6Address) GetGenericDivision(index int) DivisionType {
 	return addr.init().getDivision(index)
@@ -747,6 +954,44 @@ func (addr *IPv6Address) bitwiseOrPrefixed(other *IPv6Address, retainPrefix bool
 	return
 }
 
+// BitwiseXor does the bitwise exclusive disjunction with this address or subnet, flipping the bits that are one-bits in the given address.
+// Applied with the all-ones address it converts a network mask to the corresponding wildcard mask, and back.
+//
+// The operation is applied to all individual addresses and the result is returned.
+//
+// If this is a subnet representing multiple addresses, and applying the operation to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (addr *IPv6Address) BitwiseXor(other *IPv6Address) (masked *IPv6Address, err addrerr.IncompatibleAddressError) {
+	addr = addr.init()
+	sect, err := addr.GetSection().BitwiseXor(other.GetSection())
+	if err == nil {
+		masked = addr.checkIdentity(sect)
+	}
+	return
+}
+
+// MaskLen applies the network mask of the given prefix length to all addresses represented by this address, returning the result.
+// It is equivalent to Mask with the network mask of the same bit count.
+//
+// If this represents multiple addresses, and applying the mask to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (addr *IPv6Address) MaskLen(prefLen BitCount) (masked *IPv6Address, err addrerr.IncompatibleAddressError) {
+	addr = addr.init()
+	sect, err := addr.GetSection().MaskLen(prefLen)
+	if err == nil {
+		masked = addr.checkIdentity(sect)
+	}
+	return
+}
+
+// InvertMask flips all the bits of this address, converting a network mask such as a subnet mask to the corresponding wildcard mask, and vice versa.
+// Wildcard masks are used in place of network masks by Cisco access control lists and OSPF configurations.
+// The prefix length is dropped from the result.
+func (addr *IPv6Address) InvertMask() *IPv6Address {
+	addr = addr.init()
+	return addr.checkIdentity(addr.GetSection().InvertMask())
+}
+
 // Subtract subtracts the given subnet from this subnet, returning an array of subnets for the result (the subnets will not be contiguous so an array is required).
 // Subtract computes the subnet difference, the set of addresses in this address subnet but not in the provided subnet.
 // This is also known as the relative complement of the given argument in this subnet.
@@ -840,6 +1085,20 @@ func (addr *IPv6Address) ToZeroHost() (*IPv6Address, addrerr.IncompatibleAddress
 	return res.ToIPv6(), err
 }
 
+// ToZeroHostRanges returns the sequential ranges of the addresses obtained by converting each individual address of this address or subnet to the zero host, the address with the same prefix and a host of zero,
+// the host being the bits following the prefix length.
+// If the address or subnet has no prefix length, then it returns a single range with the all-zero address.
+//
+// Unlike ToZeroHost, this does not return an error when the converted addresses do not form a subnet.
+// Since distinct zero hosts differ by at least 2 when there are host bits, the returned ranges are single addresses, one for each prefix of this subnet.
+// The ranges are in increasing order.
+func (addr *IPv6Address) ToZeroHostRanges() (res []*SequentialRange[*IPv6Address]) {
+	addr.init().toHostRanges(false, func(lower, upper *IPAddress) {
+		res = append(res, NewSequentialRange(lower.ToIPv6(), upper.ToIPv6()))
+	})
+	return
+}
+
 // ToZeroHostLen converts the address or subnet to one in which all individual addresses have a host of zero,
 // the host being the bits following the given prefix length.
 // If this address or subnet has the same prefix length, then the returned one will too, otherwise the returned series will have no prefix length.
@@ -883,6 +1142,20 @@ func (addr *IPv6Address) ToMaxHost() (*IPv6Address, addrerr.IncompatibleAddressE
 	return res.ToIPv6(), err
 }
 
+// ToMaxHostRanges returns the sequential ranges of the addresses obtained by converting each individual address of this address or subnet to the max host, the address with the same prefix and a host of all one-bits,
+// the host being the bits following the prefix length.
+// If the address or subnet has no prefix length, then it returns a single range with the max address.
+//
+// Unlike ToMaxHost, this does not return an error when the converted addresses do not form a subnet.
+// Since distinct max hosts differ by at least 2 when there are host bits, the returned ranges are single addresses, one for each prefix of this subnet.
+// The ranges are in increasing order.
+func (addr *IPv6Address) ToMaxHostRanges() (res []*SequentialRange[*IPv6Address]) {
+	addr.init().toHostRanges(true, func(lower, upper *IPAddress) {
+		res = append(res, NewSequentialRange(lower.ToIPv6(), upper.ToIPv6()))
+	})
+	return
+}
+
 // ToMaxHostLen converts the address or subnet to one in which all individual addresses have a host of all one-bits, the max host,
 // the host being the bits following the given prefix length.
 // If this address or subnet has the same prefix length, then the resulting one will too, otherwise the resulting address or subnet will have no prefix length.
@@ -907,6 +1180,16 @@ func (addr *IPv6Address) ToPrefixBlock() *IPv6Address {
 	return addr.init().toPrefixBlock().ToIPv6()
 }
 
+// ToPrefixBlockAndHost returns both the prefix block provided by ToPrefixBlock and the host section provided by GetHostSection.
+// The host section, whose value is the offset of this address within the block, has the bits of this address beyond the prefix length.
+// If this address has no prefix length, the block is this address and the host section is the full section.
+//
+// For example, if the address is "1:2:3:4:5:6:7:8/64" it returns the subnet "1:2:3:4::/64" and the host section "5:6:7:8".
+func (addr *IPv6Address) ToPrefixBlockAndHost() (block *IPv6Address, host *IPv6AddressSection) {
+	addr = addr.init()
+	return addr.toPrefixBlock().ToIPv6(), addr.GetSection().GetHostSection()
+}
+
 // ToPrefixBlockLen returns the subnet associated with the given prefix length.
 //
 // The subnet will include all addresses with the same prefix as this one, the prefix "block" for that prefix length.
@@ -1030,6 +1313,12 @@ func (addr *IPv6Address) ContainsPrefixBlock(prefixLen BitCount) bool {
 	return addr.init().ipAddressInternal.ContainsPrefixBlock(prefixLen)
 }
 
+// ContainsPrefixBlockBySegment reports, for each segment, whether that segment spans the values required for this address to contain the block of addresses for the given prefix length.
+// ContainsPrefixBlock returns true if and only if every element is true, so the false elements identify the segments preventing a CIDR prefix block.
+func (addr *IPv6Address) ContainsPrefixBlockBySegment(prefixLen BitCount) []bool {
+	return addr.init().addressInternal.ContainsPrefixBlockBySegment(prefixLen)
+}
+
 // ContainsSinglePrefixBlock returns whether this address contains a single prefix block for the given prefix length.
 //
 // This means there is only one prefix value for the given prefix length, and it also contains the full prefix block for that prefix, all addresses with that prefix.
@@ -1072,6 +1361,41 @@ func (addr *IPv6Address) GetUpperValue() *big.Int {
 	return addr.init().section.GetUpperValue()
 }
 
+// Uint64ValuesBE returns the lowest address in this subnet or address as a pair of uint64 values in big-endian (network) byte order.
+// The high value holds the first 8 bytes of the address and the low value the last 8 bytes,
+// the most significant byte of each being the first of its 8 bytes.
+// These are the values accepted by NewIPv6AddressFromUint64.
+func (addr *IPv6Address) Uint64ValuesBE() (high, low uint64) {
+	return uint64sBE(addr.Bytes())
+}
+
+// Uint64ValuesLE returns the lowest address in this subnet or address as a pair of uint64 values in little-endian byte order.
+// The high value holds the first 8 bytes of the address and the low value the last 8 bytes,
+// the least significant byte of each being the first of its 8 bytes.
+// These are the values obtained when the 16 address bytes in network order are read as two integers on a little-endian host,
+// such as when the in6_addr structure is viewed as an array of two 64-bit integers on x86.
+func (addr *IPv6Address) Uint64ValuesLE() (high, low uint64) {
+	return uint64sLE(addr.Bytes())
+}
+
+// UpperUint64ValuesBE returns the highest address in this subnet or address as a pair of uint64 values in big-endian (network) byte order.
+func (addr *IPv6Address) UpperUint64ValuesBE() (high, low uint64) {
+	return uint64sBE(addr.UpperBytes())
+}
+
+// UpperUint64ValuesLE returns the highest address in this subnet or address as a pair of uint64 values in little-endian byte order.
+func (addr *IPv6Address) UpperUint64ValuesLE() (high, low uint64) {
+	return uint64sLE(addr.UpperBytes())
+}
+
+func uint64sBE(bytes []byte) (high, low uint64) {
+	return binary.BigEndian.Uint64(bytes[:8]), binary.BigEndian.Uint64(bytes[8:])
+}
+
+func uint64sLE(bytes []byte) (high, low uint64) {
+	return binary.LittleEndian.Uint64(bytes[:8]), binary.LittleEndian.Uint64(bytes[8:])
+}
+
 // GetNetIPAddr returns the lowest address in this subnet or address as a net.IPAddr.
 func (addr *IPv6Address) GetNetIPAddr() *net.IPAddr {
 	return addr.ToIP().GetNetIPAddr()
@@ -1148,6 +1472,21 @@ func (addr *IPv6Address) CopyUpperBytes(bytes []byte) []byte {
 	return addr.init().section.CopyUpperBytes(bytes)
 }
 
+// AppendBytes appends the value of the lowest individual address in the subnet to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices, so a buffer can be reused when encoding many addresses, as in packet encoders.
+// In contrast, Bytes and UpperBytes return a new slice with each call, while CopyBytes and CopyUpperBytes copy from bytes that are calculated and cached by the first call of any of those four methods.
+func (addr *IPv6Address) AppendBytes(bytes []byte) []byte {
+	return addr.init().section.AppendBytes(bytes)
+}
+
+// AppendUpperBytes appends the value of the highest individual address in the subnet to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (addr *IPv6Address) AppendUpperBytes(bytes []byte) []byte {
+	return addr.init().section.AppendUpperBytes(bytes)
+}
+
 // IsMax returns whether this address matches exactly the maximum possible value, the address whose bits are all ones.
 func (addr *IPv6Address) IsMax() bool {
 	return addr.init().section.IsMax()
@@ -1202,6 +1541,53 @@ func (addr *IPv6Address) Contains(other AddressType) bool {
 		addr.isSameZone(other.ToAddressBase())
 }
 
+// ContainsNonZeroHosts returns whether this address or subnet contains the non-zero host addresses of the given subnet,
+// all the addresses of the given subnet other than those whose host is zero for the prefix length of the given subnet.
+// The zero host of a prefix block is its network address, which is often not assigned to any host.
+//
+// If the given subnet has no prefix length, this is the same as Contains.
+func (addr *IPv6Address) ContainsNonZeroHosts(other *IPv6Address) bool {
+	if other == nil {
+		return true
+	} else if addr == nil {
+		return false
+	}
+	return addr.init().containsNonZeroHosts(other.ToIP())
+}
+
+// Relation returns the relation of this address or subnet to the given address or subnet,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+// Addresses and subnets of different types or versions are disjoint.
+func (addr *IPv6Address) Relation(other AddressType) Relation {
+	if addr == nil {
+		if other == nil || other.ToAddressBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return addr.init().relation(other)
+}
+
+// IsContainedBy returns whether this is the same type and version as the given address or subnet and whether it is contained by the given address or subnet.
+// It is the reverse of Contains.
+func (addr *IPv6Address) IsContainedBy(other AddressType) bool {
+	if other == nil {
+		return addr == nil
+	}
+	return other.ToAddressBase().Contains(addr)
+}
+
+// ContainsRange returns whether this is the same version as the given sequential range and whether it contains all addresses in the given range.
+// The addresses in this subnet need not be sequential.
+func (addr *IPv6Address) ContainsRange(other IPAddressSeqRangeType) bool {
+	if other == nil || other.ToIP() == nil {
+		return true
+	} else if addr == nil {
+		return false
+	}
+	return addr.init().containsRange(other.ToIP())
+}
+
 // Compare returns a negative integer, zero, or a positive integer if this address or subnet is less than, equal, or greater than the given item.
 // Any address item is comparable to any other.  All address items use CountComparator to compare.
 func (addr *IPv6Address) Compare(item AddressItem) int {
@@ -1417,6 +1803,80 @@ func (addr *IPv6Address) IsUniqueLocal() bool {
 	return addr.GetSegment(0).MatchesWithPrefixMask(0xfc00, 7)
 }
 
+const (
+	// IPv6UniqueLocalGlobalIDBitCount is the bit length of the global ID of a unique-local address, RFC 4193.
+	IPv6UniqueLocalGlobalIDBitCount = 40
+
+	// IPv6UniqueLocalPrefixLen is the prefix length of the unique-local prefixes created by NewIPv6UniqueLocalPrefix, RFC 4193.
+	IPv6UniqueLocalPrefixLen = 48
+
+	// the seconds from the NTP epoch, 1900, to the Unix epoch, 1970
+	ntpEpochOffset = 2208988800
+)
+
+// NewIPv6UniqueLocalPrefix generates a locally assigned unique-local /48 prefix block, with a pseudo-random global ID, using the algorithm of RFC 4193 section 3.2.2.
+// The global ID is the least significant 40 bits of the SHA-1 digest of the given time in 64-bit NTP format concatenated with the EUI-64 identifier of the given MAC address.
+// Typically the time is the current time from time.Now, and the MAC address is that of the system generating the prefix.
+//
+// A 48-bit MAC address is converted to its EUI-64 form as with ToEUI64(false).  If the MAC address is a collection of addresses, the lowest is used.
+// An error is returned if the MAC address is nil.
+func NewIPv6UniqueLocalPrefix(timestamp time.Time, mac *MACAddress) (*IPv6Address, addrerr.IncompatibleAddressError) {
+	if mac == nil {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.nullAddress"}}
+	}
+	eui64, err := mac.GetLower().ToEUI64(false)
+	if err != nil {
+		return nil, err
+	}
+	key := make([]byte, 8, 16)
+	seconds := uint64(timestamp.Unix() + ntpEpochOffset)
+	fraction := (uint64(timestamp.Nanosecond()) << 32) / uint64(time.Second)
+	binary.BigEndian.PutUint64(key, seconds<<32|fraction)
+	key = append(key, eui64.Bytes()...)
+	digest := sha1.Sum(key)
+	bytes := make([]byte, IPv6ByteCount)
+	bytes[0] = 0xfd // fc00::/7 with the L bit set, indicating a locally assigned global ID
+	copy(bytes[1:IPv6UniqueLocalPrefixLen/8], digest[len(digest)-IPv6UniqueLocalGlobalIDBitCount/8:])
+	addr, _ := NewIPv6AddressFromPrefixedBytes(bytes, cacheBitCount(IPv6UniqueLocalPrefixLen))
+	return addr.ToPrefixBlock(), nil
+}
+
+// GetUniqueLocalGlobalID returns the 40-bit global ID of a unique-local address, the bits following the 8-bit prefix fc00::/7 with the L bit, RFC 4193.
+// The global ID is returned as a grouping with a single 40-bit division.
+//
+// It returns nil if the address or subnet is not unique-local.
+// If this represents a subnet, this returns an error when unable to join the address segments, the first with a range of values,
+// into a division of the larger bit-length that represents the same set of values.
+func (addr *IPv6Address) GetUniqueLocalGlobalID() (*AddressDivisionGrouping, addrerr.IncompatibleAddressError) {
+	if !addr.IsUniqueLocal() {
+		return nil, nil
+	}
+	var val, upperVal DivInt
+	var previousMultiple bool
+	for i := 0; i < 3; i++ {
+		seg := addr.GetSegment(i)
+		segVal, segUpperVal := seg.GetSegmentValue(), seg.GetUpperSegmentValue()
+		if i == 0 {
+			// the global ID starts with the low byte of the first segment
+			if segVal>>8 != segUpperVal>>8 {
+				if segVal&0xff != 0 || segUpperVal&0xff != 0xff {
+					return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.invalid.joined.ranges"}}
+				}
+			}
+			segVal &= 0xff
+			segUpperVal &= 0xff
+			previousMultiple = segVal != segUpperVal
+		} else if previousMultiple && !seg.IsFullRange() {
+			return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.invalid.joined.ranges"}}
+		} else {
+			previousMultiple = previousMultiple || seg.isMultiple()
+		}
+		val = val<<IPv6BitsPerSegment | DivInt(segVal)
+		upperVal = upperVal<<IPv6BitsPerSegment | DivInt(segUpperVal)
+	}
+	return NewDivisionGrouping([]*AddressDivision{NewRangeDivision(val, upperVal, IPv6UniqueLocalGlobalIDBitCount)}), nil
+}
+
 // IsIPv4Mapped returns whether the address or all addresses in the subnet are IPv4-mapped.
 //
 // "::ffff:x:x/96" indicates an IPv6 address mapped to IPv4.
@@ -1496,12 +1956,68 @@ func (addr *IPv6Address) IsWellKnownIPv4Translatable() bool { //rfc 6052 rfc 614
 	return false
 }
 
+// IsDocumentation returns whether this address or subnet is entirely within one of the blocks reserved for documentation,
+// "2001:db8::/32" by RFC 3849 and "3fff::/20" by RFC 9637.
+func (addr *IPv6Address) IsDocumentation() bool {
+	seg0, seg1 := addr.GetSegment(0), addr.GetSegment(1)
+	return (seg0.Matches(0x2001) && seg1.Matches(0xdb8)) ||
+		(seg0.Matches(0x3fff) && seg1.MatchesWithPrefixMask(0, 4))
+}
+
+// IsBenchmarking returns whether this address or subnet is entirely within the block "2001:2::/48" reserved for benchmarking by RFC 5180.
+func (addr *IPv6Address) IsBenchmarking() bool {
+	return addr.GetSegment(0).Matches(0x2001) && addr.GetSegment(1).Matches(2) && addr.GetSegment(2).IsZero()
+}
+
+// GetAddressClassification returns the classification of this address or subnet according to the IANA IPv6 special-purpose address registry.
+// Addresses outside of any special-purpose block are classified as ClassGlobalUnicast when within "2000::/3", and as ClassReserved otherwise.
+// A subnet whose addresses span more than one classification is classified as ClassMixed,
+// unless the subnet is within a single special-purpose block, such as "2001::/23", which is classified as ClassIETFProtocolAssignment although it includes "2001::/32".
+func (addr *IPv6Address) GetAddressClassification() AddressClassification {
+	return classify(addr.init().WithoutZone().ToIP(), ipv6ClassifiedBlocks, ClassReserved)
+}
+
 // IsMulticast returns whether this address or subnet is entirely multicast.
 func (addr *IPv6Address) IsMulticast() bool {
 	// 11111111...
 	return addr.GetSegment(0).MatchesWithPrefixMask(0xff00, 8)
 }
 
+// ToSolicitedNodeMulticast returns the solicited-node multicast address for this address, RFC 4291,
+// used by neighbor discovery, which is the "ff02::1:ff00:0/104" prefix followed by the low 24 bits of this address.
+// The zone is retained, since the solicited-node multicast address is link-local in scope.
+//
+// The prefix length of this address is ignored.
+// If this is a subnet, the result is the corresponding subnet of solicited-node multicast addresses.
+// An error is returned if the low 24 bits of the subnet cannot be represented as a range of values in each segment.
+func (addr *IPv6Address) ToSolicitedNodeMulticast() (*IPv6Address, addrerr.IncompatibleAddressError) {
+	masked, err := addr.maskPrefixed(NewIPv6AddressFromUint64(0, 0xffffff), false)
+	if err != nil {
+		return nil, err
+	}
+	return masked.bitwiseOrPrefixed(NewIPv6AddressFromUint64(0xff02<<48, 0x1ff000000), false)
+}
+
+// ToMulticastMAC returns the Ethernet MAC address to which this IPv6 multicast address is mapped, RFC 2464,
+// which is the "33:33" prefix followed by the low 32 bits of this address.
+//
+// The prefix length and zone of this address are ignored.
+// If this is a subnet, the result is the corresponding MAC address collection.
+// An error is returned if the range of values of either of the last two segments cannot be split into two ranges of MAC segment values.
+func (addr *IPv6Address) ToMulticastMAC() (*MACAddress, addrerr.IncompatibleAddressError) {
+	addr = addr.init()
+	newSegs := createSegmentArray(MediaAccessControlSegmentCount)
+	newSegs[0] = NewMACSegment(0x33).ToDiv()
+	newSegs[1] = newSegs[0]
+	if err := addr.GetSegment(6).splitIntoMACSegments(newSegs, 2); err != nil {
+		return nil, err
+	}
+	if err := addr.GetSegment(7).splitIntoMACSegments(newSegs, 4); err != nil {
+		return nil, err
+	}
+	return newMACAddress(newMACSectionEUI(newSegs)), nil
+}
+
 // IsLoopback returns whether this address is a loopback address, namely "::1".
 func (addr *IPv6Address) IsLoopback() bool {
 	if addr.section == nil {
@@ -1530,6 +2046,18 @@ func (addr *IPv6Address) Iterator() Iterator[*IPv6Address] {
 	return ipv6AddressIterator{addr.init().addrIterator(nil)}
 }
 
+// PermutationIterator provides an iterator to iterate through the individual addresses of this address or subnet in a pseudo-random order determined by the given seed.
+// Each individual address is visited exactly once, and the same seed always produces the same order.
+// The order is produced by a Feistel network permuting the indices of the iteration, so the addresses are not collected in advance.
+//
+// As with Iterator, the prefix length is preserved.
+func (addr *IPv6Address) PermutationIterator(seed int64) Iterator[*IPv6Address] {
+	if addr == nil {
+		return addr.Iterator()
+	}
+	return newPermutationIterator[*IPv6Address](addr.init(), seed)
+}
+
 // PrefixIterator provides an iterator to iterate through the individual prefixes of this subnet,
 // each iterated element spanning the range of values for its prefix.
 //
@@ -1549,6 +2077,26 @@ func (addr *IPv6Address) PrefixBlockIterator() Iterator[*IPv6Address] {
 	return ipv6AddressIterator{addr.init().prefixIterator(true)}
 }
 
+// UsableHostIterator provides an iterator to iterate through the usable host addresses in the prefix blocks of this address or subnet,
+// skipping the subnet-router anycast address, the zero host, of each prefix block, except when the prefix length is 127 or 128, as described by RFC 6164.
+// GetUsableHostCount provides the count of the iterated addresses.
+//
+// As with Iterator, the prefix length is preserved.
+// If this address has no prefix length, then this is equivalent to Iterator.
+func (addr *IPv6Address) UsableHostIterator() Iterator[*IPv6Address] {
+	prefLen := addr.GetPrefixLen()
+	if prefLen == nil {
+		return addr.Iterator()
+	}
+	block := addr.ToPrefixBlock()
+	if prefLen.bitCount() >= IPv6BitCount-1 {
+		return block.Iterator()
+	}
+	return ipv6AddressIterator{NewFilteredAddrIterator(block.ToAddressBase().Iterator(), func(next *Address) bool {
+		return next.ToIP().IsZeroHost()
+	})}
+}
+
 // BlockIterator iterates through the addresses that can be obtained by iterating through all the upper segments up to the given segment count.
 // The segments following remain the same in all iterated addresses.
 func (addr *IPv6Address) BlockIterator(segmentCount int) Iterator[*IPv6Address] {
@@ -1629,6 +2177,31 @@ func (addr *IPv6Address) Increment(increment int64) *IPv6Address {
 	return addr.init().increment(increment).ToIPv6()
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with IPv6, where the count of addresses in a subnet can be much larger than the range of int64.
+//
+// On address overflow or underflow, IncrementBig returns nil.
+func (addr *IPv6Address) IncrementBig(increment *big.Int) *IPv6Address {
+	return addr.init().incrementBig(increment).ToIPv6()
+}
+
+// Enumerate returns the index of the given individual address within the iteration order of this address or subnet,
+// which is the increment that, when supplied to IncrementBig, produces the given address from this subnet.
+// If the given address is not an individual address contained by this address or subnet, then nil is returned.
+func (addr *IPv6Address) Enumerate(other AddressType) *big.Int {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().enumerate(other)
+}
+
+// Distance returns the numeric difference between the lowest address of the given address or subnet and the lowest address of this address or subnet.
+// For individual addresses, this is the increment which, when supplied to IncrementBig, produces the given address from this one.
+// The result is negative when the given address is lower than this one.
+func (addr *IPv6Address) Distance(other *IPv6Address) *big.Int {
+	return addr.init().distance(&other.init().addressInternal)
+}
+
 // SpanWithPrefixBlocks returns an array of prefix blocks that cover the same set of addresses as this subnet.
 //
 // Unlike SpanWithPrefixBlocksTo, the result only includes addresses that are a part of this subnet.
@@ -1697,6 +2270,34 @@ func (addr *IPv6Address) CoverWithPrefixBlock() *IPv6Address {
 	return addr.init().coverWithPrefixBlock().ToIPv6()
 }
 
+// IsAdjacentTo returns whether this address or subnet and the given address or subnet are contiguous, with no gap and no overlap,
+// which is to say the highest address of one immediately precedes the lowest address of the other.
+// It is equivalent to checking whether Relation returns RelationAdjacent.
+func (addr *IPv6Address) IsAdjacentTo(other *IPv6Address) bool {
+	return addr.Relation(other) == RelationAdjacent
+}
+
+// TryMergeWith returns the union of this address or subnet with the given address or subnet
+// when that union is a single sequential block, which is the case when the two are sequential blocks that are adjacent or overlapping.
+// Otherwise, it returns nil.
+//
+// Use MergeToSequentialBlocks or MergeToPrefixBlocks to merge subnets whose union spans more than one block.
+//
+// If this address is nil, the given address is returned when it is a single sequential block.
+func (addr *IPv6Address) TryMergeWith(other *IPv6Address) *IPv6Address {
+	if other == nil {
+		return nil
+	} else if addr == nil {
+		addr = other
+	} else if !addr.isSameZone(other.ToAddressBase()) {
+		return nil
+	}
+	if blocks := addr.MergeToSequentialBlocks(other); len(blocks) == 1 {
+		return blocks[0]
+	}
+	return nil
+}
+
 // MergeToSequentialBlocks merges this with the list of addresses to produce the smallest array of sequential blocks.
 //
 // The resulting slice is sorted from lowest address value to highest, regardless of the size of each prefix block.
@@ -1880,6 +2481,8 @@ func (addr *IPv6Address) toEUISegments(extended bool) ([]*AddressDivision, addre
 // Format implements [fmt.Formatter] interface. It accepts the formats
 //  - 'v' for the default address and section format (either the normalized or canonical string),
 //  - 's' (string) for the same,
+//  - 'S' (uppercase string) for the same string with uppercase hexadecimal digits,
+//  - 'r' (reverse DNS) for the reverse-DNS lookup string of IP addresses,
 //  - 'b' (binary), 'o' (octal with 0 prefix), 'O' (octal with 0o prefix),
 //  - 'd' (decimal), 'x' (lowercase hexadecimal), and
 //  - 'X' (uppercase hexadecimal).
@@ -1889,6 +2492,10 @@ func (addr *IPv6Address) toEUISegments(extended bool) ([]*AddressDivision, addre
 // a leading "0x" or "0X" for "%#x" and "%#X" respectively.
 // Also supported is specification of minimum digits precision, output field width,
 // space or zero padding, and '-' for left or right justification.
+//
+// With 's' and 'S', the '+' flag selects the fully expanded string, as provided by ToFullString,
+// while the '#' flag selects the mixed IPv6/IPv4 string provided by ToMixedString for IPv6.
+// Combining the two, as in "%+#s", produces the mixed string with expanded IPv6 segments.
 func (addr IPv6Address) Format(state fmt.State, verb rune) {
 	addr.init().format(state, verb)
 }
@@ -1909,12 +2516,29 @@ func (addr *IPv6Address) GetSegmentStrings() []string {
 	return addr.init().getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (addr *IPv6Address) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 // ToCanonicalString produces a canonical string for the address.
 //
 // For IPv6, RFC 5952 describes canonical string representation.
 // https://en.wikipedia.org/wiki/IPv6_address#Representation
 // http://tools.ietf.org/html/rfc5952
 //
+// The string is lowercase, the longest range of two or more zero-segments is compressed, choosing the leftmost range when there is a tie,
+// and a single zero-segment is never compressed.
+// Other compression choices are available from ToCustomString using addrstr.CompressOptionsBuilder.
+//
 // Each address has a unique canonical string, not counting the prefix length.
 // With IP addresses, the prefix length can cause two equal addresses to have different strings, for example "1.2.3.4/16" and "1.2.3.4".
 // It can also cause two different addresses to have the same string, such as "1.2.0.0/16" for the individual address "1.2.0.0" and also the prefix block "1.2.*.*".
@@ -1951,6 +2575,29 @@ func (addr *IPv6Address) ToCompressedString() string {
 	return addr.init().toCompressedString()
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived addresses whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this address with WithoutStringCaching.
+func (addr *IPv6Address) WarmStrings() {
+	if addr != nil {
+		addr.ToCanonicalString()
+		addr.ToNormalizedString()
+		addr.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address that does not cache the strings it produces, sharing the same segments.
+// See the WithoutStringCaching method of the address sections for the memory trade-offs of string caching.
+func (addr *IPv6Address) WithoutStringCaching() *IPv6Address {
+	if addr == nil {
+		return nil
+	}
+	addr = addr.init()
+	return createAddress(addr.GetSection().WithoutStringCaching().ToSectionBase(), addr.zone).ToIPv6()
+}
+
 // ToCanonicalWildcardString produces a string similar to the canonical string and avoids the CIDR prefix length.
 // Addresses and subnets with a network prefix length will be shown with wildcards and ranges (denoted by '*' and '-') instead of using the CIDR prefix length notation.
 // IPv6 addresses will be compressed according to the canonical representation.
@@ -1961,6 +2608,18 @@ func (addr *IPv6Address) ToCanonicalWildcardString() string {
 	return addr.init().toCanonicalWildcardString()
 }
 
+// ToHostPortString produces the socket address string combining this address with the given port, such as "[2001:db8::1%eth0]:80".
+// The address is enclosed in square brackets, with any zone inside the brackets.
+// The prefix length, if any, is omitted.  The string can be parsed back with SplitHostPort.
+//
+// An error is returned for subnets and for port 0, neither of which SplitHostPort can parse.
+func (addr *IPv6Address) ToHostPortString(port uint16) (string, addrerr.IncompatibleAddressError) {
+	if addr == nil {
+		return nilString(), nil
+	}
+	return toHostPortString(addr.init().ToIP(), PortInt(port))
+}
+
 // ToNormalizedWildcardString produces a string similar to the normalized string but avoids the CIDR prefix length.
 // CIDR addresses will be shown with wildcards and ranges (denoted by '*' and '-') instead of using the CIDR prefix notation.
 func (addr *IPv6Address) ToNormalizedWildcardString() string {
@@ -2005,6 +2664,19 @@ func (addr *IPv6Address) ToPrefixLenString() string {
 	return addr.init().toPrefixLenString()
 }
 
+// ToAddressMaskString produces a string with the address followed by its network mask, separated by a space,
+// the address-plus-netmask notation used in many network device configurations, such as "1.2.3.0 255.255.255.0".
+//
+// For a prefix block subnet, the address is the lowest address in the block, otherwise it is the canonical string without the prefix length.
+// Without a prefix length, the mask is the all-ones mask.
+// The string can be parsed by an IPAddressString when space-separated masks are allowed by the parse parameters, see AllowSpaceSeparatedMask in addrstrparam.IPAddressStringParamsBuilder.
+func (addr *IPv6Address) ToAddressMaskString() string {
+	if addr == nil {
+		return nilString()
+	}
+	return addr.init().toAddressMaskString()
+}
+
 // ToSubnetString produces a string with specific formats for subnets.
 // The subnet string looks like "1.2.*.*" or "1:2::/16".
 //
@@ -2034,6 +2706,15 @@ func (addr *IPv6Address) ToReverseDNSString() (string, addrerr.IncompatibleAddre
 	return addr.init().toReverseDNSString()
 }
 
+// ToReverseDNSZoneStrings returns the names of the reverse-DNS zones that together cover this address or subnet.
+//
+// The covered prefix length is the prefix length of this address, or when there is no prefix length, the value of GetMinPrefixLenForBlock.
+// That prefix length is rounded up to a multiple of 4, and there is one zone for each prefix block of the rounded length.
+// For "2001:db8::/30" it is the 4 zones "8.b.d.0.1.0.0.2.ip6.arpa" to "b.b.d.0.1.0.0.2.ip6.arpa".
+func (addr *IPv6Address) ToReverseDNSZoneStrings() []string {
+	return addr.init().ToIP().ToReverseDNSZoneStrings()
+}
+
 // ToHexString writes this address as a single hexadecimal value (possibly two values if a range that is not a prefixed block),
 // the number of digits according to the bit count, with or without a preceding "0x" prefix.
 //
@@ -2088,6 +2769,23 @@ func (addr *IPv6Address) ToUNCHostName() string {
 		})
 }
 
+// ToURIString produces the canonical string with the zone, if any, percent-encoded as in URIs, following RFC 6874.
+// The zone separator is encoded as "%25", and any zone characters reserved in URIs are percent-encoded,
+// so that "fe80::1%eth0" becomes "fe80::1%25eth0".
+// When used as the host of a URI, the string must be enclosed in square brackets, as in "http://[fe80::1%25eth0]/".
+//
+// The string can be parsed back using IPAddressString parameters that allow URI zone encoding with AllowURIZoneEncoding in IPv6AddressStringParamsBuilder.
+func (addr *IPv6Address) ToURIString() string {
+	if addr == nil {
+		return nilString()
+	}
+	addr = addr.init()
+	if !addr.hasZone() {
+		return addr.toCanonicalString()
+	}
+	return addr.GetSection().toCanonicalString(toURIZone(addr.zone))
+}
+
 // ToBase85String creates the base 85 string, which is described by RFC 1924, "A Compact Representation of IPv6 Addresses".
 // See https://www.rfc-editor.org/rfc/rfc1924.html
 //
@@ -2187,6 +2885,15 @@ func (addr *IPv6Address) WrapAddress() WrappedAddress {
 	return wrapAddress(addr.ToAddressBase())
 }
 
+// WriteToHash writes the canonical bytes of this address into the given hash, without allocating:
+// the address type, byte count, and the bytes of the lowest and highest values, followed by the zone.
+// The prefix length is not written.  The same bytes are written by the keys for this address.  See HashWriterTo for details.
+func (addr *IPv6Address) WriteToHash(h hash.Hash) {
+	if addr != nil {
+		addr.init().writeToHash(h)
+	}
+}
+
 // ToKey creates the associated address key.
 // While addresses can be compared with the Compare, TrieCompare or Equal methods as well as various provided instances of AddressComparator,
 // they are not comparable with Go operators.
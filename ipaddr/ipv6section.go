@@ -348,6 +348,29 @@ func (section *IPv6AddressSection) Contains(other AddressSectionType) bool {
 	return section.contains(other)
 }
 
+// Relation returns the relation of this address section to the given address section,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+//
+// Sections must have the same type, version and number of segments to be comparable, otherwise RelationDisjoint is returned.
+func (section *IPv6AddressSection) Relation(other AddressSectionType) Relation {
+	if section == nil {
+		if other == nil || other.ToSectionBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return section.relation(other)
+}
+
+// IsContainedBy returns whether this is same type and version as the given address section and whether all its values are contained in the given section.
+// It is the reverse of Contains.
+func (section *IPv6AddressSection) IsContainedBy(other AddressSectionType) bool {
+	if other == nil {
+		return section == nil
+	}
+	return other.ToSectionBase().Contains(section)
+}
+
 // Equal returns whether the given address section is equal to this address section.
 // Two address sections are equal if they represent the same set of sections.
 // They must match:
@@ -623,6 +646,42 @@ func (section *IPv6AddressSection) bitwiseOrPrefixed(other *IPv6AddressSection,
 	return
 }
 
+// BitwiseXor does the bitwise exclusive disjunction with this address section, flipping the bits that are one-bits in the given section.
+// Applied with an all-ones section it converts a network mask to the corresponding wildcard mask, and back.
+//
+// The operation is applied to all individual addresses and the result is returned.
+//
+// If this represents multiple address sections, and applying the operation to all sections creates a set of sections
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (section *IPv6AddressSection) BitwiseXor(other *IPv6AddressSection) (res *IPv6AddressSection, err addrerr.IncompatibleAddressError) {
+	sec, err := section.bitwiseXor(other.ToIP(), true)
+	if err == nil {
+		res = sec.ToIPv6()
+	}
+	return
+}
+
+// MaskLen applies the network mask of the given prefix length to all address sections represented by this section, returning the result.
+// It is equivalent to Mask with the network mask of the same bit count.
+//
+// If this represents multiple addresses, and applying the mask to all addresses creates a set of addresses
+// that cannot be represented as a sequential range within each segment, then an error is returned.
+func (section *IPv6AddressSection) MaskLen(prefLen BitCount) (res *IPv6AddressSection, err addrerr.IncompatibleAddressError) {
+	sec, err := section.maskLen(prefLen, true)
+	if err == nil {
+		res = sec.ToIPv6()
+	}
+	return
+}
+
+// InvertMask flips all the bits of this address section, converting a network mask such as a subnet mask to the corresponding wildcard mask, and vice versa.
+// The prefix length is dropped from the result.
+//
+// Each segment range is either a single value or is flipped in its entirety, so the result is always sequential within each segment.
+func (section *IPv6AddressSection) InvertMask() *IPv6AddressSection {
+	return section.invertMask().ToIPv6()
+}
+
 // MatchesWithMask applies the mask to this address section and then compares the result with the given address section,
 // returning true if they match, false otherwise.  To match, both the given section and mask must have the same number of segments as this section.
 func (section *IPv6AddressSection) MatchesWithMask(other *IPv6AddressSection, mask *IPv6AddressSection) bool {
@@ -729,6 +788,28 @@ func (section *IPv6AddressSection) ToMaxHostLen(prefixLength BitCount) (*IPv6Add
 	return res.ToIPv6(), err
 }
 
+// Uint64Values returns the lowest address in the address section range as a pair of uint64 values,
+// the high value holding the bits above the lowest 64 bits, and the low value holding the lowest 64 bits.
+// For a full 8-segment section these are the first and last 8 bytes of the address, the values accepted by NewIPv6AddressFromUint64.
+// The segment values are read directly, without creating segments or bytes.
+func (section *IPv6AddressSection) Uint64Values() (high, low uint64) {
+	for _, div := range section.getDivArray() {
+		high = (high << IPv6BitsPerSegment) | (low >> (64 - IPv6BitsPerSegment))
+		low = (low << IPv6BitsPerSegment) | uint64(div.getSegmentValue())
+	}
+	return
+}
+
+// UpperUint64Values returns the highest address in the address section range as a pair of uint64 values,
+// the high value holding the bits above the lowest 64 bits, and the low value holding the lowest 64 bits.
+func (section *IPv6AddressSection) UpperUint64Values() (high, low uint64) {
+	for _, div := range section.getDivArray() {
+		high = (high << IPv6BitsPerSegment) | (low >> (64 - IPv6BitsPerSegment))
+		low = (low << IPv6BitsPerSegment) | uint64(div.getUpperSegmentValue())
+	}
+	return
+}
+
 // ToPrefixBlock returns the section with the same prefix as this section while the remaining bits span all values.
 // The returned section will be the block of all sections with the same prefix.
 //
@@ -836,6 +917,18 @@ func (section *IPv6AddressSection) Iterator() Iterator[*IPv6AddressSection] {
 	return ipv6SectionIterator{section.sectionIterator(nil)}
 }
 
+// PermutationIterator provides an iterator to iterate through the individual address sections of this address section in a pseudo-random order determined by the given seed.
+// Each individual address section is visited exactly once, and the same seed always produces the same order.
+// The order is produced by a Feistel network permuting the indices of the iteration, so the address sections are not collected in advance.
+//
+// As with Iterator, the prefix length is preserved.
+func (section *IPv6AddressSection) PermutationIterator(seed int64) Iterator[*IPv6AddressSection] {
+	if section == nil {
+		return section.Iterator()
+	}
+	return newPermutationIterator[*IPv6AddressSection](section, seed)
+}
+
 // PrefixIterator provides an iterator to iterate through the individual prefixes of this address section,
 // each iterated element spanning the range of values for its prefix.
 //
@@ -855,6 +948,19 @@ func (section *IPv6AddressSection) PrefixBlockIterator() Iterator[*IPv6AddressSe
 	return ipv6SectionIterator{section.prefixIterator(true)}
 }
 
+// PrefixBlocksOfLenIterator provides an iterator to iterate through the prefix blocks of the given prefix length that span this address section,
+// one for each distinct prefix of that length, without regard to the prefix length of this address section.
+// For example, iterating with a prefix length of 24 through a "/16" prefix block section produces each of its 256 "/24" prefix blocks in order.
+//
+// It is equivalent to calling PrefixBlockIterator on the section returned by SetPrefixLen with the given length,
+// and the number of iterated blocks is given by GetPrefixCountLen with the same length.
+// The blocks are produced lazily as the iterator advances, without computing the addresses in each block.
+//
+// A prefix length will not be set to a value lower than zero or beyond the bit length of the address section.
+func (section *IPv6AddressSection) PrefixBlocksOfLenIterator(prefLen BitCount) Iterator[*IPv6AddressSection] {
+	return ipv6SectionIterator{section.prefixBlocksOfLenIterator(prefLen)}
+}
+
 // BlockIterator Iterates through the address sections that can be obtained by iterating through all the upper segments up to the given segment count.
 // The segments following remain the same in all iterated sections.
 func (section *IPv6AddressSection) BlockIterator(segmentCount int) Iterator[*IPv6AddressSection] {
@@ -902,6 +1008,15 @@ func (section *IPv6AddressSection) getCompressIndexAndCount(options addrstr.Comp
 		segmentCount := section.GetSegmentCount()
 		//compressMixed := createMixed && options.GetMixedCompressionOptions().compressMixed(section)
 		compressMixed := createMixed && compressMixedSect(options.GetMixedCompressionOptions(), section)
+		var runSelection addrstr.CompressionRunOptions
+		var minCount int
+		if runOptions, ok := options.(addrstr.CompressionRunOptionsProvider); ok {
+			runSelection = runOptions.GetCompressionRunOptions()
+			minCount = runOptions.GetMinCompressCount()
+		}
+		if minCount < 2 && !options.CompressSingle() {
+			minCount = 2
+		}
 		preferHost := rangeSelection == addrstr.HostPreferred
 		preferMixed := createMixed && (rangeSelection == addrstr.MixedPreferred)
 		for i := compressibleSegs.size() - 1; i >= 0; i-- {
@@ -919,10 +1034,26 @@ func (section *IPv6AddressSection) getCompressIndexAndCount(options addrstr.Comp
 					}
 				}
 			}
-			//select this range if is the longest
-			if count > 0 && count >= maxCount && (options.CompressSingle() || count > 1) {
-				maxIndex = index
-				maxCount = count
+			if count > 0 && count >= minCount {
+				var selected bool
+				switch runSelection {
+				case addrstr.LongestRightmost:
+					//select this range if it is longer, since we are going backwards the rightmost wins a tie
+					selected = count > maxCount
+				case addrstr.Leftmost:
+					//since we are going backwards, the last range selected is the leftmost
+					selected = true
+				case addrstr.Rightmost:
+					//since we are going backwards, the first range selected is the rightmost
+					selected = maxCount == 0
+				default:
+					//select this range if is the longest, since we are going backwards the leftmost wins a tie
+					selected = count >= maxCount
+				}
+				if selected {
+					maxIndex = index
+					maxCount = count
+				}
 			}
 			if preferHost && section.IsPrefixed() &&
 				(BitCount(index+count)*section.GetBitsPerSegment()) > section.getNetworkPrefixLen().bitCount() { //this range contains the host
@@ -1104,6 +1235,44 @@ func (section *IPv6AddressSection) Increment(increment int64) *IPv6AddressSectio
 		prefixLength).ToIPv6()
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with IPv6, where the count of addresses in a subnet can be much larger than the range of int64.
+//
+// On overflow or underflow, IncrementBig returns nil.
+func (section *IPv6AddressSection) IncrementBig(increment *big.Int) *IPv6AddressSection {
+	return incrementBigInt(
+		section.ToSectionBase(),
+		increment,
+		ipv6Network.getIPAddressCreator(),
+		section.getLower,
+		section.getUpper,
+		section.getPrefixLen()).ToIPv6()
+}
+
+// Enumerate returns the index of the given individual address section within the iteration order of this address section,
+// which is the increment that, when supplied to IncrementBig, produces the given section from this one.
+// If the given section is not an individual address section contained by this address section, then nil is returned.
+func (section *IPv6AddressSection) Enumerate(other AddressSectionType) *big.Int {
+	if section == nil {
+		return nil
+	}
+	return section.enumerate(other)
+}
+
+// AddToSegment adds the given delta to the value of the segment at the given index,
+// carrying into the preceding, more significant, segments whenever a segment value overflows or underflows.
+// This is the same as adding the delta multiplied by the value count of each of the segments following the index.
+// The following segments and the prefix length remain the same.  If the delta is zero, this section is returned.
+//
+// If this represents multiple values, the delta is added to each.
+// Nil is returned if the results cannot be represented as a range of values in each segment,
+// which is the case when some values in a segment range carry into the preceding segment and others do not.
+//
+// On overflow or underflow, or if the index is not the index of a segment of this section, AddToSegment returns nil.
+func (section *IPv6AddressSection) AddToSegment(index int, delta int64) *IPv6AddressSection {
+	return section.addToSegment(index, delta).ToIPv6()
+}
+
 // SpanWithPrefixBlocks returns an array of prefix blocks that spans the same set of individual address sections as this section.
 //
 // Unlike SpanWithPrefixBlocksTo, the result only includes blocks that are a part of this section.
@@ -1301,6 +1470,7 @@ var (
 	base85Wildcards = new(addrstr.WildcardsBuilder).SetRangeSeparator(AlternativeRangeSeparatorStr).ToWildcards()
 
 	mixedParams         = new(addrstr.IPv6StringOptionsBuilder).SetMixed(true).SetCompressOptions(compressMixed).ToOptions()
+	mixedFullParams     = new(addrstr.IPv6StringOptionsBuilder).SetMixed(true).SetExpandedSegments(true).ToOptions()
 	ipv6FullParams      = new(addrstr.IPv6StringOptionsBuilder).SetExpandedSegments(true).SetWildcardOptions(wildcardsRangeOnlyNetworkOnly).ToOptions()
 	ipv6CanonicalParams = new(addrstr.IPv6StringOptionsBuilder).SetCompressOptions(compressAllNoSingles).ToOptions()
 	uncParams           = new(addrstr.IPv6StringOptionsBuilder).SetSeparator(IPv6UncSegmentSeparator).SetZoneSeparator(IPv6UncZoneSeparatorStr).
@@ -1411,6 +1581,10 @@ func (section *IPv6AddressSection) toBase85String(zone Zone) (string, addrerr.In
 // https://en.wikipedia.org/wiki/IPv6_address#Representation
 // http://tools.ietf.org/html/rfc5952
 //
+// The string is lowercase, the longest range of two or more zero-segments is compressed, choosing the leftmost range when there is a tie,
+// and a single zero-segment is never compressed.
+// Other compression choices are available from ToCustomString using addrstr.CompressOptionsBuilder.
+//
 //If this section has a prefix length, it will be included in the string.
 func (section *IPv6AddressSection) ToCanonicalString() string {
 	if section == nil {
@@ -1462,6 +1636,33 @@ func (section *IPv6AddressSection) ToCompressedString() string {
 		})
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address section,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived sections whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this section with WithoutStringCaching.
+func (section *IPv6AddressSection) WarmStrings() {
+	if section != nil {
+		section.ToCanonicalString()
+		section.ToNormalizedString()
+		section.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address section that does not cache the strings it produces, sharing the same segments.
+//
+// Each string produced by an address section is cached by default, which speeds up repeated calls for the same string,
+// but retains the memory of each string as long as the section is retained.
+// For short-lived sections whose strings are produced only once, or for large numbers of retained sections whose strings are rarely produced,
+// a section without string caching uses less memory.  Sections derived from the returned section do cache their strings.
+// To disable string caching for all address items, use SetStringCaching.
+func (section *IPv6AddressSection) WithoutStringCaching() *IPv6AddressSection {
+	if section == nil {
+		return nil
+	}
+	return section.withoutStringCaching().ToIPv6()
+}
+
 // This produces the mixed IPv6/IPv4 string.  It is the shortest such string (ie fully compressed).
 // For some address sections with ranges of values in the IPv4 part of the address, there is no mixed string, and an error is returned.
 func (section *IPv6AddressSection) toMixedString() (string, addrerr.IncompatibleAddressError) {
@@ -1718,6 +1919,19 @@ func (section *IPv6AddressSection) GetSegmentStrings() []string {
 	return section.getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (section *IPv6AddressSection) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if section == nil {
+		return nil
+	}
+	return section.getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 // ToDivGrouping converts to an AddressDivisionGrouping, a polymorphic type usable with all address sections and division groupings.
 // Afterwards, you can convert back with ToIPv6.
 //
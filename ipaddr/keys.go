@@ -15,7 +15,10 @@
 
 package ipaddr
 
-import "fmt"
+import (
+	"fmt"
+	"hash"
+)
 
 func newSequentialRangeKey[T SequentialRangeConstraint[T]](rng *SequentialRange[T]) (key SequentialRangeKey[T]) {
 	lower := rng.GetLower()
@@ -175,6 +178,13 @@ func (key IPv4AddressKey) String() string {
 	return key.ToAddress().String()
 }
 
+// WriteToHash writes the same bytes into the given hash as does the WriteToHash method of the corresponding address, without allocating.
+func (key IPv4AddressKey) WriteToHash(h hash.Hash) {
+	writeHashHeader(h, ipv4Type, IPv4ByteCount)
+	writeHashBytes(h, key.vals, IPv4ByteCount)
+	writeHashBytes(h, key.vals>>IPv4BitCount, IPv4ByteCount)
+}
+
 type testComparableConstraint[T comparable] struct{}
 
 var (
@@ -194,6 +204,8 @@ var (
 // The zero value corresponds to the zero-value for IPv6Address.
 // Keys do not incorporate prefix length to ensure that all equal addresses have equal keys.
 // To create a key that has prefix length, combine into a struct with the PrefixKey obtained by passing the address into PrefixKeyFrom.
+// Keys do incorporate the zone, so that addresses differing only by zone have different keys.
+// Use WithoutZone to obtain a key that matches all zones of an address.
 // IPv6Address can be compared using the Compare or Equal methods, or using an AddressComparator.
 type IPv6AddressKey struct {
 	keyContents
@@ -209,6 +221,35 @@ func (key IPv6AddressKey) String() string {
 	return key.ToAddress().String()
 }
 
+// WriteToHash writes the same bytes into the given hash as does the WriteToHash method of the corresponding address, without allocating.
+func (key IPv6AddressKey) WriteToHash(h hash.Hash) {
+	key.keyContents.writeToHash(h, ipv6Scheme)
+}
+
+// HasZone returns whether the corresponding address includes a zone or scope.
+func (key IPv6AddressKey) HasZone() bool {
+	return key.zone != NoZone
+}
+
+// GetZone returns the zone of the corresponding address if it has one, otherwise it returns NoZone, which is an empty string.
+func (key IPv6AddressKey) GetZone() Zone {
+	return key.zone
+}
+
+// WithZone returns the key of the corresponding address with the given zone, without converting back to an address instance.
+// The key is the same as that of the address returned by SetZone of the corresponding address.
+func (key IPv6AddressKey) WithZone(zone string) IPv6AddressKey {
+	key.zone = Zone(zone)
+	return key
+}
+
+// WithoutZone returns the key of the corresponding address with no zone, without converting back to an address instance.
+// The key is the same as that of the address returned by WithoutZone of the corresponding address.
+func (key IPv6AddressKey) WithoutZone() IPv6AddressKey {
+	key.zone = NoZone
+	return key
+}
+
 // MACAddressKey is a representation of a MAC address that is comparable as defined by the language specification.
 // See https://go.dev/ref/spec#Comparison_operators
 //
@@ -235,6 +276,41 @@ func (key MACAddressKey) String() string {
 	return key.ToAddress().String()
 }
 
+// WriteToHash writes the same bytes into the given hash as does the WriteToHash method of the corresponding address, without allocating.
+func (key MACAddressKey) WriteToHash(h hash.Hash) {
+	byteCount := int(key.additionalByteCount) + MediaAccessControlSegmentCount
+	writeHashHeader(h, macType, byteCount)
+	writeHashBytes(h, key.vals.lower, byteCount)
+	writeHashBytes(h, key.vals.upper, byteCount)
+}
+
+// AddressKeyConstraint is the generic type constraint for the version-specific and type-specific address keys,
+// [IPv4AddressKey], [IPv6AddressKey] and [MACAddressKey], as well as the generic [Key], which are comparable and can be converted back to addresses of type T.
+// Along with ToKeyConstraint, it allows generic code to use the compact keys of MAC addresses alongside those of IP addresses.
+type AddressKeyConstraint[T any] interface {
+	comparable
+	fmt.Stringer
+	HashWriterTo
+
+	ToAddress() T
+}
+
+// ToKeyConstraint is the generic type constraint for an address type that can generate an address key of type K with its ToKey method.
+type ToKeyConstraint[K any] interface {
+	ToKey() K
+}
+
+type testKeyPairConstraint[T ToKeyConstraint[K], K AddressKeyConstraint[T]] struct{}
+
+var (
+	// ensure the address types and their keys convert to one another
+	_ testKeyPairConstraint[*IPv4Address, IPv4AddressKey]
+	_ testKeyPairConstraint[*IPv6Address, IPv6AddressKey]
+	_ testKeyPairConstraint[*MACAddress, MACAddressKey]
+	_ testKeyPairConstraint[*IPAddress, Key[*IPAddress]]
+	_ testKeyPairConstraint[*Address, Key[*Address]]
+)
+
 // KeyConstraint is the generic type constraint for an address type that can be generated from a generic address key.
 type KeyConstraint[T any] interface {
 	fmt.Stringer
@@ -285,6 +361,24 @@ func (key Key[T]) String() string {
 	return key.ToAddress().String()
 }
 
+// WriteToHash writes the same bytes into the given hash as does the WriteToHash method of the corresponding address, without allocating.
+func (key Key[T]) WriteToHash(h hash.Hash) {
+	scheme := key.scheme
+	var t T
+	switch any(t).(type) {
+	case *IPv4Address:
+		scheme = ipv4Scheme
+	case *IPv6Address:
+		scheme = ipv6Scheme
+	case *MACAddress:
+		// the scheme is populated only for eui64Scheme, see MACAddress.ToGenericKey
+		if scheme != eui64Scheme {
+			scheme = mac48Scheme
+		}
+	}
+	key.keyContents.writeToHash(h, scheme)
+}
+
 type keyContents struct {
 	vals [2]struct {
 		lower,
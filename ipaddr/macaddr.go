@@ -18,6 +18,7 @@ package ipaddr
 
 import (
 	"fmt"
+	"hash"
 	"math/big"
 	"net"
 
@@ -40,6 +41,15 @@ const (
 
 	MACOrganizationalUniqueIdentifierSegmentCount = 3
 
+	// MACOrganizationalUniqueIdentifierBitCount is the bit length of an IEEE MA-L assignment, an OUI.
+	MACOrganizationalUniqueIdentifierBitCount = 24
+
+	// MACMediumAssignmentBitCount is the bit length of an IEEE MA-M assignment.
+	MACMediumAssignmentBitCount = 28
+
+	// MACSmallAssignmentBitCount is the bit length of an IEEE MA-S assignment, which includes the former OUI-36 assignments.
+	MACSmallAssignmentBitCount = 36
+
 	MACSegmentMaxChars = 2
 
 	MACDashSegmentSeparator   = '-'
@@ -347,6 +357,21 @@ func (addr *MACAddress) CopyUpperBytes(bytes []byte) []byte {
 	return addr.init().section.CopyUpperBytes(bytes)
 }
 
+// AppendBytes appends the value of the lowest individual address in the address collection to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices, so a buffer can be reused when encoding many addresses, as in packet encoders.
+// In contrast, Bytes and UpperBytes return a new slice with each call, while CopyBytes and CopyUpperBytes copy from bytes that are calculated and cached by the first call of any of those four methods.
+func (addr *MACAddress) AppendBytes(bytes []byte) []byte {
+	return addr.init().section.AppendBytes(bytes)
+}
+
+// AppendUpperBytes appends the value of the highest individual address in the address collection to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (addr *MACAddress) AppendUpperBytes(bytes []byte) []byte {
+	return addr.init().section.AppendUpperBytes(bytes)
+}
+
 // GetSection returns the backing section for this address or address collection, comprising all segments.
 func (addr *MACAddress) GetSection() *MACAddressSection {
 	return addr.init().section.ToMAC()
@@ -381,6 +406,19 @@ func (addr *MACAddress) GetSegments() []*MACAddressSegment {
 	return addr.GetSection().GetSegments()
 }
 
+// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+// as much as can be fit into the slice, returning the number of segments copied.
+func (addr *MACAddress) CopyGenericSegments(segs []AddressSegmentType) (count int) {
+	return addr.GetSection().CopyGenericSegments(segs)
+}
+
+// GetGenericSegments returns a slice with the address segments as AddressSegmentType,
+// allowing all segment types to be represented by a single type.
+// The returned slice is not backed by the same array as this address.
+func (addr *MACAddress) GetGenericSegments() []AddressSegmentType {
+	return addr.GetSection().GetGenericSegments()
+}
+
 // GetSegment returns the segment at the given index.
 // The first segment is at index 0.
 // GetSegment will panic given a negative index or an index matching or larger than the segment count.
@@ -399,6 +437,33 @@ func (addr *MACAddress) ForEachSegment(consumer func(segmentIndex int, segment *
 	return addr.GetSection().ForEachSegment(consumer)
 }
 
+// ForEachSegmentValue visits each segment in order from most-significant to least, the most significant with index 0,
+// calling the given function with the lower and upper values of each, terminating early if the function returns true.
+// Unlike ForEachSegment, the segments themselves are not provided, and unlike GetSegments, no allocation is required.
+// Returns the number of visited segments.
+func (addr *MACAddress) ForEachSegmentValue(consumer func(segmentIndex int, value, upperValue SegInt, segmentPrefixLen PrefixLen) (stop bool)) int {
+	return addr.GetSection().ForEachSegmentValue(consumer)
+}
+
+// SegmentValuesAppend appends the lower segment values, the segment values of the lowest address in this subnet, to the given slice, returning the extended slice.
+// When the slice has sufficient capacity, no allocation is required.
+func (addr *MACAddress) SegmentValuesAppend(values []SegInt) []SegInt {
+	return addr.GetSection().SegmentValuesAppend(values)
+}
+
+// MapSegments returns the address obtained by replacing each segment with the result of calling the given function on that segment,
+// visiting the segments in order from most-significant to least.
+// A nil segment returned by the function is treated as a zero-valued segment.
+// As with NewMACAddressFromSegs, the prefix length of the result is derived from the resulting segments.
+func (addr *MACAddress) MapSegments(mapper func(seg *MACAddressSegment) *MACAddressSegment) *MACAddress {
+	addr = addr.init()
+	segs := addr.GetSegments()
+	for i, seg := range segs {
+		segs[i] = mapper(seg)
+	}
+	return newMACAddress(NewMACSection(segs))
+}
+
 // GetGenericDivision returns the segment at the given index as a DivisionType.
 func (addr *MACAddress) GetGenericDivision(index int) DivisionType {
 	return addr.init().getDivision(index)
@@ -569,6 +634,12 @@ func (addr *MACAddress) ContainsPrefixBlock(prefixLen BitCount) bool {
 	return addr.init().addressInternal.ContainsPrefixBlock(prefixLen)
 }
 
+// ContainsPrefixBlockBySegment reports, for each segment, whether that segment spans the values required for this address to contain the block of addresses for the given prefix length.
+// ContainsPrefixBlock returns true if and only if every element is true, so the false elements identify the segments preventing a CIDR prefix block.
+func (addr *MACAddress) ContainsPrefixBlockBySegment(prefixLen BitCount) []bool {
+	return addr.init().addressInternal.ContainsPrefixBlockBySegment(prefixLen)
+}
+
 // ContainsSinglePrefixBlock returns whether this address contains a single prefix block for the given prefix length.
 //
 // This means there is only one prefix value for the given prefix length, and it also contains the full prefix block for that prefix, all addresses with that prefix.
@@ -631,6 +702,28 @@ func (addr *MACAddress) Contains(other AddressType) bool {
 	return addr.init().contains(other)
 }
 
+// Relation returns the relation of this address or subnet to the given address or subnet,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+// Addresses and subnets of different types or versions are disjoint.
+func (addr *MACAddress) Relation(other AddressType) Relation {
+	if addr == nil {
+		if other == nil || other.ToAddressBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return addr.init().relation(other)
+}
+
+// IsContainedBy returns whether this is the same type and version as the given address or subnet and whether it is contained by the given address or subnet.
+// It is the reverse of Contains.
+func (addr *MACAddress) IsContainedBy(other AddressType) bool {
+	if other == nil {
+		return addr == nil
+	}
+	return other.ToAddressBase().Contains(addr)
+}
+
 // Equal returns whether the given address or address collection is equal to this address or address collection.
 // Two address instances are equal if they represent the same set of addresses.
 func (addr *MACAddress) Equal(other AddressType) bool {
@@ -827,6 +920,24 @@ func (addr *MACAddress) Increment(increment int64) *MACAddress {
 	return addr.init().increment(increment).ToMAC()
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with 64-bit EUI-64 addresses, where the count of addresses in a subnet can be larger than the range of int64.
+//
+// On address overflow or underflow, IncrementBig returns nil.
+func (addr *MACAddress) IncrementBig(increment *big.Int) *MACAddress {
+	return addr.init().incrementBig(increment).ToMAC()
+}
+
+// Enumerate returns the index of the given individual address within the iteration order of this address or subnet,
+// which is the increment that, when supplied to IncrementBig, produces the given address from this subnet.
+// If the given address is not an individual address contained by this address or subnet, then nil is returned.
+func (addr *MACAddress) Enumerate(other AddressType) *big.Int {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().enumerate(other)
+}
+
 // ReverseBytes returns a new address with the bytes reversed.  Any prefix length is dropped.
 func (addr *MACAddress) ReverseBytes() *MACAddress {
 	return addr.checkIdentity(addr.GetSection().ReverseBytes())
@@ -895,6 +1006,50 @@ func (addr *MACAddress) GetODISection() *MACAddressSection {
 	return addr.GetTrailingSection(MACOrganizationalUniqueIdentifierSegmentCount)
 }
 
+// GetAssignmentSection returns a section with the leading segments covering the given number of bits,
+// as a prefix block with that prefix length.
+//
+// Use MACOrganizationalUniqueIdentifierBitCount, MACMediumAssignmentBitCount or MACSmallAssignmentBitCount
+// to get the 24, 28 or 36-bit prefix identifying the organization for an IEEE MA-L, MA-M or MA-S assignment.
+// A bit count larger than the address bit count is treated as the address bit count.
+func (addr *MACAddress) GetAssignmentSection(bitCount BitCount) *MACAddressSection {
+	addr = addr.init()
+	if bitCount < 0 {
+		bitCount = 0
+	} else if addrBitCount := addr.GetBitCount(); bitCount > addrBitCount {
+		bitCount = addrBitCount
+	}
+	segCount := int((bitCount + MACBitsPerSegment - 1) >> macBitsToSegmentBitshift)
+	return addr.GetSubSection(0, segCount).ToPrefixBlockLen(bitCount)
+}
+
+// OUIResolver resolves the organization to which an IEEE MAC address block was assigned,
+// allowing applications to supply the IEEE registry data in whatever form they maintain it.
+type OUIResolver interface {
+	// ResolveOrganization returns the name of the organization that was assigned the given block, and whether such an organization is known.
+	// The block is a prefix block section, as returned by MACAddress.GetAssignmentSection,
+	// whose prefix length is one of MACOrganizationalUniqueIdentifierBitCount, MACMediumAssignmentBitCount, or MACSmallAssignmentBitCount.
+	ResolveOrganization(assignment *MACAddressSection) (organization string, found bool)
+}
+
+// GetOrganization uses the given resolver to look up the organization assigned the block containing this address.
+//
+// Since MA-M and MA-S blocks are allocated from within MA-L blocks, the resolver is queried with the 36-bit MA-S prefix first,
+// then with the 28-bit MA-M prefix, and finally with the 24-bit OUI, returning the first organization found.
+// If this is a collection of addresses spanning more than one block of a given size, the resolver is not queried with that size.
+func (addr *MACAddress) GetOrganization(resolver OUIResolver) (organization string, found bool) {
+	for _, bitCount := range []BitCount{MACSmallAssignmentBitCount, MACMediumAssignmentBitCount, MACOrganizationalUniqueIdentifierBitCount} {
+		section := addr.GetAssignmentSection(bitCount)
+		if !section.IsSinglePrefixBlock() {
+			continue
+		}
+		if organization, found = resolver.ResolveOrganization(section); found {
+			return
+		}
+	}
+	return
+}
+
 // ToOUIPrefixBlock returns a section in which the range of values match the full block for the OUI (organizationally unique identifier) bytes
 func (addr *MACAddress) ToOUIPrefixBlock() *MACAddress {
 	segmentCount := addr.GetSegmentCount()
@@ -1026,6 +1181,7 @@ func (addr *MACAddress) String() string {
 // Format implements [fmt.Formatter] interface. It accepts the formats
 //  - 'v' for the default address and section format (either the normalized or canonical string),
 //  - 's' (string) for the same,
+//  - 'S' (uppercase string) for the same string with uppercase hexadecimal digits,
 //  - 'b' (binary), 'o' (octal with 0 prefix), 'O' (octal with 0o prefix),
 //  - 'd' (decimal), 'x' (lowercase hexadecimal), and
 //  - 'X' (uppercase hexadecimal).
@@ -1047,6 +1203,19 @@ func (addr *MACAddress) GetSegmentStrings() []string {
 	return addr.init().getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (addr *MACAddress) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if addr == nil {
+		return nil
+	}
+	return addr.init().getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 // ToCanonicalString produces a canonical string for the address.
 //
 // For MAC, it uses the canonical standardized IEEE 802 MAC address representation of xx-xx-xx-xx-xx-xx.  An example is "01-23-45-67-89-ab".
@@ -1088,6 +1257,29 @@ func (addr *MACAddress) ToCompressedString() string {
 	return addr.init().toCompressedString()
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived addresses whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this address with WithoutStringCaching.
+func (addr *MACAddress) WarmStrings() {
+	if addr != nil {
+		addr.ToCanonicalString()
+		addr.ToNormalizedString()
+		addr.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address that does not cache the strings it produces, sharing the same segments.
+// See the WithoutStringCaching method of the address sections for the memory trade-offs of string caching.
+func (addr *MACAddress) WithoutStringCaching() *MACAddress {
+	if addr == nil {
+		return nil
+	}
+	addr = addr.init()
+	return newMACAddress(addr.GetSection().WithoutStringCaching())
+}
+
 // ToHexString writes this address as a single hexadecimal value (possibly two values if a range),
 // the number of digits according to the bit count, with or without a preceding "0x" prefix.
 //
@@ -1166,11 +1358,15 @@ func (addr *MACAddress) ToColonDelimitedString() string {
 }
 
 // ToCustomString creates a customized string from this address or address collection according to the given string option parameters.
+//
+// When the options are MACStringOptions with a segment grouping larger than 1, adjacent segments are joined and written as a single segment,
+// as with the dotted format aaaa.bbbb.cccc.  If the segments of a collection cannot be joined, because a segment with a range of values
+// is followed by a segment not spanning all values, the segments are written individually.
 func (addr *MACAddress) ToCustomString(stringOptions addrstr.StringOptions) string {
 	if addr == nil {
 		return nilString()
 	}
-	return addr.init().GetSection().toCustomString(stringOptions)
+	return addr.init().GetSection().ToCustomString(stringOptions)
 }
 
 // ToAddressString retrieves or generates a MACAddressString instance for this MACAddress instance.
@@ -1219,6 +1415,15 @@ func (addr *MACAddress) Wrap() WrappedAddress {
 	return wrapAddress(addr.ToAddressBase())
 }
 
+// WriteToHash writes the canonical bytes of this address into the given hash, without allocating:
+// the address type, byte count, and the bytes of the lowest and highest values.
+// The prefix length is not written.  The same bytes are written by the keys for this address.  See HashWriterTo for details.
+func (addr *MACAddress) WriteToHash(h hash.Hash) {
+	if addr != nil {
+		addr.init().writeToHash(h)
+	}
+}
+
 // ToKey creates the associated address key.
 // While addresses can be compared with the Compare, TrieCompare or Equal methods as well as various provided instances of AddressComparator,
 // they are not comparable with Go operators.
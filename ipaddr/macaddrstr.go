@@ -17,6 +17,7 @@
 package ipaddr
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"strings"
 
@@ -330,3 +331,43 @@ func (addrStr *MACAddressString) Equal(other *MACAddressString) bool {
 func (addrStr *MACAddressString) Wrap() ExtendedIdentifierString {
 	return WrappedMACAddressString{addrStr}
 }
+
+// MarshalText implements the [encoding.TextMarshaler] interface, producing the original string used to create this MACAddressString.
+func (addrStr *MACAddressString) MarshalText() ([]byte, error) {
+	return []byte(addrStr.String()), nil
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface, parsing the text with the default parameters.
+// It returns the validation error if the text is not a valid MAC address string, in which case the receiver holds the invalid string.
+func (addrStr *MACAddressString) UnmarshalText(text []byte) error {
+	*addrStr = *NewMACAddressString(string(text))
+	if err := addrStr.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Value implements the [database/sql/driver.Valuer] interface, storing the original string used to create this MACAddressString.
+// A nil MACAddressString or the empty string is stored as NULL, which Scan converts back to the empty MACAddressString.
+func (addrStr *MACAddressString) Value() (driver.Value, error) {
+	if addrStr == nil || addrStr.str == "" {
+		return nil, nil
+	}
+	return addrStr.str, nil
+}
+
+// Scan implements the [database/sql.Scanner] interface, parsing a string or byte slice column value with the default parameters.
+// A NULL column value produces the empty MACAddressString.
+// It returns the validation error if the value is not a valid MAC address string.
+func (addrStr *MACAddressString) Scan(src interface{}) error {
+	switch val := src.(type) {
+	case nil:
+		*addrStr = MACAddressString{}
+		return nil
+	case string:
+		return addrStr.UnmarshalText([]byte(val))
+	case []byte:
+		return addrStr.UnmarshalText(val)
+	}
+	return errorF("unsupported type %T for MAC address string", src)
+}
@@ -188,6 +188,29 @@ func (section *MACAddressSection) Contains(other AddressSectionType) bool {
 	return section.contains(other)
 }
 
+// Relation returns the relation of this address section to the given address section,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+//
+// Sections must have the same type, version and number of segments to be comparable, otherwise RelationDisjoint is returned.
+func (section *MACAddressSection) Relation(other AddressSectionType) Relation {
+	if section == nil {
+		if other == nil || other.ToSectionBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return section.relation(other)
+}
+
+// IsContainedBy returns whether this is same type and version as the given address section and whether all its values are contained in the given section.
+// It is the reverse of Contains.
+func (section *MACAddressSection) IsContainedBy(other AddressSectionType) bool {
+	if other == nil {
+		return section == nil
+	}
+	return other.ToSectionBase().Contains(section)
+}
+
 // Equal returns whether the given address section is equal to this address section.
 // Two address sections are equal if they represent the same set of sections.
 // They must match:
@@ -650,6 +673,44 @@ func (section *MACAddressSection) Increment(incrementVal int64) *MACAddressSecti
 		section.getPrefixLen()).ToMAC()
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with 64-bit EUI-64 address sections, where the count of sections in a subnet can be larger than the range of int64.
+//
+// On overflow or underflow, IncrementBig returns nil.
+func (section *MACAddressSection) IncrementBig(increment *big.Int) *MACAddressSection {
+	return incrementBigInt(
+		section.ToSectionBase(),
+		increment,
+		macNetwork.getAddressCreator(),
+		section.addressSectionInternal.getLower,
+		section.addressSectionInternal.getUpper,
+		section.getPrefixLen()).ToMAC()
+}
+
+// Enumerate returns the index of the given individual address section within the iteration order of this address section,
+// which is the increment that, when supplied to IncrementBig, produces the given section from this one.
+// If the given section is not an individual address section contained by this address section, then nil is returned.
+func (section *MACAddressSection) Enumerate(other AddressSectionType) *big.Int {
+	if section == nil {
+		return nil
+	}
+	return section.enumerate(other)
+}
+
+// AddToSegment adds the given delta to the value of the segment at the given index,
+// carrying into the preceding, more significant, segments whenever a segment value overflows or underflows.
+// This is the same as adding the delta multiplied by the value count of each of the segments following the index.
+// The following segments and the prefix length remain the same.  If the delta is zero, this section is returned.
+//
+// If this represents multiple values, the delta is added to each.
+// Nil is returned if the results cannot be represented as a range of values in each segment,
+// which is the case when some values in a segment range carry into the preceding segment and others do not.
+//
+// On overflow or underflow, or if the index is not the index of a segment of this section, AddToSegment returns nil.
+func (section *MACAddressSection) AddToSegment(index int, delta int64) *MACAddressSection {
+	return section.addToSegment(index, delta).ToMAC()
+}
+
 // ReverseBits returns a new section with the bits reversed.  Any prefix length is dropped.
 //
 // If the bits within a single segment cannot be reversed because the segment represents a range,
@@ -808,6 +869,33 @@ func (section *MACAddressSection) ToCompressedString() string {
 		})
 }
 
+// WarmStrings produces and caches the canonical, normalized and compressed strings of this address section,
+// so that later calls for those strings, possibly from multiple goroutines, return the cached strings without producing them.
+// This is useful for long-lived sections whose strings are required on performance-sensitive paths.
+//
+// It has no effect when string caching is disabled, either globally with SetStringCaching or for this section with WithoutStringCaching.
+func (section *MACAddressSection) WarmStrings() {
+	if section != nil {
+		section.ToCanonicalString()
+		section.ToNormalizedString()
+		section.ToCompressedString()
+	}
+}
+
+// WithoutStringCaching returns a copy of this address section that does not cache the strings it produces, sharing the same segments.
+//
+// Each string produced by an address section is cached by default, which speeds up repeated calls for the same string,
+// but retains the memory of each string as long as the section is retained.
+// For short-lived sections whose strings are produced only once, or for large numbers of retained sections whose strings are rarely produced,
+// a section without string caching uses less memory.  Sections derived from the returned section do cache their strings.
+// To disable string caching for all address items, use SetStringCaching.
+func (section *MACAddressSection) WithoutStringCaching() *MACAddressSection {
+	if section == nil {
+		return nil
+	}
+	return section.withoutStringCaching().ToMAC()
+}
+
 // ToDottedString produces the dotted hexadecimal format "aaaa.bbbb.cccc".
 func (section *MACAddressSection) ToDottedString() (string, addrerr.IncompatibleAddressError) {
 	if section == nil {
@@ -864,6 +952,53 @@ func (section *MACAddressSection) GetDottedGrouping() (*AddressDivisionGrouping,
 	return grouping, nil
 }
 
+// getJoinedGrouping returns an AddressDivisionGrouping which joins each of the given number of adjacent segments into a single division.
+// When the segment count is not a multiple of the given number, the final division joins the remaining segments.
+//
+// If this represents a collection of MAC addresses, this returns an error when unable to join address segments,
+// a segment with a range of values followed by a segment not spanning all values, into a division of the larger bit-length that represents the same set of values.
+func (section *MACAddressSection) getJoinedGrouping(segmentsPerDivision int) (*AddressDivisionGrouping, addrerr.IncompatibleAddressError) {
+	segmentCount := section.GetSegmentCount()
+	bitsPerSeg := section.GetBitsPerSegment()
+	newSegs := make([]*AddressDivision, 0, (segmentCount+segmentsPerDivision-1)/segmentsPerDivision)
+	for segIndex := 0; segIndex < segmentCount; segIndex += segmentsPerDivision {
+		endIndex := segIndex + segmentsPerDivision
+		if endIndex > segmentCount {
+			endIndex = segmentCount
+		}
+		var val, upperVal DivInt
+		var previousMultiple bool
+		for i := segIndex; i < endIndex; i++ {
+			segment := section.GetSegment(i)
+			if previousMultiple && !segment.IsFullRange() {
+				return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.invalid.joined.ranges"}}
+			}
+			previousMultiple = previousMultiple || segment.isMultiple()
+			val = (val << uint(bitsPerSeg)) | DivInt(segment.GetSegmentValue())
+			upperVal = (upperVal << uint(bitsPerSeg)) | DivInt(segment.GetUpperSegmentValue())
+		}
+		newSegs = append(newSegs, createAddressDivision(newRangeDivision(val, upperVal, BitCount(endIndex-segIndex)*bitsPerSeg)))
+	}
+	return createInitializedGrouping(newSegs, section.getPrefixLen()), nil
+}
+
+// ToCustomString creates a customized string from this address section or address collection section according to the given string option parameters.
+//
+// When the options are MACStringOptions with a segment grouping larger than 1, adjacent segments are joined and written as a single segment,
+// as with the dotted format aaaa.bbbb.cccc.  If the segments of a collection cannot be joined, because a segment with a range of values
+// is followed by a segment not spanning all values, the segments are written individually.
+func (section *MACAddressSection) ToCustomString(stringOptions addrstr.StringOptions) string {
+	if section == nil {
+		return nilString()
+	}
+	if macOptions, ok := stringOptions.(addrstr.MACStringOptions); ok && macOptions.GetSegmentGrouping() > 1 {
+		if grouping, err := section.getJoinedGrouping(macOptions.GetSegmentGrouping()); err == nil {
+			return toNormalizedString(stringOptions, grouping)
+		}
+	}
+	return section.toCustomString(stringOptions)
+}
+
 // ToSpaceDelimitedString produces a string delimited by spaces: "aa bb cc dd ee ff".
 func (section *MACAddressSection) ToSpaceDelimitedString() string {
 	if section == nil {
@@ -914,3 +1049,16 @@ func (section *MACAddressSection) GetSegmentStrings() []string {
 	}
 	return section.getSegmentStrings()
 }
+
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (section *MACAddressSection) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if section == nil {
+		return nil
+	}
+	return section.getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
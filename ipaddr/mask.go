@@ -313,6 +313,9 @@ func (masker specificValueBitwiseOrer) GetOredUpper(upperValue, maskValue uint64
 //
 //
 // MaskExtendedRange masks divisions with bit counts larger than 64 bits. Use MaskRange for smaller divisions.
+//
+// Each value is supplied as two 64-bit halves, the extended value holding the high bits and the other value holding the low bits.
+// The semantics otherwise match those of MaskRange.
 func MaskExtendedRange(
 	value, extendedValue,
 	upperValue, extendedUpperValue,
@@ -469,6 +472,11 @@ func MaskExtendedRange(
 }
 
 // MaskRange masks divisions with bit counts 64 bits or smaller. Use MaskExtendedRange for larger divisions.
+//
+// The range of values from value to upperValue inclusive is masked with maskValue, and maxValue is the largest value for the division bit count.
+// The returned Masker indicates whether the masked values are sequential, meaning that they form a single range of values with no gaps.
+// When they are sequential, it provides the lowest and highest of the masked values, which are not necessarily the masked lowest and highest values of the range.
+// When they are not, the masked values cannot be represented as a single range, and the lowest and highest it provides are bounds of those values that are not necessarily attained.
 func MaskRange(value, upperValue, maskValue, maxValue uint64) Masker {
 	if value == upperValue {
 		return defaultMasker
@@ -574,7 +582,13 @@ func MaskRange(value, upperValue, maskValue, maxValue uint64) Masker {
 	return defaultMasker
 }
 
-func bitwiseOrRange(value, upperValue, maskValue, maxValue uint64) BitwiseOrer {
+// BitwiseOrRange applies bitwise disjunction to divisions with bit counts 64 bits or smaller.
+//
+// The range of values from value to upperValue inclusive is ored with maskValue, and maxValue is the largest value for the division bit count.
+// The returned BitwiseOrer indicates whether the ored values are sequential, meaning that they form a single range of values with no gaps.
+// When they are sequential, it provides the lowest and highest of the ored values, which are not necessarily the ored lowest and highest values of the range.
+// When they are not, the ored values cannot be represented as a single range, and the lowest and highest it provides are bounds of those values that are not necessarily attained.
+func BitwiseOrRange(value, upperValue, maskValue, maxValue uint64) BitwiseOrer {
 	if value == upperValue {
 		return defaultOrMasker
 	}
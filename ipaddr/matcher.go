@@ -0,0 +1,134 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrstrparam"
+)
+
+var matcherParams = new(addrstrparam.IPAddressStringParamsBuilder).
+	AllowEmpty(false).GetIPv6AddressParamsBuilder().AllowZone(false).GetParentBuilder().ToParams()
+
+// Matcher matches addresses against compiled address patterns, such as "10.1-3.*.5" or "a:b:*::1-ff".
+//
+// Patterns are parsed as are IP address strings, allowing wildcards, ranges, and CIDR prefix lengths,
+// but not zones or empty strings.  The pattern "*" matches all IPv4 and IPv6 addresses.
+// Each pattern is compiled to the bounds of each segment not spanning all segment values,
+// so matching an address requires no allocation and no subnet construction.
+//
+// A Matcher is immutable and can be shared amongst goroutines.
+type Matcher struct {
+	patterns []addressPattern
+	matchAll bool
+}
+
+type segmentBounds struct {
+	index        int
+	lower, upper SegInt
+}
+
+type addressPattern struct {
+	addrType addrType // zeroType matches both IPv4 and IPv6
+	bounds   []segmentBounds
+}
+
+func compileAddressPattern(pattern string) (result addressPattern, err addrerr.AddressError) {
+	addrStr := NewIPAddressStringParams(pattern, matcherParams)
+	if err = addrStr.Validate(); err != nil {
+		return
+	} else if addrStr.IsAllAddresses() {
+		return
+	}
+	addr, err := addrStr.ToAddress()
+	if err != nil {
+		return
+	}
+	result.addrType = addr.getAddrType()
+	maxValue := addr.GetMaxSegmentValue()
+	for i, count := 0, addr.GetSegmentCount(); i < count; i++ {
+		seg := addr.getSegment(i)
+		if lower, upper := seg.GetSegmentValue(), seg.GetUpperSegmentValue(); lower != 0 || upper != maxValue {
+			result.bounds = append(result.bounds, segmentBounds{index: i, lower: lower, upper: upper})
+		}
+	}
+	return
+}
+
+// matches returns whether the pattern contains all the addresses of the given address or subnet.
+func (pattern *addressPattern) matches(addr *IPAddress) bool {
+	addrType := addr.getAddrType()
+	if pattern.addrType != addrType {
+		return pattern.addrType == zeroType && (addrType.isIPv4() || addrType.isIPv6())
+	}
+	section := addr.section
+	for _, bounds := range pattern.bounds {
+		seg := section.GetSegment(bounds.index)
+		if seg.GetSegmentValue() < bounds.lower || seg.GetUpperSegmentValue() > bounds.upper {
+			return false
+		}
+	}
+	return true
+}
+
+func compileMatcher(patterns []string, matchAll bool) (*Matcher, addrerr.AddressError) {
+	compiled := make([]addressPattern, len(patterns))
+	for i, pattern := range patterns {
+		var err addrerr.AddressError
+		if compiled[i], err = compileAddressPattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+	return &Matcher{patterns: compiled, matchAll: matchAll}, nil
+}
+
+// CompileMatcher compiles the given pattern into a Matcher that matches the addresses and subnets within the pattern.
+// An error is returned if the pattern is not a valid IP address string.
+func CompileMatcher(pattern string) (*Matcher, addrerr.AddressError) {
+	return compileMatcher([]string{pattern}, false)
+}
+
+// CompileAnyMatcher compiles the given patterns into a Matcher that matches the addresses and subnets within any of the patterns.
+// With no patterns, the Matcher matches nothing.
+// An error is returned if any pattern is not a valid IP address string.
+func CompileAnyMatcher(patterns ...string) (*Matcher, addrerr.AddressError) {
+	return compileMatcher(patterns, false)
+}
+
+// CompileAllMatcher compiles the given patterns into a Matcher that matches the addresses and subnets within all the patterns.
+// With no patterns, the Matcher matches all IPv4 and IPv6 addresses.
+// An error is returned if any pattern is not a valid IP address string.
+func CompileAllMatcher(patterns ...string) (*Matcher, addrerr.AddressError) {
+	return compileMatcher(patterns, true)
+}
+
+// Matches returns whether the given address matches the patterns of this Matcher.
+// A subnet matches a pattern when all of its addresses match the pattern.
+// Prefix lengths and zones of the given address are ignored.
+// Matches returns false for a nil address.
+func (matcher *Matcher) Matches(addr *IPAddress) bool {
+	if addr == nil {
+		return false
+	}
+	addr = addr.init()
+	for i := range matcher.patterns {
+		if matcher.patterns[i].matches(addr) != matcher.matchAll {
+			return !matcher.matchAll
+		}
+	}
+	return matcher.matchAll && (addr.IsIPv4() || addr.IsIPv6())
+}
@@ -0,0 +1,96 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// MustParseIP returns the IP address or subnet parsed from the given string, as provided by ToAddress of IPAddressString.
+// It panics if the string is invalid, or if it does not specify an IPv4 or IPv6 address or subnet, as with "*".
+//
+// MustParseIP and the other Must functions are intended for address literals known to be valid, such as those in tests and package-level variables.
+// The panic value is an error wrapping the parsing error, with a message naming the function and the literal.
+func MustParseIP(str string) *IPAddress {
+	addr, err := NewIPAddressString(str).ToAddress()
+	if err == nil && addr == nil {
+		err = &addressStringError{addressError{str: str, key: "ipaddress.error.ipVersionIndeterminate"}}
+	}
+	return mustParse(addr, err, "MustParseIP", str)
+}
+
+// MustParseIPv4 returns the IPv4 address or subnet parsed from the given string.
+// It panics if the string is invalid or does not specify an IPv4 address or subnet.
+func MustParseIPv4(str string) *IPv4Address {
+	addr, err := NewIPAddressString(str).ToAddress()
+	var ipv4Addr *IPv4Address
+	if err == nil {
+		if ipv4Addr = addr.ToIPv4(); ipv4Addr == nil {
+			err = versionMismatchError(addr, str)
+		}
+	}
+	return mustParse(ipv4Addr, err, "MustParseIPv4", str)
+}
+
+// MustParseIPv6 returns the IPv6 address or subnet parsed from the given string.
+// It panics if the string is invalid or does not specify an IPv6 address or subnet.
+func MustParseIPv6(str string) *IPv6Address {
+	addr, err := NewIPAddressString(str).ToAddress()
+	var ipv6Addr *IPv6Address
+	if err == nil {
+		if ipv6Addr = addr.ToIPv6(); ipv6Addr == nil {
+			err = versionMismatchError(addr, str)
+		}
+	}
+	return mustParse(ipv6Addr, err, "MustParseIPv6", str)
+}
+
+// MustParseCIDR returns the prefix block parsed from the given CIDR string, such as "1.2.0.0/16".
+// Like FromCIDRList, it accepts an individual address without a prefix length as the prefix block of that address alone.
+// It panics if the string is not the CIDR notation of a single IPv4 or IPv6 prefix block, including when host bits are set, as with "1.2.3.4/16".
+func MustParseCIDR(str string) *IPAddress {
+	block, err := parseCIDRBlock(str)
+	return mustParse(block, err, "MustParseCIDR", str)
+}
+
+// MustParseMAC returns the MAC address or address collection parsed from the given string, as provided by ToAddress of MACAddressString.
+// It panics if the string is invalid.
+func MustParseMAC(str string) *MACAddress {
+	addr, err := NewMACAddressString(str).ToAddress()
+	if err == nil && addr == nil {
+		err = &addressStringError{addressError{str: str, key: "ipaddress.error.empty"}}
+	}
+	return mustParse(addr, err, "MustParseMAC", str)
+}
+
+// versionMismatchError returns the error for a string not specifying an address of the required version
+func versionMismatchError(addr *IPAddress, str string) addrerr.AddressError {
+	key := "ipaddress.error.ipVersionIndeterminate"
+	if addr.IsIPv4() {
+		key = "ipaddress.error.address.is.ipv4"
+	} else if addr.IsIPv6() {
+		key = "ipaddress.error.address.is.ipv6"
+	}
+	return &addressStringError{addressError{str: str, key: key}}
+}
+
+func mustParse[T any](addr T, err addrerr.AddressError, funcName, str string) T {
+	if err != nil {
+		panic(wrapErrf(err, "ipaddr: %s(%q)", funcName, str))
+	}
+	return addr
+}
@@ -0,0 +1,170 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"net"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// InterfaceAddress is an address assigned to a local network interface, as returned by GetInterfaceAddresses.
+type InterfaceAddress struct {
+	// Interface is the network interface to which the address is assigned.
+	Interface net.Interface
+
+	// Address is the assigned address, with the prefix length of the interface's subnet, such as "192.168.1.5/24",
+	// and with the interface name as the zone for IPv6 link-local addresses, such as "fe80::1%eth0/64".
+	Address *IPAddress
+}
+
+// GetSubnet returns the prefix block subnet of the interface address, such as "192.168.1.0/24" for "192.168.1.5/24".
+// The zone of the address, if any, is retained.
+func (ifaceAddr InterfaceAddress) GetSubnet() *IPAddress {
+	return ifaceAddr.Address.ToPrefixBlock()
+}
+
+// String returns the interface name followed by the address, such as "eth0 192.168.1.5/24".
+func (ifaceAddr InterfaceAddress) String() string {
+	return ifaceAddr.Interface.Name + " " + ifaceAddr.Address.String()
+}
+
+// NewIPAddressesFromInterface returns the addresses assigned to the given network interface, as provided by its Addrs method.
+//
+// Each address has the prefix length of the interface's subnet, obtained from the network mask.
+// IPv6 link-local addresses have the interface name as their zone, so that they can be used to reach neighbours on that interface.
+//
+// An error is returned if the addresses of the interface cannot be retrieved, or if an address cannot be converted.
+func NewIPAddressesFromInterface(iface *net.Interface) ([]*IPAddress, error) {
+	netAddrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*IPAddress, 0, len(netAddrs))
+	for _, netAddr := range netAddrs {
+		addr, err := newIPAddressFromInterfaceAddr(iface, netAddr)
+		if err != nil {
+			return nil, err
+		} else if addr != nil {
+			result = append(result, addr)
+		}
+	}
+	return result, nil
+}
+
+func newIPAddressFromInterfaceAddr(iface *net.Interface, netAddr net.Addr) (*IPAddress, addrerr.AddressError) {
+	var ipAddr net.IPAddr
+	var prefLen PrefixLen
+	switch a := netAddr.(type) {
+	case *net.IPNet:
+		ipAddr.IP = a.IP
+		mask := a.Mask
+		if ipv4 := a.IP.To4(); ipv4 != nil {
+			ipAddr.IP = ipv4
+			if len(mask) == net.IPv6len {
+				mask = mask[IPv6MixedOriginalByteCount:]
+			}
+		}
+		if ones, bits := mask.Size(); bits == 0 {
+			return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.notNetworkMask"}}
+		} else if bits == len(ipAddr.IP)<<3 {
+			prefLen = cacheBitCount(ones)
+		}
+	case *net.IPAddr:
+		ipAddr = *a
+	default:
+		return nil, nil
+	}
+	if ipAddr.Zone == "" && ipAddr.IP.To4() == nil && (ipAddr.IP.IsLinkLocalUnicast() || ipAddr.IP.IsLinkLocalMulticast()) {
+		ipAddr.Zone = iface.Name
+	}
+	addr, err := NewIPAddressFromPrefixedNetIPAddr(&ipAddr, prefLen)
+	if err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+// GetInterfaceAddresses returns the addresses assigned to the local network interfaces, as provided by net.Interfaces,
+// with each converted as described by NewIPAddressesFromInterface.
+func GetInterfaceAddresses() ([]InterfaceAddress, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var result []InterfaceAddress
+	for i := range ifaces {
+		addrs, err := NewIPAddressesFromInterface(&ifaces[i])
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			result = append(result, InterfaceAddress{Interface: ifaces[i], Address: addr})
+		}
+	}
+	return result, nil
+}
+
+// FindInterfaceFor returns the local interface address whose subnet contains the given address,
+// which identifies the interface through which the address is directly reachable.
+// When the subnets of more than one interface address contain the address, the one with the longest prefix length is returned.
+// If the given address has a zone, only the interface with the zone as its name is considered.
+//
+// It returns nil if no local subnet contains the address, and an error if the interface addresses cannot be retrieved.
+func FindInterfaceFor(addr *IPAddress) (*InterfaceAddress, error) {
+	ifaceAddrs, err := GetInterfaceAddresses()
+	if err != nil {
+		return nil, err
+	}
+	return FindInterfaceAddressFor(ifaceAddrs, addr), nil
+}
+
+// FindInterfaceAddressFor returns the element of the given interface addresses whose subnet contains the given address,
+// choosing as FindInterfaceFor does, or nil if there is none.
+func FindInterfaceAddressFor(ifaceAddrs []InterfaceAddress, addr *IPAddress) (result *InterfaceAddress) {
+	if addr == nil {
+		return nil
+	}
+	var zone Zone
+	if addr.IsIPv6() {
+		zone = addr.ToIPv6().GetZone()
+	}
+	addr = withoutIPZone(addr.WithoutPrefixLen())
+	var resultLen BitCount = -1
+	for i := range ifaceAddrs {
+		ifaceAddr := &ifaceAddrs[i]
+		if zone != NoZone && string(zone) != ifaceAddr.Interface.Name {
+			continue
+		}
+		prefLen := ifaceAddr.Address.GetBitCount()
+		if ifacePrefLen := ifaceAddr.Address.GetPrefixLen(); ifacePrefLen != nil {
+			prefLen = ifacePrefLen.bitCount()
+		}
+		if prefLen > resultLen && withoutIPZone(ifaceAddr.Address).ToPrefixBlockLen(prefLen).Contains(addr) {
+			result, resultLen = ifaceAddr, prefLen
+		}
+	}
+	return
+}
+
+// withoutIPZone returns the address without its zone, since an address with a zone does not contain the same address with another zone or none
+func withoutIPZone(addr *IPAddress) *IPAddress {
+	if addr.IsIPv6() {
+		return addr.ToIPv6().WithoutZone().ToIP()
+	}
+	return addr
+}
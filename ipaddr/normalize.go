@@ -0,0 +1,178 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrstrparam"
+)
+
+var normalizeIPv6Params = new(addrstrparam.IPAddressStringParamsBuilder).
+	AllowIPv4(false).AllowEmpty(false).ToParams()
+
+// NormalizeIPv6String returns the canonical string of the IPv6 address or subnet given by the string,
+// which is the same string produced by ToCanonicalString on the parsed address.
+// Strings differing only in case, leading zeros, or zero-segment compression normalize to the same string.
+//
+// Strings comprising only hexadecimal segments, optionally with a single compressed "::", are normalized
+// directly without constructing an address.  All other strings, such as those with prefix lengths, zones, ranges, or embedded IPv4 addresses,
+// are parsed as IPAddressString instances would be, with the exception that IPv4 and empty strings are rejected.
+//
+// An error is returned if the string is not a valid IPv6 address string.
+func NormalizeIPv6String(str string) (string, error) {
+	str = strings.TrimSpace(str)
+	if segs, ok := parseSimpleIPv6(str); ok {
+		return toCanonicalIPv6String(&segs), nil
+	}
+	addr, err := NewIPAddressStringParams(str, normalizeIPv6Params).ToAddress()
+	if err != nil {
+		return "", err
+	}
+	return addr.ToCanonicalString(), nil
+}
+
+// parseSimpleIPv6 parses IPv6 strings consisting of at most 8 segments of one to four hex digits, with at most one "::" compression.
+// It returns false for all other strings, whether valid or not, so that they can be handled by the full parser.
+func parseSimpleIPv6(str string) (segs [IPv6SegmentCount]uint16, ok bool) {
+	if len(str) < 2 || len(str) > 39 {
+		return
+	}
+	compressIndex := strings.Index(str, "::")
+	if compressIndex < 0 {
+		var count int
+		count, ok = parseSimpleIPv6Segments(str, segs[:])
+		ok = ok && count == IPv6SegmentCount
+		return
+	}
+	head, tail := str[:compressIndex], str[compressIndex+2:]
+	headCount, headOk := parseSimpleIPv6Segments(head, segs[:IPv6SegmentCount-1])
+	if !headOk {
+		return
+	}
+	var tailSegs [IPv6SegmentCount - 1]uint16
+	tailCount, tailOk := parseSimpleIPv6Segments(tail, tailSegs[:IPv6SegmentCount-1-headCount])
+	if !tailOk {
+		return
+	}
+	copy(segs[IPv6SegmentCount-tailCount:], tailSegs[:tailCount])
+	ok = true
+	return
+}
+
+// parseSimpleIPv6Segments parses colon-separated segments of one to four hex digits into the given slice,
+// failing if there are more segments than the slice can hold.  An empty string has no segments.
+func parseSimpleIPv6Segments(str string, segs []uint16) (count int, ok bool) {
+	if len(str) == 0 {
+		ok = true
+		return
+	}
+	digitCount := 0
+	var value uint16
+	for i := 0; i <= len(str); i++ {
+		if i == len(str) || str[i] == IPv6SegmentSeparator {
+			if digitCount == 0 || count == len(segs) {
+				return
+			}
+			segs[count] = value
+			count++
+			value, digitCount = 0, 0
+			continue
+		}
+		c := str[i]
+		var digit byte
+		if c >= '0' && c <= '9' {
+			digit = c - '0'
+		} else if c >= 'a' && c <= 'f' {
+			digit = c - 'a' + 10
+		} else if c >= 'A' && c <= 'F' {
+			digit = c - 'A' + 10
+		} else {
+			return
+		}
+		if digitCount == 4 {
+			return
+		}
+		value = value<<4 | uint16(digit)
+		digitCount++
+	}
+	ok = true
+	return
+}
+
+// toCanonicalIPv6String writes the segments in the canonical form of RFC 5952,
+// compressing the leftmost longest run of two or more zero segments
+func toCanonicalIPv6String(segs *[IPv6SegmentCount]uint16) string {
+	compressIndex, compressCount := -1, 1
+	for i := 0; i < IPv6SegmentCount; {
+		if segs[i] != 0 {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < IPv6SegmentCount && segs[j] == 0 {
+			j++
+		}
+		if j-i > compressCount {
+			compressIndex, compressCount = i, j-i
+		}
+		i = j
+	}
+	var builder strings.Builder
+	builder.Grow(39)
+	for i := 0; i < IPv6SegmentCount; i++ {
+		if i == compressIndex {
+			builder.WriteString("::")
+			i += compressCount - 1
+			continue
+		}
+		if i > 0 && i != compressIndex+compressCount {
+			builder.WriteByte(IPv6SegmentSeparator)
+		}
+		seg := segs[i]
+		started := false
+		for shift := 12; shift >= 0; shift -= 4 {
+			digit := (seg >> uint(shift)) & 0xf
+			if digit != 0 || started || shift == 0 {
+				builder.WriteByte(digits[digit])
+				started = true
+			}
+		}
+	}
+	return builder.String()
+}
+
+var stringCachingDisabled uint32
+
+// SetStringCaching controls whether strings produced by addresses, sections, segments, and other address items are cached within those items.
+// Caching is enabled by default.  Disabling it reduces memory usage for workloads that produce strings from a large number of long-lived addresses,
+// at the cost of recomputing each string on every call.  Strings cached before caching is disabled remain cached.
+//
+// SetStringCaching can be called concurrently with the use of addresses.
+func SetStringCaching(enabled bool) {
+	var val uint32
+	if !enabled {
+		val = 1
+	}
+	atomic.StoreUint32(&stringCachingDisabled, val)
+}
+
+// IsStringCaching returns whether strings produced by address items are cached within those items, as configured with SetStringCaching.
+func IsStringCaching() bool {
+	return atomic.LoadUint32(&stringCachingDisabled) == 0
+}
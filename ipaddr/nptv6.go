@@ -0,0 +1,199 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+const nptv6SubnetIndex = 3 // the index of the segment holding bits 48 to 63, adjusted for prefixes of length 48 or less
+
+// NPTv6Translator is an IPv6-to-IPv6 network prefix translator, as described in RFC 6296.
+//
+// It translates addresses within an inside prefix to addresses within an outside prefix of the same length, and back again.
+// Along with the prefix, a single segment of each address is adjusted so that the translation is checksum-neutral:
+// the one's complement sum of the segments of a translated address matches that of the original, so that transport-layer checksums,
+// which include the addresses, remain valid without being recalculated.
+// For prefixes of length 48 or less, the adjusted segment is the fourth segment, holding bits 48 to 63.
+// For longer prefixes, it is the first segment of the interface identifier, the last 64 bits, that is not 0xffff.
+//
+// For example, with the inside prefix "fd01:203:405::/48" and the outside prefix "2001:db8:1::/48",
+// the address "fd01:203:405:1::1234" is translated to "2001:db8:1:d550::1234".
+//
+// The zero value is not usable, use NewNPTv6Translator to create one.
+// An NPTv6Translator is immutable and can be used concurrently by multiple goroutines.
+type NPTv6Translator struct {
+	inside, outside *IPv6Address
+
+	insideVals, outsideVals [IPv6SegmentCount]uint16
+
+	// adjustment is added to the adjusted segment when translating from inside to outside, and subtracted when translating back,
+	// it is the difference between the one's complement sums of the inside and outside prefixes
+	adjustment uint16
+}
+
+// NewNPTv6Translator constructs a translator between the given inside and outside prefixes, such as "fd01:203:405::/48" and "2001:db8:1::/48".
+// The prefixes are the prefix blocks of the given addresses, which must have the same prefix length, no larger than 64.
+//
+// An error is returned if either address is nil, has no prefix length, or spans more than a single prefix block,
+// or if the prefix lengths do not match or exceed 64.
+func NewNPTv6Translator(inside, outside *IPv6Address) (*NPTv6Translator, addrerr.AddressError) {
+	insideBlock, err := toNPTv6Prefix(inside)
+	if err != nil {
+		return nil, err
+	}
+	outsideBlock, err := toNPTv6Prefix(outside)
+	if err != nil {
+		return nil, err
+	}
+	prefLen := insideBlock.GetPrefixLen().bitCount()
+	if outsidePrefLen := outsideBlock.GetPrefixLen().bitCount(); outsidePrefLen != prefLen {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixSize"}, val: int(outsidePrefLen)}
+	}
+	translator := &NPTv6Translator{
+		inside:      insideBlock,
+		outside:     outsideBlock,
+		insideVals:  nptv6SegmentVals(insideBlock),
+		outsideVals: nptv6SegmentVals(outsideBlock),
+	}
+	translator.adjustment = onesComplementAdd(onesComplementSum(translator.insideVals), ^onesComplementSum(translator.outsideVals))
+	return translator, nil
+}
+
+func toNPTv6Prefix(addr *IPv6Address) (*IPv6Address, addrerr.AddressError) {
+	if addr == nil || !addr.IsPrefixed() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.not.block"}}
+	}
+	block := addr.WithoutZone().ToPrefixBlock()
+	if !block.IsSinglePrefixBlock() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.not.block"}}
+	} else if prefLen := block.GetPrefixLen().bitCount(); prefLen > IPv6BitCount>>1 {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixSize"}, val: int(prefLen)}
+	}
+	return block, nil
+}
+
+// GetInsidePrefix returns the inside prefix block of this translator.
+func (translator *NPTv6Translator) GetInsidePrefix() *IPv6Address {
+	return translator.inside
+}
+
+// GetOutsidePrefix returns the outside prefix block of this translator.
+func (translator *NPTv6Translator) GetOutsidePrefix() *IPv6Address {
+	return translator.outside
+}
+
+// Translate translates the given individual address within the inside prefix to the corresponding address within the outside prefix.
+// The prefix length and zone of the given address, if any, are retained.
+//
+// An error is returned if the given address is a subnet of multiple addresses or is not within the inside prefix,
+// or if it cannot be translated because the segment to be adjusted is 0xffff, as described in RFC 6296.
+// If the given address is nil, nil is returned.
+func (translator *NPTv6Translator) Translate(addr *IPv6Address) (*IPv6Address, addrerr.IncompatibleAddressError) {
+	return translator.translate(addr, translator.inside, &translator.outsideVals, translator.adjustment)
+}
+
+// Untranslate translates the given individual address within the outside prefix back to the corresponding address within the inside prefix,
+// reversing Translate.
+// The prefix length and zone of the given address, if any, are retained.
+//
+// An error is returned if the given address is a subnet of multiple addresses or is not within the outside prefix,
+// or if it cannot be translated because the segment to be adjusted is 0xffff, as described in RFC 6296.
+// If the given address is nil, nil is returned.
+func (translator *NPTv6Translator) Untranslate(addr *IPv6Address) (*IPv6Address, addrerr.IncompatibleAddressError) {
+	return translator.translate(addr, translator.outside, &translator.insideVals, ^translator.adjustment)
+}
+
+func (translator *NPTv6Translator) translate(addr, from *IPv6Address, toVals *[IPv6SegmentCount]uint16, adjustment uint16) (*IPv6Address, addrerr.IncompatibleAddressError) {
+	if addr == nil {
+		return nil, nil
+	} else if addr.IsMultiple() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.single.address.required"}}
+	} else if !from.Contains(addr.WithoutZone()) {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	vals := nptv6SegmentVals(addr)
+	prefLen := from.GetPrefixLen().bitCount()
+
+	// replace the prefix
+	for i := range vals {
+		segPrefLen := prefLen - BitCount(i)*IPv6BitsPerSegment
+		if segPrefLen <= 0 {
+			break
+		} else if segPrefLen >= IPv6BitsPerSegment {
+			vals[i] = toVals[i]
+		} else {
+			hostMask := uint16(0xffff) >> uint(segPrefLen)
+			vals[i] = (toVals[i] &^ hostMask) | (vals[i] & hostMask)
+		}
+	}
+
+	// adjust a segment outside the prefix to make the translation checksum-neutral
+	adjustIndex := nptv6SubnetIndex
+	if prefLen > 48 {
+		for adjustIndex = IPv6SegmentCount >> 1; adjustIndex < IPv6SegmentCount && vals[adjustIndex] == 0xffff; adjustIndex++ {
+		}
+	}
+	if adjustIndex == IPv6SegmentCount || vals[adjustIndex] == 0xffff {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	adjusted := onesComplementAdd(vals[adjustIndex], adjustment)
+	if adjusted == 0xffff {
+		adjusted = 0
+	}
+	vals[adjustIndex] = adjusted
+
+	var high, low uint64
+	for i := 0; i < IPv6SegmentCount>>1; i++ {
+		high = (high << IPv6BitsPerSegment) | uint64(vals[i])
+		low = (low << IPv6BitsPerSegment) | uint64(vals[i+(IPv6SegmentCount>>1)])
+	}
+	// set the prefix length afterwards, since constructing with a prefix length would make a zero host into the prefix block
+	result := NewIPv6AddressFromZonedUint64(high, low, string(addr.GetZone()))
+	if prefLen := addr.GetPrefixLen(); prefLen != nil {
+		result = result.SetPrefixLen(prefLen.Len())
+	}
+	return result, nil
+}
+
+// String returns the inside and outside prefixes separated by " <-> ", such as "fd01:203:405::/48 <-> 2001:db8:1::/48".
+func (translator *NPTv6Translator) String() string {
+	if translator == nil {
+		return nilString()
+	}
+	return translator.inside.String() + " <-> " + translator.outside.String()
+}
+
+func nptv6SegmentVals(addr *IPv6Address) (vals [IPv6SegmentCount]uint16) {
+	for i := range vals {
+		vals[i] = uint16(addr.GetSegment(i).GetSegmentValue())
+	}
+	return
+}
+
+// onesComplementAdd adds the two values using 16-bit one's complement arithmetic, in which the carry is added back in
+func onesComplementAdd(one, two uint16) uint16 {
+	sum := uint32(one) + uint32(two)
+	return uint16(sum&0xffff + sum>>16)
+}
+
+func onesComplementSum(vals [IPv6SegmentCount]uint16) (sum uint16) {
+	for _, val := range vals {
+		sum = onesComplementAdd(sum, val)
+	}
+	return
+}
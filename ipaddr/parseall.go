@@ -0,0 +1,106 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrstrparam"
+)
+
+// ParseAll parses the given strings as IPv4 or IPv6 addresses or subnets, as provided by ToAddress of IPAddressString,
+// using the given parameters, or the default parameters if nil.
+// The strings are parsed in parallel, by up to GOMAXPROCS goroutines.
+//
+// The returned slices have the same length as the given slice, and are in the same order.
+// For each index, either the address or the error is nil.
+// A string that is valid but does not specify an IPv4 or IPv6 address or subnet, such as the prefix length "/16" alone, has an error.
+// The returned error slice is nil if every string was parsed.
+func ParseAll(strs []string, params addrstrparam.IPAddressStringParams) ([]*IPAddress, []error) {
+	addrs, parseErrs := parseAll(strs, params)
+	var errs []error
+	for i, err := range parseErrs {
+		if err != nil {
+			if errs == nil {
+				errs = make([]error, len(strs))
+			}
+			errs[i] = err
+		}
+	}
+	return addrs, errs
+}
+
+// ParseAllValid is like ParseAll, but skips the strings that are not parsed.
+// It returns the addresses or subnets of the valid strings in the same order as the given slice,
+// along with an error for each invalid string, also in the same order, describing the string along with its index.
+// The returned error slice is nil if every string was parsed.
+func ParseAllValid(strs []string, params addrstrparam.IPAddressStringParams) ([]*IPAddress, []error) {
+	addrs, errs := ParseAll(strs, params)
+	if errs == nil {
+		return addrs, nil
+	}
+	validAddrs := addrs[:0]
+	var invalidErrs []error
+	for i, addr := range addrs {
+		if addr != nil {
+			validAddrs = append(validAddrs, addr)
+		} else {
+			invalidErrs = append(invalidErrs, wrapErrf(errs[i], "index %d", i))
+		}
+	}
+	return validAddrs, invalidErrs
+}
+
+// parseAll parses the strings with a pool of goroutines, each parsing a contiguous range of the strings
+func parseAll(strs []string, params addrstrparam.IPAddressStringParams) (addrs []*IPAddress, errs []addrerr.AddressError) {
+	if params == nil {
+		params = defaultIPAddrParameters
+	} else {
+		params = addrstrparam.CopyIPAddressStringParams(params)
+	}
+	addrs = make([]*IPAddress, len(strs))
+	errs = make([]addrerr.AddressError, len(strs))
+	parseRange := func(start, end int) {
+		for i := start; i < end; i++ {
+			addr, err := parseIPAddressString(strs[i], params).ToAddress()
+			if err == nil && addr == nil {
+				err = &addressStringError{addressError{str: strs[i], key: "ipaddress.error.ipVersionIndeterminate"}}
+			}
+			addrs[i], errs[i] = addr, err
+		}
+	}
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(strs) {
+		workers = len(strs)
+	}
+	if workers <= 1 {
+		parseRange(0, len(strs))
+	} else {
+		var wg sync.WaitGroup
+		wg.Add(workers)
+		for w := 0; w < workers; w++ {
+			go func(start, end int) {
+				defer wg.Done()
+				parseRange(start, end)
+			}(w*len(strs)/workers, (w+1)*len(strs)/workers)
+		}
+		wg.Wait()
+	}
+	return
+}
@@ -0,0 +1,194 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"container/list"
+	"strconv"
+	"sync"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrstrparam"
+)
+
+// NewParserCache constructs a ParserCache holding the results for at most the given number of strings,
+// parsing with the default IPAddressString parameters.
+func NewParserCache(capacity int) *ParserCache {
+	return newParserCache(capacity, defaultIPAddrParameters)
+}
+
+// NewParserCacheParams constructs a ParserCache holding the results for at most the given number of strings,
+// parsing with the given IPAddressString parameters.
+func NewParserCacheParams(capacity int, params addrstrparam.IPAddressStringParams) *ParserCache {
+	var p addrstrparam.IPAddressStringParams
+	if params == nil {
+		p = defaultIPAddrParameters
+	} else {
+		p = addrstrparam.CopyIPAddressStringParams(params)
+	}
+	return newParserCache(capacity, p)
+}
+
+func newParserCache(capacity int, params addrstrparam.IPAddressStringParams) *ParserCache {
+	return &ParserCache{
+		params:   params,
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// ParserCache parses IP address strings, caching the results of the most recently parsed strings,
+// for servers and other applications that parse the same strings repeatedly, such as the addresses of clients.
+//
+// The results for repeated strings are shared, an *IPAddress being immutable.
+// The results of invalid strings are cached as well, so that repeated invalid strings are not parsed again.
+// When the cache is full, the result for the least recently parsed string is discarded to make room for the next.
+//
+// Each cache parses strings with the IPAddressString parameters supplied to NewParserCacheParams, so the same string is parsed consistently.
+// Use a separate cache for each set of parameters.
+//
+// The zero value is not usable, use NewParserCache or NewParserCacheParams to create one.
+// A ParserCache can be used concurrently by multiple goroutines.
+type ParserCache struct {
+	params   addrstrparam.IPAddressStringParams
+	capacity int
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	order   list.List // the entries from the most recently used to the least
+
+	stats ParserCacheStats
+}
+
+type parserCacheEntry struct {
+	str  string
+	addr *IPAddress
+	err  addrerr.AddressError
+}
+
+// ParserCacheStats is a snapshot of the metrics of a ParserCache.
+type ParserCacheStats struct {
+	// Hits is the number of strings whose results were found in the cache.
+	Hits uint64
+
+	// Misses is the number of strings that were parsed because their results were not in the cache.
+	Misses uint64
+
+	// Evictions is the number of results discarded to make room for others.
+	Evictions uint64
+
+	// Size is the number of strings whose results are in the cache.
+	Size int
+}
+
+// GetHitRatio returns the fraction of lookups that were hits, or zero if there have been no lookups.
+func (stats ParserCacheStats) GetHitRatio() float64 {
+	total := stats.Hits + stats.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(stats.Hits) / float64(total)
+}
+
+// String returns the metrics, such as "hits 5, misses 2, evictions 0, size 2".
+func (stats ParserCacheStats) String() string {
+	return "hits " + strconv.FormatUint(stats.Hits, 10) +
+		", misses " + strconv.FormatUint(stats.Misses, 10) +
+		", evictions " + strconv.FormatUint(stats.Evictions, 10) +
+		", size " + strconv.Itoa(stats.Size)
+}
+
+// ToAddress returns the address or subnet parsed from the given string, as provided by ToAddress of IPAddressString,
+// or the error if the string cannot be parsed to an address.
+// Repeated calls with the same string return the same *IPAddress or error, while the result remains in the cache.
+func (cache *ParserCache) ToAddress(str string) (*IPAddress, addrerr.AddressError) {
+	entry := cache.get(str)
+	if entry == nil {
+		addr, err := NewIPAddressStringParams(str, cache.params).ToAddress()
+		entry = cache.put(&parserCacheEntry{str: str, addr: addr, err: err})
+	}
+	return entry.addr, entry.err
+}
+
+// GetAddress returns the address or subnet parsed from the given string, as provided by GetAddress of IPAddressString,
+// or nil if the string cannot be parsed to an address.
+// Repeated calls with the same string return the same *IPAddress, while the result remains in the cache.
+func (cache *ParserCache) GetAddress(str string) *IPAddress {
+	addr, _ := cache.ToAddress(str)
+	return addr
+}
+
+func (cache *ParserCache) get(str string) *parserCacheEntry {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if elem, ok := cache.entries[str]; ok {
+		cache.stats.Hits++
+		cache.order.MoveToFront(elem)
+		return elem.Value.(*parserCacheEntry)
+	}
+	cache.stats.Misses++
+	return nil
+}
+
+// put adds the given entry, returning the entry already added for the same string by another goroutine, if any, so that results are shared
+func (cache *ParserCache) put(entry *parserCacheEntry) *parserCacheEntry {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	if elem, ok := cache.entries[entry.str]; ok {
+		cache.order.MoveToFront(elem)
+		return elem.Value.(*parserCacheEntry)
+	} else if cache.capacity <= 0 {
+		return entry
+	}
+	for cache.order.Len() >= cache.capacity {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.entries, oldest.Value.(*parserCacheEntry).str)
+		cache.stats.Evictions++
+	}
+	cache.entries[entry.str] = cache.order.PushFront(entry)
+	return entry
+}
+
+// GetCapacity returns the maximum number of strings whose results are held in the cache.
+func (cache *ParserCache) GetCapacity() int {
+	return cache.capacity
+}
+
+// Len returns the number of strings whose results are in the cache.
+func (cache *ParserCache) Len() int {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	return cache.order.Len()
+}
+
+// GetStats returns a snapshot of the hit, miss and eviction counts of the cache, along with its size.
+func (cache *ParserCache) GetStats() ParserCacheStats {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	stats := cache.stats
+	stats.Size = cache.order.Len()
+	return stats
+}
+
+// Clear discards all cached results.  The hit, miss and eviction counts are retained.
+func (cache *ParserCache) Clear() {
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	cache.entries = make(map[string]*list.Element)
+	cache.order.Init()
+}
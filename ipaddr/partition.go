@@ -18,6 +18,9 @@ package ipaddr
 
 import (
 	"math/big"
+	"sort"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
 )
 
 // Partition is a collection of items (such as addresses) partitioned from an original item (such as a subnet).
@@ -304,4 +307,393 @@ func PartitionWithSingleBlockSize[T IteratePartitionConstraint[T]](newAddr T) *P
 	}
 }
 
+// PartitionIntoN partitions the sequential range into n sequential ranges of near-equal size, the sizes differing by at most one address,
+// with the larger ranges first.  If the range has fewer than n addresses, it is partitioned into its individual addresses.
+// A value of n less than one is treated as one.
+//
+// A sequential subnet can be partitioned by first converting it with ToSequentialRange.
+// The ranges are produced as they are iterated, so large partitions are not materialized.
+func PartitionIntoN[T SequentialRangeConstraint[T]](rng *SequentialRange[T], n int) *Partition[*SequentialRange[T]] {
+	count := rng.GetCount()
+	pieces := big.NewInt(int64(n))
+	if n < 1 {
+		pieces.SetInt64(1)
+	}
+	if pieces.Cmp(count) > 0 {
+		pieces.Set(count)
+	}
+	if pieces.Cmp(bigOneConst()) == 0 {
+		return &Partition[*SequentialRange[T]]{
+			original:  rng,
+			single:    rng,
+			hasSingle: true,
+			count:     bigOneConst(),
+		}
+	}
+	size, remainder := new(big.Int).QuoRem(count, pieces, new(big.Int))
+	return &Partition[*SequentialRange[T]]{
+		original: rng,
+		iterator: &nPartitionIterator[T]{
+			lower:     rng.GetLower(),
+			remaining: pieces.Int64(),
+			remainder: remainder.Int64(),
+			size:      size,
+		},
+		count: pieces,
+	}
+}
+
+type nPartitionIterator[T SequentialRangeConstraint[T]] struct {
+	lower                T
+	remaining, remainder int64
+	size                 *big.Int
+}
+
+func (iter *nPartitionIterator[T]) HasNext() bool {
+	return iter.remaining > 0
+}
+
+func (iter *nPartitionIterator[T]) Next() (res *SequentialRange[T]) {
+	if !iter.HasNext() {
+		return
+	}
+	iter.remaining--
+	// the size less one is the increment from the lower to the upper address of the range
+	increment := new(big.Int).Set(iter.size)
+	if iter.remainder == 0 {
+		increment.Sub(increment, bigOneConst())
+	} else {
+		iter.remainder--
+	}
+	upper := iter.lower.IncrementBig(increment)
+	res = NewSequentialRange(iter.lower, upper)
+	if iter.remaining > 0 {
+		iter.lower = upper.Increment(1)
+	}
+	return
+}
+
+// HostPartitionConstraint is the generic type constraint for partitions of IP subnets by host counts.
+type HostPartitionConstraint[T any] interface {
+	SpanPartitionConstraint[T]
+
+	PrefixBlockIterator() Iterator[T]
+}
+
+var (
+	_ HostPartitionConstraint[*IPAddress]
+	_ HostPartitionConstraint[*IPv4Address]
+	_ HostPartitionConstraint[*IPv6Address]
+	_ HostPartitionConstraint[*IPAddressSection]
+	_ HostPartitionConstraint[*IPv4AddressSection]
+	_ HostPartitionConstraint[*IPv6AddressSection]
+)
+
+// PartitionByHosts partitions the address series into prefix blocks and single addresses, each with no more than the given number of addresses.
+// The series is spanned by prefix blocks as with PartitionWithSpanningBlocks, and each block larger than the given size is divided into blocks of the largest size allowed.
+// A maximum less than one is treated as one.
+//
+// The blocks are produced as they are iterated, so large partitions are not materialized.
+func PartitionByHosts[T HostPartitionConstraint[T]](newAddr T, maxHosts *big.Int) *Partition[T] {
+	return partitionBlocksByHosts(newAddr, newAddr.SpanWithPrefixBlocks(), maxHosts)
+}
+
+// PartitionRangeByHosts partitions the sequential range into prefix blocks and single addresses, each with no more than the given number of addresses.
+// The range is spanned by prefix blocks as with SpanWithPrefixBlocks, and each block larger than the given size is divided into blocks of the largest size allowed.
+// A maximum less than one is treated as one.
+//
+// The blocks are produced as they are iterated, so large partitions are not materialized.
+func PartitionRangeByHosts[T interface {
+	SequentialRangeConstraint[T]
+	PrefixBlockIterator() Iterator[T]
+}](rng *SequentialRange[T], maxHosts *big.Int) *Partition[T] {
+	return partitionBlocksByHosts(rng.GetLower(), rng.SpanWithPrefixBlocks(), maxHosts)
+}
+
+// blockIteratorConstraint is the constraint for the blocks partitioned by host counts, whether spanning subnets, sections, or sequential ranges
+type blockIteratorConstraint[T any] interface {
+	AddressDivisionSeries
+	PrefixedConstraint[T]
+
+	PrefixBlockIterator() Iterator[T]
+}
+
+func partitionBlocksByHosts[T blockIteratorConstraint[T]](original T, blocks []T, maxHosts *big.Int) *Partition[T] {
+	// the largest block size allowed is the largest power of two not exceeding the maximum
+	var maxHostBits BitCount
+	if maxHosts != nil && maxHosts.Sign() > 0 {
+		maxHostBits = BitCount(maxHosts.BitLen() - 1)
+	}
+	count := bigZero()
+	for _, block := range blocks {
+		if hostBits := getPartitionHostBits(block); hostBits > maxHostBits {
+			count.Add(count, new(big.Int).Lsh(bigOneConst(), uint(hostBits-maxHostBits)))
+		} else {
+			count.Add(count, bigOneConst())
+		}
+	}
+	return &Partition[T]{
+		original: original,
+		iterator: &hostPartitionIterator[T]{blocks: blocks, maxHostBits: maxHostBits},
+		count:    count,
+	}
+}
+
+func getPartitionHostBits[T blockIteratorConstraint[T]](block T) BitCount {
+	if prefLen := block.GetPrefixLen(); prefLen != nil {
+		return block.GetBitCount() - prefLen.bitCount()
+	}
+	return 0
+}
+
+type hostPartitionIterator[T blockIteratorConstraint[T]] struct {
+	blocks      []T
+	current     Iterator[T]
+	maxHostBits BitCount
+}
+
+func (iter *hostPartitionIterator[T]) HasNext() bool {
+	return (iter.current != nil && iter.current.HasNext()) || len(iter.blocks) > 0
+}
+
+func (iter *hostPartitionIterator[T]) Next() (res T) {
+	if iter.current != nil && iter.current.HasNext() {
+		return iter.current.Next()
+	} else if len(iter.blocks) == 0 {
+		return
+	}
+	block := iter.blocks[0]
+	iter.blocks = iter.blocks[1:]
+	if hostBits := getPartitionHostBits(block); hostBits > iter.maxHostBits {
+		iter.current = block.SetPrefixLen(block.GetBitCount() - iter.maxHostBits).PrefixBlockIterator()
+		return iter.current.Next()
+	}
+	return block
+}
+
+// MaskPartitionConstraint is the generic type constraint for partitions of IP subnets by masks.
+type MaskPartitionConstraint[T any] interface {
+	IPAddressType
+}
+
+var (
+	_ MaskPartitionConstraint[*IPAddress]
+	_ MaskPartitionConstraint[*IPv4Address]
+	_ MaskPartitionConstraint[*IPv6Address]
+)
+
+// PartitionByMask partitions the subnet into the groups of addresses in which the bits selected by the mask are constant,
+// the cosets of the mask, one for each value of the subnet's addresses masked by the mask.
+// For instance, partitioning 10.0.0.0/8 by the mask 0.0.0.128 produces two interleaved groups, 10.*.*.0-127 and 10.*.*.128-255.
+// This generalizes BlockIterator, which groups the addresses by the values of leading segments, to any selection of bits.
+//
+// The groups are in ascending order of their masked values.
+// A group is not necessarily a subnet, so each group is provided as a partition of subnets, which are in ascending order.
+// For instance, partitioning 10.0.0.0-3 by the mask 0.0.0.1 produces the groups 10.0.0.0 and 10.0.0.2, and 10.0.0.1 and 10.0.0.3.
+// The subnets have no prefix length, and the zone of an IPv6 subnet is retained.
+//
+// If the mask is a subnet, its lowest address is used as the mask.
+// The groups and their subnets are produced as they are iterated, so large partitions are not materialized.
+// An error is returned if the IP versions of the subnet and the mask do not match.
+func PartitionByMask[T MaskPartitionConstraint[T]](subnet, mask T) (*Partition[*Partition[T]], addrerr.IncompatibleAddressError) {
+	subnetIP, maskIP := subnet.ToIP(), mask.ToIP()
+	if !subnetIP.IsIPv4() && !subnetIP.IsIPv6() || !subnetIP.GetIPVersion().Equal(maskIP.GetIPVersion()) {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipMismatch"}}
+	}
+	segCount := subnetIP.GetSegmentCount()
+	segs := make([]maskPartitionSegment, segCount)
+	count := bigOneConst()
+	for i := range segs {
+		seg := subnetIP.GetSegment(i)
+		segs[i] = newMaskPartitionSegment(seg.GetSegmentValue(), seg.GetUpperSegmentValue(), maskIP.GetSegment(i).GetSegmentValue())
+		if cosetCount := len(segs[i].maskedVals); cosetCount > 1 {
+			count = new(big.Int).Mul(count, big.NewInt(int64(cosetCount)))
+		}
+	}
+	var zone Zone
+	if ipv6Subnet := subnetIP.ToIPv6(); ipv6Subnet != nil {
+		zone = ipv6Subnet.GetZone()
+	}
+	return &Partition[*Partition[T]]{
+		iterator: &maskPartitionIterator[T]{
+			original: subnet,
+			isIPv4:   subnetIP.IsIPv4(),
+			zone:     string(zone),
+			segs:     segs,
+			indices:  make([]int, segCount),
+		},
+		count: count,
+	}, nil
+}
+
+type maskPartitionRange struct {
+	lower, upper SegInt
+}
+
+// maskPartitionSegment holds the values of a segment grouped by their masked values
+type maskPartitionSegment struct {
+	maskedVals []SegInt
+	ranges     [][]maskPartitionRange // the sequential ranges of segment values for each masked value
+}
+
+func newMaskPartitionSegment(lower, upper, mask SegInt) (seg maskPartitionSegment) {
+	if mask == 0 {
+		seg.maskedVals = []SegInt{0}
+		seg.ranges = [][]maskPartitionRange{{{lower, upper}}}
+		return
+	}
+	// the values sharing the bits above the lowest mask bit form sequential blocks in which the masked value is constant,
+	// while neighbouring blocks differ in the lowest mask bit
+	var shift BitCount
+	for mask&(1<<uint(shift)) == 0 {
+		shift++
+	}
+	blockMask := ^(^SegInt(0) << uint(shift))
+	indices := make(map[SegInt]int)
+	for block := lower >> uint(shift); block <= upper>>uint(shift); block++ {
+		blockLower, blockUpper := block<<uint(shift), (block<<uint(shift))|blockMask
+		if blockLower < lower {
+			blockLower = lower
+		}
+		if blockUpper > upper {
+			blockUpper = upper
+		}
+		maskedVal := blockLower & mask
+		index, ok := indices[maskedVal]
+		if !ok {
+			index = len(seg.maskedVals)
+			indices[maskedVal] = index
+			seg.maskedVals = append(seg.maskedVals, maskedVal)
+			seg.ranges = append(seg.ranges, nil)
+		}
+		seg.ranges[index] = append(seg.ranges[index], maskPartitionRange{blockLower, blockUpper})
+	}
+	// order the masked values, along with their ranges, ascending
+	sort.Sort(maskPartitionSegmentSorter{&seg})
+	return
+}
+
+type maskPartitionSegmentSorter struct {
+	*maskPartitionSegment
+}
+
+func (sorter maskPartitionSegmentSorter) Len() int {
+	return len(sorter.maskedVals)
+}
+
+func (sorter maskPartitionSegmentSorter) Less(i, j int) bool {
+	return sorter.maskedVals[i] < sorter.maskedVals[j]
+}
+
+func (sorter maskPartitionSegmentSorter) Swap(i, j int) {
+	sorter.maskedVals[i], sorter.maskedVals[j] = sorter.maskedVals[j], sorter.maskedVals[i]
+	sorter.ranges[i], sorter.ranges[j] = sorter.ranges[j], sorter.ranges[i]
+}
+
+// maskPartitionIterator iterates through the groups of a mask partition, varying the masked value of the last segment fastest
+type maskPartitionIterator[T MaskPartitionConstraint[T]] struct {
+	original T
+	isIPv4   bool
+	zone     string
+	segs     []maskPartitionSegment
+	indices  []int // the index of the masked value of each segment for the next group
+	done     bool
+}
+
+func (iter *maskPartitionIterator[T]) HasNext() bool {
+	return !iter.done
+}
+
+func (iter *maskPartitionIterator[T]) Next() (res *Partition[T]) {
+	if iter.done {
+		return
+	}
+	ranges := make([][]maskPartitionRange, len(iter.segs))
+	count := bigOneConst()
+	for i, seg := range iter.segs {
+		ranges[i] = seg.ranges[iter.indices[i]]
+		if rangeCount := len(ranges[i]); rangeCount > 1 {
+			count = new(big.Int).Mul(count, big.NewInt(int64(rangeCount)))
+		}
+	}
+	res = &Partition[T]{
+		original: iter.original,
+		iterator: &maskGroupIterator[T]{
+			isIPv4:  iter.isIPv4,
+			zone:    iter.zone,
+			ranges:  ranges,
+			indices: make([]int, len(ranges)),
+		},
+		count: count,
+	}
+	iter.done = !incrementMaskPartitionIndices(iter.indices, func(segIndex int) int {
+		return len(iter.segs[segIndex].maskedVals)
+	})
+	return
+}
+
+// maskGroupIterator iterates through the subnets of a group of a mask partition, varying the range of the last segment fastest
+type maskGroupIterator[T MaskPartitionConstraint[T]] struct {
+	isIPv4  bool
+	zone    string
+	ranges  [][]maskPartitionRange
+	indices []int // the index of the range of each segment for the next subnet
+	done    bool
+}
+
+func (iter *maskGroupIterator[T]) HasNext() bool {
+	return !iter.done
+}
+
+func (iter *maskGroupIterator[T]) Next() (res T) {
+	if iter.done {
+		return
+	}
+	getRange := func(segmentIndex int) maskPartitionRange {
+		return iter.ranges[segmentIndex][iter.indices[segmentIndex]]
+	}
+	var subnet *IPAddress
+	if iter.isIPv4 {
+		subnet = NewIPv4AddressFromRange(
+			func(segmentIndex int) IPv4SegInt {
+				return IPv4SegInt(getRange(segmentIndex).lower)
+			},
+			func(segmentIndex int) IPv4SegInt {
+				return IPv4SegInt(getRange(segmentIndex).upper)
+			}).ToIP()
+	} else {
+		subnet = NewIPv6AddressFromZonedRange(
+			func(segmentIndex int) IPv6SegInt {
+				return IPv6SegInt(getRange(segmentIndex).lower)
+			},
+			func(segmentIndex int) IPv6SegInt {
+				return IPv6SegInt(getRange(segmentIndex).upper)
+			},
+			iter.zone).ToIP()
+	}
+	switch any(res).(type) {
+	case *IPAddress:
+		res = any(subnet).(T)
+	case *IPv4Address:
+		res = any(subnet.ToIPv4()).(T)
+	case *IPv6Address:
+		res = any(subnet.ToIPv6()).(T)
+	}
+	iter.done = !incrementMaskPartitionIndices(iter.indices, func(segIndex int) int {
+		return len(iter.ranges[segIndex])
+	})
+	return
+}
+
+// incrementMaskPartitionIndices increments the indices like an odometer, the last index varying fastest, returning false when the indices wrap around
+func incrementMaskPartitionIndices(indices []int, limit func(segIndex int) int) bool {
+	for i := len(indices) - 1; i >= 0; i-- {
+		if indices[i]++; indices[i] < limit(i) {
+			return true
+		}
+		indices[i] = 0
+	}
+	return false
+}
+
 // TODO LATER partition ranges (not just addresses) with spanning blocks
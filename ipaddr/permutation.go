@@ -0,0 +1,116 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import "math/big"
+
+// permutationRounds is the number of Feistel rounds, enough to scatter neighbouring indices
+const permutationRounds = 4
+
+type permutable[T any] interface {
+	GetCount() *big.Int
+	Increment(int64) T
+	IncrementBig(*big.Int) T
+}
+
+// permutationIterator visits the indices of a subnet in the order given by a Feistel network over a power-of-two domain of indices,
+// skipping the permuted indices beyond the subnet count, which is known as cycle walking.
+// The domain is at most four times larger than the count, each domain index being split into two halves of halfBits bits.
+type permutationIterator[T permutable[T]] struct {
+	series    T
+	remaining *big.Int
+	count     *big.Int
+	halfBits  uint
+	mask      uint64
+	keys      [permutationRounds]uint64
+
+	left, right uint64 // the next domain index
+}
+
+func newPermutationIterator[T permutable[T]](series T, seed int64) Iterator[T] {
+	count := series.GetCount()
+	halfBits := uint(new(big.Int).Sub(count, bigOneConst()).BitLen()+1) >> 1
+	iter := &permutationIterator[T]{
+		series:    series,
+		remaining: new(big.Int).Set(count),
+		count:     count,
+		halfBits:  halfBits,
+	}
+	if halfBits > 0 {
+		iter.mask = ^uint64(0) >> (64 - halfBits)
+	}
+	state := uint64(seed)
+	for i := range iter.keys {
+		state += 0x9e3779b97f4a7c15
+		iter.keys[i] = mixPermutationBits(state)
+	}
+	return iter
+}
+
+// mixPermutationBits is the finalizer of the splitmix64 generator
+func mixPermutationBits(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (it *permutationIterator[T]) HasNext() bool {
+	return it.remaining.Sign() > 0
+}
+
+func (it *permutationIterator[T]) Next() (res T) {
+	if !it.HasNext() {
+		return
+	}
+	for {
+		left, right := it.left, it.right
+		if it.right == it.mask {
+			it.right = 0
+			it.left++
+		} else {
+			it.right++
+		}
+		for _, key := range it.keys {
+			left, right = right, left^(mixPermutationBits(right^key)&it.mask)
+		}
+		// the permuted index is left << halfBits | right
+		var high, low uint64
+		if it.halfBits == 64 {
+			high, low = left, right
+		} else {
+			low = left<<it.halfBits | right
+			if it.halfBits > 32 {
+				high = left >> (64 - it.halfBits)
+			}
+		}
+		if high == 0 && low <= 0x7fffffffffffffff {
+			if it.count.IsUint64() && low >= it.count.Uint64() {
+				continue
+			}
+			res = it.series.Increment(int64(low))
+		} else {
+			index := new(big.Int).SetUint64(high)
+			index.Lsh(index, 64).Or(index, new(big.Int).SetUint64(low))
+			if index.Cmp(it.count) >= 0 {
+				continue
+			}
+			res = it.series.IncrementBig(index)
+		}
+		it.remaining.Sub(it.remaining, bigOneConst())
+		return
+	}
+}
@@ -0,0 +1,315 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// AddressPoolConstraint is the generic type constraint used for an address pool.
+type AddressPoolConstraint[T any] interface {
+	PrefixBlockConstraint[T]
+	TrieKeyConstraint[T]
+}
+
+var (
+	_ = AddressPool[*IPAddress]{}
+	_ = AddressPool[*IPv4Address]{}
+	_ = AddressPool[*IPv6Address]{}
+)
+
+// AddressPool tracks the allocation of prefix blocks and individual addresses from an address space, providing accounting for IP address management.
+// Unlike PrefixBlockAllocator, which chooses the blocks to allocate, the pool records the blocks allocated by the caller,
+// and reports on the allocated and free space of the pool.
+//
+// The address space of the pool is kept as a list of sequential ranges, while the allocated blocks are kept in an address trie.
+//
+// The generic type T can be *IPAddress, *IPv4Address or *IPv6Address.
+// Once a pool of generic type *IPAddress has been provided with either an IPv4 or IPv6 address space,
+// it can only be used with the same address version from that point onwards.
+//
+// The zero value of an AddressPool is an empty pool ready for use.  An AddressPool is not safe for concurrent use by multiple goroutines if any goroutine is modifying it.
+type AddressPool[T AddressPoolConstraint[T]] struct {
+	version   IPVersion
+	space     []*SequentialRange[T]
+	allocated Trie[T]
+}
+
+// GetVersion returns the IP version of the pool,
+// which is determined by the version of the first address space added to the pool.
+func (pool *AddressPool[T]) GetVersion() IPVersion {
+	return pool.version
+}
+
+// AddToPool adds the given subnets to the address space of the pool.
+// Subnets overlapping or adjacent to the existing address space are merged with it.
+// It panics if a subnet does not match the pool's IP version.
+func (pool *AddressPool[T]) AddToPool(subnets ...T) {
+	version := pool.version
+	ranges := pool.space
+	for _, subnet := range subnets {
+		if version.IsIndeterminate() {
+			version = subnet.GetIPVersion()
+			pool.version = version
+		} else if !version.Equal(subnet.GetIPVersion()) {
+			panic(lookupStr("ipaddress.error.ipVersionMismatch"))
+		}
+		for _, block := range subnet.SpanWithPrefixBlocks() {
+			ranges = append(ranges, NewSequentialRange(block.GetLower(), block.GetUpper()))
+		}
+	}
+	pool.space = joinRanges(ranges)
+}
+
+// GetPool returns the address space of the pool as the fewest number of sequential ranges, in ascending order.
+func (pool *AddressPool[T]) GetPool() []*SequentialRange[T] {
+	return append(make([]*SequentialRange[T], 0, len(pool.space)), pool.space...)
+}
+
+// Allocate records the given prefix block or individual address as allocated,
+// returning true if it was allocated.
+//
+// The block is not allocated, and false is returned, if the block is not within the address space of the pool,
+// if it overlaps a block already allocated, or if it is neither a prefix block nor an individual address.
+// A subnet that can be converted to a prefix block by assigning a prefix length, as with ToSinglePrefixBlockOrAddress, is allocated as that block.
+func (pool *AddressPool[T]) Allocate(block T) bool {
+	var t T
+	if block == t || pool.version.IsIndeterminate() || !pool.version.Equal(block.GetIPVersion()) {
+		return false
+	}
+	block, err := block.toSinglePrefixBlockOrAddress()
+	if err != nil || !pool.inPool(block) || pool.allocated.ElementContains(block) || pool.allocated.ElementsContainedBy(block) != nil {
+		return false
+	}
+	return pool.allocated.Add(block)
+}
+
+// inPool returns whether the block is within a single range of the address space, the ranges being non-adjacent
+func (pool *AddressPool[T]) inPool(block T) bool {
+	for _, rng := range pool.space {
+		if rng.Contains(block.ToIP()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Release removes the given previously allocated block from the allocations, returning whether it had been allocated.
+// The block must match the allocated block, it cannot be a part of an allocated block.
+func (pool *AddressPool[T]) Release(block T) bool {
+	var t T
+	if block == t || pool.version.IsIndeterminate() || !pool.version.Equal(block.GetIPVersion()) {
+		return false
+	}
+	block, err := block.toSinglePrefixBlockOrAddress()
+	if err != nil {
+		return false
+	}
+	return pool.allocated.Remove(block)
+}
+
+// IsAllocated returns whether the given address or subnet is entirely within the allocated blocks,
+// whether within a single allocated block or spread across several.
+func (pool *AddressPool[T]) IsAllocated(addr T) bool {
+	var t T
+	if addr == t || pool.version.IsIndeterminate() || !pool.version.Equal(addr.GetIPVersion()) {
+		return false
+	}
+	for _, block := range addr.SpanWithPrefixBlocks() {
+		if pool.allocated.ElementContains(block) {
+			continue
+		}
+		// the allocated blocks do not overlap, so the block is allocated when the allocated blocks within it leave nothing remaining
+		node := pool.allocated.ElementsContainedBy(block)
+		if node == nil {
+			return false
+		}
+		remaining := block.GetCount()
+		for iter := node.Iterator(); iter.HasNext(); {
+			remaining.Sub(remaining, iter.Next().GetCount())
+		}
+		if remaining.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// GetAllocated returns the allocated blocks in ascending order.
+func (pool *AddressPool[T]) GetAllocated() []T {
+	result := make([]T, 0, pool.allocated.Size())
+	for iter := pool.allocated.Iterator(); iter.HasNext(); {
+		result = append(result, iter.Next())
+	}
+	return result
+}
+
+// GetTotalCount returns the number of individual addresses in the address space of the pool.
+func (pool *AddressPool[T]) GetTotalCount() *big.Int {
+	result := bigZero()
+	for _, rng := range pool.space {
+		result.Add(result, rng.GetCount())
+	}
+	return result
+}
+
+// GetAllocatedCount returns the number of individual addresses in the allocated blocks.
+func (pool *AddressPool[T]) GetAllocatedCount() *big.Int {
+	result := bigZero()
+	for iter := pool.allocated.Iterator(); iter.HasNext(); {
+		result.Add(result, iter.Next().GetCount())
+	}
+	return result
+}
+
+// GetFreeCount returns the number of individual addresses in the address space of the pool that are not allocated.
+func (pool *AddressPool[T]) GetFreeCount() *big.Int {
+	result := pool.GetTotalCount()
+	return result.Sub(result, pool.GetAllocatedCount())
+}
+
+// Utilization returns the fraction of the addresses in the pool that are allocated, from 0 for none to 1 for all.
+// An empty pool has a utilization of 0.
+func (pool *AddressPool[T]) Utilization() float64 {
+	total := pool.GetTotalCount()
+	if bigIsZero(total) {
+		return 0
+	}
+	result, _ := new(big.Float).Quo(new(big.Float).SetInt(pool.GetAllocatedCount()), new(big.Float).SetInt(total)).Float64()
+	return result
+}
+
+// GetAllocatedBlockCounts returns the number of allocated blocks for each prefix length.
+// Allocated individual addresses are counted with the prefix length of the address bit count.
+func (pool *AddressPool[T]) GetAllocatedBlockCounts() map[BitCount]int {
+	result := make(map[BitCount]int)
+	for iter := pool.allocated.Iterator(); iter.HasNext(); {
+		result[getPoolBlockPrefixLen(iter.Next())]++
+	}
+	return result
+}
+
+// GetFreeBlockCounts returns the number of blocks for each prefix length in the free space of the pool, as provided by GetFreeBlocks.
+func (pool *AddressPool[T]) GetFreeBlockCounts() map[BitCount]int {
+	result := make(map[BitCount]int)
+	for _, block := range pool.GetFreeBlocks() {
+		result[getPoolBlockPrefixLen(block)]++
+	}
+	return result
+}
+
+func getPoolBlockPrefixLen[T AddressPoolConstraint[T]](block T) BitCount {
+	if prefLen := block.GetPrefixLen(); prefLen != nil {
+		return prefLen.Len()
+	}
+	return block.GetBitCount()
+}
+
+// GetFreeRanges returns the address space of the pool that is not allocated, as the fewest number of sequential ranges, in ascending order.
+func (pool *AddressPool[T]) GetFreeRanges() (result []*SequentialRange[T]) {
+	iter := pool.allocated.Iterator()
+	var next T
+	hasNext := iter.HasNext()
+	if hasNext {
+		next = iter.Next()
+	}
+	for _, rng := range pool.space {
+		lower, upper := rng.GetLower(), rng.GetUpper()
+		exhausted := false
+		// the allocated blocks are disjoint, so the trie iterates them in ascending order
+		for ; hasNext && rng.Contains(next.ToIP()); next, hasNext = pool.nextAllocated(iter) {
+			blockLower := next.GetLower().WithoutPrefixLen()
+			if !exhausted && compareLowIPAddressValues(lower, blockLower) < 0 {
+				result = append(result, NewSequentialRange(lower, blockLower.Increment(-1)))
+			}
+			lower = next.GetUpper().WithoutPrefixLen().Increment(1)
+			var t T
+			exhausted = lower == t // the block ends at the max address
+		}
+		if !exhausted && compareLowIPAddressValues(lower, upper) <= 0 {
+			result = append(result, NewSequentialRange(lower, upper))
+		}
+	}
+	return
+}
+
+func (pool *AddressPool[T]) nextAllocated(iter Iterator[T]) (next T, hasNext bool) {
+	if hasNext = iter.HasNext(); hasNext {
+		next = iter.Next()
+	}
+	return
+}
+
+// GetFreeBlocks returns the address space of the pool that is not allocated, as the fewest number of prefix blocks in each free range, in ascending order.
+func (pool *AddressPool[T]) GetFreeBlocks() (result []T) {
+	for _, rng := range pool.GetFreeRanges() {
+		result = append(result, rng.SpanWithPrefixBlocks()...)
+	}
+	return
+}
+
+// GetLargestFreeBlock returns the largest prefix block in the free space of the pool, which is the largest block that could be allocated.
+// When there are multiple free blocks of that size, the lowest is returned.  If there is no free space, nil is returned.
+func (pool *AddressPool[T]) GetLargestFreeBlock() (result T) {
+	var t T
+	for _, block := range pool.GetFreeBlocks() {
+		if result == t || getPoolBlockPrefixLen(block) < getPoolBlockPrefixLen(result) {
+			result = block
+		}
+	}
+	return
+}
+
+// GetLargestFreeRange returns the largest contiguous range in the free space of the pool.
+// When there are multiple free ranges of that size, the lowest is returned.  If there is no free space, nil is returned.
+func (pool *AddressPool[T]) GetLargestFreeRange() (result *SequentialRange[T]) {
+	var largest *big.Int
+	for _, rng := range pool.GetFreeRanges() {
+		if count := rng.GetCount(); largest == nil || count.Cmp(largest) > 0 {
+			result, largest = rng, count
+		}
+	}
+	return
+}
+
+// String returns a string showing the total, allocated and free address counts of the pool, along with its utilization.
+func (pool AddressPool[T]) String() string {
+	var builder strings.Builder
+	builder.WriteString("total ")
+	builder.WriteString(pool.GetTotalCount().String())
+	builder.WriteString(", allocated ")
+	builder.WriteString(pool.GetAllocatedCount().String())
+	builder.WriteString(" in ")
+	builder.WriteString(fmt.Sprint(pool.allocated.Size()))
+	if pool.allocated.Size() == 1 {
+		builder.WriteString(" block")
+	} else {
+		builder.WriteString(" blocks")
+	}
+	builder.WriteString(", free ")
+	builder.WriteString(pool.GetFreeCount().String())
+	builder.WriteString(fmt.Sprintf(", utilization %.2f%%", pool.Utilization()*100))
+	return builder.String()
+}
+
+type (
+	IPPool   = AddressPool[*IPAddress]
+	IPv4Pool = AddressPool[*IPv4Address]
+	IPv6Pool = AddressPool[*IPv6Address]
+)
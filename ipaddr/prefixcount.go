@@ -0,0 +1,179 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"sort"
+	"strconv"
+)
+
+// PrefixCounterConstraint is the generic type constraint used for a prefix counter.
+type PrefixCounterConstraint[T any] interface {
+	PrefixBlockConstraint[T]
+	TrieKeyConstraint[T]
+}
+
+var (
+	_ = PrefixCounter[*IPAddress]{}
+	_ = PrefixCounter[*IPv4Address]{}
+	_ = PrefixCounter[*IPv6Address]{}
+)
+
+// PrefixCount is a prefix block along with the total count of the addresses within it, as provided by TopPrefixes of PrefixCounter.
+type PrefixCount[T PrefixCounterConstraint[T]] struct {
+	Prefix T
+	Count  uint64
+}
+
+// String returns the prefix block followed by the count, such as "1.2.3.0/24 100".
+func (prefixCount PrefixCount[T]) String() string {
+	return prefixCount.Prefix.String() + " " + strconv.FormatUint(prefixCount.Count, 10)
+}
+
+// PrefixCounter summarizes counts of addresses, such as the packet or byte counts of the hosts in network traffic,
+// reporting the prefixes covering the highest totals.
+//
+// Counts are added one address at a time with Add, so that a stream of traffic records can be summarized as it is read.
+// The counts are kept in an associative trie mapping each counted address or prefix block to its count,
+// so that the total count within any subnet can be found from the sub-trie of that subnet.
+//
+// The generic type T can be *IPAddress, *IPv4Address or *IPv6Address.
+// Once a counter of generic type *IPAddress has been provided with either an IPv4 or IPv6 address,
+// it can only be used with the same address version from that point onwards.
+//
+// The zero value of a PrefixCounter is an empty counter ready for use.  A PrefixCounter is not safe for concurrent use by multiple goroutines if any goroutine is modifying it.
+type PrefixCounter[T PrefixCounterConstraint[T]] struct {
+	version IPVersion
+	counts  AssociativeTrie[T, uint64]
+	total   uint64
+}
+
+// GetVersion returns the IP version of the counter,
+// which is determined by the version of the first address counted.
+func (counter *PrefixCounter[T]) GetVersion() IPVersion {
+	return counter.version
+}
+
+// Add adds the given count to the count of the given individual address or prefix block, returning true if the count was added.
+//
+// The count is not added, and false is returned, if the address does not match the IP version of the counter,
+// or if it is neither a prefix block nor an individual address.
+// A subnet that can be converted to a prefix block by assigning a prefix length, as with ToSinglePrefixBlockOrAddress, is counted as that block.
+func (counter *PrefixCounter[T]) Add(addr T, count uint64) bool {
+	var t T
+	if addr == t {
+		return false
+	}
+	addr, err := addr.toSinglePrefixBlockOrAddress()
+	if err != nil {
+		return false
+	} else if counter.version.IsIndeterminate() {
+		version := addr.GetIPVersion()
+		if version.IsIndeterminate() {
+			return false
+		}
+		counter.version = version
+	} else if !counter.version.Equal(addr.GetIPVersion()) {
+		return false
+	}
+	counter.counts.Remap(addr, func(existing uint64, _ bool) (uint64, bool) {
+		return existing + count, true
+	})
+	counter.total += count
+	return true
+}
+
+// GetTotal returns the total of all the counts added to the counter.
+func (counter *PrefixCounter[T]) GetTotal() uint64 {
+	return counter.total
+}
+
+// GetCount returns the total of the counts added for the given individual address or prefix block,
+// not including the counts of the addresses and blocks within it.
+// Use GetCountWithin for the total count within a subnet.
+func (counter *PrefixCounter[T]) GetCount(addr T) uint64 {
+	var t T
+	if addr == t || counter.version.IsIndeterminate() || !counter.version.Equal(addr.GetIPVersion()) {
+		return 0
+	}
+	addr, err := addr.toSinglePrefixBlockOrAddress()
+	if err != nil {
+		return 0
+	}
+	count, _ := counter.counts.Get(addr)
+	return count
+}
+
+// GetCountWithin returns the total of the counts added for the individual addresses and prefix blocks within the given subnet.
+func (counter *PrefixCounter[T]) GetCountWithin(subnet T) (total uint64) {
+	var t T
+	if subnet == t || counter.version.IsIndeterminate() || !counter.version.Equal(subnet.GetIPVersion()) {
+		return
+	}
+	for _, block := range subnet.SpanWithPrefixBlocks() {
+		if node := counter.counts.ElementsContainedBy(block); node != nil {
+			iter := node.NodeIterator(true)
+			for iter.HasNext() {
+				total += iter.Next().GetValue()
+			}
+		}
+	}
+	return
+}
+
+// GetTrie returns a copy of the associative trie mapping each counted individual address and prefix block to its count.
+func (counter *PrefixCounter[T]) GetTrie() *AssociativeTrie[T, uint64] {
+	return counter.counts.Clone()
+}
+
+// TopPrefixes returns the k prefix blocks of the given prefix length with the highest total counts, in descending order of count,
+// and in ascending order of prefix for equal counts.  If k is not positive, all the prefix blocks with counted addresses are returned.
+//
+// The total count of each prefix block includes the counts of all the individual addresses and prefix blocks within it.
+// The counts of prefix blocks with a shorter prefix length than the given length, which are not within any one block of the given length, are not included.
+func (counter *PrefixCounter[T]) TopPrefixes(prefixLen BitCount, k int) []PrefixCount[T] {
+	var prefixCounts []PrefixCount[T]
+	// the added nodes within any one prefix block form a sub-trie, which the iteration traverses consecutively
+	iter := counter.counts.NodeIterator(true)
+	for iter.HasNext() {
+		node := iter.Next()
+		key := node.GetKey()
+		if keyPrefLen := key.GetPrefixLen(); keyPrefLen != nil && keyPrefLen.Len() < prefixLen {
+			continue
+		}
+		prefix := key.ToPrefixBlockLen(prefixLen)
+		if last := len(prefixCounts) - 1; last >= 0 && prefixCounts[last].Prefix.Equal(prefix) {
+			prefixCounts[last].Count += node.GetValue()
+		} else {
+			prefixCounts = append(prefixCounts, PrefixCount[T]{Prefix: prefix, Count: node.GetValue()})
+		}
+	}
+	// the stable sort retains the ascending order of prefixes for equal counts
+	sort.SliceStable(prefixCounts, func(i, j int) bool {
+		return prefixCounts[i].Count > prefixCounts[j].Count
+	})
+	if k > 0 && k < len(prefixCounts) {
+		prefixCounts = prefixCounts[:k]
+	}
+	return prefixCounts
+}
+
+type (
+	IPPrefixCounter   = PrefixCounter[*IPAddress]
+	IPv4PrefixCounter = PrefixCounter[*IPv4Address]
+	IPv6PrefixCounter = PrefixCounter[*IPv6Address]
+)
@@ -0,0 +1,165 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// PrefixListFormat is the vendor syntax used when writing a prefix list.
+type PrefixListFormat string
+
+const (
+	// IOSPrefixListFormat produces Cisco IOS "ip prefix-list" and "ipv6 prefix-list" commands.
+	IOSPrefixListFormat PrefixListFormat = ""
+
+	// JunOSPrefixListFormat produces a Juniper JunOS "prefix-list" within a "policy-options" stanza.
+	JunOSPrefixListFormat PrefixListFormat = "junos"
+
+	// RPSLRouteSetFormat produces an RPSL "route-set" object, RFC 2622 and RFC 4012, with IPv6 prefixes listed as "mp-members".
+	RPSLRouteSetFormat PrefixListFormat = "rpsl"
+)
+
+// PrefixListOptions controls how a prefix list is written.  The zero value writes an IOS prefix list permitting each prefix,
+// with sequence numbers starting at 5 and incremented by 5.
+type PrefixListOptions struct {
+	// Format is the vendor syntax of the prefix list.
+	Format PrefixListFormat
+
+	// Description, if not empty, is written as an IOS description, a JunOS comment, or an RPSL "descr" attribute.
+	Description string
+
+	// Deny makes IOS entries deny rather than permit the prefixes.  It is ignored by the other formats.
+	Deny bool
+
+	// SequenceStart is the first IOS sequence number, and SequenceIncrement the increment between sequence numbers.
+	// Each defaults to 5 when zero or negative.  IPv4 and IPv6 prefix lists are numbered independently.
+	SequenceStart, SequenceIncrement int
+}
+
+// WritePrefixList writes the given IP addresses and subnets to the writer as a prefix list with the given name,
+// in the vendor syntax selected by the options.
+//
+// Each subnet that is not a prefix block is written as the series of prefix blocks spanning it, as provided by SpanWithPrefixBlocks,
+// while an individual address without a prefix length is written with the full bit-length as its prefix length.
+// IPv6 zones are omitted.  The prefixes are written in the order given, with all IPv4 prefixes preceding the IPv6 prefixes.
+//
+// An error is returned if any of the addresses is not an IPv4 or IPv6 address, or if writing fails.
+func WritePrefixList[T TrieKeyConstraint[T]](writer io.Writer, name string, blocks []T, options PrefixListOptions) error {
+	ipv4Blocks, ipv6Blocks, err := toPrefixListBlocks(blocks)
+	if err != nil {
+		return err
+	}
+	builder := strings.Builder{}
+	switch options.Format {
+	case JunOSPrefixListFormat:
+		writeJunOSPrefixList(&builder, name, ipv4Blocks, ipv6Blocks, options)
+	case RPSLRouteSetFormat:
+		writeRPSLRouteSet(&builder, name, ipv4Blocks, ipv6Blocks, options)
+	default:
+		writeIOSPrefixList(&builder, "ip", name, ipv4Blocks, options)
+		writeIOSPrefixList(&builder, "ipv6", name, ipv6Blocks, options)
+	}
+	_, err = io.WriteString(writer, builder.String())
+	return err
+}
+
+func toPrefixListBlocks[T TrieKeyConstraint[T]](blocks []T) (ipv4Blocks, ipv6Blocks []string, err error) {
+	for _, block := range blocks {
+		addr := block.ToAddressBase().ToIP()
+		if ipv6Addr := addr.ToIPv6(); ipv6Addr != nil {
+			addr = ipv6Addr.WithoutZone().ToIP()
+		} else if !addr.IsIPv4() {
+			return nil, nil, &incompatibleAddressError{addressError{key: "ipaddress.error.ipVersionIndeterminate"}}
+		}
+		for _, span := range addr.SpanWithPrefixBlocks() {
+			if !span.IsPrefixed() {
+				span = span.ToPrefixBlockLen(span.GetBitCount())
+			}
+			if span.IsIPv4() {
+				ipv4Blocks = append(ipv4Blocks, span.ToCanonicalString())
+			} else {
+				ipv6Blocks = append(ipv6Blocks, span.ToCanonicalString())
+			}
+		}
+	}
+	return
+}
+
+func writeIOSPrefixList(builder *strings.Builder, command, name string, blocks []string, options PrefixListOptions) {
+	if len(blocks) == 0 {
+		return
+	}
+	seq, increment := options.SequenceStart, options.SequenceIncrement
+	if seq <= 0 {
+		seq = 5
+	}
+	if increment <= 0 {
+		increment = 5
+	}
+	action := " permit "
+	if options.Deny {
+		action = " deny "
+	}
+	if options.Description != "" {
+		builder.WriteString(command + " prefix-list " + name + " description " + options.Description + "\n")
+	}
+	for _, block := range blocks {
+		builder.WriteString(command + " prefix-list " + name + " seq " + strconv.Itoa(seq) + action + block + "\n")
+		seq += increment
+	}
+}
+
+func writeJunOSPrefixList(builder *strings.Builder, name string, ipv4Blocks, ipv6Blocks []string, options PrefixListOptions) {
+	builder.WriteString("policy-options {\n    prefix-list " + name + " {\n")
+	if options.Description != "" {
+		builder.WriteString("        /* " + options.Description + " */\n")
+	}
+	for _, blocks := range [][]string{ipv4Blocks, ipv6Blocks} {
+		for _, block := range blocks {
+			builder.WriteString("        " + block + ";\n")
+		}
+	}
+	builder.WriteString("    }\n}\n")
+}
+
+func writeRPSLRouteSet(builder *strings.Builder, name string, ipv4Blocks, ipv6Blocks []string, options PrefixListOptions) {
+	writeRPSLAttribute(builder, "route-set", name)
+	if options.Description != "" {
+		writeRPSLAttribute(builder, "descr", options.Description)
+	}
+	for _, block := range ipv4Blocks {
+		writeRPSLAttribute(builder, "members", block)
+	}
+	for _, block := range ipv6Blocks {
+		writeRPSLAttribute(builder, "mp-members", block)
+	}
+}
+
+// writeRPSLAttribute writes an attribute with its value aligned to the column conventionally used by the routing registries.
+func writeRPSLAttribute(builder *strings.Builder, attribute, value string) {
+	const valueColumn = 16
+	builder.WriteString(attribute)
+	builder.WriteByte(':')
+	for i := len(attribute) + 1; i < valueColumn; i++ {
+		builder.WriteByte(' ')
+	}
+	builder.WriteString(value)
+	builder.WriteByte('\n')
+}
@@ -0,0 +1,283 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// NewRangeSet returns a RangeSet containing the addresses of the given ranges.  Nil ranges are ignored.
+func NewRangeSet[T SequentialRangeConstraint[T]](ranges ...*SequentialRange[T]) *RangeSet[T] {
+	set := &RangeSet[T]{}
+	for _, rng := range ranges {
+		set.Add(rng)
+	}
+	return set
+}
+
+// RangeSet is a set of addresses stored as sequential address ranges,
+// for collections of addresses that arrive as ranges rather than prefix blocks, such as the ranges of geolocation or reputation data sets.
+//
+// The ranges are kept normalized: they are sorted, disjoint, and no two are adjacent, so that each set of addresses has a single representation.
+// Adding a range that overlaps or adjoins ranges in the set merges them into a single range, and removing a range can split a range in two.
+// Membership is determined with a binary search of the ranges.
+// Ranges of both IP versions can be added to a set of *IPAddress ranges, with IPv4 ranges ordered before IPv6 ranges.
+//
+// Unlike a RangeTree, which holds each added range as is, a RangeSet holds only the addresses of the added ranges.
+// For collections of prefix blocks and individual addresses, use a Trie.
+//
+// The zero value is an empty set ready to use.  RangeSet is not concurrency-safe.
+type RangeSet[T SequentialRangeConstraint[T]] struct {
+	ranges []*SequentialRange[T]
+}
+
+// Size returns the number of ranges in the normalized set, which is not the number of addresses.  Use GetCount for the number of addresses.
+func (set *RangeSet[T]) Size() int {
+	return len(set.ranges)
+}
+
+// IsEmpty returns true if there are no addresses in the set.
+func (set *RangeSet[T]) IsEmpty() bool {
+	return len(set.ranges) == 0
+}
+
+// GetCount returns the number of addresses in the set.
+func (set *RangeSet[T]) GetCount() *big.Int {
+	count := new(big.Int)
+	for _, rng := range set.ranges {
+		count.Add(count, rng.GetCount())
+	}
+	return count
+}
+
+// Clear removes all addresses from the set.
+func (set *RangeSet[T]) Clear() {
+	set.ranges = nil
+}
+
+// Clone returns a copy of the set.
+func (set *RangeSet[T]) Clone() *RangeSet[T] {
+	return &RangeSet[T]{set.Ranges()}
+}
+
+// precedesAdjacent returns whether the lower address immediately follows the upper address
+func precedesAdjacent[T SequentialRangeConstraint[T]](upper, lower T) bool {
+	var zero T
+	next := upper.Increment(1)
+	return next != zero && next.GetIPVersion().Equal(lower.GetIPVersion()) && compareLowIPAddressValues(next, lower) == 0
+}
+
+// searchUpper returns the index of the first range whose upper address is not below the given address,
+// or whose upper address immediately precedes the given address when joinAdjacent is true
+func (set *RangeSet[T]) searchUpper(addr T, joinAdjacent bool) int {
+	return sort.Search(len(set.ranges), func(i int) bool {
+		upper := set.ranges[i].GetUpper()
+		return compareLowIPAddressValues(upper, addr) >= 0 || (joinAdjacent && precedesAdjacent(upper, addr))
+	})
+}
+
+// searchLower returns the index of the first range whose lower address is above the given address,
+// and does not immediately follow the given address when joinAdjacent is true
+func (set *RangeSet[T]) searchLower(addr T, joinAdjacent bool) int {
+	return sort.Search(len(set.ranges), func(i int) bool {
+		lower := set.ranges[i].GetLower()
+		return compareLowIPAddressValues(lower, addr) > 0 && !(joinAdjacent && precedesAdjacent(addr, lower))
+	})
+}
+
+// Add adds the addresses of the given range to the set, merging it with the ranges it overlaps or adjoins.
+// Returns true if the set was changed, which is when the set did not already contain all the addresses of the range.
+// A nil range is not added.
+func (set *RangeSet[T]) Add(rng *SequentialRange[T]) bool {
+	if rng == nil {
+		return false
+	}
+	rng = rng.init()
+	lower, upper := rng.GetLower(), rng.GetUpper()
+	start, end := set.searchUpper(lower, true), set.searchLower(upper, true)
+	if start == end {
+		set.ranges = append(set.ranges, nil)
+		copy(set.ranges[start+1:], set.ranges[start:])
+		set.ranges[start] = NewSequentialRange(lower, upper)
+		return true
+	}
+	first, last := set.ranges[start], set.ranges[end-1]
+	if start+1 == end && first.ContainsRange(rng) {
+		return false
+	}
+	if compareLowIPAddressValues(first.GetLower(), lower) < 0 {
+		lower = first.GetLower()
+	}
+	if compareLowIPAddressValues(last.GetUpper(), upper) > 0 {
+		upper = last.GetUpper()
+	}
+	set.ranges[start] = NewSequentialRange(lower, upper)
+	set.ranges = append(set.ranges[:start+1], set.ranges[end:]...)
+	return true
+}
+
+// AddAll adds the addresses of the given ranges to the set, returning true if the set was changed.
+func (set *RangeSet[T]) AddAll(ranges ...*SequentialRange[T]) (changed bool) {
+	for _, rng := range ranges {
+		if set.Add(rng) {
+			changed = true
+		}
+	}
+	return
+}
+
+// Remove removes the addresses of the given range from the set, splitting a range of the set in two when the given range is inside it.
+// Returns true if the set was changed, which is when the set contained any of the addresses of the range.
+func (set *RangeSet[T]) Remove(rng *SequentialRange[T]) bool {
+	if rng == nil {
+		return false
+	}
+	rng = rng.init()
+	start, end := set.searchUpper(rng.GetLower(), false), set.searchLower(rng.GetUpper(), false)
+	if start == end {
+		return false
+	}
+	var remaining []*SequentialRange[T]
+	for _, existing := range set.ranges[start:end] {
+		remaining = append(remaining, existing.Subtract(rng)...)
+	}
+	set.ranges = append(set.ranges[:start], append(remaining, set.ranges[end:]...)...)
+	return true
+}
+
+// Contains returns whether the set contains all the addresses of the given address or subnet.
+func (set *RangeSet[T]) Contains(addr T) bool {
+	var zero T
+	if addr == zero {
+		return false
+	}
+	index := set.searchUpper(addr.GetUpper(), false)
+	return index < len(set.ranges) && set.ranges[index].Contains(addr.ToIP())
+}
+
+// ContainsRange returns whether the set contains all the addresses of the given range.
+func (set *RangeSet[T]) ContainsRange(rng *SequentialRange[T]) bool {
+	if rng == nil {
+		return false
+	}
+	index := set.searchUpper(rng.GetUpper(), false)
+	return index < len(set.ranges) && set.ranges[index].ContainsRange(rng)
+}
+
+// Overlaps returns whether the set contains any of the addresses of the given range.
+func (set *RangeSet[T]) Overlaps(rng *SequentialRange[T]) bool {
+	if rng == nil {
+		return false
+	}
+	index := set.searchUpper(rng.GetLower(), false)
+	return index < len(set.ranges) && set.ranges[index].Overlaps(rng)
+}
+
+// Union returns a new set containing the addresses in this set or the given set, or both.
+func (set *RangeSet[T]) Union(other *RangeSet[T]) *RangeSet[T] {
+	ranges := make([]*SequentialRange[T], 0, len(set.ranges)+len(other.ranges))
+	ranges = append(append(ranges, set.ranges...), other.ranges...)
+	return &RangeSet[T]{joinRanges(ranges)}
+}
+
+// Intersect returns a new set containing the addresses in both this set and the given set.
+func (set *RangeSet[T]) Intersect(other *RangeSet[T]) *RangeSet[T] {
+	var result []*SequentialRange[T]
+	for i, j := 0, 0; i < len(set.ranges) && j < len(other.ranges); {
+		one, two := set.ranges[i], other.ranges[j]
+		if intersection := one.Intersect(two); intersection != nil {
+			result = append(result, intersection)
+		}
+		if compareLowIPAddressValues(one.GetUpper(), two.GetUpper()) < 0 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return &RangeSet[T]{result}
+}
+
+// Subtract returns a new set containing the addresses in this set that are not in the given set.
+func (set *RangeSet[T]) Subtract(other *RangeSet[T]) *RangeSet[T] {
+	var result []*SequentialRange[T]
+	j := 0
+	for _, rng := range set.ranges {
+		for j < len(other.ranges) && compareLowIPAddressValues(other.ranges[j].GetUpper(), rng.GetLower()) < 0 {
+			j++
+		}
+		// subtract the ranges of the other set that overlap, which are in order, leaving the addresses above each to be subtracted from next
+		for k := j; rng != nil && k < len(other.ranges) && compareLowIPAddressValues(other.ranges[k].GetLower(), rng.GetUpper()) <= 0; k++ {
+			remaining := rng.Subtract(other.ranges[k])
+			rng = nil
+			for _, part := range remaining {
+				if compareLowIPAddressValues(part.GetLower(), other.ranges[k].GetLower()) < 0 {
+					result = append(result, part)
+				} else {
+					rng = part
+				}
+			}
+		}
+		if rng != nil {
+			result = append(result, rng)
+		}
+	}
+	return &RangeSet[T]{result}
+}
+
+// Equal returns whether this set and the given set contain the same addresses.
+func (set *RangeSet[T]) Equal(other *RangeSet[T]) bool {
+	if len(set.ranges) != len(other.ranges) {
+		return false
+	}
+	for i, rng := range set.ranges {
+		if !rng.Equal(other.ranges[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Iterator returns an iterator that iterates through the ranges of the normalized set in sorted order.
+// The iterator is not affected by later changes to the set.
+func (set *RangeSet[T]) Iterator() Iterator[*SequentialRange[T]] {
+	return &sliceIterator[*SequentialRange[T]]{set.Ranges()}
+}
+
+// Ranges returns the ranges of the normalized set in sorted order.
+func (set *RangeSet[T]) Ranges() []*SequentialRange[T] {
+	return append(make([]*SequentialRange[T], 0, len(set.ranges)), set.ranges...)
+}
+
+// String returns the ranges of the set in sorted order, separated by commas and enclosed in square brackets, such as "[1.0.0.0 -> 1.0.0.5, 2.0.0.0 -> 2.0.0.8]".
+// It returns "<nil>" if the receiver is a nil pointer.
+func (set *RangeSet[T]) String() string {
+	if set == nil {
+		return nilString()
+	}
+	var builder strings.Builder
+	builder.WriteByte('[')
+	for i, rng := range set.ranges {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(rng.String())
+	}
+	builder.WriteByte(']')
+	return builder.String()
+}
@@ -0,0 +1,315 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"strings"
+)
+
+type rangeTreeNode[T SequentialRangeConstraint[T]] struct {
+	rng         *SequentialRange[T]
+	maxUpper    T // the highest upper address of the ranges in the sub-tree
+	left, right *rangeTreeNode[T]
+	height      int
+}
+
+func (node *rangeTreeNode[T]) getHeight() int {
+	if node == nil {
+		return 0
+	}
+	return node.height
+}
+
+func (node *rangeTreeNode[T]) update() {
+	leftHeight, rightHeight := node.left.getHeight(), node.right.getHeight()
+	if leftHeight > rightHeight {
+		node.height = leftHeight + 1
+	} else {
+		node.height = rightHeight + 1
+	}
+	node.maxUpper = node.rng.GetUpper()
+	for _, child := range []*rangeTreeNode[T]{node.left, node.right} {
+		if child != nil && compareLowIPAddressValues(child.maxUpper, node.maxUpper) > 0 {
+			node.maxUpper = child.maxUpper
+		}
+	}
+}
+
+func (node *rangeTreeNode[T]) rotateLeft() *rangeTreeNode[T] {
+	right := node.right
+	node.right = right.left
+	node.update()
+	right.left = node
+	right.update()
+	return right
+}
+
+func (node *rangeTreeNode[T]) rotateRight() *rangeTreeNode[T] {
+	left := node.left
+	node.left = left.right
+	node.update()
+	left.right = node
+	left.update()
+	return left
+}
+
+// balance restores the AVL balance of the sub-tree after an insertion or removal beneath this node, returning the new sub-tree root
+func (node *rangeTreeNode[T]) balance() *rangeTreeNode[T] {
+	node.update()
+	if diff := node.left.getHeight() - node.right.getHeight(); diff > 1 {
+		if node.left.left.getHeight() < node.left.right.getHeight() {
+			node.left = node.left.rotateLeft()
+		}
+		return node.rotateRight()
+	} else if diff < -1 {
+		if node.right.right.getHeight() < node.right.left.getHeight() {
+			node.right = node.right.rotateRight()
+		}
+		return node.rotateLeft()
+	}
+	return node
+}
+
+// compareRanges orders ranges by lower address, and then by upper address
+func compareRanges[T SequentialRangeConstraint[T]](one, two *SequentialRange[T]) int {
+	if result := compareLowIPAddressValues(one.GetLower(), two.GetLower()); result != 0 {
+		return result
+	}
+	return compareLowIPAddressValues(one.GetUpper(), two.GetUpper())
+}
+
+// RangeTree is an interval tree of sequential address ranges, for collections of arbitrary ranges that are not necessarily prefix blocks,
+// such as the ranges of geolocation or WHOIS data sets.
+// For collections of prefix blocks and individual addresses, use a Trie.
+//
+// The tree is a balanced binary tree ordered by the lower address of each range, and then the upper address,
+// in which each node records the highest upper address beneath it,
+// so that the ranges containing an address, or overlapping a range, are found without visiting the ranges that do not.
+// Ranges of both IP versions can be added to a tree of *IPAddress ranges, with IPv4 ranges ordered before IPv6 ranges.
+//
+// A range is added at most once, ranges being equal when their lower and upper addresses are equal, but added ranges can otherwise overlap.
+//
+// The zero value is an empty tree ready to use.  RangeTree is not concurrency-safe.
+type RangeTree[T SequentialRangeConstraint[T]] struct {
+	root *rangeTreeNode[T]
+	size int
+}
+
+// Size returns the number of ranges in the tree.
+func (tree *RangeTree[T]) Size() int {
+	return tree.size
+}
+
+// IsEmpty returns true if there are no ranges in the tree.
+func (tree *RangeTree[T]) IsEmpty() bool {
+	return tree.size == 0
+}
+
+// Clear removes all ranges from the tree.
+func (tree *RangeTree[T]) Clear() {
+	tree.root = nil
+	tree.size = 0
+}
+
+// Add adds the given range to the tree.
+// Returns true if the range was not already in the tree.  A nil range is not added.
+func (tree *RangeTree[T]) Add(rng *SequentialRange[T]) bool {
+	if rng == nil {
+		return false
+	}
+	added := false
+	tree.root = tree.add(tree.root, rng.init(), &added)
+	if added {
+		tree.size++
+	}
+	return added
+}
+
+func (tree *RangeTree[T]) add(node *rangeTreeNode[T], rng *SequentialRange[T], added *bool) *rangeTreeNode[T] {
+	if node == nil {
+		*added = true
+		newNode := &rangeTreeNode[T]{rng: rng}
+		newNode.update()
+		return newNode
+	}
+	comp := compareRanges(rng, node.rng)
+	if comp < 0 {
+		node.left = tree.add(node.left, rng, added)
+	} else if comp > 0 {
+		node.right = tree.add(node.right, rng, added)
+	} else {
+		return node
+	}
+	return node.balance()
+}
+
+// Remove removes the given range from the tree.
+// Returns true if the range was in the tree and was removed.
+func (tree *RangeTree[T]) Remove(rng *SequentialRange[T]) bool {
+	if rng == nil {
+		return false
+	}
+	removed := false
+	tree.root = tree.remove(tree.root, rng.init(), &removed)
+	if removed {
+		tree.size--
+	}
+	return removed
+}
+
+func (tree *RangeTree[T]) remove(node *rangeTreeNode[T], rng *SequentialRange[T], removed *bool) *rangeTreeNode[T] {
+	if node == nil {
+		return nil
+	}
+	comp := compareRanges(rng, node.rng)
+	if comp < 0 {
+		node.left = tree.remove(node.left, rng, removed)
+	} else if comp > 0 {
+		node.right = tree.remove(node.right, rng, removed)
+	} else {
+		*removed = true
+		if node.left == nil {
+			return node.right
+		} else if node.right == nil {
+			return node.left
+		}
+		// replace the range with its successor, the lowest range in the right sub-tree
+		successor := node.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		node.rng = successor.rng
+		node.right = tree.remove(node.right, successor.rng, new(bool))
+	}
+	return node.balance()
+}
+
+// Contains returns whether the given range is in the tree.
+// To find the ranges in the tree containing an address or range, use ElementsContaining.
+func (tree *RangeTree[T]) Contains(rng *SequentialRange[T]) bool {
+	if rng == nil {
+		return false
+	}
+	rng = rng.init()
+	for node := tree.root; node != nil; {
+		comp := compareRanges(rng, node.rng)
+		if comp == 0 {
+			return true
+		} else if comp < 0 {
+			node = node.left
+		} else {
+			node = node.right
+		}
+	}
+	return false
+}
+
+// ElementsContaining returns the ranges in the tree that contain all the addresses of the given address or subnet, in sorted order.
+// With an individual address this is a stabbing query, returning the ranges into which the address falls.
+func (tree *RangeTree[T]) ElementsContaining(addr T) []*SequentialRange[T] {
+	var zero T
+	if addr == zero {
+		return nil
+	}
+	return tree.collect(addr.GetLower(), addr.GetUpper(), func(rng *SequentialRange[T]) bool {
+		return rng.Contains(addr.ToIP())
+	})
+}
+
+// ElementsContainingRange returns the ranges in the tree that contain all the addresses of the given range, in sorted order.
+func (tree *RangeTree[T]) ElementsContainingRange(rng *SequentialRange[T]) []*SequentialRange[T] {
+	if rng == nil {
+		return nil
+	}
+	rng = rng.init()
+	return tree.collect(rng.GetLower(), rng.GetUpper(), func(element *SequentialRange[T]) bool {
+		return element.ContainsRange(rng)
+	})
+}
+
+// ElementsOverlapping returns the ranges in the tree that share at least one address with the given range, in sorted order.
+func (tree *RangeTree[T]) ElementsOverlapping(rng *SequentialRange[T]) []*SequentialRange[T] {
+	if rng == nil {
+		return nil
+	}
+	rng = rng.init()
+	lower, upper := rng.GetLower(), rng.GetUpper()
+	return tree.collect(upper, lower, func(element *SequentialRange[T]) bool {
+		return element.Overlaps(rng)
+	})
+}
+
+// collect returns the ranges matching the given filter, in order, skipping the sub-trees whose ranges all start above maxLower,
+// and the sub-trees whose ranges all end below minUpper
+func (tree *RangeTree[T]) collect(maxLower, minUpper T, filter func(*SequentialRange[T]) bool) (result []*SequentialRange[T]) {
+	var visit func(node *rangeTreeNode[T])
+	visit = func(node *rangeTreeNode[T]) {
+		if node == nil || compareLowIPAddressValues(node.maxUpper, minUpper) < 0 {
+			return
+		}
+		visit(node.left)
+		if compareLowIPAddressValues(node.rng.GetLower(), maxLower) > 0 {
+			return
+		}
+		if filter(node.rng) {
+			result = append(result, node.rng)
+		}
+		visit(node.right)
+	}
+	visit(tree.root)
+	return
+}
+
+// Iterator returns an iterator that iterates through the ranges of the tree in sorted order,
+// by lower address and then by upper address.
+// The iterator is not affected by later changes to the tree.
+func (tree *RangeTree[T]) Iterator() Iterator[*SequentialRange[T]] {
+	return &sliceIterator[*SequentialRange[T]]{tree.Elements()}
+}
+
+// Elements returns the ranges of the tree in sorted order, by lower address and then by upper address.
+func (tree *RangeTree[T]) Elements() []*SequentialRange[T] {
+	result := make([]*SequentialRange[T], 0, tree.size)
+	var visit func(node *rangeTreeNode[T])
+	visit = func(node *rangeTreeNode[T]) {
+		if node != nil {
+			visit(node.left)
+			result = append(result, node.rng)
+			visit(node.right)
+		}
+	}
+	visit(tree.root)
+	return result
+}
+
+// String returns the ranges of the tree in sorted order, separated by commas and enclosed in square brackets, such as "[1.0.0.0 -> 1.0.0.5, 2.0.0.0 -> 2.0.0.8]".
+// It returns "<nil>" if the receiver is a nil pointer.
+func (tree *RangeTree[T]) String() string {
+	if tree == nil {
+		return nilString()
+	}
+	var builder strings.Builder
+	builder.WriteByte('[')
+	for i, rng := range tree.Elements() {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(rng.String())
+	}
+	builder.WriteByte(']')
+	return builder.String()
+}
@@ -0,0 +1,191 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// NetworkRegistry maps user-defined names to networks, such as "corp-lan" to 10.0.0.0/8,
+// where each network is a CIDR prefix block or an individual address.
+// Each network has a single name, and each name a single network.
+//
+// Matching an address to the networks containing it uses an address trie for each IP version.
+//
+// The zero value is an empty registry ready for use.  A NetworkRegistry is not safe for concurrent use by multiple goroutines if any goroutine is modifying it.
+//
+// A NetworkRegistry is marshalled to a JSON object mapping each name to the string of its network.
+type NetworkRegistry struct {
+	networks map[string]*IPAddress
+	ipv4Trie AssociativeTrie[*IPv4Address, string]
+	ipv6Trie AssociativeTrie[*IPv6Address, string]
+}
+
+// Register assigns the given name to the given network, which must be a CIDR prefix block or an individual address,
+// or a subnet that can be converted to a prefix block by assigning a prefix length, as with ToSinglePrefixBlockOrAddress.
+// Any IPv6 zone is dropped.
+//
+// If the name was already registered, it is reassigned to the given network.
+// If the network was already registered with a different name, that name is unregistered.
+//
+// If the network is nil or not a prefix block or individual address, the registry is unchanged and an error is returned.
+func (registry *NetworkRegistry) Register(name string, network *IPAddress) addrerr.IncompatibleAddressError {
+	network, err := toRegisteredNetwork(network)
+	if err != nil {
+		return err
+	}
+	registry.Unregister(name)
+	var previousName string
+	var added bool
+	if ipv4Network := network.ToIPv4(); ipv4Network != nil {
+		previousName, added = registry.ipv4Trie.Put(ipv4Network, name)
+	} else {
+		previousName, added = registry.ipv6Trie.Put(network.ToIPv6(), name)
+	}
+	if !added {
+		delete(registry.networks, previousName)
+	}
+	if registry.networks == nil {
+		registry.networks = make(map[string]*IPAddress)
+	}
+	registry.networks[name] = network
+	return nil
+}
+
+func toRegisteredNetwork(network *IPAddress) (*IPAddress, addrerr.IncompatibleAddressError) {
+	if ipv6Network := network.ToIPv6(); ipv6Network != nil && ipv6Network.HasZone() {
+		network = ipv6Network.WithoutZone().ToIP()
+	}
+	if !network.IsIPv4() && !network.IsIPv6() {
+		return nil, &incompatibleAddressError{addressError{key: "ipaddress.error.address.not.block"}}
+	}
+	return network.toSinglePrefixBlockOrAddress()
+}
+
+// Unregister removes the given name and its network from the registry, returning whether the name was registered.
+func (registry *NetworkRegistry) Unregister(name string) bool {
+	network, ok := registry.networks[name]
+	if !ok {
+		return false
+	}
+	delete(registry.networks, name)
+	if ipv4Network := network.ToIPv4(); ipv4Network != nil {
+		registry.ipv4Trie.Remove(ipv4Network)
+	} else {
+		registry.ipv6Trie.Remove(network.ToIPv6())
+	}
+	return true
+}
+
+// Get returns the network registered with the given name, or nil if the name is not registered.
+func (registry *NetworkRegistry) Get(name string) *IPAddress {
+	return registry.networks[name]
+}
+
+// Len returns the number of registered names.
+func (registry *NetworkRegistry) Len() int {
+	return len(registry.networks)
+}
+
+// Names returns the registered names in sorted order.
+func (registry *NetworkRegistry) Names() []string {
+	names := make([]string, 0, len(registry.networks))
+	for name := range registry.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the name of the most specific registered network containing the given address or subnet, along with that network.
+// It returns the empty string and a nil network if no registered network contains the address or subnet.
+func (registry *NetworkRegistry) Lookup(addr *IPAddress) (name string, network *IPAddress) {
+	if ipv4Addr := addr.ToIPv4(); ipv4Addr != nil {
+		if node := registry.ipv4Trie.LongestPrefixMatchNode(toLookupKey(ipv4Addr)); node != nil {
+			return node.GetValue(), node.GetKey().ToIP()
+		}
+	} else if ipv6Addr := addr.ToIPv6(); ipv6Addr != nil {
+		if node := registry.ipv6Trie.LongestPrefixMatchNode(toLookupKey(ipv6Addr.WithoutZone())); node != nil {
+			return node.GetValue(), node.GetKey().ToIP()
+		}
+	}
+	return
+}
+
+// LookupAll returns the names of all registered networks containing the given address or subnet,
+// ordered from the least specific network to the most specific.
+func (registry *NetworkRegistry) LookupAll(addr *IPAddress) (names []string) {
+	if ipv4Addr := addr.ToIPv4(); ipv4Addr != nil {
+		for node := registry.ipv4Trie.ElementsContaining(toLookupKey(ipv4Addr)).ShortestPrefixMatch(); node != nil; node = node.Next() {
+			names = append(names, node.GetValue())
+		}
+	} else if ipv6Addr := addr.ToIPv6(); ipv6Addr != nil {
+		for node := registry.ipv6Trie.ElementsContaining(toLookupKey(ipv6Addr.WithoutZone())).ShortestPrefixMatch(); node != nil; node = node.Next() {
+			names = append(names, node.GetValue())
+		}
+	}
+	return
+}
+
+// toLookupKey converts a subnet to the smallest prefix block containing it,
+// since any registered network containing the subnet contains that block as well.
+// The prefix length is removed first, so that an individual address with a prefix length is treated as that address.
+func toLookupKey[T interface {
+	WithoutPrefixLen() T
+	CoverWithPrefixBlock() T
+	ToSinglePrefixBlockOrAddress() T
+}](addr T) T {
+	return addr.WithoutPrefixLen().CoverWithPrefixBlock().ToSinglePrefixBlockOrAddress()
+}
+
+// MarshalJSON implements the json.Marshaler interface, writing a JSON object mapping each name to the canonical string of its network.
+func (registry NetworkRegistry) MarshalJSON() ([]byte, error) {
+	strs := make(map[string]string, len(registry.networks))
+	for name, network := range registry.networks {
+		strs[name] = network.String()
+	}
+	return json.Marshal(strs)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, replacing the contents of the registry with the names and networks of the given JSON object.
+// If a network string cannot be parsed, or is not a prefix block or individual address, or the same network appears under more than one name,
+// an error is returned and the registry is unchanged.
+func (registry *NetworkRegistry) UnmarshalJSON(data []byte) error {
+	var strs map[string]string
+	if err := json.Unmarshal(data, &strs); err != nil {
+		return err
+	}
+	var result NetworkRegistry
+	var count int
+	for name, str := range strs {
+		network, err := NewIPAddressString(str).ToAddress()
+		if err != nil {
+			return err
+		} else if err = result.Register(name, network); err != nil {
+			return err
+		}
+		// the names are distinct, so registering drops another name only when the network was already registered
+		if count++; result.Len() != count {
+			return &incompatibleAddressError{addressError{str: str, key: "ipaddress.error.duplicate.network"}}
+		}
+	}
+	*registry = result
+	return nil
+}
@@ -0,0 +1,172 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// Relation describes how the set of values in one address, subnet, section or sequential range relates to the set of values in another.
+type Relation int
+
+const (
+	// RelationDisjoint indicates the two share no values and are not adjacent.
+	// Items of different types, versions or segment counts are always disjoint.
+	RelationDisjoint Relation = iota
+
+	// RelationAdjacent indicates the two share no values, but the highest value of one immediately precedes the lowest value of the other.
+	RelationAdjacent
+
+	// RelationOverlaps indicates the two share some values, but neither contains the other.
+	RelationOverlaps
+
+	// RelationContainedBy indicates all values of the first are values of the second, which has additional values.
+	RelationContainedBy
+
+	// RelationContains indicates all values of the second are values of the first, which has additional values.
+	RelationContains
+
+	// RelationEqual indicates the two have the same values.
+	RelationEqual
+)
+
+var relationStrings = [...]string{
+	RelationDisjoint:    "Disjoint",
+	RelationAdjacent:    "Adjacent",
+	RelationOverlaps:    "Overlaps",
+	RelationContainedBy: "ContainedBy",
+	RelationContains:    "Contains",
+	RelationEqual:       "Equal",
+}
+
+// String returns the name of the relation, such as "Contains" or "Disjoint".
+func (relation Relation) String() string {
+	if relation >= 0 && int(relation) < len(relationStrings) {
+		return relationStrings[relation]
+	}
+	return "Relation(" + strconv.Itoa(int(relation)) + ")"
+}
+
+// Reverse returns the relation with the roles of the two items swapped, so that Contains becomes ContainedBy and vice-versa.
+func (relation Relation) Reverse() Relation {
+	switch relation {
+	case RelationContains:
+		return RelationContainedBy
+	case RelationContainedBy:
+		return RelationContains
+	}
+	return relation
+}
+
+// IsOverlapping returns whether the relation is one in which the two items share at least one value.
+func (relation Relation) IsOverlapping() bool {
+	return relation >= RelationOverlaps
+}
+
+func (section *addressSectionInternal) relation(other AddressSectionType) Relation {
+	if other == nil {
+		return RelationDisjoint
+	}
+	otherSection := other.ToSectionBase()
+	if otherSection == nil {
+		return RelationDisjoint
+	} else if section.toAddressSection() == otherSection {
+		return RelationEqual
+	}
+	matches, count := section.matchesTypeAndCount(otherSection)
+	if !matches {
+		return RelationDisjoint
+	}
+	// subnets are the cartesian product of their segment ranges,
+	// so they overlap when each pair of segments overlaps, and one contains the other when each segment does
+	contains, containedBy := true, true
+	for i := 0; i < count; i++ {
+		seg, otherSeg := section.GetSegment(i), otherSection.GetSegment(i)
+		lower, upper := seg.GetSegmentValue(), seg.GetUpperSegmentValue()
+		otherLower, otherUpper := otherSeg.GetSegmentValue(), otherSeg.GetUpperSegmentValue()
+		if upper < otherLower || otherUpper < lower {
+			if isAdjacentValue(section.GetUpperValue(), otherSection.GetValue()) ||
+				isAdjacentValue(otherSection.GetUpperValue(), section.GetValue()) {
+				return RelationAdjacent
+			}
+			return RelationDisjoint
+		}
+		contains = contains && lower <= otherLower && upper >= otherUpper
+		containedBy = containedBy && otherLower <= lower && otherUpper >= upper
+	}
+	return toRelation(contains, containedBy)
+}
+
+func (addr *addressInternal) relation(other AddressType) Relation {
+	if other == nil {
+		return RelationDisjoint
+	}
+	otherAddr := other.ToAddressBase()
+	if otherAddr == nil {
+		return RelationDisjoint
+	} else if addr.toAddress() == otherAddr {
+		return RelationEqual
+	} else if addr.section == nil {
+		if otherAddr.GetSection().GetSegmentCount() == 0 {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	} else if !addr.isSameZone(otherAddr) {
+		// addresses in different zones have no addresses in common
+		return RelationDisjoint
+	}
+	return addr.section.relation(otherAddr.GetSection())
+}
+
+func (rng *SequentialRange[T]) relation(other *SequentialRange[*IPAddress]) Relation {
+	lower, upper := rng.lower, rng.upper
+	otherLower, otherUpper := other.GetLower(), other.GetUpper()
+	if lower.GetIPVersion() != otherLower.GetIPVersion() {
+		return RelationDisjoint
+	} else if compareLowIPAddressValues(upper, otherLower) < 0 {
+		if isAdjacentValue(upper.GetValue(), otherLower.GetValue()) {
+			return RelationAdjacent
+		}
+		return RelationDisjoint
+	} else if compareLowIPAddressValues(otherUpper, lower) < 0 {
+		if isAdjacentValue(otherUpper.GetValue(), lower.GetValue()) {
+			return RelationAdjacent
+		}
+		return RelationDisjoint
+	}
+	lowerComp, upperComp := compareLowIPAddressValues(lower, otherLower), compareLowIPAddressValues(upper, otherUpper)
+	return toRelation(lowerComp <= 0 && upperComp >= 0, lowerComp >= 0 && upperComp <= 0)
+}
+
+func toRelation(contains, containedBy bool) Relation {
+	if contains {
+		if containedBy {
+			return RelationEqual
+		}
+		return RelationContains
+	} else if containedBy {
+		return RelationContainedBy
+	}
+	return RelationOverlaps
+}
+
+// isAdjacentValue returns whether the upper value immediately precedes the lower value.
+// The given upper value is modified.
+func isAdjacentValue(upper, lower *big.Int) bool {
+	return upper.Add(upper, bigOneConst()).Cmp(lower) == 0
+}
@@ -0,0 +1,169 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"strconv"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+// ReverseDNSCNAME is a CNAME record placed in an octet-aligned reverse-DNS zone to delegate the PTR record of a single address
+// to a classless reverse-DNS zone, as described by RFC 2317.
+type ReverseDNSCNAME struct {
+	// Name is the owner name of the record, the reverse-DNS string of the address, such as "5.2.0.192.in-addr.arpa".
+	Name string
+
+	// Target is the name of the PTR record within the classless zone, such as "5.0/26.2.0.192.in-addr.arpa".
+	Target string
+}
+
+// ReverseDNSZone is a reverse-DNS zone covering a prefix block of addresses, produced by SplitToReverseZones.
+//
+// Reverse-DNS zones are delegated on octet boundaries for IPv4 and nibble boundaries for IPv6.
+// An IPv4 block with a prefix length longer than 24 has no zone of its own in the reverse-DNS tree,
+// so it is given a classless zone named as described by RFC 2317, such as "0/26.2.0.192.in-addr.arpa" for "192.0.2.0/26".
+// The zone is delegated from the parent octet-aligned zone with the CNAME records returned by GetCNAMERecords.
+type ReverseDNSZone struct {
+	block      *IPAddress
+	name       string
+	parentName string
+}
+
+// GetBlock returns the prefix block of addresses covered by this zone.
+func (zone *ReverseDNSZone) GetBlock() *IPAddress {
+	return zone.block
+}
+
+// GetName returns the name of this zone, such as "2.0.192.in-addr.arpa", or "0/26.2.0.192.in-addr.arpa" for a classless zone.
+func (zone *ReverseDNSZone) GetName() string {
+	return zone.name
+}
+
+// GetParentName returns the name of the octet-aligned zone containing the CNAME records that delegate a classless zone,
+// such as "2.0.192.in-addr.arpa" for "0/26.2.0.192.in-addr.arpa".
+// For a zone that is not classless, it is the same as GetName.
+func (zone *ReverseDNSZone) GetParentName() string {
+	return zone.parentName
+}
+
+// IsClassless returns whether this is an RFC 2317 classless zone, an IPv4 zone for a prefix length longer than 24.
+func (zone *ReverseDNSZone) IsClassless() bool {
+	return zone.name != zone.parentName
+}
+
+// ToPTRRecordName returns the name of the PTR record for the given address within this zone.
+// For a classless zone such as "0/26.2.0.192.in-addr.arpa" and the address "192.0.2.5" it is "5.0/26.2.0.192.in-addr.arpa",
+// otherwise it is the reverse-DNS string of the address.
+//
+// An error is returned if the given address is not a single address within this zone.
+func (zone *ReverseDNSZone) ToPTRRecordName(addr *IPAddress) (string, addrerr.IncompatibleAddressError) {
+	if addr == nil || addr.IsMultiple() || !zone.block.Contains(addr) {
+		return "", &incompatibleAddressError{addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	if zone.IsClassless() {
+		bytes := addr.Bytes()
+		return strconv.Itoa(int(bytes[len(bytes)-1])) + string(IPv4SegmentSeparator) + zone.name, nil
+	}
+	return addr.ToReverseDNSString()
+}
+
+// GetCNAMERecords returns the CNAME records to place in the parent zone to delegate the PTR records of a classless zone, one for each address in the zone.
+// It returns nil if this zone is not classless.
+func (zone *ReverseDNSZone) GetCNAMERecords() []ReverseDNSCNAME {
+	if !zone.IsClassless() {
+		return nil
+	}
+	var records []ReverseDNSCNAME
+	for iter := zone.block.WithoutPrefixLen().Iterator(); iter.HasNext(); {
+		addr := iter.Next()
+		name, _ := addr.ToReverseDNSString()
+		target, _ := zone.ToPTRRecordName(addr)
+		records = append(records, ReverseDNSCNAME{Name: name, Target: target})
+	}
+	return records
+}
+
+// String returns the zone name, or "<nil>" if the receiver is a nil pointer.
+func (zone *ReverseDNSZone) String() string {
+	if zone == nil {
+		return nilString()
+	}
+	return zone.name
+}
+
+// newReverseDNSZones returns the zones covering the given prefix block
+func newReverseDNSZones(block *IPAddress) []*ReverseDNSZone {
+	prefLen := block.GetMinPrefixLenForBlock()
+	block = block.ToPrefixBlockLen(prefLen)
+	if block.IsIPv4() && prefLen > IPv4BitCount-IPv4BitsPerSegment {
+		parentName := block.ToPrefixBlockLen(IPv4BitCount - IPv4BitsPerSegment).ToReverseDNSZoneStrings()[0]
+		bytes := block.Bytes()
+		name := strconv.Itoa(int(bytes[len(bytes)-1])) + "/" + strconv.Itoa(int(prefLen)) + string(IPv4SegmentSeparator) + parentName
+		return []*ReverseDNSZone{{block: block, name: name, parentName: parentName}}
+	}
+	var labelBits BitCount = 4
+	if block.IsIPv4() {
+		labelBits = IPv4BitsPerSegment
+	}
+	zoneLen := (prefLen + labelBits - 1) / labelBits * labelBits
+	var zones []*ReverseDNSZone
+	for iter := block.SetPrefixLen(zoneLen).PrefixBlockIterator(); iter.HasNext(); {
+		zoneBlock := iter.Next()
+		name := zoneBlock.ToReverseDNSZoneStrings()[0]
+		zones = append(zones, &ReverseDNSZone{block: zoneBlock, name: name, parentName: name})
+	}
+	return zones
+}
+
+// SplitToReverseZones returns the reverse-DNS zones that together cover the addresses of this range, in increasing order.
+//
+// The range is split into prefix blocks, and each block is split into octet-aligned zones for IPv4 or nibble-aligned zones for IPv6.
+// An IPv4 block with a prefix length longer than 24 becomes an RFC 2317 classless zone, see ReverseDNSZone.
+// For the range "192.0.2.0" to "192.0.2.95" the zones are "0/26.2.0.192.in-addr.arpa" and "64/27.2.0.192.in-addr.arpa".
+func (rng *SequentialRange[T]) SplitToReverseZones() []*ReverseDNSZone {
+	ipRange := rng.ToIP()
+	if ipRange == nil || !ipRange.GetLower().IsIPv4() && !ipRange.GetLower().IsIPv6() {
+		return nil
+	}
+	var zones []*ReverseDNSZone
+	for _, block := range ipRange.SpanWithPrefixBlocks() {
+		zones = append(zones, newReverseDNSZones(block)...)
+	}
+	return zones
+}
+
+// ToPTRRecordName returns the name of the PTR record for the given address within the reverse-DNS zones of this range, as returned by SplitToReverseZones.
+// For an address in a classless zone, such as "192.0.2.5" in the zone "0/26.2.0.192.in-addr.arpa", it is "5.0/26.2.0.192.in-addr.arpa",
+// otherwise it is the reverse-DNS string of the address.
+//
+// An error is returned if the given address is not a single address within this range.
+func (rng *SequentialRange[T]) ToPTRRecordName(addr T) (string, addrerr.IncompatibleAddressError) {
+	ipAddr := addr.ToIP()
+	if ipAddr == nil || ipAddr.IsMultiple() || !rng.Contains(ipAddr) {
+		return "", &incompatibleAddressError{addressError{key: "ipaddress.error.address.out.of.range"}}
+	}
+	for _, block := range rng.ToIP().SpanWithPrefixBlocks() {
+		if block.Contains(ipAddr) {
+			if block.IsIPv4() && block.GetMinPrefixLenForBlock() > IPv4BitCount-IPv4BitsPerSegment {
+				return newReverseDNSZones(block)[0].ToPTRRecordName(ipAddr)
+			}
+			break
+		}
+	}
+	return ipAddr.ToReverseDNSString()
+}
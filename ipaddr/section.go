@@ -18,8 +18,10 @@ package ipaddr
 
 import (
 	"fmt"
+	"hash"
 	"math/big"
 	"strconv"
+	"strings"
 	"unsafe"
 
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
@@ -387,11 +389,57 @@ func (section *addressSectionInternal) GetGenericSegment(index int) AddressSegme
 	return section.GetSegment(index)
 }
 
+// CopyGenericSegments copies the existing segments as AddressSegmentType into the given slice,
+// as much as can be fit into the slice, returning the number of segments copied.
+func (section *addressSectionInternal) CopyGenericSegments(segs []AddressSegmentType) (count int) {
+	divArray := section.getDivArray()
+	if count = len(divArray); count > len(segs) {
+		count = len(segs)
+	}
+	for i := 0; i < count; i++ {
+		segs[i] = divArray[i].ToSegmentBase()
+	}
+	return
+}
+
+// GetGenericSegments returns a slice with the segments as AddressSegmentType,
+// allowing all segment types to be represented by a single type.
+// The returned slice is not backed by the same array as this section.
+func (section *addressSectionInternal) GetGenericSegments() (res []AddressSegmentType) {
+	res = make([]AddressSegmentType, section.GetSegmentCount())
+	section.CopyGenericSegments(res)
+	return
+}
+
 // GetSegmentCount returns the segment count.
 func (section *addressSectionInternal) GetSegmentCount() int {
 	return section.GetDivisionCount()
 }
 
+// IsEmptySection returns whether this section has no segments,
+// such as the zero value of a section type or a section constructed from an empty slice of segments.
+//
+// An empty section represents a single value with a bit count of zero.
+// Its count is 1 and it is sequential, so its iterators produce the section itself once,
+// and its spans and merges with prefix or sequential blocks consist of the section itself.
+// It cannot have a prefix length, and it contains the prefix block of its only prefix length, which is zero.
+//
+// An empty section equals, and contains, another section only when the other is an empty section of the same address type.
+// The adaptive zero section, described by IsAdaptiveZero, is considered its own type,
+// so it is not equal to the empty section of any specific address type, such as that constructed by NewIPv4Section(nil).
+func (section *addressSectionInternal) IsEmptySection() bool {
+	return section.GetSegmentCount() == 0
+}
+
+// WriteToHash writes the address type, byte count, and the bytes of the lowest and highest values of this section into the given hash, without allocating.
+// The prefix length is not written.  See HashWriterTo for details.
+func (section *addressSectionInternal) WriteToHash(h hash.Hash) {
+	lower, upper := section.getCachedBytes(section.calcBytes)
+	writeHashHeader(h, section.getAddrType(), len(lower))
+	h.Write(lower)
+	h.Write(upper)
+}
+
 // ForEachSegment visits each segment in order from most-significant to least, the most significant with index 0, calling the given function for each, terminating early if the function returns true.
 // Returns the number of visited segments.
 func (section *addressSectionInternal) ForEachSegment(consumer func(segmentIndex int, segment *AddressSegment) (stop bool)) int {
@@ -406,6 +454,29 @@ func (section *addressSectionInternal) ForEachSegment(consumer func(segmentIndex
 	return len(divArray)
 }
 
+// ForEachSegmentValue visits each segment in order from most-significant to least, the most significant with index 0,
+// calling the given function with the lower and upper values of each, and the segment prefix length for IP segments, terminating early if the function returns true.
+// Unlike ForEachSegment, the segments themselves are not provided, and unlike GetSegments, no allocation is required.
+// Returns the number of visited segments.
+func (section *addressSectionInternal) ForEachSegmentValue(consumer func(segmentIndex int, value, upperValue SegInt, segmentPrefixLen PrefixLen) (stop bool)) int {
+	divArray := section.getDivArray()
+	for i, div := range divArray {
+		if consumer(i, div.getSegmentValue(), div.getUpperSegmentValue(), div.getDivisionPrefixLength()) {
+			return i + 1
+		}
+	}
+	return len(divArray)
+}
+
+// SegmentValuesAppend appends the lower segment values, the segment values of the lowest address in this section, to the given slice, returning the extended slice.
+// When the slice has sufficient capacity, no allocation is required.
+func (section *addressSectionInternal) SegmentValuesAppend(values []SegInt) []SegInt {
+	for _, div := range section.getDivArray() {
+		values = append(values, div.getSegmentValue())
+	}
+	return values
+}
+
 // GetBitCount returns the number of bits in each value comprising this address item.
 func (section *addressSectionInternal) GetBitCount() BitCount {
 	divLen := section.GetDivisionCount()
@@ -1164,17 +1235,45 @@ func (section *addressSectionInternal) contains(other AddressSectionType) bool {
 	return true
 }
 
+// enumerate returns the index of the given individual section within the iteration order of this section,
+// or nil if the given section is not an individual section contained by this one.
+func (section *addressSectionInternal) enumerate(other AddressSectionType) *big.Int {
+	if other == nil {
+		return nil
+	}
+	otherSection := other.ToSectionBase()
+	if otherSection == nil || otherSection.isMultiple() || !section.contains(otherSection) {
+		return nil
+	}
+	index := bigZero()
+	var segCount, segOffset big.Int
+	for i, count := 0, section.GetSegmentCount(); i < count; i++ {
+		seg := section.GetSegment(i)
+		segCount.SetUint64(uint64(seg.GetValueCount()))
+		segOffset.SetUint64(uint64(otherSection.GetSegment(i).GetSegmentValue() - seg.GetSegmentValue()))
+		index.Mul(index, &segCount).Add(index, &segOffset)
+	}
+	return index
+}
+
 func (section *addressSectionInternal) getStringCache() *stringCache {
 	if section.hasNoDivisions() {
 		return &zeroStringCache
 	}
 	cache := section.cache
-	if cache == nil {
+	if cache == nil || cache.stringCachingDisabled {
 		return nil
 	}
 	return &cache.stringCache
 }
 
+// withoutStringCaching returns a copy of this section, sharing the same segments, that does not cache its strings
+func (section *addressSectionInternal) withoutStringCaching() *AddressSection {
+	result := *section.toAddressSection()
+	result.cache = &valueCache{stringCachingDisabled: true}
+	return &result
+}
+
 func (section *addressSectionInternal) getLower() *AddressSection {
 	lower, _ := section.getLowestHighestSections()
 	return lower
@@ -1206,6 +1305,21 @@ func (section *addressSectionInternal) increment(increment int64) *AddressSectio
 	return nil
 }
 
+func (section *addressSectionInternal) incrementBig(increment *big.Int) *AddressSection {
+	if sect := section.toIPv4AddressSection(); sect != nil {
+		return sect.IncrementBig(increment).ToSectionBase()
+	} else if sect := section.toIPv6AddressSection(); sect != nil {
+		return sect.IncrementBig(increment).ToSectionBase()
+	} else if sect := section.toMACAddressSection(); sect != nil {
+		return sect.IncrementBig(increment).ToSectionBase()
+	}
+	return nil
+}
+
+func (section *addressSectionInternal) addToSegment(index int, delta int64) *AddressSection {
+	return addToSegment(section.toAddressSection(), index, delta, section.getPrefixLen())
+}
+
 var (
 	otherOctalPrefix = "0o"
 	otherHexPrefix   = "0X"
@@ -1227,8 +1341,10 @@ var (
 // When called by a function in the fmt package, nil values are detected before this method is called, avoiding a panic when calling this method.
 
 // Format implements [fmt.Formatter] interface. It accepts the formats
-//  - 'v' for the default address and section format (either the normalized or canonical string),
+//  - 'v' for the default address and section format (either the normalized or canonical string, or the format selected with SetDefaultStringFormat),
 //  - 's' (string) for the same,
+//  - 'S' (uppercase string) for the same string with uppercase hexadecimal digits,
+//  - 'r' (reverse DNS) for the reverse-DNS lookup string of IP addresses and sections,
 //  - 'b' (binary), 'o' (octal with 0 prefix), 'O' (octal with 0o prefix),
 //  - 'd' (decimal), 'x' (lowercase hexadecimal), and
 //  - 'X' (uppercase hexadecimal).
@@ -1238,6 +1354,10 @@ var (
 // a leading "0x" or "0X" for "%#x" and "%#X" respectively.
 // Also supported is specification of minimum digits precision, output field width,
 // space or zero padding, and '-' for left or right justification.
+//
+// With 's' and 'S', the '+' flag selects the fully expanded string, with all leading zeros and no compression, as provided by ToFullString,
+// while the '#' flag selects the mixed IPv6/IPv4 string provided by ToMixedString for IPv6.
+// Combining the two, as in "%+#s", produces the mixed string with expanded IPv6 segments.
 func (section addressSectionInternal) Format(state fmt.State, verb rune) {
 	section.format(state, verb, NoZone, false)
 }
@@ -1251,20 +1371,22 @@ func (section *addressSectionInternal) format(state fmt.State, verb rune, zone Z
 	_, hasWidth := state.Width()
 	useDefaultStr := !hasPrecision && !hasWidth
 	switch verb {
-	case 's', 'v', 'q':
+	case 's', 'v', 'q', 'S':
 		isStringFormat = true
-		if useCanonical {
-			if zone != NoZone {
-				str = section.toAddressSection().ToIPv6().toCanonicalString(zone)
-			} else {
-				str = section.toCanonicalString()
+		var expand, mixed bool
+		if verb == 's' || verb == 'S' {
+			expand, mixed = state.Flag('+'), state.Flag('#')
+		}
+		if expand || mixed || verb == 'S' {
+			str = section.toAlternateString(useCanonical, expand, mixed)
+			if verb == 'S' {
+				str = strings.ToUpper(str)
 			}
-		} else {
 			if zone != NoZone {
-				str = section.toAddressSection().ToIPv6().toNormalizedString(zone)
-			} else {
-				str = section.toNormalizedString()
+				str += IPv6ZoneSeparatorStr + string(zone)
 			}
+		} else {
+			str = section.toDefaultFormatString(zone, useCanonical)
 		}
 		if verb == 'q' && useDefaultStr {
 			if state.Flag('#') && (zone == NoZone || strconv.CanBackquote(string(zone))) {
@@ -1275,6 +1397,14 @@ func (section *addressSectionInternal) format(state fmt.State, verb rune, zone Z
 				str = strconv.Quote(str) // zones should not have special characters, but you cannot be sure
 			}
 		}
+	case 'r':
+		sect := section.toIPAddressSection()
+		if sect == nil {
+			_, _ = fmt.Fprintf(state, "%%!%c(address=%s)", verb, section.toString())
+			return
+		}
+		isStringFormat = true
+		str, err = sect.toReverseDNSString()
 	case 'x':
 		useDefaultStr = useDefaultStr && zone == NoZone
 		str, err = section.toHexString(useDefaultStr && state.Flag('#'))
@@ -1324,6 +1454,34 @@ func (section *addressSectionInternal) format(state fmt.State, verb rune, zone Z
 	}
 }
 
+// toAlternateString produces the string, without zone, for the string verbs combined with the '+' flag for the fully expanded string
+// or the '#' flag for the mixed IPv6/IPv4 string.  Flags that do not apply to the address type are ignored.
+func (section *addressSectionInternal) toAlternateString(useCanonical, expand, mixed bool) string {
+	if sect := section.toIPv6AddressSection(); sect != nil {
+		if mixed {
+			var str string
+			var err addrerr.IncompatibleAddressError
+			if expand {
+				str, err = sect.toNormalizedMixedZonedString(mixedFullParams, NoZone)
+			} else {
+				str, err = sect.toMixedString()
+			}
+			if err == nil {
+				return str
+			}
+		}
+		if expand {
+			return sect.ToFullString()
+		}
+	} else if sect := section.toIPv4AddressSection(); sect != nil && expand {
+		return sect.ToFullString()
+	}
+	if useCanonical {
+		return section.toCanonicalString()
+	}
+	return section.toNormalizedString()
+}
+
 func (section addressSectionInternal) writeStrFmt(state fmt.State, verb rune, str string, zone Zone) {
 	if precision, hasPrecision := state.Precision(); hasPrecision && len(str) > precision {
 		str = str[:precision]
@@ -1744,6 +1902,10 @@ func (section *addressSectionInternal) prefixIterator(isBlockIterator bool) Iter
 		iterator)
 }
 
+func (section *addressSectionInternal) prefixBlocksOfLenIterator(prefLen BitCount) Iterator[*AddressSection] {
+	return section.setPrefixLen(checkBitCount(prefLen, section.GetBitCount())).prefixIterator(true)
+}
+
 func (section *addressSectionInternal) blockIterator(segmentCount int) Iterator[*AddressSection] {
 	if segmentCount < 0 {
 		segmentCount = 0
@@ -1954,6 +2116,10 @@ func (section *addressSectionInternal) GetPrefixLen() PrefixLen {
 func (section *addressSectionInternal) ContainsPrefixBlock(prefixLen BitCount) bool {
 	prefixLen = checkSubnet(section, prefixLen)
 	divCount := section.GetSegmentCount()
+	if divCount == 0 {
+		// the adaptive zero section has no bits per segment with which to locate the host segment
+		return true
+	}
 	bitsPerSegment := section.GetBitsPerSegment()
 	i := getHostSegmentIndex(prefixLen, section.GetBytesPerSegment(), bitsPerSegment)
 	if i < divCount {
@@ -1972,6 +2138,36 @@ func (section *addressSectionInternal) ContainsPrefixBlock(prefixLen BitCount) b
 	return true
 }
 
+// ContainsPrefixBlockBySegment reports, for each segment, whether that segment spans the values required for this section to contain the prefix block for the given prefix length.
+//
+// Segments entirely within the network prefix are always true, since they may hold any range of values.
+// The segment containing the prefix boundary is true if it contains the prefix block for the bits of the prefix that fall within it,
+// and segments entirely within the host are true only if they span the full range of segment values.
+//
+// ContainsPrefixBlock returns true for the same prefix length if and only if every element of the returned slice is true,
+// so the false elements identify the segments that prevent this section from being represented as a CIDR prefix block.
+func (section *addressSectionInternal) ContainsPrefixBlockBySegment(prefixLen BitCount) []bool {
+	prefixLen = checkSubnet(section, prefixLen)
+	segCount := section.GetSegmentCount()
+	if segCount == 0 {
+		return []bool{}
+	}
+	bitsPerSegment := section.GetBitsPerSegment()
+	result := make([]bool, segCount)
+	hostIndex := getHostSegmentIndex(prefixLen, section.GetBytesPerSegment(), bitsPerSegment)
+	for i := 0; i < segCount; i++ {
+		if i < hostIndex {
+			result[i] = true
+		} else if i == hostIndex {
+			segmentPrefixLength := getPrefixedSegmentPrefixLength(bitsPerSegment, prefixLen, i)
+			result[i] = section.GetSegment(i).ContainsPrefixBlock(segmentPrefixLength.bitCount())
+		} else {
+			result[i] = section.GetSegment(i).IsFullRange()
+		}
+	}
+	return result
+}
+
 // ContainsSinglePrefixBlock returns whether the values of this grouping contains a single prefix block for the given prefix length.
 //
 // This means there is only one prefix of the given length in this item, and this item contains the prefix block for that given prefix.
@@ -2060,6 +2256,20 @@ func (section *addressSectionInternal) CopyUpperBytes(bytes []byte) []byte {
 	return section.addressDivisionGroupingInternal.CopyUpperBytes(bytes)
 }
 
+// AppendBytes appends the value of the lowest individual address section in the section to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (section *addressSectionInternal) AppendBytes(bytes []byte) []byte {
+	return section.addressDivisionGroupingInternal.AppendBytes(bytes)
+}
+
+// AppendUpperBytes appends the value of the highest individual address section in the section to the given byte slice, returning the extended slice, as with the built-in append.
+//
+// No allocation is made when the capacity of the given slice suffices.
+func (section *addressSectionInternal) AppendUpperBytes(bytes []byte) []byte {
+	return section.addressDivisionGroupingInternal.AppendUpperBytes(bytes)
+}
+
 // IsSequential returns  whether the section represents a range of values that are sequential.
 //
 // Generally, this means that any segment covering a range of values must be followed by segment that are full range, covering all values.
@@ -2142,6 +2352,29 @@ func (section *AddressSection) Contains(other AddressSectionType) bool {
 	return section.contains(other)
 }
 
+// Relation returns the relation of this address section to the given address section,
+// determining whether they are equal, whether one contains the other, whether they overlap, or whether they are adjacent or disjoint.
+//
+// Sections must have the same type, version and number of segments to be comparable, otherwise RelationDisjoint is returned.
+func (section *AddressSection) Relation(other AddressSectionType) Relation {
+	if section == nil {
+		if other == nil || other.ToSectionBase() == nil {
+			return RelationEqual
+		}
+		return RelationDisjoint
+	}
+	return section.relation(other)
+}
+
+// IsContainedBy returns whether this is same type and version as the given address section and whether all its values are contained in the given section.
+// It is the reverse of Contains.
+func (section *AddressSection) IsContainedBy(other AddressSectionType) bool {
+	if other == nil {
+		return section == nil
+	}
+	return other.ToSectionBase().Contains(section)
+}
+
 // Equal returns whether the given address section is equal to this address section.
 // Two address sections are equal if they represent the same set of sections.
 // They must match:
@@ -2556,6 +2789,38 @@ func (section *AddressSection) Increment(increment int64) *AddressSection {
 	return section.increment(increment)
 }
 
+// IncrementBig is the same as Increment, but accepts an increment of any size.
+// This is useful with IPv6, where the count of addresses in a subnet can be much larger than the range of int64.
+//
+// On overflow or underflow, IncrementBig returns nil.
+func (section *AddressSection) IncrementBig(increment *big.Int) *AddressSection {
+	return section.incrementBig(increment)
+}
+
+// Enumerate returns the index of the given individual address section within the iteration order of this address section,
+// which is the increment that, when supplied to IncrementBig, produces the given section from this one.
+// If the given section is not an individual address section contained by this address section, then nil is returned.
+func (section *AddressSection) Enumerate(other AddressSectionType) *big.Int {
+	if section == nil {
+		return nil
+	}
+	return section.enumerate(other)
+}
+
+// AddToSegment adds the given delta to the value of the segment at the given index,
+// carrying into the preceding, more significant, segments whenever a segment value overflows or underflows.
+// This is the same as adding the delta multiplied by the value count of each of the segments following the index.
+// The following segments and the prefix length remain the same.  If the delta is zero, this section is returned.
+//
+// If this represents multiple values, the delta is added to each.
+// Nil is returned if the results cannot be represented as a range of values in each segment,
+// which is the case when some values in a segment range carry into the preceding segment and others do not.
+//
+// On overflow or underflow, or if the index is not the index of a segment of this section, AddToSegment returns nil.
+func (section *AddressSection) AddToSegment(index int, delta int64) *AddressSection {
+	return section.addToSegment(index, delta)
+}
+
 // ReverseBits returns a new section with the bits reversed.  Any prefix length is dropped.
 //
 // If the bits within a single segment cannot be reversed because the segment represents a range,
@@ -2707,6 +2972,19 @@ func (section *AddressSection) GetSegmentStrings() []string {
 	return section.getSegmentStrings()
 }
 
+// GetSegmentStringsRadix returns a slice with the string for each segment, written in the given radix between 2 and 36,
+// with uppercase digits beyond 9 if uppercase is true, and padded with leading zeros to the maximum digit count of the segments if leadingZeros is true.
+// A range of segment values is written with the range separator '-', and the full range of segment values is written with the wildcard '*'.
+// It returns nil if the radix is invalid.
+//
+// For example, with radix 2 and leading zeros, the segments of "1.2.3.4" are "00000001", "00000010", "00000011" and "00000100".
+func (section *AddressSection) GetSegmentStringsRadix(radix int, uppercase, leadingZeros bool) []string {
+	if section == nil {
+		return nil
+	}
+	return section.getSegmentStringsRadix(radix, uppercase, leadingZeros)
+}
+
 func seriesValsSame(one, two AddressSegmentSeries) bool {
 	if one == two {
 		return true
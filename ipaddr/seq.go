@@ -0,0 +1,225 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.23
+
+package ipaddr
+
+import "iter"
+
+// seqOf returns a sequence that iterates with a new iterator each time it is ranged over.
+func seqOf[T any](newIterator func() Iterator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for iterator := newIterator(); iterator.HasNext(); {
+			if !yield(iterator.Next()) {
+				return
+			}
+		}
+	}
+}
+
+// All returns a sequence of the individual addresses of this address or subnet, the same as those provided by Iterator, for use with a for-range loop.
+func (addr *Address) All() iter.Seq[*Address] {
+	return seqOf(addr.Iterator)
+}
+
+// All returns a sequence of the individual addresses of this address or subnet, the same as those provided by Iterator, for use with a for-range loop.
+func (addr *IPAddress) All() iter.Seq[*IPAddress] {
+	return seqOf(addr.Iterator)
+}
+
+// All returns a sequence of the individual addresses of this address or subnet, the same as those provided by Iterator, for use with a for-range loop.
+func (addr *IPv4Address) All() iter.Seq[*IPv4Address] {
+	return seqOf(addr.Iterator)
+}
+
+// All returns a sequence of the individual addresses of this address or subnet, the same as those provided by Iterator, for use with a for-range loop.
+func (addr *IPv6Address) All() iter.Seq[*IPv6Address] {
+	return seqOf(addr.Iterator)
+}
+
+// All returns a sequence of the individual addresses of this address or address collection, the same as those provided by Iterator, for use with a for-range loop.
+func (addr *MACAddress) All() iter.Seq[*MACAddress] {
+	return seqOf(addr.Iterator)
+}
+
+// All returns a sequence of the individual address sections of this address section, the same as those provided by Iterator, for use with a for-range loop.
+func (section *AddressSection) All() iter.Seq[*AddressSection] {
+	return seqOf(section.Iterator)
+}
+
+// All returns a sequence of the individual address sections of this address section, the same as those provided by Iterator, for use with a for-range loop.
+func (section *IPAddressSection) All() iter.Seq[*IPAddressSection] {
+	return seqOf(section.Iterator)
+}
+
+// All returns a sequence of the individual address sections of this address section, the same as those provided by Iterator, for use with a for-range loop.
+func (section *IPv4AddressSection) All() iter.Seq[*IPv4AddressSection] {
+	return seqOf(section.Iterator)
+}
+
+// All returns a sequence of the individual address sections of this address section, the same as those provided by Iterator, for use with a for-range loop.
+func (section *IPv6AddressSection) All() iter.Seq[*IPv6AddressSection] {
+	return seqOf(section.Iterator)
+}
+
+// All returns a sequence of the individual address sections of this address section, the same as those provided by Iterator, for use with a for-range loop.
+func (section *MACAddressSection) All() iter.Seq[*MACAddressSection] {
+	return seqOf(section.Iterator)
+}
+
+// All returns a sequence of the individual segments of this segment, the same as those provided by Iterator, for use with a for-range loop.
+func (seg *AddressSegment) All() iter.Seq[*AddressSegment] {
+	return seqOf(seg.Iterator)
+}
+
+// All returns a sequence of the individual segments of this segment, the same as those provided by Iterator, for use with a for-range loop.
+func (seg *IPAddressSegment) All() iter.Seq[*IPAddressSegment] {
+	return seqOf(seg.Iterator)
+}
+
+// All returns a sequence of the individual segments of this segment, the same as those provided by Iterator, for use with a for-range loop.
+func (seg *IPv4AddressSegment) All() iter.Seq[*IPv4AddressSegment] {
+	return seqOf(seg.Iterator)
+}
+
+// All returns a sequence of the individual segments of this segment, the same as those provided by Iterator, for use with a for-range loop.
+func (seg *IPv6AddressSegment) All() iter.Seq[*IPv6AddressSegment] {
+	return seqOf(seg.Iterator)
+}
+
+// All returns a sequence of the individual segments of this segment, the same as those provided by Iterator, for use with a for-range loop.
+func (seg *MACAddressSegment) All() iter.Seq[*MACAddressSegment] {
+	return seqOf(seg.Iterator)
+}
+
+// All returns a sequence of the individual addresses of this range, the same as those provided by Iterator, for use with a for-range loop.
+func (rng *SequentialRange[T]) All() iter.Seq[T] {
+	return seqOf(rng.Iterator)
+}
+
+// All returns a sequence of the addresses in the vector, in order, for use with a for-range loop.
+func (vec *IPAddressVector) All() iter.Seq[*IPAddress] {
+	return seqOf(vec.Iterator)
+}
+
+// All returns a sequence of the ranges in the tree, in sorted order, for use with a for-range loop.
+func (tree *RangeTree[T]) All() iter.Seq[*SequentialRange[T]] {
+	return seqOf(tree.Iterator)
+}
+
+// All returns a sequence of the ranges of the normalized set, in sorted order, for use with a for-range loop.
+func (set *RangeSet[T]) All() iter.Seq[*SequentialRange[T]] {
+	return seqOf(set.Iterator)
+}
+
+// All returns a sequence of the added addresses and prefix blocks in the trie, in sorted element order, for use with a for-range loop.
+func (trie *Trie[T]) All() iter.Seq[T] {
+	return seqOf(trie.Iterator)
+}
+
+// All returns a sequence of the elements of the view, in sorted element order, for use with a for-range loop.
+func (sub *SubTrie[T]) All() iter.Seq[T] {
+	return seqOf(sub.Iterator)
+}
+
+// Backward returns a sequence of the added addresses and prefix blocks in the trie, in reverse sorted element order, for use with a for-range loop.
+func (trie *Trie[T]) Backward() iter.Seq[T] {
+	return seqOf(trie.DescendingIterator)
+}
+
+// Nodes returns a sequence of the added nodes in the trie, in forward trie order, for use with a for-range loop.
+func (trie *Trie[T]) Nodes() iter.Seq[*TrieNode[T]] {
+	return seqOf(func() Iterator[*TrieNode[T]] { return trie.NodeIterator(true) })
+}
+
+// All returns a sequence of the elements of the sub-trie with this node as the root, in sorted element order, for use with a for-range loop.
+func (node *TrieNode[T]) All() iter.Seq[T] {
+	return seqOf(node.Iterator)
+}
+
+// Backward returns a sequence of the elements of the sub-trie with this node as the root, in reverse sorted element order, for use with a for-range loop.
+func (node *TrieNode[T]) Backward() iter.Seq[T] {
+	return seqOf(node.DescendingIterator)
+}
+
+// Nodes returns a sequence of the added nodes of the sub-trie with this node as the root, in forward trie order, for use with a for-range loop.
+func (node *TrieNode[T]) Nodes() iter.Seq[*TrieNode[T]] {
+	return seqOf(func() Iterator[*TrieNode[T]] { return node.NodeIterator(true) })
+}
+
+// All returns a sequence of the added addresses and prefix blocks in the trie paired with their mapped values, in sorted element order, for use with a for-range loop.
+func (trie *AssociativeTrie[T, V]) All() iter.Seq2[T, V] {
+	return seq2Of(func() Iterator[*AssociativeTrieNode[T, V]] { return trie.NodeIterator(true) })
+}
+
+// Keys returns a sequence of the added addresses and prefix blocks in the trie, in sorted element order, for use with a for-range loop.
+func (trie *AssociativeTrie[T, V]) Keys() iter.Seq[T] {
+	return seqOf(trie.Iterator)
+}
+
+// Values returns a sequence of the values mapped to the added addresses and prefix blocks in the trie, in sorted element order, for use with a for-range loop.
+func (trie *AssociativeTrie[T, V]) Values() iter.Seq[V] {
+	return valueSeqOf(func() Iterator[*AssociativeTrieNode[T, V]] { return trie.NodeIterator(true) })
+}
+
+// Nodes returns a sequence of the added nodes in the trie, in forward trie order, for use with a for-range loop.
+func (trie *AssociativeTrie[T, V]) Nodes() iter.Seq[*AssociativeTrieNode[T, V]] {
+	return seqOf(func() Iterator[*AssociativeTrieNode[T, V]] { return trie.NodeIterator(true) })
+}
+
+// All returns a sequence of the elements of the sub-trie with this node as the root paired with their mapped values, in sorted element order, for use with a for-range loop.
+func (node *AssociativeTrieNode[T, V]) All() iter.Seq2[T, V] {
+	return seq2Of(func() Iterator[*AssociativeTrieNode[T, V]] { return node.NodeIterator(true) })
+}
+
+// Keys returns a sequence of the elements of the sub-trie with this node as the root, in sorted element order, for use with a for-range loop.
+func (node *AssociativeTrieNode[T, V]) Keys() iter.Seq[T] {
+	return seqOf(node.Iterator)
+}
+
+// Values returns a sequence of the values mapped to the elements of the sub-trie with this node as the root, in sorted element order, for use with a for-range loop.
+func (node *AssociativeTrieNode[T, V]) Values() iter.Seq[V] {
+	return valueSeqOf(func() Iterator[*AssociativeTrieNode[T, V]] { return node.NodeIterator(true) })
+}
+
+// Nodes returns a sequence of the added nodes of the sub-trie with this node as the root, in forward trie order, for use with a for-range loop.
+func (node *AssociativeTrieNode[T, V]) Nodes() iter.Seq[*AssociativeTrieNode[T, V]] {
+	return seqOf(func() Iterator[*AssociativeTrieNode[T, V]] { return node.NodeIterator(true) })
+}
+
+// seq2Of returns a sequence of the keys and values of the nodes from a new node iterator each time it is ranged over.
+func seq2Of[T TrieKeyConstraint[T], V any](newIterator func() Iterator[*AssociativeTrieNode[T, V]]) iter.Seq2[T, V] {
+	return func(yield func(T, V) bool) {
+		for iterator := newIterator(); iterator.HasNext(); {
+			node := iterator.Next()
+			if !yield(node.GetKey(), node.GetValue()) {
+				return
+			}
+		}
+	}
+}
+
+// valueSeqOf returns a sequence of the values of the nodes from a new node iterator each time it is ranged over.
+func valueSeqOf[T TrieKeyConstraint[T], V any](newIterator func() Iterator[*AssociativeTrieNode[T, V]]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for iterator := newIterator(); iterator.HasNext(); {
+			if !yield(iterator.Next().GetValue()) {
+				return
+			}
+		}
+	}
+}
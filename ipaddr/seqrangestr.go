@@ -0,0 +1,126 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"strings"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrstrparam"
+)
+
+// NewSeqRangeStringParams constructs a SeqRangeString that will parse the given string,
+// parsing each address according to the given parameters.
+func NewSeqRangeStringParams(str string, params addrstrparam.IPAddressStringParams) *SeqRangeString {
+	var p addrstrparam.IPAddressStringParams
+	if params == nil {
+		p = defaultIPAddrParameters
+	} else {
+		p = addrstrparam.CopyIPAddressStringParams(params)
+	}
+	return parseSeqRangeString(str, p)
+}
+
+// NewSeqRangeString constructs a SeqRangeString.
+func NewSeqRangeString(str string) *SeqRangeString {
+	return parseSeqRangeString(str, defaultIPAddrParameters)
+}
+
+// SeqRangeString parses the string representation of a sequential range of IP addresses,
+// in which the range is written as the lower and upper addresses separated by a dash, such as "1.2.3.4-1.2.5.9" or "1::-2::5".
+// The separator " -> " of the strings produced by IPAddressSeqRange is also accepted, such as "1.2.3.4 -> 1.2.5.9".
+// The upper address can precede the lower.
+//
+// A string that does not have two addresses separated by a dash is parsed as an IPAddressString, and the range is that of ToSequentialRange of IPAddressString.
+// So the range of "1.2.3.0/24" is "1.2.3.0 -> 1.2.3.255", and the range of a single address is just that address.
+// When a string is a valid IPAddressString, such as "1.2.3.4-5", in which the dash denotes a range of segment values, it is parsed as an IPAddressString.
+//
+// Each address is parsed as an IPAddressString, with the parameters supplied to NewSeqRangeStringParams.
+// When either address is a subnet, the range spans from the lowest address of the lower subnet to the highest address of the upper subnet.
+// A string like "*" that denotes addresses of both IP versions is not a valid range.
+type SeqRangeString struct {
+	str string
+	rng *IPAddressSeqRange
+	err addrerr.AddressStringError
+}
+
+func parseSeqRangeString(str string, params addrstrparam.IPAddressStringParams) *SeqRangeString {
+	str = strings.TrimSpace(str)
+	res := &SeqRangeString{str: str}
+	rng, err := parseIPAddressString(str, params).ToSequentialRange()
+	if err == nil {
+		if rng != nil {
+			res.rng = rng
+			return res
+		}
+		// strings like "*" have no range since they span both IP versions
+		err = &addressStringError{addressError{str: str, key: "ipaddress.error.ipVersionIndeterminate"}}
+	}
+	for i := 0; i < len(str); i++ {
+		if str[i] != RangeSeparator {
+			continue
+		}
+		lowerStr, upperStr := str[:i], strings.TrimPrefix(str[i+1:], ">")
+		lowerRng, lowerErr := parseIPAddressString(lowerStr, params).ToSequentialRange()
+		if lowerErr != nil || lowerRng == nil {
+			continue
+		}
+		upperRng, upperErr := parseIPAddressString(upperStr, params).ToSequentialRange()
+		if upperErr != nil || upperRng == nil {
+			continue
+		} else if lowerRng.GetLower().GetIPVersion() != upperRng.GetUpper().GetIPVersion() {
+			err = &addressStringError{addressError{str: str, key: "ipaddress.error.ipVersionMismatch"}}
+			continue
+		}
+		// either range can be the lower, so span from the lowest address of both to the highest
+		res.rng = lowerRng.Extend(upperRng)
+		return res
+	}
+	res.err = err
+	return res
+}
+
+// IsValid returns whether this is a valid sequential range string.
+func (rngStr *SeqRangeString) IsValid() bool {
+	return rngStr.Validate() == nil
+}
+
+// Validate returns nil if this is a valid sequential range string, and otherwise returns an error with a descriptive message indicating why it is not.
+func (rngStr *SeqRangeString) Validate() addrerr.AddressStringError {
+	return rngStr.err
+}
+
+// GetSequentialRange returns the sequential range represented by this string, or nil if this string is not valid.
+//
+// This is similar to ToSequentialRange except that ToSequentialRange provides a descriptive error when nil is returned.
+func (rngStr *SeqRangeString) GetSequentialRange() *IPAddressSeqRange {
+	return rngStr.rng
+}
+
+// ToSequentialRange returns the sequential range represented by this string,
+// or a descriptive error if this string is not valid.
+func (rngStr *SeqRangeString) ToSequentialRange() (*IPAddressSeqRange, addrerr.AddressStringError) {
+	return rngStr.rng, rngStr.err
+}
+
+// String returns the original string used to construct this SeqRangeString, with leading and trailing whitespace removed.
+func (rngStr *SeqRangeString) String() string {
+	if rngStr == nil {
+		return nilString()
+	}
+	return rngStr.str
+}
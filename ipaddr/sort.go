@@ -0,0 +1,96 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import "sort"
+
+// CompareFunc returns a function comparing address items with the given comparator,
+// returning a negative integer, zero, or a positive integer if the first item is less than, equal, or greater than the second.
+// The returned function can be used with slices.SortFunc.
+func CompareFunc[T AddressItem](comp AddressComparator) func(one, two T) int {
+	return func(one, two T) int {
+		return comp.Compare(one, two)
+	}
+}
+
+// LessFunc returns a function for use with sort.Slice and sort.SliceStable, which orders the items of the given slice using the given comparator.
+func LessFunc[T AddressItem](comp AddressComparator, items []T) func(i, j int) bool {
+	return func(i, j int) bool {
+		return comp.Compare(items[i], items[j]) < 0
+	}
+}
+
+// SortableItems adapts a slice of address items to sort.Interface, ordering the items with the given comparator.
+// The zero value of the comparator acts like CountComparator.
+type SortableItems[T AddressItem] struct {
+	Items      []T
+	Comparator AddressComparator
+}
+
+// Len returns the number of items.
+func (items SortableItems[T]) Len() int {
+	return len(items.Items)
+}
+
+// Less returns whether the item at index i is ordered before the item at index j.
+func (items SortableItems[T]) Less(i, j int) bool {
+	return items.Comparator.Compare(items.Items[i], items.Items[j]) < 0
+}
+
+// Swap swaps the items at indices i and j.
+func (items SortableItems[T]) Swap(i, j int) {
+	items.Items[i], items.Items[j] = items.Items[j], items.Items[i]
+}
+
+var _ sort.Interface = SortableItems[*IPAddress]{}
+
+// SortAddresses sorts the given addresses and subnets with CountComparator, the same ordering as the Compare method of the addresses.
+// Nil addresses are ordered first.
+func SortAddresses[T AddressType](addrs []T) {
+	sort.SliceStable(addrs, func(i, j int) bool {
+		return CountComparator.CompareAddresses(addrs[i], addrs[j]) < 0
+	})
+}
+
+// SortRanges sorts the given sequential ranges with LowValueComparator, by lower address and then by upper address.
+// Nil ranges are ordered first.
+func SortRanges[T SequentialRangeConstraint[T]](ranges []*SequentialRange[T]) {
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return LowValueComparator.CompareRanges(ranges[i], ranges[j]) < 0
+	})
+}
+
+// SortByTrieOrder sorts the given individual addresses and CIDR prefix blocks into the order in which they are visited by a trie iterator,
+// the ordering of the TrieCompare method of the addresses.
+// Addresses of differing bit counts, such as IPv4 and IPv6 addresses, cannot be compared with TrieCompare and are instead ordered by bit count.
+// Nil addresses are ordered first.
+//
+// As with TrieCompare, addresses that are neither individual addresses nor prefix blocks are treated like one.
+func SortByTrieOrder[T TrieKeyConstraint[T]](addrs []T) {
+	var zero T
+	sort.SliceStable(addrs, func(i, j int) bool {
+		one, two := addrs[i], addrs[j]
+		if one == zero {
+			return two != zero
+		} else if two == zero {
+			return false
+		} else if oneBits, twoBits := one.GetBitCount(), two.GetBitCount(); oneBits != twoBits {
+			return oneBits < twoBits
+		}
+		return one.trieCompare(two.ToAddressBase()) < 0
+	})
+}
@@ -1177,10 +1177,12 @@ func (writer stringWriter) getRangeString(segmentIndex int, params addressSegmen
 	//check the case where we can use the result of getWildcardString which is cached.
 	//It must have same radix and no chopped digits, and no splitting or reversal of digits.
 	//We can insert leading zeros, string prefix, and a different separator string if necessary.
-	//Also, we cannot in the case of full range (in which case we are only here because we do not want '*')
+	//Also, we cannot in the case of full range (in which case we are only here because we do not want '*'),
+	//nor with uppercase digits, since the cached string is lowercase.
 	if rangeDigitCount == 0 &&
 		radix == writer.getDefaultTextualRadix() &&
 		!splitDigits &&
+		(radix <= 10 || !params.isUppercase()) &&
 		!writer.IsFullRange() {
 		str := writer.GetWildcardString()
 		rangeSep := writer.getDefaultRangeSeparatorString()
@@ -40,6 +40,176 @@ type addressOrderTest struct {
 
 func (t addressOrderTest) run() {
 	t.testOrder()
+
+	t.testSortAddresses([]string{"1::", "1.2.3.4", "", "1.2.0.0/16", "1.2.3.3", "1.2.3.4"}, []string{"", "1.2.3.3", "1.2.3.4", "1.2.3.4", "1.2.0.0/16", "1::"})
+	t.testSortAddresses([]string{"1.2.3.4-5", "1.2.3.3", "::", "1.2.3.5-6"}, []string{"1.2.3.3", "1.2.3.4-5", "1.2.3.5-6", "::"})
+	t.testSortAddresses(nil, nil)
+	t.testSortByTrieOrder([]string{"1.2.3.4", "1.2.0.0/16", "1.2.3.0/24", "1.2.128.0/17", "0.0.0.0/0", "1.2.3.255", "1.2.3.128/25", "255.0.0.1", "1.2.3.4"})
+	t.testSortByTrieOrder([]string{"1::/64", "1::1", "::", "8000::/1", "1:0:0:1::", "ffff::"})
+	t.testSortByTrieOrderMixed([]string{"::1", "1.2.3.4", "", "1.2.0.0/16", "::/64"}, []string{"", "1.2.3.4", "1.2.0.0/16", "::1", "::/64"})
+	t.testSortRanges([][2]string{{"1.2.3.4", "1.2.3.10"}, {"1.2.3.4", "1.2.3.5"}, {"::", "::1"}, {"1.2.3.1", "1.2.3.255"}, {"", ""}},
+		[][2]string{{"", ""}, {"1.2.3.1", "1.2.3.255"}, {"1.2.3.4", "1.2.3.5"}, {"1.2.3.4", "1.2.3.10"}, {"::", "::1"}})
+	t.testReverseComparator(ipaddr.LowValueComparator, []string{"1.2.3.4-10", "1.2.3.4-5", "1.2.3.1-20", "1.2.3.6"}, []string{"1.2.3.6", "1.2.3.4-10", "1.2.3.4-5", "1.2.3.1-20"})
+	t.testReverseComparator(ipaddr.HighValueComparator, []string{"1.2.3.4-10", "1.2.3.4-5", "1.2.3.1-20", "1.2.3.6"}, []string{"1.2.3.1-20", "1.2.3.4-10", "1.2.3.6", "1.2.3.4-5"})
+	t.testReverseComparator(ipaddr.CountComparator, []string{"1::", "1.2.3.4", "", "1.2.0.0/16", "1.2.3.3"}, []string{"1::", "1.2.0.0/16", "1.2.3.4", "1.2.3.3", ""})
+	t.testReverseComparator(ipaddr.AddressComparator{}, []string{"1.2.3.3", "1.2.3.4"}, []string{"1.2.3.4", "1.2.3.3"})
+	t.testReverseComparator(ipaddr.ReverseLowValueComparator, []string{"1.2.3.4-10", "1.2.3.4-5", "1.2.3.1-20", "1.2.3.6"}, []string{"1.2.3.6", "1.2.3.4-5", "1.2.3.4-10", "1.2.3.1-20"})
+	t.testReverseComparator(ipaddr.ReverseHighValueComparator, []string{"1.2.3.4-10", "1.2.3.4-5", "1.2.3.1-20", "1.2.3.6"}, []string{"1.2.3.1-20", "1.2.3.4-10", "1.2.3.6", "1.2.3.4-5"})
+}
+
+func (t addressOrderTest) testReverseComparator(comp ipaddr.AddressComparator, strs, expected []string) {
+	reversed := comp.Reverse()
+	if comp.IsReversed() || !reversed.IsReversed() || reversed.Reverse() != comp {
+		t.addFailure(newFailure("unexpected comparator reversal", nil))
+	}
+	addrs := createOrderAddresses(strs)
+	sort.SliceStable(addrs, ipaddr.LessFunc(reversed, addrs))
+	t.checkSorted("reversed comparator", addrs, expected)
+
+	forward := createOrderAddresses(strs)
+	sort.SliceStable(forward, ipaddr.LessFunc(comp, forward))
+	for i, addr := range forward {
+		if other := addrs[len(addrs)-1-i]; comp.Compare(addr, other) != 0 {
+			t.addFailure(newFailure(fmt.Sprintf("reversed order %v is not the reverse of %v", addrs, forward), nil))
+			break
+		}
+	}
+	for i := 1; i < len(addrs); i++ {
+		one, two := addrs[i-1], addrs[i]
+		if result := reversed.Compare(one, two); result > 0 || result != comp.Compare(two, one) {
+			t.addFailure(newFailure(fmt.Sprintf("reversed comparison of %v and %v was %d", one, two, result), nil))
+		} else if one == nil || two == nil {
+			continue
+		} else if result = reversed.CompareAddresses(one, two); result != comp.CompareAddresses(two, one) {
+			t.addFailure(newFailure(fmt.Sprintf("reversed address comparison of %v and %v was %d", one, two, result), nil))
+		} else if result = reversed.CompareAddressSections(one.GetSection(), two.GetSection()); result != comp.CompareAddressSections(two.GetSection(), one.GetSection()) {
+			t.addFailure(newFailure(fmt.Sprintf("reversed section comparison of %v and %v was %d", one, two, result), nil))
+		} else if result = reversed.CompareSeries(one, two); result != comp.CompareSeries(two, one) {
+			t.addFailure(newFailure(fmt.Sprintf("reversed series comparison of %v and %v was %d", one, two, result), nil))
+		} else if result = reversed.CompareSegments(one.GetSegment(0), two.GetSegment(0)); result != comp.CompareSegments(two.GetSegment(0), one.GetSegment(0)) {
+			t.addFailure(newFailure(fmt.Sprintf("reversed segment comparison of %v and %v was %d", one, two, result), nil))
+		} else if result = reversed.CompareDivisions(one.GetSegment(0), two.GetSegment(0)); result != comp.CompareDivisions(two.GetSegment(0), one.GetSegment(0)) {
+			t.addFailure(newFailure(fmt.Sprintf("reversed division comparison of %v and %v was %d", one, two, result), nil))
+		} else if oneRng, twoRng := one.ToSequentialRange(), two.ToSequentialRange(); reversed.CompareRanges(oneRng, twoRng) != comp.CompareRanges(twoRng, oneRng) {
+			t.addFailure(newFailure(fmt.Sprintf("reversed range comparison of %v and %v was %d", oneRng, twoRng, reversed.CompareRanges(oneRng, twoRng)), nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func createOrderAddresses(strs []string) []*ipaddr.IPAddress {
+	addrs := make([]*ipaddr.IPAddress, len(strs))
+	for i, str := range strs {
+		if str != "" {
+			addrs[i] = ipaddr.NewIPAddressString(str).GetAddress()
+		}
+	}
+	return addrs
+}
+
+func (t addressOrderTest) checkSorted(description string, addrs []*ipaddr.IPAddress, expected []string) {
+	expectedAddrs := createOrderAddresses(expected)
+	matches := len(addrs) == len(expectedAddrs)
+	for i := 0; matches && i < len(addrs); i++ {
+		if addrs[i] == nil || expectedAddrs[i] == nil {
+			matches = addrs[i] == expectedAddrs[i]
+		} else {
+			matches = addrs[i].Equal(expectedAddrs[i]) && addrs[i].GetPrefixLen().Equal(expectedAddrs[i].GetPrefixLen())
+		}
+	}
+	if !matches {
+		t.addFailure(newFailure(fmt.Sprintf("%s sorted to %v, expected %v", description, addrs, expectedAddrs), nil))
+	}
+}
+
+func (t addressOrderTest) testSortAddresses(strs, expected []string) {
+	addrs := createOrderAddresses(strs)
+	ipaddr.SortAddresses(addrs)
+	t.checkSorted("SortAddresses", addrs, expected)
+
+	addrs = createOrderAddresses(strs)
+	sort.Sort(ipaddr.SortableItems[*ipaddr.IPAddress]{Items: addrs, Comparator: ipaddr.CountComparator})
+	t.checkSorted("sort.Interface", addrs, expected)
+
+	addrs = createOrderAddresses(strs)
+	sort.SliceStable(addrs, ipaddr.LessFunc(ipaddr.CountComparator, addrs))
+	t.checkSorted("less func", addrs, expected)
+
+	addrs = createOrderAddresses(strs)
+	compare := ipaddr.CompareFunc[*ipaddr.IPAddress](ipaddr.CountComparator)
+	sort.SliceStable(addrs, func(i, j int) bool {
+		return compare(addrs[i], addrs[j]) < 0
+	})
+	t.checkSorted("compare func", addrs, expected)
+
+	ipv4Addrs := make([]*ipaddr.IPv4Address, 0, len(strs))
+	for _, addr := range createOrderAddresses(strs) {
+		if addr.IsIPv4() {
+			ipv4Addrs = append(ipv4Addrs, addr.ToIPv4())
+		}
+	}
+	ipaddr.SortAddresses(ipv4Addrs)
+	for i := 1; i < len(ipv4Addrs); i++ {
+		if ipv4Addrs[i-1].Compare(ipv4Addrs[i]) > 0 {
+			t.addFailure(newFailure(fmt.Sprintf("IPv4 addresses sorted to %v", ipv4Addrs), nil))
+			break
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t addressOrderTest) testSortByTrieOrder(strs []string) {
+	addrs := createOrderAddresses(strs)
+	trie := ipaddr.Trie[*ipaddr.IPAddress]{}
+	for _, addr := range addrs {
+		trie.Add(addr)
+	}
+	expected := make([]string, 0, len(addrs))
+	for iterator := trie.Iterator(); iterator.HasNext(); {
+		expected = append(expected, iterator.Next().String())
+	}
+	ipaddr.SortByTrieOrder(addrs)
+	unique := addrs[:0]
+	for i, addr := range addrs {
+		if i == 0 || !addr.Equal(addrs[i-1]) {
+			unique = append(unique, addr)
+		}
+	}
+	t.checkSorted("trie order", unique, expected)
+	t.incrementTestCount()
+}
+
+func (t addressOrderTest) testSortByTrieOrderMixed(strs, expected []string) {
+	addrs := createOrderAddresses(strs)
+	ipaddr.SortByTrieOrder(addrs)
+	t.checkSorted("trie order", addrs, expected)
+	t.incrementTestCount()
+}
+
+func (t addressOrderTest) testSortRanges(strs, expected [][2]string) {
+	createRanges := func(strs [][2]string) []*ipaddr.IPAddressSeqRange {
+		ranges := make([]*ipaddr.IPAddressSeqRange, len(strs))
+		for i, str := range strs {
+			if str[0] != "" {
+				ranges[i] = ipaddr.NewIPAddressString(str[0]).GetAddress().SpanWithRange(ipaddr.NewIPAddressString(str[1]).GetAddress())
+			}
+		}
+		return ranges
+	}
+	ranges, expectedRanges := createRanges(strs), createRanges(expected)
+	ipaddr.SortRanges(ranges)
+	for i, rng := range ranges {
+		if rng == nil || expectedRanges[i] == nil {
+			if rng != expectedRanges[i] {
+				t.addFailure(newFailure(fmt.Sprintf("ranges sorted to %v, expected %v", ranges, expectedRanges), nil))
+				break
+			}
+		} else if !rng.Equal(expectedRanges[i]) {
+			t.addFailure(newFailure(fmt.Sprintf("ranges sorted to %v, expected %v", ranges, expectedRanges), nil))
+			break
+		}
+	}
+	t.incrementTestCount()
 }
 
 func (t addressOrderTest) testOrder() {
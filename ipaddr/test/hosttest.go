@@ -17,8 +17,10 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -475,6 +477,62 @@ func (t hostTester) run() {
 	t.testHostAddressPortZone("255.22.2.111.in-addr.arpa", "111.2.22.255", nil, "")
 	t.testHostAddressPortZone("255.22.2.111.in-addr.arpa:35", "111.2.22.255", port35, "")
 	t.testHostPortZone("255.22.2.111.3.in-addr.arpa:35", "255.22.2.111.3.in-addr.arpa", port35, "")
+
+	t.testReverseDNSZones("1.2.16.0/20", "16.2.1.in-addr.arpa", "31.2.1.in-addr.arpa", 16)
+	t.testReverseDNSZones("1.2.3.0/24", "3.2.1.in-addr.arpa", "3.2.1.in-addr.arpa", 1)
+	t.testReverseDNSZones("1.2.3.4/24", "3.2.1.in-addr.arpa", "3.2.1.in-addr.arpa", 1)
+	t.testReverseDNSZones("1.2.2.0/23", "2.2.1.in-addr.arpa", "3.2.1.in-addr.arpa", 2)
+	t.testReverseDNSZones("1.2.3.4", "4.3.2.1.in-addr.arpa", "4.3.2.1.in-addr.arpa", 1)
+	t.testReverseDNSZones("0.0.0.0/0", "in-addr.arpa", "in-addr.arpa", 1)
+	t.testReverseDNSZones("2001:db8::/30", "8.b.d.0.1.0.0.2.ip6.arpa", "b.b.d.0.1.0.0.2.ip6.arpa", 4)
+	t.testReverseDNSZones("2001:db8::/32", "8.b.d.0.1.0.0.2.ip6.arpa", "8.b.d.0.1.0.0.2.ip6.arpa", 1)
+	t.testReverseDNSZones("2001:db8:a0::/44", "a.0.0.8.b.d.0.1.0.0.2.ip6.arpa", "a.0.0.8.b.d.0.1.0.0.2.ip6.arpa", 1)
+	t.testReverseDNSZones("::/0", "ip6.arpa", "ip6.arpa", 1)
+
+	t.testParseReverseDNS("2.1.in-addr.arpa", "1.2.0.0/16")
+	t.testParseReverseDNS("2.1.IN-ADDR.ARPA.", "1.2.0.0/16")
+	t.testParseReverseDNS("in-addr.arpa", "0.0.0.0/0")
+	t.testParseReverseDNS("255.22.2.111.in-addr.arpa", "111.2.22.255")
+	t.testParseReverseDNS("8.b.d.0.1.0.0.2.ip6.arpa", "2001:db8::/32")
+	t.testParseReverseDNS("a.0.0.8.b.d.0.1.0.0.2.ip6.int", "2001:db8:a0::/44")
+	t.testParseReverseDNS("b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", "2001:db8::567:89ab")
+	t.testParseReverseDNS("255.22.2.111.3.in-addr.arpa", "")
+	t.testParseReverseDNS("2..1.in-addr.arpa", "")
+	t.testParseReverseDNS("a.1.in-addr.arpa", "")
+	t.testParseReverseDNS("2.1.in-addr.arpa:35", "")
+	t.testParseReverseDNS("2.1.example.com", "")
+
+	for _, str := range []string{"www.example.com", "1.2.3.4", "[1::2]:80", "1::2", "a_b.com", "-a.com", "", "a..b", "1.2.3.4/16", "example.com:http", "a.b.c.d.e.f.g.h.i"} {
+		t.testHostValidationOnly(str)
+	}
+
+	t.testAuthority("user:pw@[fe80::1%25eth0]:8080", "[fe80::1%25eth0]:8080", "fe80::1%eth0")
+	t.testAuthority("[fe80::1%25en%2F0]", "[fe80::1%25en%2F0]", "fe80::1%en/0")
+	t.testAuthority("[2001:db8:0::1]:443", "[2001:db8::1]:443", "2001:db8::1")
+	t.testAuthority("user@1.2.3.4:80", "1.2.3.4:80", "1.2.3.4")
+	t.testAuthority("Example.COM:8080", "example.com:8080", "")
+	t.testAuthority("a@b@example.com", "example.com", "")
+	t.testAuthority("example.com/path", "", "")
+	t.testAuthority("example.com:http", "", "")
+	t.testAuthority("1.2.3.4/24", "", "")
+	t.testAuthority("fe80::1", "", "")
+	t.testAuthority("[fe80::1%eth0]", "", "")
+	t.testAuthority("[fe80::1%2eth0]", "", "")
+	t.testAuthority("[fe80::*%25eth0]", "", "")
+	t.testAuthority("1.2.*.4:80", "", "")
+	t.testAuthority("*", "", "")
+	t.testAuthority("example.com:", "example.com", "")
+	t.testAuthority("1.2.3.4:", "1.2.3.4", "1.2.3.4")
+	t.testAuthority("[fe80::1%25eth0]:", "[fe80::1%25eth0]", "fe80::1%eth0")
+	t.testAuthority("[::]:", "[::]", "::")
+	t.testAuthority("::", "", "")
+	t.testAuthority("example.com::", "", "")
+	t.testURLHostSubnet("[fe80::*%eth0]:8080")
+	t.testURLHostSubnet("1.2.*.4")
+	t.testURLHostSubnet("*")
+	t.testHostFromURL("http://user@[fe80::1%25eth0]:8080/path?q", "[fe80::1%25eth0]:8080")
+	t.testHostFromURL("https://1.2.3.4/", "1.2.3.4")
+	t.testHostFromURL("https://www.example.com:8443/", "www.example.com:8443")
 	t.testHostAddressPortZone("1.2.2.1:33", "1.2.2.1", port33, "")
 	t.testHostAddressPortZone("[::1]:33", "::1", port33, "")
 	t.testHostAddressPortZone("::1:33", "::1:33", nil, "")
@@ -618,6 +676,223 @@ func (t hostTester) run() {
 	}, nil)
 	t.testHostInetSocketAddressSA("1.2.3.4:http", nil, nil)
 
+	t.testHostPortString("1.2.3.4", 80, "1.2.3.4:80")
+	t.testHostPortString("1.2.3.4/16", 443, "1.2.3.4:443")
+	t.testHostPortString("2001:db8::1", 80, "[2001:db8::1]:80")
+	t.testHostPortString("fe80::1%eth0", 8080, "[fe80::1%eth0]:8080")
+	t.testHostPortString("::1", 1, "[::1]:1")
+	t.testHostPortString("1.2.*.*", 80, "")
+	t.testHostPortString("1.2.3.4", 0, "")
+	t.testHostPortString("1::/64", 80, "")
+	t.testZeroHostPortString()
+	t.testSplitHostPortInvalid("1.2.3.4:0")
+	t.testSplitHostPortInvalid("1.2.*.*:80")
+	t.testSplitHostPortInvalid(":80")
+	t.testSplitHostPortInvalid("::1:80")
+	t.testSplitHostPortInvalid("1.2.3.4")
+	t.testSplitHostPortInvalid("a.com:80")
+	t.testSplitHostPortInvalid("[::1]:http")
+
+	t.testResolveWithResolver("a.test", addrstrparam.IPv6, []string{"1.2.3.4", "1::2", "1.2.3.1", "1::1"}, []string{"1::2", "1::1", "1.2.3.4", "1.2.3.1"})
+	t.testResolveWithResolver("a.test", addrstrparam.IPv4, []string{"1::2", "1.2.3.4", "1::1", "1.2.3.1"}, []string{"1.2.3.4", "1.2.3.1", "1::2", "1::1"})
+	t.testResolveWithResolver("a.test/24", addrstrparam.IPv4, []string{"1.2.3.4"}, []string{"1.2.3.4/24"})
+	t.testResolveWithResolver("a.test/255.255.0.0", addrstrparam.IPv4, []string{"1.2.3.4"}, []string{"1.2.0.0/16"})
+	t.testResolveAll("a.test", addrstrparam.IPv6, []string{"1.2.3.4", "1::2", "1.2.3.1", "1::1", "1::2", "1.2.3.4"}, []string{"1::1", "1::2", "1.2.3.1", "1.2.3.4"})
+	t.testResolveAll("a.test", addrstrparam.IPv4, []string{"1.2.3.4", "1::2", "1.2.3.1", "1::1"}, []string{"1.2.3.1", "1.2.3.4", "1::1", "1::2"})
+	t.testResolveAll("a.test", addrstrparam.IPv4, nil, nil)
+	t.testResolveAll("1.2.3.4", addrstrparam.IPv4, []string{"5.6.7.8"}, []string{"1.2.3.4"})
+	t.testResolveAll("[1::1]", addrstrparam.IPv4, []string{"5.6.7.8"}, []string{"1::1"})
+	t.testResolveCancelled("a.test")
+	t.testResolveCancelled("1.2.3.4")
+}
+
+// testResolver resolves every host name to the same addresses
+type testResolver struct {
+	addrs []net.IPAddr
+	count *int32
+}
+
+func (resolver testResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	atomic.AddInt32(resolver.count, 1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	result := make([]net.IPAddr, len(resolver.addrs))
+	for i, addr := range resolver.addrs {
+		result[i] = net.IPAddr{IP: append(net.IP(nil), addr.IP...)}
+	}
+	return result, nil
+}
+
+func newTestResolver(addrStrs []string) testResolver {
+	addrs := make([]net.IPAddr, len(addrStrs))
+	for i, str := range addrStrs {
+		addrs[i] = net.IPAddr{IP: net.ParseIP(str)}
+	}
+	return testResolver{addrs: addrs, count: new(int32)}
+}
+
+func (t hostTester) createPreferredHost(hostStr string, version addrstrparam.IPVersion) *ipaddr.HostName {
+	params := new(addrstrparam.HostNameParamsBuilder).Set(hostOptions).SetPreferredVersion(version).ToParams()
+	return ipaddr.NewHostNameParams(hostStr, params)
+}
+
+func (t hostTester) checkResolved(host *ipaddr.HostName, addrs []*ipaddr.IPAddress, expected []string) {
+	if len(addrs) != len(expected) {
+		t.addFailure(newHostFailure(fmt.Sprintf("resolved to %v, expected %v", addrs, expected), host))
+		return
+	}
+	for i, addr := range addrs {
+		expectedAddr := ipaddr.NewIPAddressString(expected[i]).GetAddress()
+		if !addr.Equal(expectedAddr) || !addr.GetPrefixLen().Equal(expectedAddr.GetPrefixLen()) {
+			t.addFailure(newHostFailure(fmt.Sprintf("resolved to %v, expected %v", addrs, expected), host))
+			return
+		}
+	}
+}
+
+func (t hostTester) testResolveWithResolver(hostStr string, version addrstrparam.IPVersion, resolved, expected []string) {
+	host := t.createPreferredHost(hostStr, version)
+	resolver := newTestResolver(resolved)
+	addrs, err := host.ResolveWithResolver(context.Background(), resolver)
+	if err != nil {
+		t.addFailure(newHostFailure("unexpected error "+err.Error(), host))
+	} else {
+		t.checkResolved(host, addrs, expected)
+		// results from a custom resolver are not cached
+		if _, err = host.ResolveWithResolver(context.Background(), resolver); err != nil {
+			t.addFailure(newHostFailure("unexpected error "+err.Error(), host))
+		} else if count := atomic.LoadInt32(resolver.count); count != 2 {
+			t.addFailure(newHostFailure("resolver called "+strconv.Itoa(int(count))+" times, expected 2", host))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testResolveAll(hostStr string, version addrstrparam.IPVersion, resolved, expected []string) {
+	host := t.createPreferredHost(hostStr, version)
+	resolver := newTestResolver(resolved)
+	addrs, err := host.ResolveAll(context.Background(), resolver)
+	if err != nil {
+		t.addFailure(newHostFailure("unexpected error "+err.Error(), host))
+	} else {
+		t.checkResolved(host, addrs, expected)
+		if host.IsAddressString() {
+			if count := atomic.LoadInt32(resolver.count); count != 0 {
+				t.addFailure(newHostFailure("resolver called for address string", host))
+			}
+			// address strings need no lookup, so the default resolver is never used
+			if addrs, err = host.ResolveAll(context.Background(), nil); err != nil {
+				t.addFailure(newHostFailure("unexpected error "+err.Error(), host))
+			} else {
+				t.checkResolved(host, addrs, expected)
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testResolveCancelled(hostStr string) {
+	host := t.createHost(hostStr)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resolver := newTestResolver([]string{"1.2.3.4"})
+	addrs, err := host.ResolveWithResolver(ctx, resolver)
+	if host.IsAddressString() {
+		// no lookup is required, so the context is not consulted
+		if err != nil {
+			t.addFailure(newHostFailure("unexpected error "+err.Error(), host))
+		} else {
+			t.checkResolved(host, addrs, []string{hostStr})
+		}
+	} else if err == nil {
+		t.addFailure(newHostFailure(fmt.Sprintf("resolved to %v with cancelled context", addrs), host))
+	} else if addrs, err = host.ResolveWithContext(ctx); err == nil {
+		t.addFailure(newHostFailure(fmt.Sprintf("resolved to %v with cancelled context", addrs), host))
+	}
+	t.incrementTestCount()
+}
+
+// testHostPortString checks the socket address string and its round trip through SplitHostPort, or an error when expected is empty
+func (t hostTester) testHostPortString(addrStr string, port uint16, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	str, err := addr.ToHostPortString(port)
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("expected error for host port string, got "+str, addr))
+		}
+	} else if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected host port string error "+err.Error(), addr))
+	} else if str != expected {
+		t.addFailure(newIPAddrFailure("host port string "+str+" does not match expected "+expected, addr))
+	} else if parsedAddr, parsedPort, err := ipaddr.SplitHostPort(str); err != nil {
+		t.addFailure(newIPAddrFailure("failed to split "+str+": "+err.Error(), addr))
+	} else if !parsedAddr.Equal(addr.WithoutPrefixLen()) || !parsedPort.Matches(ipaddr.PortInt(port)) {
+		t.addFailure(newIPAddrFailure("split "+str+" into "+parsedAddr.String()+" and port "+parsedPort.String(), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testZeroHostPortString() {
+	var zero ipaddr.IPAddress
+	if str, err := zero.ToHostPortString(80); err == nil {
+		t.addFailure(newIPAddrFailure("expected error for zero address host port string, got "+str, &zero))
+	}
+	var zeroIPv4 ipaddr.IPv4Address
+	if str, err := zeroIPv4.ToHostPortString(80); err != nil || str != "0.0.0.0:80" {
+		t.addFailure(newIPAddrFailure("zero IPv4 address host port string was "+str, zeroIPv4.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testSplitHostPortInvalid(str string) {
+	if addr, port, err := ipaddr.SplitHostPort(str); err == nil {
+		t.addFailure(newHostFailure("split invalid socket address into "+addr.String()+" and port "+port.String(), t.createHost(str)))
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testAuthority(authority, expectedURLHost, expectedAddr string) {
+	host, err := ipaddr.ParseAuthority(authority)
+	if expectedURLHost == "" {
+		if err == nil {
+			t.addFailure(newHostFailure("unexpectedly parsed authority "+authority, host))
+		}
+	} else if err != nil {
+		t.addFailure(newHostFailure("failed to parse authority "+authority+": "+err.Error(), t.createHost(authority)))
+	} else if str, err := host.ToURLHostString(); err != nil {
+		t.addFailure(newHostFailure("unexpected error "+err.Error(), host))
+	} else if str != expectedURLHost {
+		t.addFailure(newHostFailure("URL host string "+str+" does not match expected "+expectedURLHost, host))
+	} else if expectedAddr != "" && (!host.IsAddress() || host.AsAddress().String() != expectedAddr) {
+		t.addFailure(newHostFailure("authority address "+host.AsAddress().String()+" does not match expected "+expectedAddr, host))
+	} else if reparsed, err := ipaddr.ParseAuthority(str); err != nil || !reparsed.Equal(host) {
+		t.addFailure(newHostFailure("URL host string "+str+" did not parse to the same host", host))
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testURLHostSubnet(hostStr string) {
+	host := ipaddr.NewHostName(hostStr)
+	if str, err := host.ToURLHostString(); err == nil {
+		t.addFailure(newHostFailure("unexpected URL host string "+str+" for subnet", host))
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testHostFromURL(urlStr, expectedURLHost string) {
+	u, _ := url.Parse(urlStr)
+	host, err := ipaddr.NewHostNameFromURL(u)
+	if err != nil {
+		t.addFailure(newHostFailure("failed to parse URL "+urlStr+": "+err.Error(), t.createHost(u.Host)))
+	} else if str, err := host.ToURLHostString(); err != nil {
+		t.addFailure(newHostFailure("unexpected error "+err.Error(), host))
+	} else if str != expectedURLHost {
+		t.addFailure(newHostFailure("URL host string "+str+" does not match expected "+expectedURLHost, host))
+	} else if !strings.Contains(urlStr, "@"+str) && !strings.Contains(urlStr, "//"+str) {
+		t.addFailure(newHostFailure("URL host string "+str+" not found in "+urlStr, host))
+	}
+	t.incrementTestCount()
 }
 
 func (t hostTester) testSelf(host string, isSelf bool) {
@@ -900,6 +1175,53 @@ func (t hostTester) testHostWithService(host, hostExpected, serviceExpected stri
 	t.testHostPortServZonePref(host, hostExpected, "", nil, serviceExpected, expectedZone, nil)
 }
 
+func (t hostTester) testReverseDNSZones(addrStr, expectedFirst, expectedLast string, expectedCount int) {
+	addr := t.createAddress(addrStr).GetAddress()
+	zones := addr.ToReverseDNSZoneStrings()
+	if len(zones) != expectedCount {
+		t.addFailure(newIPAddrFailure("zone count "+strconv.Itoa(len(zones))+" expected "+strconv.Itoa(expectedCount), addr))
+	} else if zones[0] != expectedFirst || zones[len(zones)-1] != expectedLast {
+		t.addFailure(newIPAddrFailure("zones "+zones[0]+" to "+zones[len(zones)-1]+" expected "+expectedFirst+" to "+expectedLast, addr))
+	} else {
+		for _, zone := range zones {
+			parsed, err := ipaddr.ParseReverseDNSName(zone)
+			if err != nil {
+				t.addFailure(newIPAddrFailure("unexpected error parsing zone "+zone+": "+err.Error(), addr))
+			} else if !addr.ToPrefixBlock().Contains(parsed) {
+				t.addFailure(newIPAddrFailure("zone "+zone+" parsed to "+parsed.String()+" not in subnet", addr))
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testHostValidationOnly(str string) {
+	for _, params := range []addrstrparam.HostNameParams{nil, hostOnlyOptions} {
+		host := ipaddr.NewHostNameParams(str, params)
+		if result := ipaddr.IsValidHostName(str, params); result != host.IsValid() {
+			t.addFailure(newHostFailure(fmt.Sprint("host validation of ", str, " was ", result), host))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t hostTester) testParseReverseDNS(name, expected string) {
+	parsed, err := ipaddr.ParseReverseDNSName(name)
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newHostFailure("reverse DNS name parsed to "+parsed.String()+" but expected failure", t.createHost(name)))
+		}
+	} else if err != nil {
+		t.addFailure(newHostFailure("unexpected error "+err.Error(), t.createHost(name)))
+	} else {
+		expectedAddr := t.createAddress(expected).GetAddress()
+		if !parsed.Equal(expectedAddr) || !parsed.GetPrefixLen().Equal(expectedAddr.GetPrefixLen()) {
+			t.addFailure(newHostFailure("reverse DNS name parsed to "+parsed.String()+" expected "+expected, t.createHost(name)))
+		}
+	}
+	t.incrementTestCount()
+}
+
 func (t hostTester) testHostAddressPortZone(host, hostExpected string, portExpected ipaddr.Port, expectedZone ipaddr.Zone) {
 	t.testHostAddress(host, hostExpected, hostExpected, portExpected, expectedZone)
 }
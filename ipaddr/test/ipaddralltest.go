@@ -304,6 +304,8 @@ func (t ipAddressAllTester) run() {
 	t.testMaskedRange(0x40100000000, 0x5ffffffffff, 0x1ffffffffff, true, 0x100000000, 0x1ffffffffff)
 	t.testMaskedRange(0x400ffffffff, 0x5ffffffffff, 0x1ffffffffff, true, 0xffffffff, 0x1ffffffffff)
 
+	t.testRangeMaskingBits(5)
+
 	t.testMaskedRangeExtended(
 		1, 0xcafe, // lower
 		1, 0xbadcafe, // upper
@@ -434,6 +436,59 @@ func (t ipAddressAllTester) testBackAndForthIPv6(addrStr string) {
 	}
 }
 
+// tests MaskRange and BitwiseOrRange against the results of masking and oring every value in the range, for every range and mask of the given bit count
+func (t ipAddressAllTester) testRangeMaskingBits(bitCount uint) {
+	maxValue := ^(^uint64(0) << bitCount)
+	for value := uint64(0); value <= maxValue; value++ {
+		for upperValue := value; upperValue <= maxValue; upperValue++ {
+			for maskValue := uint64(0); maskValue <= maxValue; maskValue++ {
+				var masked, ored [64]bool
+				maskedLower, maskedUpper, oredLower, oredUpper := maxValue, uint64(0), maxValue, uint64(0)
+				for val := value; val <= upperValue; val++ {
+					m, o := val&maskValue, val|maskValue
+					masked[m], ored[o] = true, true
+					if m < maskedLower {
+						maskedLower = m
+					}
+					if m > maskedUpper {
+						maskedUpper = m
+					}
+					if o < oredLower {
+						oredLower = o
+					}
+					if o > oredUpper {
+						oredUpper = o
+					}
+				}
+				isSequential := func(vals *[64]bool, lower, upper uint64) bool {
+					for val := lower; val <= upper; val++ {
+						if !vals[val] {
+							return false
+						}
+					}
+					return true
+				}
+				desc := strconv.FormatUint(value, 2) + " to " + strconv.FormatUint(upperValue, 2) + " with " + strconv.FormatUint(maskValue, 2)
+				masker := ipaddr.MaskRange(value, upperValue, maskValue, maxValue)
+				// the lowest and highest values are provided only when the resulting values are sequential
+				if sequential := isSequential(&masked, maskedLower, maskedUpper); masker.IsSequential() != sequential ||
+					(sequential && (masker.GetMaskedLower(value, maskValue) != maskedLower || masker.GetMaskedUpper(upperValue, maskValue) != maskedUpper)) {
+					t.addFailure(newFailure("invalid masking of "+desc+", expected "+
+						strconv.FormatUint(maskedLower, 2)+" to "+strconv.FormatUint(maskedUpper, 2), nil))
+				}
+				orer := ipaddr.BitwiseOrRange(value, upperValue, maskValue, maxValue)
+				// the lowest and highest values are provided only when the resulting values are sequential
+				if sequential := isSequential(&ored, oredLower, oredUpper); orer.IsSequential() != sequential ||
+					(sequential && (orer.GetOredLower(value, maskValue) != oredLower || orer.GetOredUpper(upperValue, maskValue) != oredUpper)) {
+					t.addFailure(newFailure("invalid oring of "+desc+", expected "+
+						strconv.FormatUint(oredLower, 2)+" to "+strconv.FormatUint(oredUpper, 2), nil))
+				}
+				t.incrementTestCount()
+			}
+		}
+	}
+}
+
 // tests the maskRange method and its counterpart that works with divs > 64 bits, maskExtendedRange
 func (t ipAddressAllTester) testMaskedRange(value, upperValue, maskValue uint64, expectedIsSequential bool, expectedLower, expectedUpper uint64) {
 	masker := ipaddr.MaskRange(value, upperValue, maskValue, math.MaxUint64)
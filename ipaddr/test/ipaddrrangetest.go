@@ -1647,6 +1647,54 @@ func (t ipAddressRangeTester) run() {
 	t.testIncrement("ffff:3-4:ffff:ffff:ffff:1-2:2-3::", 7, "ffff:4:ffff:ffff:ffff:2:3::")
 	t.testIncrement("ffff:3-4:ffff:ffff:ffff:1-2:2-3::", 9, "ffff:4:ffff:ffff:ffff:2:3:2")
 
+	// increments within a multi-valued address end at the upper value only with the increment of the count less one
+	t.testIncrement("1:2:3:4:5:6:7:0-1ff", 0x1fd, "1:2:3:4:5:6:7:1fd")
+	t.testIncrement("1:2:3:4:5:6:7:0-1ff", 0x1fe, "1:2:3:4:5:6:7:1fe")
+	t.testIncrement("1:2:3:4:5:6:7:0-1ff", 0x1ff, "1:2:3:4:5:6:7:1ff")
+	t.testIncrement("1:2:3:4:5:6:7:0-1ff", 0x200, "1:2:3:4:5:6:7:200")
+	t.testIncrement("1:2:3:4:5:6:0-1:fffe-ffff", 2, "1:2:3:4:5:6:1:fffe")
+	t.testIncrement("1:2:3:4:5:6:0-1:fffe-ffff", 3, "1:2:3:4:5:6:1:ffff")
+	t.testIncrement("1:2:3:4:5:6:0-1:fffe-ffff", 4, "1:2:3:4:5:6:2::")
+
+	t.testIncrementBig("::", "10000000000000000", "::1:0:0:0:0")
+	t.testIncrementBig("::1:0:0:0:0", "-10000000000000000", "::")
+	t.testIncrementBig("::1:0:0:0:0", "-10000000000000001", "")
+	t.testIncrementBig("ffff::", "ffffffffffffffffffffffffffff", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff")
+	t.testIncrementBig("ffff::", "10000000000000000000000000000", "")
+	t.testIncrementBig("1:2:3:4::/64", "fedcba9876543210", "1:2:3:4:fedc:ba98:7654:3210")
+	t.testIncrementBig("1:2:3:4::/64", "10000000000000001", "1:2:3:5::1")
+	t.testIncrementBig("1:*:*:*::/64", "fffffffffffffffffffffffff", "1:f:ffff:ffff:ffff:ffff:ffff:ffff")
+	t.testIncrementBig("1:*:*:*::/64", "10000000000000000000000000000", "2::")
+	t.testIncrementBig("*:*:*:*::", "ffffffffffffffff", "ffff:ffff:ffff:ffff::")
+	t.testIncrementBig("*:*:*:*::", "10000000000000000", "ffff:ffff:ffff:ffff::1")
+
+	t.testRangeIncrementBig("1::1", "1::ffff", "10000000000000000", "1:0:0:1::1", "1:0:0:1::ffff")
+	t.testRangeIncrementBig("1::1", "1::ffff", "-10000000000000000000000000000", "::1", "::ffff")
+	t.testRangeIncrementBig("::1", "::ffff", "-2", "", "")
+
+	t.testRangeMask("1.2.3.0", "1.2.5.255", "255.255.0.0", false, "1.2.0.0", "1.2.0.0")
+	t.testRangeMask("1.2.3.4", "1.2.3.100", "255.255.255.0", false, "1.2.3.0", "1.2.3.0")
+	t.testRangeMask("1.2.0.0", "1.3.255.255", "255.254.0.0", false, "1.2.0.0", "1.2.0.0")
+	t.testRangeMask("10.0.0.5", "10.0.0.10", "0.0.0.255", false, "0.0.0.5", "0.0.0.10")
+	t.testRangeMask("1.2.0.0", "1.2.255.255", "0.0.255.255", false, "0.0.0.0", "0.0.255.255")
+	t.testRangeMask("1.2.3.250", "1.2.4.5", "255.255.255.0", false, "", "")
+	t.testRangeMask("1.2.0.0", "1.2.255.255", "255.255.255.0", false, "", "")
+	t.testRangeMask("1.2.3.0", "1.2.3.255", "255.255.255.240", false, "", "")
+	t.testRangeMask("1.2.3.250", "1.2.4.5", "255.255.0.0", false, "1.2.0.0", "1.2.0.0")
+	t.testRangeMask("1.2.3.0", "1.2.3.255", "0.0.0.255", true, "1.2.3.255", "1.2.3.255")
+	t.testRangeMask("1.2.0.0", "1.2.0.15", "0.0.0.16", true, "1.2.0.16", "1.2.0.31")
+	t.testRangeMask("1.2.3.250", "1.2.4.5", "0.0.0.15", true, "", "")
+	t.testRangeMask("1.2.0.0", "1.2.0.15", "0.0.0.1", true, "", "")
+	t.testRangeMask("1.2.3.4", "1.2.3.5", "::1", false, "", "")
+	t.testRangeMask("1:2::", "1:2:ffff:ffff:ffff:ffff:ffff:ffff", "ffff:ffff::", false, "1:2::", "1:2::")
+	t.testRangeMask("1::", "1::ffff:ffff:ffff:ffff", "::ffff:ffff:ffff:ffff", false, "::", "::ffff:ffff:ffff:ffff")
+	t.testRangeMask("1::ffff:ffff:ffff:fff0", "1:0:0:1::f", "ffff:ffff:ffff:ffff::", false, "", "")
+	t.testRangeMask("1::ffff:ffff:ffff:fff0", "1:0:0:1::f", "ffff:ffff:ffff:fffe::", false, "1::", "1::")
+	t.testRangeMask("1::ffff:ffff:ffff:fff0", "1:0:0:1::f", "::ffff", false, "", "")
+	t.testRangeMask("1::", "1::ff", "::ff00", true, "1::ff00", "1::ffff")
+	t.testRangeMask("1::ffff:ffff:ffff:fff0", "1:0:0:1::f", "ffff::", true, "ffff::ffff:ffff:ffff:fff0", "ffff:0:0:1::f")
+	t.testRangeMask("1::ffff:ffff:ffff:fff0", "1:0:0:1::f", "::1:0:0:0:0", true, "", "")
+
 	t.testLeadingZeroAddr("00-1.1.2.3", true)
 	t.testLeadingZeroAddr("1.00-1.2.3", true)
 	t.testLeadingZeroAddr("1.2.00-1.3", true)
@@ -2458,9 +2506,392 @@ func (t ipAddressRangeTester) run() {
 	t.testCover("::1", "::", "::0-1/127")
 	t.testCoverSingle("ffff:ffff:ffff:ffff::/64", "ffff:ffff:ffff:ffff:*/64")
 
+	t.testRangeTree([]string{"1.0.0.0,1.0.0.255", "1.0.0.10,1.0.0.20", "1.0.0.15,1.0.1.5", "1.0.0.0,1.0.0.255", "0.0.0.0,255.255.255.255",
+		"1.0.0.15,1.0.0.15", "2.0.0.0,2.0.0.8", "1.0.0.200,1.0.1.0", "::,::ffff:ffff", "1::,1::5", "1:0:0:0:0:0:0:3,2::"},
+		[]string{"1.0.0.15", "1.0.0.9", "1.0.1.0", "2.0.0.9", "1::4", "::1", "::1.0.0.15", "1.0.0.10,1.0.0.16", "0.0.0.0,1.0.0.0", "1::5,3::"})
+	t.testRangeTreeSeries(200)
+
+	t.testRangeSet([]string{"1.0.0.0,1.0.0.10", "1.0.0.11,1.0.0.20", "1.0.0.30,1.0.0.40", "1.0.0.35,1.0.0.50", "::1,::5", "255.255.255.255,255.255.255.255", "::,::"},
+		[]string{"1.0.0.5,1.0.0.6"},
+		"[1.0.0.0 -> 1.0.0.4, 1.0.0.7 -> 1.0.0.20, 1.0.0.30 -> 1.0.0.50, 255.255.255.255 -> 255.255.255.255, :: -> ::5]")
+	t.testRangeSet([]string{"1.0.0.0,1.0.0.10", "1.0.0.20,1.0.0.30", "1.0.0.40,1.0.0.50", "1.0.0.5,1.0.0.45"}, nil, "[1.0.0.0 -> 1.0.0.50]")
+	t.testRangeSet([]string{"1.0.0.0,1.0.0.10", "1.0.0.20,1.0.0.30", "1.0.0.40,1.0.0.50"}, []string{"1.0.0.5,1.0.0.45"}, "[1.0.0.0 -> 1.0.0.4, 1.0.0.46 -> 1.0.0.50]")
+	t.testRangeSet([]string{"1.0.0.0,1.0.0.10", "1.0.0.20,1.0.0.30"}, []string{"1.0.0.0,1.0.0.30", "2.0.0.0,2.0.0.1"}, "[]")
+	t.testRangeSet([]string{"1::,1::ffff", "1.0.0.0,1.0.0.10"}, []string{"1::5,1::5", "1.0.0.10,1.0.0.10"}, "[1.0.0.0 -> 1.0.0.9, 1:: -> 1::4, 1::6 -> 1::ffff]")
+	t.testRangeSet(nil, nil, "[]")
+	t.testRangeSetSeries(300)
+
+	t.testSeqRangeString("1.2.3.4-1.2.5.9", "1.2.3.4", "1.2.5.9")
+	t.testSeqRangeString(" 1.2.5.9 - 1.2.3.4 ", "1.2.3.4", "1.2.5.9")
+	t.testSeqRangeString("1.2.3.4 -> 1.2.5.9", "1.2.3.4", "1.2.5.9")
+	t.testSeqRangeString("1.2.3.4-5", "1.2.3.4", "1.2.3.5")
+	t.testSeqRangeString("1.2.3.0/24", "1.2.3.0", "1.2.3.255")
+	t.testSeqRangeString("1.2.3.0/24-1.2.5.0/24", "1.2.3.0", "1.2.5.255")
+	t.testSeqRangeString("1.2.5.0/24-1.2.3.0/24", "1.2.3.0", "1.2.5.255")
+	t.testSeqRangeString("1.2.3.0/24-1.2.3.128/25", "1.2.3.0", "1.2.3.255")
+	t.testSeqRangeString("*", "", "")
+	t.testSeqRangeString("*-1.2.3.4", "", "")
+	t.testSeqRangeString("1.2.3.4", "1.2.3.4", "1.2.3.4")
+	t.testSeqRangeString("1::-2::5", "1::", "2::5")
+	t.testSeqRangeString("1::1-2:3::", "1::1", "2:3::")
+	t.testSeqRangeString("1::1-2 -> 1::8", "1::1", "1::8")
+	t.testSeqRangeString("::ffff:1.2.3.4-::ffff:1.2.3.9", "::ffff:1.2.3.4", "::ffff:1.2.3.9")
+	t.testSeqRangeString("1.2.3.4-1::", "", "")
+	t.testSeqRangeString("1.2.3.4-x", "", "")
+	t.testSeqRangeString("1.2.3.4-1.2.3.4.5", "", "")
+	t.testSeqRangeString("1::2-1:2:3:4:5:6:7:8:9", "", "")
+	t.testSeqRangeString("1.2.3.4-1.2.3.a", "", "")
+
 	t.ipAddressTester.run()
 }
 
+func (t ipAddressRangeTester) testSeqRangeString(str, expectedLower, expectedUpper string) {
+	rngStr := ipaddr.NewSeqRangeString(str)
+	rng, err := rngStr.ToSequentialRange()
+	if expectedLower == "" {
+		if err == nil || rngStr.IsValid() || rng != nil || rngStr.GetSequentialRange() != nil {
+			t.addFailure(newSeqRangeFailure("expected error parsing "+str, rng))
+		}
+	} else if err != nil {
+		t.addFailure(newFailure("unexpected error parsing range: "+err.Error(), nil))
+	} else {
+		expected := ipaddr.NewSequentialRange(t.createAddress(expectedLower).GetAddress(), t.createAddress(expectedUpper).GetAddress())
+		if !rng.Equal(expected) || !rngStr.GetSequentialRange().Equal(expected) {
+			t.addFailure(newSeqRangeFailure("parsed "+str+" to "+rng.String()+", expected "+expected.String(), rng))
+		} else if reparsed := ipaddr.NewSeqRangeString(rng.String()).GetSequentialRange(); !reparsed.Equal(rng) {
+			t.addFailure(newSeqRangeFailure("range string "+rng.String()+" reparsed to "+reparsed.String(), rng))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressRangeTester) createRange(str string) *ipaddr.IPAddressSeqRange {
+	bounds := strings.Split(str, ",")
+	lower := t.createAddress(bounds[0]).GetAddress()
+	if len(bounds) == 1 {
+		return lower.ToSequentialRange()
+	}
+	return lower.SpanWithRange(t.createAddress(bounds[1]).GetAddress())
+}
+
+func (t ipAddressRangeTester) testRangeTree(rangeStrs, queryStrs []string) {
+	tree := ipaddr.RangeTree[*ipaddr.IPAddress]{}
+	var ranges []*ipaddr.IPAddressSeqRange
+	for _, str := range rangeStrs {
+		rng := t.createRange(str)
+		isNew := true
+		for _, existing := range ranges {
+			if existing.Equal(rng) {
+				isNew = false
+			}
+		}
+		if added := tree.Add(rng); added != isNew {
+			t.addFailure(newSeqRangeFailure(fmt.Sprint("range tree add returned ", added), rng))
+		} else if isNew {
+			ranges = append(ranges, rng)
+		}
+	}
+	var queries []*ipaddr.IPAddressSeqRange
+	for _, str := range queryStrs {
+		queries = append(queries, t.createRange(str))
+	}
+	t.checkRangeTree(&tree, ranges, queries)
+
+	// remove every second range, then the remainder
+	for len(ranges) > 0 {
+		var remaining []*ipaddr.IPAddressSeqRange
+		for i, rng := range ranges {
+			if i%2 == 1 {
+				remaining = append(remaining, rng)
+			} else if !tree.Remove(rng) {
+				t.addFailure(newSeqRangeFailure("range tree remove failed", rng))
+			} else if tree.Remove(rng) || tree.Contains(rng) {
+				t.addFailure(newSeqRangeFailure("range tree removed range still present", rng))
+			}
+		}
+		ranges = remaining
+		t.checkRangeTree(&tree, ranges, queries)
+	}
+	if !tree.IsEmpty() || tree.String() != "[]" {
+		t.addFailure(newFailure("range tree not empty: "+tree.String(), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressRangeTester) testRangeTreeSeries(count int) {
+	tree := ipaddr.RangeTree[*ipaddr.IPv4Address]{}
+	var ranges []*ipaddr.IPv4AddressSeqRange
+	for i := 0; i < count; i++ {
+		// a series of overlapping ranges of varying length
+		lower := ipaddr.NewIPv4AddressFromUint32(uint32(i*37%count) << 8)
+		upper := lower.Increment(int64(i*53%count) << 6)
+		rng := lower.SpanWithRange(upper)
+		if tree.Add(rng) {
+			ranges = append(ranges, rng)
+		}
+	}
+	if tree.Size() != len(ranges) {
+		t.addFailure(newFailure(fmt.Sprint("range tree size ", tree.Size(), " expected ", len(ranges)), nil))
+	}
+	for i := 0; i < count; i += 7 {
+		addr := ipaddr.NewIPv4AddressFromUint32(uint32(i) << 8)
+		query := addr.SpanWithRange(addr.Increment(300))
+		var expectedContaining, expectedOverlapping int
+		for _, rng := range ranges {
+			if rng.Contains(addr) {
+				expectedContaining++
+			}
+			if rng.Overlaps(query) {
+				expectedOverlapping++
+			}
+		}
+		if containing := tree.ElementsContaining(addr); len(containing) != expectedContaining {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("range tree found ", len(containing), " ranges containing, expected ", expectedContaining), addr.ToIP()))
+		} else if overlapping := tree.ElementsOverlapping(query); len(overlapping) != expectedOverlapping {
+			t.addFailure(newSeqRangeFailure(fmt.Sprint("range tree found ", len(overlapping), " ranges overlapping, expected ", expectedOverlapping), query.ToIP()))
+		}
+	}
+	previous := (*ipaddr.IPv4AddressSeqRange)(nil)
+	for rng := tree.Iterator(); rng.HasNext(); {
+		next := rng.Next()
+		if previous != nil && (previous.GetLower().Compare(next.GetLower()) > 0 ||
+			previous.GetLower().Equal(next.GetLower()) && previous.GetUpper().Compare(next.GetUpper()) >= 0) {
+			t.addFailure(newSeqRangeFailure("range tree out of order at "+previous.String(), next.ToIP()))
+		}
+		previous = next
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressRangeTester) testRangeSet(adds, removes []string, expected string) {
+	set := ipaddr.RangeSet[*ipaddr.IPAddress]{}
+	for _, str := range adds {
+		set.Add(t.createRange(str))
+	}
+	for _, str := range removes {
+		set.Remove(t.createRange(str))
+	}
+	if set.String() != expected {
+		t.addFailure(newFailure("range set "+set.String()+" does not match expected "+expected, nil))
+	}
+	for _, str := range adds {
+		rng := t.createRange(str)
+		if contained := set.ContainsRange(rng); set.Add(rng) == contained {
+			t.addFailure(newSeqRangeFailure("range set add result does not match containment in "+set.String(), rng))
+		} else if !set.ContainsRange(rng) || !set.Contains(rng.GetLower()) || !set.Contains(rng.GetUpper()) || !set.Overlaps(rng) {
+			t.addFailure(newSeqRangeFailure("range set does not contain added range "+set.String(), rng))
+		}
+	}
+	for _, str := range removes {
+		rng := t.createRange(str)
+		if overlapped := set.Overlaps(rng); set.Remove(rng) != overlapped {
+			t.addFailure(newSeqRangeFailure("range set remove result does not match overlap in "+set.String(), rng))
+		} else if set.Overlaps(rng) || set.Contains(rng.GetLower()) || set.Contains(rng.GetUpper()) || set.Remove(rng) {
+			t.addFailure(newSeqRangeFailure("range set contains removed range "+set.String(), rng))
+		}
+	}
+	if set.String() != expected {
+		t.addFailure(newFailure("range set "+set.String()+" does not match expected "+expected+" after adding and removing again", nil))
+	} else if clone := set.Clone(); !clone.Equal(&set) || !ipaddr.NewRangeSet(set.Ranges()...).Equal(&set) {
+		t.addFailure(newFailure("range set copies do not match "+set.String(), nil))
+	} else if set.IsEmpty() != (expected == "[]") || set.GetCount().Sign() != 0 == set.IsEmpty() {
+		t.addFailure(newFailure("range set emptiness does not match "+set.String(), nil))
+	}
+	t.incrementTestCount()
+}
+
+// testRangeSetSeries checks a range set of addresses from 0.0.0.0 to 0.0.15.255 against a bitmap of the same addresses
+func (t ipAddressRangeTester) testRangeSetSeries(count int) {
+	const space = 4096
+	newRange := func(i int) (*ipaddr.IPv4AddressSeqRange, int, int) {
+		lower := i * 571 % space
+		upper := lower + i*97%150
+		if upper >= space {
+			upper = space - 1
+		}
+		return ipaddr.NewIPv4AddressFromUint32(uint32(lower)).SpanWithRange(ipaddr.NewIPv4AddressFromUint32(uint32(upper))), lower, upper
+	}
+	var sets [2]ipaddr.RangeSet[*ipaddr.IPv4Address]
+	var bitmaps [2][space]bool
+	for i := 1; i < count; i++ {
+		rng, lower, upper := newRange(i)
+		which := i % 2
+		changed := false
+		if i%5 == 0 {
+			for j := lower; j <= upper; j++ {
+				changed = changed || bitmaps[which][j]
+				bitmaps[which][j] = false
+			}
+			if set := &sets[which]; set.Remove(rng) != changed {
+				t.addFailure(newSeqRangeFailure(fmt.Sprint("range set remove returned ", !changed), rng.ToIP()))
+			}
+		} else {
+			for j := lower; j <= upper; j++ {
+				changed = changed || !bitmaps[which][j]
+				bitmaps[which][j] = true
+			}
+			if set := &sets[which]; set.Add(rng) != changed {
+				t.addFailure(newSeqRangeFailure(fmt.Sprint("range set add returned ", !changed), rng.ToIP()))
+			}
+		}
+	}
+	union, intersection, difference := sets[0].Union(&sets[1]), sets[0].Intersect(&sets[1]), sets[0].Subtract(&sets[1])
+	checks := []struct {
+		set      *ipaddr.RangeSet[*ipaddr.IPv4Address]
+		contains func(int) bool
+	}{
+		{&sets[0], func(j int) bool { return bitmaps[0][j] }},
+		{&sets[1], func(j int) bool { return bitmaps[1][j] }},
+		{union, func(j int) bool { return bitmaps[0][j] || bitmaps[1][j] }},
+		{intersection, func(j int) bool { return bitmaps[0][j] && bitmaps[1][j] }},
+		{difference, func(j int) bool { return bitmaps[0][j] && !bitmaps[1][j] }},
+	}
+	for i, check := range checks {
+		expectedCount, expectedRanges := 0, 0
+		for j := 0; j < space; j++ {
+			contains := check.contains(j)
+			if contains {
+				expectedCount++
+				if j == 0 || !check.contains(j-1) {
+					expectedRanges++
+				}
+			}
+			if addr := ipaddr.NewIPv4AddressFromUint32(uint32(j)); check.set.Contains(addr) != contains {
+				t.addFailure(newIPAddrFailure(fmt.Sprint("range set ", i, " containment is not ", contains), addr.ToIP()))
+				break
+			}
+		}
+		if check.set.GetCount().Int64() != int64(expectedCount) {
+			t.addFailure(newFailure(fmt.Sprint("range set ", i, " count ", check.set.GetCount(), " expected ", expectedCount), nil))
+		} else if check.set.Size() != expectedRanges {
+			t.addFailure(newFailure(fmt.Sprint("range set ", i, " is not normalized, size ", check.set.Size(), " expected ", expectedRanges), nil))
+		}
+		t.incrementTestCount()
+	}
+}
+
+func (t ipAddressRangeTester) checkRangeTree(tree *ipaddr.RangeTree[*ipaddr.IPAddress], ranges, queries []*ipaddr.IPAddressSeqRange) {
+	if tree.Size() != len(ranges) {
+		t.addFailure(newFailure(fmt.Sprint("range tree size ", tree.Size(), " expected ", len(ranges), ": ", tree), nil))
+	}
+	for _, rng := range ranges {
+		if !tree.Contains(rng) {
+			t.addFailure(newSeqRangeFailure("range tree missing range", rng))
+		}
+	}
+	for _, query := range queries {
+		var expectedContaining, expectedOverlapping []*ipaddr.IPAddressSeqRange
+		for _, element := range tree.Elements() { // sorted
+			if element.ContainsRange(query) {
+				expectedContaining = append(expectedContaining, element)
+			}
+			if element.Overlaps(query) {
+				expectedOverlapping = append(expectedOverlapping, element)
+			}
+		}
+		if !query.IsMultiple() {
+			t.checkRangeTreeResult("containing", query, tree.ElementsContaining(query.GetLower()), expectedContaining)
+		}
+		t.checkRangeTreeResult("containing range", query, tree.ElementsContainingRange(query), expectedContaining)
+		t.checkRangeTreeResult("overlapping", query, tree.ElementsOverlapping(query), expectedOverlapping)
+	}
+}
+
+func (t ipAddressRangeTester) checkRangeTreeResult(kind string, query *ipaddr.IPAddressSeqRange, result, expected []*ipaddr.IPAddressSeqRange) {
+	matches := len(result) == len(expected)
+	for i := 0; matches && i < len(result); i++ {
+		matches = result[i].Equal(expected[i])
+	}
+	if !matches {
+		t.addFailure(newSeqRangeFailure(fmt.Sprint("range tree ", kind, " returned ", result, " expected ", expected), query))
+	}
+}
+
+func (t ipAddressRangeTester) testIncrementBig(originalStr, incrementStr, resultStr string) {
+	orig := t.createAddress(originalStr).GetAddress()
+	increment, _ := new(big.Int).SetString(incrementStr, 16)
+	result := orig.IncrementBig(increment)
+	if resultStr == "" {
+		if result != nil {
+			t.addFailure(newIPAddrFailure("big increment mismatch result "+result.String()+" vs none expected", orig))
+		}
+	} else {
+		expectedResult := t.createAddress(resultStr).GetAddress()
+		if !result.Equal(expectedResult) {
+			t.addFailure(newIPAddrFailure("big increment mismatch result "+result.String()+" vs expected "+expectedResult.String(), orig))
+		} else if !orig.IsMultiple() {
+			if distance := orig.Distance(result); distance.Cmp(increment) != 0 {
+				t.addFailure(newIPAddrFailure("distance mismatch result "+distance.String()+" vs expected "+increment.String(), orig))
+			} else if back := result.IncrementBig(distance.Neg(distance)); !back.Equal(orig) {
+				t.addFailure(newIPAddrFailure("big decrement mismatch result "+back.String()+" vs expected "+orig.String(), result))
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressRangeTester) testRangeIncrementBig(lowerStr, upperStr, incrementStr, expectedLowerStr, expectedUpperStr string) {
+	rng := t.createAddress(lowerStr).GetAddress().SpanWithRange(t.createAddress(upperStr).GetAddress())
+	increment, _ := new(big.Int).SetString(incrementStr, 16)
+	result := rng.IncrementBig(increment)
+	if expectedLowerStr == "" {
+		if result != nil {
+			t.addFailure(newSeqRangeFailure("big increment mismatch result "+result.String()+" vs none expected", rng))
+		}
+	} else {
+		expected := t.createAddress(expectedLowerStr).GetAddress().SpanWithRange(t.createAddress(expectedUpperStr).GetAddress())
+		if !result.Equal(expected) {
+			t.addFailure(newSeqRangeFailure("big increment mismatch result "+result.String()+" vs expected "+expected.String(), rng))
+		} else if result.GetCount().Cmp(rng.GetCount()) != 0 {
+			t.addFailure(newSeqRangeFailure("big increment count mismatch result "+result.GetCount().String()+" vs expected "+rng.GetCount().String(), rng))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressRangeTester) testRangeMask(lowerStr, upperStr, maskStr string, isOr bool, expectedLowerStr, expectedUpperStr string) {
+	rng := t.createAddress(lowerStr).GetAddress().SpanWithRange(t.createAddress(upperStr).GetAddress())
+	mask := t.createAddress(maskStr).GetAddress()
+	var result *ipaddr.IPAddressSeqRange
+	var err error
+	if isOr {
+		result, err = rng.BitwiseOr(mask)
+	} else {
+		result, err = rng.Mask(mask)
+	}
+	if expectedLowerStr == "" {
+		if err == nil {
+			t.addFailure(newSeqRangeFailure("mask mismatch result "+result.String()+" vs error expected", rng))
+		}
+	} else if err != nil {
+		t.addFailure(newSeqRangeFailure("unexpected mask error "+err.Error(), rng))
+	} else {
+		expected := t.createAddress(expectedLowerStr).GetAddress().SpanWithRange(t.createAddress(expectedUpperStr).GetAddress())
+		if !result.Equal(expected) {
+			t.addFailure(newSeqRangeFailure("mask mismatch result "+result.String()+" vs expected "+expected.String(), rng))
+		} else if rng.GetCount().Cmp(big.NewInt(1024)) <= 0 {
+			// every masked address must be in the result, and the result must have no gaps
+			masked := make(map[string]struct{})
+			for iter := rng.Iterator(); iter.HasNext(); {
+				var addr *ipaddr.IPAddress
+				if isOr {
+					addr, _ = iter.Next().BitwiseOr(mask)
+				} else {
+					addr, _ = iter.Next().Mask(mask)
+				}
+				if !result.Contains(addr) {
+					t.addFailure(newSeqRangeFailure("mask result "+result.String()+" does not contain "+addr.String(), rng))
+				}
+				masked[addr.String()] = struct{}{}
+			}
+			if result.GetCount().Cmp(big.NewInt(int64(len(masked)))) != 0 {
+				t.addFailure(newSeqRangeFailure("mask result "+result.String()+" count does not match "+strconv.Itoa(len(masked)), rng))
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
 func setBigString(str string, base int) *big.Int {
 	res, b := new(big.Int).SetString(str, base)
 	if !b {
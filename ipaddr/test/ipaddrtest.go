@@ -18,14 +18,24 @@ package test
 
 import (
 	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
+	"math/rand"
 	"net"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"testing"
+	"time"
 
 	"github.com/seancfoley/ipaddress-go/ipaddr"
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrstr"
 	"github.com/seancfoley/ipaddress-go/ipaddr/addrstrparam"
 )
@@ -1771,6 +1781,840 @@ func (t ipAddressTester) run() {
 	t.testMixedNoComp("::", "::", "::0.0.0.0")
 	t.testMixed("::1", "::0.0.0.1")
 
+	t.testCompressRun("1:0:0:f:0:0:1:1", addrstr.LongestLeftmost, 0, "1::f:0:0:1:1")
+	t.testCompressRun("1:0:0:f:0:0:1:1", addrstr.LongestRightmost, 0, "1:0:0:f::1:1")
+	t.testCompressRun("1:0:0:f:0:0:0:1", addrstr.LongestRightmost, 0, "1:0:0:f::1")
+	t.testCompressRun("1:0:0:f:0:0:0:1", addrstr.Leftmost, 0, "1::f:0:0:0:1")
+	t.testCompressRun("1:0:0:f:0:0:0:1", addrstr.Rightmost, 0, "1:0:0:f::1")
+	t.testCompressRun("1:0:0:f:0:0:0:1", addrstr.LongestLeftmost, 4, "1:0:0:f:0:0:0:1")
+	t.testCompressRun("1:0:0:f:0:0:0:1", addrstr.Leftmost, 3, "1:0:0:f::1")
+
+	t.testParamsSerialization(hostOptions, "1.2.3", false)
+	t.testParamsSerialization(hostInetAtonOptions, "1.2.3", true)
+	t.testParamsSerialization(hostWildcardAndRangeInetAtonOptions, "1.2-3.*.4", true)
+	t.testParamsSerialization(hostOnlyOptions, "1.2.3.4", false)
+	t.testParamsSerialization(defaultHostOptions, "1.2.3.4/16", true)
+
+	t.testContainsPrefixBlockBySegment("1.2.*.*", 16, "1111")
+	t.testContainsPrefixBlockBySegment("1.2.0-127.*", 16, "1101")
+	t.testContainsPrefixBlockBySegment("1.2.0-127.*", 17, "1111")
+	t.testContainsPrefixBlockBySegment("1.2.3.4-5", 24, "1110")
+	t.testContainsPrefixBlockBySegment("1.2.3.4-5", 31, "1111")
+	t.testContainsPrefixBlockBySegment("1.2.3-4.5", 0, "0000")
+	t.testContainsPrefixBlockBySegment("1:2:3:4:*:*:*:*", 64, "11111111")
+	t.testContainsPrefixBlockBySegment("1:2:3:4:5:*:*:*", 64, "11110111")
+
+	t.testMapSegments("1.2.3.4", "2.3.4.5")
+	t.testMapSegments("1.2.3.4/16", "2.3.4.5/16")
+	t.testMapSegments("1:2:3:4:5:6:7:8%eth0", "2:3:4:5:6:7:8:9%eth0")
+	t.testMapSegments("1::/64", "2:1:1:1:1:1:1:1/64")
+
+	t.testRegroupByBitCounts("6012:3456::", []ipaddr.BitCount{4, 8, 20}, "[0x6 0x1 0x23456]")
+	t.testRegroupByBitCounts("6fff:ffff::", []ipaddr.BitCount{4, 8, 20}, "[0x6 0xff 0xfffff]")
+	t.testRegroupByBitCounts("6012:*::", []ipaddr.BitCount{4, 8, 20}, "[0x6 0x1 0x20000-0x2ffff]")
+	t.testRegroupByBitCounts("6012:3456::", []ipaddr.BitCount{4, 8, 16}, "")
+	t.testRegroupByBitCounts("6012:3456::", []ipaddr.BitCount{0, 12, 20}, "")
+	t.testRegroupByBitCounts("6010-6011:3456::", []ipaddr.BitCount{4, 8, 20}, "")
+
+	t.testParseVersion("IPv4", ipaddr.IPv4, true)
+	t.testParseVersion("ipv6", ipaddr.IPv6, true)
+	t.testParseVersion("4", ipaddr.IPv4, true)
+	t.testParseVersion("6", ipaddr.IPv6, true)
+	t.testParseVersion("", ipaddr.IndeterminateIPVersion, true)
+	t.testParseVersion("IPv5", ipaddr.IndeterminateIPVersion, false)
+	t.testParseVersion("v4", ipaddr.IndeterminateIPVersion, false)
+	t.testVersions()
+
+	t.testTeredo("2001:0:4136:e378:8000:63bf:3fff:fdd2", "65.54.227.120", "192.0.2.45", 0x8000, 40000)
+	t.testTeredo("2001::ffff:ffff", "0.0.0.0", "0.0.0.0", 0, 0xffff)
+	t.testEmbeddedIPv4("::ffff:1.2.3.4", "1.2.3.4", "")
+	t.testEmbeddedIPv4("::ffff:1.2.3.*", "1.2.3.*", "")
+	t.testEmbeddedIPv4("::1.2.3.4", "", "1.2.3.4")
+	t.testEmbeddedIPv4("1::1.2.3.4", "", "")
+	t.test6rd("2001:db8::1", "2001:db8::/32", 0, "", "0.0.0.0")
+	t.test6rd("2001:db8:c000:201::1", "2001:db8::/32", 0, "", "192.0.2.1")
+	t.test6rd("2001:db8:c000:201::/64", "2001:db8::/32", 0, "", "192.0.2.1")
+	t.test6rd("2001:db8:64c8:1ff::1", "2001:db8::/32", 8, "10.0.0.1", "10.100.200.1")
+	t.test6rd("2001:db8:64c8:1ff::1", "2001:db8::/32", 8, "", "0.100.200.1")
+	t.test6rd("2001:dbb:0:804::1", "2001:db8::/30", 0, "", "192.0.2.1")
+	t.test6rd("2001:db8::", "2001:db8::/32", 32, "192.0.2.1", "192.0.2.1")
+	t.test6rd("2001:db8:0:0:c000:201::", "2001:db8::/64", 0, "", "192.0.2.1")
+	t.test6rd("2001:db8::c000:201", "2001:db8::/96", 0, "", "192.0.2.1")
+	t.test6rd("2001:db9::1", "2001:db8::/32", 0, "", "")
+	t.test6rd("2001:db8::1", "2001:db8::/112", 0, "", "")
+	t.test6rd("2001:db8::1", "2001:db8::/32", 33, "", "")
+	t.test6rd("2001:db8::1", "2001:db8::", 0, "", "")
+	t.test6To4("192.0.2.4", 1, 0x1234, "2002:c000:204:1::1234")
+
+	t.testSolicitedNodeMulticast("2001:db8::1:2:3:4", "ff02::1:ff03:4")
+	t.testSolicitedNodeMulticast("fe80::2aa:ff:fe28:9c5a%eth0", "ff02::1:ff28:9c5a%eth0")
+	t.testSolicitedNodeMulticast("2001:db8::/64", "ff02::1:ff00-ffff:*")
+	t.testSolicitedNodeMulticast("2001:db8::1:2:3:4/64", "ff02::1:ff03:4")
+	t.testSolicitedNodeMulticast("2001:db8::1:2:1-2:4", "ff02::1:ff01-ff02:4")
+	t.testSolicitedNodeMulticast("2001:db8::1:2:ff-100:4", "")
+	t.testIPv6MulticastMAC("ff02::1", "33:33:00:00:00:01")
+	t.testIPv6MulticastMAC("ff02::1:ff28:9c5a%eth0", "33:33:ff:28:9c:5a")
+	t.testIPv6MulticastMAC("ff02::1:ff00:0/104", "33:33:ff:*:*:*")
+	t.testIPv6MulticastMAC("ff02::1:ff00-ffff:0", "33:33:ff:*:00:00")
+	t.testIPv6MulticastMAC("ff02::1:fe00-ff00:0", "")
+	t.testIPv4MulticastMAC("224.0.0.251", "01:00:5e:00:00:fb")
+	t.testIPv4MulticastMAC("239.255.255.250", "01:00:5e:7f:ff:fa")
+	t.testIPv4MulticastMAC("224.128.0.251", "01:00:5e:00:00:fb")
+	t.testIPv4MulticastMAC("224.0.0.0/24", "01:00:5e:00:00:*")
+	t.testIPv4MulticastMAC("224.0.0.0/4", "01:00:5e:00-7f:*:*")
+	t.testIPv4MulticastMAC("224.100-200.0.1", "")
+
+	t.testPrefixBlocksOfLen("1.2.0.0/16", 24, 256, "1.2.0.0/24", "1.2.255.0/24")
+	t.testPrefixBlocksOfLen("1.2.0.0/16", 16, 1, "1.2.0.0/16", "1.2.0.0/16")
+	t.testPrefixBlocksOfLen("1.2.0.0/16", 8, 1, "1.0.0.0/8", "1.0.0.0/8")
+	t.testPrefixBlocksOfLen("1.2.3-4.5", 24, 2, "1.2.3.0/24", "1.2.4.0/24")
+	t.testPrefixBlocksOfLen("1.2.3.4", 40, 1, "1.2.3.4/32", "1.2.3.4/32")
+	t.testPrefixBlocksOfLen("1.2.3.4", -1, 1, "0.0.0.0/0", "0.0.0.0/0")
+	t.testPrefixBlocksOfLen("1:2::/32", 48, 65536, "1:2::/48", "1:2:ffff::/48")
+	t.testPrefixBlocksOfLen("1:2:3:4::/64", 56, 1, "1:2:3::/56", "1:2:3::/56")
+
+	t.testAddToSegment("1.2.3.4", 3, 1, "1.2.3.5")
+	t.testAddToSegment("1.2.3.255", 3, 1, "1.2.4.0")
+	t.testAddToSegment("1.2.255.255", 3, 1, "1.3.0.0")
+	t.testAddToSegment("1.2.3.4", 2, -4, "1.1.255.4")
+	t.testAddToSegment("1.2.3.4", 2, 256, "1.3.3.4")
+	t.testAddToSegment("1.2.3.4", 2, 3*65536, "4.2.3.4")
+	t.testAddToSegment("1.2.3.4", 1, 0, "1.2.3.4")
+	t.testAddToSegment("255.255.255.255", 3, 1, "")
+	t.testAddToSegment("0.0.0.0", 0, -1, "")
+	t.testAddToSegment("1.2.3-4.*", 2, 10, "1.2.13-14.*")
+	t.testAddToSegment("1.2.250-255.*", 2, 6, "1.3.0-5.*")
+	t.testAddToSegment("1.2.250-255.*", 2, 10, "1.3.4-9.*")
+	t.testAddToSegment("1.2.250-255.*", 2, 3, "")
+	t.testAddToSegment("1.2.0.0/16", 1, 1, "1.3.0.0/16")
+	t.testAddToSegment("1.2.3.4/24", 3, 257, "1.2.4.5/24")
+	t.testAddToSegment("1.2.3.0/24", 3, 257, "")
+	t.testAddToSegment("1.2.3.4", -1, 1, "")
+	t.testAddToSegment("1.2.3.4", 4, 1, "")
+	t.testAddToSegment("1.2.3.4", 4, 0, "")
+	t.testAddToSegment("1::", 8, 1, "")
+	t.testAddToSegmentZeroSection()
+	t.testAddToSegment("1:2::ffff", 7, 1, "1:2::1:0")
+	t.testAddToSegment("1:2::", 1, -3, "0:ffff::")
+	t.testAddToSegment("ffff:2:3:4:5:6:7:8", 0, 1, "")
+	t.testAddToSegment("1:2::/32", 1, 0x10000, "2:2::/32")
+
+	t.testMatcher([]string{"10.1-3.*.5"}, false, "10.2.200.5", true)
+	t.testMatcher([]string{"10.1-3.*.5"}, false, "10.4.200.5", false)
+	t.testMatcher([]string{"10.1-3.*.5"}, false, "10.2.200.6", false)
+	t.testMatcher([]string{"10.1-3.*.5"}, false, "10.1-2.0-255.5", true)
+	t.testMatcher([]string{"10.1-3.*.5"}, false, "10.1-4.0.5", false)
+	t.testMatcher([]string{"10.1-3.*.5"}, false, "10.2.3.5/16", true)
+	t.testMatcher([]string{"10.1-3.*.5"}, false, "::ffff:10.2.200.5", false)
+	t.testMatcher([]string{"a:b:*::1-ff"}, false, "a:b:c::ff", true)
+	t.testMatcher([]string{"a:b:*::1-ff"}, false, "a:b:c::100", false)
+	t.testMatcher([]string{"a:b:*::1-ff"}, false, "a:b:c:0:0:0:1:1", false)
+	t.testMatcher([]string{"a:b:*::1-ff"}, false, "a:b:c::1%eth0", true)
+	t.testMatcher([]string{"10.0.0.0/8"}, false, "10.255.0.1", true)
+	t.testMatcher([]string{"10.0.0.0/8"}, false, "11.0.0.1", false)
+	t.testMatcher([]string{"*"}, false, "1.2.3.4", true)
+	t.testMatcher([]string{"*"}, false, "1::", true)
+	t.testMatcher([]string{"10.*.*.*", "1::/16"}, false, "1:2::", true)
+	t.testMatcher([]string{"10.*.*.*", "1::/16"}, false, "10.2.3.4", true)
+	t.testMatcher([]string{"10.*.*.*", "1::/16"}, false, "11.2.3.4", false)
+	t.testMatcher([]string{"10.*.*.*", "*.*.*.1-5"}, true, "10.2.3.4", true)
+	t.testMatcher([]string{"10.*.*.*", "*.*.*.1-5"}, true, "10.2.3.6", false)
+	t.testMatcher([]string{"10.*.*.*", "*.*.*.1-5"}, true, "11.2.3.4", false)
+	t.testMatcher([]string{"10.*.*.*", "1::/16"}, true, "10.2.3.4", false)
+	t.testMatcher(nil, false, "1.2.3.4", false)
+	t.testMatcher(nil, true, "1.2.3.4", true)
+	t.testMatcherInvalid("")
+	t.testMatcherInvalid("1.2.3.4.5")
+	t.testMatcherInvalid("fe80::1%eth0")
+	t.testMatcherInvalid("1.2.3.*", "a:b:c:d:e:f:g:h")
+
+	t.testPermutationIterator("1.2.3.4", 1)
+	t.testPermutationIterator("1.2.3.0/31", 1)
+	t.testPermutationIterator("1.2.3.*", 7)
+	t.testPermutationIterator("1.2.3.0/24", -7)
+	t.testPermutationIterator("1.2.0-15.*", 12345)
+	t.testPermutationIterator("1-3.2.3-7.4", 0)
+	t.testPermutationIterator("1:2:3:4:5:6:7:0-1ff", 99)
+	t.testPermutationIterator("1:2:3:4:5:6:1-2:0-2ff", 3)
+	t.testLargePermutationIterator("1::/64", 5)
+	t.testLargePermutationIterator("::/0", 5)
+	t.testLargePermutationIterator("1:*::/24", 5)
+
+	t.testNormalizeIPv6String("1:2:3:4:5:6:7:8")
+	t.testNormalizeIPv6String("1:0:0:4:5:0:0:0")
+	t.testNormalizeIPv6String("1:0:3:4:5:6:7:8")
+	t.testNormalizeIPv6String("0:0:3:4:5:0:0:8")
+	t.testNormalizeIPv6String("0:0:0:0:0:0:0:0")
+	t.testNormalizeIPv6String("00AB:0Cd:000:0:0:00ef:0:1")
+	t.testNormalizeIPv6String("::")
+	t.testNormalizeIPv6String("::1")
+	t.testNormalizeIPv6String("1::")
+	t.testNormalizeIPv6String("1:2:3:4:5:6:7::")
+	t.testNormalizeIPv6String("::2:3:4:5:6:7:8")
+	t.testNormalizeIPv6String("1:0::0:8")
+	t.testNormalizeIPv6String("ffff::ffff:0:0")
+	t.testNormalizeIPv6String(" 1::2 ")
+	t.testNormalizeIPv6String("1::2/64")
+	t.testNormalizeIPv6String("1::*")
+	t.testNormalizeIPv6String("::ffff:1.2.3.4")
+	t.testNormalizeIPv6String("fe80::1%eth0")
+	t.testNormalizeIPv6StringInvalid("")
+	t.testNormalizeIPv6StringInvalid("1")
+	t.testNormalizeIPv6StringInvalid("1.2.3.4")
+	t.testNormalizeIPv6StringInvalid("1:2:3:4:5:6:7:8:9")
+	t.testNormalizeIPv6StringInvalid("1:2:3:4:5:6:7")
+	t.testNormalizeIPv6StringInvalid("1:2:3:4::5:6:7:8")
+	t.testNormalizeIPv6StringInvalid("1::2::3")
+	t.testNormalizeIPv6StringInvalid(":::")
+	t.testNormalizeIPv6StringInvalid(":1::2")
+	t.testNormalizeIPv6StringInvalid("1::2:")
+	t.testNormalizeIPv6StringInvalid("12345::")
+	t.testNormalizeIPv6StringInvalid("g::")
+	t.testStringCaching("1:0:0:4:5:0:0:0/64")
+	t.testWithoutStringCaching("1:0:0:4:5:0:0:0/64")
+	t.testWithoutStringCaching("fe80::1:0:0%eth0")
+	t.testWithoutStringCaching("1.2.0.0/16")
+	t.testWithoutStringCaching("1.2.3-4.*")
+
+	t.testURIZone("fe80::1%25eth0", "eth0", "fe80::1%25eth0")
+	t.testURIZone("fe80::1%eth0", "eth0", "fe80::1%25eth0")
+	t.testURIZone("fe80::1%25en%301", "en01", "fe80::1%25en01")
+	t.testURIZone("fe80::1%25a%2Fb", "a/b", "fe80::1%25a%2Fb")
+	t.testURIZone("fe80::1%25eth0/64", "eth0", "fe80::1%25eth0/64")
+	t.testURIZone("::ffff:1.2.3.4%25eth0", "eth0", "::ffff:102:304%25eth0")
+	t.testURIZone("fe80::1", "", "fe80::1")
+	t.testURIZone("1.2.3.4", "", "1.2.3.4")
+	t.testURIZoneInvalid("fe80::1%25en%3")
+	t.testURIZoneInvalid("fe80::1%25en%3g")
+	t.testURIZoneInvalid("fe80::1%25a:b")
+	t.testURIZoneNoProvider("fe80::1%25eth0", "25eth0")
+	t.testURIZoneNoProvider("fe80::1%eth0", "eth0")
+
+	t.testReverseDNSParse("4.3.2.1.in-addr.arpa", "1.2.3.4")
+	t.testReverseDNSParse("4.3.2.1.IN-ADDR.ARPA.", "1.2.3.4")
+	t.testReverseDNSParse("2.1.in-addr.arpa", "1.2.0.0/16")
+	t.testReverseDNSParse("b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa", "2001:db8::567:89ab")
+	t.testReverseDNSParse("8.b.d.0.1.0.0.2.ip6.arpa", "2001:db8::/32")
+	t.testReverseDNSParse("8.b.d.0.1.0.0.2.ip6.int", "2001:db8::/32")
+	t.testReverseDNSParse("5.4.3.2.1.in-addr.arpa", "")
+	t.testReverseDNSParse("256.3.2.1.in-addr.arpa", "")
+	t.testReverseDNSParse("x.8.b.d.0.1.0.0.2.ip6.arpa", "")
+	t.testReverseDNSParse("1.2.3.4", "1.2.3.4")
+
+	t.testForEachSegmentValue("1.2.3.4")
+	t.testForEachSegmentValue("1.2.3-4.*/16")
+	t.testForEachSegmentValue("1:2:3:4:5-6:*::/64")
+	t.testForEachSegmentValue("1.2.3.0/24")
+	t.testSectionUint64Values("1:2:3:4:5:6:7:8", 0, 8)
+	t.testSectionUint64Values("1:2:3:4:5-6:*::/64", 0, 8)
+	t.testSectionUint64Values("ffff:fffe:fffd:fffc:fffb:fffa:fff9:fff8", 2, 7)
+	t.testSectionUint64Values("1:2:3:4:5:6:7:8", 5, 8)
+	t.testSectionUint64Values("1:2:3:4:5:6:7:8", 3, 3)
+	macValues := ipaddr.NewMACAddressString("1:2:3:4:5:6").GetAddress().SegmentValuesAppend(nil)
+	if len(macValues) != 6 || macValues[0] != 1 || macValues[5] != 6 {
+		t.addFailure(newFailure(fmt.Sprint("MAC segment values ", macValues), nil))
+	}
+	t.testZeroForEachSegmentValue()
+
+	t.testErrorMatching()
+
+	strictParams := new(addrstrparam.IPAddressStringParamsBuilder).Set_inet_aton_strict().ToParams()
+	permissiveParams := new(addrstrparam.IPAddressStringParamsBuilder).Set_inet_aton_strict().Set_inet_aton_permissive().ToParams()
+	noTwoSegmentsParams := new(addrstrparam.IPAddressStringParamsBuilder).GetIPv4AddressParamsBuilder().Allow_inet_aton_joinedSegmentCount(2, false).GetParentBuilder().ToParams()
+	noThreeSegmentsParams := new(addrstrparam.IPAddressStringParamsBuilder).GetIPv4AddressParamsBuilder().Allow_inet_aton_joinedSegmentCount(3, false).GetParentBuilder().ToParams()
+	t.testInetAtonParse("1.2.3.4", strictParams, "1.2.3.4")
+	t.testInetAtonParse("::1.2.3.4", strictParams, "::102:304")
+	t.testInetAtonParse("01.2.3.4", strictParams, "")
+	t.testInetAtonParse("0x1.2.3.4", strictParams, "")
+	t.testInetAtonParse("1.2.3", strictParams, "")
+	t.testInetAtonParse("1.2", strictParams, "")
+	t.testInetAtonParse("16909060", strictParams, "")
+	t.testInetAtonParse("1.2.3.4", permissiveParams, "1.2.3.4")
+	t.testInetAtonParse("0x1.2.3.4", permissiveParams, "1.2.3.4")
+	t.testInetAtonParse("010.2.3.4", permissiveParams, "8.2.3.4")
+	t.testInetAtonParse("0x001.2.3.4", permissiveParams, "1.2.3.4")
+	t.testInetAtonParse("1.2.3", permissiveParams, "1.2.0.3")
+	t.testInetAtonParse("1.2", permissiveParams, "1.0.0.2")
+	t.testInetAtonParse("16909060", permissiveParams, "1.2.3.4")
+	t.testInetAtonParse("1.2.3", noTwoSegmentsParams, "1.2.0.3")
+	t.testInetAtonParse("1.2", noTwoSegmentsParams, "")
+	t.testInetAtonParse("1.2.3", noThreeSegmentsParams, "")
+	t.testInetAtonParse("1.2", noThreeSegmentsParams, "1.0.0.2")
+	t.testInetAtonParse("1.2", ipAddressParamsWithoutJoinedSegmentCount{noTwoSegmentsParams}, "1.0.0.2")
+	t.testInetAtonParse("1.2.3", ipAddressParamsWithoutJoinedSegmentCount{noThreeSegmentsParams}, "1.2.0.3")
+	t.testInetAtonParse("1.2", ipAddressParamsWithoutJoinedSegmentCount{strictParams}, "")
+
+	ipv4Addrs := []*ipaddr.IPv4Address{
+		ipaddr.NewIPAddressString("1.2.3.4").GetAddress().ToIPv4(),
+		nil,
+		ipaddr.NewIPAddressString("1.2.0.0/16").GetAddress().ToIPv4(),
+		ipaddr.NewIPAddressString("1.2.3.4").GetAddress().ToIPv4(),
+		ipaddr.NewIPAddressString("0.0.0.1").GetAddress().ToIPv4(),
+	}
+	testCollection(t, ipv4Addrs, "0.0.0.1", "1.2.0.0/16", []string{"0.0.0.1", "1.2.3.4", "1.2.0.0/16"})
+	testContainsAll(t, ipv4Addrs, []*ipaddr.IPv4Address{ipaddr.NewIPAddressString("1.2.5.0/24").GetAddress().ToIPv4(), nil}, true, true)
+	testContainsAll(t, ipv4Addrs, []*ipaddr.IPv4Address{ipaddr.NewIPAddressString("1.2.5.0/24").GetAddress().ToIPv4(), ipaddr.NewIPAddressString("1.3.0.0").GetAddress().ToIPv4()}, false, true)
+	testContainsAll(t, ipv4Addrs, []*ipaddr.IPv4Address{ipaddr.NewIPAddressString("0.0.0.0-1").GetAddress().ToIPv4()}, false, false)
+	ipAddrs := []*ipaddr.IPAddress{
+		ipaddr.NewIPAddressString("::1").GetAddress(),
+		ipaddr.NewIPAddressString("1.2.3.4").GetAddress(),
+		ipaddr.NewIPAddressString("::1").GetAddress(),
+	}
+	testCollection(t, ipAddrs, "1.2.3.4", "::1", []string{"1.2.3.4", "::1"})
+	testContainsAll(t, ipAddrs, []*ipaddr.IPAddress{ipaddr.NewIPAddressString("::1").GetAddress(), ipaddr.NewIPAddressString("1.2.3.4").GetAddress()}, true, true)
+	macAddrs := []*ipaddr.MACAddress{
+		ipaddr.NewMACAddressString("1:2:3:4:5:6").GetAddress(),
+		ipaddr.NewMACAddressString("1:2:3:4:5:5").GetAddress(),
+	}
+	testCollection(t, macAddrs, "01:02:03:04:05:05", "01:02:03:04:05:06", []string{"01:02:03:04:05:05", "01:02:03:04:05:06"})
+	testCollection(t, []*ipaddr.IPv6Address{nil}, "<nil>", "<nil>", []string{})
+
+	t.testAddressMaskString("1.2.3.0/24", "1.2.3.0 255.255.255.0")
+	t.testAddressMaskString("1.2.3.4/24", "1.2.3.4 255.255.255.0")
+	t.testAddressMaskString("1.2.3.4", "1.2.3.4 255.255.255.255")
+	t.testAddressMaskString("1.2.0.0/15", "1.2.0.0 255.254.0.0")
+	t.testAddressMaskString("1:2::/64", "1:2:: ffff:ffff:ffff:ffff::")
+	t.testAddressMaskStringNoProvider("1.2.3.0 255.255.255.0")
+	t.testZeroAddressMaskString()
+	t.testAddressMaskParse("1.2.3.0 255.255.255.0", "1.2.3.0/24")
+	t.testAddressMaskParse("1.2.3.4  255.255.0.0", "1.2.3.4/16")
+	t.testAddressMaskParse("1.2.3.4 255.0.255.0", "1.0.3.0")
+	t.testAddressMaskParse("1:2:: ffff:ffff::", "1:2::/32")
+	t.testAddressMaskParse("1.2.3.4 24", "")
+	t.testAddressMaskParse("1.2.3.4 ffff::", "")
+	t.testAddressMaskParse("1.2.3.4 255.255.0.0 1", "")
+
+	t.testRelation("1.2.3.4", "1.2.3.4", ipaddr.RelationEqual)
+	t.testRelation("1.2.3.0/24", "1.2.3.4", ipaddr.RelationContains)
+	t.testRelation("1.2.3.4", "1.2.0.0/16", ipaddr.RelationContainedBy)
+	t.testRelation("1.2.3.0-128", "1.2.3.100-200", ipaddr.RelationOverlaps)
+	t.testRelation("1.2.3.0/25", "1.2.3.128/25", ipaddr.RelationAdjacent)
+	t.testRelation("1.2.3.128/25", "1.2.3.0/25", ipaddr.RelationAdjacent)
+	t.testRelation("1.2.3.0/25", "1.2.3.129-255", ipaddr.RelationDisjoint)
+	t.testRelation("1.2.0-1.*", "1.2.2.0", ipaddr.RelationAdjacent)
+	t.testRelation("1-2.1.1.1", "1.1-2.1.1", ipaddr.RelationOverlaps)
+	t.testRelation("1.1-2.1.1", "1.2-3.2.1", ipaddr.RelationDisjoint)
+	t.testRelation("255.255.255.255", "0.0.0.0", ipaddr.RelationDisjoint)
+	t.testRelation("1::/64", "1::1", ipaddr.RelationContains)
+	t.testRelation("1::1%eth0", "1::1%eth1", ipaddr.RelationDisjoint)
+	t.testRelation("::ffff:0:0/96", "0.0.0.0/0", ipaddr.RelationDisjoint)
+
+	t.testGob("1.2.3.4")
+	t.testGob("1.2.3.4/24")
+	t.testGob("1.2.3.0/24")
+	t.testGob("1.2.3-4.5-6/16")
+	t.testGob("1:2::/64")
+	t.testGob("fe80::1%eth0")
+	t.testGob("1:2:*:4::5/48")
+	t.testGob("")
+	t.testGob("*")
+	t.testGobAddr(ipaddr.NewIPAddressString("1.2.3.0/24").GetAddress().GetLower())
+	t.testGobAddr(ipaddr.NewIPAddressString("1::/64").GetAddress().GetLower())
+	t.testGobMAC("1:2:3:4:5:6")
+	t.testGobMAC("1:2:3:*:*:*")
+	t.testGobMAC("1:2:3:4:5:6:7:8")
+	t.testGobMismatch()
+
+	t.testTryMerge("1.2.3.0/25", "1.2.3.128/25", true, "1.2.3.0/24")
+	t.testTryMerge("1.2.3.0-127", "1.2.3.128-130", true, "1.2.3.0-130")
+	t.testTryMerge("1.2.3.0-200", "1.2.3.128-255", false, "1.2.3.*")
+	t.testTryMerge("1.2.3.0/25", "1.2.3.129-255", false, "")
+	t.testTryMerge("1.0-1.0.0-127", "1.0-1.0.128-255", false, "")
+	t.testTryMerge("1.0-1.0.0", "1.0-1.0.1", false, "")
+	t.testTryMerge("1::/65", "1::8000:0:0:0/65", true, "1::/64")
+	t.testTryMerge("1::", "1.2.3.4", false, "")
+	t.testTryMerge("1::1%eth0", "1::1%eth1", false, "")
+	t.testTryMerge("1::1%eth0", "1::2%eth0", true, "1::1-2%eth0")
+	t.testNilTryMerge("1.2.3.4", "1.2.3.4")
+	t.testNilTryMerge("1.2.3.0/24", "1.2.3.0/24")
+	t.testNilTryMerge("1.2.*.4", "")
+	t.testNilTryMerge("1::1%eth0", "1::1%eth0")
+	t.testNilTryMerge("1:*::1", "")
+
+	locators, _ := ipaddr.NewAddressFamily(64, 16, ':', 16)
+	t.testAddressFamily(locators, "1:2:3:4", "1:2:3:4", "", true)
+	t.testAddressFamily(locators, "1:2:ffff:0-ff", "1:2:ffff:0-ff", "1:2:ffff:*", false)
+	t.testAddressFamily(locators, "1:2:*:*/32", "1:2:*:*/32", "1:2:*:*", true)
+	t.testAddressFamily(locators, "1:2:3:4/32", "1:2:3:4/32", "1:2:*:*", true)
+	t.testAddressFamily(locators, "1:2:3:4/40", "1:2:3:4/40", "1:2:3-3:*", false)
+	t.testAddressFamily(locators, "1:2:3:4/40", "1:2:3:4/40", "1:2:0-ff:*", true)
+	t.testAddressFamily(locators, "1:2:3", "", "", false)
+	t.testAddressFamily(locators, "1:2:3:4:5", "", "", false)
+	t.testAddressFamily(locators, "1:2:3:10000", "", "", false)
+	t.testAddressFamily(locators, "1:2:3:g", "", "", false)
+	t.testAddressFamily(locators, "1:2:3:5-4", "", "", false)
+	t.testAddressFamily(locators, "1:2:3:4/65", "", "", false)
+	t.testAddressFamily(locators, "1:2:3:+4", "", "", false)
+	overlay, _ := ipaddr.NewAddressFamily(160, 8, '.', 10)
+	t.testAddressFamily(overlay, "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15.16.17.18.19.20", "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15.16.17.18.19.20", "", true)
+	t.testAddressFamily(overlay, "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15.16.17.18.19.20/150", "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15.16.17.18.19.20/150", "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15.16.17.18.16-19.*", true)
+	t.testAddressFamily(overlay, "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15.16.17.18.19.256", "", "", false)
+	t.testAddressFamily(overlay, "1.2.3.4.5.6.7.8.9.10.11.12.13.14.15.16.17.18.19.ff", "", "", false)
+	t.testAddressFamilyCreation(64, 16, ':', 16, true)
+	t.testAddressFamilyCreation(24, 3, '.', 8, true)
+	t.testAddressFamilyCreation(63, 16, ':', 16, false)
+	t.testAddressFamilyCreation(248, 8, '.', 10, true)
+	t.testAddressFamilyCreation(256, 8, '.', 10, false)
+	t.testAddressFamilyCreation(512, 64, ':', 16, false)
+	t.testAddressFamilyCreation(130, 65, ':', 16, false)
+	t.testAddressFamilyCreation(64, 16, 'a', 16, false)
+	t.testAddressFamilyCreation(64, 16, 'a', 10, true)
+	t.testAddressFamilyCreation(64, 16, '-', 10, false)
+	t.testAddressFamilyCreation(64, 16, ':', 37, false)
+
+	t.testHostBitmap("1.2.3.4", []string{"1.2.3.4"}, []string{"1.2.3.4/32"}, 1)
+	t.testHostBitmap("1.2.0.0", []string{"1.2.3.0/24", "1.2.4.0/24", "1.3.0.0"}, []string{"1.2.3.0/24", "1.2.4.0/24"}, 512)
+	t.testHostBitmap("1.2.0.0", []string{"1.2.3.5-70", "1.2.3.71-255"}, []string{"1.2.3.5/32", "1.2.3.6/31", "1.2.3.8/29", "1.2.3.16/28", "1.2.3.32/27", "1.2.3.64/26", "1.2.3.128/25"}, 251)
+	t.testHostBitmap("1.2.0.0", []string{"1.2.*.1", "::1"}, nil, 256)
+	t.testHostBitmap("1.2.0.0", []string{"1.0.0.0/8"}, []string{"1.2.0.0/16"}, 65536)
+	t.testHostBitmap("1.2.0.0", []string{"*.*.*.1", "*.*.*.2"}, nil, 512)
+	t.testHostBitmap("1:2:3:4:5:6:7:8", []string{"*:*:*:*:*:*:*:1-2"}, []string{"1:2:3:4:5:6:7:1/128", "1:2:3:4:5:6:7:2/128"}, 2)
+	t.testHostBitmap("1:2:3:4:5:6:7:8", []string{"1:2:3:4:5:6:7:0-7f", "1:2:3:4:5:6:8:0/112"}, []string{"1:2:3:4:5:6:7:0/121"}, 128)
+	t.testHostBitmap("1:2:3:4:5:6:7:8", []string{"1:2:3:4:5:6:7:3f-40"}, []string{"1:2:3:4:5:6:7:3f/128", "1:2:3:4:5:6:7:40/128"}, 2)
+
+	t.testPrefixLenArithmetic(p16, 8, 32, p24, false)
+	t.testPrefixLenArithmetic(p16, -16, 32, p0, false)
+	t.testPrefixLenArithmetic(p16, -17, 32, nil, true)
+	t.testPrefixLenArithmetic(p16, 17, 32, nil, true)
+	t.testPrefixLenArithmetic(p16, 112, 128, p128, false)
+	t.testPrefixLenArithmetic(nil, 8, 32, nil, false)
+
+	t.testUniqueLocalPrefix(time.Date(2020, 1, 2, 3, 4, 5, 500000000, time.UTC), "00:12:7f:eb:6b:40", "fdb9:9701:3325::/48")
+	t.testUniqueLocalPrefix(time.Date(2020, 1, 2, 3, 4, 5, 500000000, time.UTC), "00:12:7f:ff:fe:eb:6b:40", "fdb9:9701:3325::/48")
+	t.testNilUniqueLocalPrefix()
+	t.testUniqueLocalGlobalID("fd12:3456:789a::1", "0x123456789a", false)
+	t.testUniqueLocalGlobalID("fc00::", "0x0000000000", false)
+	t.testUniqueLocalGlobalID("fd12:3456:*::", "0x1234560000-0x123456ffff", false)
+	t.testUniqueLocalGlobalID("fd12:3456-3457:*::", "0x1234560000-0x123457ffff", false)
+	t.testUniqueLocalGlobalID("fd12:3456-3457:1::", "", true)
+	t.testUniqueLocalGlobalID("fc00-fdff:*:*::", "0x0000000000-0xffffffffff", false)
+	t.testUniqueLocalGlobalID("fc01-fd00:*:*::", "", true)
+	t.testUniqueLocalGlobalID("fe80::1", "", false)
+
+	t.testDiagnose("1.2.3.4", "", -1)
+	t.testDiagnose("1.2.3.4.5", "ipaddress.error.ipv4.too.many.segments", 8)
+	t.testDiagnose("1:2:3:4:5:6:7:8:9", "ipaddress.error.too.many.segments", 16)
+	t.testDiagnose("1:2:3:4:5:6:7:1.2.3.4", "ipaddress.error.too.many.segments", 14)
+	t.testDiagnose("1.2.3.4/33", "ipaddress.error.prefixSize", -1)
+	t.testDiagnose("1.2.3.4%x", "ipaddress.error.invalid.character.combination.at.index", 8)
+	t.testDiagnose("1.2.3.a", "ipaddress.error.ipv4.invalid.decimal.digit", -1)
+	if testAddressSeqs != nil {
+		testAddressSeqs(t)
+	}
+
+	t.testSubnetLayout("192.168.10.0/24", 2, []uint64{50, 100, 20, 2, 10}, []string{"192.168.10.0/25", "192.168.10.128/26", "192.168.10.192/27", "192.168.10.224/28", "192.168.10.240/30"}, false)
+	t.testSubnetLayout("192.168.10.0/24", 2, []uint64{126, 126, 1}, nil, true)
+	t.testSubnetLayoutErrorValue("192.168.10.0/24", []uint64{1000}, 1000)
+	t.testSubnetLayoutErrorValue("192.168.10.0/24", []uint64{math.MaxInt64 + 1}, math.MaxInt)
+	t.testSubnetLayoutErrorValue("1::/96", []uint64{math.MaxUint64, 1}, math.MaxInt)
+	t.testSubnetLayout("192.168.10.0/24", 0, []uint64{128, 0, 128}, []string{"192.168.10.0/25", "192.168.10.128/25"}, false)
+	t.testSubnetLayout("1::/64", 0, []uint64{1 << 62, 1 << 63}, []string{"1::/65", "1::8000:0:0:0/66"}, false)
+	t.testSubnetPrefixLenLayout("192.168.10.0/24", []ipaddr.BitCount{26, 25, 30, 27}, []string{"192.168.10.0/25", "192.168.10.128/26", "192.168.10.192/27", "192.168.10.224/30"}, false)
+	t.testSubnetPrefixLenLayout("192.168.10.0/24", []ipaddr.BitCount{25, 25, 32}, nil, true)
+	t.testSubnetPrefixLenLayout("192.168.10.0/24", []ipaddr.BitCount{23}, nil, true)
+	t.testSubnetPrefixLenLayout("192.168.10.0/24", []ipaddr.BitCount{33}, nil, true)
+
+	t.testInvertMask("255.255.255.0", "0.0.0.255")
+	t.testInvertMask("255.255.240.0", "0.0.15.255")
+	t.testInvertMask("255.255.240.0/20", "0.0.0-15.*")
+	t.testInvertMask("0.0.0.255", "255.255.255.0")
+	t.testInvertMask("1.2.3.0-15", "254.253.252.240-255")
+	t.testInvertMask("ffff:ffff::", "::ffff:ffff:ffff:ffff:ffff:ffff")
+
+	t.testBitwiseXor("1.2.3.4", "255.255.255.255", "254.253.252.251", false)
+	t.testBitwiseXor("1.2.3.4/24", "0.0.0.5", "1.2.3.1/24", false)
+	t.testBitwiseXor("10.0.0.0-255", "0.0.0.15", "10.0.0.0-255", false)
+	t.testBitwiseXor("10.0.0.16-31", "0.0.0.3", "10.0.0.16-31", false)
+	t.testBitwiseXor("10.0.0.16-31", "0.0.0.32", "10.0.0.48-63", false)
+	t.testBitwiseXor("10.0.0.17-30", "0.0.0.15", "10.0.0.17-30", false)
+	t.testBitwiseXor("10.0.0.1-2", "0.0.0.1", "", true)
+	t.testBitwiseXor("1:2::", "ffff:ffff::", "fffe:fffd::", false)
+	t.testBitwiseXor("1.2.3.4", "::1", "", true)
+	t.testBitwiseXor("10.0.0.0-5", "0.0.0.6", "10.0.0.2-7", false)
+	t.testBitwiseXorSegments(64)
+
+	t.testMaskLen("1.2.3.4", 20, "1.2.0.0", false)
+	t.testMaskLen("1.2.3.4/16", 24, "1.2.3.0/16", false)
+	t.testMaskLen("1.2.3-4.5", 20, "1.2.0.0", false)
+	t.testMaskLen("1.2.3.4", 33, "1.2.3.4", false)
+	t.testMaskLen("1.2.3.5-10", 30, "", true)
+	t.testMaskLen("a:b:c:d::1", 32, "a:b::", false)
+	t.testMaskLen("a:b:c:d::1", 0, "::", false)
+
+	t.testReverseZones("192.0.2.0", "192.0.2.95", []string{"0/26.2.0.192.in-addr.arpa", "64/27.2.0.192.in-addr.arpa"})
+	t.testReverseZones("192.0.2.0", "192.0.3.255", []string{"2.0.192.in-addr.arpa", "3.0.192.in-addr.arpa"})
+	t.testReverseZones("192.0.1.255", "192.0.3.0", []string{"255/32.1.0.192.in-addr.arpa", "2.0.192.in-addr.arpa", "0/32.3.0.192.in-addr.arpa"})
+	t.testReverseZones("10.0.0.0", "10.1.255.255", []string{"0.10.in-addr.arpa", "1.10.in-addr.arpa"})
+	t.testReverseZones("2001:db8::", "2001:db8:7fff:ffff:ffff:ffff:ffff:ffff", []string{
+		"0.8.b.d.0.1.0.0.2.ip6.arpa", "1.8.b.d.0.1.0.0.2.ip6.arpa", "2.8.b.d.0.1.0.0.2.ip6.arpa", "3.8.b.d.0.1.0.0.2.ip6.arpa",
+		"4.8.b.d.0.1.0.0.2.ip6.arpa", "5.8.b.d.0.1.0.0.2.ip6.arpa", "6.8.b.d.0.1.0.0.2.ip6.arpa", "7.8.b.d.0.1.0.0.2.ip6.arpa"})
+
+	t.testPTRRecordName("192.0.2.0", "192.0.2.95", "192.0.2.5", "5.0/26.2.0.192.in-addr.arpa")
+	t.testPTRRecordName("192.0.2.0", "192.0.2.95", "192.0.2.70", "70.64/27.2.0.192.in-addr.arpa")
+	t.testPTRRecordName("192.0.2.0", "192.0.3.255", "192.0.3.7", "7.3.0.192.in-addr.arpa")
+	t.testPTRRecordName("192.0.2.0", "192.0.2.95", "192.0.2.96", "")
+
+	t.testAddressVector(ipaddr.IPv4, []string{"5.6.7.8", "1.2.3.4", "1.2.3.4/24", "5.6.7.8", "1.2.3.4", "0.0.0.1"},
+		[]string{"0.0.0.1", "1.2.3.4/24", "1.2.3.4", "5.6.7.8"})
+	t.testAddressVector(ipaddr.IPv6, []string{"1::2", "::1", "1::2", "::1/64"},
+		[]string{"::1/64", "::1", "1::2"})
+	t.testAddressVector(ipaddr.IPv6, []string{"a::b", "1::2"},
+		[]string{"1::2", "a::b"})
+	t.testAddressVectorPrefixed("1.2.0.0", 16)
+	t.testAddressVectorPrefixed("1::", 64)
+	t.testAddressVectorPrefixed("0.0.0.0", 0)
+	t.testAddressVectorFailure(ipaddr.IPv4, "1.2.3.4", "::1")
+	t.testAddressVectorFailure(ipaddr.IPv4, "1.2.3.4", "1.2.3.*")
+	t.testAddressVectorFailure(ipaddr.IPv6, "::1", "fe80::1%eth0")
+	t.testAddressVectorFailure(ipaddr.IPv6, "::1", "bad::address::")
+
+	t.testRangeSeries("1.2.0.0/16", "1.2.0.0", "1.2.255.255")
+	t.testRangeSeries("1.2.3.*", "1.2.3.0", "1.2.3.255")
+	t.testRangeSeries("1.2.3.4", "1.2.3.4", "1.2.3.4")
+	t.testRangeSeries("1:2::/126", "1:2::", "1:2::3")
+
+	t.testAddressTemplate("10.0.1.2-3", "host-{seg3}-{seg4}.example.com", "host-1-2.example.com", "host-1-3.example.com")
+	t.testAddressTemplate("10.0.1.8-10", "h{index:pad}-{seg4:hex}", "h0-8", "h1-9", "h2-a")
+	t.testAddressTemplate("10.0.1.0-10", "h{index:pad}", "h00", "h01", "h02", "h03", "h04", "h05", "h06", "h07", "h08", "h09", "h10")
+	t.testAddressTemplate("1:2::a-b", "{seg1:padhex}:{seg8:pad} {{{index}}}", "0001:00010 {0}", "0001:00011 {1}")
+	t.testAddressTemplate("aa:bb:cc:dd:ee:f0-f1", "{seg6:padhex}", "f0", "f1")
+	t.testAddressTemplate("1.2.3.4", "plain", "plain")
+	t.testAddressTemplateFormat("10.0.1.0-10", "h{index:pad}", 3, "h03")
+	t.testAddressTemplateFormat("10.0.1.0-10", "h{index:pad}", 123, "h123")
+	t.testAddressTemplateFormat("10.0.1.5", "h{index:pad}", 3, "h3")
+	t.testAddressTemplateFormat("10.0.1.*", "{index:padhex}", 10, "0a")
+	t.testAddressTemplateFormat("1::/64", "{index:pad}", 7, "00000000000000000007")
+	t.testAddressTemplateFailure("{seg3", "1.2.3.4")
+	t.testAddressTemplateFailure("seg3}", "1.2.3.4")
+	t.testAddressTemplateFailure("{seg0}", "1.2.3.4")
+	t.testAddressTemplateFailure("{seg+1}", "1.2.3.4")
+	t.testAddressTemplateFailure("{host}", "1.2.3.4")
+	t.testAddressTemplateFailure("{seg1:oct}", "1.2.3.4")
+	t.testAddressTemplateFailure("{seg5}", "1.2.3.4")
+
+	t.testUsableHosts("1.2.3.4/24", 254, "1.2.3.1/24", "1.2.3.254/24")
+	t.testUsableHosts("1.2.3.0/30", 2, "1.2.3.1/30", "1.2.3.2/30")
+	t.testUsableHosts("1.2.3.0-4/30", 4, "1.2.3.1/30", "1.2.3.6/30")
+	t.testUsableHosts("1.2.3.4/31", 2, "1.2.3.4/31", "1.2.3.5/31")
+	t.testUsableHosts("1.2.3.4/32", 1, "1.2.3.4/32", "1.2.3.4/32")
+	t.testUsableHosts("1.2.3.4", 1, "1.2.3.4", "1.2.3.4")
+	t.testUsableHosts("1.2.3.4-6", 3, "1.2.3.4", "1.2.3.6")
+	t.testUsableHosts("1::/120", 255, "1::1/120", "1::ff/120")
+	t.testUsableHosts("1::/127", 2, "1::/127", "1::1/127")
+	t.testUsableHosts("1::1/128", 1, "1::1/128", "1::1/128")
+	t.testUsableHosts("1::1", 1, "1::1", "1::1")
+
+	t.testContainsNonZeroHosts("1.2.3.1-255", "1.2.3.0/24", true)
+	t.testContainsNonZeroHosts("1.2.3.2-255", "1.2.3.0/24", false)
+	t.testContainsNonZeroHosts("1.2.3.1-255", "1.2.3.0", false)
+	t.testContainsNonZeroHosts("1.2.3.0/24", "1.2.3.0/24", true)
+	t.testContainsNonZeroHosts("1.2.3.1-255", "1.2.3.1/24", true)
+	t.testContainsNonZeroHosts("1.2.1-2.1-255", "1.2.1.0/23", false)
+	t.testContainsNonZeroHosts("1.2.0-1.1-255", "1.2.0-1.0/24", true)
+	t.testContainsNonZeroHosts("1.2.0-1.*", "1.2.0.0/23", true)
+	t.testContainsNonZeroHosts("1.2.0.1-255", "1.2.0.0/23", false)
+	t.testContainsNonZeroHosts("1.2.3.4", "1.2.3.0/24", false)
+	t.testContainsNonZeroHosts("1.2.3.4", "1.2.3.0-4/30", false)
+	t.testContainsNonZeroHosts("1.2.3.1-3", "1.2.3.0/30", true)
+	t.testContainsNonZeroHosts("1::1-ffff", "1::/112", true)
+	t.testContainsNonZeroHosts("1::2-ffff", "1::/112", false)
+	t.testContainsNonZeroHosts("1::1-ffff", "1.2.3.0/24", false)
+
+	t.testBigIntRoundTrip("1:2:3:4:5:6:7:8/64")
+	t.testBigIntRoundTrip("1:2:3:4::/64")
+	t.testBigIntRoundTrip("::/0")
+	t.testBigIntRoundTrip("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff/128")
+	t.testBigIntRoundTrip("::1")
+	t.testBigIntRoundTrip("1.2.3.4/16")
+	t.testBigIntRoundTrip("1.2.0.0/16")
+	t.testBigIntRoundTrip("255.255.255.255")
+	t.testBigIntRoundTrip("0.0.0.0/0")
+	t.testBigIntSectionRoundTrip(0x12345678, 2, 20, "1234:5678/20")
+	t.testBigIntSectionRoundTrip(0x12340000, 2, 16, "1234:0/16")
+	t.testBigIntSectionRoundTrip(0xffff, 1, 16, "ffff/16")
+	t.testBigIntSectionOverflow(new(big.Int).Lsh(bigOneConst(), 32), 2)
+	t.testBigIntSectionOverflow(new(big.Int).Lsh(bigOneConst(), 16), 1)
+	t.testBigIntSectionOverflow(new(big.Int).Add(one28(), bigOneConst()), ipaddr.IPv6SegmentCount)
+	t.testBigIntSectionOverflow(big.NewInt(-1), ipaddr.IPv6SegmentCount)
+
+	t.testHostRanges("1.2.3.5-200/28", false, "1.2.3.0", "1.2.3.16", "1.2.3.32", "1.2.3.48", "1.2.3.64", "1.2.3.80", "1.2.3.96", "1.2.3.112", "1.2.3.128", "1.2.3.144", "1.2.3.160", "1.2.3.176", "1.2.3.192")
+	t.testHostRanges("1.2.3.5-40/28", true, "1.2.3.15", "1.2.3.31", "1.2.3.47")
+	t.testHostRanges("1.2-3.4.5/24", false, "1.2.4.0", "1.3.4.0")
+	t.testHostRanges("1.2-3.4.5/24", true, "1.2.4.255", "1.3.4.255")
+	t.testHostRanges("1.2.3.4/16", false, "1.2.0.0")
+	t.testHostRanges("1.2.3.4-5", false, "0.0.0.0")
+	t.testHostRanges("1.2.3.4-5", true, "255.255.255.255")
+	t.testHostRanges("1.2.3.4-5/32", false, "1.2.3.4 -> 1.2.3.5")
+	t.testHostRanges("1.2.3-4.5/32", true, "1.2.3.5", "1.2.4.5")
+	t.testHostRanges("1::5-40/124", false, "1::", "1::10", "1::20", "1::30", "1::40")
+	t.testHostRanges("1::5-40/124", true, "1::f", "1::1f", "1::2f", "1::3f", "1::4f")
+
+	for _, str := range []string{"1.2.3.4", "1.2.3.4/16", "1.2.*.4", "1.2.3", "1:2::3", "1:2::3/64", "::ffff:1.2.3.4", "1.2.3.256", "", "  1.2.3.4 ", "fe80::1%eth0",
+		"4.3.2.1.in-addr.arpa", "1.2.3.4 255.255.0.0", "*", "a:b:c:d:e:f:a:b:c", "0x01020304", "1.2.3.4:80"} {
+		t.testValidationOnly(str)
+	}
+
+	t.testAnonymize("1.2.3.4", "1.2.3.0")
+	t.testAnonymize("1.2.3.4/16", "1.2.3.0")
+	t.testAnonymize("1.2.3.4/24", "1.2.3.0/24")
+	t.testAnonymize("2001:db8:1:2:3:4:5:6", "2001:db8:1::")
+	t.testAnonymize("2001:db8:1:2:3:4:5:6%eth0", "2001:db8:1::%eth0")
+	t.testAnonymize("1.2.3.0-255", "1.2.3.0")
+	t.testAnonymize("1.2.3-4.5", "1.2.3-4.0")
+
+	t.testPseudonymize("1.2.3.4", "1.2.3.5", 31)
+	t.testPseudonymize("1.2.3.4", "1.2.128.4", 16)
+	t.testPseudonymize("1.2.3.4", "129.2.3.4", 0)
+	t.testPseudonymize("1.2.3.4", "1.2.3.4", 32)
+	t.testPseudonymize("2001:db8::1", "2001:db8::2", 126)
+	t.testPseudonymize("2001:db8::1", "2001:db8:0:1::1", 63)
+
+	t.testFindInterfaceAddress("192.168.1.77", "eth1 192.168.1.5/24")
+	t.testFindInterfaceAddress("10.1.2.3", "eth0 10.1.0.1/16")
+	t.testFindInterfaceAddress("10.2.2.3", "eth2 10.0.0.1/8")
+	t.testFindInterfaceAddress("11.2.2.3", "")
+	t.testFindInterfaceAddress("2001:db8:1::99", "eth0 2001:db8:1::5/64")
+	t.testFindInterfaceAddress("fe80::99", "eth0 fe80::1%eth0/64")
+	t.testFindInterfaceAddress("fe80::99%eth1", "eth1 fe80::2%eth1/64")
+	t.testFindInterfaceAddress("fe80::99%eth3", "")
+	t.testFindInterfaceAddress("127.0.0.1", "lo 127.0.0.1/8")
+	t.testFindInterfaceAddress("1.2.3.4", "")
+	t.testLocalInterfaceAddresses()
+
+	t.testMergeAll([]string{"1::/64", "10.0.0.0/9", "1:0:0:1::/64", "10.128.0.0/9", "", "::/128", "192.168.0.1", "192.168.0.0"},
+		[]string{"10.0.0.0/8", "192.168.0.0/31", "::/128", "1::/63"},
+		[]string{"10.*.*.*", "192.168.0.0-1", "::", "1:0:0:0-1:*:*:*:*"})
+	t.testMergeAll([]string{"1.2.3.4", "1.2.3.5-6", "1.2.3.4"}, []string{"1.2.3.4/31", "1.2.3.6"}, []string{"1.2.3.4-6"})
+	t.testMergeAll([]string{"a:b::c", "a:b::d"}, []string{"a:b::c/127"}, []string{"a:b::c-d"})
+	t.testMergeAll([]string{"", "a:b:c:d:e:f:a:b"}, []string{"a:b:c:d:e:f:a:b"}, []string{"a:b:c:d:e:f:a:b"})
+	t.testMergeAll(nil, nil, nil)
+
+	t.testNilStrings()
+
+	t.testNPTv6("fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405:1::1234", "2001:db8:1:d550::1234")
+	t.testNPTv6("fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405::", "2001:db8:1:d54f::")
+	t.testNPTv6("fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405:a:1:2:3:4/64", "2001:db8:1:d559:1:2:3:4/64")
+	t.testNPTv6("fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405:ffff::1", "")
+	t.testNPTv6("fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:406::1", "")
+	t.testNPTv6("fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405:1::1-2", "")
+	t.testNPTv6("fd01:203:405::/48", "2001:db8:1::/48", "1.2.3.4", "")
+	t.testNPTv6("fd00:1:2:3::/64", "2001:db8:a:b::/64", "fd00:1:2:3::1", "2001:db8:a:b:cf38::1")
+	t.testNPTv6ZeroHost("fd01:203:405::/48", "2001:db8:1::/48", "fd01:203:405:1::", 64, "2001:db8:1:d550::")
+	t.testNPTv6ZeroHost("fd00:1:2:3::/64", "2001:db8:a:b::/64", "fd00:1:2:3::", 64, "2001:db8:a:b:cf38::")
+	t.testNPTv6("fd00:1:2:3::/64", "2001:db8:a:b::/64", "fd00:1:2:3:ffff:ffff::1", "2001:db8:a:b:ffff:ffff:cf38:1")
+	t.testNPTv6("fd00:1:2:3::/64", "2001:db8:a:b::/64", "fd00:1:2:3:ffff:ffff:ffff:ffff", "")
+	t.testNPTv6("fd00:1:2:3::/56", "2001:db8:a:b::/56", "fd00:1:2:3::1", "2001:db8:a:3:cf40::1")
+	t.testNPTv6("fd00:1:2:3::/60", "2001:db8:a:b::/60", "fd00:1:2:7::1", "2001:db8:a:7:cf40::1")
+	t.testNPTv6("fd00:1:2:3::/60", "2001:db8:a:b::/60", "fd00:1:2:f::1", "2001:db8:a:f:cf40::1")
+	t.testNPTv6("fd00:1:2:3::/44", "2001:db8:a:b::/44", "fd00:1:c:7::1", "2001:db8:c:cf4f::1")
+	t.testNPTv6("fd00:1:2:3::/60", "2001:db8:a:b::/60", "fd00:1:2:13::1", "")
+	t.testNPTv6("fd00:1:2::/48", "2001:db8:a::/56", "fd00:1:2::1", "")
+	t.testNPTv6("fd00:1:2::/72", "2001:db8:a::/72", "fd00:1:2::1", "")
+	t.testNPTv6("fd00:1:2::", "2001:db8:a::/48", "fd00:1:2::1", "")
+	t.testNPTv6("fd00:1-2:2::/48", "2001:db8:a::/48", "fd00:1:2::1", "")
+
+	t.testSegmentStringsRadix("1.2.3.4", 2, false, true, []string{"00000001", "00000010", "00000011", "00000100"})
+	t.testSegmentStringsRadix("1.2.3.4", 2, false, false, []string{"1", "10", "11", "100"})
+	t.testSegmentStringsRadix("10.200.3.255", 16, true, true, []string{"0A", "C8", "03", "FF"})
+	t.testSegmentStringsRadix("10.200.3.255", 16, false, false, []string{"a", "c8", "3", "ff"})
+	t.testSegmentStringsRadix("10.200.3.255/16", 8, false, true, []string{"012", "310", "003", "377"})
+	t.testSegmentStringsRadix("1.2-3.*.255", 10, false, true, []string{"001", "002-003", "*", "255"})
+	t.testSegmentStringsRadix("a:b-c:*::ffff", 16, true, true, []string{"000A", "000B-000C", "*", "0000", "0000", "0000", "0000", "FFFF"})
+	t.testSegmentStringsRadix("a:b-c:*::ffff", 10, false, false, []string{"10", "11-12", "*", "0", "0", "0", "0", "65535"})
+	t.testSegmentStringsRadix("1::23", 36, true, false, []string{"1", "0", "0", "0", "0", "0", "0", "Z"})
+	t.testSegmentStringsRadix("1.2.3.4", 1, false, false, nil)
+	t.testSegmentStringsRadix("1.2.3.4", 37, false, false, nil)
+
+	t.testParserCache()
+	t.testCIDRList([]string{"1.2.0.0/16", " a:b::/64 ", "1.2.3.4", "fe80::%eth0/64", "0.0.0.0/0"},
+		[]string{"1.2.0.0/16", "a:b::/64", "1.2.3.4/32", "fe80::/64", "0.0.0.0/0"}, nil)
+	t.testCIDRList([]string{"1.2.0.0/16", "1.2.3.4/16", "", "1.2.*.*", "a:b::/64", "1.2.3-4.0/24", "1.2.3.z"},
+		nil, []int{2, 3, 4, 6, 7})
+	t.testCIDRList(nil, []string{}, nil)
+	t.testDelegationPool()
+	t.testMustParse()
+	t.testIPAddressPort("1.2.3.4:80", "1.2.3.4:80", "1.2.3.4", 80, "")
+	t.testIPAddressPort("[a:b::1%eth0]:443", "[a:b::1%eth0]:443", "a:b::1%eth0", 443, "eth0")
+	t.testIPAddressPort(" [A:B:0::1]:65535", "[a:b::1]:65535", "a:b::1", 65535, "")
+	t.testIPAddressPort("1.2.3.*:1", "1.2.3.*:1", "1.2.3.*", 1, "")
+	t.testIPAddressPort("[a:b::/64]:53", "[a:b::/64]:53", "a:b::/64", 53, "")
+	t.testIPAddressPortInvalid("1.2.3.4")
+	t.testIPAddressPortInvalid("1.2.3.4:http")
+	t.testIPAddressPortInvalid("1.2.3.4:70000")
+	t.testIPAddressPortInvalid("foo.com:80")
+	t.testIPAddressPortInvalid("1.2.3:80:80")
+	t.testIPAddressPortInvalid("")
+
+	t.testParseAll()
+
+	t.testAddressPool()
+	t.testPrefixCounter()
+
+	t.testPartitionIntoN("1.2.3.0", "1.2.3.9", 3, []string{"1.2.3.0 -> 1.2.3.3", "1.2.3.4 -> 1.2.3.6", "1.2.3.7 -> 1.2.3.9"})
+	t.testPartitionIntoN("1.2.3.0", "1.2.3.255", 4, []string{"1.2.3.0 -> 1.2.3.63", "1.2.3.64 -> 1.2.3.127", "1.2.3.128 -> 1.2.3.191", "1.2.3.192 -> 1.2.3.255"})
+	t.testPartitionIntoN("1.2.3.0", "1.2.3.2", 5, []string{"1.2.3.0 -> 1.2.3.0", "1.2.3.1 -> 1.2.3.1", "1.2.3.2 -> 1.2.3.2"})
+	t.testPartitionIntoN("1.2.3.0", "1.2.3.2", 0, []string{"1.2.3.0 -> 1.2.3.2"})
+	t.testPartitionIntoN("::", "::ffff:ffff:ffff:ffff:ffff:ffff:ffff", 2, []string{":: -> 0:7fff:ffff:ffff:ffff:ffff:ffff:ffff", "0:8000:: -> 0:ffff:ffff:ffff:ffff:ffff:ffff:ffff"})
+
+	t.testPartitionByHosts("1.2.3.0/24", 64, []string{"1.2.3.0/26", "1.2.3.64/26", "1.2.3.128/26", "1.2.3.192/26"})
+	t.testPartitionByHosts("1.2.3.0/24", 100, []string{"1.2.3.0/26", "1.2.3.64/26", "1.2.3.128/26", "1.2.3.192/26"})
+	t.testPartitionByHosts("1.2.3.0/24", 1000, []string{"1.2.3.0/24"})
+	t.testPartitionByHosts("1.2.3.0-2", 1, []string{"1.2.3.0/32", "1.2.3.1/32", "1.2.3.2/32"})
+	t.testPartitionByHosts("1.2.3.2-9", 4, []string{"1.2.3.2/31", "1.2.3.4/30", "1.2.3.8/31"})
+	t.testPartitionByHosts("1:2::/120", 128, []string{"1:2::/121", "1:2::80/121"})
+
+	t.testPartitionByMask("10.0.0.0/8", "0.0.0.128", [][]string{{"10.*.*.0-127"}, {"10.*.*.128-255"}})
+	t.testPartitionByMask("10.0.0.0/30", "0.0.0.1", [][]string{{"10.0.0.0", "10.0.0.2"}, {"10.0.0.1", "10.0.0.3"}})
+	t.testPartitionByMask("1.2.3-5.*", "0.0.255.0", [][]string{{"1.2.3.*"}, {"1.2.4.*"}, {"1.2.5.*"}})
+	t.testPartitionByMask("1.2.3.4", "255.0.0.0", [][]string{{"1.2.3.4"}})
+	t.testPartitionByMask("1.2.3.100-200", "0.0.0.128", [][]string{{"1.2.3.100-127"}, {"1.2.3.128-200"}})
+	t.testPartitionByMask("1.2.3.100-200", "0.0.0.0", [][]string{{"1.2.3.100-200"}})
+	t.testPartitionByMask("1.2.0-3.0-3", "0.0.2.1-3", [][]string{
+		{"1.2.0-1.0", "1.2.0-1.2"}, {"1.2.0-1.1", "1.2.0-1.3"}, {"1.2.2-3.0", "1.2.2-3.2"}, {"1.2.2-3.1", "1.2.2-3.3"}})
+	t.testPartitionByMask("a:b::/64", "0:0:0:0:8000::", [][]string{{"a:b::0-7fff:*:*:*"}, {"a:b::8000-ffff:*:*:*"}})
+	t.testPartitionByMask("fe80::%eth0/126", "::1", [][]string{{"fe80::%eth0", "fe80::2%eth0"}, {"fe80::1%eth0", "fe80::3%eth0"}})
+	t.testPartitionByMask("1.2.3.4", "::1", nil)
+
+	t.testConverter(ipaddr.SixToFourAddressConverter{}, "1.2.3.4", "2002:102:304::/48")
+	t.testConverter(ipaddr.SixToFourAddressConverter{}, "1.2.3.*", "2002:102:300-3ff::/48")
+	t.testConverter(ipaddr.DefaultAddressConverter{}, "1.2.3.4", "::ffff:1.2.3.4")
+	t.testConverter(mustNAT64Converter(nil), "192.0.2.33", "64:ff9b::192.0.2.33")
+	t.testConverter(mustNAT64Converter(nil), "192.0.2.*", "64:ff9b::192.0.2.*")
+	t.testConverter(mustNAT64Converter(ipaddr.NewIPAddressString("2001:db8::/32").GetAddress().ToIPv6()), "192.0.2.33", "2001:db8:c000:221::")
+	t.testConverter(mustNAT64Converter(ipaddr.NewIPAddressString("2001:db8:100::/40").GetAddress().ToIPv6()), "192.0.2.33", "2001:db8:1c0:2:21::")
+	t.testConverter(mustNAT64Converter(ipaddr.NewIPAddressString("2001:db8:122::/48").GetAddress().ToIPv6()), "192.0.2.33", "2001:db8:122:c000:2:2100::")
+	t.testConverter(mustNAT64Converter(ipaddr.NewIPAddressString("2001:db8:122:300::/56").GetAddress().ToIPv6()), "192.0.2.33", "2001:db8:122:3c0:0:221::")
+	t.testConverter(mustNAT64Converter(ipaddr.NewIPAddressString("2001:db8:122:344::/64").GetAddress().ToIPv6()), "192.0.2.33", "2001:db8:122:344:c0:2:2100:0")
+	t.testConverter(mustNAT64Converter(ipaddr.NewIPAddressString("2001:db8:122:344::/96").GetAddress().ToIPv6()), "192.0.2.33", "2001:db8:122:344::192.0.2.33")
+	t.testConverter(ipaddr.ChainedAddressConverter{ipaddr.DefaultAddressConverter{}, ipaddr.SixToFourAddressConverter{}}, "1.2.3.4", "::ffff:1.2.3.4")
+	t.testConverterToIPv4(ipaddr.ChainedAddressConverter{ipaddr.DefaultAddressConverter{}, ipaddr.SixToFourAddressConverter{}}, "2002:102:304::1", "1.2.3.4")
+	t.testConverterToIPv4(ipaddr.SixToFourAddressConverter{}, "::ffff:1.2.3.4", "")
+	t.testConverterToIPv4(ipaddr.DefaultAddressConverter{}, "::ffff:1.2.3.4", "1.2.3.4")
+	t.testConverterToIPv4(ipaddr.DefaultAddressConverter{}, "1::1.2.3.4", "")
+	t.testConverterToIPv4(mustNAT64Converter(ipaddr.NewIPAddressString("2001:db8::/32").GetAddress().ToIPv6()), "2001:db8:c000:221:100::", "")
+	t.testConverterToIPv4(mustNAT64Converter(nil), "64:ff9b:1::192.0.2.33", "")
+	t.testNAT64ConverterInvalid("2001:db8::/36")
+	t.testNAT64ConverterInvalid("2001:db8::")
+	t.testNAT64ConverterInvalid("2001:db8-db9::/32")
+
+	t.testContainsRange("1.2.0.0/16", "1.2.3.4", "1.2.5.6", true)
+	t.testContainsRange("1.2.0.0/16", "1.1.255.255", "1.2.5.6", false)
+	t.testContainsRange("1.2-3.*.*", "1.2.255.255", "1.3.0.1", true)
+	t.testContainsRange("1.2.3-4.*", "1.2.3.255", "1.2.4.0", true)
+	t.testContainsRange("1.2-3.3.*", "1.2.3.255", "1.3.3.0", false)
+	t.testContainsRange("1.*.3.*", "1.2.3.4", "1.2.3.5", true)
+	t.testContainsRange("1.*.3.*", "1.2.3.4", "1.2.4.5", false)
+	t.testContainsRange("1:2::/64", "1:2::1", "1:2::ffff", true)
+	t.testContainsRange("1:2::/64", "1.2.3.4", "1.2.3.5", false)
+	t.testContainsRange("1:2:*:3::/64", "1:2:1:3::", "1:2:1:3::ff", true)
+	t.testContainsRange("1:2:*:3::/64", "1:2:1:3::", "1:2:2:3::", false)
+
+	t.testEnclosingPrefixBlock("1.2.3.4", "1.2.3.4", "1.2.3.4/32", true)
+	t.testEnclosingPrefixBlock("1.2.3.0", "1.2.3.255", "1.2.3.0/24", true)
+	t.testEnclosingPrefixBlock("1.2.3.0", "1.2.3.254", "1.2.3.0/24", false)
+	t.testEnclosingPrefixBlock("1.2.3.255", "1.2.4.0", "1.2.0.0/21", false)
+	t.testEnclosingPrefixBlock("1:2::", "1:2::ffff:ffff", "1:2::/96", true)
+	t.testEnclosingPrefixBlock("1:2::1", "1:2::ffff:ffff", "1:2::/96", false)
+
+	t.testEnumerate("1.2.3.4", "1.2.3.4", 0)
+	t.testEnumerate("1.2.3.4", "1.2.3.5", -1)
+	t.testEnumerate("1.2.3.0/24", "1.2.3.5", 5)
+	t.testEnumerate("1.2.3.0/24", "1.2.4.5", -1)
+	t.testEnumerate("1.2.3.0/24", "1.2.3.0/31", -1)
+	t.testEnumerate("1-3.2.3-7.4", "2.2.5.4", 7)
+	t.testEnumerate("1-3.2.3-7.4", "3.2.7.4", 14)
+	t.testEnumerate("1-3.2.3-7.4", "2.2.8.4", -1)
+	t.testEnumerate("1.2.3.0/24", "::1", -1)
+	t.testEnumerate("1:2:3:4:5:6:1-2:0-2ff", "1:2:3:4:5:6:2:100", 0x400)
+	t.testEnumerate("::/0", "::1:2", 0x10002)
+	t.testEnumerate("1::/64", "1::ff:ffff:ffff:ffff", 0xffffffffffffff)
+
+	for _, str := range []string{"1.2.3.4", "1.2-3.*.4/16", "1:2::ff", "a:b:c:d:*::/64"} {
+		addr := ipaddr.NewIPAddressString(str).GetAddress()
+		t.testGenericSegments(addr)
+		t.testGenericSegments(addr.ToAddressBase())
+		t.testGenericSegments(addr.GetSection())
+		t.testGenericSegments(addr.GetSection().ToSectionBase())
+		if addr.IsIPv4() {
+			t.testGenericSegments(addr.ToIPv4())
+			t.testGenericSegments(addr.ToIPv4().GetSection())
+		} else {
+			t.testGenericSegments(addr.ToIPv6())
+			t.testGenericSegments(addr.ToIPv6().GetSection())
+		}
+	}
+	t.testGenericSegments(ipaddr.NewMACAddressString("1:2:3:4:5:6").GetAddress())
+	t.testGenericSegments(ipaddr.NewMACAddressString("1:2:3:4:5:6:7:8").GetAddress().GetSection())
+	t.testGenericSegments(ipaddr.NewMACAddressString("a-b:*:3:4:5:6").GetAddress().ToAddressBase())
+
+	t.testUint32ByteOrder("1.2.3.4", 0x01020304, 0x04030201)
+	t.testUint32ByteOrder("255.0.0.1", 0xff000001, 0x010000ff)
+	t.testUint64ByteOrder("1:2:3:4:5:6:7:8", 0x0001000200030004, 0x0005000600070008, 0x0400030002000100, 0x0800070006000500)
+	t.testUint64ByteOrder("ff00::1", 0xff00000000000000, 1, 0xff, 0x0100000000000000)
+
+	t.testFormatVerb("a:b::c", "%+s", "000a:000b:0000:0000:0000:0000:0000:000c")
+	t.testFormatVerb("a:b::c", "%S", "A:B::C")
+	t.testFormatVerb("a:b::c", "%+S", "000A:000B:0000:0000:0000:0000:0000:000C")
+	t.testFormatVerb("::ffff:1.2.3.4", "%#s", "::ffff:1.2.3.4")
+	t.testFormatVerb("::ffff:1.2.3.4", "%+#s", "0000:0000:0000:0000:0000:ffff:001.002.003.004")
+	t.testFormatVerb("fe80::a%eth0", "%+S", "FE80:0000:0000:0000:0000:0000:0000:000A%eth0")
+	t.testFormatVerb("1.2.3.4", "%+s", "001.002.003.004")
+
+	t.testAddressClassification("8.8.8.8", ipaddr.ClassGlobalUnicast)
+	t.testAddressClassification("0.0.0.0", ipaddr.ClassUnspecified)
+	t.testAddressClassification("0.1.2.3", ipaddr.ClassThisNetwork)
+	t.testAddressClassification("127.0.0.1", ipaddr.ClassLoopback)
+	t.testAddressClassification("172.20.1.1", ipaddr.ClassPrivateUse)
+	t.testAddressClassification("100.100.1.1", ipaddr.ClassSharedAddressSpace)
+	t.testAddressClassification("100.128.1.1", ipaddr.ClassGlobalUnicast)
+	t.testAddressClassification("169.254.3.4", ipaddr.ClassLinkLocal)
+	t.testAddressClassification("198.51.100.7", ipaddr.ClassDocumentation)
+	t.testAddressClassification("198.19.255.255", ipaddr.ClassBenchmarking)
+	t.testAddressClassification("224.0.0.1", ipaddr.ClassMulticast)
+	t.testAddressClassification("255.255.255.255", ipaddr.ClassLimitedBroadcast)
+	t.testAddressClassification("250.1.1.1", ipaddr.ClassReserved)
+	t.testAddressClassification("10.0.0.0/8", ipaddr.ClassPrivateUse)
+	t.testAddressClassification("192.0.0.0/16", ipaddr.ClassMixed)
+	t.testAddressClassification("0.0.0.0/8", ipaddr.ClassThisNetwork)
+	t.testAddressClassification("0.0.0.0/31", ipaddr.ClassThisNetwork)
+	t.testAddressClassification("0.0.0.0/7", ipaddr.ClassMixed)
+	t.testAddressClassification("240.0.0.0/4", ipaddr.ClassReserved)
+	t.testAddressClassification("0.0.0.0/0", ipaddr.ClassMixed)
+	t.testAddressClassification("::", ipaddr.ClassUnspecified)
+	t.testAddressClassification("::1", ipaddr.ClassLoopback)
+	t.testAddressClassification("::ffff:1.2.3.4", ipaddr.ClassIPv4Mapped)
+	t.testAddressClassification("64:ff9b::1.2.3.4", ipaddr.ClassIPv4Translation)
+	t.testAddressClassification("100::1", ipaddr.ClassDiscardOnly)
+	t.testAddressClassification("2001:0:1::1", ipaddr.ClassTeredo)
+	t.testAddressClassification("2001:2::1", ipaddr.ClassBenchmarking)
+	t.testAddressClassification("2001:10::1", ipaddr.ClassIETFProtocolAssignment)
+	t.testAddressClassification("2001:db8::1", ipaddr.ClassDocumentation)
+	t.testAddressClassification("3fff:fff::1", ipaddr.ClassDocumentation)
+	t.testAddressClassification("2002:c000:204::1", ipaddr.Class6To4)
+	t.testAddressClassification("2600::1", ipaddr.ClassGlobalUnicast)
+	t.testAddressClassification("fd00::1", ipaddr.ClassUniqueLocal)
+	t.testAddressClassification("fe80::1%eth0", ipaddr.ClassLinkLocal)
+	t.testAddressClassification("fec0::1", ipaddr.ClassSiteLocal)
+	t.testAddressClassification("ff02::1", ipaddr.ClassMulticast)
+	t.testAddressClassification("4000::1", ipaddr.ClassReserved)
+	t.testAddressClassification("2001::/16", ipaddr.ClassMixed)
+	t.testAddressClassification("2001::/23", ipaddr.ClassIETFProtocolAssignment)
+	t.testAddressClassification("2000::/3", ipaddr.ClassMixed)
+	t.testAddressClassification("::/127", ipaddr.ClassMixed)
+	t.testAddressClassification("::/0", ipaddr.ClassMixed)
+	t.testAddressClassification("2001:db8::/48", ipaddr.ClassDocumentation)
+	t.testFormatVerb("1.2.3.4", "%r", "4.3.2.1.in-addr.arpa")
+	t.testFormatVerb("1.2.3.4", "%v", "1.2.3.4")
+	t.testFormatVerb("2001:db8::567:89ab", "%r", "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa")
+	t.testMACMapSegments("aa:bb:cc:dd:ee:ff", "55:44:33:22:11:0")
+
+	t.testPrefixBlockAndHost("1.2.3.4/16", "1.2.0.0/16", 0x304)
+	t.testPrefixBlockAndHost("1.2.3.4/20", "1.2.0.0/20", 0x304)
+	t.testPrefixBlockAndHost("1.2.3.4/32", "1.2.3.4/32", 0)
+	t.testPrefixBlockAndHost("1.2.3.4", "1.2.3.4", 0x1020304)
+	t.testPrefixBlockAndHost("1:2:3:4:5:6:7:8/64", "1:2:3:4::/64", 0x5000600070008)
+	t.testPrefixBlockAndHost("1:2:3:4:5:6:7:8/120", "1:2:3:4:5:6:7:0/120", 8)
+
 	t.testMask("1.2.3.4", "0.0.2.0", "0.0.2.0")
 	t.testMask("1.2.3.4", "0.0.1.0", "0.0.1.0")
 	t.testMask("A:B:C:D:E:F:A:B", "A:0:C:0:E:0:A:0", "A:0:C:0:E:0:A:0")
@@ -1932,6 +2776,13 @@ func (t ipAddressTester) run() {
 	t.testPrefixBlocks("a:b:c:d:e:f:a:b/127", 128, true, true)
 	t.testPrefixBlocks("a:b:c:d:e:f:a:b/128", 128, true, true)
 
+	t.testAppendBytes("1.2.3.4")
+	t.testAppendBytes("1.2.3-4.*")
+	t.testAppendBytes("a:b:c:d::/64")
+	t.testAppendBytes("1:2:3:4:5:6:7:8")
+	t.testAppendBytes("aa:bb:cc:dd:ee:ff")
+	t.testAppendBytes("aa:bb:cc:*:*:*")
+	t.testAppendBytes("aa:bb:cc:dd:ee:ff:11:22")
 	t.testSplitBytes("1.2.3.4")
 	t.testSplitBytes("1.2.3.4/16")
 	t.testSplitBytes("1.2.3.4/0")
@@ -2493,6 +3344,41 @@ func (t ipAddressTester) run() {
 			addr:  "1::78/126",
 		},
 	})
+
+	t.testAllocatorPrefixLen("192.168.10.0/24", []ipaddr.BitCount{26, 25, 24, 33, 26}, []string{"192.168.10.0/26", "192.168.10.128/25", "", "", "192.168.10.64/26"})
+	t.testAllocatorPrefixLen("1::/64", []ipaddr.BitCount{66, 64, 65}, []string{"1::/66", "", "1::8000:0:0:0/65"})
+}
+
+func (t ipAddressTester) testAllocatorPrefixLen(blockStr string, prefLens []ipaddr.BitCount, expected []string) {
+	block := t.createAddress(blockStr).GetAddress()
+	alloc := ipaddr.IPPrefixBlockAllocator{}
+	alloc.AddAvailable(block)
+	// repeat the cycle of allocating and freeing, which should return the allocator to the same state each time
+	for cycle := 0; cycle < 3; cycle++ {
+		var allocated []*ipaddr.IPAddress
+		for i, prefLen := range prefLens {
+			result := alloc.AllocatePrefixLen(prefLen)
+			if expected[i] == "" {
+				if result != nil {
+					t.addFailure(newIPAddrFailure(fmt.Sprint("allocated ", result, " for prefix length ", prefLen, " expected none"), block))
+				}
+			} else if expectedAddr := t.createAddress(expected[i]).GetAddress(); !result.Equal(expectedAddr) || !result.GetPrefixLen().Equal(expectedAddr.GetPrefixLen()) {
+				t.addFailure(newIPAddrFailure(fmt.Sprint("allocated ", result, " for prefix length ", prefLen, " expected ", expectedAddr), block))
+			} else {
+				allocated = append(allocated, result)
+			}
+		}
+		if count := alloc.GetBlockCount(); count != len(alloc.GetAvailable()) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("block count after allocating was ", count, " in cycle ", cycle), block))
+		}
+		alloc.Free(allocated...)
+		if available := alloc.GetAvailable(); len(available) != 1 || !available[0].Equal(block) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("available after free was ", available, " in cycle ", cycle), block))
+		} else if count := alloc.GetBlockCount(); count != 1 {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("block count after free was ", count, " in cycle ", cycle), block))
+		}
+	}
+	t.incrementTestCount()
 }
 
 func one28() *big.Int {
@@ -2523,6 +3409,147 @@ func (t ipAddressTester) testAllocatorLen(blocksStrs []string, bitLengths []ipad
 
 }
 
+func (t ipAddressTester) testSubnetLayout(parentStr string, reservedCount int, hostCounts []uint64, expected []string, expectError bool) {
+	parent := t.createAddress(parentStr).GetAddress()
+	allocated, err := ipaddr.AllocateSubnets(parent, reservedCount, hostCounts...)
+	t.checkSubnetLayout(parent, allocated, err, expected, expectError)
+	if parent.IsIPv4() {
+		allocated4, err4 := ipaddr.AllocateSubnets(parent.ToIPv4(), reservedCount, hostCounts...)
+		if (err4 == nil) != (err == nil) || len(allocated4) != len(allocated) {
+			t.addFailure(newIPAddrFailure("IPv4 layout mismatch", parent))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testSubnetLayoutErrorValue(parentStr string, hostCounts []uint64, expectedValue int) {
+	parent := t.createAddress(parentStr).GetAddress()
+	var valueErr addrerr.ValueError
+	if _, err := ipaddr.AllocateSubnets(parent, 2, hostCounts...); !errors.As(err, &valueErr) {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("expected value error, got ", err), parent))
+	} else if value := valueErr.GetValue(); value != expectedValue {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("error value was ", value, ", expected ", expectedValue), parent))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testSubnetPrefixLenLayout(parentStr string, prefixLens []ipaddr.BitCount, expected []string, expectError bool) {
+	parent := t.createAddress(parentStr).GetAddress()
+	allocated, err := ipaddr.AllocateSubnetPrefixLens(parent, prefixLens...)
+	t.checkSubnetLayout(parent, allocated, err, expected, expectError)
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) checkSubnetLayout(parent *ipaddr.IPAddress, allocated []ipaddr.AllocatedBlock[*ipaddr.IPAddress], err error, expected []string, expectError bool) {
+	if expectError {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("unexpected layout "+fmt.Sprint(allocated), parent))
+		}
+		return
+	} else if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected layout error "+err.Error(), parent))
+		return
+	} else if len(allocated) != len(expected) {
+		t.addFailure(newIPAddrFailure("layout was "+fmt.Sprint(allocated), parent))
+		return
+	}
+	for i, block := range allocated {
+		expectedBlock := t.createAddress(expected[i]).GetAddress()
+		if !block.GetAddress().Equal(expectedBlock) || !parent.Contains(block.GetAddress()) {
+			t.addFailure(newIPAddrFailure("layout block was "+block.GetAddress().String()+" not "+expected[i], parent))
+		} else if i > 0 && block.GetAddress().Intersect(allocated[i-1].GetAddress()) != nil {
+			t.addFailure(newIPAddrFailure("layout blocks overlap "+block.GetAddress().String(), parent))
+		}
+	}
+}
+
+func (t ipAddressTester) testInvertMask(original, expected string) {
+	addr := ipaddr.NewIPAddressString(original).GetAddress()
+	expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress()
+	inverted := addr.InvertMask()
+	if !inverted.Equal(expectedAddr) || inverted.IsPrefixed() {
+		t.addFailure(newIPAddrFailure("inverted mask was "+inverted.String()+" not "+expected, addr))
+	} else if !inverted.InvertMask().Equal(addr) {
+		t.addFailure(newIPAddrFailure("inverted mask did not revert "+inverted.InvertMask().String(), addr))
+	} else if !addr.GetSection().InvertMask().Equal(expectedAddr.GetSection()) {
+		t.addFailure(newIPAddrFailure("inverted section was "+addr.GetSection().InvertMask().String(), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testBitwiseXor(original, mask, expected string, expectError bool) {
+	addr := ipaddr.NewIPAddressString(original).GetAddress()
+	maskAddr := ipaddr.NewIPAddressString(mask).GetAddress()
+	result, err := addr.BitwiseXor(maskAddr)
+	sectResult, sectErr := addr.GetSection().BitwiseXor(maskAddr.GetSection())
+	if expectError {
+		if err == nil || sectErr == nil {
+			t.addFailure(newIPAddrFailure("unexpected xor result "+result.String(), addr))
+		}
+	} else if err != nil || sectErr != nil {
+		t.addFailure(newIPAddrFailure("unexpected xor error with "+mask, addr))
+	} else {
+		expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress()
+		if !result.Equal(expectedAddr) || !result.GetPrefixLen().Equal(expectedAddr.GetPrefixLen()) {
+			t.addFailure(newIPAddrFailure("xor result was "+result.String()+" not "+expected, addr))
+		} else if !sectResult.Equal(result.GetSection()) {
+			t.addFailure(newIPAddrFailure("xor section result was "+sectResult.String(), addr))
+		} else if reverted, _ := result.BitwiseXor(maskAddr); !reverted.Equal(addr) {
+			t.addFailure(newIPAddrFailure("xor did not revert "+reverted.String(), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+// testBitwiseXorSegments compares the exclusive disjunction of every single-segment range and mask below the given limit with the brute-force result
+func (t ipAddressTester) testBitwiseXorSegments(limit ipaddr.IPv4SegInt) {
+	for mask := ipaddr.IPv4SegInt(0); mask < limit; mask++ {
+		maskSection := ipaddr.NewIPv4Section([]*ipaddr.IPv4AddressSegment{ipaddr.NewIPv4Segment(mask)})
+		for lower := ipaddr.IPv4SegInt(0); lower < limit; lower++ {
+			for upper := lower; upper < limit; upper++ {
+				section := ipaddr.NewIPv4Section([]*ipaddr.IPv4AddressSegment{ipaddr.NewIPv4RangeSegment(lower, upper)})
+				resultLower, resultUpper := ^ipaddr.IPv4SegInt(0), ipaddr.IPv4SegInt(0)
+				for val := lower; val <= upper; val++ {
+					if xored := val ^ mask; xored < resultLower {
+						resultLower = xored
+					}
+					if xored := val ^ mask; xored > resultUpper {
+						resultUpper = xored
+					}
+				}
+				sequential := resultUpper-resultLower == upper-lower
+				result, err := section.BitwiseXor(maskSection)
+				if sequential != (err == nil) {
+					t.addFailure(newFailure(fmt.Sprint("xor of ", section, " with ", mask, " sequential mismatch, error: ", err), nil))
+				} else if sequential && (result.GetSegment(0).GetIPv4SegmentValue() != resultLower || result.GetSegment(0).GetIPv4UpperSegmentValue() != resultUpper) {
+					t.addFailure(newFailure(fmt.Sprint("xor of ", section, " with ", mask, " was ", result, " not ", resultLower, "-", resultUpper), nil))
+				}
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMaskLen(original string, prefLen ipaddr.BitCount, expected string, expectError bool) {
+	addr := ipaddr.NewIPAddressString(original).GetAddress()
+	result, err := addr.MaskLen(prefLen)
+	if expectError {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("unexpected masked result "+result.String(), addr))
+		}
+	} else if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected mask error "+err.Error(), addr))
+	} else {
+		expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress()
+		if !result.Equal(expectedAddr) || !result.GetPrefixLen().Equal(expectedAddr.GetPrefixLen()) {
+			t.addFailure(newIPAddrFailure("masked result was "+result.String()+" not "+expected, addr))
+		} else if masked, _ := addr.Mask(addr.GetNetwork().GetNetworkMask(prefLen)); !masked.Equal(result) {
+			t.addFailure(newIPAddrFailure("masked result was "+result.String()+" not "+masked.String(), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
 // PrefixBlockAllocator[T PrefixBlockConstraint[T]]
 
 func testAllocatorLen[T ipaddr.PrefixBlockConstraint[T]](t ipAddressTester, blocks []T, bitLengths []ipaddr.BitCount, expected []struct {
@@ -4099,6 +5126,85 @@ func (t ipAddressTester) testCanonical(original, expected string) {
 	t.incrementTestCount()
 }
 
+func (t ipAddressTester) testCompressRun(original string, runSelection addrstr.CompressionRunOptions, minCount int, expected string) {
+	w := t.createAddress(original)
+	val := w.GetAddress().ToIPv6()
+	compressOpts := new(addrstr.CompressOptionsBuilder).SetCompressionRunOptions(runSelection).SetMinCompressCount(minCount).ToOptions()
+	compressed, err := val.ToCustomString(new(addrstr.IPv6StringOptionsBuilder).SetCompressOptions(compressOpts).ToOptions())
+	if err != nil {
+		t.addFailure(newIPAddrFailure("ToCustomString errored with error: "+err.Error(), val.ToIP()))
+	} else if compressed != expected {
+		t.addFailure(newFailure("compressed was "+compressed+" expected was "+expected, w))
+	}
+
+	// options that do not provide the run options compress the longest leftmost range, like the default options
+	defaultOpts := new(addrstr.IPv6StringOptionsBuilder).SetCompressOptions(new(addrstr.CompressOptionsBuilder).ToOptions()).ToOptions()
+	runlessOpts := new(addrstr.IPv6StringOptionsBuilder).SetCompressOptions(compressOptionsWithoutRuns{compressOpts}).ToOptions()
+	if expectedDefault, err := val.ToCustomString(defaultOpts); err != nil {
+		t.addFailure(newIPAddrFailure("ToCustomString errored with error: "+err.Error(), val.ToIP()))
+	} else if compressed, err = val.ToCustomString(runlessOpts); err != nil {
+		t.addFailure(newIPAddrFailure("ToCustomString errored with error: "+err.Error(), val.ToIP()))
+	} else if compressed != expectedDefault {
+		t.addFailure(newFailure("compressed without run options was "+compressed+" expected was "+expectedDefault, w))
+	}
+	t.incrementTestCount()
+}
+
+// compressOptionsWithoutRuns hides the CompressionRunOptionsProvider methods of the wrapped options
+type compressOptionsWithoutRuns struct {
+	addrstr.CompressOptions
+}
+
+func (t ipAddressTester) testParamsSerialization(params addrstrparam.HostNameParams, str string, expectValid bool) {
+	hostStr := ipaddr.NewHostNameParams(str, params)
+	encoded, err := addrstrparam.MarshalHostNameParams(params)
+	if err != nil {
+		t.addFailure(newHostFailure("MarshalHostNameParams errored with error: "+err.Error(), hostStr))
+		t.incrementTestCount()
+		return
+	}
+	decoded, err := addrstrparam.UnmarshalHostNameParams(encoded)
+	if err != nil {
+		t.addFailure(newHostFailure("UnmarshalHostNameParams errored with error: "+err.Error()+" for "+string(encoded), hostStr))
+		t.incrementTestCount()
+		return
+	}
+	if reencoded, _ := addrstrparam.MarshalHostNameParams(decoded); !bytes.Equal(encoded, reencoded) {
+		t.addFailure(newHostFailure("params encoded as "+string(reencoded)+" after round trip, originally "+string(encoded), hostStr))
+	} else if params == defaultHostOptions && string(encoded) != "{}" {
+		t.addFailure(newHostFailure("default params encoded as "+string(encoded), hostStr))
+	}
+	decodedHost := ipaddr.NewHostNameParams(str, decoded)
+	if hostStr.IsValid() != expectValid {
+		t.addFailure(newHostFailure("validity mismatch with original params, expected "+strconv.FormatBool(expectValid), hostStr))
+	} else if decodedHost.IsValid() != expectValid {
+		t.addFailure(newHostFailure("validity mismatch with round-tripped params, expected "+strconv.FormatBool(expectValid), decodedHost))
+	}
+
+	ipEncoded, _ := addrstrparam.MarshalIPAddressStringParams(params.GetIPAddressParams())
+	if ipDecoded, err := addrstrparam.UnmarshalIPAddressStringParams(ipEncoded); err != nil {
+		t.addFailure(newHostFailure("UnmarshalIPAddressStringParams errored with error: "+err.Error(), hostStr))
+	} else if ipReencoded, _ := addrstrparam.MarshalIPAddressStringParams(ipDecoded); !bytes.Equal(ipEncoded, ipReencoded) {
+		t.addFailure(newHostFailure("IP params encoded as "+string(ipReencoded)+" after round trip, originally "+string(ipEncoded), hostStr))
+	}
+
+	if _, err := addrstrparam.UnmarshalHostNameParams([]byte(`{"unknownSetting":true}`)); err == nil {
+		t.addFailure(newHostFailure("unknown setting was accepted", hostStr))
+	}
+	if _, err := addrstrparam.UnmarshalHostNameParams([]byte(`{"preferredVersion":"IPv5"}`)); err == nil {
+		t.addFailure(newHostFailure("invalid IP version was accepted", hostStr))
+	}
+	// the keys are lower camel case throughout
+	if ipDecoded, err := addrstrparam.UnmarshalIPAddressStringParams([]byte(`{"ipv4Params":{"inetAtonHex":false,"inetAtonTwoSegments":false,"leadingZeros":false}}`)); err != nil {
+		t.addFailure(newHostFailure("camel case keys were not accepted: "+err.Error(), hostStr))
+	} else if ipv4Params := ipDecoded.GetIPv4Params(); ipv4Params.Allows_inet_aton_hex() || ipv4Params.AllowsLeadingZeros() ||
+		ipv4Params.(addrstrparam.JoinedSegmentCountProvider).Allows_inet_aton_joinedSegmentCount(2) ||
+		!ipv4Params.(addrstrparam.JoinedSegmentCountProvider).Allows_inet_aton_joinedSegmentCount(3) {
+		t.addFailure(newHostFailure("camel case keys were not applied", hostStr))
+	}
+	t.incrementTestCount()
+}
+
 func (t ipAddressTester) testMixed(original, expected string) {
 	t.testMixedNoComp(original, expected, expected)
 }
@@ -4560,6 +5666,47 @@ func (t ipAddressTester) testMaxHost(addrString, maxHostString string) {
 	t.incrementTestCount()
 }
 
+type appendBytesTestItem interface {
+	Bytes() []byte
+	UpperBytes() []byte
+	AppendBytes([]byte) []byte
+	AppendUpperBytes([]byte) []byte
+}
+
+func (t ipAddressTester) testAppendBytes(addrStr string) {
+	var items []appendBytesTestItem
+	if ipAddr := ipaddr.NewIPAddressString(addrStr).GetAddress(); ipAddr != nil {
+		items = []appendBytesTestItem{ipAddr, ipAddr.ToAddressBase(), ipAddr.GetSection(), ipAddr.GetSection().ToSectionBase()}
+		if ipv4Addr := ipAddr.ToIPv4(); ipv4Addr != nil {
+			items = append(items, ipv4Addr, ipv4Addr.GetSection())
+		} else if ipv6Addr := ipAddr.ToIPv6(); ipv6Addr != nil {
+			items = append(items, ipv6Addr, ipv6Addr.GetSection())
+		}
+	} else {
+		macAddr := ipaddr.NewMACAddressString(addrStr).GetAddress()
+		items = []appendBytesTestItem{macAddr, macAddr.ToAddressBase(), macAddr.GetSection()}
+	}
+	for _, item := range items {
+		prefix := []byte{0xfe}
+		expected := append(append([]byte{}, prefix...), item.Bytes()...)
+		expectedUpper := append(append([]byte{}, prefix...), item.UpperBytes()...)
+		if result := item.AppendBytes(prefix); !bytes.Equal(result, expected) {
+			t.addFailure(newFailure(fmt.Sprint("appended bytes ", result, " of ", addrStr, " do not match expected ", expected), nil))
+		} else if result = item.AppendUpperBytes(prefix); !bytes.Equal(result, expectedUpper) {
+			t.addFailure(newFailure(fmt.Sprint("appended upper bytes ", result, " of ", addrStr, " do not match expected ", expectedUpper), nil))
+		}
+		buf := make([]byte, 0, ipaddr.IPv6ByteCount)
+		allocs := testing.AllocsPerRun(10, func() {
+			buf = item.AppendBytes(buf[:0])
+			buf = item.AppendUpperBytes(buf[:0])
+		})
+		if allocs != 0 {
+			t.addFailure(newFailure(fmt.Sprint("appending bytes of ", addrStr, " allocated ", allocs, " times"), nil))
+		}
+		t.incrementTestCount()
+	}
+}
+
 func (t ipAddressTester) testSplitBytes(addressStr string) {
 	addr := t.createAddress(addressStr).GetAddress()
 	t.testSplitBytesAddr(addr)
@@ -4762,6 +5909,2816 @@ func (t ipAddressTester) testPrefixBlocks(
 	t.incrementTestCount()
 }
 
+func (t ipAddressTester) testContainsPrefixBlockBySegment(orig string, prefLen ipaddr.BitCount, expected string) {
+	addr := ipaddr.NewIPAddressString(orig).GetAddress()
+	var result strings.Builder
+	allTrue := true
+	for _, spans := range addr.ContainsPrefixBlockBySegment(prefLen) {
+		if spans {
+			result.WriteByte('1')
+		} else {
+			result.WriteByte('0')
+			allTrue = false
+		}
+	}
+	if result.String() != expected {
+		t.addFailure(newIPAddrFailure("segment prefix blocks were "+result.String()+" expected: "+expected, addr))
+	} else if allTrue != addr.ContainsPrefixBlock(prefLen) {
+		t.addFailure(newIPAddrFailure("segment prefix blocks "+result.String()+" mismatch ContainsPrefixBlock for "+strconv.Itoa(int(prefLen)), addr))
+	} else if sectionResult := addr.GetSection().ContainsPrefixBlockBySegment(prefLen); len(sectionResult) != len(expected) {
+		t.addFailure(newIPAddrFailure("section segment prefix blocks count was "+strconv.Itoa(len(sectionResult)), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testParseVersion(str string, expected ipaddr.IPVersion, isValid bool) {
+	version, err := ipaddr.ParseVersion(str)
+	if (err == nil) != isValid {
+		t.addFailure(newFailure("unexpected parse result "+fmt.Sprint(err)+" for version "+str, nil))
+	} else if err != nil && !strings.Contains(err.Error(), "invalid IP version") {
+		t.addFailure(newFailure("unexpected error "+err.Error()+" for version "+str, nil))
+	} else if version != expected {
+		t.addFailure(newFailure("parsed version "+version.String()+" does not match expected "+expected.String(), nil))
+	} else if isValid {
+		encoded, err := json.Marshal(version)
+		if err != nil {
+			t.addFailure(newFailure("failed to marshal version "+version.String()+": "+err.Error(), nil))
+		} else {
+			var unmarshalled ipaddr.IPVersion
+			if err = json.Unmarshal(encoded, &unmarshalled); err != nil {
+				t.addFailure(newFailure("failed to unmarshal version "+string(encoded)+": "+err.Error(), nil))
+			} else if unmarshalled != version {
+				t.addFailure(newFailure("unmarshalled version "+unmarshalled.String()+" does not match "+version.String(), nil))
+			}
+		}
+	} else {
+		var unmarshalled ipaddr.IPVersion
+		if json.Unmarshal([]byte(strconv.Quote(str)), &unmarshalled) == nil {
+			t.addFailure(newFailure("unmarshalled invalid version "+str, nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testVersions() {
+	versions := ipaddr.Versions()
+	if len(versions) != 2 || !versions[0].IsIPv4() || !versions[1].IsIPv6() {
+		t.addFailure(newFailure("unexpected versions "+fmt.Sprint(versions), nil))
+	}
+	for _, version := range versions {
+		if version.GetBitCount() != version.GetBitsPerSegment()*ipaddr.BitCount(version.GetSegmentCount()) ||
+			version.GetByteCount() != version.GetBytesPerSegment()*version.GetSegmentCount() {
+			t.addFailure(newFailure("inconsistent counts for version "+version.String(), nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testTeredo(addrStr, serverStr, clientStr string, flags, port uint16) {
+	addr := t.createAddress(addrStr).GetAddress().ToIPv6()
+	server := t.createAddress(serverStr).GetAddress().ToIPv4()
+	client := t.createAddress(clientStr).GetAddress().ToIPv4()
+	components := addr.GetTeredoComponents()
+	if components == nil {
+		t.addFailure(newIPAddrFailure("no Teredo components", addr.ToIP()))
+	} else if !components.Server.Equal(server) || !components.Client.Equal(client) || components.Flags != flags || components.Port != port {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("Teredo components %v %v %x %d do not match expected %v %v %x %d",
+			components.Server, components.Client, components.Flags, components.Port, server, client, flags, port), addr.ToIP()))
+	} else if reconstructed := ipaddr.NewIPv6AddressFromTeredo(*components); !reconstructed.Equal(addr) {
+		t.addFailure(newIPAddrFailure("Teredo address reconstructed as "+reconstructed.String(), addr.ToIP()))
+	} else if t.createAddress("2002::1").GetAddress().ToIPv6().GetTeredoComponents() != nil {
+		t.addFailure(newIPAddrFailure("Teredo components for non-Teredo address", addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testEmbeddedIPv4(addrStr, expectedMapped, expectedCompatible string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress().ToIPv6()
+	toIPv4String := func(str string) string {
+		if str == "" {
+			return (*ipaddr.IPv4Address)(nil).String()
+		}
+		return ipaddr.NewIPAddressString(str).GetAddress().ToIPv4().String()
+	}
+	mapped, err := addr.GetIPv4MappedAddress()
+	if err != nil || mapped.String() != toIPv4String(expectedMapped) || (mapped != nil) != addr.IsIPv4Mapped() {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("IPv4-mapped address ", mapped, " ", err, " does not match expected ", expectedMapped), addr.ToIP()))
+	}
+	compatible, err := addr.GetIPv4CompatibleAddress()
+	if err != nil || compatible.String() != toIPv4String(expectedCompatible) || (compatible != nil) != addr.IsIPv4Compatible() {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("IPv4-compatible address ", compatible, " ", err, " does not match expected ", expectedCompatible), addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) test6rd(addrStr, prefixStr string, ipv4MaskLen ipaddr.BitCount, domainStr, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress().ToIPv6()
+	prefix := ipaddr.NewIPAddressString(prefixStr).GetAddress().ToIPv6()
+	var domain *ipaddr.IPv4Address
+	if domainStr != "" {
+		domain = ipaddr.NewIPAddressString(domainStr).GetAddress().ToIPv4()
+	}
+	embedded := addr.Get6rdIPv4Address(prefix, ipv4MaskLen, domain)
+	if expected == "" {
+		if embedded != nil || addr.Is6rd(prefix, ipv4MaskLen) {
+			t.addFailure(newIPAddrFailure("unexpected 6rd IPv4 address "+embedded.String()+" with prefix "+prefixStr, addr.ToIP()))
+		}
+	} else if !addr.Is6rd(prefix, ipv4MaskLen) {
+		t.addFailure(newIPAddrFailure("not 6rd with prefix "+prefixStr, addr.ToIP()))
+	} else if embedded.String() != expected {
+		t.addFailure(newIPAddrFailure("6rd IPv4 address "+embedded.String()+" does not match expected "+expected, addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) test6To4(ipv4Str string, subnetID uint16, interfaceID uint64, expected string) {
+	ipv4 := t.createAddress(ipv4Str).GetAddress().ToIPv4()
+	addr := ipaddr.NewIPv6AddressFrom6To4(ipv4, subnetID, interfaceID)
+	expectedAddr := t.createAddress(expected).GetAddress().ToIPv6()
+	if !addr.Equal(expectedAddr) {
+		t.addFailure(newIPAddrFailure("6to4 address "+addr.String()+" does not match expected "+expected, ipv4.ToIP()))
+	} else if !addr.Is6To4() {
+		t.addFailure(newIPAddrFailure("constructed address "+addr.String()+" is not 6to4", ipv4.ToIP()))
+	} else if embedded, err := addr.Get6To4IPv4Address(); err != nil || !embedded.Equal(ipv4) {
+		t.addFailure(newIPAddrFailure("6to4 address "+addr.String()+" embeds "+embedded.String(), ipv4.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testSolicitedNodeMulticast(addrStr, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress().ToIPv6()
+	result, err := addr.ToSolicitedNodeMulticast()
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("expected error for solicited-node multicast, got "+result.String(), addr.ToIP()))
+		}
+	} else if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error for solicited-node multicast: "+err.Error(), addr.ToIP()))
+	} else if expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress().ToIPv6(); !result.Equal(expectedAddr) || result.GetZone() != expectedAddr.GetZone() {
+		t.addFailure(newIPAddrFailure("solicited-node multicast "+result.String()+" does not match expected "+expected, addr.ToIP()))
+	} else if result.IsPrefixed() {
+		t.addFailure(newIPAddrFailure("solicited-node multicast "+result.String()+" is prefixed", addr.ToIP()))
+	} else if !result.IsMulticast() {
+		t.addFailure(newIPAddrFailure("solicited-node multicast "+result.String()+" is not multicast", addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testIPv6MulticastMAC(addrStr, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress().ToIPv6()
+	result, err := addr.ToMulticastMAC()
+	t.checkMulticastMAC(addr.ToIP(), result, err, expected)
+}
+
+func (t ipAddressTester) testIPv4MulticastMAC(addrStr, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress().ToIPv4()
+	result, err := addr.ToMulticastMAC()
+	t.checkMulticastMAC(addr.ToIP(), result, err, expected)
+}
+
+func (t ipAddressTester) checkMulticastMAC(addr *ipaddr.IPAddress, result *ipaddr.MACAddress, err error, expected string) {
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("expected error for multicast MAC, got "+result.String(), addr))
+		}
+	} else if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error for multicast MAC: "+err.Error(), addr))
+	} else if expectedAddr := ipaddr.NewMACAddressString(expected).GetAddress(); !result.Equal(expectedAddr) {
+		t.addFailure(newIPAddrFailure("multicast MAC "+result.String()+" does not match expected "+expected, addr))
+	} else if !result.IsMulticast() {
+		t.addFailure(newIPAddrFailure("multicast MAC "+result.String()+" is not multicast", addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPermutationIterator(addrStr string, seed int64) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	expected := make(map[string]bool)
+	var inOrder []string
+	for iter := addr.Iterator(); iter.HasNext(); {
+		str := iter.Next().String()
+		expected[str] = true
+		inOrder = append(inOrder, str)
+	}
+	var permuted []string
+	for iter := addr.PermutationIterator(seed); iter.HasNext(); {
+		next := iter.Next()
+		if next == nil || !expected[next.String()] {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected permutation element %v", next), addr))
+			break
+		}
+		delete(expected, next.String())
+		permuted = append(permuted, next.String())
+	}
+	if len(expected) > 0 || len(permuted) != len(inOrder) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("permutation %v does not match iteration %v", permuted, inOrder), addr))
+	} else if len(inOrder) > 16 && reflect.DeepEqual(permuted, inOrder) {
+		t.addFailure(newIPAddrFailure("permutation matches iteration order", addr))
+	} else {
+		var again []string
+		for iter := addr.GetSection().PermutationIterator(seed); iter.HasNext(); {
+			again = append(again, iter.Next().String())
+		}
+		var expectedSections []string
+		for _, str := range permuted {
+			expectedSections = append(expectedSections, ipaddr.NewIPAddressString(str).GetAddress().GetSection().String())
+		}
+		if !reflect.DeepEqual(again, expectedSections) {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("section permutation %v does not match address permutation %v", again, permuted), addr))
+		}
+		if addr.IsIPv4() {
+			iter := addr.ToIPv4().PermutationIterator(seed)
+			for i := 0; iter.HasNext(); i++ {
+				if str := iter.Next().String(); str != permuted[i] {
+					t.addFailure(newIPAddrFailure("IPv4 permutation element "+str+" does not match "+permuted[i], addr))
+					break
+				}
+			}
+		} else {
+			iter := addr.ToIPv6().GetSection().PermutationIterator(seed)
+			for i := 0; iter.HasNext(); i++ {
+				if str := iter.Next().String(); str != expectedSections[i] {
+					t.addFailure(newIPAddrFailure("IPv6 section permutation element "+str+" does not match "+expectedSections[i], addr))
+					break
+				}
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testNormalizeIPv6String(str string) {
+	addrStr := ipaddr.NewIPAddressString(str)
+	expected := addrStr.GetAddress().ToCanonicalString()
+	normalized, err := ipaddr.NormalizeIPv6String(str)
+	if err != nil {
+		t.addFailure(newFailure("unexpected error "+err.Error(), addrStr))
+	} else if normalized != expected {
+		t.addFailure(newFailure("normalized "+normalized+" does not match "+expected, addrStr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testNormalizeIPv6StringInvalid(str string) {
+	if normalized, err := ipaddr.NormalizeIPv6String(str); err == nil {
+		t.addFailure(newFailure("unexpectedly normalized to "+normalized, ipaddr.NewIPAddressString(str)))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testStringCaching(str string) {
+	expected := ipaddr.NewIPAddressString(str).GetAddress()
+	// caching is a package-wide setting shared with concurrently running tests, so we check only that strings are unaffected
+	ipaddr.SetStringCaching(false)
+	addr := ipaddr.NewIPAddressString(str).GetAddress()
+	for i := 0; i < 2; i++ {
+		if addr.String() != expected.String() || addr.ToNormalizedString() != expected.ToNormalizedString() ||
+			addr.ToFullString() != expected.ToFullString() || addr.ToCanonicalWildcardString() != expected.ToCanonicalWildcardString() {
+			t.addFailure(newIPAddrFailure("uncached string does not match "+expected.String(), addr))
+		}
+	}
+	ipaddr.SetStringCaching(true)
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testWithoutStringCaching(str string) {
+	expected := ipaddr.NewIPAddressString(str).GetAddress()
+	addr := ipaddr.NewIPAddressString(str).GetAddress()
+	addr.WarmStrings()
+	uncached := addr.WithoutStringCaching()
+	uncachedSection := expected.GetSection().WithoutStringCaching()
+	for i := 0; i < 2; i++ {
+		for _, item := range []interface {
+			ToCanonicalString() string
+			ToNormalizedString() string
+			ToCompressedString() string
+		}{addr, uncached} {
+			if item.ToCanonicalString() != expected.ToCanonicalString() || item.ToNormalizedString() != expected.ToNormalizedString() ||
+				item.ToCompressedString() != expected.ToCompressedString() {
+				t.addFailure(newIPAddrFailure("string does not match "+expected.String(), addr))
+			}
+		}
+		if uncachedSection.ToCanonicalString() != expected.GetSection().ToCanonicalString() || uncachedSection.String() != expected.GetSection().String() {
+			t.addFailure(newIPAddrFailure("uncached section string does not match "+expected.GetSection().String(), addr))
+		}
+	}
+	if !uncached.Equal(addr) || !uncachedSection.Equal(addr.GetSection()) || uncached.GetNetworkPrefixLen().Compare(addr.GetNetworkPrefixLen()) != 0 {
+		t.addFailure(newIPAddrFailure("uncached address does not match", uncached))
+	}
+	if addr.IsIPv4() {
+		section := addr.ToIPv4().GetSection()
+		section.WarmStrings()
+		if uncachedIPv4 := section.WithoutStringCaching(); uncachedIPv4.String() != section.String() || !uncachedIPv4.Equal(section) {
+			t.addFailure(newIPAddrFailure("uncached IPv4 section does not match "+section.String(), addr))
+		}
+	} else if addr.IsIPv6() {
+		ipv6Addr := addr.ToIPv6()
+		if uncachedIPv6 := ipv6Addr.WithoutStringCaching(); uncachedIPv6.String() != ipv6Addr.String() || uncachedIPv6.GetZone() != ipv6Addr.GetZone() {
+			t.addFailure(newIPAddrFailure("uncached IPv6 address does not match "+ipv6Addr.String(), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testDefaultStringFormat() {
+	t.testDefaultStringFormatStr("1:0:0:4:0:0:0:1-ff/64")
+	t.testDefaultStringFormatStr("1.2.3.4/24")
+	t.testDefaultStringFormatStr("fe80::1%eth0")
+	t.testDefaultStringFormatStr("1.2.*.*")
+	t.testDefaultStringFormatStr("a::b:0:0")
+}
+
+func (t ipAddressTester) testDefaultStringFormatStr(str string) {
+	addr := ipaddr.NewIPAddressString(str).GetAddress()
+	section := addr.GetSection()
+	rng := addr.ToSequentialRange()
+	formats := []struct {
+		format                          ipaddr.StringFormat
+		addrStr, sectionStr, lowerStr string
+	}{
+		{ipaddr.DefaultStringFormat, addr.ToCanonicalString(), section.ToNormalizedString(), rng.GetLower().ToCanonicalString()},
+		{ipaddr.CanonicalStringFormat, addr.ToCanonicalString(), section.ToCanonicalString(), rng.GetLower().ToCanonicalString()},
+		{ipaddr.NormalizedStringFormat, addr.ToNormalizedString(), section.ToNormalizedString(), rng.GetLower().ToNormalizedString()},
+		{ipaddr.NormalizedWildcardStringFormat, addr.ToNormalizedWildcardString(), section.ToNormalizedWildcardString(), rng.GetLower().ToNormalizedWildcardString()},
+		{ipaddr.FullStringFormat, addr.ToFullString(), section.ToFullString(), rng.GetLower().ToFullString()},
+	}
+	defer ipaddr.SetDefaultStringFormat(ipaddr.DefaultStringFormat)
+	for _, expected := range formats {
+		ipaddr.SetDefaultStringFormat(expected.format)
+		if ipaddr.GetDefaultStringFormat() != expected.format {
+			t.addFailure(newIPAddrFailure("default string format is not "+expected.format.String(), addr))
+		}
+		if addr.String() != expected.addrStr || fmt.Sprint(addr) != expected.addrStr || fmt.Sprintf("%s", addr.ToAddressBase()) != expected.addrStr {
+			t.addFailure(newIPAddrFailure(expected.format.String()+" string "+addr.String()+" does not match "+expected.addrStr, addr))
+		}
+		if section.String() != expected.sectionStr || fmt.Sprintf("%v", section) != expected.sectionStr {
+			t.addFailure(newIPAddrFailure(expected.format.String()+" section string "+section.String()+" does not match "+expected.sectionStr, addr))
+		}
+		if addr.IsIPv4() {
+			if sect := section.ToIPv4(); sect.String() != expected.sectionStr {
+				t.addFailure(newIPAddrFailure(expected.format.String()+" IPv4 section string "+sect.String()+" does not match "+expected.sectionStr, addr))
+			}
+		} else if sect := addr.ToIPv6(); sect.String() != expected.addrStr {
+			t.addFailure(newIPAddrFailure(expected.format.String()+" IPv6 string "+sect.String()+" does not match "+expected.addrStr, addr))
+		}
+		expectedRng := expected.lowerStr + ipaddr.DefaultSeqRangeSeparator
+		if !strings.HasPrefix(rng.String(), expectedRng) || !strings.HasPrefix(fmt.Sprint(rng), expectedRng) {
+			t.addFailure(newIPAddrFailure(expected.format.String()+" range string "+rng.String()+" does not start with "+expectedRng, addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+var uriZoneParams = new(addrstrparam.IPAddressStringParamsBuilder).GetIPv6AddressParamsBuilder().AllowURIZoneEncoding(true).GetParentBuilder().ToParams()
+
+func (t ipAddressTester) testURIZone(str string, expectedZone ipaddr.Zone, expectedURIStr string) {
+	addrStr := ipaddr.NewIPAddressStringParams(str, uriZoneParams)
+	addr, err := addrStr.ToAddress()
+	if err != nil {
+		t.addFailure(newFailure("unexpected error "+err.Error(), addrStr))
+	} else if zone := getZone(addr); zone != expectedZone {
+		t.addFailure(newIPAddrFailure("zone "+zone.String()+" does not match "+expectedZone.String(), addr))
+	} else if uriStr := addr.ToURIString(); uriStr != expectedURIStr {
+		t.addFailure(newIPAddrFailure("URI string "+uriStr+" does not match "+expectedURIStr, addr))
+	} else if back := ipaddr.NewIPAddressStringParams(uriStr, uriZoneParams).GetAddress(); !addr.Equal(back) || getZone(addr) != getZone(back) {
+		t.addFailure(newIPAddrFailure("URI string "+uriStr+" parsed to "+back.String(), addr))
+	} else if strings.Contains(str, "%25") {
+		// without URI zone encoding, the zone is not decoded
+		if unencoded := ipaddr.NewIPAddressString(str).GetAddress(); unencoded != nil && getZone(unencoded) == expectedZone {
+			t.addFailure(newIPAddrFailure("unexpected decoding of zone without URI zone encoding", addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func getZone(addr *ipaddr.IPAddress) ipaddr.Zone {
+	if addr.IsIPv6() {
+		return addr.ToIPv6().GetZone()
+	}
+	return ipaddr.NoZone
+}
+
+func (t ipAddressTester) testURIZoneInvalid(str string) {
+	addrStr := ipaddr.NewIPAddressStringParams(str, uriZoneParams)
+	if addr, err := addrStr.ToAddress(); err == nil {
+		t.addFailure(newFailure("unexpectedly parsed to "+addr.String(), addrStr))
+	}
+	t.incrementTestCount()
+}
+
+// ipv6ParamsWithoutURIZone hides the URIZoneEncodingProvider method of the wrapped parameters
+type ipv6ParamsWithoutURIZone struct {
+	addrstrparam.IPv6AddressStringParams
+}
+
+// ipAddressParamsWithoutURIZone provides IPv6 parameters that hide the URIZoneEncodingProvider method
+type ipAddressParamsWithoutURIZone struct {
+	addrstrparam.IPAddressStringParams
+}
+
+func (params ipAddressParamsWithoutURIZone) GetIPv6Params() addrstrparam.IPv6AddressStringParams {
+	return ipv6ParamsWithoutURIZone{params.IPAddressStringParams.GetIPv6Params()}
+}
+
+// testURIZoneNoProvider checks that IPv6 parameters which do not provide AllowsURIZoneEncoding do not decode zones
+func (t ipAddressTester) testURIZoneNoProvider(str string, expectedZone ipaddr.Zone) {
+	addrStr := ipaddr.NewIPAddressStringParams(str, ipAddressParamsWithoutURIZone{uriZoneParams})
+	if addr, err := addrStr.ToAddress(); err != nil {
+		t.addFailure(newFailure("unexpected error "+err.Error(), addrStr))
+	} else if zone := getZone(addr); zone != expectedZone {
+		t.addFailure(newIPAddrFailure("zone "+zone.String()+" does not match "+expectedZone.String(), addr))
+	}
+	t.incrementTestCount()
+}
+
+// ipv4ParamsWithoutJoinedSegmentCount hides the JoinedSegmentCountProvider method of the wrapped parameters
+type ipv4ParamsWithoutJoinedSegmentCount struct {
+	addrstrparam.IPv4AddressStringParams
+}
+
+// ipAddressParamsWithoutJoinedSegmentCount provides IPv4 parameters that hide the JoinedSegmentCountProvider method
+type ipAddressParamsWithoutJoinedSegmentCount struct {
+	addrstrparam.IPAddressStringParams
+}
+
+func (params ipAddressParamsWithoutJoinedSegmentCount) GetIPv4Params() addrstrparam.IPv4AddressStringParams {
+	return ipv4ParamsWithoutJoinedSegmentCount{params.IPAddressStringParams.GetIPv4Params()}
+}
+
+func (t ipAddressTester) testInetAtonParse(str string, params addrstrparam.IPAddressStringParams, expected string) {
+	addrStr := ipaddr.NewIPAddressStringParams(str, params)
+	addr, err := addrStr.ToAddress()
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newFailure("unexpectedly parsed to "+addr.String(), addrStr))
+		}
+	} else if err != nil {
+		t.addFailure(newFailure("unexpected error "+err.Error(), addrStr))
+	} else if addr.String() != expected {
+		t.addFailure(newFailure("parsed to "+addr.String()+" not "+expected, addrStr))
+	}
+	encoded, _ := addrstrparam.MarshalIPAddressStringParams(params)
+	if decoded, err := addrstrparam.UnmarshalIPAddressStringParams(encoded); err != nil {
+		t.addFailure(newFailure("unexpected error decoding params "+err.Error(), addrStr))
+	} else if ipaddr.NewIPAddressStringParams(str, decoded).IsValid() != (expected != "") {
+		t.addFailure(newFailure("decoded params "+string(encoded)+" do not match", addrStr))
+	}
+	t.incrementTestCount()
+}
+
+func testCollection[T ipaddr.AddressConstraint[T]](t ipAddressTester, addrs []T, expectedMin, expectedMax string, expectedUnique []string) {
+	if min := ipaddr.Min(addrs...); min.String() != expectedMin {
+		t.addFailure(newFailure(fmt.Sprint("min of ", addrs, " is ", min, ", expected ", expectedMin), nil))
+	}
+	if max := ipaddr.Max(addrs...); max.String() != expectedMax {
+		t.addFailure(newFailure(fmt.Sprint("max of ", addrs, " is ", max, ", expected ", expectedMax), nil))
+	}
+	original := append([]T(nil), addrs...)
+	unique := ipaddr.SortedUnique(addrs)
+	strs := make([]string, 0, len(unique))
+	for _, addr := range unique {
+		strs = append(strs, addr.String())
+	}
+	if !reflect.DeepEqual(strs, expectedUnique) {
+		t.addFailure(newFailure(fmt.Sprint("sorted unique of ", addrs, " is ", strs, ", expected ", expectedUnique), nil))
+	} else if !reflect.DeepEqual(original, addrs) {
+		t.addFailure(newFailure(fmt.Sprint("sorted unique modified ", original, " to ", addrs), nil))
+	}
+	t.incrementTestCount()
+}
+
+func testContainsAll[T ipaddr.AddressConstraint[T]](t ipAddressTester, addrs, contained []T, expectedAll, expectedAny bool) {
+	if result := ipaddr.ContainsAll(addrs, contained...); result != expectedAll {
+		t.addFailure(newFailure(fmt.Sprint(addrs, " contains all of ", contained, " is ", result), nil))
+	}
+	if result := ipaddr.ContainsAny(addrs, contained...); result != expectedAny {
+		t.addFailure(newFailure(fmt.Sprint(addrs, " contains any of ", contained, " is ", result), nil))
+	}
+	t.incrementTestCount()
+}
+
+var addressMaskParams = new(addrstrparam.IPAddressStringParamsBuilder).AllowSpaceSeparatedMask(true).ToParams()
+
+// ipAddressParamsWithoutSpaceMask hides the SpaceSeparatedMaskProvider method of the wrapped parameters
+type ipAddressParamsWithoutSpaceMask struct {
+	addrstrparam.IPAddressStringParams
+}
+
+// testAddressMaskStringNoProvider checks that parameters which do not provide AllowsSpaceSeparatedMask do not allow space-separated masks
+func (t ipAddressTester) testAddressMaskStringNoProvider(str string) {
+	if !ipaddr.NewIPAddressStringParams(str, addressMaskParams).IsValid() {
+		t.addFailure(newFailure("expected valid space-separated mask", ipaddr.NewIPAddressStringParams(str, addressMaskParams)))
+	} else if addrStr := ipaddr.NewIPAddressStringParams(str, ipAddressParamsWithoutSpaceMask{addressMaskParams}); addrStr.IsValid() {
+		t.addFailure(newFailure("expected invalid space-separated mask without the optional parameters", addrStr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testZeroAddressMaskString() {
+	var zero ipaddr.IPAddress
+	if str := zero.ToAddressMaskString(); str != zero.ToCanonicalString() {
+		t.addFailure(newIPAddrFailure("zero address mask string was "+str, &zero))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressMaskString(addrStr, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	if str := addr.ToAddressMaskString(); str != expected {
+		t.addFailure(newIPAddrFailure("address mask string "+str+" expected "+expected, addr))
+	} else if addr.IsIPv4() && addr.ToIPv4().ToAddressMaskString() != expected {
+		t.addFailure(newIPAddrFailure("IPv4 address mask string mismatch", addr))
+	} else if addr.IsIPv6() && addr.ToIPv6().ToAddressMaskString() != expected {
+		t.addFailure(newIPAddrFailure("IPv6 address mask string mismatch", addr))
+	} else if parsed, err := ipaddr.NewIPAddressStringParams(str, addressMaskParams).ToAddress(); err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error parsing "+str+": "+err.Error(), addr))
+	} else if !parsed.Equal(addr) || (addr.IsPrefixed() && !parsed.GetPrefixLen().Equal(addr.GetPrefixLen())) {
+		// without a prefix length, the all-ones mask is parsed as the full-length prefix
+		t.addFailure(newIPAddrFailure("parsed "+str+" to "+parsed.String(), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressMaskParse(str, expected string) {
+	addrStr := ipaddr.NewIPAddressStringParams(str, addressMaskParams)
+	addr, err := addrStr.ToAddress()
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newFailure("unexpectedly parsed to "+addr.String(), addrStr))
+		}
+	} else if err != nil {
+		t.addFailure(newFailure("unexpected error "+err.Error(), addrStr))
+	} else if expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress(); !addr.Equal(expectedAddr) || !addr.GetPrefixLen().Equal(expectedAddr.GetPrefixLen()) {
+		t.addFailure(newFailure("parsed to "+addr.String()+" not "+expected, addrStr))
+	} else if ipaddr.NewIPAddressString(str).IsValid() {
+		t.addFailure(newFailure("unexpectedly valid without space-separated masks", addrStr))
+	} else if ipaddr.NewIPAddressStringParams(str, new(addrstrparam.IPAddressStringParamsBuilder).Set(addressMaskParams).AllowMask(false).ToParams()).IsValid() {
+		t.addFailure(newFailure("unexpectedly valid without masks", addrStr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testRelation(str1, str2 string, expected ipaddr.Relation) {
+	addr1, addr2 := ipaddr.NewIPAddressString(str1).GetAddress(), ipaddr.NewIPAddressString(str2).GetAddress()
+	sameZone := !addr1.IsIPv6() || !addr2.IsIPv6() || addr1.ToIPv6().GetZone() == addr2.ToIPv6().GetZone()
+	if relation := addr1.Relation(addr2); relation != expected {
+		t.addFailure(newIPAddrFailure("relation to "+str2+" was "+relation.String()+" not "+expected.String(), addr1))
+	} else if reverse := addr2.Relation(addr1); reverse != expected.Reverse() {
+		t.addFailure(newIPAddrFailure("reverse relation to "+str1+" was "+reverse.String(), addr2))
+	} else if relation = addr1.ToAddressBase().Relation(addr2.ToAddressBase()); relation != expected {
+		t.addFailure(newIPAddrFailure("address relation to "+str2+" was "+relation.String(), addr1))
+	} else if addr1.Contains(addr2) != (expected == ipaddr.RelationContains || expected == ipaddr.RelationEqual) ||
+		addr1.IsContainedBy(addr2) != addr2.Contains(addr1) || (sameZone && relation.IsOverlapping() != (addr1.Intersect(addr2) != nil)) {
+		t.addFailure(newIPAddrFailure("relation "+relation.String()+" inconsistent with containment of "+str2, addr1))
+	} else if addr1.IsIPv4() && addr2.IsIPv4() && addr1.ToIPv4().Relation(addr2.ToIPv4()) != expected {
+		t.addFailure(newIPAddrFailure("IPv4 relation mismatch with "+str2, addr1))
+	} else if addr1.IsIPv6() && addr2.IsIPv6() && addr1.ToIPv6().Relation(addr2.ToIPv6()) != expected {
+		t.addFailure(newIPAddrFailure("IPv6 relation mismatch with "+str2, addr1))
+	} else if sameZone && addr1.GetSection().Relation(addr2.GetSection()) != expected {
+		t.addFailure(newIPAddrFailure("section relation mismatch with "+str2, addr1))
+	} else if addr1.IsSequential() && addr2.IsSequential() && addr1.ToSequentialRange().Relation(addr2.ToSequentialRange()) != expected {
+		t.addFailure(newIPAddrFailure("range relation mismatch with "+str2, addr1))
+	} else if addr1.IsSequential() && addr2.IsSequential() && addr1.ToSequentialRange().IsContainedBy(addr2.ToSequentialRange()) != addr1.IsContainedBy(addr2) {
+		t.addFailure(newIPAddrFailure("range containment mismatch with "+str2, addr1))
+	}
+	t.incrementTestCount()
+}
+
+type gobTestStruct struct {
+	Addr  *ipaddr.IPAddress
+	Base  *ipaddr.Address
+	IPv4  *ipaddr.IPv4Address
+	IPv6  *ipaddr.IPv6Address
+	Range *ipaddr.SequentialRange[*ipaddr.IPAddress]
+}
+
+func gobRoundTrip(val, result any) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(val); err != nil {
+		return err
+	}
+	return gob.NewDecoder(&buf).Decode(result)
+}
+
+func (t ipAddressTester) testGob(str string) {
+	addr := ipaddr.NewIPAddressString(str).GetAddress()
+	if addr == nil {
+		addr = &ipaddr.IPAddress{}
+	}
+	t.testGobAddr(addr)
+}
+
+func (t ipAddressTester) testGobAddr(addr *ipaddr.IPAddress) {
+	orig := gobTestStruct{Addr: addr, Base: addr.ToAddressBase(), IPv4: addr.ToIPv4(), IPv6: addr.ToIPv6(), Range: addr.ToSequentialRange()}
+	var decoded gobTestStruct
+	if err := gobRoundTrip(orig, &decoded); err != nil {
+		t.addFailure(newIPAddrFailure("gob round trip failed: "+err.Error(), addr))
+	} else if !decoded.Addr.Equal(addr) || decoded.Addr.String() != addr.String() || !decoded.Addr.GetPrefixLen().Equal(addr.GetPrefixLen()) ||
+		decoded.Addr.IsMultiple() != addr.IsMultiple() {
+		t.addFailure(newIPAddrFailure("gob decoded to "+decoded.Addr.String(), addr))
+	} else if !decoded.Base.Equal(addr) || decoded.Base.String() != addr.String() {
+		t.addFailure(newIPAddrFailure("gob decoded base address to "+decoded.Base.String(), addr))
+	} else if (addr.IsIPv4() && decoded.IPv4.String() != addr.String()) || (addr.IsIPv6() && decoded.IPv6.String() != addr.String()) {
+		t.addFailure(newIPAddrFailure("gob decoded version-specific address mismatch", addr))
+	} else if !decoded.Range.Equal(orig.Range) {
+		t.addFailure(newIPAddrFailure("gob decoded range to "+decoded.Range.String(), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testGobMAC(str string) {
+	addr := ipaddr.NewMACAddressString(str).GetAddress()
+	var decoded, decodedBase *ipaddr.MACAddress
+	if err := gobRoundTrip(addr, &decoded); err != nil {
+		t.addFailure(newMACAddrFailure("gob round trip failed: "+err.Error(), addr))
+	} else if !decoded.Equal(addr) || decoded.String() != addr.String() || !decoded.GetPrefixLen().Equal(addr.GetPrefixLen()) {
+		t.addFailure(newMACAddrFailure("gob decoded to "+decoded.String(), addr))
+	} else if err = gobRoundTrip(addr.ToAddressBase(), &decodedBase); err != nil || !decodedBase.Equal(addr) {
+		t.addFailure(newMACAddrFailure("gob round trip failed through base address", addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testGobMismatch() {
+	addr := ipaddr.NewIPAddressString("1::1").GetAddress()
+	var ipv4 *ipaddr.IPv4Address
+	var mac *ipaddr.MACAddress
+	if err := gobRoundTrip(addr, &ipv4); err == nil {
+		t.addFailure(newIPAddrFailure("unexpectedly decoded IPv6 gob to IPv4 address "+ipv4.String(), addr))
+	} else if err = gobRoundTrip(addr, &mac); err == nil {
+		t.addFailure(newIPAddrFailure("unexpectedly decoded IPv6 gob to MAC address "+mac.String(), addr))
+	} else if err = ipv4.GobDecode([]byte{9, 1, 0, 4, 1, 2, 3, 4, 1, 2, 3, 4}); err == nil {
+		t.addFailure(newIPAddrFailure("unexpectedly decoded unsupported gob format version", addr))
+	} else if err = new(ipaddr.IPv4Address).GobDecode([]byte{ipaddr.GobFormatVersion, 1, 0, 4, 1, 2}); err == nil {
+		t.addFailure(newIPAddrFailure("unexpectedly decoded truncated gob", addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testNilTryMerge(str, expected string) {
+	addr := ipaddr.NewIPAddressString(str).GetAddress()
+	var nilAddr *ipaddr.IPAddress
+	results := []*ipaddr.IPAddress{nilAddr.TryMergeWith(addr)}
+	if addr.IsIPv4() {
+		results = append(results, nilAddr.ToIPv4().TryMergeWith(addr.ToIPv4()).ToIP())
+	} else {
+		results = append(results, nilAddr.ToIPv6().TryMergeWith(addr.ToIPv6()).ToIP())
+	}
+	for _, result := range results {
+		if expected == "" {
+			if result != nil {
+				t.addFailure(newIPAddrFailure("unexpectedly merged nil to "+result.String(), addr))
+			}
+		} else if expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress(); !result.Equal(expectedAddr) || getZone(result) != getZone(expectedAddr) {
+			t.addFailure(newIPAddrFailure("merged nil to "+result.String()+" not "+expected, addr))
+		}
+	}
+	if merged := addr.TryMergeWith(nilAddr); merged != nil {
+		t.addFailure(newIPAddrFailure("unexpectedly merged with nil to "+merged.String(), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testTryMerge(str1, str2 string, adjacent bool, expected string) {
+	addr1, addr2 := ipaddr.NewIPAddressString(str1).GetAddress(), ipaddr.NewIPAddressString(str2).GetAddress()
+	merged := addr1.TryMergeWith(addr2)
+	if addr1.IsAdjacentTo(addr2) != adjacent || addr2.IsAdjacentTo(addr1) != adjacent {
+		t.addFailure(newIPAddrFailure("adjacency to "+str2+" not "+strconv.FormatBool(adjacent), addr1))
+	} else if expected == "" {
+		if merged != nil {
+			t.addFailure(newIPAddrFailure("unexpectedly merged with "+str2+" to "+merged.String(), addr1))
+		}
+	} else if expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress(); !merged.Equal(expectedAddr) {
+		t.addFailure(newIPAddrFailure("merged with "+str2+" to "+merged.String()+" not "+expected, addr1))
+	} else if reverse := addr2.TryMergeWith(addr1); !reverse.Equal(merged) {
+		t.addFailure(newIPAddrFailure("reverse merge with "+str2+" was "+reverse.String(), addr1))
+	} else if addr1.IsIPv4() && !addr1.ToIPv4().TryMergeWith(addr2.ToIPv4()).Equal(merged) {
+		t.addFailure(newIPAddrFailure("IPv4 merge mismatch with "+str2, addr1))
+	} else if addr1.IsIPv6() && !addr1.ToIPv6().TryMergeWith(addr2.ToIPv6()).Equal(merged) {
+		t.addFailure(newIPAddrFailure("IPv6 merge mismatch with "+str2, addr1))
+	}
+	if addr1.IsSequential() && addr2.IsSequential() {
+		rng1, rng2 := addr1.ToSequentialRange(), addr2.ToSequentialRange()
+		if rng1.IsAdjacent(rng2) != adjacent || rng2.IsAdjacent(rng1) != adjacent {
+			t.addFailure(newIPAddrFailure("range adjacency to "+str2+" not "+strconv.FormatBool(adjacent), addr1))
+		}
+	}
+	t.incrementTestCount()
+}
+
+// testAddressFamily parses the string in the given family, checking the formatted result and the prefix block.
+// If prefixBlock is empty, the address is checked to be its own prefix block.
+// If expected is empty, the string is expected to be invalid.
+func (t ipAddressTester) testAddressFamily(family *ipaddr.AddressFamily, str, expected, prefixBlock string, matches bool) {
+	grouping, err := family.Parse(str)
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newFailure("unexpectedly parsed to "+family.ToString(grouping), nil))
+		}
+		t.incrementTestCount()
+		return
+	} else if err != nil {
+		t.addFailure(newFailure("unexpected error parsing "+str+": "+err.Error(), nil))
+		t.incrementTestCount()
+		return
+	} else if result := family.ToString(grouping); result != expected {
+		t.addFailure(newFailure("formatted "+str+" as "+result+" not "+expected, nil))
+	} else if !family.Contains(grouping, grouping) {
+		t.addFailure(newFailure("not containing itself: "+str, nil))
+	} else if reparsed, _ := family.Parse(expected); !family.Contains(reparsed, grouping) || !family.Contains(grouping, reparsed) {
+		t.addFailure(newFailure("reparsed "+expected+" mismatch", nil))
+	}
+	if prefixBlock == "" {
+		prefixBlock = expected
+	}
+	block := family.ToPrefixBlock(grouping)
+	expectedBlock, _ := family.Parse(prefixBlock)
+	isMatch := family.Contains(block, expectedBlock) && family.Contains(expectedBlock, block)
+	if isMatch != matches {
+		t.addFailure(newFailure("prefix block of "+str+" is "+family.ToString(block)+", expected match with "+prefixBlock+" "+strconv.FormatBool(matches), nil))
+	} else if !family.Contains(block, grouping) {
+		t.addFailure(newFailure("prefix block "+family.ToString(block)+" not containing "+str, nil))
+	} else if block.GetBitCount() != family.GetBitCount() || block.GetDivisionCount() != family.GetSegmentCount() {
+		t.addFailure(newFailure("prefix block "+family.ToString(block)+" has wrong size", nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressFamilyCreation(bitCount, bitsPerSegment ipaddr.BitCount, separator byte, radix int, valid bool) {
+	family, err := ipaddr.NewAddressFamily(bitCount, bitsPerSegment, separator, radix)
+	if valid != (err == nil) {
+		t.addFailure(newFailure("family creation with bit count "+strconv.Itoa(int(bitCount))+" and separator "+string(separator)+" not "+strconv.FormatBool(valid), nil))
+	} else if valid && (family.GetBitCount() != bitCount || family.GetSegmentCount()*int(bitsPerSegment) != int(bitCount) ||
+		family.GetSeparator() != separator || family.GetRadix() != radix || family.GetBitsPerSegment() != bitsPerSegment) {
+		t.addFailure(newFailure("family mismatch with bit count "+strconv.Itoa(int(bitCount)), nil))
+	}
+	t.incrementTestCount()
+}
+
+// testHostBitmap adds the addresses to the bitmap for the block of the given address,
+// checking the count and the prefix blocks of the bitmap, and a round trip through the bitmap words.
+func (t ipAddressTester) testHostBitmap(blockStr string, addrStrs, expectedBlocks []string, expectedCount int) {
+	blockAddr := ipaddr.NewIPAddressString(blockStr).GetAddress()
+	bitmap := ipaddr.NewHostBitmap(blockAddr)
+	for _, str := range addrStrs {
+		addr := ipaddr.NewIPAddressString(str).GetAddress()
+		if added := bitmap.Add(addr); added != (bitmap.GetBlock().Intersect(addr) != nil) {
+			t.addFailure(newIPAddrFailure("bitmap add of "+str+" returned "+strconv.FormatBool(added), blockAddr))
+		}
+	}
+	blocks := bitmap.ToPrefixBlocks()
+	var blockStrs []string
+	for _, block := range blocks {
+		blockStrs = append(blockStrs, block.String())
+	}
+	if expectedBlocks != nil && fmt.Sprint(blockStrs) != fmt.Sprint(expectedBlocks) {
+		t.addFailure(newIPAddrFailure("bitmap blocks were "+fmt.Sprint(blockStrs)+" not "+fmt.Sprint(expectedBlocks), blockAddr))
+	} else if count := bitmap.GetCount(); count != expectedCount {
+		t.addFailure(newIPAddrFailure("bitmap count was "+strconv.Itoa(count), blockAddr))
+	} else if bitmap.IsEmpty() != (expectedCount == 0) {
+		t.addFailure(newIPAddrFailure("bitmap emptiness mismatch", blockAddr))
+	} else if !bitmap.GetBlock().Contains(blockAddr) || bitmap.GetBlock().GetBitCount()-bitmap.GetBlock().GetPrefixLen().Len() != ipaddr.HostBitmapHostBitCount {
+		t.addFailure(newIPAddrFailure("bitmap block was "+bitmap.GetBlock().String(), blockAddr))
+	} else {
+		copied := ipaddr.NewHostBitmapFromWords(blockAddr, bitmap.Words())
+		var total int
+		for _, rng := range copied.ToSequentialRanges() {
+			total += int(rng.GetCount().Int64())
+			if !copied.Contains(rng.GetLower()) || !copied.Contains(rng.GetUpper()) {
+				t.addFailure(newIPAddrFailure("bitmap not containing range "+rng.String(), blockAddr))
+			}
+		}
+		for _, block := range blocks {
+			if !copied.Contains(block) {
+				t.addFailure(newIPAddrFailure("bitmap not containing block "+block.String(), blockAddr))
+			}
+		}
+		if total != expectedCount {
+			t.addFailure(newIPAddrFailure("bitmap range count was "+strconv.Itoa(total), blockAddr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPrefixLenArithmetic(prefLen ipaddr.PrefixLen, bits, maxBitCount ipaddr.BitCount, expected ipaddr.PrefixLen, expectError bool) {
+	result, err := prefLen.Add(bits, maxBitCount)
+	if expectError != (err != nil) {
+		t.addFailure(newFailure("prefix length "+prefLen.String()+" plus "+strconv.Itoa(bits)+" error mismatch", nil))
+	} else if !result.Equal(expected) {
+		t.addFailure(newFailure("prefix length "+prefLen.String()+" plus "+strconv.Itoa(bits)+" was "+result.String(), nil))
+	} else if expected != nil && (result.CompareBitCount(expected.Len()) != 0 || result.CompareBitCount(expected.Len()+1) >= 0 || result.CompareBitCount(expected.Len()-1) <= 0) {
+		t.addFailure(newFailure("prefix length "+result.String()+" bit count comparison mismatch", nil))
+	} else if prefLen == nil && (prefLen.CompareBitCount(maxBitCount) <= 0 || prefLen.LenOr(maxBitCount) != maxBitCount) {
+		t.addFailure(newFailure("nil prefix length comparison mismatch", nil))
+	} else if prefLen != nil && prefLen.LenOr(maxBitCount) != prefLen.Len() {
+		t.addFailure(newFailure("prefix length "+prefLen.String()+" default mismatch", nil))
+	} else if !prefLen.Min(result).Equal(result.Min(prefLen)) || !prefLen.Max(result).Equal(result.Max(prefLen)) {
+		t.addFailure(newFailure("prefix length "+prefLen.String()+" min or max asymmetric", nil))
+	} else if min, max := prefLen.Min(result), prefLen.Max(result); min.Compare(max) > 0 || (min == nil) != (prefLen == nil && result == nil) || (max == nil) != (prefLen == nil || result == nil) {
+		t.addFailure(newFailure("prefix length "+prefLen.String()+" min "+min.String()+" max "+max.String(), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testNilUniqueLocalPrefix() {
+	if prefix, err := ipaddr.NewIPv6UniqueLocalPrefix(time.Now(), nil); err == nil {
+		t.addFailure(newFailure("unexpected unique local prefix "+prefix.String()+" for nil MAC address", nil))
+	} else if !strings.Contains(err.Error(), "address is nil") {
+		t.addFailure(newFailure("unexpected error for nil MAC address: "+err.Error(), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testUniqueLocalPrefix(timestamp time.Time, macStr, expected string) {
+	mac := ipaddr.NewMACAddressString(macStr).GetAddress()
+	prefix, err := ipaddr.NewIPv6UniqueLocalPrefix(timestamp, mac)
+	expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress().ToIPv6()
+	if err != nil {
+		t.addFailure(newMACAddrFailure("unexpected error "+err.Error(), mac))
+	} else if !prefix.Equal(expectedAddr) || !prefix.GetNetworkPrefixLen().Equal(expectedAddr.GetNetworkPrefixLen()) {
+		t.addFailure(newMACAddrFailure("unique local prefix was "+prefix.String()+" not "+expected, mac))
+	} else if !prefix.IsUniqueLocal() || !prefix.IsPrefixBlock() {
+		t.addFailure(newMACAddrFailure("generated prefix "+prefix.String()+" not a unique local block", mac))
+	} else if other, _ := ipaddr.NewIPv6UniqueLocalPrefix(timestamp.Add(time.Millisecond), mac); other.Equal(prefix) {
+		t.addFailure(newMACAddrFailure("unique local prefix did not vary with time", mac))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testUniqueLocalGlobalID(addrStr, expected string, expectError bool) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress().ToIPv6()
+	globalID, err := addr.GetUniqueLocalGlobalID()
+	if expectError != (err != nil) {
+		t.addFailure(newIPAddrFailure("global ID error mismatch", addr.ToIP()))
+	} else if expected == "" {
+		if globalID != nil {
+			t.addFailure(newIPAddrFailure("unexpected global ID "+globalID.String(), addr.ToIP()))
+		}
+	} else if div := globalID.GetDivision(0); fmt.Sprintf("%#010x", div.GetDivisionValue()) != strings.Split(expected, "-")[0] ||
+		fmt.Sprintf("%#010x", div.GetUpperDivisionValue()) != strings.Split(expected, "-")[len(strings.Split(expected, "-"))-1] {
+		str := fmt.Sprintf("%#010x-%#010x", div.GetDivisionValue(), div.GetUpperDivisionValue())
+		t.addFailure(newIPAddrFailure("global ID was "+str+" not "+expected, addr.ToIP()))
+	} else if globalID.GetBitCount() != ipaddr.IPv6UniqueLocalGlobalIDBitCount {
+		t.addFailure(newIPAddrFailure("global ID bit count was "+strconv.Itoa(globalID.GetBitCount()), addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testDiagnose(str, expectedKey string, expectedIndex int) {
+	addrStr := ipaddr.NewIPAddressString(str)
+	diagnostics := addrStr.Diagnose()
+	if expectedKey == "" {
+		if diagnostics != nil {
+			t.addFailure(newFailure("unexpected diagnostics "+fmt.Sprint(diagnostics), addrStr))
+		}
+	} else if len(diagnostics) == 0 {
+		t.addFailure(newFailure("no diagnostics", addrStr))
+	} else if diagnostic := diagnostics[0]; diagnostic.Key != expectedKey || diagnostic.Index != expectedIndex || diagnostic.Input != str {
+		t.addFailure(newFailure(fmt.Sprintf("diagnostic was %#v, expected key %s and index %d", diagnostic, expectedKey, expectedIndex), addrStr))
+	} else if diagnostic.Message == "" || diagnostic.Message == diagnostic.Key {
+		t.addFailure(newFailure("diagnostic message missing for "+diagnostic.Key, addrStr))
+	} else if fromErr := ipaddr.Diagnose(addrStr.Validate()); fmt.Sprint(fromErr) != fmt.Sprint(diagnostics) {
+		t.addFailure(newFailure("diagnostics from error were "+fmt.Sprint(fromErr), addrStr))
+	} else if hostDiagnostics := ipaddr.NewHostName(str).Diagnose(); len(hostDiagnostics) > 0 && ipaddr.NewHostName(str).IsValid() {
+		t.addFailure(newFailure("host diagnostics for valid host", addrStr))
+	}
+	t.incrementTestCount()
+}
+
+// testAddressSeqs tests the address sequences, which are available when built with Go 1.23 or later
+var testAddressSeqs func(ipAddressTester)
+
+func (t ipAddressTester) testReverseZones(lowerStr, upperStr string, expected []string) {
+	lower, upper := t.createAddress(lowerStr).GetAddress(), t.createAddress(upperStr).GetAddress()
+	rng := lower.SpanWithRange(upper)
+	zones := rng.SplitToReverseZones()
+	if len(zones) != len(expected) {
+		t.addFailure(newIPAddrFailure("reverse zones were "+fmt.Sprint(zones), lower))
+	} else {
+		for i, zone := range zones {
+			if zone.GetName() != expected[i] {
+				t.addFailure(newIPAddrFailure("reverse zone was "+zone.GetName()+" not "+expected[i], lower))
+			} else if !rng.ContainsRange(zone.GetBlock().ToSequentialRange()) {
+				t.addFailure(newIPAddrFailure("reverse zone block "+zone.GetBlock().String()+" not in range", lower))
+			} else if zone.IsClassless() {
+				records := zone.GetCNAMERecords()
+				if len(records) != int(zone.GetBlock().GetCount().Int64()) {
+					t.addFailure(newIPAddrFailure("reverse zone records were "+fmt.Sprint(records), lower))
+				} else if !strings.HasSuffix(records[0].Target, "."+zone.GetName()) || !strings.HasSuffix(records[0].Name, "."+zone.GetParentName()) {
+					t.addFailure(newIPAddrFailure("reverse zone record was "+fmt.Sprint(records[0]), lower))
+				}
+			} else if zone.GetCNAMERecords() != nil || zone.GetParentName() != zone.GetName() {
+				t.addFailure(newIPAddrFailure("reverse zone "+zone.GetName()+" is classless", lower))
+			} else if parsed, err := ipaddr.ParseReverseDNSName(zone.GetName()); err != nil || !parsed.Equal(zone.GetBlock()) {
+				t.addFailure(newIPAddrFailure("reverse zone "+zone.GetName()+" does not match "+zone.GetBlock().String(), lower))
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPTRRecordName(lowerStr, upperStr, addrStr, expected string) {
+	lower, upper := t.createAddress(lowerStr).GetAddress(), t.createAddress(upperStr).GetAddress()
+	addr := t.createAddress(addrStr).GetAddress()
+	name, err := lower.SpanWithRange(upper).ToPTRRecordName(addr)
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("unexpected PTR record name "+name, addr))
+		}
+	} else if err != nil || name != expected {
+		t.addFailure(newIPAddrFailure("PTR record name was "+name+" not "+expected, addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressVector(version ipaddr.IPVersion, strs, expected []string) {
+	vec := ipaddr.NewIPAddressVector(version)
+	if err := vec.AppendStrings(strs[:1]...); err != nil {
+		t.addFailure(newFailure("vector append failed with "+err.Error(), nil))
+		return
+	}
+	var addrs []*ipaddr.IPAddress
+	for _, str := range strs[1:] {
+		addrs = append(addrs, t.createAddress(str).GetAddress())
+	}
+	if err := vec.Append(addrs...); err != nil {
+		t.addFailure(newFailure("vector append failed with "+err.Error(), nil))
+		return
+	} else if vec.Len() != len(strs) {
+		t.addFailure(newFailure("vector length was "+strconv.Itoa(vec.Len()), nil))
+		return
+	}
+	for i, str := range strs {
+		addr := t.createAddress(str).GetAddress()
+		if got := vec.Get(i); !got.Equal(addr) || !got.GetPrefixLen().Equal(addr.GetPrefixLen()) || !bytes.Equal(vec.Bytes(i), addr.Bytes()) {
+			t.addFailure(newIPAddrFailure("vector element was "+got.String(), addr))
+		}
+	}
+	vec.Sort()
+	if removed := vec.Dedup(); removed != len(strs)-len(expected) || vec.Len() != len(expected) {
+		t.addFailure(newFailure("vector dedup removed "+strconv.Itoa(removed), nil))
+		return
+	}
+	var i int
+	for iter := vec.Iterator(); iter.HasNext(); i++ {
+		got := iter.Next()
+		if got.String() != t.createAddress(expected[i]).GetAddress().String() {
+			t.addFailure(newIPAddrFailure("sorted vector element was "+got.String()+" not "+expected[i], got))
+		}
+	}
+	t.incrementTestCount()
+}
+
+// testAddressVectorPrefixed checks that a single address with a zero host and a prefix length remains a single address after a round trip through a vector
+func (t ipAddressTester) testAddressVectorPrefixed(str string, prefLen ipaddr.BitCount) {
+	addr := t.createAddress(str).GetAddress().SetPrefixLen(prefLen)
+	vec := ipaddr.NewIPAddressVector(addr.GetIPVersion())
+	if err := vec.Append(addr); err != nil {
+		t.addFailure(newIPAddrFailure("vector append failed with "+err.Error(), addr))
+	} else if got := vec.Get(0); got.IsMultiple() || !got.Equal(addr) || !got.GetPrefixLen().Equal(addr.GetPrefixLen()) {
+		t.addFailure(newIPAddrFailure("vector element was "+got.String(), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressVectorFailure(version ipaddr.IPVersion, validStr, invalidStr string) {
+	vec := ipaddr.NewIPAddressVector(version)
+	if err := vec.AppendStrings(validStr, invalidStr); err == nil {
+		t.addFailure(newFailure("vector accepted "+invalidStr, nil))
+	} else if vec.Len() != 0 {
+		t.addFailure(newFailure("vector retained addresses after failing on "+invalidStr, nil))
+	} else if addr := ipaddr.NewIPAddressString(invalidStr).GetAddress(); addr != nil {
+		if err := vec.Append(t.createAddress(validStr).GetAddress(), addr); err == nil || vec.Len() != 0 {
+			t.addFailure(newFailure("vector accepted "+invalidStr, nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testRangeSeries(addrStr, lowerStr, upperStr string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	rng := t.createAddress(lowerStr).GetAddress().SpanWithRange(t.createAddress(upperStr).GetAddress())
+	series := []ipaddr.IPAddressRangeSeries{addr, rng}
+	for _, one := range series {
+		for _, two := range series {
+			if !one.ContainsRange(rng) || one.GetCount().Cmp(two.GetCount()) != 0 ||
+				!one.GetLowerIPAddress().Equal(two.GetLowerIPAddress()) || !one.GetUpperIPAddress().Equal(two.GetUpperIPAddress()) {
+				t.addFailure(newIPAddrFailure("mismatched range series "+one.ToNormalizedString()+" and "+two.ToNormalizedString(), addr))
+			} else if !one.CoverWithPrefixBlock().Equal(two.CoverWithPrefixBlock()) {
+				t.addFailure(newIPAddrFailure("mismatched range series cover "+one.CoverWithPrefixBlock().String(), addr))
+			} else if !equalAddrs(one.SpanWithPrefixBlocks(), two.SpanWithPrefixBlocks()) ||
+				!equalAddrs(one.SpanWithSequentialBlocks(), two.SpanWithSequentialBlocks()) {
+				t.addFailure(newIPAddrFailure("mismatched range series spans "+fmt.Sprint(one.SpanWithPrefixBlocks()), addr))
+			}
+		}
+		var count int64
+		for iter := one.Iterator(); iter.HasNext(); count++ {
+			if next := iter.Next(); !addr.Contains(next) || next.IsMultiple() {
+				t.addFailure(newIPAddrFailure("range series iterated "+next.String(), addr))
+				break
+			}
+		}
+		if count != one.GetCount().Int64() {
+			t.addFailure(newIPAddrFailure("range series iterated "+strconv.FormatInt(count, 10)+" addresses", addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func equalAddrs(one, two []*ipaddr.IPAddress) bool {
+	if len(one) != len(two) {
+		return false
+	}
+	for i := range one {
+		if !one[i].Equal(two[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t ipAddressTester) testAddressTemplate(subnetStr, pattern string, expected ...string) {
+	var subnet *ipaddr.Address
+	if ipAddr := ipaddr.NewIPAddressString(subnetStr).GetAddress(); ipAddr != nil {
+		subnet = ipAddr.ToAddressBase()
+	} else {
+		subnet = ipaddr.NewMACAddressString(subnetStr).GetAddress().ToAddressBase()
+	}
+	octal := func(value, _ uint64) string {
+		return strconv.FormatUint(value, 8)
+	}
+	template, err := ipaddr.NewAddressTemplate(pattern, map[string]ipaddr.AddressTemplateFormatter{"oct": octal})
+	if err != nil {
+		t.addFailure(newFailure("unexpected template error: "+err.Error(), nil))
+		return
+	}
+	iterator, err := template.Iterator(subnet)
+	if err != nil {
+		t.addFailure(newFailure("unexpected template error: "+err.Error(), nil))
+		return
+	}
+	var result []string
+	for iterator.HasNext() {
+		result = append(result, iterator.Next())
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.addFailure(newFailure(fmt.Sprint("template ", template, " generated ", result, ", expected ", expected), nil))
+	} else if str, err := template.Format(subnet.GetLower(), 0); err != nil || len(expected) == 1 && str != expected[0] {
+		t.addFailure(newFailure(fmt.Sprint("template ", template, " formatted ", str, ", expected ", expected[0]), nil))
+	}
+	if octTemplate, err := ipaddr.NewAddressTemplate("{seg1:oct}", map[string]ipaddr.AddressTemplateFormatter{"oct": octal}); err != nil {
+		t.addFailure(newFailure("unexpected template error: "+err.Error(), nil))
+	} else if str, _ := octTemplate.Format(subnet, 0); str != strconv.FormatUint(uint64(subnet.GetSegment(0).GetSegmentValue()), 8) {
+		t.addFailure(newFailure("unexpected octal formatting "+str, nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressTemplateFormat(subnetStr, pattern string, index uint64, expected string) {
+	subnet := ipaddr.NewIPAddressString(subnetStr).GetAddress()
+	template, err := ipaddr.NewAddressTemplate(pattern, nil)
+	if err != nil {
+		t.addFailure(newFailure("unexpected template error: "+err.Error(), nil))
+	} else if str, err := template.Format(subnet, index); err != nil || str != expected {
+		t.addFailure(newFailure(fmt.Sprint("template ", template, " formatted ", str, ", expected ", expected), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressTemplateFailure(pattern, addrStr string) {
+	template, err := ipaddr.NewAddressTemplate(pattern, nil)
+	if err == nil {
+		addr := t.createAddress(addrStr).GetAddress()
+		if _, err = template.Format(addr, 0); err == nil {
+			t.addFailure(newFailure("expected failure for template "+pattern, t.createAddress(addrStr)))
+		} else if _, err = template.Iterator(addr); err == nil {
+			t.addFailure(newFailure("expected iterator failure for template "+pattern, t.createAddress(addrStr)))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testUsableHosts(addrStr string, expectedCount uint64, expectedFirst, expectedLast string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	var count *big.Int
+	var iterator ipaddr.Iterator[*ipaddr.Address]
+	if addr.IsIPv4() {
+		count = new(big.Int).SetUint64(addr.ToIPv4().GetUsableHostCount())
+		iterator = wrappedAddressIterator[*ipaddr.IPv4Address]{addr.ToIPv4().UsableHostIterator()}
+	} else {
+		count = addr.ToIPv6().GetUsableHostCount()
+		iterator = wrappedAddressIterator[*ipaddr.IPv6Address]{addr.ToIPv6().UsableHostIterator()}
+	}
+	var first, last *ipaddr.Address
+	var iterCount uint64
+	for ; iterator.HasNext(); iterCount++ {
+		last = iterator.Next()
+		if first == nil {
+			first = last
+		}
+	}
+	if !count.IsUint64() || count.Uint64() != expectedCount || iterCount != expectedCount {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("usable host count ", count, " and iterated count ", iterCount, ", expected ", expectedCount), addr))
+	} else if first.String() != expectedFirst || last.String() != expectedLast {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("usable hosts from ", first, " to ", last, ", expected ", expectedFirst, " to ", expectedLast), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testContainsNonZeroHosts(addrStr, otherStr string, expected bool) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	other := ipaddr.NewIPAddressString(otherStr).GetAddress()
+	if result := addr.ContainsNonZeroHosts(other); result != expected {
+		t.addFailure(newIPAddrFailure(fmt.Sprint(addr, " contains non-zero hosts of ", other, " is ", result, ", expected ", expected), addr))
+	} else if addr.GetIPVersion() == other.GetIPVersion() {
+		if addr.IsIPv4() {
+			result = addr.ToIPv4().ContainsNonZeroHosts(other.ToIPv4())
+		} else {
+			result = addr.ToIPv6().ContainsNonZeroHosts(other.ToIPv6())
+		}
+		if result != expected {
+			t.addFailure(newIPAddrFailure(fmt.Sprint(addr, " contains non-zero hosts of ", other, " is ", result, ", expected ", expected), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testBigIntRoundTrip(addrStr string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	value := addr.GetValue()
+	if ipv6Addr := addr.ToIPv6(); ipv6Addr != nil {
+		result, err := ipaddr.NewIPv6AddressFromPrefixedInt(value, addr.GetPrefixLen())
+		if err != nil {
+			t.addFailure(newIPAddrFailure("unexpected error "+err.Error(), addr))
+		} else if !result.Equal(ipv6Addr) || !result.GetPrefixLen().Equal(addr.GetPrefixLen()) || result.GetValue().Cmp(value) != 0 {
+			t.addFailure(newIPAddrFailure("big integer round trip produced "+result.String(), addr))
+		} else if section, err := ipaddr.NewIPv6SectionFromPrefixedBigInt(value, ipaddr.IPv6SegmentCount, addr.GetPrefixLen()); err != nil || !section.Equal(ipv6Addr.GetSection()) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("big integer section round trip produced ", section), addr))
+		}
+	} else {
+		ipv4Addr := addr.ToIPv4()
+		uint32Val := ipv4Addr.Uint32Value()
+		result := ipaddr.NewIPv4AddressFromPrefixedUint32(uint32Val, addr.GetPrefixLen())
+		if !value.IsUint64() || value.Uint64() != uint64(uint32Val) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("big integer value ", value, " does not match integer value ", uint32Val), addr))
+		} else if !result.Equal(ipv4Addr) || !result.GetPrefixLen().Equal(addr.GetPrefixLen()) || result.GetValue().Cmp(value) != 0 {
+			t.addFailure(newIPAddrFailure("integer round trip produced "+result.String(), addr))
+		} else if section := ipaddr.NewIPv4SectionFromPrefixedUint32(uint32Val, ipaddr.IPv4SegmentCount, addr.GetPrefixLen()); !section.Equal(ipv4Addr.GetSection()) {
+			t.addFailure(newIPAddrFailure("integer section round trip produced "+section.String(), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testBigIntSectionRoundTrip(value int64, segmentCount int, prefLen ipaddr.BitCount, expected string) {
+	section, err := ipaddr.NewIPv6SectionFromPrefixedBigInt(big.NewInt(value), segmentCount, ipaddr.ToPrefixLen(int(prefLen)))
+	if err != nil {
+		t.addFailure(newFailure("unexpected error "+err.Error(), nil))
+	} else if section.String() != expected {
+		t.addFailure(newFailure("big integer section was "+section.String()+", expected "+expected, nil))
+	} else if section.GetValue().Cmp(big.NewInt(value)) != 0 || section.GetSegmentCount() != segmentCount ||
+		section.IsPrefixBlock() != section.IncludesZeroHost() {
+		t.addFailure(newFailure("big integer section "+section.String()+" has value "+section.GetValue().String(), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testBigIntSectionOverflow(value *big.Int, segmentCount int) {
+	if section, err := ipaddr.NewIPv6SectionFromPrefixedBigInt(value, segmentCount, nil); err == nil {
+		t.addFailure(newFailure(fmt.Sprint("expected error for ", value, " in ", segmentCount, " segments, got ", section), nil))
+	} else if segmentCount == ipaddr.IPv6SegmentCount {
+		if addr, err := ipaddr.NewIPv6AddressFromPrefixedInt(value, ipaddr.ToPrefixLen(64)); err == nil {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("expected error for ", value), addr.ToIP()))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testHostRanges(addrStr string, maxHost bool, expected ...string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	var ranges []*ipaddr.SequentialRange[*ipaddr.IPAddress]
+	var typedRanges []string
+	if maxHost {
+		ranges = addr.ToMaxHostRanges()
+		if addr.IsIPv4() {
+			for _, rng := range addr.ToIPv4().ToMaxHostRanges() {
+				typedRanges = append(typedRanges, rng.String())
+			}
+		} else {
+			for _, rng := range addr.ToIPv6().ToMaxHostRanges() {
+				typedRanges = append(typedRanges, rng.String())
+			}
+		}
+	} else {
+		ranges = addr.ToZeroHostRanges()
+		if addr.IsIPv4() {
+			for _, rng := range addr.ToIPv4().ToZeroHostRanges() {
+				typedRanges = append(typedRanges, rng.String())
+			}
+		} else {
+			for _, rng := range addr.ToIPv6().ToZeroHostRanges() {
+				typedRanges = append(typedRanges, rng.String())
+			}
+		}
+	}
+	strs := make([]string, 0, len(ranges))
+	for _, rng := range ranges {
+		if rng.IsMultiple() {
+			strs = append(strs, rng.String())
+		} else {
+			strs = append(strs, rng.GetLower().String())
+		}
+	}
+	if !reflect.DeepEqual(strs, expected) {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("host ranges ", strs, ", expected ", expected), addr))
+	} else if len(typedRanges) != len(ranges) {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("host ranges ", typedRanges, ", expected ", ranges), addr))
+	} else {
+		for i, rng := range ranges {
+			if rng.String() != typedRanges[i] {
+				t.addFailure(newIPAddrFailure(fmt.Sprint("host ranges ", typedRanges, ", expected ", ranges), addr))
+				break
+			}
+		}
+	}
+	if host, err := addr.ToZeroHost(); !maxHost && err == nil {
+		var hostStrs []string
+		for iter := host.WithoutPrefixLen().SequentialBlockIterator(); iter.HasNext(); {
+			block := iter.Next()
+			if block.IsMultiple() {
+				hostStrs = append(hostStrs, block.ToSequentialRange().String())
+			} else {
+				hostStrs = append(hostStrs, block.String())
+			}
+		}
+		if !reflect.DeepEqual(hostStrs, expected) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("zero host ", host, " does not match host ranges ", expected), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+var validationOnlyParams = new(addrstrparam.IPAddressStringParamsBuilder).AllowPrefix(false).AllowWildcardedSeparator(false).
+	SetRangeParams(addrstrparam.NoRange).AllowReverseDNS(true).AllowSpaceSeparatedMask(true).ToParams()
+
+func (t ipAddressTester) testValidationOnly(str string) {
+	for _, params := range []addrstrparam.IPAddressStringParams{nil, validationOnlyParams} {
+		addrStr := ipaddr.NewIPAddressStringParams(str, params)
+		if result := ipaddr.IsValidIPv4String(str, params); result != addrStr.IsIPv4() {
+			t.addFailure(newFailure(fmt.Sprint("IPv4 validation of ", str, " was ", result), addrStr))
+		}
+		if result := ipaddr.IsValidIPv6String(str, params); result != addrStr.IsIPv6() {
+			t.addFailure(newFailure(fmt.Sprint("IPv6 validation of ", str, " was ", result), addrStr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAnonymize(addrStr, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	if result, err := addr.Anonymize(24, 48); err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error "+err.Error(), addr))
+	} else if result.String() != expected {
+		t.addFailure(newIPAddrFailure("anonymized to "+result.String()+", expected "+expected, addr))
+	}
+	t.incrementTestCount()
+}
+
+var pseudonymizer = ipaddr.NewPrefixPreservingPseudonymizer([]byte("0123456789abcdef0123456789abcdef"))
+
+func (t ipAddressTester) testPseudonymize(oneStr, twoStr string, sharedPrefLen ipaddr.BitCount) {
+	one, two := ipaddr.NewIPAddressString(oneStr).GetAddress(), ipaddr.NewIPAddressString(twoStr).GetAddress()
+	pseudoOne, err := pseudonymizer.Pseudonymize(one)
+	if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error "+err.Error(), one))
+		return
+	}
+	pseudoTwo, err := pseudonymizer.Pseudonymize(two)
+	if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error "+err.Error(), two))
+		return
+	}
+	again, _ := ipaddr.NewPrefixPreservingPseudonymizer([]byte("0123456789abcdef0123456789abcdef")).Pseudonymize(one)
+	otherKey, _ := ipaddr.NewPrefixPreservingPseudonymizer([]byte("another key")).Pseudonymize(one)
+	if !again.Equal(pseudoOne) || otherKey.Equal(pseudoOne) {
+		t.addFailure(newIPAddrFailure("pseudonymization is not determined by the key: "+pseudoOne.String(), one))
+	} else if pseudoOne.GetIPVersion() != one.GetIPVersion() || pseudoOne.IsPrefixed() || pseudoOne.IsMultiple() {
+		t.addFailure(newIPAddrFailure("unexpected pseudonymous address "+pseudoOne.String(), one))
+	} else if one.Equal(two) != pseudoOne.Equal(pseudoTwo) {
+		t.addFailure(newIPAddrFailure("pseudonymization is not a permutation: "+pseudoOne.String()+" and "+pseudoTwo.String(), one))
+	} else if !one.Equal(two) && (!pseudoOne.ToPrefixBlockLen(sharedPrefLen).Equal(pseudoTwo.ToPrefixBlockLen(sharedPrefLen)) ||
+		pseudoOne.ToPrefixBlockLen(sharedPrefLen+1).Equal(pseudoTwo.ToPrefixBlockLen(sharedPrefLen+1))) {
+		t.addFailure(newIPAddrFailure("pseudonymization "+pseudoOne.String()+" and "+pseudoTwo.String()+" does not preserve the prefix length "+strconv.Itoa(int(sharedPrefLen)), one))
+	}
+	if _, err = pseudonymizer.Pseudonymize(one.ToPrefixBlockLen(sharedPrefLen)); err == nil && sharedPrefLen < one.GetBitCount() {
+		t.addFailure(newIPAddrFailure("expected error pseudonymizing subnet", one))
+	}
+	t.incrementTestCount()
+}
+
+var testInterfaceAddresses = []string{"lo 127.0.0.1/8", "eth0 10.1.0.1/16", "eth0 2001:db8:1::5/64", "eth0 fe80::1%eth0/64",
+	"eth1 192.168.1.5/24", "eth1 fe80::2%eth1/64", "eth2 10.0.0.1/8"}
+
+func (t ipAddressTester) testFindInterfaceAddress(addrStr, expected string) {
+	var ifaceAddrs []ipaddr.InterfaceAddress
+	for _, str := range testInterfaceAddresses {
+		name, addrStr, _ := strings.Cut(str, " ")
+		ifaceAddrs = append(ifaceAddrs, ipaddr.InterfaceAddress{Interface: net.Interface{Name: name}, Address: ipaddr.NewIPAddressString(addrStr).GetAddress()})
+	}
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	result := ipaddr.FindInterfaceAddressFor(ifaceAddrs, addr)
+	if expected == "" {
+		if result != nil {
+			t.addFailure(newIPAddrFailure("unexpected interface "+result.String(), addr))
+		}
+	} else if result == nil {
+		t.addFailure(newIPAddrFailure("no interface found, expected "+expected, addr))
+	} else if result.String() != expected {
+		t.addFailure(newIPAddrFailure("found interface "+result.String()+", expected "+expected, addr))
+	} else if subnet := result.GetSubnet(); !subnet.IsPrefixBlock() || !subnet.Contains(result.Address) {
+		t.addFailure(newIPAddrFailure("interface subnet "+subnet.String()+" does not contain the interface address", addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testLocalInterfaceAddresses() {
+	ifaceAddrs, err := ipaddr.GetInterfaceAddresses()
+	if err != nil {
+		// the local interfaces are not always available
+		t.incrementTestCount()
+		return
+	}
+	for _, ifaceAddr := range ifaceAddrs {
+		addr := ifaceAddr.Address
+		if !addr.IsPrefixed() {
+			t.addFailure(newIPAddrFailure("interface address has no prefix length", addr))
+		} else if addr.IsIPv6() && addr.IsLinkLocal() && string(addr.ToIPv6().GetZone()) != ifaceAddr.Interface.Name {
+			t.addFailure(newIPAddrFailure("link-local interface address zone does not match "+ifaceAddr.Interface.Name, addr))
+		} else if found := ipaddr.FindInterfaceAddressFor(ifaceAddrs, addr.WithoutPrefixLen()); found == nil || !found.GetSubnet().Contains(addr.WithoutPrefixLen()) {
+			t.addFailure(newIPAddrFailure("interface address not found", addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMergeAll(addrStrs, expectedPrefixBlocks, expectedSequentialBlocks []string) {
+	var addrs []*ipaddr.IPAddress
+	for _, str := range addrStrs {
+		if str == "" {
+			addrs = append(addrs, nil) // nil addresses are ignored
+		} else {
+			addrs = append(addrs, ipaddr.NewIPAddressString(str).GetAddress())
+		}
+	}
+	reversed := make([]*ipaddr.IPAddress, len(addrs))
+	for i, addr := range addrs {
+		reversed[len(addrs)-1-i] = addr
+	}
+	checkResult := func(kind string, result, reversedResult []*ipaddr.IPAddress, expected []string) {
+		if len(result) != len(expected) || len(reversedResult) != len(expected) {
+			t.addFailure(newFailure(fmt.Sprint(kind, " merge of ", addrStrs, " was ", result, " and ", reversedResult, ", expected ", expected), nil))
+			return
+		}
+		for i, addr := range result {
+			if expectedAddr := ipaddr.NewIPAddressString(expected[i]).GetAddress(); !addr.Equal(expectedAddr) || !reversedResult[i].Equal(expectedAddr) {
+				t.addFailure(newFailure(fmt.Sprint(kind, " merge of ", addrStrs, " was ", result, " and ", reversedResult, ", expected ", expected), nil))
+				return
+			}
+		}
+	}
+	checkResult("prefix block", ipaddr.MergeAllToPrefixBlocks(addrs...), ipaddr.MergeAllToPrefixBlocks(reversed...), expectedPrefixBlocks)
+	checkResult("sequential block", ipaddr.MergeAllToSequentialBlocks(addrs...), ipaddr.MergeAllToSequentialBlocks(reversed...), expectedSequentialBlocks)
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testNilStrings() {
+	var nilAddr *ipaddr.Address
+	var nilIPAddr *ipaddr.IPAddress
+	var nilIPv4Addr *ipaddr.IPv4Address
+	var nilIPv6Addr *ipaddr.IPv6Address
+	var nilMACAddr *ipaddr.MACAddress
+	var addrTypes = []ipaddr.AddressType{nil, nilAddr, nilIPAddr, nilIPv4Addr, nilIPv6Addr, nilMACAddr}
+	for _, addr := range addrTypes {
+		if str := ipaddr.SafeString(addr); str != "<nil>" {
+			t.addFailure(newFailure("expected <nil> for nil address, got "+str, nil))
+		} else if str = ipaddr.SafeCanonical(addr); str != "<nil>" {
+			t.addFailure(newFailure("expected <nil> canonical string for nil address, got "+str, nil))
+		}
+		t.incrementTestCount()
+	}
+	for _, str := range []string{"1.2.3.4", "1.2.0.0/16", "a:b::c%eth0", "0-1:2::/64"} {
+		addr := t.createAddress(str).GetAddress()
+		if safeStr := ipaddr.SafeString(addr); safeStr != addr.String() {
+			t.addFailure(newIPAddrFailure("expected "+addr.String()+" got "+safeStr, addr))
+		} else if safeStr = ipaddr.SafeCanonical(addr.ToIPv4()); addr.IsIPv4() && safeStr != addr.ToCanonicalString() {
+			t.addFailure(newIPAddrFailure("expected canonical "+addr.ToCanonicalString()+" got "+safeStr, addr))
+		} else if safeStr = ipaddr.SafeCanonical(addr.ToIPv6()); addr.IsIPv6() && safeStr != addr.ToCanonicalString() {
+			t.addFailure(newIPAddrFailure("expected canonical "+addr.ToCanonicalString()+" got "+safeStr, addr))
+		}
+		t.incrementTestCount()
+	}
+	macAddr := ipaddr.NewMACAddressString("a:b:c:d:e:f").GetAddress()
+	if str := ipaddr.SafeCanonical(macAddr); str != macAddr.ToCanonicalString() {
+		t.addFailure(newFailure("expected canonical "+macAddr.ToCanonicalString()+" got "+str, nil))
+	}
+	t.incrementTestCount()
+
+	var nilRange *ipaddr.IPAddressSeqRange
+	var nilTrie *ipaddr.Trie[*ipaddr.IPAddress]
+	var nilAssocTrie *ipaddr.AssociativeTrie[*ipaddr.IPAddress, int]
+	var nilSubTrie *ipaddr.SubTrie[*ipaddr.IPAddress]
+	var nilTrieNode *ipaddr.TrieNode[*ipaddr.IPAddress]
+	var nilAssocTrieNode *ipaddr.AssociativeTrieNode[*ipaddr.IPAddress, int]
+	var nilPath *ipaddr.ContainmentPath[*ipaddr.IPAddress]
+	var nilValuesPath *ipaddr.ContainmentValuesPath[*ipaddr.IPAddress, int]
+	var nilPathNode *ipaddr.ContainmentPathNode[*ipaddr.IPAddress]
+	var nilValuesPathNode *ipaddr.ContainmentValuesPathNode[*ipaddr.IPAddress, int]
+	var nilRangeTree *ipaddr.RangeTree[*ipaddr.IPAddress]
+	var nilRangeStr *ipaddr.SeqRangeString
+	var nilTemplate *ipaddr.AddressTemplate
+	var nilZone *ipaddr.ReverseDNSZone
+	var nilHost *ipaddr.HostName
+	var nilAddrStr *ipaddr.IPAddressString
+	var nilMACAddrStr *ipaddr.MACAddressString
+	var nilSection *ipaddr.IPAddressSection
+	var nilSegment *ipaddr.IPAddressSegment
+	stringers := []fmt.Stringer{
+		nilAddr, nilIPAddr, nilIPv4Addr, nilIPv6Addr, nilMACAddr,
+		nilRange, nilTrie, nilAssocTrie, nilSubTrie, nilTrieNode, nilAssocTrieNode,
+		nilPath, nilValuesPath, nilPathNode, nilValuesPathNode,
+		nilRangeTree, nilRangeStr, nilTemplate, nilZone,
+		nilHost, nilAddrStr, nilMACAddrStr, nilSection, nilSegment,
+	}
+	for i, stringer := range stringers {
+		t.checkNilString(i, stringer)
+	}
+}
+
+func (t ipAddressTester) checkNilString(index int, stringer fmt.Stringer) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.addFailure(newFailure(fmt.Sprintf("String of nil %T at index %d panicked: %v", stringer, index, r), nil))
+		}
+	}()
+	if str := stringer.String(); str != "<nil>" {
+		t.addFailure(newFailure(fmt.Sprintf("expected <nil> for nil %T, got %s", stringer, str), nil))
+	} else if str = fmt.Sprintf("%v", stringer); str != "<nil>" {
+		t.addFailure(newFailure(fmt.Sprintf("expected <nil> from %%v for nil %T, got %s", stringer, str), nil))
+	}
+	t.incrementTestCount()
+}
+
+// testNPTv6 checks the translation of addrStr with the translator between the given prefixes, an empty expected string meaning an error is expected.
+// A translation is also checked for being checksum-neutral and for translating back.
+func (t ipAddressTester) testNPTv6(insideStr, outsideStr, addrStr, expectedStr string) {
+	inside := t.createAddress(insideStr).GetAddress().ToIPv6()
+	outside := t.createAddress(outsideStr).GetAddress().ToIPv6()
+	addr := t.createAddress(addrStr).GetAddress()
+	translator, err := ipaddr.NewNPTv6Translator(inside, outside)
+	if err != nil {
+		if expectedStr != "" {
+			t.addFailure(newIPAddrFailure("unexpected translator error "+err.Error(), addr))
+		}
+		t.incrementTestCount()
+		return
+	}
+	translated, err := translator.Translate(addr.ToIPv6())
+	if err != nil || translated == nil {
+		if expectedStr != "" {
+			t.addFailure(newIPAddrFailure("no translation to "+expectedStr, addr))
+		}
+		t.incrementTestCount()
+		return
+	} else if expectedStr == "" {
+		t.addFailure(newIPAddrFailure("expected translation error, got "+translated.String(), addr))
+	} else if expected := t.createAddress(expectedStr).GetAddress(); !translated.Equal(expected) || !translated.GetPrefixLen().Equal(expected.GetPrefixLen()) {
+		t.addFailure(newIPAddrFailure("translation "+translated.String()+" does not match "+expected.String(), addr))
+	}
+	inside, outside = translator.GetInsidePrefix(), translator.GetOutsidePrefix()
+	if !outside.Contains(translated) {
+		t.addFailure(newIPAddrFailure("translation "+translated.String()+" not in "+outside.String(), addr))
+	} else if onesSum(translated) != onesSum(addr.ToIPv6()) {
+		t.addFailure(newIPAddrFailure("translation "+translated.String()+" is not checksum neutral", addr))
+	} else if back, err := translator.Untranslate(translated); err != nil {
+		t.addFailure(newIPAddrFailure("unexpected reverse translation error "+err.Error(), addr))
+	} else if !back.Equal(addr) {
+		t.addFailure(newIPAddrFailure("reverse translation "+back.String()+" does not match", addr))
+	} else if _, err := translator.Untranslate(addr.ToIPv6()); err == nil && !inside.Contains(outside) && !outside.Contains(inside) {
+		t.addFailure(newIPAddrFailure("expected error translating inside address from outside", addr))
+	}
+	t.incrementTestCount()
+}
+
+// testNPTv6ZeroHost checks that a single address with a zero host and a prefix length translates to a single address, not a prefix block
+func (t ipAddressTester) testNPTv6ZeroHost(insideStr, outsideStr, addrStr string, prefLen ipaddr.BitCount, expectedStr string) {
+	inside := t.createAddress(insideStr).GetAddress().ToIPv6()
+	outside := t.createAddress(outsideStr).GetAddress().ToIPv6()
+	addr := t.createAddress(addrStr).GetAddress().ToIPv6().SetPrefixLen(prefLen)
+	expected := t.createAddress(expectedStr).GetAddress().ToIPv6().SetPrefixLen(prefLen)
+	translator, err := ipaddr.NewNPTv6Translator(inside, outside)
+	if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected translator error "+err.Error(), addr.ToIP()))
+	} else if translated, err := translator.Translate(addr); err != nil {
+		t.addFailure(newIPAddrFailure("unexpected translation error "+err.Error(), addr.ToIP()))
+	} else if translated.IsMultiple() || !translated.Equal(expected) || !translated.GetPrefixLen().Equal(expected.GetPrefixLen()) {
+		t.addFailure(newIPAddrFailure("translation "+translated.String()+" does not match "+expected.String(), addr.ToIP()))
+	} else if back, err := translator.Untranslate(translated); err != nil || back.IsMultiple() || !back.Equal(addr) {
+		t.addFailure(newIPAddrFailure("reverse translation does not match", addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+// onesSum returns the one's complement sum of the segments, with 0xffff, negative zero, normalized to 0
+func onesSum(addr *ipaddr.IPv6Address) uint32 {
+	var sum uint32
+	for _, seg := range addr.GetSegments() {
+		sum += uint32(seg.GetSegmentValue())
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	if sum == 0xffff {
+		sum = 0
+	}
+	return sum
+}
+
+// segmentSeriesWithoutRadix hides the SegmentStringsRadixProvider method of the wrapped series
+type segmentSeriesWithoutRadix struct {
+	ipaddr.AddressSegmentSeries
+}
+
+func (t ipAddressTester) testSegmentStringsRadix(addrStr string, radix int, uppercase, leadingZeros bool, expected []string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	options := ipaddr.SegmentStringOptions{Radix: radix, Uppercase: uppercase, LeadingZeros: leadingZeros}
+	results := [][]string{
+		addr.GetSegmentStringsRadix(radix, uppercase, leadingZeros),
+		addr.ToAddressBase().GetSegmentStringsRadix(radix, uppercase, leadingZeros),
+		addr.GetSection().GetSegmentStringsRadix(radix, uppercase, leadingZeros),
+		addr.GetSection().ToSectionBase().GetSegmentStringsRadix(radix, uppercase, leadingZeros),
+		options.GetSegmentStrings(addr),
+		options.GetSegmentStrings(addr.Wrap()),
+		options.GetSegmentStrings(segmentSeriesWithoutRadix{addr}),
+	}
+	if addr.IsIPv4() {
+		results = append(results, addr.ToIPv4().GetSegmentStringsRadix(radix, uppercase, leadingZeros),
+			addr.ToIPv4().GetSection().GetSegmentStringsRadix(radix, uppercase, leadingZeros))
+	} else {
+		results = append(results, addr.ToIPv6().GetSegmentStringsRadix(radix, uppercase, leadingZeros),
+			addr.ToIPv6().GetSection().GetSegmentStringsRadix(radix, uppercase, leadingZeros))
+	}
+	for _, result := range results {
+		if !reflect.DeepEqual(result, expected) {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("segment strings %v in radix %d do not match expected %v", result, radix, expected), addr))
+			break
+		}
+	}
+	if radix == 16 && !leadingZeros && !uppercase && !addr.IsMultiple() {
+		mac := ipaddr.NewMACAddressString("a:b:c:d:e:f").GetAddress()
+		if strs := mac.GetSegmentStringsRadix(radix, uppercase, leadingZeros); !reflect.DeepEqual(strs, mac.GetSegmentStrings()) {
+			t.addFailure(newFailure(fmt.Sprintf("segment strings %v do not match %v", strs, mac.GetSegmentStrings()), nil))
+		} else if strs = mac.GetSection().GetSegmentStringsRadix(radix, true, true); !reflect.DeepEqual(strs, []string{"0A", "0B", "0C", "0D", "0E", "0F"}) {
+			t.addFailure(newFailure(fmt.Sprintf("segment strings %v do not match", strs), nil))
+		}
+	}
+	var nilAddr *ipaddr.IPv4Address
+	if strs := options.GetSegmentStrings(nilAddr); strs != nil {
+		t.addFailure(newFailure(fmt.Sprintf("expected nil segment strings for nil address, got %v", strs), nil))
+	} else if strs = options.GetSegmentStrings(nil); strs != nil {
+		t.addFailure(newFailure(fmt.Sprintf("expected nil segment strings for nil series, got %v", strs), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testParserCache() {
+	cache := ipaddr.NewParserCache(3)
+	addr := cache.GetAddress("1.2.3.4")
+	if addr == nil || addr.String() != "1.2.3.4" {
+		t.addFailure(newIPAddrFailure("unexpected parsed address", addr))
+	} else if again := cache.GetAddress("1.2.3.4"); again != addr {
+		t.addFailure(newIPAddrFailure("repeated parse did not share result "+again.String(), addr))
+	} else if stats := cache.GetStats(); stats != (ipaddr.ParserCacheStats{Hits: 1, Misses: 1, Size: 1}) {
+		t.addFailure(newIPAddrFailure("unexpected stats "+stats.String(), addr))
+	} else if ratio := stats.GetHitRatio(); ratio != 0.5 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected hit ratio %v", ratio), addr))
+	}
+	t.incrementTestCount()
+
+	// an invalid string is cached with its error
+	if addr, err := cache.ToAddress("1.2.3.4.5"); addr != nil || err == nil {
+		t.addFailure(newFailure("expected error for invalid string", nil))
+	} else if _, again := cache.ToAddress("1.2.3.4.5"); again != err {
+		t.addFailure(newFailure("expected cached error for invalid string", nil))
+	}
+	t.incrementTestCount()
+
+	// the least recently used is evicted: 1.2.3.4 was used more recently than 1.2.3.4.5
+	cache.GetAddress("1.2.3.4")
+	cache.GetAddress("a::b")
+	cache.GetAddress("1.0.0.0/8")
+	if stats := cache.GetStats(); stats != (ipaddr.ParserCacheStats{Hits: 3, Misses: 4, Evictions: 1, Size: 3}) {
+		t.addFailure(newFailure("unexpected stats after eviction "+stats.String(), nil))
+	} else if cache.GetAddress("1.2.3.4") != addr {
+		t.addFailure(newIPAddrFailure("expected cached result after eviction of another", addr))
+	} else if stats = cache.GetStats(); stats.Hits != 4 {
+		t.addFailure(newFailure("expected hit after eviction of another "+stats.String(), nil))
+	}
+	cache.ToAddress("1.2.3.4.5")
+	if stats := cache.GetStats(); stats.Misses != 5 || stats.Evictions != 2 || cache.Len() != 3 {
+		t.addFailure(newFailure("expected miss for evicted string "+stats.String(), nil))
+	}
+	cache.Clear()
+	if stats := cache.GetStats(); stats.Size != 0 || stats.Hits != 4 || cache.Len() != 0 {
+		t.addFailure(newFailure("unexpected stats after clear "+stats.String(), nil))
+	} else if again := cache.GetAddress("1.2.3.4"); again == addr || !again.Equal(addr) {
+		t.addFailure(newIPAddrFailure("expected new equal result after clear", again))
+	}
+	t.incrementTestCount()
+
+	// the cache uses its parameters
+	ipv4Params := new(addrstrparam.IPAddressStringParamsBuilder).AllowIPv6(false).ToParams()
+	ipv4Cache := ipaddr.NewParserCacheParams(10, ipv4Params)
+	if ipv4Cache.GetAddress("a::b") != nil {
+		t.addFailure(newFailure("expected IPv6 to be disallowed by cache parameters", nil))
+	} else if ipv4Cache.GetAddress("1.2.3.4") == nil {
+		t.addFailure(newFailure("expected IPv4 to be allowed by cache parameters", nil))
+	} else if ipaddr.NewParserCacheParams(10, nil).GetAddress("a::b") == nil {
+		t.addFailure(newFailure("expected default parameters for nil parameters", nil))
+	}
+	t.incrementTestCount()
+
+	// a cache with no capacity parses every time
+	noCache := ipaddr.NewParserCache(0)
+	if one, two := noCache.GetAddress("1.2.3.4"), noCache.GetAddress("1.2.3.4"); one == two || !one.Equal(two) {
+		t.addFailure(newIPAddrFailure("expected distinct equal results without capacity", one))
+	} else if stats := noCache.GetStats(); stats != (ipaddr.ParserCacheStats{Misses: 2}) {
+		t.addFailure(newFailure("unexpected stats without capacity "+stats.String(), nil))
+	}
+	t.incrementTestCount()
+
+	// concurrent use
+	sharedCache := ipaddr.NewParserCache(8)
+	strs := []string{"1.2.3.4", "1.2.3.5", "a::b", "a::c", "10.0.0.0/8", "1.2.3.4.5"}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				for _, str := range strs {
+					sharedCache.GetAddress(str)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if stats := sharedCache.GetStats(); stats.Hits+stats.Misses != 8*50*uint64(len(strs)) || stats.Size != len(strs) || stats.Evictions != 0 {
+		t.addFailure(newFailure("unexpected stats after concurrent use "+stats.String(), nil))
+	}
+	for _, str := range strs {
+		if addr := sharedCache.GetAddress(str); addr != sharedCache.GetAddress(str) {
+			t.addFailure(newIPAddrFailure("expected shared result after concurrent use", addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testCIDRList(cidrs, expected []string, expectedErrLines []int) {
+	blocks, err := ipaddr.FromCIDRList(cidrs)
+	if expectedErrLines != nil {
+		if err == nil || blocks != nil {
+			t.addFailure(newFailure(fmt.Sprintf("expected error for lines %v of %v, got %v", expectedErrLines, cidrs, blocks), nil))
+		} else {
+			for _, line := range expectedErrLines {
+				if !strings.Contains(err.Error(), "line "+strconv.Itoa(line)+":") {
+					t.addFailure(newFailure(fmt.Sprintf("line %d not reported in error %v", line, err), nil))
+				}
+			}
+			if strings.Count(err.Error(), "line ") != len(expectedErrLines) {
+				t.addFailure(newFailure(fmt.Sprintf("expected %d lines reported in error %v", len(expectedErrLines), err), nil))
+			}
+		}
+		t.incrementTestCount()
+		return
+	} else if err != nil {
+		t.addFailure(newFailure(fmt.Sprintf("unexpected error %v parsing %v", err, cidrs), nil))
+		return
+	}
+	strs, err := ipaddr.ToCIDRList(blocks)
+	if err != nil {
+		t.addFailure(newFailure(fmt.Sprintf("unexpected error %v writing %v", err, blocks), nil))
+	} else if !reflect.DeepEqual(strs, expected) {
+		t.addFailure(newFailure(fmt.Sprintf("expected %v, got %v", expected, strs), nil))
+	} else if again, err := ipaddr.FromCIDRList(strs); err != nil || len(again) != len(blocks) {
+		t.addFailure(newFailure(fmt.Sprintf("round trip of %v failed with %v", strs, err), nil))
+	} else {
+		for i, block := range blocks {
+			if !block.IsSinglePrefixBlock() || !again[i].Equal(block) {
+				t.addFailure(newIPAddrFailure("round trip mismatch with "+again[i].String(), block))
+			}
+		}
+	}
+
+	// addresses that are not prefix blocks are rejected when writing, with their indices
+	if len(blocks) > 0 {
+		invalid := append([]*ipaddr.IPAddress{nil}, blocks...)
+		invalid = append(invalid, ipaddr.NewIPAddressString("1.2.3.4/16").GetAddress())
+		if strs, err := ipaddr.ToCIDRList(invalid); err == nil || strs != nil {
+			t.addFailure(newFailure(fmt.Sprintf("expected error writing %v", invalid), nil))
+		} else if msg := err.Error(); !strings.Contains(msg, "index 0:") || !strings.Contains(msg, "index "+strconv.Itoa(len(invalid)-1)+":") ||
+			strings.Count(msg, "index ") != 2 {
+			t.addFailure(newFailure("unexpected error writing invalid addresses "+msg, nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testDelegationPool() {
+	parent := ipaddr.NewIPAddressString("2001:db8::/48").GetAddress().ToIPv6()
+	pool, err := ipaddr.NewDelegationPool(parent, ipaddr.DelegationPoolOptions{})
+	if err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error "+err.Error(), parent.ToIP()))
+		return
+	}
+	delegate := func(id, expected string) {
+		if prefix := pool.Delegate(id); prefix.String() != expected {
+			t.addFailure(newIPAddrFailure("delegated "+prefix.String()+" to "+id+", expected "+expected, parent.ToIP()))
+		} else if lease := pool.GetLease(id); lease != prefix {
+			t.addFailure(newIPAddrFailure("lease of "+id+" is "+lease.String()+", expected "+expected, parent.ToIP()))
+		}
+		t.incrementTestCount()
+	}
+	delegate("a", "2001:db8::/64")
+	delegate("b", "2001:db8:0:1::/64")
+	delegate("c", "2001:db8:0:2::/64")
+	delegate("b", "2001:db8:0:1::/64")
+	if !pool.Release("b") || pool.Release("b") || pool.GetLease("b") != nil || pool.GetLeaseCount() != 2 {
+		t.addFailure(newIPAddrFailure("release of b failed", parent.ToIP()))
+	}
+	delegate("d", "2001:db8:0:1::/64")
+	delegate("e", "2001:db8:0:3::/64")
+	if leases := fmt.Sprint(pool.GetLeases()); leases != "[a 2001:db8::/64 d 2001:db8:0:1::/64 c 2001:db8:0:2::/64 e 2001:db8:0:3::/64]" {
+		t.addFailure(newIPAddrFailure("unexpected leases "+leases, parent.ToIP()))
+	} else if count := pool.GetSpace().GetAllocatedCount(); count.Cmp(new(big.Int).Lsh(big.NewInt(4), 64)) != 0 {
+		t.addFailure(newIPAddrFailure("unexpected delegated count "+count.String(), parent.ToIP()))
+	}
+
+	// persist the pool and restore it into a new pool
+	bytes, jsonErr := json.Marshal(pool.Snapshot())
+	var snapshot ipaddr.DelegationPoolSnapshot
+	if jsonErr == nil {
+		jsonErr = json.Unmarshal(bytes, &snapshot)
+	}
+	restored, _ := ipaddr.NewDelegationPool(parent, ipaddr.DelegationPoolOptions{})
+	if jsonErr != nil {
+		t.addFailure(newIPAddrFailure("unexpected error "+jsonErr.Error(), parent.ToIP()))
+	} else if err := restored.Restore(snapshot); err != nil {
+		t.addFailure(newIPAddrFailure("unexpected error restoring "+string(bytes)+": "+err.Error(), parent.ToIP()))
+	} else if !reflect.DeepEqual(fmt.Sprint(restored.GetLeases()), fmt.Sprint(pool.GetLeases())) {
+		t.addFailure(newIPAddrFailure("restored leases "+fmt.Sprint(restored.GetLeases()), parent.ToIP()))
+	} else if prefix := restored.Delegate("f"); prefix.String() != "2001:db8:0:4::/64" {
+		t.addFailure(newIPAddrFailure("delegated "+prefix.String()+" after restore", parent.ToIP()))
+	}
+	for _, invalid := range []ipaddr.DelegationPoolSnapshot{
+		{Parent: "2001:db9::/48", PrefixLen: 64},
+		{Parent: "2001:db8::/48", PrefixLen: 56},
+		{Parent: "2001:db8::/48", PrefixLen: 64, Leases: map[string]string{"a": "2001:db8::/64", "b": "2001:db8::/64"}},
+		{Parent: "2001:db8::/48", PrefixLen: 64, Leases: map[string]string{"a": "2001:db9::/64"}},
+		{Parent: "2001:db8::/48", PrefixLen: 64, Leases: map[string]string{"a": "2001:db8::/56"}},
+		{Parent: "2001:db8::/48", PrefixLen: 64, Leases: map[string]string{"a": "2001:db8::1/64"}},
+		{Parent: "2001:db8::/48", PrefixLen: 64, Leases: map[string]string{"a": "1.2.3.0/24"}},
+	} {
+		if err := restored.Restore(invalid); err == nil {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("expected failure restoring ", invalid), parent.ToIP()))
+		} else if restored.GetLeaseCount() != 5 {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("failure restoring ", invalid, " changed the pool"), parent.ToIP()))
+		}
+		t.incrementTestCount()
+	}
+
+	// random delegation of /62 prefixes from a /60 eventually exhausts the pool
+	smallParent := ipaddr.NewIPAddressString("2001:db8:0:10::/60").GetAddress().ToIPv6()
+	pool, _ = ipaddr.NewDelegationPool(smallParent, ipaddr.DelegationPoolOptions{PrefixLen: 62, Order: ipaddr.RandomDelegation, Random: rand.New(rand.NewSource(1))})
+	delegated := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		prefix := pool.Delegate(strconv.Itoa(i))
+		if prefix == nil || !smallParent.Contains(prefix) || prefix.GetPrefixLen().Len() != 62 || !prefix.IsSinglePrefixBlock() || delegated[prefix.String()] {
+			t.addFailure(newIPAddrFailure("invalid random delegation "+prefix.String(), smallParent.ToIP()))
+		}
+		delegated[prefix.String()] = true
+	}
+	if prefix := pool.Delegate("4"); prefix != nil {
+		t.addFailure(newIPAddrFailure("delegated "+prefix.String()+" from exhausted pool", smallParent.ToIP()))
+	}
+	pool.Release("2")
+	if prefix := pool.Delegate("4"); prefix == nil || len(delegated) != 4 || !delegated[prefix.String()] {
+		t.addFailure(newIPAddrFailure("unexpected delegation "+prefix.String()+" after release", smallParent.ToIP()))
+	}
+
+	// delegate every prefix of a /52, then release some and check they are delegated again from the lowest
+	largeParent := ipaddr.NewIPAddressString("2001:db8:0:f000::/52").GetAddress().ToIPv6()
+	for _, order := range []ipaddr.DelegationOrder{ipaddr.SequentialDelegation, ipaddr.RandomDelegation} {
+		pool, _ = ipaddr.NewDelegationPool(largeParent, ipaddr.DelegationPoolOptions{Order: order, Random: rand.New(rand.NewSource(1))})
+		delegated = map[string]bool{}
+		prefixIter := largeParent.SetPrefixLen(64).PrefixBlockIterator()
+		for i := 0; prefixIter.HasNext(); i++ {
+			prefix := pool.Delegate(strconv.Itoa(i))
+			if expected := prefixIter.Next(); prefix == nil || !largeParent.Contains(prefix) || delegated[prefix.String()] ||
+				(order == ipaddr.SequentialDelegation && !prefix.Equal(expected)) {
+				t.addFailure(newIPAddrFailure("invalid delegation "+prefix.String()+" of "+strconv.Itoa(i), largeParent.ToIP()))
+				break
+			}
+			delegated[prefix.String()] = true
+		}
+		if prefix := pool.Delegate("4096"); prefix != nil {
+			t.addFailure(newIPAddrFailure("delegated "+prefix.String()+" from exhausted pool", largeParent.ToIP()))
+		}
+		var released []*ipaddr.IPv6Address
+		for _, id := range []string{"4095", "7", "2048", "6", "4", "5"} {
+			released = append(released, pool.GetLease(id))
+			pool.Release(id)
+		}
+		if freeBlocks := fmt.Sprint(pool.GetSpace().GetFreeBlocks()); order == ipaddr.SequentialDelegation &&
+			freeBlocks != "[2001:db8:0:f004::/62 2001:db8:0:f800::/64 2001:db8:0:ffff::/64]" {
+			t.addFailure(newIPAddrFailure("unexpected free blocks "+freeBlocks, largeParent.ToIP()))
+		}
+		sort.Slice(released, func(i, j int) bool {
+			return released[i].Compare(released[j]) < 0
+		})
+		for i, expected := range released {
+			prefix := pool.Delegate("released" + strconv.Itoa(i))
+			if order == ipaddr.SequentialDelegation && !prefix.Equal(expected) {
+				t.addFailure(newIPAddrFailure("delegated "+prefix.String()+" after release, expected "+expected.String(), largeParent.ToIP()))
+			} else if prefix == nil || !delegated[prefix.String()] {
+				t.addFailure(newIPAddrFailure("delegated "+prefix.String()+" after release", largeParent.ToIP()))
+			}
+		}
+		if prefix := pool.Delegate("4096"); prefix != nil {
+			t.addFailure(newIPAddrFailure("delegated "+prefix.String()+" from exhausted pool", largeParent.ToIP()))
+		}
+		t.incrementTestCount()
+	}
+
+	if _, err := ipaddr.NewDelegationPool(parent, ipaddr.DelegationPoolOptions{PrefixLen: 40}); err == nil {
+		t.addFailure(newIPAddrFailure("expected failure with prefix length 40", parent.ToIP()))
+	} else if _, err := ipaddr.NewDelegationPool(ipaddr.NewIPAddressString("2001:db8::1/48").GetAddress().ToIPv6(), ipaddr.DelegationPoolOptions{}); err == nil {
+		t.addFailure(newIPAddrFailure("expected failure with parent that is not a block", parent.ToIP()))
+	} else if _, err := ipaddr.NewDelegationPool(nil, ipaddr.DelegationPoolOptions{}); err == nil {
+		t.addFailure(newIPAddrFailure("expected failure with nil parent", parent.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMustParse() {
+	if addr := ipaddr.MustParseIP("1.2.3.4/16"); addr.String() != "1.2.3.4/16" {
+		t.addFailure(newIPAddrFailure("unexpected MustParseIP result", addr))
+	}
+	if addr := ipaddr.MustParseIPv4("1.2.*.4"); addr.String() != "1.2.*.4" {
+		t.addFailure(newIPAddrFailure("unexpected MustParseIPv4 result", addr.ToIP()))
+	}
+	if addr := ipaddr.MustParseIPv6("a:b::%eth0"); addr.String() != "a:b::%eth0" {
+		t.addFailure(newIPAddrFailure("unexpected MustParseIPv6 result", addr.ToIP()))
+	}
+	if addr := ipaddr.MustParseCIDR("1.2.0.0/16"); addr.String() != "1.2.0.0/16" || !addr.IsSinglePrefixBlock() {
+		t.addFailure(newIPAddrFailure("unexpected MustParseCIDR result", addr))
+	}
+	if addr := ipaddr.MustParseCIDR("a:b::1"); addr.String() != "a:b::1/128" {
+		t.addFailure(newIPAddrFailure("unexpected MustParseCIDR result", addr))
+	}
+	if addr := ipaddr.MustParseMAC("aa:bb:cc:dd:ee:ff"); addr.String() != "aa:bb:cc:dd:ee:ff" {
+		t.addFailure(newMACAddrFailure("unexpected MustParseMAC result", addr))
+	}
+	t.incrementTestCount()
+
+	t.checkMustParsePanics("MustParseIP", "1.2.3.4.5", func(str string) { ipaddr.MustParseIP(str) })
+	t.checkMustParsePanics("MustParseIP", "*", func(str string) { ipaddr.MustParseIP(str) })
+	t.checkMustParsePanics("MustParseIPv4", "a:b::", func(str string) { ipaddr.MustParseIPv4(str) })
+	t.checkMustParsePanics("MustParseIPv4", "1.2.3.x", func(str string) { ipaddr.MustParseIPv4(str) })
+	t.checkMustParsePanics("MustParseIPv6", "1.2.3.4", func(str string) { ipaddr.MustParseIPv6(str) })
+	t.checkMustParsePanics("MustParseCIDR", "1.2.3.4/16", func(str string) { ipaddr.MustParseCIDR(str) })
+	t.checkMustParsePanics("MustParseCIDR", "", func(str string) { ipaddr.MustParseCIDR(str) })
+	t.checkMustParsePanics("MustParseMAC", "aa:bb:cc:dd:ee:fg", func(str string) { ipaddr.MustParseMAC(str) })
+}
+
+func (t ipAddressTester) checkMustParsePanics(funcName, str string, parse func(string)) {
+	defer func() {
+		recovered := recover()
+		if err, ok := recovered.(error); !ok {
+			t.addFailure(newFailure(fmt.Sprintf("%s(%q) did not panic with an error, recovered %v", funcName, str, recovered), nil))
+		} else if prefix := fmt.Sprintf("ipaddr: %s(%q): ", funcName, str); !strings.HasPrefix(err.Error(), prefix) {
+			t.addFailure(newFailure(fmt.Sprintf("%s(%q) panic message %q does not start with %q", funcName, str, err.Error(), prefix), nil))
+		} else if !errors.As(err, new(addrerr.AddressStringError)) {
+			t.addFailure(newFailure(fmt.Sprintf("%s(%q) panic does not wrap the parsing error", funcName, str), nil))
+		}
+		t.incrementTestCount()
+	}()
+	parse(str)
+}
+
+func (t ipAddressTester) testIPAddressPort(str, expectedStr, expectedAddr string, expectedPort uint16, expectedZone string) {
+	addrPort, err := ipaddr.ParseIPAddressPort(str)
+	if err != nil {
+		t.addFailure(newFailure("unexpected error parsing "+str+": "+err.Error(), nil))
+		return
+	}
+	addr := addrPort.GetAddress()
+	if addrPort.String() != expectedStr || addr.String() != expectedAddr || addrPort.GetPort() != expectedPort || string(addrPort.GetZone()) != expectedZone {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("parsed %s to %v with port %d and zone %q", str, addrPort, addrPort.GetPort(), addrPort.GetZone()), addr))
+	} else if !addrPort.Equal(ipaddr.NewIPAddressPort(ipaddr.NewIPAddressString(expectedAddr).GetAddress(), expectedPort)) {
+		t.addFailure(newIPAddrFailure("parsed "+str+" not equal to constructed", addr))
+	} else if host := addrPort.ToHostName(); host.GetPort() == nil || uint16(*host.GetPort()) != expectedPort || !host.AsAddress().Equal(addr) {
+		t.addFailure(newIPAddrFailure("host name "+host.String()+" does not match "+expectedStr, addr))
+	}
+
+	// the key can be used with Go operators and maps
+	keys := map[ipaddr.IPAddressPortKey]int{addrPort.ToKey(): 1}
+	if again, _ := ipaddr.ParseIPAddressPort(str); keys[again.ToKey()] != 1 || !addrPort.ToKey().ToAddressPort().Equal(addrPort) {
+		t.addFailure(newIPAddrFailure("key mismatch for "+str, addr))
+	}
+	other := ipaddr.NewIPAddressPort(addr, expectedPort^1)
+	if other.Equal(addrPort) || keys[other.ToKey()] != 0 || (addrPort.Compare(other) < 0) != (expectedPort < other.GetPort()) {
+		t.addFailure(newIPAddrFailure("port not distinguished for "+str, addr))
+	} else if addrPort.Compare(addrPort) != 0 || (*ipaddr.IPAddressPort)(nil).Compare(addrPort) >= 0 || addrPort.Compare(nil) <= 0 {
+		t.addFailure(newIPAddrFailure("unexpected comparison for "+str, addr))
+	}
+
+	// conversion to the standard library uses the lowest address of a subnet
+	lower := addr.GetLower().WithoutPrefixLen()
+	tcpAddr, udpAddr, netipAddrPort := addrPort.ToNetTCPAddr(), addrPort.ToNetUDPAddr(), addrPort.ToNetNetIPAddrPort()
+	if tcpAddr.Port != int(expectedPort) || tcpAddr.Zone != expectedZone || !tcpAddr.IP.Equal(lower.GetNetIP()) {
+		t.addFailure(newIPAddrFailure("unexpected TCP address "+tcpAddr.String(), addr))
+	} else if udpAddr.String() != tcpAddr.String() {
+		t.addFailure(newIPAddrFailure("unexpected UDP address "+udpAddr.String(), addr))
+	} else if netipAddrPort.Port() != expectedPort || netipAddrPort.Addr().Zone() != expectedZone {
+		t.addFailure(newIPAddrFailure("unexpected netip address "+netipAddrPort.String(), addr))
+	} else if !addr.IsMultiple() {
+		expected := ipaddr.NewIPAddressPort(addr.WithoutPrefixLen(), expectedPort)
+		if back, err := ipaddr.NewIPAddressPortFromNetTCPAddr(tcpAddr); err != nil || !back.Equal(expected) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("TCP round trip produced ", back, " ", err), addr))
+		} else if back, err := ipaddr.NewIPAddressPortFromNetUDPAddr(udpAddr); err != nil || !back.Equal(expected) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("UDP round trip produced ", back, " ", err), addr))
+		} else if back := ipaddr.NewIPAddressPortFromNetNetIPAddrPort(netipAddrPort); !back.Equal(expected) {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("netip round trip produced ", back), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testIPAddressPortInvalid(str string) {
+	if addrPort, err := ipaddr.ParseIPAddressPort(str); err == nil {
+		t.addFailure(newFailure("expected error parsing "+str+", got "+addrPort.String(), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testParseAll() {
+	strs := []string{"1.2.3.4", "a:b::/64", "1.2.3.4.5", "/16", "1.2.*.4", "::1%eth0", "a:b:c:d:e:f:a:b:c"}
+	valid := []bool{true, true, false, false, true, true, false}
+	check := func(addrs []*ipaddr.IPAddress, errs []error, strs []string, valid []bool, params addrstrparam.IPAddressStringParams) {
+		if len(addrs) != len(strs) || len(errs) != len(strs) {
+			t.addFailure(newFailure(fmt.Sprint("parsed ", len(addrs), " addresses and ", len(errs), " errors from ", len(strs), " strings"), nil))
+			return
+		}
+		for i, str := range strs {
+			if !valid[i] {
+				if addrs[i] != nil || errs[i] == nil {
+					t.addFailure(newFailure(fmt.Sprint("expected error parsing ", str, " at index ", i, ", parsed ", addrs[i]), nil))
+				}
+			} else if errs[i] != nil {
+				t.addFailure(newFailure(fmt.Sprint("unexpected error parsing ", str, " at index ", i, ": ", errs[i]), nil))
+			} else if expected := ipaddr.NewIPAddressStringParams(str, params).GetAddress(); !addrs[i].Equal(expected) || addrs[i].String() != expected.String() {
+				t.addFailure(newIPAddrFailure(fmt.Sprint("parsed ", str, " at index ", i, " does not match expected ", expected), addrs[i]))
+			}
+		}
+	}
+	addrs, errs := ipaddr.ParseAll(strs, nil)
+	check(addrs, errs, strs, valid, nil)
+
+	validAddrs, invalidErrs := ipaddr.ParseAllValid(strs, nil)
+	if len(validAddrs) != 4 || len(invalidErrs) != 3 {
+		t.addFailure(newFailure(fmt.Sprint("parsed valid ", validAddrs, " with errors ", invalidErrs), nil))
+	} else if !validAddrs[2].Equal(ipaddr.NewIPAddressString("1.2.*.4").GetAddress()) {
+		t.addFailure(newIPAddrFailure("valid address out of order", validAddrs[2]))
+	} else if !strings.Contains(invalidErrs[1].Error(), "index 3") {
+		t.addFailure(newFailure("missing index in error "+invalidErrs[1].Error(), nil))
+	}
+
+	ipv4Params := new(addrstrparam.IPAddressStringParamsBuilder).AllowIPv6(false).ToParams()
+	addrs, errs = ipaddr.ParseAll(strs, ipv4Params)
+	check(addrs, errs, strs, []bool{true, false, false, false, true, false, false}, ipv4Params)
+
+	// enough strings to be parsed by multiple goroutines
+	var manyStrs []string
+	var manyValid []bool
+	for i := 0; i < 1000; i++ {
+		if i%7 == 3 {
+			manyStrs = append(manyStrs, fmt.Sprintf("%x::%x::", i, i))
+			manyValid = append(manyValid, false)
+		} else if i%2 == 0 {
+			manyStrs = append(manyStrs, fmt.Sprintf("10.%d.%d.0/24", i>>8, i&0xff))
+			manyValid = append(manyValid, true)
+		} else {
+			manyStrs = append(manyStrs, fmt.Sprintf("2001:db8::%x", i))
+			manyValid = append(manyValid, true)
+		}
+	}
+	addrs, errs = ipaddr.ParseAll(manyStrs, nil)
+	check(addrs, errs, manyStrs, manyValid, nil)
+
+	addrs, errs = ipaddr.ParseAll(manyStrs[:2], nil)
+	if len(addrs) != 2 || errs != nil {
+		t.addFailure(newFailure(fmt.Sprint("parsed ", addrs, " with errors ", errs), nil))
+	}
+	addrs, errs = ipaddr.ParseAll(nil, nil)
+	if len(addrs) != 0 || errs != nil {
+		t.addFailure(newFailure(fmt.Sprint("parsed ", addrs, " with errors ", errs), nil))
+	}
+	t.incrementTestCount()
+}
+
+var reverseDNSParams = new(addrstrparam.IPAddressStringParamsBuilder).AllowReverseDNS(true).ToParams()
+
+// ipAddressParamsWithoutReverseDNS hides the ReverseDNSProvider method of the wrapped parameters
+type ipAddressParamsWithoutReverseDNS struct {
+	addrstrparam.IPAddressStringParams
+}
+
+func (t ipAddressTester) testReverseDNSParse(str, expected string) {
+	addrStr := ipaddr.NewIPAddressStringParams(str, reverseDNSParams)
+	addr, err := addrStr.ToAddress()
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newFailure("unexpectedly parsed to "+addr.String(), addrStr))
+		}
+	} else if err != nil {
+		t.addFailure(newFailure("unexpected error "+err.Error(), addrStr))
+	} else if expectedAddr := ipaddr.NewIPAddressString(expected).GetAddress(); !addr.Equal(expectedAddr) || !addr.GetNetworkPrefixLen().Equal(expectedAddr.GetNetworkPrefixLen()) {
+		t.addFailure(newFailure("parsed to "+addr.String()+" not "+expected, addrStr))
+	} else if !addrStr.GetNetworkPrefixLen().Equal(expectedAddr.GetNetworkPrefixLen()) || addrStr.GetIPVersion() != expectedAddr.GetIPVersion() {
+		t.addFailure(newFailure("unexpected prefix length or version", addrStr))
+	} else if str != expected && ipaddr.NewIPAddressString(str).IsValid() {
+		// without the option, reverse-DNS names are not parsed
+		t.addFailure(newFailure("unexpectedly valid without reverse DNS parsing", addrStr))
+	} else if str != expected && ipaddr.NewIPAddressStringParams(str, ipAddressParamsWithoutReverseDNS{reverseDNSParams}).IsValid() {
+		t.addFailure(newFailure("unexpectedly valid without the optional reverse DNS parameters", addrStr))
+	} else if addr.IsPrefixed() {
+		noPrefixParams := new(addrstrparam.IPAddressStringParamsBuilder).Set(reverseDNSParams).AllowPrefix(false).ToParams()
+		if ipaddr.NewIPAddressStringParams(str, noPrefixParams).IsValid() {
+			t.addFailure(newFailure("unexpectedly valid without prefixes", addrStr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testContainsRange(subnetStr, lowerStr, upperStr string, expected bool) {
+	subnet := ipaddr.NewIPAddressString(subnetStr).GetAddress()
+	rng := ipaddr.NewIPAddressString(lowerStr).GetAddress().SpanWithRange(ipaddr.NewIPAddressString(upperStr).GetAddress())
+	if result := subnet.ContainsRange(rng); result != expected {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("containment of %v is %v", rng, result), subnet))
+	} else if subnet.IsIPv4() && subnet.ToIPv4().ContainsRange(rng) != expected {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("IPv4 containment of %v is not %v", rng, expected), subnet))
+	} else if subnet.IsIPv6() && subnet.ToIPv6().ContainsRange(rng) != expected {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("IPv6 containment of %v is not %v", rng, expected), subnet))
+	} else if subnet.IsSequential() && subnet.ToSequentialRange().ContainsRange(rng) != expected {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("range containment of %v is not %v", rng, expected), subnet))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testEnclosingPrefixBlock(lowerStr, upperStr, expectedStr string, isSingle bool) {
+	lower := ipaddr.NewIPAddressString(lowerStr).GetAddress()
+	rng := lower.SpanWithRange(ipaddr.NewIPAddressString(upperStr).GetAddress())
+	expected := ipaddr.NewIPAddressString(expectedStr).GetAddress()
+	if block := rng.EnclosingPrefixBlock(); !block.Equal(expected) || !block.GetNetworkPrefixLen().Equal(expected.GetNetworkPrefixLen()) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("enclosing block %v of %v does not match %v", block, rng, expected), lower))
+	} else if !expected.ContainsRange(rng) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("enclosing block %v does not contain %v", expected, rng), lower))
+	} else if rng.IsSingleCIDR() != isSingle {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("%v single CIDR is not %v", rng, isSingle), lower))
+	} else if isSingle && !expected.ToSequentialRange().Equal(rng) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("%v does not match block %v", rng, expected), lower))
+	}
+	t.incrementTestCount()
+}
+
+func mustNAT64Converter(prefix *ipaddr.IPv6Address) *ipaddr.NAT64AddressConverter {
+	converter, err := ipaddr.NewNAT64AddressConverter(prefix)
+	if err != nil {
+		panic(err)
+	}
+	return converter
+}
+
+func (t ipAddressTester) testConverter(converter ipaddr.IPAddressConverter, ipv4Str, ipv6Str string) {
+	ipv4 := ipaddr.NewIPAddressString(ipv4Str).GetAddress()
+	ipv6 := ipaddr.NewIPAddressString(ipv6Str).GetAddress()
+	if !converter.IsIPv6Convertible(ipv4) {
+		t.addFailure(newIPAddrFailure("not convertible to IPv6", ipv4))
+	} else if converted := converter.ToIPv6(ipv4); !converted.Equal(ipv6) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("converted to %v not %v", converted, ipv6), ipv4))
+	} else if !converter.IsIPv4Convertible(ipv6) {
+		t.addFailure(newIPAddrFailure("not convertible to IPv4", ipv6))
+	} else if back := converter.ToIPv4(ipv6); !back.Equal(ipv4) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("converted back to %v not %v", back, ipv4), ipv6))
+	} else if converter.ToIPv4(ipv4) != ipv4.ToIPv4() || converter.ToIPv6(ipv6) != ipv6.ToIPv6() {
+		t.addFailure(newIPAddrFailure("conversion to the same version is not the identity", ipv4))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testConverterToIPv4(converter ipaddr.IPAddressConverter, ipv6Str, ipv4Str string) {
+	ipv6 := ipaddr.NewIPAddressString(ipv6Str).GetAddress()
+	converted := converter.ToIPv4(ipv6)
+	if ipv4Str == "" {
+		if converted != nil || converter.IsIPv4Convertible(ipv6) {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpectedly converted to %v", converted), ipv6))
+		}
+	} else if expected := ipaddr.NewIPAddressString(ipv4Str).GetAddress(); !converted.Equal(expected) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("converted to %v not %v", converted, expected), ipv6))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testNAT64ConverterInvalid(prefixStr string) {
+	prefix := ipaddr.NewIPAddressString(prefixStr).GetAddress()
+	if converter, err := ipaddr.NewNAT64AddressConverter(prefix.ToIPv6()); err == nil {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpectedly created converter with prefix %v", converter.GetPrefix()), prefix))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testRegisteredAddressConverter() {
+	ipv4 := ipaddr.NewIPAddressString("1.2.3.4").GetAddress()
+	mapped := ipaddr.NewIPAddressString("::ffff:1.2.3.4").GetAddress()
+	sixToFour := ipaddr.NewIPAddressString("2002:102:304::1").GetAddress()
+	if !ipv4.EqualConverted(mapped) || !mapped.EqualConverted(ipv4) || ipv4.EqualConverted(sixToFour) {
+		t.addFailure(newIPAddrFailure("unexpected default conversion", ipv4))
+	}
+	if !ipaddr.NewIPAddressString("1.2.0.0/16").GetAddress().ContainsConverted(mapped) || !mapped.IsIPv4Convertible() || sixToFour.IsIPv4Convertible() {
+		t.addFailure(newIPAddrFailure("unexpected default conversion", mapped))
+	}
+	ipaddr.SetAddressConverter(ipaddr.ChainedAddressConverter{ipaddr.DefaultAddressConverter{}, ipaddr.SixToFourAddressConverter{}})
+	if !ipv4.EqualConverted(sixToFour) || !ipv4.EqualConverted(mapped) || !sixToFour.IsIPv4Convertible() {
+		t.addFailure(newIPAddrFailure("unexpected chained conversion", sixToFour))
+	} else if converted := sixToFour.ConvertToIPv4(); !converted.Equal(ipv4) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("converted to %v", converted), sixToFour))
+	} else if converted := ipv4.ConvertToIPv6(); !converted.Equal(mapped) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("converted to %v", converted), ipv4))
+	}
+	ipaddr.SetAddressConverter(ipaddr.SixToFourAddressConverter{})
+	if !ipaddr.NewIPAddressString("2002::/16").GetAddress().ContainsConverted(ipv4) || mapped.IsIPv4Convertible() {
+		t.addFailure(newIPAddrFailure("unexpected 6to4 conversion", ipv4))
+	}
+	ipaddr.SetAddressConverter(nil)
+	if _, ok := ipaddr.GetAddressConverter().(ipaddr.DefaultAddressConverter); !ok {
+		t.addFailure(newIPAddrFailure("converter not restored", ipv4))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPartitionIntoN(lowerStr, upperStr string, n int, expected []string) {
+	lower := ipaddr.NewIPAddressString(lowerStr).GetAddress()
+	rng := lower.SpanWithRange(ipaddr.NewIPAddressString(upperStr).GetAddress())
+	var result []string
+	ipaddr.PartitionIntoN(rng, n).ForEach(func(piece *ipaddr.IPAddressSeqRange) {
+		result = append(result, piece.String())
+	})
+	if !reflect.DeepEqual(result, expected) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("partition of %v into %d is %v not %v", rng, n, result, expected), lower))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPartitionByMask(subnetStr, maskStr string, expected [][]string) {
+	subnet := ipaddr.NewIPAddressString(subnetStr).GetAddress()
+	mask := ipaddr.NewIPAddressString(maskStr).GetAddress()
+	part, err := ipaddr.PartitionByMask(subnet, mask)
+	if expected == nil {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("partition by mismatched mask "+maskStr+" succeeded", subnet))
+		}
+		t.incrementTestCount()
+		return
+	} else if err != nil {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("partition by mask %s failed: %v", maskStr, err), subnet))
+		return
+	}
+	maskLower := mask.GetLower()
+	checkAddrs := subnet.GetCount().Cmp(big.NewInt(4096)) <= 0
+	var result [][]string
+	var addrCount int64
+	part.ForEach(func(group *ipaddr.Partition[*ipaddr.IPAddress]) {
+		var groupResult []string
+		var maskedVal *ipaddr.IPAddress
+		group.ForEach(func(groupSubnet *ipaddr.IPAddress) {
+			groupResult = append(groupResult, groupSubnet.String())
+			if !checkAddrs {
+				return
+			}
+			// every address of the group has the same masked value
+			for iter := groupSubnet.Iterator(); iter.HasNext(); addrCount++ {
+				masked, _ := iter.Next().Mask(maskLower)
+				if maskedVal == nil {
+					maskedVal = masked
+				} else if !masked.Equal(maskedVal) {
+					t.addFailure(newIPAddrFailure(fmt.Sprintf("masked value %v in group of %v does not match %v", masked, groupSubnet, maskedVal), subnet))
+				}
+			}
+		})
+		result = append(result, groupResult)
+	})
+	if !reflect.DeepEqual(result, expected) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("partition by mask %s is %v not %v", maskStr, result, expected), subnet))
+	} else if checkAddrs && subnet.GetCount().Cmp(big.NewInt(addrCount)) != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("partition by mask %s has %d addresses", maskStr, addrCount), subnet))
+	}
+	if subnet.IsIPv4() {
+		var ipv4Result [][]string
+		ipv4Part, _ := ipaddr.PartitionByMask(subnet.ToIPv4(), mask.ToIPv4())
+		for iter := ipv4Part.Iterator(); iter.HasNext(); {
+			var groupResult []string
+			iter.Next().ForEach(func(groupSubnet *ipaddr.IPv4Address) {
+				groupResult = append(groupResult, groupSubnet.String())
+			})
+			ipv4Result = append(ipv4Result, groupResult)
+		}
+		if !reflect.DeepEqual(ipv4Result, expected) {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("IPv4 partition by mask %s is %v not %v", maskStr, ipv4Result, expected), subnet))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPartitionByHosts(subnetStr string, maxHosts int64, expected []string) {
+	subnet := ipaddr.NewIPAddressString(subnetStr).GetAddress()
+	var result []string
+	ipaddr.PartitionByHosts(subnet, big.NewInt(maxHosts)).ForEach(func(block *ipaddr.IPAddress) {
+		result = append(result, block.String())
+	})
+	if !reflect.DeepEqual(result, expected) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("partition by %d hosts is %v not %v", maxHosts, result, expected), subnet))
+	} else if subnet.IsSequential() {
+		var rngResult []string
+		for iter := ipaddr.PartitionRangeByHosts(subnet.ToSequentialRange(), big.NewInt(maxHosts)).Iterator(); iter.HasNext(); {
+			rngResult = append(rngResult, iter.Next().String())
+		}
+		if !reflect.DeepEqual(rngResult, expected) {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("range partition by %d hosts is %v not %v", maxHosts, rngResult, expected), subnet))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testForEachSegmentValue(addrStr string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	segs := addr.GetSegments()
+	count := addr.ForEachSegmentValue(func(index int, value, upperValue ipaddr.SegInt, segPrefLen ipaddr.PrefixLen) bool {
+		seg := segs[index]
+		if value != seg.GetSegmentValue() || upperValue != seg.GetUpperSegmentValue() || !segPrefLen.Equal(seg.GetSegmentPrefixLen()) {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("segment %d mismatch, values %d %d %v, expected segment %v", index, value, upperValue, segPrefLen, seg), addr))
+		}
+		return false
+	})
+	if count != len(segs) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("visited %d segments, expected %d", count, len(segs)), addr))
+	}
+	count = addr.GetSection().ForEachSegmentValue(func(index int, value, upperValue ipaddr.SegInt, segPrefLen ipaddr.PrefixLen) bool {
+		return index == 1
+	})
+	if count != 2 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("visited %d segments, expected 2", count), addr))
+	}
+	values := make([]ipaddr.SegInt, 1, 1+len(segs))
+	values = addr.SegmentValuesAppend(values)
+	if len(values) != 1+len(segs) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("appended %d values, expected %d", len(values)-1, len(segs)), addr))
+	} else {
+		for i, seg := range segs {
+			if values[i+1] != seg.GetSegmentValue() {
+				t.addFailure(newIPAddrFailure(fmt.Sprintf("value %d is %d, expected %d", i, values[i+1], seg.GetSegmentValue()), addr))
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
+// testZeroForEachSegmentValue checks that the zero values of the address types visit the same segments as ForEachSegment
+func (t ipAddressTester) testZeroForEachSegmentValue() {
+	var ipv4Addr ipaddr.IPv4Address
+	var ipv6Addr ipaddr.IPv6Address
+	var macAddr ipaddr.MACAddress
+	var ipAddr ipaddr.IPAddress
+	var addr ipaddr.Address
+	visit := func(int, ipaddr.SegInt, ipaddr.SegInt, ipaddr.PrefixLen) bool { return false }
+	for _, counts := range [][3]int{
+		{ipv4Addr.ForEachSegmentValue(visit), len(ipv4Addr.SegmentValuesAppend(nil)), ipaddr.IPv4SegmentCount},
+		{ipv6Addr.ForEachSegmentValue(visit), len(ipv6Addr.SegmentValuesAppend(nil)), ipaddr.IPv6SegmentCount},
+		{macAddr.ForEachSegmentValue(visit), len(macAddr.SegmentValuesAppend(nil)), ipaddr.MediaAccessControlSegmentCount},
+		{ipAddr.ForEachSegmentValue(visit), len(ipAddr.SegmentValuesAppend(nil)), ipAddr.GetSegmentCount()},
+		{addr.ForEachSegmentValue(visit), len(addr.SegmentValuesAppend(nil)), addr.GetSegmentCount()},
+	} {
+		if counts[0] != counts[2] || counts[1] != counts[2] {
+			t.addFailure(newFailure(fmt.Sprint("zero address visited ", counts[0], " and appended ", counts[1], " segments, expected ", counts[2]), nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testSectionUint64Values(addrStr string, start, end int) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress().ToIPv6()
+	section := addr.GetSubSection(start, end)
+	toBig := func(high, low uint64) *big.Int {
+		val := new(big.Int).SetUint64(high)
+		return val.Lsh(val, 64).Or(val, new(big.Int).SetUint64(low))
+	}
+	if high, low := section.Uint64Values(); toBig(high, low).Cmp(section.GetValue()) != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("section values %x %x do not match %v", high, low, section), addr.ToIP()))
+	} else if high, low := section.UpperUint64Values(); toBig(high, low).Cmp(section.GetUpperValue()) != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("section upper values %x %x do not match %v", high, low, section), addr.ToIP()))
+	} else if start == 0 && end == ipaddr.IPv6SegmentCount {
+		if high, low := section.Uint64Values(); !ipaddr.NewIPv6AddressFromUint64(high, low).Equal(addr.GetLower()) {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("section values %x %x do not match address", high, low), addr.ToIP()))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testErrorMatching() {
+	_, err := ipaddr.NewIPAddressString("1.2.3.4/33").ToAddress()
+	t.checkErrorIs(err, addrerr.ErrInvalidPrefixLen, true)
+	t.checkErrorIs(err, addrerr.ErrIncompatibleRange, false)
+
+	_, err = ipaddr.NewHostName("1.2.3.4/33").ToAddress()
+	t.checkErrorIs(err, addrerr.ErrInvalidPrefixLen, true)
+
+	_, err = ipaddr.NewIPAddressString("1.2.*.4").GetAddress().Mask(ipaddr.NewIPAddressString("255.255.254.255").GetAddress())
+	t.checkErrorIs(err, addrerr.ErrIncompatibleRange, true)
+	t.checkErrorIs(err, addrerr.ErrInvalidPrefixLen, false)
+
+	_, err = ipaddr.NewIPAddressString("1.2.3.4").GetAddress().Mask(ipaddr.NewIPAddressString("::").GetAddress())
+	t.checkErrorIs(err, addrerr.ErrVersionMismatch, true)
+
+	_, err = ipaddr.NewIPAddressStringParams("::1", new(addrstrparam.IPAddressStringParamsBuilder).AllowIPv6(false).ToParams()).ToAddress()
+	t.checkErrorIs(err, addrerr.ErrVersionNotAllowed, true)
+
+	str := "1.2.3.x"
+	_, err = ipaddr.NewIPAddressString(str).ToAddress()
+	var indexErr addrerr.IndexError
+	if !errors.As(err, &indexErr) {
+		t.addFailure(newFailure(fmt.Sprint("expected index error, got ", err), ipaddr.NewIPAddressString(str)))
+	} else if indexErr.GetIndex() != 6 || indexErr.GetInput() != str {
+		t.addFailure(newFailure(fmt.Sprint("mismatched index ", indexErr.GetIndex(), " or input ", indexErr.GetInput()), ipaddr.NewIPAddressString(str)))
+	}
+
+	err = ipaddr.NewHostName("a..b").Validate()
+	var inputErr addrerr.InputError
+	if !errors.As(err, &inputErr) || inputErr.GetInput() != "a..b" {
+		t.addFailure(newFailure(fmt.Sprint("expected input error, got ", err), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) checkErrorIs(err error, target *addrerr.SentinelError, expected bool) {
+	if errors.Is(err, target) != expected {
+		t.addFailure(newFailure(fmt.Sprint("error ", err, " matching ", target, " expected to be ", expected), nil))
+	}
+}
+
+func (t ipAddressTester) testPrefixCounter() {
+	addr := func(str string) *ipaddr.IPAddress {
+		return ipaddr.NewIPAddressString(str).GetAddress()
+	}
+	var counter ipaddr.IPPrefixCounter
+	if top := counter.TopPrefixes(24, 3); len(top) != 0 || counter.GetTotal() != 0 || counter.GetCountWithin(addr("0.0.0.0/0")) != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected prefixes ", top, " of empty counter"), nil))
+	}
+	counts := []struct {
+		addr  string
+		count uint64
+	}{
+		{"10.0.0.1", 5}, {"10.0.1.1", 20}, {"10.0.0.2", 10}, {"192.168.1.1", 7}, {"10.0.1.1", 2},
+		{"10.0.2.0/25", 4}, {"10.0.3.*", 8}, {"10.0.0.0/8", 100}, {"172.16.0.1", 15}, {"192.168.1.2", 8},
+	}
+	for _, c := range counts {
+		if !counter.Add(addr(c.addr), c.count) {
+			t.addFailure(newIPAddrFailure("count not added", addr(c.addr)))
+		}
+	}
+	if counter.Add(addr("1::1"), 1) || counter.Add(addr("10.0.0.1-3"), 1) || counter.Add(nil, 1) {
+		t.addFailure(newIPAddrFailure("invalid count added", nil))
+	}
+	if !counter.GetVersion().IsIPv4() || counter.GetTotal() != 179 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected version ", counter.GetVersion(), " or total ", counter.GetTotal()), nil))
+	}
+	if counter.GetCount(addr("10.0.1.1")) != 22 || counter.GetCount(addr("10.0.3.0/24")) != 8 || counter.GetCount(addr("10.0.0.3")) != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected count ", counter.GetCount(addr("10.0.1.1"))), addr("10.0.1.1")))
+	}
+	if within := counter.GetCountWithin(addr("10.0.0.0/22")); within != 49 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected count within ", within), addr("10.0.0.0/22")))
+	} else if within = counter.GetCountWithin(addr("10.0.0.0/8")); within != 149 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected count within ", within), addr("10.0.0.0/8")))
+	} else if within = counter.GetCountWithin(addr("10.0.0-1.1-255")); within != 37 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected count within ", within), addr("10.0.0-1.1-255")))
+	}
+	checkTop := func(top []ipaddr.PrefixCount[*ipaddr.IPAddress], expected string) {
+		if fmt.Sprint(top) != expected {
+			t.addFailure(newIPAddrFailure(fmt.Sprint("top prefixes ", top, " do not match expected ", expected), nil))
+		}
+		t.incrementTestCount()
+	}
+	checkTop(counter.TopPrefixes(24, 3), "[10.0.1.0/24 22 10.0.0.0/24 15 172.16.0.0/24 15]")
+	checkTop(counter.TopPrefixes(24, 0), "[10.0.1.0/24 22 10.0.0.0/24 15 172.16.0.0/24 15 192.168.1.0/24 15 10.0.3.0/24 8 10.0.2.0/24 4]")
+	checkTop(counter.TopPrefixes(16, 2), "[10.0.0.0/16 49 172.16.0.0/16 15]")
+	checkTop(counter.TopPrefixes(8, 1), "[10.0.0.0/8 149]")
+	checkTop(counter.TopPrefixes(32, 2), "[10.0.1.1/32 22 172.16.0.1/32 15]")
+	if trie := counter.GetTrie(); trie.Size() != 9 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected trie ", trie), nil))
+	}
+
+	var counter6 ipaddr.IPv6PrefixCounter
+	for i := 0; i < 100; i++ {
+		counter6.Add(ipaddr.NewIPAddressString(fmt.Sprintf("2001:db8:%x::%x", i%3, i)).GetAddress().ToIPv6(), uint64(i))
+	}
+	top6 := counter6.TopPrefixes(48, 0)
+	if fmt.Sprint(top6) != "[2001:db8::/48 1683 2001:db8:2::/48 1650 2001:db8:1::/48 1617]" || counter6.GetTotal() != 4950 {
+		t.addFailure(newIPAddrFailure(fmt.Sprint("unexpected IPv6 top prefixes ", top6), nil))
+	}
+}
+
+func (t ipAddressTester) testAddressPool() {
+	var pool ipaddr.IPPool
+	pool.AddToPool(ipaddr.NewIPAddressString("10.0.0.0/24").GetAddress(), ipaddr.NewIPAddressString("10.0.1.0/24").GetAddress())
+	if pool.GetTotalCount().Int64() != 512 || len(pool.GetPool()) != 1 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected pool %v", pool.GetPool()), nil))
+	}
+	if pool.Utilization() != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected utilization %v", pool.Utilization()), nil))
+	}
+	block1 := ipaddr.NewIPAddressString("10.0.0.0/26").GetAddress()
+	block2 := ipaddr.NewIPAddressString("10.0.1.128/25").GetAddress()
+	addr := ipaddr.NewIPAddressString("10.0.0.64").GetAddress()
+	if !pool.Allocate(block1) || !pool.Allocate(block2) || !pool.Allocate(addr) {
+		t.addFailure(newIPAddrFailure("allocation failed", block1))
+	}
+	if pool.Allocate(ipaddr.NewIPAddressString("10.0.0.0/25").GetAddress()) || pool.Allocate(ipaddr.NewIPAddressString("10.0.0.1").GetAddress()) {
+		t.addFailure(newIPAddrFailure("overlapping allocation succeeded", block1))
+	}
+	if pool.Allocate(ipaddr.NewIPAddressString("10.0.2.0/24").GetAddress()) || pool.Allocate(ipaddr.NewIPAddressString("1::/64").GetAddress()) {
+		t.addFailure(newIPAddrFailure("allocation outside pool succeeded", block1))
+	}
+	if pool.GetAllocatedCount().Int64() != 193 || pool.GetFreeCount().Int64() != 319 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected counts %v %v", pool.GetAllocatedCount(), pool.GetFreeCount()), block1))
+	}
+	if util := pool.Utilization(); util != 193.0/512.0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected utilization %v", util), block1))
+	}
+	counts := pool.GetAllocatedBlockCounts()
+	if len(counts) != 3 || counts[26] != 1 || counts[25] != 1 || counts[32] != 1 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected allocated block counts %v", counts), block1))
+	}
+	freeRanges := pool.GetFreeRanges()
+	if len(freeRanges) != 1 || freeRanges[0].String() != "10.0.0.65 -> 10.0.1.127" {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected free ranges %v", freeRanges), block1))
+	}
+	largest := pool.GetLargestFreeBlock()
+	if largest == nil || largest.String() != "10.0.0.128/25" {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected largest free block %v", largest), block1))
+	}
+	freeCounts := pool.GetFreeBlockCounts()
+	if len(freeCounts) != 7 || freeCounts[25] != 2 || freeCounts[32] != 1 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected free block counts %v", freeCounts), block1))
+	}
+	if !pool.IsAllocated(ipaddr.NewIPAddressString("10.0.0.1-63").GetAddress()) || pool.IsAllocated(ipaddr.NewIPAddressString("10.0.0.60-70").GetAddress()) {
+		t.addFailure(newIPAddrFailure("unexpected allocation status", block1))
+	}
+	if pool.Release(ipaddr.NewIPAddressString("10.0.0.0/27").GetAddress()) || !pool.Release(block1) || pool.Release(block1) {
+		t.addFailure(newIPAddrFailure("unexpected release", block1))
+	}
+	if free := pool.GetFreeRanges(); len(free) != 2 || free[0].String() != "10.0.0.0 -> 10.0.0.63" {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected free ranges %v", free), block1))
+	}
+	if largest = pool.GetLargestFreeBlock(); largest == nil || largest.String() != "10.0.0.128/25" {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected largest free block %v", largest), block1))
+	}
+	if rng := pool.GetLargestFreeRange(); rng == nil || rng.String() != "10.0.0.65 -> 10.0.1.127" {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected largest free range %v", rng), block1))
+	}
+	if len(pool.GetAllocated()) != 2 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected allocated %v", pool.GetAllocated()), block1))
+	}
+
+	// a subnet spread across several allocated blocks is allocated
+	var splitPool ipaddr.IPPool
+	splitPool.AddToPool(ipaddr.NewIPAddressString("1.2.0.0/16").GetAddress())
+	if !splitPool.Allocate(ipaddr.NewIPAddressString("1.2.3.0/25").GetAddress()) || !splitPool.Allocate(ipaddr.NewIPAddressString("1.2.3.128/25").GetAddress()) {
+		t.addFailure(newIPAddrFailure("allocation failed", nil))
+	}
+	for _, str := range []string{"1.2.3.0/24", "1.2.3.64-191", "1.2.3.128/25", "1.2.3.4"} {
+		if addr := ipaddr.NewIPAddressString(str).GetAddress(); !splitPool.IsAllocated(addr) {
+			t.addFailure(newIPAddrFailure("expected allocated", addr))
+		}
+	}
+	for _, str := range []string{"1.2.2.0/23", "1.2.3.0-4.0", "1.2.4.0/24"} {
+		if addr := ipaddr.NewIPAddressString(str).GetAddress(); splitPool.IsAllocated(addr) {
+			t.addFailure(newIPAddrFailure("expected not allocated", addr))
+		}
+	}
+
+	var pool6 ipaddr.IPv6Pool
+	pool6.AddToPool(ipaddr.NewIPAddressString("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/120").GetAddress().ToIPv6())
+	if !pool6.Allocate(ipaddr.NewIPAddressString("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff80/121").GetAddress().ToIPv6()) {
+		t.addFailure(newIPAddrFailure("allocation failed", nil))
+	}
+	if free := pool6.GetFreeBlocks(); len(free) != 1 || free[0].String() != "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/121" {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected free blocks %v", free), nil))
+	}
+	if util := pool6.Utilization(); util != 0.5 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected utilization %v", util), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testEnumerate(subnetStr, addrStr string, expected int64) {
+	subnet := ipaddr.NewIPAddressString(subnetStr).GetAddress()
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	index := subnet.Enumerate(addr)
+	if expected < 0 {
+		if index != nil {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected index %v of %v", index, addr), subnet))
+		}
+		t.incrementTestCount()
+		return
+	}
+	expectedIndex := big.NewInt(expected)
+	if index == nil || index.Cmp(expectedIndex) != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("index %v of %v does not match %v", index, addr, expectedIndex), subnet))
+	} else if !subnet.IncrementBig(index).Equal(addr) && !subnet.IncrementBig(index).WithoutPrefixLen().Equal(addr) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("increment %v does not produce %v", index, addr), subnet))
+	} else if sectionIndex := subnet.GetSection().Enumerate(addr.GetSection()); sectionIndex == nil || sectionIndex.Cmp(index) != 0 {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("section index %v does not match %v", sectionIndex, index), subnet))
+	} else if subnet.IsSequential() {
+		rngIndex := subnet.ToSequentialRange().Enumerate(addr)
+		if rngIndex == nil || rngIndex.Cmp(index) != 0 {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("range index %v does not match %v", rngIndex, index), subnet))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testLargePermutationIterator(addrStr string, seed int64) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	seen := make(map[string]bool)
+	iter := addr.PermutationIterator(seed)
+	for i := 0; i < 100; i++ {
+		if !iter.HasNext() {
+			t.addFailure(newIPAddrFailure("permutation ended early", addr))
+			break
+		}
+		next := iter.Next()
+		if next == nil || next.IsMultiple() || !addr.Contains(next) || seen[next.String()] {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected permutation element %v", next), addr))
+			break
+		}
+		seen[next.String()] = true
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMatcher(patterns []string, matchAll bool, addrStr string, expected bool) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	compile := ipaddr.CompileAnyMatcher
+	if matchAll {
+		compile = ipaddr.CompileAllMatcher
+	} else if len(patterns) == 1 {
+		compile = func(patterns ...string) (*ipaddr.Matcher, addrerr.AddressError) {
+			return ipaddr.CompileMatcher(patterns[0])
+		}
+	}
+	if matcher, err := compile(patterns...); err != nil {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected error compiling %v: %v", patterns, err), addr))
+	} else if matches := matcher.Matches(addr); matches != expected {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("match against %v (all: %v) is %v, expected %v", patterns, matchAll, matches, expected), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMatcherInvalid(patterns ...string) {
+	if _, err := ipaddr.CompileAnyMatcher(patterns...); err == nil {
+		t.addFailure(newFailure(fmt.Sprintf("expected failure compiling %v", patterns), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPrefixBlocksOfLen(addrStr string, prefLen ipaddr.BitCount, expectedCount uint64, expectedFirst, expectedLast string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	first := ipaddr.NewIPAddressString(expectedFirst).GetAddress().GetSection()
+	last := ipaddr.NewIPAddressString(expectedLast).GetAddress().GetSection()
+	section := addr.GetSection()
+	var count uint64
+	var firstBlock, lastBlock *ipaddr.IPAddressSection
+	for iterator := section.PrefixBlocksOfLenIterator(prefLen); iterator.HasNext(); count++ {
+		lastBlock = iterator.Next()
+		if firstBlock == nil {
+			firstBlock = lastBlock
+		}
+		if !lastBlock.IsSinglePrefixBlock() {
+			t.addFailure(newIPAddrFailure("iterated "+lastBlock.String()+" is not a prefix block", addr))
+		}
+	}
+	if count != expectedCount || section.GetPrefixCountLen(prefLen).Uint64() != expectedCount {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("prefix block count %d or %v for length %d does not match expected %d",
+			count, section.GetPrefixCountLen(prefLen), prefLen, expectedCount), addr))
+	} else if !firstBlock.Equal(first) || !lastBlock.Equal(last) ||
+		firstBlock.GetPrefixLen().Len() != first.GetPrefixLen().Len() || lastBlock.GetPrefixLen().Len() != last.GetPrefixLen().Len() {
+		t.addFailure(newIPAddrFailure("prefix blocks "+firstBlock.String()+" to "+lastBlock.String()+" do not match expected "+expectedFirst+" to "+expectedLast, addr))
+	}
+	count = 0
+	if addr.IsIPv4() {
+		for iterator := addr.ToIPv4().GetSection().PrefixBlocksOfLenIterator(prefLen); iterator.HasNext(); iterator.Next() {
+			count++
+		}
+	} else {
+		for iterator := addr.ToIPv6().GetSection().PrefixBlocksOfLenIterator(prefLen); iterator.HasNext(); iterator.Next() {
+			count++
+		}
+	}
+	if count != expectedCount {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("%v prefix block count %d does not match expected %d", addr.GetIPVersion(), count, expectedCount), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddToSegment(addrStr string, index int, delta int64, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	result := addr.GetSection().AddToSegment(index, delta)
+	var versionResult *ipaddr.IPAddressSection
+	if addr.IsIPv4() {
+		versionResult = addr.ToIPv4().GetSection().AddToSegment(index, delta).ToIP()
+	} else {
+		versionResult = addr.ToIPv6().GetSection().AddToSegment(index, delta).ToIP()
+	}
+	if expected == "" {
+		if result != nil || versionResult != nil {
+			t.addFailure(newIPAddrFailure(fmt.Sprintf("expected nil adding %d to segment %d, got %v and %v", delta, index, result, versionResult), addr))
+		}
+	} else if expectedSection := ipaddr.NewIPAddressString(expected).GetAddress().GetSection(); result == nil || versionResult == nil {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("unexpected nil adding %d to segment %d", delta, index), addr))
+	} else if !result.Equal(expectedSection) || !versionResult.Equal(expectedSection) ||
+		result.GetPrefixLen().Len() != expectedSection.GetPrefixLen().Len() || result.IsMultiple() != expectedSection.IsMultiple() {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("adding %d to segment %d gave %v and %v, expected %v", delta, index, result, versionResult, expected), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddToSegmentZeroSection() {
+	var section ipaddr.AddressSection
+	var ipSection ipaddr.IPAddressSection
+	var ipv4Section ipaddr.IPv4AddressSection
+	for _, index := range []int{-1, 0, 1} {
+		if section.AddToSegment(index, 1) != nil || ipSection.AddToSegment(index, 1) != nil ||
+			ipv4Section.AddToSegment(index, 0) != nil {
+			t.addFailure(newFailure(fmt.Sprintf("expected nil adding to segment %d of zero section", index), nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testUint32ByteOrder(addrStr string, expectedBE, expectedLE uint32) {
+	addr := t.createAddress(addrStr).GetAddress().ToIPv4()
+	if be, le := addr.Uint32ValueBE(), addr.Uint32ValueLE(); be != expectedBE || le != expectedLE {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("byte order values %x %x do not match expected %x %x", be, le, expectedBE, expectedLE), addr.ToIP()))
+	} else if be != addr.Uint32Value() || addr.UpperUint32ValueLE() != le {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("big-endian value %x does not match %x", be, addr.Uint32Value()), addr.ToIP()))
+	} else if !ipaddr.NewIPv4AddressFromUint32BE(be).Equal(addr) || !ipaddr.NewIPv4AddressFromUint32LE(le).Equal(addr) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("byte order values %x %x do not reconstruct the address", be, le), addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testUint64ByteOrder(addrStr string, expectedHighBE, expectedLowBE, expectedHighLE, expectedLowLE uint64) {
+	addr := t.createAddress(addrStr).GetAddress().ToIPv6()
+	highBE, lowBE := addr.Uint64ValuesBE()
+	highLE, lowLE := addr.Uint64ValuesLE()
+	if highBE != expectedHighBE || lowBE != expectedLowBE {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("big-endian values %x %x do not match expected %x %x", highBE, lowBE, expectedHighBE, expectedLowBE), addr.ToIP()))
+	} else if highLE != expectedHighLE || lowLE != expectedLowLE {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("little-endian values %x %x do not match expected %x %x", highLE, lowLE, expectedHighLE, expectedLowLE), addr.ToIP()))
+	} else if !ipaddr.NewIPv6AddressFromUint64(highBE, lowBE).Equal(addr) || !ipaddr.NewIPv6AddressFromUint64BE(highBE, lowBE).Equal(addr) ||
+		!ipaddr.NewIPv6AddressFromUint64LE(highLE, lowLE).Equal(addr) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("values %x %x and %x %x do not reconstruct the address", highBE, lowBE, highLE, lowLE), addr.ToIP()))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testAddressClassification(addrStr string, expected ipaddr.AddressClassification) {
+	addr := t.createAddress(addrStr).GetAddress()
+	if class := addr.GetAddressClassification(); class != expected {
+		t.addFailure(newIPAddrFailure("classification "+class.String()+" does not match expected "+expected.String(), addr))
+	} else if (class == ipaddr.ClassDocumentation) != addr.IsDocumentation() {
+		t.addFailure(newIPAddrFailure("documentation mismatch for classification "+class.String(), addr))
+	} else if (class == ipaddr.ClassBenchmarking) != addr.IsBenchmarking() {
+		t.addFailure(newIPAddrFailure("benchmarking mismatch for classification "+class.String(), addr))
+	} else if (class == ipaddr.ClassLoopback) != addr.IsLoopback() {
+		t.addFailure(newIPAddrFailure("loopback mismatch for classification "+class.String(), addr))
+	} else if ipv4Addr := addr.ToIPv4(); ipv4Addr != nil {
+		if (class == ipaddr.ClassSharedAddressSpace) != ipv4Addr.IsCGNAT() {
+			t.addFailure(newIPAddrFailure("shared address space mismatch for classification "+class.String(), addr))
+		} else if (class == ipaddr.ClassPrivateUse) != ipv4Addr.IsPrivate() {
+			t.addFailure(newIPAddrFailure("private use mismatch for classification "+class.String(), addr))
+		}
+	} else if ipv6Addr := addr.ToIPv6(); ipv6Addr != nil {
+		if (class == ipaddr.ClassTeredo) != ipv6Addr.IsTeredo() {
+			t.addFailure(newIPAddrFailure("Teredo mismatch for classification "+class.String(), addr))
+		} else if (class == ipaddr.Class6To4) != ipv6Addr.Is6To4() {
+			t.addFailure(newIPAddrFailure("6to4 mismatch for classification "+class.String(), addr))
+		} else if (class == ipaddr.ClassUniqueLocal) != ipv6Addr.IsUniqueLocal() {
+			t.addFailure(newIPAddrFailure("unique local mismatch for classification "+class.String(), addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testFormatVerb(addrStr, format, expected string) {
+	addr := t.createAddress(addrStr).GetAddress()
+	if str := fmt.Sprintf(format, addr); str != expected {
+		t.addFailure(newIPAddrFailure("format "+format+" produced "+str+" instead of expected "+expected, addr))
+	} else if str = fmt.Sprintf(format, addr.ToAddressBase()); str != expected {
+		t.addFailure(newIPAddrFailure("format "+format+" produced "+str+" for address base instead of expected "+expected, addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testRegroupByBitCounts(addrStr string, bitCounts []ipaddr.BitCount, expected string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	section := addr.ToIPv6().GetSubSection(0, 2)
+	grouping, err := section.RegroupByBitCounts(bitCounts...)
+	if expected == "" {
+		if err == nil {
+			t.addFailure(newIPAddrFailure("regrouping unexpectedly succeeded as "+grouping.String(), addr))
+		}
+	} else if err != nil {
+		t.addFailure(newIPAddrFailure("regrouping failed: "+err.Error(), addr))
+	} else if grouping.String() != expected {
+		t.addFailure(newIPAddrFailure("regrouping "+grouping.String()+" does not match expected "+expected, addr))
+	} else if !grouping.IsMultiple() {
+		header := ipaddr.NewIPv6HeaderFlowGrouping(grouping.GetDivision(1).GetDivisionValue(), grouping.GetDivision(2).GetDivisionValue())
+		if header.String() != expected || header.GetBitCount() != section.GetBitCount() {
+			t.addFailure(newIPAddrFailure("header grouping "+header.String()+" does not match expected "+expected, addr))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMapSegments(orig, expected string) {
+	original := t.createAddress(orig).GetAddress()
+	expectedAddr := t.createAddress(expected).GetAddress()
+	var result *ipaddr.IPAddress
+	if original.IsIPv4() {
+		result = original.ToIPv4().MapSegments(func(seg *ipaddr.IPv4AddressSegment) *ipaddr.IPv4AddressSegment {
+			return ipaddr.NewIPv4Segment(seg.GetIPv4SegmentValue() + 1)
+		}).ToIP()
+	} else {
+		result = original.ToIPv6().MapSegments(func(seg *ipaddr.IPv6AddressSegment) *ipaddr.IPv6AddressSegment {
+			return ipaddr.NewIPv6Segment(seg.GetIPv6SegmentValue() + 1)
+		}).ToIP()
+	}
+	if !result.Equal(expectedAddr) {
+		t.addFailure(newIPAddrFailure("mapped segments was "+result.String()+" expected: "+expected, original))
+	} else if !result.GetNetworkPrefixLen().Equal(expectedAddr.GetNetworkPrefixLen()) {
+		t.addFailure(newIPAddrFailure("mapped prefix length was "+result.GetNetworkPrefixLen().String()+" expected: "+expectedAddr.GetNetworkPrefixLen().String(), original))
+	} else if original.IsIPv6() {
+		identity := original.ToIPv6().MapSegments(func(seg *ipaddr.IPv6AddressSegment) *ipaddr.IPv6AddressSegment { return seg })
+		if !identity.Equal(original.ToIPv6()) || identity.GetZone() != original.ToIPv6().GetZone() {
+			t.addFailure(newIPAddrFailure("identity mapping was "+identity.String(), original))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMACMapSegments(orig, expected string) {
+	original := ipaddr.NewMACAddressString(orig).GetAddress()
+	result := original.MapSegments(func(seg *ipaddr.MACAddressSegment) *ipaddr.MACAddressSegment {
+		return ipaddr.NewMACSegment(seg.GetMACSegmentValue() ^ 0xff)
+	})
+	if expectedAddr := ipaddr.NewMACAddressString(expected).GetAddress(); !result.Equal(expectedAddr) {
+		t.addFailure(newSegmentSeriesFailure("mapped segments was "+result.String()+" expected: "+expected, original))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testPrefixBlockAndHost(orig, expectedBlock string, expectedHost uint64) {
+	original := t.createAddress(orig).GetAddress()
+	block, host := original.ToPrefixBlockAndHost()
+	if !block.Equal(t.createAddress(expectedBlock).GetAddress()) {
+		t.addFailure(newIPAddrFailure("prefix block was "+block.String()+" expected: "+expectedBlock, original))
+	} else if !block.Equal(original.ToPrefixBlock()) {
+		t.addFailure(newIPAddrFailure("prefix block was "+block.String()+" expected: "+original.ToPrefixBlock().String(), original))
+	} else if host.GetValue().Uint64() != expectedHost {
+		t.addFailure(newIPAddrFailure("host was "+host.String()+" expected value: "+strconv.FormatUint(expectedHost, 16), original))
+	} else if !host.Equal(original.GetHostSection()) {
+		t.addFailure(newIPAddrFailure("host was "+host.String()+" expected: "+original.GetHostSection().String(), original))
+	}
+	t.incrementTestCount()
+}
+
 func (t ipAddressTester) testIncrement(originalStr string, increment int64, resultStr string) {
 	var addr *ipaddr.IPAddress
 	if resultStr != "" {
@@ -1,6 +1,8 @@
 package test
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"github.com/seancfoley/ipaddress-go/ipaddr"
 	"sync/atomic"
@@ -46,6 +48,7 @@ func (t keyTester) run() {
 					}
 					ipv4Addrs = append(ipv4Addrs, &zero4Addr)
 					testGenericKeys[*ipaddr.IPv4Address](t, ipv4Addrs)
+					testSpecificKeys[*ipaddr.IPv4Address, ipaddr.IPv4AddressKey](t, ipv4Addrs)
 
 					ipv6Addrs := make([]*ipaddr.IPv6Address, 0, len(cached)+1)
 					for _, addr := range cached {
@@ -55,6 +58,7 @@ func (t keyTester) run() {
 					}
 					ipv6Addrs = append(ipv6Addrs, &zero6Addr)
 					testGenericKeys[*ipaddr.IPv6Address](t, ipv6Addrs)
+					testSpecificKeys[*ipaddr.IPv6Address, ipaddr.IPv6AddressKey](t, ipv6Addrs)
 
 					t.testNetNetIPs(cached)
 					t.testNetIPAddrs(cached)
@@ -67,6 +71,7 @@ func (t keyTester) run() {
 
 					//fmt.Printf("testing %d MACS\n", len(cachedMAC))
 					testGenericKeys[*ipaddr.MACAddress](t, cachedMAC)
+					testSpecificKeys[*ipaddr.MACAddress, ipaddr.MACAddressKey](t, cachedMAC)
 
 					addrs := make([]*ipaddr.Address, 0, len(cached)+1)
 					for _, addr := range cached {
@@ -113,6 +118,90 @@ func (t keyTester) run() {
 	equals(t, ipv4key.ToAddress(), &zero4Addr)
 	equals(t, ipv6key.ToAddress(), &zero6Addr)
 	equals(t, macAddrKey.ToAddress(), &zeroMACAddr)
+
+	hashEquals(t, &zero4Addr, ipv4key, true)
+	hashEquals(t, &zero6Addr, ipv6key, true)
+	hashEquals(t, &zeroMACAddr, macAddrKey, true)
+	hashEquals(t, &zero4Addr, key4, true)
+	hashEquals(t, &zero6Addr, key6, true)
+	hashEquals(t, &zeroMACAddr, macKey, true)
+	hashEquals(t, &zeroIPAddr, ipKey, true)
+	hashEquals(t, &zeroAddr, key, true)
+	hashEquals(t, &zeroAddr, &zeroIPAddr, true)
+	hashEquals(t, &zeroAddr, &zero4Addr, false)
+	hashEquals(t, &zero4Addr, &zero6Addr, false)
+
+	t.testHashes()
+	t.testZonedKeys()
+}
+
+func (t keyTester) testZonedKeys() {
+	addr := func(str string) *ipaddr.IPv6Address {
+		return ipaddr.NewIPAddressString(str).GetAddress().ToIPv6()
+	}
+	zoned0, zoned1, unzoned := addr("fe80::1%eth0"), addr("fe80::1%eth1"), addr("fe80::1")
+	keys := map[ipaddr.IPv6AddressKey]*ipaddr.IPv6Address{
+		zoned0.ToKey():  zoned0,
+		zoned1.ToKey():  zoned1,
+		unzoned.ToKey(): unzoned,
+	}
+	if len(keys) != 3 {
+		t.addFailure(newFailure(fmt.Sprint("keys of addresses differing by zone collide: ", keys), nil))
+	}
+	for key, address := range keys {
+		if key.HasZone() != address.HasZone() || key.GetZone() != address.GetZone() {
+			t.addFailure(newAddrFailure("mismatched zone "+string(key.GetZone())+" of key "+key.String(), address.ToAddressBase()))
+		}
+		equals(t, key.ToAddress(), address)
+		if key.ToAddress().GetZone() != address.GetZone() {
+			t.addFailure(newAddrFailure("mismatched zone of address "+key.ToAddress().String()+" from key", address.ToAddressBase()))
+		}
+		keyEquals(t, key.WithoutZone(), unzoned.ToKey())
+		keyEquals(t, key.WithoutZone(), address.WithoutZone().ToKey())
+		keyEquals(t, key.WithZone("eth1"), zoned1.ToKey())
+		keyEquals(t, key.WithZone("eth2"), address.SetZone("eth2").ToKey())
+		keyEquals(t, key.WithZone(""), unzoned.ToKey())
+		hashEquals(t, key.WithZone("eth0"), zoned0, true)
+	}
+	t.incrementTestCount()
+}
+
+func (t keyTester) testHashes() {
+	addr := func(str string) *ipaddr.IPAddress {
+		return ipaddr.NewIPAddressString(str).GetAddress()
+	}
+	mac := func(str string) *ipaddr.MACAddress {
+		return ipaddr.NewMACAddressString(str).GetAddress()
+	}
+	hashEquals(t, addr("1.2.3.4"), addr("1.2.3.4/16"), true)
+	hashEquals(t, addr("1.2.0.0/16"), addr("1.2.*.*"), true)
+	hashEquals(t, addr("1.2.0.0/16"), addr("1.2.0.0"), false)
+	hashEquals(t, addr("1.2.3.4"), addr("::1.2.3.4"), false)
+	hashEquals(t, addr("1.2.3.4"), addr("1.2.3.5"), false)
+	hashEquals(t, addr("fe80::1%eth0"), addr("fe80::1"), false)
+	hashEquals(t, addr("fe80::1%eth0"), addr("fe80::1%eth1"), false)
+	hashEquals(t, addr("fe80::1%eth0").ToIPv6().ToKey(), addr("fe80::1%eth0"), true)
+	hashEquals(t, addr("1:2::/64").ToIPv6().ToKey(), addr("1:2::/64").ToKey(), true)
+	hashEquals(t, addr("1.2.3.4").GetSection(), addr("1.2.3.4"), true)
+	hashEquals(t, addr("1.2.3.4").GetSection(), addr("1.2.3.4").ToIPv4().GetSection(), true)
+	hashEquals(t, addr("1.2.3.4").GetSection(), addr("1.2.3.4").GetSubSection(0, 2), false)
+	hashEquals(t, mac("1:2:3:4:5:6"), mac("1:2:3:4:5:6").ToKey(), true)
+	hashEquals(t, mac("1:2:3:4:5:6:7:8"), mac("1:2:3:4:5:6:7:8").ToGenericKey(), true)
+	hashEquals(t, mac("1:2:3:4:5:6:7:8"), mac("1:2:3:4:5:6:7:8").ToAddressBase().ToKey(), true)
+	hashEquals(t, mac("1:2:3:*:*:*"), mac("1:2:3:*:*:*").ToKey(), true)
+	hashEquals(t, mac("1:2:3:4:5:6"), addr("102:304:506::").GetSubSection(0, 3), false)
+	hashEquals(t, &ipaddr.IPv4AddressSection{}, ipaddr.NewIPv4Section(nil), false)
+	hashEquals(t, &ipaddr.IPv4AddressSection{}, &ipaddr.MACAddressSection{}, true)
+}
+
+func hashEquals(t keyTester, one, two ipaddr.HashWriterTo, expected bool) {
+	hash1, hash2 := sha256.New(), sha256.New()
+	one.WriteToHash(hash1)
+	two.WriteToHash(hash2)
+	if bytes.Equal(hash1.Sum(nil), hash2.Sum(nil)) != expected {
+		t.addFailure(newFailure(fmt.Sprintf("hash of %v and %v match mismatch, expected match %v", one, two, expected), nil))
+	}
+	t.incrementTestCount()
 }
 
 var (
@@ -290,6 +379,8 @@ func (t keyTester) testKeys(cached []*ipaddr.Address) {
 	for _, addr := range cached {
 		addr2 := addr.ToKey().ToAddress()
 		equals(t, addr, addr2)
+		hashEquals(t, addr, addr.ToKey(), true)
+		hashEquals(t, addr, addr2, true)
 		if ipAddr := addr.ToIP(); ipAddr != nil {
 			other := ipAddr.ToKey().ToAddress()
 			equals(t, ipAddr, other)
@@ -310,6 +401,7 @@ func (t keyTester) testKeys(cached []*ipaddr.Address) {
 		if addrv4 := addr.ToIPv4(); addrv4 != nil {
 			other := addrv4.ToKey().ToAddress()
 			equals(t, addrv4, other)
+			hashEquals(t, addr, addrv4.ToKey(), true)
 
 			ipRange := ipaddr.NewSequentialRange(addrv4, &zero4Addr)
 			ipRangeBack := ipRange.ToKey().ToSeqRange()
@@ -329,6 +421,7 @@ func (t keyTester) testKeys(cached []*ipaddr.Address) {
 		if addrv6 := addr.ToIPv6(); addrv6 != nil {
 			other := addrv6.ToKey().ToAddress()
 			equals(t, addrv6, other)
+			hashEquals(t, addr, addrv6.ToKey(), true)
 
 			ipRange := ipaddr.NewSequentialRange(addrv6, &zero6Addr)
 			ipRangeBack := ipRange.ToKey().ToSeqRange()
@@ -348,6 +441,7 @@ func (t keyTester) testKeys(cached []*ipaddr.Address) {
 		if addrmac := addr.ToMAC(); addrmac != nil {
 			other := addrmac.ToKey().ToAddress()
 			equals(t, addrmac, other)
+			hashEquals(t, addr, addrmac.ToKey(), true)
 			//macCount++
 		}
 		t.incrementTestCount()
@@ -357,14 +451,39 @@ func (t keyTester) testKeys(cached []*ipaddr.Address) {
 type AddrConstraint[T ipaddr.KeyConstraint[T]] interface {
 	ipaddr.GenericKeyConstraint[T]
 	ipaddr.AddressType
+	ipaddr.HashWriterTo
 }
 
 func testGenericKeys[T AddrConstraint[T]](t keyTester, cached []T) {
 	for _, addr := range cached {
 		addr2 := addr.ToGenericKey().ToAddress()
 		equals(t, addr, addr2)
+		hashEquals(t, addr, addr.ToGenericKey(), true)
+		t.incrementTestCount()
+	}
+}
+
+func testSpecificKeys[T interface {
+	ipaddr.ToKeyConstraint[K]
+	ipaddr.AddressType
+	ipaddr.HashWriterTo
+}, K ipaddr.AddressKeyConstraint[T]](t keyTester, cached []T) {
+	keyed := make(map[K]T, len(cached))
+	for _, addr := range cached {
+		key := addr.ToKey()
+		equals(t, addr, key.ToAddress())
+		hashEquals(t, addr, key, true)
+		if existing, ok := keyed[key]; ok {
+			equals(t, addr, existing)
+		}
+		keyed[key] = addr
 		t.incrementTestCount()
 	}
+	for key, addr := range keyed {
+		if addr.ToKey() != key {
+			t.addFailure(newAddrFailure("key mismatch for "+key.String(), addr.ToAddressBase()))
+		}
+	}
 }
 
 func equals[TE interface{ addFailure(failure) }, T ipaddr.AddressType](t TE, one, two T) {
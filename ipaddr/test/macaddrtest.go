@@ -18,6 +18,7 @@ package test
 
 import (
 	"bytes"
+	"fmt"
 	"math"
 	"math/big"
 	"net"
@@ -570,6 +571,14 @@ func (t macAddressTester) run() {
 	t.testInsertAndAppendPrefs("a:b:c:d:e:f:aa:bb", "1:2:3:4:5:6:7:8", zerosPref[:])
 	t.testReplace("a:b:c:d:e:f:aa:bb", "1:2:3:4:5:6:7:8")
 
+	t.testOrganization("00:1b:c5:00:1f:ff", "small")
+	t.testOrganization("00:1b:c5:00:20:00", "large")
+	t.testOrganization("00:1b:c5:70:20:00", "medium")
+	t.testOrganization("00:1b:c5:00:1f:ff:fe:01", "small")
+	t.testOrganization("00:1c:00:00:00:00", "")
+	t.testOrganization("00:1b:c5:00:10-1f:*", "small")
+	t.testOrganization("00:1b:c5:00:*:*", "large")
+
 	t.testInvalidMACValues()
 
 	var sixZeros [6]int
@@ -592,6 +601,27 @@ func (t macAddressTester) run() {
 	t.testMACIPv6("FE80::212:7FFF:FEEB:6B40", "0012.7feb.6b40")
 	t.testMACIPv6("2001:DB8::212:7FFF:FEEB:6B40", "0012.7feb.6b40")
 
+	t.testAddToSegment("1:2:3:4:5:ff", 5, 1, "1:2:3:4:6:0")
+	t.testAddToSegment("1:2:3:4:5:6", 4, -6, "1:2:3:3:ff:6")
+	t.testAddToSegment("1:2:3:4:5:6:7:8", 1, 0x100, "2:2:3:4:5:6:7:8")
+	t.testAddToSegment("ff:ff:ff:ff:ff:ff", 5, 1, "")
+	t.testAddToSegment("1:2:3:fe-ff:*:*", 3, 1, "")
+	t.testAddToSegment("1:2:3:fe-ff:*:*", 3, 2, "1:2:4:0-1:*:*")
+
+	t.testGroupedString("a:b:c:d:e:f", 2, '.', false, "0a0b.0c0d.0e0f")
+	t.testGroupedString("a:b:c:d:e:f", 2, '.', true, "0A0B.0C0D.0E0F")
+	t.testGroupedString("a:b:c:d:e:f", 3, '-', false, "0a0b0c-0d0e0f")
+	t.testGroupedString("a:b:c:d:e:f", 1, ':', true, "0A:0B:0C:0D:0E:0F")
+	t.testGroupedString("a:b:c:d:e:f:1:2", 3, '.', false, "0a0b0c.0d0e0f.0102")
+	t.testGroupedString("a:b:c:*:*:*", 3, '-', false, "0a0b0c-*")
+	t.testGroupedString("a:b:1-2:*:*:*", 2, '.', false, "0a0b.0100-02ff.*")
+	t.testGroupedString("a:b:1-2:3:e:f", 2, '.', false, "0a.0b.01-02.03.0e.0f")
+
+	t.testMACTextAndSQL("a:b:c:d:e:f", true)
+	t.testMACTextAndSQL("0a0b.0c0d.0e0f", true)
+	t.testMACTextAndSQL("a:b:c:d:e:f:g", false)
+	t.testMACTextAndSQL("", true)
+
 	t.testStrings()
 }
 
@@ -719,6 +749,36 @@ func (t macAddressTester) testInsertAndAppendPrefs(front, back string, expectedP
 		ipaddr.MACColonSegmentSeparator, expectedPref, true)
 }
 
+type macOrgResolver map[string]string
+
+func (resolver macOrgResolver) ResolveOrganization(assignment *ipaddr.MACAddressSection) (string, bool) {
+	org, found := resolver[assignment.String()]
+	return org, found
+}
+
+func (t macAddressTester) testOrganization(addrStr, expectedOrg string) {
+	assignment := func(str string, bitCount ipaddr.BitCount) string {
+		return ipaddr.NewMACAddressString(str).GetAddress().GetAssignmentSection(bitCount).String()
+	}
+	resolver := macOrgResolver{
+		assignment("00:1b:c5:00:00:00", ipaddr.MACOrganizationalUniqueIdentifierBitCount): "large",
+		assignment("00:1b:c5:70:00:00", ipaddr.MACMediumAssignmentBitCount):               "medium",
+		assignment("00:1b:c5:00:10:00", ipaddr.MACSmallAssignmentBitCount):                "small",
+	}
+	addr := ipaddr.NewMACAddressString(addrStr).GetAddress()
+	org, found := addr.GetOrganization(resolver)
+	if found != (expectedOrg != "") || org != expectedOrg {
+		t.addFailure(newSegmentSeriesFailure("organization was "+org+" expected: "+expectedOrg, addr))
+	}
+	section := addr.GetAssignmentSection(ipaddr.MACSmallAssignmentBitCount)
+	if section.GetSegmentCount() != 5 || section.GetPrefixLen().Len() != ipaddr.MACSmallAssignmentBitCount {
+		t.addFailure(newSegmentSeriesFailure("assignment section was "+section.String(), addr))
+	} else if !addr.GetOUISection().ToPrefixBlockLen(24).Equal(addr.GetAssignmentSection(ipaddr.MACOrganizationalUniqueIdentifierBitCount)) {
+		t.addFailure(newSegmentSeriesFailure("OUI assignment section was "+addr.GetAssignmentSection(ipaddr.MACOrganizationalUniqueIdentifierBitCount).String(), addr))
+	}
+	t.incrementTestCount()
+}
+
 func (t macAddressTester) testReplace(front, back string) {
 	f := t.createMACAddress(front).GetAddress()
 	b := t.createMACAddress(back).GetAddress()
@@ -963,6 +1023,53 @@ func (t macAddressTester) testRadices(original, expected string, radix int) {
 	t.incrementTestCount()
 }
 
+func (t macAddressTester) testGroupedString(original string, segmentGrouping int, separator byte, uppercase bool, expected string) {
+	w := ipaddr.NewMACAddressString(original)
+	val := w.GetAddress()
+	options := new(addrstr.MACStringOptionsBuilder).SetSegmentGrouping(segmentGrouping).SetSeparator(separator).
+		SetUppercase(uppercase).SetExpandedSegments(true).ToOptions()
+	if str := val.ToCustomString(options); str != expected {
+		t.addFailure(newMACFailure("grouped string was "+str+" expected was "+expected, w))
+	} else if str = val.GetSection().ToCustomString(options); str != expected {
+		t.addFailure(newMACFailure("grouped section string was "+str+" expected was "+expected, w))
+	} else if options.GetSegmentGrouping() != segmentGrouping {
+		t.addFailure(newMACFailure("segment grouping was "+strconv.Itoa(options.GetSegmentGrouping()), w))
+	} else if segmentGrouping == 2 && separator == '.' && !uppercase && !val.IsMultiple() {
+		if dotted, _ := val.ToDottedString(); dotted != expected {
+			t.addFailure(newMACFailure("dotted string was "+dotted+" expected was "+expected, w))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t macAddressTester) testMACTextAndSQL(str string, valid bool) {
+	addrStr := ipaddr.NewMACAddressString(str)
+	var unmarshalled, scanned, scannedBytes ipaddr.MACAddressString
+	err := unmarshalled.UnmarshalText([]byte(str))
+	if valid != (err == nil) {
+		t.addFailure(newMACFailure("unmarshal validity not "+strconv.FormatBool(valid), addrStr))
+	} else if text, _ := unmarshalled.MarshalText(); string(text) != str {
+		t.addFailure(newMACFailure("marshalled to "+string(text), addrStr))
+	} else if err = scanned.Scan(str); valid != (err == nil) {
+		t.addFailure(newMACFailure("scan validity not "+strconv.FormatBool(valid), addrStr))
+	} else if err = scannedBytes.Scan([]byte(str)); valid != (err == nil) {
+		t.addFailure(newMACFailure("byte scan validity not "+strconv.FormatBool(valid), addrStr))
+	} else if value, _ := scanned.Value(); (str == "" && value != nil) || (str != "" && value != str) {
+		t.addFailure(newMACFailure(fmt.Sprint("value was ", value), addrStr))
+	} else if valid && (!unmarshalled.Equal(addrStr) || !scanned.Equal(addrStr) || !scannedBytes.Equal(addrStr)) {
+		t.addFailure(newMACFailure("unmarshalled or scanned string mismatch", addrStr))
+	} else if err = scanned.Scan(nil); err != nil || scanned.String() != "" {
+		t.addFailure(newMACFailure("scan of nil not empty", addrStr))
+	} else if value, _ := scanned.Value(); value != nil {
+		t.addFailure(newMACFailure(fmt.Sprint("value of empty string was ", value), addrStr))
+	} else if value, _ := (*ipaddr.MACAddressString)(nil).Value(); value != nil {
+		t.addFailure(newMACFailure(fmt.Sprint("value of nil string was ", value), addrStr))
+	} else if err = scanned.Scan(1); err == nil {
+		t.addFailure(newMACFailure("scan of integer succeeded", addrStr))
+	}
+	t.incrementTestCount()
+}
+
 func (t macAddressTester) testReverse(addressStr string, bitsReversedIsSame, bitsReversedPerByteIsSame bool) {
 	str := t.createMACAddress(addressStr)
 	t.testBase.testReverse(str.GetAddress().ToAddressBase().Wrap(), bitsReversedIsSame, bitsReversedPerByteIsSame)
@@ -1114,6 +1221,21 @@ func (t macAddressTester) isNotExpectedNonZero(expectedPass bool, addr *ipaddr.M
 	return !expectedPass
 }
 
+func (t macAddressTester) testAddToSegment(addrStr string, index int, delta int64, expected string) {
+	addr := ipaddr.NewMACAddressString(addrStr).GetAddress()
+	result := addr.GetSection().AddToSegment(index, delta)
+	if expected == "" {
+		if result != nil {
+			t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("expected nil adding %d to segment %d, got %v", delta, index, result), addr))
+		}
+	} else if expectedSection := ipaddr.NewMACAddressString(expected).GetAddress().GetSection(); result == nil {
+		t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("unexpected nil adding %d to segment %d", delta, index), addr))
+	} else if !result.Equal(expectedSection) || !addr.GetSection().ToSectionBase().AddToSegment(index, delta).Equal(expectedSection) {
+		t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("adding %d to segment %d gave %v, expected %v", delta, index, result, expected), addr))
+	}
+	t.incrementTestCount()
+}
+
 func (t macAddressTester) testMACIPv6(ipv6, mac string) {
 	ipv6Str := t.createAddress(ipv6)
 	macStr := t.createMACAddress(mac)
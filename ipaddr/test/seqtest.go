@@ -0,0 +1,233 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+//go:build go1.23
+
+package test
+
+import (
+	"strconv"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr"
+)
+
+func init() {
+	testAddressSeqs = func(t ipAddressTester) {
+		t.testSeq("1.2.3.4")
+		t.testSeq("1.2.3-4.5-6")
+		t.testSeq("1:2::3-5")
+		t.testSeqRange("1.2.3.250", "1.2.4.3")
+		t.testMACSeq("a:b:c:d:e:1-3")
+		t.testVectorSeq([]string{"1.2.3.4", "1.2.3.5/24", "5.6.7.8"})
+	}
+	testTrieSeqs = func(t trieTesterGeneric) {
+		t.testTrieSeqs([]string{"1.2.3.4", "1.2.0.0/16", "1.2.3.5", "2.0.0.0/8"})
+	}
+}
+
+func (t ipAddressTester) testSeq(str string) {
+	addr := ipaddr.NewIPAddressString(str).GetAddress()
+	var addrs []*ipaddr.IPAddress
+	for a := range addr.All() {
+		addrs = append(addrs, a)
+	}
+	iterator := addr.Iterator()
+	for _, a := range addrs {
+		if !iterator.HasNext() || !iterator.Next().Equal(a) {
+			t.addFailure(newIPAddrFailure("sequence mismatch with iterator at "+a.String(), addr))
+		}
+	}
+	if iterator.HasNext() || uint64(len(addrs)) != addr.GetCount().Uint64() {
+		t.addFailure(newIPAddrFailure("sequence length "+strconv.Itoa(len(addrs)), addr))
+	}
+	var sections int
+	for section := range addr.GetSection().All() {
+		if !section.Equal(addrs[sections].GetSection()) {
+			t.addFailure(newIPAddrFailure("section sequence mismatch at "+section.String(), addr))
+		}
+		sections++
+	}
+	var segs int
+	for range addr.GetSegment(addr.GetSegmentCount() - 1).All() {
+		segs++
+	}
+	if sections != len(addrs) || segs == 0 {
+		t.addFailure(newIPAddrFailure("section or segment sequence length mismatch", addr))
+	}
+	var count int
+	for range addr.ToAddressBase().All() {
+		count++
+		break
+	}
+	if count != 1 {
+		t.addFailure(newIPAddrFailure("sequence not stopped by break", addr))
+	}
+	if addr.IsIPv4() {
+		count = 0
+		for a := range addr.ToIPv4().All() {
+			if !a.Equal(addrs[count]) {
+				t.addFailure(newIPAddrFailure("IPv4 sequence mismatch at "+a.String(), addr))
+			}
+			count++
+		}
+	} else {
+		count = 0
+		for a := range addr.ToIPv6().All() {
+			if !a.Equal(addrs[count]) {
+				t.addFailure(newIPAddrFailure("IPv6 sequence mismatch at "+a.String(), addr))
+			}
+			count++
+		}
+	}
+	if count != len(addrs) {
+		t.addFailure(newIPAddrFailure("version sequence length "+strconv.Itoa(count), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testSeqRange(lowerStr, upperStr string) {
+	lower, upper := ipaddr.NewIPAddressString(lowerStr).GetAddress(), ipaddr.NewIPAddressString(upperStr).GetAddress()
+	rng := lower.SpanWithRange(upper)
+	expected := lower
+	var count int
+	for a := range rng.All() {
+		if !a.Equal(expected) {
+			t.addFailure(newIPAddrFailure("range sequence was "+a.String()+" not "+expected.String(), lower))
+			break
+		}
+		expected = expected.Increment(1)
+		count++
+	}
+	if uint64(count) != rng.GetCount().Uint64() {
+		t.addFailure(newIPAddrFailure("range sequence length "+strconv.Itoa(count), lower))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testVectorSeq(strs []string) {
+	vec := ipaddr.NewIPAddressVector(ipaddr.IPv4)
+	vec.AppendStrings(strs...)
+	var i int
+	for a := range vec.All() {
+		if expected := ipaddr.NewIPAddressString(strs[i]).GetAddress(); !a.Equal(expected) {
+			t.addFailure(newIPAddrFailure("vector sequence was "+a.String()+" not "+strs[i], expected))
+			break
+		}
+		i++
+	}
+	if i != len(strs) {
+		t.addFailure(newFailure("vector sequence length "+strconv.Itoa(i), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t ipAddressTester) testMACSeq(str string) {
+	addr := ipaddr.NewMACAddressString(str).GetAddress()
+	var count int
+	for a := range addr.All() {
+		if !addr.Contains(a) || a.IsMultiple() {
+			t.addFailure(newMACAddrFailure("MAC sequence produced "+a.String(), addr))
+		}
+		count++
+	}
+	var sections int
+	for range addr.GetSection().All() {
+		sections++
+	}
+	if uint64(count) != addr.GetCount().Uint64() || sections != count {
+		t.addFailure(newMACAddrFailure("MAC sequence length "+strconv.Itoa(count), addr))
+	}
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) testTrieSeqs(strs []string) {
+	trie := ipaddr.Trie[*ipaddr.IPv4Address]{}
+	assocTrie := ipaddr.AssociativeTrie[*ipaddr.IPv4Address, int]{}
+	for i, str := range strs {
+		addr := ipaddr.NewIPAddressString(str).GetAddress().ToIPv4()
+		trie.Add(addr)
+		assocTrie.Put(addr, i)
+	}
+	iterator := trie.Iterator()
+	for addr := range trie.All() {
+		if !iterator.HasNext() || !iterator.Next().Equal(addr) {
+			t.addFailure(newAddressItemFailure("trie sequence mismatch", addr))
+		}
+	}
+	if iterator.HasNext() {
+		t.addFailure(newFailure("trie sequence too short", nil))
+	}
+	descending := trie.DescendingIterator()
+	for addr := range trie.Backward() {
+		if !descending.HasNext() || !descending.Next().Equal(addr) {
+			t.addFailure(newAddressItemFailure("trie backward sequence mismatch", addr))
+		}
+	}
+	var nodes, rootNodes, rootKeys int
+	for node := range trie.Nodes() {
+		if !node.IsAdded() {
+			t.addFailure(newAddressItemFailure("trie node sequence produced non-added node", node.GetKey()))
+		}
+		nodes++
+	}
+	for range trie.GetRoot().Nodes() {
+		rootNodes++
+	}
+	for range trie.GetRoot().All() {
+		rootKeys++
+	}
+	for range trie.GetRoot().Backward() {
+		rootKeys--
+	}
+	if nodes != trie.Size() || rootNodes != nodes || rootKeys != 0 {
+		t.addFailure(newFailure("trie node sequence length "+strconv.Itoa(nodes), nil))
+	}
+	keys := assocTrie.Iterator()
+	var sum, valueSum, count int
+	for addr, val := range assocTrie.All() {
+		if mapped, _ := assocTrie.Get(addr); !keys.HasNext() || !keys.Next().Equal(addr) || mapped != val {
+			t.addFailure(newAddressItemFailure("associative trie sequence mismatch", addr))
+		}
+		sum += val
+	}
+	for val := range assocTrie.Values() {
+		valueSum += val
+	}
+	for range assocTrie.Keys() {
+		count++
+	}
+	var rootSum int
+	for node := range assocTrie.GetRoot().Nodes() {
+		rootSum += node.GetValue()
+	}
+	for _, val := range assocTrie.GetRoot().All() {
+		rootSum += val
+	}
+	for val := range assocTrie.GetRoot().Values() {
+		rootSum += val
+	}
+	for range assocTrie.GetRoot().Keys() {
+		count--
+	}
+	for range assocTrie.Nodes() {
+		count++
+	}
+	expectedSum := len(strs) * (len(strs) - 1) / 2
+	if sum != expectedSum || valueSum != expectedSum || rootSum != 3*expectedSum || count != assocTrie.Size() {
+		t.addFailure(newFailure("associative trie sequence sums "+strconv.Itoa(sum)+" "+strconv.Itoa(valueSum), nil))
+	}
+	t.incrementTestCount()
+}
@@ -155,6 +155,7 @@ func (t specialTypesTester) run() {
 
 	t.testNils()
 	t.testZeros()
+	t.testEmptySections()
 }
 
 func (t specialTypesTester) testIPv4Strings(addr string, explicit bool, normalizedString, normalizedWildcardString, sqlString, fullString, reverseDNSString, singleHex, singleOctal string) {
@@ -799,6 +800,69 @@ func (t specialTypesTester) testZeros() {
 	t.incrementTestCount()
 }
 
+func (t specialTypesTester) testEmptySections() {
+	// each section is paired with a name for its type, empty sections being equal only when their types match
+	emptySections := []struct {
+		section  *ipaddr.AddressSection
+		typeName string
+	}{
+		{ipaddr.NewIPv4Section(nil).ToSectionBase(), "ipv4"},
+		{ipaddr.NewIPv4Section(nil).ToIP().ToSectionBase(), "ipv4"},
+		{ipaddr.NewIPv6Section(nil).ToSectionBase(), "ipv6"},
+		{ipaddr.NewMACSection(nil).ToSectionBase(), "mac"},
+		{(&ipaddr.IPv4AddressSection{}).ToSectionBase(), "zero"},
+		{(&ipaddr.IPv6AddressSection{}).ToSectionBase(), "zero"},
+		{(&ipaddr.MACAddressSection{}).ToSectionBase(), "zero"},
+		{(&ipaddr.IPAddressSection{}).ToSectionBase(), "zero"},
+		{&ipaddr.AddressSection{}, "zero"},
+	}
+	for _, empty := range emptySections {
+		section := empty.section
+		if !section.IsEmptySection() {
+			t.addFailure(newSegmentSeriesFailure("not an empty section", section))
+		} else if section.GetCount().Cmp(bigOneConst()) != 0 || section.IsMultiple() || !section.IsSequential() {
+			t.addFailure(newSegmentSeriesFailure("empty section count is "+section.GetCount().String(), section))
+		} else if section.IsPrefixed() || section.ToPrefixBlockLen(0).IsPrefixed() {
+			t.addFailure(newSegmentSeriesFailure("empty section is prefixed", section))
+		} else if !section.ContainsPrefixBlock(0) || !section.ContainsSinglePrefixBlock(0) ||
+			len(section.ContainsPrefixBlockBySegment(0)) != 0 || section.GetMinPrefixLenForBlock() != 0 {
+			t.addFailure(newSegmentSeriesFailure("empty section does not contain its prefix block", section))
+		}
+		var iterated []*ipaddr.AddressSection
+		for iter := section.Iterator(); iter.HasNext(); {
+			iterated = append(iterated, iter.Next())
+		}
+		if len(iterated) != 1 || !iterated[0].Equal(section) {
+			t.addFailure(newSegmentSeriesFailure("empty section iterated "+strconv.Itoa(len(iterated))+" times", section))
+		}
+		if ipSection := section.ToIP(); ipSection != nil {
+			if spanned := ipSection.SpanWithPrefixBlocks(); len(spanned) != 1 || !spanned[0].Equal(section) {
+				t.addFailure(newSegmentSeriesFailure("empty section spanned by "+strconv.Itoa(len(spanned))+" blocks", section))
+			} else if spanned = ipSection.SpanWithSequentialBlocks(); len(spanned) != 1 || !spanned[0].Equal(section) {
+				t.addFailure(newSegmentSeriesFailure("empty section spanned by "+strconv.Itoa(len(spanned))+" sequential blocks", section))
+			}
+		}
+		for _, other := range emptySections {
+			expected := empty.typeName == other.typeName
+			if section.Equal(other.section) != expected || section.Contains(other.section) != expected ||
+				(section.Compare(other.section) == 0) != expected {
+				t.addFailure(newSegmentSeriesFailure("empty section comparison with "+other.typeName+" section, expected equality "+strconv.FormatBool(expected), section))
+			}
+		}
+		t.incrementTestCount()
+	}
+
+	ipZero := &ipaddr.IPAddress{}
+	if intersection := ipZero.Intersect(ipZero); intersection == nil || !intersection.Equal(ipZero) {
+		t.addFailure(newIPAddrFailure("zero address does not intersect itself", ipZero))
+	} else if subtracted := ipZero.Subtract(ipZero); len(subtracted) != 0 {
+		t.addFailure(newIPAddrFailure("zero address subtracted from itself is not empty", ipZero))
+	} else if intersection = ipZero.Intersect(ipaddr.NewIPAddressString("0.0.0.0").GetAddress()); intersection != nil {
+		t.addFailure(newIPAddrFailure("zero address intersects IPv4 zero address", ipZero))
+	}
+	t.incrementTestCount()
+}
+
 func (t specialTypesTester) testNils() {
 	var ipRangesIPv4 []*ipaddr.IPAddressSeqRange
 	ipv4Addr1 := ipaddr.NewIPAddressString("1.2.3.3").GetAddress().ToIPv4()
@@ -64,6 +64,11 @@ func Test(isLimited bool) {
 		wg.Wait()
 	}
 
+	// the default string format and the address converter apply package-wide, so they are tested only when no other tests are running
+	globalTester := ipAddressTester{testBase{testResults: &acc, testAddresses: &addresses, fullTest: fullTest}}
+	globalTester.testDefaultStringFormat()
+	globalTester.testRegisteredAddressConverter()
+
 	endTime := time.Now().Sub(startTime)
 	//fmt.Printf("TestRunner\ntest count: %d\nfail count: %d\n", acc.counter, len(acc.failures))
 	if len(acc.failures) > 0 {
@@ -155,6 +160,38 @@ type testBase struct {
 	testAddresses
 }
 
+// genericSegmentSeries is a series providing all its segments as ipaddr.AddressSegmentType
+type genericSegmentSeries interface {
+	ipaddr.AddressSegmentSeries
+	ipaddr.GenericSegmentsProvider
+}
+
+func (t testBase) testGenericSegments(series genericSegmentSeries) {
+	segs := series.GetGenericSegments()
+	if len(segs) != series.GetSegmentCount() {
+		t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("generic segment count %d does not match %d", len(segs), series.GetSegmentCount()), series))
+	}
+	for i, seg := range segs {
+		if other := series.GetGenericSegment(i); !seg.Equal(other) || seg.GetBitCount() != other.GetBitCount() {
+			t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("generic segment %v at index %d does not match %v", seg, i, other), series))
+		}
+	}
+	copied := make([]ipaddr.AddressSegmentType, series.GetSegmentCount()-1)
+	if count := series.CopyGenericSegments(copied); count != len(copied) {
+		t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("copied %d generic segments into slice of length %d", count, len(copied)), series))
+	}
+	for i, seg := range copied {
+		if !seg.Equal(segs[i]) {
+			t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("copied generic segment %v at index %d does not match %v", seg, i, segs[i]), series))
+		}
+	}
+	copied = make([]ipaddr.AddressSegmentType, series.GetSegmentCount()+1)
+	if count := series.CopyGenericSegments(copied); count != series.GetSegmentCount() || copied[count] != nil {
+		t.addFailure(newSegmentSeriesFailure(fmt.Sprintf("copied %d generic segments into slice of length %d", count, len(copied)), series))
+	}
+	t.incrementTestCount()
+}
+
 func (t testBase) testReverse(series ipaddr.ExtendedSegmentSeries, bitsReversedIsSame, bitsReversedPerByteIsSame bool) {
 	segmentsReversed := series.ReverseSegments()
 	divCount := series.GetDivisionCount()
@@ -742,6 +779,10 @@ func (t testBase) testIncrement(orig *ipaddr.Address, increment int64, expectedR
 
 func (t testBase) testIncrementF(orig *ipaddr.Address, increment int64, expectedResult *ipaddr.Address, first bool) {
 	result := orig.Increment(increment)
+	bigResult := orig.IncrementBig(big.NewInt(increment))
+	if (result == nil) != (bigResult == nil) || (result != nil && !result.Equal(bigResult)) {
+		t.addFailure(newSegmentSeriesFailure("big increment mismatch result "+bigResult.String()+" vs "+result.String(), orig))
+	}
 	if expectedResult == nil {
 		if result != nil {
 			t.addFailure(newSegmentSeriesFailure("increment mismatch result "+result.String()+" vs none expected", orig))
@@ -16,10 +16,15 @@
 package test
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"github.com/seancfoley/ipaddress-go/ipaddr"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 )
 
@@ -56,10 +61,24 @@ func NewAssociativeAddressGenericTrie[V any]() *ipaddr.AssociativeTrie[*ipaddr.A
 	return &ipaddr.AssociativeTrie[*ipaddr.Address, V]{}
 }
 
+// testTrieSeqs tests the trie sequences, which are available when built with Go 1.23 or later
+var testTrieSeqs func(trieTesterGeneric)
+
 func (t trieTesterGeneric) run() {
 
 	t.testAddressCheck()
 	t.partitionTest()
+	t.testPrefixList()
+	t.testCSV()
+	t.testDiff()
+	t.testValueKeys()
+	t.testValueUpdates()
+	t.testSubTrie()
+	t.testTrieStructure()
+	t.testGob()
+	if testTrieSeqs != nil {
+		testTrieSeqs(t)
+	}
 
 	sampleIPAddressTries := t.getSampleIPAddressTries()
 	for _, treeAddrs := range sampleIPAddressTries {
@@ -249,6 +268,109 @@ func (t trieTesterGeneric) run() {
 		t.addFailure(newTrieFailure("unexpected size "+strconv.Itoa(trie.Size()), trie))
 	}
 	t.incrementTestCount()
+
+	t.testNetworkRegistry()
+}
+
+func (t trieTesterGeneric) testNetworkRegistry() {
+	registry := &ipaddr.NetworkRegistry{}
+	register := func(name, str string, expectErr bool) {
+		err := registry.Register(name, ipaddr.NewIPAddressString(str).GetAddress())
+		if (err != nil) != expectErr {
+			t.addFailure(newFailure(fmt.Sprintf("unexpected result registering %s: %v", name, err), ipaddr.NewIPAddressString(str)))
+		}
+	}
+	register("corp-lan", "10.0.0.0/8", false)
+	register("lab", "10.1.0.0/16", false)
+	register("printer", "10.1.2.3", false)
+	register("dmz", "192.168.1.0/24", false)
+	register("v6-lan", "2001:db8::/32", false)
+	register("v6-host", "2001:db8::1%eth0", false)
+	register("range", "10.2.3.1-2", true)
+	register("converted", "10.3.0-255.*", false) // converted to 10.3.0.0/16
+	t.checkRegistryLookups(registry)
+
+	// reassign names and networks
+	register("lab", "10.2.0.0/16", false)
+	register("dmz2", "192.168.1.0/24", false)
+	if net := registry.Get("dmz"); net != nil {
+		t.addFailure(newFailure("dmz still registered as "+net.String(), nil))
+	}
+	t.checkRegistryLookup(registry, "10.1.2.4", "corp-lan", "corp-lan")
+	t.checkRegistryLookup(registry, "10.2.2.4", "lab", "corp-lan", "lab")
+	t.checkRegistryLookup(registry, "192.168.1.1", "dmz2", "dmz2")
+	if !registry.Unregister("dmz2") || registry.Unregister("dmz2") {
+		t.addFailure(newFailure("unexpected unregister result", nil))
+	}
+	t.checkRegistryLookup(registry, "192.168.1.1", "")
+	register("lab", "10.1.0.0/16", false)
+	register("dmz", "192.168.1.0/24", false)
+
+	bytes, err := json.Marshal(registry)
+	if err != nil {
+		t.addFailure(newFailure("failed to marshal registry: "+err.Error(), nil))
+	} else if str := string(bytes); str != `{"converted":"10.3.0.0/16","corp-lan":"10.0.0.0/8","dmz":"192.168.1.0/24","lab":"10.1.0.0/16","printer":"10.1.2.3","v6-host":"2001:db8::1","v6-lan":"2001:db8::/32"}` {
+		t.addFailure(newFailure("unexpected JSON "+str, nil))
+	} else {
+		unmarshalled := &ipaddr.NetworkRegistry{}
+		if err = json.Unmarshal(bytes, unmarshalled); err != nil {
+			t.addFailure(newFailure("failed to unmarshal registry: "+err.Error(), nil))
+		} else {
+			t.checkRegistryLookups(unmarshalled)
+		}
+	}
+	invalid := &ipaddr.NetworkRegistry{}
+	invalid.Register("a", ipaddr.NewIPAddressString("1.2.3.4").GetAddress())
+	if err = json.Unmarshal([]byte(`{"a":"1.2.3.4","b":"1.2.3.1-2"}`), invalid); err == nil {
+		t.addFailure(newFailure("unmarshalled non-block network", nil))
+	} else if invalid.Len() != 1 || invalid.Get("a") == nil {
+		t.addFailure(newFailure("registry modified by failed unmarshal", nil))
+	}
+	if err = json.Unmarshal([]byte(`{"b":"10.0.0.0/8","c":"10.0.0.0/8"}`), invalid); err == nil {
+		t.addFailure(newFailure("unmarshalled network with two names", nil))
+	} else if invalid.Len() != 1 || invalid.Get("a") == nil {
+		t.addFailure(newFailure("registry modified by failed unmarshal", nil))
+	}
+	if bytes, err = json.Marshal(*invalid); err != nil || string(bytes) != `{"a":"1.2.3.4"}` {
+		t.addFailure(newFailure("unexpected JSON of registry value "+string(bytes), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) checkRegistryLookups(registry *ipaddr.NetworkRegistry) {
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{"converted", "corp-lan", "dmz", "lab", "printer", "v6-host", "v6-lan"}) {
+		t.addFailure(newFailure(fmt.Sprintf("unexpected names %v", names), nil))
+	}
+	t.checkRegistryLookup(registry, "10.1.2.3", "printer", "corp-lan", "lab", "printer")
+	t.checkRegistryLookup(registry, "10.1.2.4", "lab", "corp-lan", "lab")
+	t.checkRegistryLookup(registry, "10.1.2.0/24", "lab", "corp-lan", "lab")
+	t.checkRegistryLookup(registry, "10.1.2.3-4", "lab", "corp-lan", "lab")
+	t.checkRegistryLookup(registry, "10.3.2.1", "converted", "corp-lan", "converted")
+	t.checkRegistryLookup(registry, "10.0.0.0/7", "")
+	t.checkRegistryLookup(registry, "11.1.2.3", "")
+	t.checkRegistryLookup(registry, "192.168.1.1/24", "dmz", "dmz")
+	t.checkRegistryLookup(registry, "2001:db8::1", "v6-host", "v6-lan", "v6-host")
+	t.checkRegistryLookup(registry, "2001:db8::1%eth1", "v6-host", "v6-lan", "v6-host")
+	t.checkRegistryLookup(registry, "2001:db8::2", "v6-lan", "v6-lan")
+	t.checkRegistryLookup(registry, "::ffff:10.1.2.3", "")
+}
+
+func (t trieTesterGeneric) checkRegistryLookup(registry *ipaddr.NetworkRegistry, addrStr, expectedName string, expectedAll ...string) {
+	addr := ipaddr.NewIPAddressString(addrStr).GetAddress()
+	name, network := registry.Lookup(addr)
+	if ipv6Addr := addr.ToIPv6(); ipv6Addr != nil {
+		addr = ipv6Addr.WithoutZone().ToIP()
+	}
+	if name != expectedName {
+		t.addFailure(newIPAddrFailure("looked up name "+name+", expected "+expectedName, addr))
+	} else if name != "" && (!network.Equal(registry.Get(name)) || !network.Contains(addr)) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("looked up network %v for name %s", network, name), addr))
+	} else if name == "" && network != nil {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("looked up network %v with no name", network), addr))
+	}
+	if all := registry.LookupAll(addr); !reflect.DeepEqual(all, expectedAll) && (len(all) > 0 || len(expectedAll) > 0) {
+		t.addFailure(newIPAddrFailure(fmt.Sprintf("looked up names %v, expected %v", all, expectedAll), addr))
+	}
 }
 
 func (t trieTesterGeneric) testString(strs trieStrings) {
@@ -1099,6 +1221,449 @@ func (t trieTesterGeneric) testIterator(
 	t.incrementTestCount()
 }
 
+func (t trieTesterGeneric) testPrefixList() {
+	var blocks []*ipaddr.IPAddress
+	for _, str := range []string{"2001:db8::/32", "1.2.3.4-5", "10.0.0.0/8", "192.168.1.1"} {
+		blocks = append(blocks, ipaddr.NewIPAddressString(str).GetAddress())
+	}
+	t.testPrefixListFormat(blocks, "EXAMPLE", ipaddr.PrefixListOptions{Description: "test"},
+		"ip prefix-list EXAMPLE description test\n"+
+			"ip prefix-list EXAMPLE seq 5 permit 1.2.3.4/31\n"+
+			"ip prefix-list EXAMPLE seq 10 permit 10.0.0.0/8\n"+
+			"ip prefix-list EXAMPLE seq 15 permit 192.168.1.1/32\n"+
+			"ipv6 prefix-list EXAMPLE description test\n"+
+			"ipv6 prefix-list EXAMPLE seq 5 permit 2001:db8::/32\n")
+	t.testPrefixListFormat(blocks, "EXAMPLE", ipaddr.PrefixListOptions{Deny: true, SequenceStart: 10, SequenceIncrement: 1},
+		"ip prefix-list EXAMPLE seq 10 deny 1.2.3.4/31\n"+
+			"ip prefix-list EXAMPLE seq 11 deny 10.0.0.0/8\n"+
+			"ip prefix-list EXAMPLE seq 12 deny 192.168.1.1/32\n"+
+			"ipv6 prefix-list EXAMPLE seq 10 deny 2001:db8::/32\n")
+	t.testPrefixListFormat(blocks, "EXAMPLE", ipaddr.PrefixListOptions{Format: ipaddr.JunOSPrefixListFormat},
+		"policy-options {\n"+
+			"    prefix-list EXAMPLE {\n"+
+			"        1.2.3.4/31;\n"+
+			"        10.0.0.0/8;\n"+
+			"        192.168.1.1/32;\n"+
+			"        2001:db8::/32;\n"+
+			"    }\n"+
+			"}\n")
+	t.testPrefixListFormat(blocks, "RS-EXAMPLE", ipaddr.PrefixListOptions{Format: ipaddr.RPSLRouteSetFormat, Description: "test"},
+		"route-set:      RS-EXAMPLE\n"+
+			"descr:          test\n"+
+			"members:        1.2.3.4/31\n"+
+			"members:        10.0.0.0/8\n"+
+			"members:        192.168.1.1/32\n"+
+			"mp-members:     2001:db8::/32\n")
+
+	var builder strings.Builder
+	macs := []*ipaddr.Address{ipaddr.NewMACAddressString("1:2:3:4:5:6").GetAddress().ToAddressBase()}
+	if err := ipaddr.WritePrefixList(&builder, "EXAMPLE", macs, ipaddr.PrefixListOptions{}); err == nil {
+		t.addFailure(newAddressItemFailure("expected failure writing MAC addresses as prefix list, got "+builder.String(), macs[0]))
+	}
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) testPrefixListFormat(blocks []*ipaddr.IPAddress, name string, options ipaddr.PrefixListOptions, expected string) {
+	var builder strings.Builder
+	if err := ipaddr.WritePrefixList(&builder, name, blocks, options); err != nil {
+		t.addFailure(newFailure("unexpected error writing prefix list: "+err.Error(), nil))
+	} else if builder.String() != expected {
+		t.addFailure(newFailure("prefix list\n"+builder.String()+"does not match expected\n"+expected, nil))
+	} else {
+		// the IPv4 blocks are already sorted, so the trie should produce the same list
+		trie := ipaddr.Trie[*ipaddr.IPAddress]{}
+		var ipv4Blocks []*ipaddr.IPAddress
+		for _, block := range blocks {
+			if block.IsIPv4() {
+				for _, span := range block.SpanWithPrefixBlocks() {
+					trie.Add(span)
+					ipv4Blocks = append(ipv4Blocks, span)
+				}
+			}
+		}
+		var ipv4Builder strings.Builder
+		_ = ipaddr.WritePrefixList(&ipv4Builder, name, ipv4Blocks, options)
+		builder.Reset()
+		if err = trie.WritePrefixList(&builder, name, options); err != nil {
+			t.addFailure(newFailure("unexpected error writing trie prefix list: "+err.Error(), nil))
+		} else if builder.String() != ipv4Builder.String() {
+			t.addFailure(newFailure("trie prefix list\n"+builder.String()+"does not match expected\n"+ipv4Builder.String(), nil))
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) testCSV() {
+	ipv4Trie := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]()
+	for i, str := range []string{"1.2.0.0/16", "1.2.3.4", "10.0.0.0/8", "0.0.0.0/0", "1.2.3.128/25"} {
+		ipv4Trie.Put(ipaddr.NewIPAddressString(str).GetAddress().ToIPv4(), i)
+	}
+	testCSVRoundTrip(t, ipv4Trie, strconv.Itoa, strconv.Atoi)
+
+	ipv6Trie := ipaddr.NewAssociativeTrie[*ipaddr.IPv6Address, string]()
+	for _, str := range []string{"2001:db8::/32", "2001:db8::1", "fe80::1%eth0", "::/0"} {
+		ipv6Trie.Put(ipaddr.NewIPAddressString(str).GetAddress().ToIPv6(), "value, \"quoted\" "+str)
+	}
+	testCSVRoundTrip(t, ipv6Trie, func(s string) string { return s }, func(s string) (string, error) { return s, nil })
+
+	macTrie := ipaddr.NewAssociativeTrie[*ipaddr.Address, string]()
+	for _, str := range []string{"1:2:3:*:*:*", "1:2:3:4:5:6", "a:b:c:d:*:*"} {
+		macTrie.Put(ipaddr.NewMACAddressString(str).GetAddress().ToAddressBase(), str)
+	}
+	testCSVRoundTrip(t, macTrie, func(s string) string { return s }, func(s string) (string, error) { return s, nil })
+	t.testCSVVersions()
+
+	for _, invalid := range []string{"#version,1\n1.2.3.4,1\n1.2.3.4-6,2\n", "#version,1\n1.2.3.4,1\nfoo,2\n", "#version,1\n1.2.3.4,x\n",
+		"#version,1\n1.2.3.4\n", "#version,1\n::1,1\n", "#version,2\n1.2.3.4,1\n", "#version,x\n", "#version,1\n1.2.3.4,1\n#version,1\n",
+		"1.2.3.4,1\n", ""} {
+		trie := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]()
+		if err := trie.ReadCSV(strings.NewReader(invalid), strconv.Atoi); err == nil {
+			t.addFailure(newFailure("expected failure reading CSV "+invalid+", got trie "+trie.String(), nil))
+		}
+		t.incrementTestCount()
+	}
+}
+
+func (t trieTesterGeneric) testDiff() {
+	trie, otherTrie := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int](), ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]()
+	for i, str := range []string{"1.2.0.0/16", "1.2.3.4", "10.0.0.0/8", "0.0.0.0/0", "1.2.3.128/25"} {
+		trie.Put(ipaddr.NewIPAddressString(str).GetAddress().ToIPv4(), i)
+	}
+	for i, str := range []string{"1.2.0.0/16", "1.2.3.5", "10.0.0.0/8", "1.2.3.128/25", "192.168.0.0/16"} {
+		otherTrie.Put(ipaddr.NewIPAddressString(str).GetAddress().ToIPv4(), i)
+	}
+	onlyInThis, onlyInOther, changed := trie.Diff(otherTrie, nil)
+	t.checkDiffKeys(onlyInThis, "1.2.3.4", "0.0.0.0/0")
+	t.checkDiffKeys(onlyInOther, "1.2.3.5", "192.168.0.0/16")
+	t.checkDiffKeys(changed, "1.2.3.128/25")
+
+	_, _, changed = trie.Diff(otherTrie, func(value, otherValue int) bool { return value/8 == otherValue/8 })
+	t.checkDiffKeys(changed)
+
+	onlyInOther, onlyInThis, changed = otherTrie.Diff(trie, nil)
+	t.checkDiffKeys(onlyInThis, "1.2.3.4", "0.0.0.0/0")
+	t.checkDiffKeys(onlyInOther, "1.2.3.5", "192.168.0.0/16")
+	t.checkDiffKeys(changed, "1.2.3.128/25")
+
+	keys, otherKeys := ipaddr.Trie[*ipaddr.IPv4Address]{}, ipaddr.Trie[*ipaddr.IPv4Address]{}
+	for iter := trie.Iterator(); iter.HasNext(); {
+		keys.Add(iter.Next())
+	}
+	for iter := otherTrie.Iterator(); iter.HasNext(); {
+		otherKeys.Add(iter.Next())
+	}
+	keysOnlyInThis, keysOnlyInOther := keys.Diff(&otherKeys)
+	t.checkDiffKeys(keysOnlyInThis, "1.2.3.4", "0.0.0.0/0")
+	t.checkDiffKeys(keysOnlyInOther, "1.2.3.5", "192.168.0.0/16")
+
+	keysOnlyInThis, keysOnlyInOther = keys.Diff(nil)
+	t.checkDiffKeys(keysOnlyInThis, "1.2.3.4", "1.2.3.128/25", "1.2.0.0/16", "10.0.0.0/8", "0.0.0.0/0")
+	t.checkDiffKeys(keysOnlyInOther)
+
+	count := 0
+	keys.DiffFunc(&otherKeys, func(node, otherNode *ipaddr.TrieNode[*ipaddr.IPv4Address]) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.addFailure(newFailure("diff visited "+strconv.Itoa(count)+" nodes after stopping, expected 1", nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) testGob() {
+	trie := ipaddr.NewAssociativeTrie[*ipaddr.IPAddress, string]()
+	keys := ipaddr.Trie[*ipaddr.IPAddress]{}
+	for _, str := range []string{"1.2.0.0/16", "1.2.3.4", "10.0.0.0/8", "0.0.0.0/0", "1.2.3.128/25"} {
+		addr := ipaddr.NewIPAddressString(str).GetAddress()
+		trie.Put(addr, str)
+		keys.Add(addr)
+	}
+	var buf bytes.Buffer
+	decoded, decodedKeys := ipaddr.NewAssociativeTrie[*ipaddr.IPAddress, string](), ipaddr.Trie[*ipaddr.IPAddress]{}
+	if err := gob.NewEncoder(&buf).Encode(trie); err != nil {
+		t.addFailure(newFailure("gob encoding failed: "+err.Error(), nil))
+	} else if err = gob.NewDecoder(&buf).Decode(decoded); err != nil {
+		t.addFailure(newFailure("gob decoding failed: "+err.Error(), nil))
+	} else if onlyInThis, onlyInOther, changed := trie.Diff(decoded, nil); len(onlyInThis)+len(onlyInOther)+len(changed) > 0 {
+		t.addFailure(newFailure(fmt.Sprint("gob decoded trie ", decoded, " does not match ", trie), nil))
+	} else if err = gob.NewEncoder(&buf).Encode(&keys); err != nil {
+		t.addFailure(newFailure("gob encoding failed: "+err.Error(), nil))
+	} else if err = gob.NewDecoder(&buf).Decode(&decodedKeys); err != nil {
+		t.addFailure(newFailure("gob decoding failed: "+err.Error(), nil))
+	} else if onlyInThis, onlyInOther := keys.Diff(&decodedKeys); len(onlyInThis)+len(onlyInOther) > 0 || decodedKeys.Size() != keys.Size() {
+		t.addFailure(newFailure(fmt.Sprint("gob decoded trie ", decodedKeys, " does not match ", keys), nil))
+	}
+
+	// keys added without values map to nil values
+	pointerTrie := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, *int]()
+	for i, str := range []string{"1.2.0.0/16", "1.2.3.4", "10.0.0.0/8", "0.0.0.0/0"} {
+		addr := ipaddr.NewIPAddressString(str).GetAddress().ToIPv4()
+		if i%2 == 0 {
+			pointerTrie.Add(addr)
+		} else {
+			value := i
+			pointerTrie.Put(addr, &value)
+		}
+	}
+	zero := 0
+	pointerTrie.Put(ipaddr.NewIPAddressString("1.2.3.5").GetAddress().ToIPv4(), &zero)
+	decodedPointers := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, *int]()
+	buf.Reset()
+	if err := gob.NewEncoder(&buf).Encode(pointerTrie); err != nil {
+		t.addFailure(newFailure("gob encoding failed: "+err.Error(), nil))
+	} else if err = gob.NewDecoder(&buf).Decode(decodedPointers); err != nil {
+		t.addFailure(newFailure("gob decoding failed: "+err.Error(), nil))
+	} else if onlyInThis, onlyInOther, changed := pointerTrie.Diff(decodedPointers, nil); len(onlyInThis)+len(onlyInOther)+len(changed) > 0 {
+		t.addFailure(newFailure(fmt.Sprint("gob decoded trie ", decodedPointers, " does not match ", pointerTrie), nil))
+	}
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) testSubTrie() {
+	toAddr := func(str string) *ipaddr.IPv4Address {
+		return ipaddr.NewIPAddressString(str).GetAddress().ToIPv4()
+	}
+	subKeys := func(iter ipaddr.Iterator[*ipaddr.IPv4Address]) (keys []*ipaddr.IPv4Address) {
+		for iter.HasNext() {
+			keys = append(keys, iter.Next())
+		}
+		return
+	}
+	trie := ipaddr.Trie[*ipaddr.IPv4Address]{}
+	for _, str := range []string{"1.2.0.0/16", "1.2.3.4", "1.2.3.5", "10.0.0.0/8", "1.3.0.0/16"} {
+		trie.Add(toAddr(str))
+	}
+	sub := trie.SubTrie(toAddr("1.2.0.0/16"))
+	if sub.Size() != 3 {
+		t.addFailure(newFailure("sub-trie size "+strconv.Itoa(sub.Size())+", expected 3", nil))
+	}
+	if !sub.Contains(toAddr("1.2.3.4")) || sub.Contains(toAddr("10.0.0.0/8")) || sub.Contains(toAddr("1.2.3.6")) {
+		t.addFailure(newFailure(fmt.Sprint("unexpected sub-trie membership in ", sub), nil))
+	}
+	if !sub.Add(toAddr("1.2.5.0/24")) || !trie.Contains(toAddr("1.2.5.0/24")) {
+		t.addFailure(newFailure("sub-trie addition not reflected in trie", nil))
+	}
+	if sub.Add(toAddr("1.4.0.0")) || trie.Contains(toAddr("1.4.0.0")) {
+		t.addFailure(newFailure("sub-trie added address outside its block", nil))
+	}
+	trie.Add(toAddr("1.2.3.6"))
+	t.checkDiffKeys(subKeys(sub.Iterator()), "1.2.3.4", "1.2.3.5", "1.2.3.6", "1.2.5.0/24", "1.2.0.0/16")
+	t.checkDiffKeys(subKeys(sub.DescendingIterator()), "1.2.0.0/16", "1.2.5.0/24", "1.2.3.6", "1.2.3.5", "1.2.3.4")
+	if sub.Remove(toAddr("10.0.0.0/8")) || !sub.Remove(toAddr("1.2.3.5")) || trie.Contains(toAddr("1.2.3.5")) {
+		t.addFailure(newFailure("unexpected sub-trie removal in "+trie.String(), nil))
+	}
+	clone := sub.Clone()
+	sub.Clear()
+	if !sub.IsEmpty() || sub.GetRoot() != nil || sub.Iterator().HasNext() {
+		t.addFailure(newFailure(fmt.Sprint("sub-trie not cleared: ", sub), nil))
+	}
+	t.checkDiffKeys(subKeys(trie.Iterator()), "1.3.0.0/16", "10.0.0.0/8")
+	t.checkDiffKeys(subKeys(clone.Iterator()), "1.2.3.4", "1.2.3.6", "1.2.5.0/24", "1.2.0.0/16")
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) testValueKeys() {
+	trie := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]()
+	for i, str := range []string{"1.2.0.0/16", "1.2.3.4", "10.0.0.0/8", "0.0.0.0/0", "1.2.3.128/25", "192.168.0.0/16"} {
+		trie.Put(ipaddr.NewIPAddressString(str).GetAddress().ToIPv4(), 64500+i%3)
+	}
+	var values []int
+	for iter := trie.ValuesIterator(nil); iter.HasNext(); {
+		values = append(values, iter.Next())
+	}
+	// in sorted element order, 1.2.3.4 maps to 64501, 1.2.3.128/25 to 64501, 1.2.0.0/16 to 64500, 10.0.0.0/8 to 64502, 192.168.0.0/16 to 64502, 0.0.0.0/0 to 64500
+	if !reflect.DeepEqual(values, []int{64501, 64500, 64502}) {
+		t.addFailure(newFailure(fmt.Sprint("distinct values ", values, ", expected [64501 64500 64502]"), nil))
+	}
+	values = values[:0]
+	for iter := trie.ValuesIterator(func(value, otherValue int) bool { return value/2 == otherValue/2 }); iter.HasNext(); {
+		values = append(values, iter.Next())
+	}
+	if !reflect.DeepEqual(values, []int{64501, 64502}) {
+		t.addFailure(newFailure(fmt.Sprint("distinct values ", values, ", expected [64501 64502]"), nil))
+	}
+
+	keysForValue := func(iter ipaddr.Iterator[*ipaddr.IPv4Address]) (keys []*ipaddr.IPv4Address) {
+		for iter.HasNext() {
+			keys = append(keys, iter.Next())
+		}
+		return
+	}
+	t.checkDiffKeys(keysForValue(trie.KeysForValue(64500, nil)), "1.2.0.0/16", "0.0.0.0/0")
+	t.checkDiffKeys(keysForValue(trie.KeysForValue(64501, nil)), "1.2.3.4", "1.2.3.128/25")
+	t.checkDiffKeys(keysForValue(trie.KeysForValue(64499, nil)))
+	t.checkDiffKeys(keysForValue(trie.KeysForValue(64501, func(value, otherValue int) bool { return value/2 == otherValue/2 })),
+		"1.2.3.4", "1.2.3.128/25", "1.2.0.0/16", "0.0.0.0/0")
+
+	inverse := ipaddr.InvertAssociativeTrie(trie)
+	if len(inverse) != 3 {
+		t.addFailure(newFailure(fmt.Sprint("inverse ", inverse, " has ", len(inverse), " values, expected 3"), nil))
+	}
+	for value, keys := range inverse {
+		t.checkDiffKeys(keys, t.keyStrings(keysForValue(trie.KeysForValue(value, nil)))...)
+	}
+	t.checkDiffKeys(inverse[64502], "10.0.0.0/8", "192.168.0.0/16")
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) testValueUpdates() {
+	trie := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]()
+	block := ipaddr.NewIPAddressString("1.2.0.0/16").GetAddress().ToIPv4()
+	addr := ipaddr.NewIPAddressString("1.2.3.4").GetAddress().ToIPv4()
+	trie.Put(block, 0)
+	trie.Put(addr, 10)
+	if value, found := trie.UpdateValue(addr, func(value int) int { return value + 1 }); !found || value != 11 {
+		t.addFailure(newFailure(fmt.Sprint("updated value ", value, " found ", found, ", expected 11"), nil))
+	} else if _, found := trie.UpdateValue(ipaddr.NewIPAddressString("1.2.3.5").GetAddress().ToIPv4(), func(int) int { return 1 }); found || trie.Size() != 2 {
+		t.addFailure(newFailure("updated value of key not in trie "+trie.String(), nil))
+	}
+	node := trie.GetAddedNode(addr)
+	if node.CompareAndSetValue(10, 20, nil) || node.LoadValue() != 11 {
+		t.addFailure(newFailure(fmt.Sprint("value set with mismatched old value, value is ", node.GetValue()), nil))
+	} else if !node.CompareAndSetValue(11, 20, nil) || node.LoadValue() != 20 {
+		t.addFailure(newFailure(fmt.Sprint("value not set with matching old value, value is ", node.GetValue()), nil))
+	} else if !node.CompareAndSetValue(29, 30, func(value, otherValue int) bool { return value/10 == otherValue/10 }) || node.GetValue() != 30 {
+		t.addFailure(newFailure(fmt.Sprint("value not set with equal old value, value is ", node.GetValue()), nil))
+	} else if nonAdded := trie.GetNode(ipaddr.NewIPAddressString("1.2.3.0/24").GetAddress().ToIPv4()); nonAdded != nil && nonAdded.CompareAndSetValue(0, 1, nil) {
+		t.addFailure(newFailure("value set for non-added node", nil))
+	}
+
+	// concurrent increments under a shared read lock, with adds and removals of other keys under the write lock
+	var lock sync.RWMutex
+	var wg sync.WaitGroup
+	const goroutines, increments = 8, 500
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			other := ipaddr.NewIPAddressString("10.0.0." + strconv.Itoa(i)).GetAddress().ToIPv4()
+			for j := 0; j < increments; j++ {
+				lock.RLock()
+				if i%2 == 0 {
+					trie.UpdateValue(block, func(value int) int { return value + 1 })
+				} else {
+					node := trie.GetAddedNode(block)
+					for value := node.LoadValue(); !node.CompareAndSetValue(value, value+1, nil); value = node.LoadValue() {
+					}
+				}
+				lock.RUnlock()
+				lock.Lock()
+				if j%2 == 0 {
+					trie.Add(other)
+				} else {
+					trie.Remove(other)
+				}
+				lock.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if value, _ := trie.Get(block); value != goroutines*increments {
+		t.addFailure(newFailure(fmt.Sprint("concurrent updates produced ", value, ", expected ", goroutines*increments), nil))
+	}
+
+	// updaters that read and update the values of other nodes, enough nodes that some share the same value lock
+	nested := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]()
+	var keys []*ipaddr.IPv4Address
+	for iter := ipaddr.NewIPAddressString("1.2.3.0/24").GetAddress().ToIPv4().Iterator(); iter.HasNext(); {
+		key := iter.Next().WithoutPrefixLen()
+		keys = append(keys, key)
+		nested.Put(key, 0)
+	}
+	for i, key := range keys {
+		other := keys[(i+1)%len(keys)]
+		nested.UpdateValue(key, func(value int) int {
+			otherValue, _ := nested.UpdateValue(other, func(otherValue int) int { return otherValue + 1 })
+			return value + nested.GetAddedNode(other).LoadValue() - otherValue + 1
+		})
+	}
+	for _, key := range keys {
+		if value, _ := nested.Get(key); value != 2 {
+			t.addFailure(newFailure(fmt.Sprint("nested updates produced ", value, " for ", key, ", expected 2"), nil))
+			break
+		}
+	}
+	t.incrementTestCount()
+}
+
+func (t trieTesterGeneric) keyStrings(keys []*ipaddr.IPv4Address) []string {
+	strs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		strs = append(strs, key.String())
+	}
+	return strs
+}
+
+func (t trieTesterGeneric) checkDiffKeys(keys []*ipaddr.IPv4Address, expected ...string) {
+	strs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		strs = append(strs, key.String())
+	}
+	if !reflect.DeepEqual(strs, append([]string{}, expected...)) {
+		t.addFailure(newFailure(fmt.Sprint("diff keys ", strs, ", expected ", expected), nil))
+	}
+}
+
+func (t trieTesterGeneric) testCSVVersions() {
+	current := "#version,1\n1.2.3.4,2\n1.2.0.0/16,1\n"
+	t.testCSVVersion(current, 1)
+	t.testCSVVersion("#version,1\n", 1)
+	for _, invalid := range []string{"#version,2\n", "#version,0\n", "#version,x\n", "1.2.0.0/16,1\n1.2.3.4,2\n", ""} {
+		if _, err := ipaddr.TrieCSVVersion([]byte(invalid)); err == nil {
+			t.addFailure(newFailure("expected failure detecting version of "+invalid, nil))
+		}
+		if err := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]().ReadCSV(strings.NewReader(invalid), strconv.Atoi); err == nil {
+			t.addFailure(newFailure("expected failure reading "+invalid, nil))
+		}
+		t.incrementTestCount()
+	}
+}
+
+func (t trieTesterGeneric) testCSVVersion(csv string, expectedVersion int) {
+	if version, err := ipaddr.TrieCSVVersion([]byte(csv)); err != nil {
+		t.addFailure(newFailure("unexpected error detecting CSV version: "+err.Error(), nil))
+	} else if version != expectedVersion {
+		t.addFailure(newFailure("detected CSV version "+strconv.Itoa(version)+", expected "+strconv.Itoa(expectedVersion), nil))
+	}
+	var builder strings.Builder
+	trie := ipaddr.NewAssociativeTrie[*ipaddr.IPv4Address, int]()
+	if err := trie.ReadCSV(strings.NewReader(csv), strconv.Atoi); err != nil {
+		t.addFailure(newFailure("unexpected error reading CSV "+csv+": "+err.Error(), nil))
+	} else if err = trie.WriteCSV(&builder, strconv.Itoa); err != nil {
+		t.addFailure(newFailure("unexpected error writing CSV: "+err.Error(), nil))
+	} else if builder.String() != csv {
+		t.addFailure(newFailure("rewritten CSV "+builder.String()+", expected "+csv, nil))
+	}
+	t.incrementTestCount()
+}
+
+func testCSVRoundTrip[T ipaddr.TrieKeyConstraint[T], V any](t trieTesterGeneric, trie *ipaddr.AssociativeTrie[T, V], encoder func(V) string, decoder func(string) (V, error)) {
+	var builder strings.Builder
+	if err := trie.WriteCSV(&builder, encoder); err != nil {
+		t.addFailure(newFailure("unexpected error writing CSV: "+err.Error(), nil))
+	} else {
+		reloaded := ipaddr.NewAssociativeTrie[T, V]()
+		if err = reloaded.ReadCSV(strings.NewReader(builder.String()), decoder); err != nil {
+			t.addFailure(newFailure("unexpected error reading CSV "+builder.String()+": "+err.Error(), nil))
+		} else if !reloaded.Equal(trie) {
+			t.addFailure(newFailure("reloaded trie\n"+reloaded.String()+"does not match original\n"+trie.String(), nil))
+		} else {
+			for iter := trie.NodeIterator(true); iter.HasNext(); {
+				node := iter.Next()
+				reloadedNode := reloaded.GetAddedNode(node.GetKey())
+				if reloadedKey := reloadedNode.GetKey(); reloadedKey.String() != node.GetKey().String() ||
+					!reloadedKey.GetPrefixLen().Equal(node.GetKey().GetPrefixLen()) {
+					t.addFailure(newFailure("reloaded key "+reloadedKey.String()+" does not match original "+node.GetKey().String(), nil))
+				} else if !reflect.DeepEqual(reloadedNode.GetValue(), node.GetValue()) {
+					t.addFailure(newFailure("reloaded value for "+reloadedKey.String()+" does not match original", nil))
+				}
+			}
+		}
+	}
+	t.incrementTestCount()
+}
+
 func (t trieTesterGeneric) testContains(trie *AddressTrie) {
 	if trie.Size() > 0 {
 		last := trie.GetAddedNode(trie.LastAddedNode().GetKey())
@@ -1114,6 +1679,8 @@ func (t trieTesterGeneric) testContains(trie *AddressTrie) {
 			t.addFailure(newTrieFailure("failure "+last.String()+" not in trie ", trie))
 		}
 	}
+	var halfwayAddrs, lpms, nonAdded []*ipaddr.Address
+	allContained, anyContained := true, false
 	iterator := trie.AllNodeIterator(true)
 	for iterator.HasNext() {
 		next := iterator.Next()
@@ -1168,6 +1735,13 @@ func (t trieTesterGeneric) testContains(trie *AddressTrie) {
 		smallestContaining := trie.LongestPrefixMatchNode(halfwayAddr)
 		containing := trie.ElementsContaining(halfwayAddr)
 		elementsContains := trie.ElementContains(halfwayAddr)
+		halfwayAddrs = append(halfwayAddrs, halfwayAddr)
+		lpms = append(lpms, lpm)
+		allContained = allContained && elementsContains
+		anyContained = anyContained || elementsContains
+		if !next.IsAdded() {
+			nonAdded = append(nonAdded, nextAddr)
+		}
 		addedParent := parent
 		for addedParent != nil && !addedParent.IsAdded() {
 			addedParent = addedParent.GetParent()
@@ -1207,6 +1781,21 @@ func (t trieTesterGeneric) testContains(trie *AddressTrie) {
 			}
 		}
 	}
+	if trie.ElementContainsAll(halfwayAddrs) != allContained {
+		t.addFailure(newTrieFailure("ElementContainsAll mismatch, expected "+strconv.FormatBool(allContained), trie))
+	} else if trie.ElementContainsAny(halfwayAddrs) != anyContained {
+		t.addFailure(newTrieFailure("ElementContainsAny mismatch, expected "+strconv.FormatBool(anyContained), trie))
+	} else if trie.ContainsAny(nonAdded) {
+		t.addFailure(newTrieFailure("ContainsAny is true for non-added nodes", trie))
+	} else if trie.Size() > 0 && !trie.ContainsAny(append(nonAdded, trie.FirstAddedNode().GetKey())) {
+		t.addFailure(newTrieFailure("ContainsAny is false for "+trie.FirstAddedNode().String(), trie))
+	}
+	for i, match := range trie.LongestPrefixMatchAll(halfwayAddrs) {
+		if match != lpms[i] {
+			t.addFailure(newTrieFailure("LongestPrefixMatchAll is "+match.String()+" for address "+halfwayAddrs[i].String()+" instead of expected "+lpms[i].String(), trie))
+			break
+		}
+	}
 	t.incrementTestCount()
 }
 
@@ -1371,6 +1960,26 @@ func (t trieTesterGeneric) testMap(trie *ipaddr.AssociativeTrie[*ipaddr.Address,
 			//v, _ = trie.Get(addr)
 		}
 	}
+	priority := func(node *ipaddr.AssociativeTrieNode[*ipaddr.Address, any]) int {
+		if node.GetKey().IsPrefixed() {
+			return 0
+		}
+		return 1
+	}
+	sorted := trie.SortedNodes(func(one, two *ipaddr.AssociativeTrieNode[*ipaddr.Address, any]) int {
+		return priority(one) - priority(two)
+	})
+	if len(sorted) != trie.Size() {
+		t.addFailure(newAssocTrieFailure("sorted size mismatch, got "+strconv.Itoa(len(sorted))+" not "+strconv.Itoa(trie.Size()), trie))
+	}
+	for i := 1; i < len(sorted); i++ {
+		prev, next := sorted[i-1], sorted[i]
+		if priority(prev) > priority(next) || (priority(prev) == priority(next) && prev.Compare(next) >= 0) {
+			t.addFailure(newAssocTrieFailure(fmt.Sprintf("sorted nodes out of order, %v before %v", prev, next), trie))
+		}
+	}
+	t.incrementTestCount()
+
 	// all trie2 from now on
 	trie2.PutTrie(trie.GetRoot())
 	for i, addr := range addrs {
@@ -1649,3 +2258,66 @@ func (t trieTesterGeneric) testMap(trie *ipaddr.AssociativeTrie[*ipaddr.Address,
 	}
 	// end put tests
 }
+
+func (t trieTesterGeneric) testTrieStructure() {
+	toAddr := func(str string) *ipaddr.IPv4Address {
+		return ipaddr.NewIPAddressString(str).GetAddress().ToIPv4()
+	}
+	trie := ipaddr.Trie[*ipaddr.IPv4Address]{}
+	if trie.StructureIterator().HasNext() {
+		t.addFailure(newFailure("structure of empty trie is not empty", nil))
+	}
+	for _, str := range []string{"1.2.0.0/16", "1.2.3.4", "1.2.3.5", "10.0.0.0/8", "1.3.0.0/16", "1.2.128.0/17"} {
+		trie.Add(toAddr(str))
+	}
+	var infos []ipaddr.TrieNodeInfo[*ipaddr.IPv4Address]
+	for iter := trie.StructureIterator(); iter.HasNext(); {
+		infos = append(infos, iter.Next())
+	}
+	// the structure lists the nodes in the same order as the pre-order all-node iterator
+	i := 0
+	for iter := trie.ContainingFirstAllNodeIterator(true); iter.HasNext(); i++ {
+		node := iter.Next()
+		if i >= len(infos) {
+			t.addFailure(newFailure("structure is missing node "+node.String(), nil))
+			break
+		}
+		info := infos[i]
+		if !info.Key.Equal(node.GetKey()) || info.Depth != node.GetDepth() || info.SplitBit != node.GetSplitBit() || info.IsAdded != node.IsAdded() ||
+			info.HasLowerSubNode != (node.GetLowerSubNode() != nil) || info.HasUpperSubNode != (node.GetUpperSubNode() != nil) {
+			t.addFailure(newFailure(fmt.Sprintf("structure %+v does not match node %v", info, node), nil))
+		}
+		if lower := node.GetLowerSubNode(); lower != nil && lower.GetKey().IsOneBit(info.SplitBit) {
+			t.addFailure(newFailure(fmt.Sprint("lower sub-node ", lower, " has one bit at split bit ", info.SplitBit), nil))
+		}
+		if upper := node.GetUpperSubNode(); upper != nil && !upper.GetKey().IsOneBit(info.SplitBit) {
+			t.addFailure(newFailure(fmt.Sprint("upper sub-node ", upper, " has zero bit at split bit ", info.SplitBit), nil))
+		}
+	}
+	if i != len(infos) {
+		t.addFailure(newFailure(fmt.Sprint("structure has ", len(infos), " nodes, expected ", i), nil))
+	}
+	node := trie.GetAddedNode(toAddr("1.2.3.5"))
+	if node.GetDepth() != 5 || node.GetSplitBit() != 32 || !node.IsAdded() {
+		t.addFailure(newFailure(fmt.Sprint("unexpected depth ", node.GetDepth(), " or split bit ", node.GetSplitBit(), " of ", node), nil))
+	}
+	junction := node.GetParent()
+	if junction.IsAdded() || junction.GetSplitBit() != 31 || junction.GetDepth() != 4 {
+		t.addFailure(newFailure(fmt.Sprint("unexpected junction ", junction, " depth ", junction.GetDepth(), " split bit ", junction.GetSplitBit()), nil))
+	}
+	sub := trie.GetAddedNode(toAddr("1.2.0.0/16"))
+	subIter := sub.StructureIterator()
+	if first := subIter.Next(); !first.Key.Equal(sub.GetKey()) || first.Depth != sub.GetDepth() || !first.IsAdded {
+		t.addFailure(newFailure(fmt.Sprintf("unexpected first sub-trie structure %+v", first), nil))
+	}
+	count := 1
+	for ; subIter.HasNext(); count++ {
+		if info := subIter.Next(); info.Depth <= sub.GetDepth() {
+			t.addFailure(newFailure(fmt.Sprintf("sub-trie structure %+v not deeper than its root", info), nil))
+		}
+	}
+	if count != sub.NodeSize() {
+		t.addFailure(newFailure(fmt.Sprint("sub-trie structure has ", count, " nodes, expected ", sub.NodeSize()), nil))
+	}
+	t.incrementTestCount()
+}
@@ -20,6 +20,8 @@ import (
 	"math"
 	"math/big"
 	"strconv"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
 )
 
 type boolSetting struct {
@@ -106,6 +108,60 @@ func (prefixBitCount *PrefixBitCount) Compare(other PrefixLen) int {
 	return prefixBitCount.bitCount() - other.bitCount()
 }
 
+// CompareBitCount compares this PrefixLen value with a bit count, returning -1, 0, or 1 if this prefix length is less than, equal to, or greater than the given bit count.
+// Like Compare, the nil PrefixLen, representing the absence of a prefix length, is greater than any bit count.
+func (prefixBitCount *PrefixBitCount) CompareBitCount(other BitCount) int {
+	if prefixBitCount == nil {
+		return 1
+	} else if bitCount := prefixBitCount.bitCount(); bitCount < other {
+		return -1
+	} else if bitCount > other {
+		return 1
+	}
+	return 0
+}
+
+// LenOr returns the length of the prefix, or the given default if the receiver is nil, representing the absence of a prefix length.
+// A typical default is the bit count of the address, the network portion of an address without a prefix length being the entire address.
+func (prefixBitCount *PrefixBitCount) LenOr(defaultLen BitCount) BitCount {
+	if prefixBitCount == nil {
+		return defaultLen
+	}
+	return prefixBitCount.bitCount()
+}
+
+// Add returns the prefix length increased by the given number of bits, which may be negative to shorten the prefix length.
+// It returns an error if the result is negative or exceeds the given maximum bit count, typically the bit count of the address.
+// The nil PrefixLen, representing the absence of a prefix length, is returned unchanged.
+func (prefixBitCount *PrefixBitCount) Add(bits, maxBitCount BitCount) (PrefixLen, addrerr.AddressValueError) {
+	if prefixBitCount == nil {
+		return nil, nil
+	}
+	result := prefixBitCount.bitCount() + bits
+	if result < 0 || result > maxBitCount || result > maxBitCountInternal {
+		return nil, &addressValueError{addressError: addressError{key: "ipaddress.error.prefixSize"}, val: result}
+	}
+	return ToPrefixLen(result), nil
+}
+
+// Min returns the shorter of this prefix length and the given prefix length.
+// The nil PrefixLen, representing the absence of a prefix length, is longer than any prefix length, so the result is nil only when both are nil.
+func (prefixBitCount *PrefixBitCount) Min(other PrefixLen) PrefixLen {
+	if prefixBitCount.Compare(other) <= 0 {
+		return prefixBitCount
+	}
+	return other
+}
+
+// Max returns the longer of this prefix length and the given prefix length.
+// The nil PrefixLen, representing the absence of a prefix length, is longer than any prefix length, so the result is nil when either is nil.
+func (prefixBitCount *PrefixBitCount) Max(other PrefixLen) PrefixLen {
+	if prefixBitCount.Compare(other) >= 0 {
+		return prefixBitCount
+	}
+	return other
+}
+
 // String returns the bit count as a base-10 positive integer string, or "<nil>" if the receiver is a nil pointer.
 func (prefixBitCount *PrefixBitCount) String() string {
 	if prefixBitCount == nil {
@@ -172,7 +228,7 @@ var cachedPrefixBitCounts, cachedPrefixLens = initPrefLens()
 func initPrefLens() ([]PrefixBitCount, []PrefixLen) {
 	cachedPrefBitcounts := make([]PrefixBitCount, maxBitCountInternal)
 	cachedPrefLens := make([]PrefixLen, maxBitCountInternal)
-	for i := 0; i <= IPv6BitCount; i++ {
+	for i := 0; i < maxBitCountInternal; i++ {
 		cachedPrefBitcounts[i] = PrefixBitCount(i)
 		cachedPrefLens[i] = &cachedPrefBitcounts[i]
 	}
@@ -141,6 +141,14 @@ type strValidator struct{}
 
 func (strValidator) validateIPAddressStr(fromString *IPAddressString, validationOptions addrstrparam.IPAddressStringParams) (prov ipAddressProvider, err addrerr.AddressStringError) {
 	str := fromString.str
+	if dnsProvider, ok := validationOptions.(addrstrparam.ReverseDNSProvider); ok && dnsProvider.AllowsReverseDNS() && isReverseDNSName(str) {
+		return validateReverseDNSStr(str, validationOptions)
+	}
+	if maskProvider, ok := validationOptions.(addrstrparam.SpaceSeparatedMaskProvider); ok && maskProvider.AllowsSpaceSeparatedMask() {
+		if fields := strings.Fields(str); len(fields) == 2 && strings.ContainsAny(fields[1], ".:") {
+			return validateAddressMaskStr(str, fields[0], fields[1], validationOptions)
+		}
+	}
 	pa := parsedIPAddress{
 		originator:         fromString,
 		options:            validationOptions,
@@ -158,6 +166,47 @@ func (strValidator) validateIPAddressStr(fromString *IPAddressString, validation
 	return
 }
 
+// isReverseDNSName returns whether the string ends with one of the reverse-DNS suffixes, ignoring any trailing dot
+func isReverseDNSName(str string) bool {
+	str = strings.TrimSuffix(str, ".")
+	return hasReverseDNSSuffix(str, IPv4ReverseDnsSuffix) ||
+		hasReverseDNSSuffix(str, IPv6ReverseDnsSuffix) ||
+		hasReverseDNSSuffix(str, IPv6ReverseDnsSuffixDeprecated)
+}
+
+// validateReverseDNSStr parses a reverse-DNS name as the address it maps to, or the prefix block for a zone name,
+// and then checks the resulting address against the validation options
+func validateReverseDNSStr(str string, validationOptions addrstrparam.IPAddressStringParams) (prov ipAddressProvider, err addrerr.AddressStringError) {
+	addr, hostErr := ParseReverseDNSName(str)
+	if hostErr != nil {
+		err = &addressStringError{addressError{str: str, key: hostErr.GetKey()}}
+	} else if addr.IsIPv4() && !validationOptions.AllowsIPv4() {
+		err = &addressStringError{addressError{str: str, key: "ipaddress.error.ipv4"}}
+	} else if addr.IsIPv6() && !validationOptions.AllowsIPv6() {
+		err = &addressStringError{addressError{str: str, key: "ipaddress.error.ipv6"}}
+	} else if addr.IsPrefixed() && !validationOptions.AllowsPrefix() {
+		err = &addressStringError{addressError{str: str, key: "ipaddress.error.CIDRNotAllowed"}}
+	} else {
+		prov = addr.getProvider()
+		return
+	}
+	prov = getInvalidProvider(validationOptions)
+	return
+}
+
+// validateAddressMaskStr parses an address followed by a space-separated mask as if the mask followed a '/' character
+func validateAddressMaskStr(str, addrStr, maskStr string, validationOptions addrstrparam.IPAddressStringParams) (prov ipAddressProvider, err addrerr.AddressStringError) {
+	options := new(addrstrparam.IPAddressStringParamsBuilder).Set(validationOptions).AllowSpaceSeparatedMask(false).ToParams()
+	addr, addrErr := NewIPAddressStringParams(addrStr+string(PrefixLenSeparator)+maskStr, options).ToAddress()
+	if addrErr != nil {
+		err = &addressStringError{addressError{str: str, key: addrErr.GetKey()}}
+		prov = getInvalidProvider(validationOptions)
+		return
+	}
+	prov = addr.getProvider()
+	return
+}
+
 func getInvalidProvider(validationOptions addrstrparam.IPAddressStringParams) ipAddressProvider {
 	if validationOptions == defaultIPAddrParameters {
 		return invalidProvider
@@ -535,7 +584,9 @@ func validateAddress(
 						limit = MediaAccessControlDotted64SegmentCount
 					}
 					if segCount >= limit {
-						return &addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}}
+						return &addressStringIndexError{
+							addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}},
+							segmentStartIndex}
 					}
 				}
 			} else {
@@ -557,7 +608,9 @@ func validateAddress(
 					}
 					totalSegmentCount := segCount + IPv6MixedReplacedSegmentCount
 					if totalSegmentCount > IPv6SegmentCount {
-						return &addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}}
+						return &addressStringIndexError{
+							addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}},
+							segmentStartIndex}
 					}
 					if wildcardCount > 0 {
 						if parseData.getConsecutiveSeparatorIndex() < 0 &&
@@ -589,7 +642,9 @@ func validateAddress(
 					index = pa.getAddressParseData().getAddressEndIndex()
 					continue
 				} else if segCount >= IPv4SegmentCount {
-					return &addressStringError{addressError{str: str, key: "ipaddress.error.ipv4.too.many.segments"}}
+					return &addressStringIndexError{
+						addressStringError{addressError{str: str, key: "ipaddress.error.ipv4.too.many.segments"}},
+						segmentStartIndex}
 				}
 			}
 			if wildcardCount > 0 {
@@ -1411,7 +1466,9 @@ func validateAddress(
 						segLimit = ExtendedUniqueIdentifier64SegmentCount
 					}
 					if segCount >= segLimit {
-						return &addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}}
+						return &addressStringIndexError{
+							addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}},
+							segmentStartIndex}
 					}
 				}
 				hexMaxChars = MACSegmentMaxChars //will be ignored for single or double segments due to checkCharCounts booleans
@@ -1458,7 +1515,9 @@ func validateAddress(
 				} else if ipParseData.getProviderIPVersion().IsIPv4() {
 					return &addressStringError{addressError{str: str, key: "ipaddress.error.ipv6.separator"}}
 				} else if segCount >= IPv6SegmentCount {
-					return &addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}}
+					return &addressStringIndexError{
+						addressStringError{addressError{str: str, key: "ipaddress.error.too.many.segments"}},
+						segmentStartIndex}
 				}
 				hexMaxChars = IPv6SegmentMaxChars // will be ignored for single segment due to checkCharCounts boolean
 			}
@@ -2078,6 +2137,13 @@ func parseAddressQualifier(
 			err = &addressStringError{addressError{str: fullAddr, key: "ipaddress.error.only.zone"}}
 			return
 		}
+		if zoneProvider, ok := validationOptions.GetIPv6Params().(addrstrparam.URIZoneEncodingProvider); ok && zoneProvider.AllowsURIZoneEncoding() {
+			if endIndex-qualifierIndex >= 2 && fullAddr[qualifierIndex] == '2' && fullAddr[qualifierIndex+1] == '5' {
+				//handle %25 from rfc 6874
+				qualifierIndex += 2
+			}
+			return parseEncodedZone(fullAddr, validationOptions, res, addressIsEmpty, qualifierIndex, endIndex, ipVersion)
+		}
 		return parseZone(fullAddr, validationOptions, res, addressIsEmpty, qualifierIndex, endIndex, ipVersion)
 	}
 	return
@@ -2226,6 +2292,24 @@ func ValidateZoneStr(zoneStr string) (zone Zone, err addrerr.AddressStringError)
 	return Zone(zoneStr), nil
 }
 
+// toURIZone percent-encodes the zone for a URI, including the leading "25" that encodes the preceding zone separator
+func toURIZone(zone Zone) Zone {
+	var builder strings.Builder
+	builder.Grow(len(zone) + 2)
+	builder.WriteString("25")
+	for i := 0; i < len(zone); i++ {
+		c := zone[i]
+		if isReserved(c) {
+			builder.WriteByte(IPv6ZoneSeparator)
+			builder.WriteByte(uppercaseDigits[c>>4])
+			builder.WriteByte(uppercaseDigits[c&0xf])
+		} else {
+			builder.WriteByte(c)
+		}
+	}
+	return Zone(builder.String())
+}
+
 func isReserved(c byte) bool {
 	isUnreserved :=
 		(c >= '0' && c <= '9') ||
@@ -2306,10 +2390,15 @@ func parseEncodedZone(
 				result.WriteString(fullAddr[index:i])
 			}
 			charArray := chars
-			i++
-			c = charArray[fullAddr[i]] << 4
-			i++
-			c |= charArray[fullAddr[i]]
+			high, low := charArray[fullAddr[i+1]], charArray[fullAddr[i+2]]
+			if high > 0xf || low > 0xf || (high == 0 && fullAddr[i+1] != '0') || (low == 0 && fullAddr[i+2] != '0') {
+				err = &addressStringIndexError{
+					addressStringError{addressError{str: fullAddr, key: "ipaddress.error.invalid.zone.encoding"}},
+					i}
+				return
+			}
+			i += 2
+			c = high<<4 | low
 		} else if c == PrefixLenSeparator {
 			if i == index && !validationOptions.GetIPv6Params().AllowsEmptyZone() {
 				err = &addressStringIndexError{addressStringError{addressError{str: fullAddr, key: "ipaddress.error.invalid.zone"}}, index}
@@ -2947,6 +3036,15 @@ func checkMACSegments(
 	return nil
 }
 
+// allows_inet_aton_joinedSegmentCount returns whether the given parameters allow joined segments with the given segment count,
+// which depends only on Allows_inet_aton_joinedSegments when the parameters do not implement addrstrparam.JoinedSegmentCountProvider
+func allows_inet_aton_joinedSegmentCount(ipv4Options addrstrparam.IPv4AddressStringParams, segCount int) bool {
+	if countProvider, ok := ipv4Options.(addrstrparam.JoinedSegmentCountProvider); ok {
+		return countProvider.Allows_inet_aton_joinedSegmentCount(segCount)
+	}
+	return ipv4Options.Allows_inet_aton_joinedSegments() && (segCount == 2 || segCount == 3)
+}
+
 func checkSegments(
 	fullAddr string,
 	validationOptions addrstrparam.IPAddressStringParams,
@@ -2961,7 +3059,7 @@ func checkSegments(
 
 		//single segments are handled in the parsing code with the allowSingleSegment setting
 		if missingCount > 0 && segCount > 1 {
-			if ipv4Options.Allows_inet_aton_joinedSegments() {
+			if allows_inet_aton_joinedSegmentCount(ipv4Options, segCount) {
 				parseData.set_inet_aton_joined(true)
 			} else if !hasWildcardSeparator {
 				return &addressStringError{addressError{str: fullAddr, key: "ipaddress.error.ipv4.too.few.segments"}}
@@ -2970,7 +3068,12 @@ func checkSegments(
 
 		//here we check whether values are too large
 		notUnlimitedLength := !ipv4Options.AllowsUnlimitedLeadingZeros()
-		hasMissingSegs := missingCount > 0 && ipv4Options.Allows_inet_aton_joinedSegments()
+		var hasMissingSegs bool
+		if segCount > 1 {
+			hasMissingSegs = missingCount > 0 && allows_inet_aton_joinedSegmentCount(ipv4Options, segCount)
+		} else {
+			hasMissingSegs = missingCount > 0 && ipv4Options.Allows_inet_aton_joinedSegments()
+		}
 		for i := 0; i < segCount; i++ {
 			var max uint64
 			if hasMissingSegs && i == segCount-1 {
@@ -3518,7 +3621,8 @@ func (strValidator) validateHostName(fromHost *HostName, validationOptions addrs
 				isPossiblyIPv4 &&
 					(labelCount+1 == IPv4SegmentCount) ||
 					(labelCount+1 < IPv4SegmentCount && isSpecialOnlyIndex >= 0) ||
-					(labelCount+1 < IPv4SegmentCount && validationOptions.GetIPAddressParams().GetIPv4Params().Allows_inet_aton_joinedSegments()) ||
+					(labelCount == 0 && validationOptions.GetIPAddressParams().GetIPv4Params().Allows_inet_aton_joinedSegments()) ||
+					(labelCount+1 < IPv4SegmentCount && allows_inet_aton_joinedSegmentCount(validationOptions.GetIPAddressParams().GetIPv4Params(), labelCount+1)) ||
 					labelCount == 0 && validationOptions.GetIPAddressParams().AllowsSingleSegment()
 			if isAllDigits {
 				if isPossiblyIPv4 && segmentCountMatchesIPv4 {
@@ -0,0 +1,264 @@
+//
+// Copyright 2020-2022 Sean C Foley
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+
+package ipaddr
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/seancfoley/ipaddress-go/ipaddr/addrerr"
+)
+
+const noVectorPrefixLen = 0xff
+
+// IPAddressVector is a compact list of individual addresses of a single IP version,
+// for holding large numbers of addresses with much less memory than a slice of *IPAddress.
+//
+// The address bytes are stored contiguously, along with one byte for the prefix length of each address when any address has a prefix length.
+// Each *IPAddress is created when it is accessed, so callers holding onto the results of Get should not expect them to share memory with the vector.
+//
+// IPv6 zones are not stored, so zoned addresses cannot be added.
+// The zero value is not usable, use NewIPAddressVector to create a vector.
+//
+// IPAddressVector implements sort.Interface, so the addresses can be sorted in place with Sort, or with the functions in the sort package.
+type IPAddressVector struct {
+	version    IPVersion
+	byteCount  int
+	bytes      []byte
+	prefixLens []byte // nil until a prefixed address is added
+}
+
+// NewIPAddressVector creates an empty vector for addresses of the given IP version.
+// It returns nil if the version is indeterminate.
+func NewIPAddressVector(version IPVersion) *IPAddressVector {
+	if version.IsIndeterminate() {
+		return nil
+	}
+	return &IPAddressVector{version: version, byteCount: version.GetByteCount()}
+}
+
+// GetIPVersion returns the IP version of the addresses in this vector.
+func (vec *IPAddressVector) GetIPVersion() IPVersion {
+	return vec.version
+}
+
+// Len returns the number of addresses in this vector.
+func (vec *IPAddressVector) Len() int {
+	return len(vec.bytes) / vec.byteCount
+}
+
+// Grow increases the capacity of this vector, if necessary, to guarantee space for another n addresses without reallocation.
+func (vec *IPAddressVector) Grow(n int) {
+	if n > 0 {
+		if newLen := len(vec.bytes) + n*vec.byteCount; newLen > cap(vec.bytes) {
+			newBytes := make([]byte, len(vec.bytes), newLen)
+			copy(newBytes, vec.bytes)
+			vec.bytes = newBytes
+		}
+	}
+}
+
+// Append adds the given addresses to the end of this vector.
+//
+// An error is returned if any of the addresses is a subnet of multiple addresses, has an IPv6 zone, or is not the IP version of this vector,
+// in which case none of the addresses are added.
+func (vec *IPAddressVector) Append(addrs ...*IPAddress) addrerr.IncompatibleAddressError {
+	for _, addr := range addrs {
+		if err := vec.checkAddress(addr); err != nil {
+			return err
+		}
+	}
+	vec.Grow(len(addrs))
+	for _, addr := range addrs {
+		vec.append(addr)
+	}
+	return nil
+}
+
+// AppendStrings parses the given address strings and adds the resulting addresses to the end of this vector.
+//
+// An error is returned if any of the strings is invalid, or is a valid string for which Append would return an error,
+// in which case none of the addresses are added.
+func (vec *IPAddressVector) AppendStrings(strs ...string) addrerr.AddressError {
+	origLen := len(vec.bytes)
+	vec.Grow(len(strs))
+	for _, str := range strs {
+		addr, err := NewIPAddressString(str).ToAddress()
+		if err == nil {
+			err = vec.checkAddress(addr)
+		}
+		if err != nil {
+			vec.truncateBytes(origLen)
+			return err
+		}
+		vec.append(addr)
+	}
+	return nil
+}
+
+func (vec *IPAddressVector) checkAddress(addr *IPAddress) addrerr.IncompatibleAddressError {
+	if addr == nil || addr.GetIPVersion() != vec.version {
+		return &incompatibleAddressError{addressError{key: "ipaddress.error.ipVersionMismatch"}}
+	} else if addr.IsMultiple() {
+		return &incompatibleAddressError{addressError{key: "ipaddress.error.single.address.required"}}
+	} else if addr.hasZone() {
+		return &incompatibleAddressError{addressError{key: "ipaddress.error.zone"}}
+	}
+	return nil
+}
+
+func (vec *IPAddressVector) append(addr *IPAddress) {
+	index := vec.Len()
+	vec.bytes = append(vec.bytes, addr.Bytes()...)
+	if prefLen := addr.GetPrefixLen(); prefLen != nil {
+		if vec.prefixLens == nil {
+			vec.prefixLens = make([]byte, index, cap(vec.bytes)/vec.byteCount)
+			for i := range vec.prefixLens {
+				vec.prefixLens[i] = noVectorPrefixLen
+			}
+		}
+		vec.prefixLens = append(vec.prefixLens, byte(prefLen.bitCount()))
+	} else if vec.prefixLens != nil {
+		vec.prefixLens = append(vec.prefixLens, noVectorPrefixLen)
+	}
+}
+
+func (vec *IPAddressVector) truncateBytes(byteLen int) {
+	vec.bytes = vec.bytes[:byteLen]
+	if vec.prefixLens != nil {
+		vec.prefixLens = vec.prefixLens[:vec.Len()]
+	}
+}
+
+// Get returns the address at the given index, creating it from the stored bytes and prefix length.
+// It panics if the index is out of range.
+func (vec *IPAddressVector) Get(index int) *IPAddress {
+	addrBytes := vec.getBytes(index)
+	var addr *IPAddress
+	if vec.version.IsIPv4() {
+		ipv4Addr, _ := NewIPv4AddressFromBytes(addrBytes)
+		addr = ipv4Addr.ToIP()
+	} else {
+		ipv6Addr, _ := NewIPv6AddressFromBytes(addrBytes)
+		addr = ipv6Addr.ToIP()
+	}
+	// set the prefix length afterwards, since constructing with a prefix length would make a zero host into the prefix block
+	if prefLen := vec.GetPrefixLen(index); prefLen != nil {
+		addr = addr.SetPrefixLen(prefLen.Len())
+	}
+	return addr
+}
+
+// GetPrefixLen returns the prefix length of the address at the given index, or nil if it has none.
+// It panics if the index is out of range.
+func (vec *IPAddressVector) GetPrefixLen(index int) PrefixLen {
+	vec.getBytes(index) // check the index
+	if vec.prefixLens != nil {
+		if prefLen := vec.prefixLens[index]; prefLen != noVectorPrefixLen {
+			return cacheBitCount(BitCount(prefLen))
+		}
+	}
+	return nil
+}
+
+// Bytes returns a copy of the bytes of the address at the given index.
+// It panics if the index is out of range.
+func (vec *IPAddressVector) Bytes(index int) []byte {
+	return cloneBytes(vec.getBytes(index))
+}
+
+func (vec *IPAddressVector) getBytes(index int) []byte {
+	start := index * vec.byteCount
+	return vec.bytes[start : start+vec.byteCount : start+vec.byteCount]
+}
+
+// Compare returns a negative integer, zero, or a positive integer if the address at index i is less than, equal to, or greater than the address at index j.
+// Addresses are ordered by value, and addresses of equal value are ordered by prefix length, with no prefix length being the longest.
+func (vec *IPAddressVector) Compare(i, j int) int {
+	if result := bytes.Compare(vec.getBytes(i), vec.getBytes(j)); result != 0 || vec.prefixLens == nil {
+		return result
+	}
+	// noVectorPrefixLen is larger than any prefix length
+	return int(vec.prefixLens[i]) - int(vec.prefixLens[j])
+}
+
+// Less returns whether the address at index i is less than the address at index j, as determined by Compare.
+func (vec *IPAddressVector) Less(i, j int) bool {
+	return vec.Compare(i, j) < 0
+}
+
+// Swap swaps the addresses at the given indices.
+func (vec *IPAddressVector) Swap(i, j int) {
+	one, two := vec.getBytes(i), vec.getBytes(j)
+	for k := range one {
+		one[k], two[k] = two[k], one[k]
+	}
+	if vec.prefixLens != nil {
+		vec.prefixLens[i], vec.prefixLens[j] = vec.prefixLens[j], vec.prefixLens[i]
+	}
+}
+
+// Sort sorts the addresses in place, in the order determined by Compare.
+func (vec *IPAddressVector) Sort() {
+	sort.Sort(vec)
+}
+
+// Dedup removes in place each address that is equal, including the prefix length, to the address preceding it.
+// When the vector is sorted, this removes all duplicates.
+// It returns the number of addresses removed.
+func (vec *IPAddressVector) Dedup() int {
+	origLen := vec.Len()
+	if origLen == 0 {
+		return 0
+	}
+	count := 1
+	for i := 1; i < origLen; i++ {
+		if vec.Compare(i, count-1) != 0 {
+			if i != count {
+				copy(vec.getBytes(count), vec.getBytes(i))
+				if vec.prefixLens != nil {
+					vec.prefixLens[count] = vec.prefixLens[i]
+				}
+			}
+			count++
+		}
+	}
+	vec.truncateBytes(count * vec.byteCount)
+	return origLen - count
+}
+
+// Iterator provides an iterator to iterate through the addresses of this vector in order.
+func (vec *IPAddressVector) Iterator() Iterator[*IPAddress] {
+	return &ipAddressVectorIterator{vec: vec}
+}
+
+type ipAddressVectorIterator struct {
+	vec   *IPAddressVector
+	index int
+}
+
+func (iter *ipAddressVectorIterator) HasNext() bool {
+	return iter.index < iter.vec.Len()
+}
+
+func (iter *ipAddressVectorIterator) Next() (res *IPAddress) {
+	if iter.HasNext() {
+		res = iter.vec.Get(iter.index)
+		iter.index++
+	}
+	return
+}